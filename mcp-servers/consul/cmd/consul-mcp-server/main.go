@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/mcpserver"
+	consulClient "github.com/vignesan/infra-genie/mcp-servers/consul/pkg/client"
+	"github.com/vignesan/infra-genie/mcp-servers/consul/pkg/tools"
+	"github.com/vignesan/infra-genie/mcp-servers/consul/version"
+)
+
+var serverConfig = mcpserver.Config{
+	Name:                "consul-mcp-server",
+	Title:               "Consul MCP Server",
+	Description:         `A Consul MCP server that exposes tools for the service catalog, health checks, KV store and intentions.`,
+	Version:             version.Version,
+	BuildInfo:           fmt.Sprintf("Version: %s\nCommit: %s\nBuild Date: %s", version.GetHumanVersion(), version.GitCommit, version.BuildDate),
+	Register:            tools.RegisterTools,
+	ContextMiddleware:   consulClient.ContextHeaders.Middleware,
+	OnRegisterSession:   consulClient.SessionClients.OnRegisterSession,
+	OnUnregisterSession: consulClient.SessionClients.OnUnregisterSession,
+}
+
+func main() {
+	mcpserver.Execute(serverConfig)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-mcp-server/pkg/mcpserver"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ConsulAddress        = "CONSUL_HTTP_ADDR"
+	ConsulToken          = "CONSUL_HTTP_TOKEN"
+	ConsulNamespace      = "CONSUL_NAMESPACE"
+	ConsulSSLVerify      = "CONSUL_HTTP_SSL_VERIFY"
+	DefaultConsulAddress = "http://127.0.0.1:8500"
+)
+
+// ErrNotFound is returned when Consul responds with 404, e.g. for a missing key or an empty KV prefix
+var ErrNotFound = errors.New("not found")
+
+// ConsulClient is a minimal client for the Consul HTTP API
+type ConsulClient struct {
+	Address    string
+	Token      string
+	Namespace  string
+	HTTPClient *http.Client
+}
+
+// NewConsulClient creates a new Consul client.
+// Unlike Vault, Consul can run without ACLs so an empty token is allowed.
+func NewConsulClient(address string, token string, namespace string, skipTLSVerify bool, logger *log.Logger) (*ConsulClient, error) {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	if _, err := url.Parse(address); err != nil {
+		return nil, fmt.Errorf("invalid Consul address %q: %w", address, err)
+	}
+
+	httpClient := cleanhttp.DefaultPooledClient()
+	httpClient.Timeout = 10 * time.Second
+	transport := httpClient.Transport.(*http.Transport)
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: skipTLSVerify}
+
+	client := &ConsulClient{
+		Address:    strings.TrimSuffix(address, "/"),
+		Token:      token,
+		Namespace:  namespace,
+		HTTPClient: httpClient,
+	}
+
+	return client, nil
+}
+
+// ContextHeaders are the Consul settings read from the headers of the StreamableHTTP requests
+var ContextHeaders = mcpserver.ContextHeaders{
+	Service: "Consul",
+	Address: ConsulAddress,
+	Token:   ConsulToken,
+	Others:  []string{ConsulNamespace, ConsulSSLVerify},
+}
+
+// SessionClients holds the Consul client of each session
+var SessionClients = &mcpserver.SessionClients[*ConsulClient]{
+	Service: "Consul",
+	Create:  newConsulClientFromContext,
+	Missing: "CONSUL_HTTP_ADDR is valid",
+}
+
+// GetConsulClientFromContext returns the Consul client of the session of ctx
+func GetConsulClientFromContext(ctx context.Context, logger *log.Logger) (*ConsulClient, error) {
+	return SessionClients.FromContext(ctx, logger)
+}
+
+// newConsulClientFromContext creates a Consul client using the values stored in the request context or environment
+func newConsulClientFromContext(ctx context.Context, logger *log.Logger) (*ConsulClient, error) {
+	address := mcpserver.ContextValue(ctx, ConsulAddress, DefaultConsulAddress)
+	token := mcpserver.ContextValue(ctx, ConsulToken, "")
+	namespace := mcpserver.ContextValue(ctx, ConsulNamespace, "")
+
+	// CONSUL_HTTP_SSL_VERIFY follows the Consul CLI semantics where 'false' disables verification
+	sslVerify, err := strconv.ParseBool(mcpserver.ContextValue(ctx, ConsulSSLVerify, "true"))
+	skipTLSVerify := err == nil && !sslVerify
+
+	return NewConsulClient(address, token, namespace, skipTLSVerify, logger)
+}
+
+// Get performs a GET request against the given API path, e.g. catalog/services, and returns the raw body
+func (c *ConsulClient) Get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.Namespace != "" && query.Get("ns") == "" {
+		query.Set("ns", c.Namespace)
+	}
+
+	reqURL := fmt.Sprintf("%s/v1/%s", c.Address, strings.TrimPrefix(path, "/"))
+	if encoded := query.Encode(); encoded != "" {
+		reqURL += "?" + encoded
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading Consul response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("consul returned %s for %s: %w", resp.Status, path, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if msg := strings.TrimSpace(string(body)); msg != "" {
+			return nil, fmt.Errorf("consul returned %s for %s: %s", resp.Status, path, msg)
+		}
+		return nil, fmt.Errorf("consul returned %s for %s", resp.Status, path)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulClientGet(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-token", r.Header.Get("X-Consul-Token"))
+		assert.Equal(t, "team-a", r.URL.Query().Get("ns"))
+
+		switch r.URL.Path {
+		case "/v1/catalog/services":
+			assert.Equal(t, "dc2", r.URL.Query().Get("dc"))
+			w.Write([]byte(`{"consul":[],"web":["v1"]}`))
+		case "/v1/acl/denied":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Permission denied"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewConsulClient(server.URL, "test-token", "team-a", false, logger)
+	require.NoError(t, err)
+
+	body, err := client.Get(t.Context(), "catalog/services", url.Values{"dc": []string{"dc2"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"consul":[],"web":["v1"]}`, string(body))
+
+	_, err = client.Get(t.Context(), "kv/missing", nil)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = client.Get(t.Context(), "acl/denied", nil)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.Contains(t, err.Error(), "Permission denied")
+}
+
+func TestNewConsulClientAddsScheme(t *testing.T) {
+	client, err := NewConsulClient("consul.service:8500", "", "", false, log.New())
+	require.NoError(t, err)
+	assert.Equal(t, "http://consul.service:8500", client.Address)
+}
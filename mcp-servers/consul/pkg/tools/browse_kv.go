@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/consul/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxKVValueBytes limits the size of a KV value returned to the client
+const maxKVValueBytes = 16 * 1024
+
+var errNoKVEntry = errors.New("key not found")
+
+// KVEntry is a single Consul KV entry
+type KVEntry struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Binary      bool   `json:"binary"`
+	Truncated   bool   `json:"truncated"`
+	Flags       uint64 `json:"flags"`
+	ModifyIndex uint64 `json:"modify_index"`
+}
+
+// BrowseKV creates a tool to browse the Consul KV store.
+func BrowseKV(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("browse_kv",
+			mcp.WithDescription(`Browses the Consul KV store. When 'key' is empty or ends with '/', the keys directly under that prefix are listed (folders end with '/'). Otherwise the value of the key is returned; binary values are returned base64 encoded and large values are truncated.`),
+			mcp.WithTitleAnnotation("Browse the Consul KV store"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("key",
+				mcp.Description("The key or prefix to browse, e.g. 'config/web/' (default: the root of the KV store)"),
+			),
+			mcp.WithString("datacenter",
+				mcp.Description("Optional datacenter to query (default: the agent's datacenter)"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return browseKVHandler(ctx, request, logger)
+		},
+	}
+}
+
+func browseKVHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	key := strings.TrimPrefix(strings.TrimSpace(request.GetString("key", "")), "/")
+	query := datacenterQuery(request)
+
+	consulClient, err := client.GetConsulClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Consul client - please ensure CONSUL_HTTP_ADDR is properly configured", err)
+	}
+
+	var result any
+	if key == "" || strings.HasSuffix(key, "/") {
+		query.Set("keys", "")
+		query.Set("separator", "/")
+		body, err := consulClient.Get(ctx, "kv/"+escapeKVPath(key), query)
+		if err != nil {
+			// Consul responds with 404 when a prefix has no keys
+			if errors.Is(err, client.ErrNotFound) {
+				body = []byte("[]")
+			} else {
+				return nil, utils.LogAndReturnError(logger, "listing KV keys", err)
+			}
+		}
+		var keys []string
+		if err := json.Unmarshal(body, &keys); err != nil {
+			return nil, utils.LogAndReturnError(logger, "decoding KV keys", err)
+		}
+		result = map[string]any{"prefix": key, "keys": keys, "total": len(keys)}
+	} else {
+		body, err := consulClient.Get(ctx, "kv/"+escapeKVPath(key), query)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading KV key", err)
+		}
+		entry, err := parseKVEntry(body)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "decoding KV entry", err)
+		}
+		result = entry
+	}
+
+	buf, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling KV entries", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+func parseKVEntry(body []byte) (*KVEntry, error) {
+	var raw []struct {
+		Key         string
+		Value       string
+		Flags       uint64
+		ModifyIndex uint64
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, errNoKVEntry
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(raw[0].Value)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &KVEntry{
+		Key:         raw[0].Key,
+		Flags:       raw[0].Flags,
+		ModifyIndex: raw[0].ModifyIndex,
+	}
+	if len(decoded) > maxKVValueBytes {
+		decoded = decoded[:maxKVValueBytes]
+		entry.Truncated = true
+		// Drop a multi-byte character cut in half by the truncation
+		for i := 0; i < utf8.UTFMax && len(decoded) > 0 && !utf8.Valid(decoded); i++ {
+			decoded = decoded[:len(decoded)-1]
+		}
+	}
+	if utf8.Valid(decoded) {
+		entry.Value = string(decoded)
+	} else {
+		entry.Binary = true
+		entry.Value = base64.StdEncoding.EncodeToString(decoded)
+	}
+	return entry, nil
+}
+
+// escapeKVPath escapes each segment of a KV path while keeping the separators
+func escapeKVPath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
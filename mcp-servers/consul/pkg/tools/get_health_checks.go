@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/consul/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// HealthCheck is a single Consul health check result
+type HealthCheck struct {
+	Node        string `json:"node"`
+	CheckID     string `json:"check_id"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Output      string `json:"output,omitempty"`
+	ServiceID   string `json:"service_id,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// GetHealthChecks creates a tool to read Consul health checks for a service or in a given state.
+func GetHealthChecks(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("get_health_checks",
+			mcp.WithDescription(`Returns Consul health checks with a status summary. Provide 'service_name' to get the checks of a service, otherwise the checks in 'state' across the datacenter are returned.`),
+			mcp.WithTitleAnnotation("Get Consul health checks"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("service_name",
+				mcp.Description("Optional service name to get the health checks of"),
+			),
+			mcp.WithString("state",
+				mcp.Description("Check state to list when no service is given (default: 'critical')"),
+				mcp.Enum("any", "passing", "warning", "critical"),
+			),
+			mcp.WithString("datacenter",
+				mcp.Description("Optional datacenter to query (default: the agent's datacenter)"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return getHealthChecksHandler(ctx, request, logger)
+		},
+	}
+}
+
+func getHealthChecksHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	serviceName := strings.TrimSpace(request.GetString("service_name", ""))
+	state := strings.TrimSpace(request.GetString("state", "critical"))
+	switch state {
+	case "any", "passing", "warning", "critical":
+	default:
+		return nil, utils.LogAndReturnError(logger, "invalid state: must be 'any', 'passing', 'warning' or 'critical'", nil)
+	}
+
+	consulClient, err := client.GetConsulClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Consul client - please ensure CONSUL_HTTP_ADDR is properly configured", err)
+	}
+
+	path := "health/state/" + state
+	if serviceName != "" {
+		path = "health/checks/" + url.PathEscape(serviceName)
+	}
+
+	body, err := consulClient.Get(ctx, path, datacenterQuery(request))
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "reading health checks", err)
+	}
+
+	checks, err := parseHealthChecks(body)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "decoding health checks", err)
+	}
+
+	summary := map[string]int{}
+	for _, check := range checks {
+		summary[check.Status]++
+	}
+
+	buf, err := json.Marshal(map[string]any{
+		"checks":  checks,
+		"summary": summary,
+		"total":   len(checks),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling health checks", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+func parseHealthChecks(body []byte) ([]HealthCheck, error) {
+	var raw []struct {
+		Node        string
+		CheckID     string
+		Name        string
+		Status      string
+		Output      string
+		ServiceID   string
+		ServiceName string
+		Type        string
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	checks := make([]HealthCheck, 0, len(raw))
+	for _, r := range raw {
+		checks = append(checks, HealthCheck(r))
+	}
+	return checks, nil
+}
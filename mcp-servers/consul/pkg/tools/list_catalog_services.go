@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/consul/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CatalogService is a service registered in the Consul catalog with its tags
+type CatalogService struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// ServiceInstance is a single registered instance of a catalog service
+type ServiceInstance struct {
+	Node           string            `json:"node"`
+	Address        string            `json:"address"`
+	Datacenter     string            `json:"datacenter"`
+	ServiceID      string            `json:"service_id"`
+	ServiceAddress string            `json:"service_address,omitempty"`
+	ServicePort    int               `json:"service_port"`
+	ServiceTags    []string          `json:"service_tags"`
+	ServiceMeta    map[string]string `json:"service_meta,omitempty"`
+}
+
+// ListCatalogServices creates a tool to list the services registered in the Consul catalog.
+func ListCatalogServices(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_catalog_services",
+			mcp.WithDescription(`Lists the services registered in the Consul catalog with their tags. Provide 'service_name' to list the registered instances of a single service instead.`),
+			mcp.WithTitleAnnotation("List Consul catalog services"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("service_name",
+				mcp.Description("Optional service name to list the instances of"),
+			),
+			mcp.WithString("datacenter",
+				mcp.Description("Optional datacenter to query (default: the agent's datacenter)"),
+			),
+			mcp.WithString("filter",
+				mcp.Description("Optional Consul filter expression, e.g. 'ServiceTags contains \"primary\"'"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listCatalogServicesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listCatalogServicesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	serviceName := strings.TrimSpace(request.GetString("service_name", ""))
+	query := datacenterQuery(request)
+	if filter := strings.TrimSpace(request.GetString("filter", "")); filter != "" {
+		query.Set("filter", filter)
+	}
+
+	consulClient, err := client.GetConsulClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Consul client - please ensure CONSUL_HTTP_ADDR is properly configured", err)
+	}
+
+	var result any
+	if serviceName == "" {
+		body, err := consulClient.Get(ctx, "catalog/services", query)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "listing catalog services", err)
+		}
+		services, err := parseCatalogServices(body)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "decoding catalog services", err)
+		}
+		result = map[string]any{"services": services, "total": len(services)}
+	} else {
+		body, err := consulClient.Get(ctx, "catalog/service/"+url.PathEscape(serviceName), query)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "listing service instances", err)
+		}
+		instances, err := parseServiceInstances(body)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "decoding service instances", err)
+		}
+		result = map[string]any{"service": serviceName, "instances": instances, "total": len(instances)}
+	}
+
+	buf, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling catalog services", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+func parseCatalogServices(body []byte) ([]CatalogService, error) {
+	var raw map[string][]string
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	services := make([]CatalogService, 0, len(raw))
+	for name, tags := range raw {
+		if tags == nil {
+			tags = []string{}
+		}
+		services = append(services, CatalogService{Name: name, Tags: tags})
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+func parseServiceInstances(body []byte) ([]ServiceInstance, error) {
+	var raw []struct {
+		Node           string
+		Address        string
+		Datacenter     string
+		ServiceID      string
+		ServiceAddress string
+		ServicePort    int
+		ServiceTags    []string
+		ServiceMeta    map[string]string
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	instances := make([]ServiceInstance, 0, len(raw))
+	for _, r := range raw {
+		instances = append(instances, ServiceInstance{
+			Node:           r.Node,
+			Address:        r.Address,
+			Datacenter:     r.Datacenter,
+			ServiceID:      r.ServiceID,
+			ServiceAddress: r.ServiceAddress,
+			ServicePort:    r.ServicePort,
+			ServiceTags:    r.ServiceTags,
+			ServiceMeta:    r.ServiceMeta,
+		})
+	}
+	return instances, nil
+}
+
+// datacenterQuery builds the query parameters shared by all tools
+func datacenterQuery(request mcp.CallToolRequest) url.Values {
+	query := url.Values{}
+	if datacenter := strings.TrimSpace(request.GetString("datacenter", "")); datacenter != "" {
+		query.Set("dc", datacenter)
+	}
+	return query
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/consul/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Intention is a service mesh intention between a source and a destination service
+type Intention struct {
+	SourceName      string `json:"source_name"`
+	DestinationName string `json:"destination_name"`
+	Action          string `json:"action,omitempty"`
+	Permissions     int    `json:"permissions,omitempty"`
+	Precedence      int    `json:"precedence"`
+	Description     string `json:"description,omitempty"`
+}
+
+// ListIntentions creates a tool to list the service mesh intentions defined in Consul.
+func ListIntentions(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_intentions",
+			mcp.WithDescription(`Lists the Consul service mesh intentions ordered by precedence. Provide 'service_name' to only return intentions where the service is the source or destination.
+Intentions that use L7 permissions have no action and report the number of permissions instead.`),
+			mcp.WithTitleAnnotation("List Consul service intentions"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("service_name",
+				mcp.Description("Optional service name to filter intentions by"),
+			),
+			mcp.WithString("datacenter",
+				mcp.Description("Optional datacenter to query (default: the agent's datacenter)"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listIntentionsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listIntentionsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	serviceName := strings.TrimSpace(request.GetString("service_name", ""))
+	query := datacenterQuery(request)
+	if serviceName != "" {
+		query.Set("filter", serviceFilter(serviceName))
+	}
+
+	consulClient, err := client.GetConsulClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Consul client - please ensure CONSUL_HTTP_ADDR is properly configured", err)
+	}
+
+	body, err := consulClient.Get(ctx, "connect/intentions", query)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing intentions", err)
+	}
+
+	intentions, err := parseIntentions(body)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "decoding intentions", err)
+	}
+
+	buf, err := json.Marshal(map[string]any{
+		"intentions": intentions,
+		"total":      len(intentions),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling intentions", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+// serviceFilter builds a Consul filter expression matching intentions for the service on either side
+func serviceFilter(serviceName string) string {
+	quoted := strings.ReplaceAll(serviceName, `"`, `\"`)
+	return `SourceName == "` + quoted + `" or DestinationName == "` + quoted + `"`
+}
+
+func parseIntentions(body []byte) ([]Intention, error) {
+	var raw []struct {
+		SourceName      string
+		DestinationName string
+		Action          string
+		Permissions     []json.RawMessage
+		Precedence      int
+		Description     string
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	intentions := make([]Intention, 0, len(raw))
+	for _, r := range raw {
+		intentions = append(intentions, Intention{
+			SourceName:      r.SourceName,
+			DestinationName: r.DestinationName,
+			Action:          r.Action,
+			Permissions:     len(r.Permissions),
+			Precedence:      r.Precedence,
+			Description:     r.Description,
+		})
+	}
+	sort.SliceStable(intentions, func(i, j int) bool {
+		return intentions[i].Precedence > intentions[j].Precedence
+	})
+	return intentions, nil
+}
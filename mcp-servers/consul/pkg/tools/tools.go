@@ -0,0 +1,26 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+func RegisterTools(hcServer *server.MCPServer, logger *log.Logger) {
+	// Catalog and health tools
+	listCatalogServicesTool := ListCatalogServices(logger)
+	hcServer.AddTool(listCatalogServicesTool.Tool, listCatalogServicesTool.Handler)
+
+	getHealthChecksTool := GetHealthChecks(logger)
+	hcServer.AddTool(getHealthChecksTool.Tool, getHealthChecksTool.Handler)
+
+	// KV tools
+	browseKVTool := BrowseKV(logger)
+	hcServer.AddTool(browseKVTool.Tool, browseKVTool.Handler)
+
+	// Service mesh tools
+	listIntentionsTool := ListIntentions(logger)
+	hcServer.AddTool(listIntentionsTool.Tool, listIntentionsTool.Handler)
+}
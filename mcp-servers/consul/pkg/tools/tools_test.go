@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCatalogServices(t *testing.T) {
+	services, err := parseCatalogServices([]byte(`{"web":["v1","primary"],"consul":null}`))
+	require.NoError(t, err)
+	assert.Equal(t, []CatalogService{
+		{Name: "consul", Tags: []string{}},
+		{Name: "web", Tags: []string{"v1", "primary"}},
+	}, services)
+}
+
+func TestParseKVEntry(t *testing.T) {
+	encode := func(value []byte) string {
+		return base64.StdEncoding.EncodeToString(value)
+	}
+
+	t.Run("text value", func(t *testing.T) {
+		entry, err := parseKVEntry([]byte(`[{"Key":"config/web","Value":"` + encode([]byte("port = 8080")) + `","ModifyIndex":12}]`))
+		require.NoError(t, err)
+		assert.Equal(t, "port = 8080", entry.Value)
+		assert.False(t, entry.Binary)
+		assert.Equal(t, uint64(12), entry.ModifyIndex)
+	})
+
+	t.Run("binary value", func(t *testing.T) {
+		entry, err := parseKVEntry([]byte(`[{"Key":"bin","Value":"` + encode([]byte{0xff, 0xfe}) + `"}]`))
+		require.NoError(t, err)
+		assert.True(t, entry.Binary)
+		assert.Equal(t, encode([]byte{0xff, 0xfe}), entry.Value)
+	})
+
+	t.Run("large value is truncated", func(t *testing.T) {
+		value := strings.Repeat("é", maxKVValueBytes)
+		entry, err := parseKVEntry([]byte(`[{"Key":"big","Value":"` + encode([]byte(value)) + `"}]`))
+		require.NoError(t, err)
+		assert.True(t, entry.Truncated)
+		assert.False(t, entry.Binary)
+		assert.LessOrEqual(t, len(entry.Value), maxKVValueBytes)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := parseKVEntry([]byte(`[]`))
+		assert.ErrorIs(t, err, errNoKVEntry)
+	})
+}
+
+func TestParseIntentions(t *testing.T) {
+	intentions, err := parseIntentions([]byte(`[
+		{"SourceName":"*","DestinationName":"db","Action":"deny","Precedence":6},
+		{"SourceName":"web","DestinationName":"api","Permissions":[{},{}],"Precedence":9}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, intentions, 2)
+	assert.Equal(t, "web", intentions[0].SourceName)
+	assert.Equal(t, 2, intentions[0].Permissions)
+	assert.Equal(t, "deny", intentions[1].Action)
+}
+
+func TestServiceFilter(t *testing.T) {
+	assert.Equal(t, `SourceName == "web" or DestinationName == "web"`, serviceFilter("web"))
+	assert.Equal(t, `SourceName == "a\"b" or DestinationName == "a\"b"`, serviceFilter(`a"b`))
+}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/mcpserver"
+	packerClient "github.com/vignesan/infra-genie/mcp-servers/packer/pkg/client"
+	"github.com/vignesan/infra-genie/mcp-servers/packer/pkg/tools"
+	"github.com/vignesan/infra-genie/mcp-servers/packer/version"
+)
+
+var serverConfig = mcpserver.Config{
+	Name:                "packer-mcp-server",
+	Title:               "HCP Packer MCP Server",
+	Description:         `An HCP Packer MCP server that exposes tools for image buckets, versions and channels.`,
+	Version:             version.Version,
+	BuildInfo:           fmt.Sprintf("Version: %s\nCommit: %s\nBuild Date: %s", version.GetHumanVersion(), version.GitCommit, version.BuildDate),
+	Register:            tools.RegisterTools,
+	ContextMiddleware:   packerClient.ContextHeaders.Middleware,
+	OnRegisterSession:   packerClient.SessionClients.OnRegisterSession,
+	OnUnregisterSession: packerClient.SessionClients.OnUnregisterSession,
+}
+
+func main() {
+	mcpserver.Execute(serverConfig)
+}
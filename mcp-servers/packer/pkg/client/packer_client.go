@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-mcp-server/pkg/mcpserver"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	HCPClientID          = "HCP_CLIENT_ID"
+	HCPClientSecret      = "HCP_CLIENT_SECRET"
+	HCPOrganizationID    = "HCP_ORGANIZATION_ID"
+	HCPProjectID         = "HCP_PROJECT_ID"
+	HCPAPIAddress        = "HCP_API_ADDRESS"
+	HCPAuthURL           = "HCP_AUTH_URL"
+	DefaultHCPAPIAddress = "https://api.cloud.hashicorp.com"
+	DefaultHCPAuthURL    = "https://auth.idp.hashicorp.com"
+
+	// packerAPIVersion is the version of the HCP Packer API used by the client
+	packerAPIVersion = "2023-01-01"
+	// hcpAudience is the audience requested for HCP service principal tokens
+	hcpAudience = "https://api.hashicorp.cloud"
+	// tokenExpiryMargin renews the access token shortly before it expires
+	tokenExpiryMargin = 30 * time.Second
+)
+
+// ErrNotFound is returned when HCP Packer responds with 404, e.g. for a missing bucket or channel
+var ErrNotFound = errors.New("not found")
+
+// PackerClient is a minimal client for the HCP Packer API authenticated with a service principal
+type PackerClient struct {
+	APIAddress     string
+	AuthURL        string
+	ClientID       string
+	ClientSecret   string
+	OrganizationID string
+	ProjectID      string
+	HTTPClient     *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewPackerClient creates a new HCP Packer client
+func NewPackerClient(apiAddress string, authURL string, clientID string, clientSecret string, organizationID string, projectID string, logger *log.Logger) (*PackerClient, error) {
+	if clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("%s and %s are required", HCPClientID, HCPClientSecret)
+	}
+	if organizationID == "" || projectID == "" {
+		return nil, fmt.Errorf("%s and %s are required", HCPOrganizationID, HCPProjectID)
+	}
+	for _, address := range []string{apiAddress, authURL} {
+		if _, err := url.ParseRequestURI(address); err != nil {
+			return nil, fmt.Errorf("invalid HCP address %q: %w", address, err)
+		}
+	}
+
+	httpClient := cleanhttp.DefaultPooledClient()
+	httpClient.Timeout = 10 * time.Second
+
+	client := &PackerClient{
+		APIAddress:     strings.TrimSuffix(apiAddress, "/"),
+		AuthURL:        strings.TrimSuffix(authURL, "/"),
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		OrganizationID: organizationID,
+		ProjectID:      projectID,
+		HTTPClient:     httpClient,
+	}
+
+	return client, nil
+}
+
+// ContextHeaders are the HCP Packer settings read from the headers of the StreamableHTTP requests
+var ContextHeaders = mcpserver.ContextHeaders{
+	Service: "HCP Packer",
+	Address: HCPAPIAddress,
+	Token:   HCPClientSecret,
+	Others:  []string{HCPAuthURL, HCPClientID, HCPOrganizationID, HCPProjectID},
+}
+
+// SessionClients holds the HCP Packer client of each session
+var SessionClients = &mcpserver.SessionClients[*PackerClient]{
+	Service: "HCP Packer",
+	Create:  newPackerClientFromContext,
+	Missing: "HCP_CLIENT_ID, HCP_CLIENT_SECRET, HCP_ORGANIZATION_ID and HCP_PROJECT_ID are set",
+}
+
+// GetPackerClientFromContext returns the HCP Packer client of the session of ctx
+func GetPackerClientFromContext(ctx context.Context, logger *log.Logger) (*PackerClient, error) {
+	return SessionClients.FromContext(ctx, logger)
+}
+
+// newPackerClientFromContext creates an HCP Packer client using the values stored in the request context or environment
+func newPackerClientFromContext(ctx context.Context, logger *log.Logger) (*PackerClient, error) {
+	return NewPackerClient(
+		mcpserver.ContextValue(ctx, HCPAPIAddress, DefaultHCPAPIAddress),
+		mcpserver.ContextValue(ctx, HCPAuthURL, DefaultHCPAuthURL),
+		mcpserver.ContextValue(ctx, HCPClientID, ""),
+		mcpserver.ContextValue(ctx, HCPClientSecret, ""),
+		mcpserver.ContextValue(ctx, HCPOrganizationID, ""),
+		mcpserver.ContextValue(ctx, HCPProjectID, ""),
+		logger,
+	)
+}
+
+// Get performs a GET request against a path relative to the project, e.g. buckets/ubuntu/channels, and returns the raw body
+func (c *PackerClient) Get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/packer/%s/organizations/%s/projects/%s/%s",
+		c.APIAddress, packerAPIVersion, url.PathEscape(c.OrganizationID), url.PathEscape(c.ProjectID), strings.TrimPrefix(path, "/"))
+	if encoded := query.Encode(); encoded != "" {
+		reqURL += "?" + encoded
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading HCP Packer response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("HCP Packer returned %s for %s: %w", resp.Status, path, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HCP Packer returned %s for %s: %s", resp.Status, path, errorMessage(body))
+	}
+
+	return body, nil
+}
+
+// accessToken returns a cached service principal token, requesting a new one when it is about to expire
+func (c *PackerClient) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.ClientID},
+		"client_secret": {c.ClientSecret},
+		"audience":      {hcpAudience},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.AuthURL+"/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting HCP access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading HCP token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting HCP access token returned %s: %s", resp.Status, errorMessage(body))
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", fmt.Errorf("decoding HCP token response: %w", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", fmt.Errorf("HCP token response did not contain an access token")
+	}
+
+	c.token = tokenResponse.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return c.token, nil
+}
+
+// errorMessage extracts the message from an HCP error response, falling back to the raw body
+func errorMessage(body []byte) string {
+	var apiError struct {
+		Message          string `json:"message"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &apiError); err == nil {
+		if apiError.Message != "" {
+			return apiError.Message
+		}
+		if apiError.ErrorDescription != "" {
+			return apiError.ErrorDescription
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
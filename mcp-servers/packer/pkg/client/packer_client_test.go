@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackerClientGet(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth2/token":
+			tokenRequests++
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "test-id", r.PostForm.Get("client_id"))
+			assert.Equal(t, "test-secret", r.PostForm.Get("client_secret"))
+			w.Write([]byte(`{"access_token":"test-token","expires_in":3600}`))
+		case "/packer/2023-01-01/organizations/org-1/projects/proj-1/buckets":
+			assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"buckets":[]}`))
+		case "/packer/2023-01-01/organizations/org-1/projects/proj-1/buckets/denied":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code":7,"message":"permission denied"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewPackerClient(server.URL, server.URL, "test-id", "test-secret", "org-1", "proj-1", logger)
+	require.NoError(t, err)
+
+	body, err := client.Get(t.Context(), "buckets", nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"buckets":[]}`, string(body))
+
+	_, err = client.Get(t.Context(), "buckets/missing", nil)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = client.Get(t.Context(), "buckets/denied", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+
+	// The access token is cached between requests
+	assert.Equal(t, 1, tokenRequests)
+}
+
+func TestNewPackerClientRequiresCredentials(t *testing.T) {
+	logger := log.New()
+
+	_, err := NewPackerClient(DefaultHCPAPIAddress, DefaultHCPAuthURL, "", "", "org-1", "proj-1", logger)
+	assert.ErrorContains(t, err, HCPClientID)
+
+	_, err = NewPackerClient(DefaultHCPAPIAddress, DefaultHCPAuthURL, "id", "secret", "org-1", "", logger)
+	assert.ErrorContains(t, err, HCPProjectID)
+}
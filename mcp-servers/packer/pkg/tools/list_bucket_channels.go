@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/packer/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Channel is a named pointer to a bucket version, e.g. 'production'
+type Channel struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Revoked     bool   `json:"revoked"`
+	Managed     bool   `json:"managed"`
+	Restricted  bool   `json:"restricted"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// rawChannel mirrors the channel object returned by the HCP Packer API
+type rawChannel struct {
+	Name       string      `json:"name"`
+	Managed    bool        `json:"managed"`
+	Restricted bool        `json:"restricted"`
+	UpdatedAt  string      `json:"updated_at"`
+	Version    *rawVersion `json:"version"`
+}
+
+// ListBucketChannels creates a tool to list the channels of an HCP Packer bucket.
+func ListBucketChannels(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_bucket_channels",
+			mcp.WithDescription(`Lists the channels of an HCP Packer bucket and the version each channel points to. Channels without a version are unassigned.`),
+			mcp.WithTitleAnnotation("List HCP Packer bucket channels"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("bucket_name",
+				mcp.Required(),
+				mcp.Description("The name of the bucket, e.g. 'ubuntu-base'"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listBucketChannelsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listBucketChannelsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	bucketName, err := request.RequireString("bucket_name")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "the 'bucket_name' parameter is required", err)
+	}
+	bucketName = strings.TrimSpace(bucketName)
+
+	packerClient, err := client.GetPackerClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting HCP Packer client - please ensure HCP_CLIENT_ID, HCP_CLIENT_SECRET, HCP_ORGANIZATION_ID and HCP_PROJECT_ID are set", err)
+	}
+
+	body, err := packerClient.Get(ctx, "buckets/"+url.PathEscape(bucketName)+"/channels", nil)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			return nil, utils.LogAndReturnError(logger, "bucket '"+bucketName+"' not found", err)
+		}
+		return nil, utils.LogAndReturnError(logger, "listing bucket channels", err)
+	}
+
+	channels, err := parseChannels(body, time.Now())
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "decoding bucket channels", err)
+	}
+
+	buf, err := json.Marshal(map[string]any{
+		"bucket":   bucketName,
+		"channels": channels,
+		"total":    len(channels),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling bucket channels", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+func parseChannels(body []byte, now time.Time) ([]Channel, error) {
+	var raw struct {
+		Channels []rawChannel `json:"channels"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	channels := make([]Channel, 0, len(raw.Channels))
+	for _, r := range raw.Channels {
+		channel := Channel{
+			Name:       r.Name,
+			Managed:    r.Managed,
+			Restricted: r.Restricted,
+			UpdatedAt:  r.UpdatedAt,
+		}
+		if r.Version != nil {
+			version := toImageVersion(*r.Version, now)
+			channel.Version = version.Name
+			channel.Fingerprint = version.Fingerprint
+			channel.Revoked = version.Revoked
+		}
+		channels = append(channels, channel)
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+	return channels, nil
+}
@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/packer/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ImageVersion is a version of a bucket, i.e. the images produced by one Packer build run
+type ImageVersion struct {
+	Name        string  `json:"name"`
+	Fingerprint string  `json:"fingerprint"`
+	Status      string  `json:"status"`
+	Revoked     bool    `json:"revoked"`
+	RevokeAt    string  `json:"revoke_at,omitempty"`
+	CreatedAt   string  `json:"created_at,omitempty"`
+	Builds      []Build `json:"builds"`
+}
+
+// Build is the output of a single Packer source for an image version
+type Build struct {
+	Platform      string     `json:"platform"`
+	ComponentType string     `json:"component_type"`
+	Status        string     `json:"status"`
+	Artifacts     []Artifact `json:"artifacts"`
+}
+
+// Artifact is an image produced by a build, e.g. an AMI in a given region
+type Artifact struct {
+	ExternalIdentifier string `json:"external_identifier"`
+	Region             string `json:"region,omitempty"`
+}
+
+// rawVersion mirrors the version object returned by the HCP Packer API
+type rawVersion struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	Status      string `json:"status"`
+	RevokeAt    string `json:"revoke_at"`
+	CreatedAt   string `json:"created_at"`
+	Builds      []struct {
+		Platform      string `json:"platform"`
+		ComponentType string `json:"component_type"`
+		Status        string `json:"status"`
+		Artifacts     []struct {
+			ExternalIdentifier string `json:"external_identifier"`
+			Region             string `json:"region"`
+		} `json:"artifacts"`
+	} `json:"builds"`
+}
+
+// ListBucketVersions creates a tool to list the image versions of an HCP Packer bucket.
+func ListBucketVersions(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_bucket_versions",
+			mcp.WithDescription(`Lists the image versions of an HCP Packer bucket, newest first, with the images built for every platform and region. Revoked versions are flagged and can be excluded.`),
+			mcp.WithTitleAnnotation("List HCP Packer bucket versions"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("bucket_name",
+				mcp.Required(),
+				mcp.Description("The name of the bucket, e.g. 'ubuntu-base'"),
+			),
+			mcp.WithString("include_revoked",
+				mcp.Description("Whether to include revoked versions (default: 'true')"),
+				mcp.Enum("true", "false"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum number of versions to return (default: 20)"),
+				mcp.Min(1),
+				mcp.Max(200),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listBucketVersionsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listBucketVersionsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	bucketName, err := request.RequireString("bucket_name")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "the 'bucket_name' parameter is required", err)
+	}
+	bucketName = strings.TrimSpace(bucketName)
+	includeRevoked := request.GetString("include_revoked", "true") == "true"
+	limit := request.GetInt("limit", 20)
+
+	packerClient, err := client.GetPackerClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting HCP Packer client - please ensure HCP_CLIENT_ID, HCP_CLIENT_SECRET, HCP_ORGANIZATION_ID and HCP_PROJECT_ID are set", err)
+	}
+
+	pages, err := listAllPages(ctx, packerClient, "buckets/"+url.PathEscape(bucketName)+"/versions", nil)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			return nil, utils.LogAndReturnError(logger, "bucket '"+bucketName+"' not found", err)
+		}
+		return nil, utils.LogAndReturnError(logger, "listing bucket versions", err)
+	}
+
+	now := time.Now()
+	versions := []ImageVersion{}
+	for _, page := range pages {
+		var raw struct {
+			Versions []rawVersion `json:"versions"`
+		}
+		if err := json.Unmarshal(page, &raw); err != nil {
+			return nil, utils.LogAndReturnError(logger, "decoding bucket versions", err)
+		}
+		for _, r := range raw.Versions {
+			version := toImageVersion(r, now)
+			if version.Revoked && !includeRevoked {
+				continue
+			}
+			versions = append(versions, version)
+		}
+	}
+
+	// created_at is an RFC 3339 timestamp so the newest versions sort first lexically
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].CreatedAt > versions[j].CreatedAt
+	})
+
+	total := len(versions)
+	if limit > 0 && len(versions) > limit {
+		versions = versions[:limit]
+	}
+
+	buf, err := json.Marshal(map[string]any{
+		"bucket":   bucketName,
+		"versions": versions,
+		"total":    total,
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling bucket versions", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+// toImageVersion converts an API version and determines whether it is revoked at the given time
+func toImageVersion(r rawVersion, now time.Time) ImageVersion {
+	version := ImageVersion{
+		Name:        r.Name,
+		Fingerprint: r.Fingerprint,
+		Status:      r.Status,
+		RevokeAt:    r.RevokeAt,
+		CreatedAt:   r.CreatedAt,
+		Builds:      make([]Build, 0, len(r.Builds)),
+	}
+
+	// A revocation can be scheduled in the future, so only a past revoke_at revokes the version
+	version.Revoked = strings.HasSuffix(r.Status, "REVOKED")
+	if revokeAt, err := time.Parse(time.RFC3339, r.RevokeAt); err == nil && !revokeAt.After(now) {
+		version.Revoked = true
+	}
+
+	for _, b := range r.Builds {
+		build := Build{
+			Platform:      b.Platform,
+			ComponentType: b.ComponentType,
+			Status:        b.Status,
+			Artifacts:     make([]Artifact, 0, len(b.Artifacts)),
+		}
+		for _, a := range b.Artifacts {
+			build.Artifacts = append(build.Artifacts, Artifact(a))
+		}
+		version.Builds = append(version.Builds, build)
+	}
+	return version
+}
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/packer/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ResolvedImage is an image of a channel's version matching the requested platform and region
+type ResolvedImage struct {
+	Platform           string `json:"platform"`
+	ComponentType      string `json:"component_type"`
+	Region             string `json:"region,omitempty"`
+	ExternalIdentifier string `json:"external_identifier"`
+}
+
+// ChannelResolution is the result of resolving the images of a channel
+type ChannelResolution struct {
+	Bucket      string          `json:"bucket"`
+	Channel     string          `json:"channel"`
+	Version     string          `json:"version"`
+	Fingerprint string          `json:"fingerprint"`
+	Revoked     bool            `json:"revoked"`
+	Images      []ResolvedImage `json:"images"`
+	Warnings    []string        `json:"warnings,omitempty"`
+}
+
+// ResolveChannelImage creates a tool to resolve the image a channel of an HCP Packer bucket points to.
+func ResolveChannelImage(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("resolve_channel_image",
+			mcp.WithDescription(`Resolves the image a channel of an HCP Packer bucket currently points to, e.g. the AMI ID of the 'production' channel in 'us-east-1'.
+This is the same lookup the hcp_packer_artifact data source performs in Terraform. Revoked versions are reported with a warning.`),
+			mcp.WithTitleAnnotation("Resolve an HCP Packer channel image"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("bucket_name",
+				mcp.Required(),
+				mcp.Description("The name of the bucket, e.g. 'ubuntu-base'"),
+			),
+			mcp.WithString("channel_name",
+				mcp.Required(),
+				mcp.Description("The name of the channel, e.g. 'production'"),
+			),
+			mcp.WithString("platform",
+				mcp.Description("Optional platform to resolve the image for, e.g. 'aws', 'azure' or 'gce'"),
+			),
+			mcp.WithString("region",
+				mcp.Description("Optional region to resolve the image for, e.g. 'us-east-1'"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return resolveChannelImageHandler(ctx, request, logger)
+		},
+	}
+}
+
+func resolveChannelImageHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	bucketName, err := request.RequireString("bucket_name")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "the 'bucket_name' parameter is required", err)
+	}
+	channelName, err := request.RequireString("channel_name")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "the 'channel_name' parameter is required", err)
+	}
+	bucketName = strings.TrimSpace(bucketName)
+	channelName = strings.TrimSpace(channelName)
+	platform := strings.TrimSpace(request.GetString("platform", ""))
+	region := strings.TrimSpace(request.GetString("region", ""))
+
+	packerClient, err := client.GetPackerClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting HCP Packer client - please ensure HCP_CLIENT_ID, HCP_CLIENT_SECRET, HCP_ORGANIZATION_ID and HCP_PROJECT_ID are set", err)
+	}
+
+	body, err := packerClient.Get(ctx, "buckets/"+url.PathEscape(bucketName)+"/channels/"+url.PathEscape(channelName), nil)
+	if err != nil {
+		if errors.Is(err, client.ErrNotFound) {
+			return nil, utils.LogAndReturnError(logger, fmt.Sprintf("channel '%s' not found in bucket '%s'", channelName, bucketName), err)
+		}
+		return nil, utils.LogAndReturnError(logger, "reading bucket channel", err)
+	}
+
+	var raw struct {
+		Channel rawChannel `json:"channel"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, utils.LogAndReturnError(logger, "decoding bucket channel", err)
+	}
+	if raw.Channel.Version == nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("channel '%s' of bucket '%s' is not assigned to a version", channelName, bucketName), nil)
+	}
+
+	resolution := resolveImages(bucketName, channelName, toImageVersion(*raw.Channel.Version, time.Now()), platform, region)
+	buf, err := json.Marshal(resolution)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling channel resolution", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+// resolveImages selects the images of the version matching the platform and region, an empty filter matches everything
+func resolveImages(bucketName string, channelName string, version ImageVersion, platform string, region string) ChannelResolution {
+	resolution := ChannelResolution{
+		Bucket:      bucketName,
+		Channel:     channelName,
+		Version:     version.Name,
+		Fingerprint: version.Fingerprint,
+		Revoked:     version.Revoked,
+		Images:      []ResolvedImage{},
+	}
+
+	for _, build := range version.Builds {
+		if platform != "" && !strings.EqualFold(build.Platform, platform) {
+			continue
+		}
+		for _, artifact := range build.Artifacts {
+			if region != "" && !strings.EqualFold(artifact.Region, region) {
+				continue
+			}
+			resolution.Images = append(resolution.Images, ResolvedImage{
+				Platform:           build.Platform,
+				ComponentType:      build.ComponentType,
+				Region:             artifact.Region,
+				ExternalIdentifier: artifact.ExternalIdentifier,
+			})
+		}
+	}
+
+	if version.Revoked {
+		resolution.Warnings = append(resolution.Warnings, fmt.Sprintf("version %s is revoked, new infrastructure should not be built from it", version.Name))
+	}
+	if len(resolution.Images) == 0 {
+		resolution.Warnings = append(resolution.Warnings, fmt.Sprintf("version %s has no images matching platform %q and region %q", version.Name, platform, region))
+	}
+	return resolution
+}
@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/packer/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxListPages bounds the number of pages fetched when following HCP pagination tokens
+const maxListPages = 20
+
+// Bucket is an HCP Packer bucket, i.e. an image family built from one Packer template
+type Bucket struct {
+	Name          string            `json:"name"`
+	Description   string            `json:"description,omitempty"`
+	Platforms     []string          `json:"platforms"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	VersionCount  string            `json:"version_count,omitempty"`
+	LatestVersion string            `json:"latest_version,omitempty"`
+	UpdatedAt     string            `json:"updated_at,omitempty"`
+}
+
+// SearchBuckets creates a tool to search the HCP Packer buckets of the project.
+func SearchBuckets(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("search_buckets",
+			mcp.WithDescription(`Searches the HCP Packer buckets of the configured project. A bucket matches when 'query' is found in its name, description or labels (case-insensitive); an empty query lists all buckets.
+Use the bucket name with list_bucket_versions, list_bucket_channels and resolve_channel_image.`),
+			mcp.WithTitleAnnotation("Search HCP Packer buckets"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("query",
+				mcp.Description("Optional text to search for, e.g. 'ubuntu'"),
+			),
+			mcp.WithString("platform",
+				mcp.Description("Optional platform the bucket must have images for, e.g. 'aws', 'azure' or 'gce'"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum number of buckets to return (default: 50)"),
+				mcp.Min(1),
+				mcp.Max(500),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return searchBucketsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func searchBucketsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	query := strings.TrimSpace(request.GetString("query", ""))
+	platform := strings.TrimSpace(request.GetString("platform", ""))
+	limit := request.GetInt("limit", 50)
+
+	packerClient, err := client.GetPackerClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting HCP Packer client - please ensure HCP_CLIENT_ID, HCP_CLIENT_SECRET, HCP_ORGANIZATION_ID and HCP_PROJECT_ID are set", err)
+	}
+
+	pages, err := listAllPages(ctx, packerClient, "buckets", nil)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing buckets", err)
+	}
+
+	var buckets []Bucket
+	for _, page := range pages {
+		parsed, err := parseBuckets(page)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "decoding buckets", err)
+		}
+		buckets = append(buckets, parsed...)
+	}
+
+	matches := filterBuckets(buckets, query, platform)
+	total := len(matches)
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	buf, err := json.Marshal(map[string]any{
+		"buckets": matches,
+		"total":   total,
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling buckets", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+func parseBuckets(body []byte) ([]Bucket, error) {
+	var raw struct {
+		Buckets []struct {
+			Name          string            `json:"name"`
+			Description   string            `json:"description"`
+			Platforms     []string          `json:"platforms"`
+			Labels        map[string]string `json:"labels"`
+			VersionCount  string            `json:"version_count"`
+			UpdatedAt     string            `json:"updated_at"`
+			LatestVersion *struct {
+				Name string `json:"name"`
+			} `json:"latest_version"`
+		} `json:"buckets"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+
+	buckets := make([]Bucket, 0, len(raw.Buckets))
+	for _, r := range raw.Buckets {
+		bucket := Bucket{
+			Name:         r.Name,
+			Description:  r.Description,
+			Platforms:    r.Platforms,
+			Labels:       r.Labels,
+			VersionCount: r.VersionCount,
+			UpdatedAt:    r.UpdatedAt,
+		}
+		if bucket.Platforms == nil {
+			bucket.Platforms = []string{}
+		}
+		if r.LatestVersion != nil {
+			bucket.LatestVersion = r.LatestVersion.Name
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// filterBuckets returns the buckets matching the query and platform, sorted by name
+func filterBuckets(buckets []Bucket, query string, platform string) []Bucket {
+	query = strings.ToLower(query)
+	matches := []Bucket{}
+	for _, bucket := range buckets {
+		if platform != "" && !containsFold(bucket.Platforms, platform) {
+			continue
+		}
+		if query != "" && !bucketMatches(bucket, query) {
+			continue
+		}
+		matches = append(matches, bucket)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Name < matches[j].Name
+	})
+	return matches
+}
+
+func bucketMatches(bucket Bucket, query string) bool {
+	if strings.Contains(strings.ToLower(bucket.Name), query) || strings.Contains(strings.ToLower(bucket.Description), query) {
+		return true
+	}
+	for key, value := range bucket.Labels {
+		if strings.Contains(strings.ToLower(key), query) || strings.Contains(strings.ToLower(value), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// listAllPages follows the HCP pagination tokens of a list endpoint and returns the raw body of every page
+func listAllPages(ctx context.Context, packerClient *client.PackerClient, path string, query url.Values) ([][]byte, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+
+	var pages [][]byte
+	for range maxListPages {
+		body, err := packerClient.Get(ctx, path, query)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, body)
+
+		var page struct {
+			Pagination struct {
+				NextPageToken string `json:"next_page_token"`
+			} `json:"pagination"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		if page.Pagination.NextPageToken == "" {
+			break
+		}
+		query.Set("pagination.next_page_token", page.Pagination.NextPageToken)
+	}
+	return pages, nil
+}
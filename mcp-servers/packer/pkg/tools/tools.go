@@ -0,0 +1,25 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+func RegisterTools(hcServer *server.MCPServer, logger *log.Logger) {
+	// Bucket tools
+	searchBucketsTool := SearchBuckets(logger)
+	hcServer.AddTool(searchBucketsTool.Tool, searchBucketsTool.Handler)
+
+	listBucketVersionsTool := ListBucketVersions(logger)
+	hcServer.AddTool(listBucketVersionsTool.Tool, listBucketVersionsTool.Handler)
+
+	// Channel tools
+	listBucketChannelsTool := ListBucketChannels(logger)
+	hcServer.AddTool(listBucketChannelsTool.Tool, listBucketChannelsTool.Handler)
+
+	resolveChannelImageTool := ResolveChannelImage(logger)
+	hcServer.AddTool(resolveChannelImageTool.Tool, resolveChannelImageTool.Handler)
+}
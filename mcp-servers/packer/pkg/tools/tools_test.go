@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterBuckets(t *testing.T) {
+	buckets, err := parseBuckets([]byte(`{"buckets":[
+		{"name":"windows-2022","platforms":["azure"],"latest_version":{"name":"v4"}},
+		{"name":"ubuntu-base","description":"Hardened Ubuntu","platforms":["aws","gce"]},
+		{"name":"golden","labels":{"os":"ubuntu"},"platforms":["aws"]}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, buckets, 3)
+	assert.Equal(t, "v4", buckets[0].LatestVersion)
+
+	names := func(buckets []Bucket) []string {
+		result := []string{}
+		for _, bucket := range buckets {
+			result = append(result, bucket.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"golden", "ubuntu-base", "windows-2022"}, names(filterBuckets(buckets, "", "")))
+	assert.Equal(t, []string{"golden", "ubuntu-base"}, names(filterBuckets(buckets, "UBUNTU", "")))
+	assert.Equal(t, []string{"ubuntu-base"}, names(filterBuckets(buckets, "", "GCE")))
+	assert.Empty(t, filterBuckets(buckets, "rhel", ""))
+}
+
+func TestToImageVersionRevocation(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.False(t, toImageVersion(rawVersion{Name: "v1", Status: "VERSION_ACTIVE"}, now).Revoked)
+	assert.True(t, toImageVersion(rawVersion{Name: "v1", Status: "VERSION_REVOKED"}, now).Revoked)
+	assert.True(t, toImageVersion(rawVersion{Name: "v1", RevokeAt: "2025-05-01T00:00:00Z"}, now).Revoked)
+	// A revocation scheduled in the future does not revoke the version yet
+	assert.False(t, toImageVersion(rawVersion{Name: "v1", RevokeAt: "2025-07-01T00:00:00Z"}, now).Revoked)
+}
+
+func TestParseChannels(t *testing.T) {
+	channels, err := parseChannels([]byte(`{"channels":[
+		{"name":"production","version":{"name":"v3","fingerprint":"abc"}},
+		{"name":"latest","managed":true,"version":{"name":"v4","fingerprint":"def"}},
+		{"name":"staging"}
+	]}`), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []Channel{
+		{Name: "latest", Version: "v4", Fingerprint: "def", Managed: true},
+		{Name: "production", Version: "v3", Fingerprint: "abc"},
+		{Name: "staging"},
+	}, channels)
+}
+
+func TestResolveImages(t *testing.T) {
+	version := ImageVersion{
+		Name:        "v3",
+		Fingerprint: "abc",
+		Builds: []Build{
+			{Platform: "aws", ComponentType: "amazon-ebs.ubuntu", Artifacts: []Artifact{
+				{ExternalIdentifier: "ami-east", Region: "us-east-1"},
+				{ExternalIdentifier: "ami-west", Region: "us-west-2"},
+			}},
+			{Platform: "gce", ComponentType: "googlecompute.ubuntu", Artifacts: []Artifact{
+				{ExternalIdentifier: "ubuntu-v3", Region: "us-central1-a"},
+			}},
+		},
+	}
+
+	resolution := resolveImages("ubuntu-base", "production", version, "aws", "us-west-2")
+	require.Len(t, resolution.Images, 1)
+	assert.Equal(t, "ami-west", resolution.Images[0].ExternalIdentifier)
+	assert.Empty(t, resolution.Warnings)
+
+	resolution = resolveImages("ubuntu-base", "production", version, "", "")
+	assert.Len(t, resolution.Images, 3)
+
+	version.Revoked = true
+	resolution = resolveImages("ubuntu-base", "production", version, "azure", "")
+	assert.Empty(t, resolution.Images)
+	assert.Len(t, resolution.Warnings, 2)
+}
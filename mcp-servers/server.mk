@@ -0,0 +1,51 @@
+SHELL := /usr/bin/env bash -euo pipefail -c
+
+# Common targets of the Go MCP servers next to terraform, e.g. vault. The Makefile of a server sets SERVER,
+# the name of its directory, before including this file.
+MODULE = github.com/vignesan/infra-genie/mcp-servers/$(SERVER)
+
+BINARY_NAME ?= $(SERVER)-mcp-server
+VERSION ?= $(if $(shell printenv VERSION),$(shell printenv VERSION),dev)
+
+GO=go
+
+# Build flags
+LDFLAGS=-ldflags="-s -w -X $(MODULE)/version.GitCommit=$(shell git rev-parse HEAD) -X $(MODULE)/version.BuildDate=$(shell git show --no-show-signature -s --format=%cd --date=format:"%Y-%m-%dT%H:%M:%SZ" HEAD)"
+
+.PHONY: all build test clean deps run-http help
+
+# Default target
+all: build
+
+ARCH     = $(shell A=$$(uname -m); [ $$A = x86_64 ] && A=amd64; echo $$A)
+OS       = $(shell uname | tr [[:upper:]] [[:lower:]])
+build:
+	CGO_ENABLED=0 GOARCH=$(ARCH) GOOS=$(OS) $(GO) build $(LDFLAGS) -o bin/$(BINARY_NAME) ./cmd/$(SERVER)-mcp-server
+
+# Run tests
+test:
+	$(GO) test -v ./...
+
+# Clean build artifacts
+clean:
+	rm -rf bin
+	$(GO) clean
+
+# Download dependencies
+deps:
+	$(GO) mod download
+
+# Run HTTP server locally
+run-http:
+	bin/$(BINARY_NAME) streamable-http --transport-port 8080 --transport-host 0.0.0.0
+
+# Show help
+help:
+	@echo "Available commands:"
+	@echo "  all           - Build the binary (default)"
+	@echo "  build         - Build the binary"
+	@echo "  test          - Run all tests"
+	@echo "  clean         - Remove build artifacts"
+	@echo "  deps          - Download dependencies"
+	@echo "  run-http      - Run HTTP server locally on port 8080"
+	@echo "  help          - Show this help message"
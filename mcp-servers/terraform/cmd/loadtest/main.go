@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// loadtest drives concurrent MCP sessions with a mix of tool calls against a running
+// StreamableHTTP server, e.g. to validate the rate limiter and the registry cache under load:
+//
+//	go run ./cmd/loadtest -url http://localhost:8080/mcp -sessions 20 -duration 1m -pid $(pgrep terraform-mcp-server)
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/internal/loadtest"
+)
+
+// headerFlags collects the repeated -header flags
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(map[string]string(h))
+}
+
+func (h headerFlags) Set(value string) error {
+	name, headerValue, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("expected 'Name: value', got %q", value)
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+	return nil
+}
+
+func main() {
+	cfg := loadtest.Config{Headers: headerFlags{}}
+	var callsFile string
+	var jsonOutput bool
+
+	flag.StringVar(&cfg.URL, "url", "http://localhost:8080/mcp", "MCP endpoint of the server")
+	flag.IntVar(&cfg.Sessions, "sessions", 10, "number of concurrent sessions")
+	flag.DurationVar(&cfg.Duration, "duration", 30*time.Second, "how long the sessions call tools")
+	flag.StringVar(&callsFile, "calls", "", "JSON file with the mix of tool calls, a list of {name, arguments, weight}; a mix of registry tools by default")
+	flag.Var(headerFlags(cfg.Headers), "header", "header sent with every request, e.g. 'Authorization: Bearer <token>', can be repeated")
+	flag.IntVar(&cfg.PID, "pid", 0, "process ID of the server to sample its memory, Linux only")
+	flag.DurationVar(&cfg.SampleInterval, "sample-interval", time.Second, "interval of the memory samples")
+	flag.Int64Var(&cfg.Seed, "seed", time.Now().UnixNano(), "seed of the sequence of tool calls")
+	flag.BoolVar(&jsonOutput, "json", false, "print the report as JSON")
+	flag.Parse()
+
+	if callsFile != "" {
+		data, err := os.ReadFile(callsFile)
+		if err != nil {
+			fatal(err)
+		}
+		if err := json.Unmarshal(data, &cfg.Calls); err != nil {
+			fatal(fmt.Errorf("reading the tool calls of %s: %w", callsFile, err))
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := loadtest.Run(ctx, cfg)
+	if err != nil {
+		fatal(err)
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	report.Write(os.Stdout)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "loadtest:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/mcpserver"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// preflightChecks checks the proxy and the CA bundles of the outbound calls, and the HCP Terraform or TFE
+// token when the server is configured with one. Tokens sent per session in HTTP headers cannot be checked.
+func preflightChecks(ctx context.Context, logger *log.Logger) []mcpserver.Check {
+	checks := []mcpserver.Check{
+		proxyCheck("registry proxy", client.RegistryAddress()),
+		caBundleCheck(),
+	}
+
+	token := os.Getenv(client.TerraformToken)
+	address := strings.TrimSuffix(utils.GetEnv(client.TerraformAddress, client.DefaultTerraformAddress), "/")
+	if token == "" && os.Getenv(client.TerraformAddress) == "" {
+		return append(checks, mcpserver.Check{Name: "tfe token", Status: mcpserver.CheckOK, Detail: fmt.Sprintf("not configured, %s is expected in the headers of HTTP sessions", client.TerraformToken)})
+	}
+	checks = append(checks, proxyCheck("tfe proxy", address))
+	if token == "" {
+		return append(checks, mcpserver.Check{Name: "tfe token", Status: mcpserver.CheckWarn, Detail: fmt.Sprintf("%s is set without %s, the TFE tools need a token in the headers of HTTP sessions", client.TerraformAddress, client.TerraformToken)})
+	}
+	return append(checks, tfeTokenCheck(ctx, address, token, logger))
+}
+
+func proxyCheck(name string, target string) mcpserver.Check {
+	if _, err := url.ParseRequestURI(target); err != nil {
+		return mcpserver.Check{Name: name, Status: mcpserver.CheckFail, Detail: fmt.Sprintf("invalid address %q: %v", target, err)}
+	}
+	proxyURL, err := client.OutboundProxyFor(target)
+	switch {
+	case err != nil:
+		return mcpserver.Check{Name: name, Status: mcpserver.CheckWarn, Detail: fmt.Sprintf("%v, the proxy environment variables are used instead", err)}
+	case proxyURL == nil:
+		return mcpserver.Check{Name: name, Status: mcpserver.CheckOK, Detail: fmt.Sprintf("%s is reached directly", target)}
+	}
+	return mcpserver.Check{Name: name, Status: mcpserver.CheckOK, Detail: fmt.Sprintf("%s is reached through %s", target, proxyURL.Redacted())}
+}
+
+// caBundleCheck loads the CA bundles and the client certificate of the outbound calls
+func caBundleCheck() mcpserver.Check {
+	if _, err := (client.TfeTLSOptions{CACertFile: strings.TrimSpace(os.Getenv(client.CACertFile))}).TLSConfig(); err != nil {
+		return mcpserver.Check{Name: "certificates", Status: mcpserver.CheckFail, Detail: fmt.Sprintf("%s: %v", client.CACertFile, err)}
+	}
+	if _, err := client.LoadTfeTLSOptionsFromEnv(false).TLSConfig(); err != nil {
+		return mcpserver.Check{Name: "certificates", Status: mcpserver.CheckFail, Detail: err.Error()}
+	}
+	if os.Getenv(client.CACertFile) == "" && os.Getenv(client.TerraformCACertFile) == "" {
+		return mcpserver.Check{Name: "certificates", Status: mcpserver.CheckOK, Detail: "system certificates"}
+	}
+	return mcpserver.Check{Name: "certificates", Status: mcpserver.CheckOK, Detail: "CA bundles loaded"}
+}
+
+func tfeTokenCheck(ctx context.Context, address string, token string, logger *log.Logger) mcpserver.Check {
+	skipVerify, _ := strconv.ParseBool(os.Getenv(client.TerraformSkipTLSVerify))
+	user, err := client.VerifyTfeToken(ctx, address, skipVerify, token, logger)
+	if err != nil {
+		return mcpserver.Check{Name: "tfe token", Status: mcpserver.CheckFail, Detail: fmt.Sprintf("%s: %v", address, err)}
+	}
+	detail := fmt.Sprintf("authenticated to %s as %s", address, user.Username)
+	if skipVerify {
+		return mcpserver.Check{Name: "tfe token", Status: mcpserver.CheckWarn, Detail: detail + fmt.Sprintf(", with %s so its certificate is not verified", client.TerraformSkipTLSVerify)}
+	}
+	return mcpserver.Check{Name: "tfe token", Status: mcpserver.CheckOK, Detail: detail}
+}
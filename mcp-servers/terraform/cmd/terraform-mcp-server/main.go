@@ -6,168 +6,72 @@ package main
 import (
 	"context"
 	"fmt"
-	stdlog "log"
 	"os"
-	"os/signal"
+	"strconv"
 	"strings"
-	"syscall"
+	// The image is built from scratch without zoneinfo, the time zones of the guardrail windows and of
+	// MCP_REGISTRY_CACHE_REFRESH_HOURS are embedded in the binary
+	_ "time/tzdata"
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/mcpserver"
+	"github.com/hashicorp/terraform-mcp-server/pkg/resources"
+	"github.com/hashicorp/terraform-mcp-server/pkg/tools"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
 	"github.com/hashicorp/terraform-mcp-server/version"
 
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
-	"github.com/spf13/cobra"
 )
 
-func runHTTPServer(logger *log.Logger, host string, port string, endpointPath string) error {
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
-
-	hcServer := NewServer(version.Version, logger)
-	registerToolsAndResources(hcServer, logger)
-
-	return streamableHTTPServerInit(ctx, hcServer, logger, host, port, endpointPath)
-}
-
-func runStdioServer(logger *log.Logger) error {
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
-
-	hcServer := NewServer(version.Version, logger)
-	registerToolsAndResources(hcServer, logger)
-
-	return serverInit(ctx, hcServer, logger)
+var serverConfig = mcpserver.Config{
+	Name:                "terraform-mcp-server",
+	Title:               "Terraform MCP Server",
+	Description:         `A Terraform MCP server that handles various tools and resources.`,
+	Version:             version.Version,
+	BuildInfo:           fmt.Sprintf("Version: %s\nCommit: %s\nBuild Date: %s", version.GetHumanVersion(), version.GitCommit, version.BuildDate),
+	Register:            registerToolsAndResources,
+	ContextMiddleware:   client.TerraformContextMiddleware,
+	ToolMiddleware:      tools.TFEToolMiddleware,
+	OnRegisterSession:   client.NewSessionHandler,
+	OnUnregisterSession: client.EndSessionHandler,
+	ReadinessProbes:     readinessProbes,
+	SelfTest:            client.RegistrySelfTest,
+	Preflight:           preflightChecks,
+	Capabilities:        tools.ServerCapabilities,
 }
 
-func NewServer(version string, logger *log.Logger, opts ...server.ServerOption) *server.MCPServer {
-	// Create rate limiting middleware with environment-based configuration
-	rateLimitConfig := client.LoadRateLimitConfigFromEnv()
-	rateLimitMiddleware := client.NewRateLimitMiddleware(rateLimitConfig, logger)
-
-	// Add default options
-	defaultOpts := []server.ServerOption{
-		server.WithToolCapabilities(true),
-		server.WithResourceCapabilities(true, true),
-		server.WithToolHandlerMiddleware(rateLimitMiddleware.Middleware()),
+// readinessProbes checks the registry or its configured mirror, and HCP Terraform or TFE when the server is configured
+// with one. Addresses and tokens sent per session in HTTP headers cannot be probed ahead of time. The probes go through
+// the proxy and use the certificates of the registry and TFE clients, so they fail when the tools cannot reach them.
+func readinessProbes(logger *log.Logger) []mcpserver.Probe {
+	probes := []mcpserver.Probe{
+		mcpserver.HTTPProbe("registry", client.RegistryAddress()+"/.well-known/terraform.json", client.NewRegistryHTTPClient(logger)),
 	}
-	opts = append(defaultOpts, opts...)
-
-	// Create hooks for session management
-	hooks := &server.Hooks{}
-	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
-		client.NewSessionHandler(ctx, session, logger)
-	})
-	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
-		client.EndSessionHandler(ctx, session, logger)
-	})
-
-	// Add hooks to options
-	opts = append(opts, server.WithHooks(hooks))
-
-	// Create a new MCP server
-	s := server.NewMCPServer(
-		"terraform-mcp-server",
-		version,
-		opts...,
-	)
-	return s
-}
-
-// runDefaultCommand handles the default behavior when no subcommand is provided
-func runDefaultCommand(cmd *cobra.Command, _ []string) {
-	// Default to stdio mode when no subcommand is provided
-	logFile, err := cmd.PersistentFlags().GetString("log-file")
-	if err != nil {
-		stdlog.Fatal("Failed to get log file:", err)
-	}
-	logger, err := initLogger(logFile)
-	if err != nil {
-		stdlog.Fatal("Failed to initialize logger:", err)
-	}
-
-	if err := runStdioServer(logger); err != nil {
-		stdlog.Fatal("failed to run stdio server:", err)
-	}
-}
-
-func main() {
-	// Check environment variables first - they override command line args
-	if shouldUseStreamableHTTPMode() {
-		port := getHTTPPort()
-		host := getHTTPHost()
-		endpointPath := getEndpointPath(nil)
-
-		logFile, _ := rootCmd.PersistentFlags().GetString("log-file")
-		logger, err := initLogger(logFile)
+	if os.Getenv(client.TerraformToken) != "" || os.Getenv(client.TerraformAddress) != "" {
+		address := strings.TrimSuffix(utils.GetEnv(client.TerraformAddress, client.DefaultTerraformAddress), "/")
+		skipVerify, _ := strconv.ParseBool(os.Getenv(client.TerraformSkipTLSVerify))
+		httpClient, err := client.NewTfeHTTPClient(skipVerify, logger)
 		if err != nil {
-			stdlog.Fatal("Failed to initialize logger:", err)
+			// The TFE client cannot be created either, the server is not ready
+			probes = append(probes, mcpserver.Probe{
+				Name:  "tfe",
+				Check: func(context.Context) error { return fmt.Errorf("configuring TLS for the TFE client: %w", err) },
+			})
+			return probes
 		}
-
-		if err := runHTTPServer(logger, host, port, endpointPath); err != nil {
-			stdlog.Fatal("failed to run StreamableHTTP server:", err)
-		}
-		return
-	}
-
-	// Fall back to normal CLI behavior
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-}
-
-// shouldUseStreamableHTTPMode checks if environment variables indicate HTTP mode
-func shouldUseStreamableHTTPMode() bool {
-	transportMode := os.Getenv("TRANSPORT_MODE")
-	return transportMode == "http" || transportMode == "streamable-http" ||
-		os.Getenv("TRANSPORT_PORT") != "" ||
-		os.Getenv("TRANSPORT_HOST") != "" ||
-		os.Getenv("MCP_ENDPOINT") != ""
-}
-
-// shouldUseStatelessMode returns true if the MCP_SESSION_MODE environment variable is set to "stateless"
-func shouldUseStatelessMode() bool {
-	mode := strings.ToLower(os.Getenv("MCP_SESSION_MODE"))
-
-	// Explicitly check for "stateless" value
-	if mode == "stateless" {
-		return true
-	}
-
-	// All other values (including empty string, "stateful", or any other value) default to stateful mode
-	return false
-}
-
-// getHTTPPort returns the port from environment variables or default
-func getHTTPPort() string {
-	if port := os.Getenv("TRANSPORT_PORT"); port != "" {
-		return port
+		probes = append(probes, mcpserver.HTTPProbe("tfe", address+"/api/v2/ping", httpClient))
 	}
-	return "8080"
+	return probes
 }
 
-// getHTTPHost returns the host from environment variables or default
-func getHTTPHost() string {
-	if host := os.Getenv("TRANSPORT_HOST"); host != "" {
-		return host
-	}
-	return "127.0.0.1"
+// registerToolsAndResources registers tools and resources with the MCP server
+func registerToolsAndResources(hcServer *server.MCPServer, logger *log.Logger) {
+	tools.RegisterTools(hcServer, logger)
+	resources.RegisterResources(hcServer, logger)
+	resources.RegisterResourceTemplates(hcServer, logger)
 }
 
-// Add function to get endpoint path from environment or flag
-func getEndpointPath(cmd *cobra.Command) string {
-	// First check environment variable
-	if envPath := os.Getenv("MCP_ENDPOINT"); envPath != "" {
-		return envPath
-	}
-
-	// Fall back to command line flag
-	if cmd != nil {
-		if path, err := cmd.Flags().GetString("mcp-endpoint"); err == nil && path != "" {
-			return path
-		}
-	}
-
-	return "/mcp"
+func main() {
+	mcpserver.Execute(serverConfig)
 }
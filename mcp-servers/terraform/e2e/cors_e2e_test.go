@@ -47,7 +47,7 @@ type InitializeResponse struct {
 // TestCORSE2E tests CORS validation in the MCP server using direct HTTP requests
 func TestCORSE2E(t *testing.T) {
 	// Build the Docker image for our tests
-	buildDockerImage(t)
+	image := buildDockerImage(t)
 
 	// Ensure all test containers are cleaned up at the end
 	t.Cleanup(func() {
@@ -72,7 +72,7 @@ func TestCORSE2E(t *testing.T) {
 			baseURL := fmt.Sprintf("http://localhost:%s", config.port)
 			mcpURL := fmt.Sprintf("%s/mcp", baseURL)
 
-			containerID := startHTTPContainerWithCORS(t, config.port, config.mode, config.origins)
+			containerID := startHTTPContainerWithCORS(t, image, config.port, config.mode, config.origins)
 			defer func() {
 				stopCmd := exec.Command("docker", "stop", containerID)
 				stopCmd.Run()
@@ -87,10 +87,10 @@ func TestCORSE2E(t *testing.T) {
 }
 
 // startHTTPContainerWithCORS starts a Docker container with specific CORS settings
-func startHTTPContainerWithCORS(t *testing.T, port, mode, origins string) string {
+func startHTTPContainerWithCORS(t *testing.T, image e2eImage, port, mode, origins string) string {
 	portMapping := fmt.Sprintf("%s:8080", port)
-	cmd := exec.Command(
-		"docker", "run", "-d", "--rm",
+	args := append([]string{"run", "-d", "--rm"}, image.RunArgs()...)
+	args = append(args,
 		"-e", "TRANSPORT_MODE=streamable-http",
 		"-e", "TRANSPORT_HOST=0.0.0.0",
 		"-e", "MCP_SESSION_MODE=stateful",
@@ -98,9 +98,9 @@ func startHTTPContainerWithCORS(t *testing.T, port, mode, origins string) string
 		"-e", fmt.Sprintf("MCP_CORS_MODE=%s", mode),
 		"-e", fmt.Sprintf("MCP_ALLOWED_ORIGINS=%s", origins),
 		"-p", portMapping,
-		"terraform-mcp-server:test-e2e",
+		image.Tag,
 	)
-	output, err := cmd.CombinedOutput()
+	output, err := exec.Command("docker", args...).CombinedOutput()
 	if err != nil {
 		t.Logf("Docker command failed: %s", string(output))
 		require.NoError(t, err, "expected to start HTTP container successfully")
@@ -20,7 +20,16 @@ import (
 )
 
 func TestE2E(t *testing.T) {
-	buildDockerImage(t)
+	images := buildDockerImages(t)
+
+	// The server calls the registry through the recorder, which replays the checked-in fixtures
+	// unless the tests run with -live
+	registry := startRegistryRecorder(t, publicRegistryURL, registryFixturesDir, *liveRegistry)
+	t.Cleanup(func() {
+		if missing := registry.Missing(); len(missing) > 0 {
+			t.Errorf("%d registry requests have no recorded fixture, run the e2e tests with -live to record them:\n%s", len(missing), strings.Join(missing, "\n"))
+		}
+	})
 
 	// Ensure all test containers are cleaned up at the end
 	t.Cleanup(func() {
@@ -29,17 +38,21 @@ func TestE2E(t *testing.T) {
 
 	testCases := []struct {
 		name          string
-		clientFactory func(t *testing.T) (mcpClient.MCPClient, func())
+		clientFactory func(t *testing.T, image e2eImage, registryAddress string) (mcpClient.MCPClient, func())
 	}{
 		{"Stdio", createStdioClient},
 		{"HTTP", createHTTPClient},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			client, cleanup := tc.clientFactory(t)
-			defer cleanup()
-			runTestSuite(t, client, tc.name)
+	for _, image := range images {
+		t.Run(image.Name(), func(t *testing.T) {
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					client, cleanup := tc.clientFactory(t, image, registry.ContainerAddress())
+					defer cleanup()
+					runTestSuite(t, client, tc.name)
+				})
+			}
 		})
 	}
 }
@@ -368,16 +381,15 @@ func runTestSuite(t *testing.T, client mcpClient.MCPClient, transportName string
 }
 
 // createStdioClient creates a stdio-based MCP client
-func createStdioClient(t *testing.T) (mcpClient.MCPClient, func()) {
-	args := []string{
-		"docker",
-		"run",
-		"-i",
-		"--rm",
+func createStdioClient(t *testing.T, image e2eImage, registryAddress string) (mcpClient.MCPClient, func()) {
+	args := append([]string{"docker", "run", "-i", "--rm"}, image.RunArgs()...)
+	args = append(args,
+		"--add-host=host.docker.internal:host-gateway",
+		"-e", "TERRAFORM_REGISTRY_ADDRESS="+registryAddress,
 		"-e", "MCP_RATE_LIMIT_GLOBAL=50:100",
 		"-e", "MCP_RATE_LIMIT_SESSION=50:100",
-		"terraform-mcp-server:test-e2e",
-	}
+		image.Tag,
+	)
 	t.Log("Starting Stdio MCP client...")
 	client, err := mcpClient.NewStdioMCPClient(args[0], []string{}, args[1:]...)
 	require.NoError(t, err, "expected to create stdio client successfully")
@@ -390,7 +402,7 @@ func createStdioClient(t *testing.T) (mcpClient.MCPClient, func()) {
 }
 
 // createHTTPClient creates an HTTP-based MCP client
-func createHTTPClient(t *testing.T) (mcpClient.MCPClient, func()) {
+func createHTTPClient(t *testing.T, image e2eImage, registryAddress string) (mcpClient.MCPClient, func()) {
 	t.Log("Starting HTTP MCP server...")
 
 	port := getTestPort()
@@ -398,15 +410,16 @@ func createHTTPClient(t *testing.T) (mcpClient.MCPClient, func()) {
 	mcpURL := fmt.Sprintf("http://localhost:%s/mcp", port)
 
 	// Start container in HTTP mode
-	containerID := startHTTPContainer(t, port)
+	containerID := startHTTPContainer(t, image, port, registryAddress)
 
 	// Ensure container cleanup even if test fails
 	t.Cleanup(func() {
 		stopContainer(t, containerID)
 	})
 
-	// Wait for server to be ready
+	// Wait for server to be ready, and for the HEALTHCHECK of the image to report it
 	waitForServer(t, baseURL)
+	waitForContainerHealthy(t, containerID)
 
 	// Create client with MCP endpoint
 	client, err := mcpClient.NewStreamableHttpClient(mcpURL)
@@ -423,19 +436,23 @@ func createHTTPClient(t *testing.T) (mcpClient.MCPClient, func()) {
 }
 
 // startHTTPContainer starts a Docker container in HTTP mode and returns container ID
-func startHTTPContainer(t *testing.T, port string) string {
+func startHTTPContainer(t *testing.T, image e2eImage, port string, registryAddress string) string {
 	portMapping := fmt.Sprintf("%s:8080", port)
-	cmd := exec.Command(
-		"docker", "run", "-d", "--rm",
+	args := append([]string{"run", "-d", "--rm"}, image.RunArgs()...)
+	args = append(args,
+		// Run the HEALTHCHECK of the image often enough for the test to wait for it
+		"--health-interval=1s",
+		"--add-host=host.docker.internal:host-gateway",
+		"-e", "TERRAFORM_REGISTRY_ADDRESS="+registryAddress,
 		"-e", "TRANSPORT_MODE=streamable-http",
 		"-e", "TRANSPORT_HOST=0.0.0.0",
 		"-e", "MCP_SESSION_MODE=stateful",
 		"-e", "MCP_RATE_LIMIT_GLOBAL=50:100",
 		"-e", "MCP_RATE_LIMIT_SESSION=50:100",
 		"-p", portMapping,
-		"terraform-mcp-server:test-e2e",
+		image.Tag,
 	)
-	output, err := cmd.Output()
+	output, err := exec.Command("docker", args...).Output()
 	require.NoError(t, err, "expected to start HTTP container successfully")
 
 	containerID := string(output)[:12] // First 12 chars of container ID
@@ -461,6 +478,22 @@ func waitForServer(t *testing.T, baseURL string) {
 	t.Fatal("HTTP server failed to start within 30 seconds")
 }
 
+// waitForContainerHealthy waits for the HEALTHCHECK of the image to report the container healthy
+func waitForContainerHealthy(t *testing.T, containerID string) {
+	status := ""
+	for i := 0; i < 30; i++ {
+		output, err := exec.Command("docker", "inspect", "--format", "{{.State.Health.Status}}", containerID).Output()
+		require.NoError(t, err, "expected to inspect container %s", containerID)
+		status = strings.TrimSpace(string(output))
+		if status == "healthy" {
+			t.Log("Container is healthy")
+			return
+		}
+		time.Sleep(1 * time.Second)
+	}
+	t.Fatalf("Container %s is not healthy within 30 seconds, its health status is %q", containerID, status)
+}
+
 // stopContainer stops the Docker container
 func stopContainer(t *testing.T, containerID string) {
 	if containerID == "" {
@@ -485,8 +518,8 @@ func stopContainer(t *testing.T, containerID string) {
 func cleanupAllTestContainers(t *testing.T) {
 	t.Log("Cleaning up all test containers...")
 
-	// Find all containers with our test image
-	cmd := exec.Command("docker", "ps", "-q", "--filter", "ancestor=terraform-mcp-server:test-e2e")
+	// Find all containers started by the tests
+	cmd := exec.Command("docker", "ps", "-q", "--filter", "label="+e2eContainerLabel)
 	output, err := cmd.Output()
 	if err != nil {
 		t.Logf("Warning: failed to list test containers: %v", err)
@@ -517,11 +550,96 @@ func getTestPort() string {
 	return "8080"
 }
 
-func buildDockerImage(t *testing.T) {
+// e2eContainerLabel labels the containers started by the tests, so that they are cleaned up whatever their image
+const e2eContainerLabel = "terraform-mcp-server-e2e"
+
+// e2ePlatforms are the platforms the tests run the image on, when the Docker builder supports them
+var e2ePlatforms = []string{"linux/amd64", "linux/arm64"}
+
+// e2eImage is the image under test for one platform
+type e2eImage struct {
+	// Platform is empty for the image built for the platform of the Docker daemon
+	Platform string
+	Tag      string
+}
+
+// Name names the subtests of the image
+func (i e2eImage) Name() string {
+	if i.Platform == "" {
+		return "native"
+	}
+	return strings.TrimPrefix(i.Platform, "linux/")
+}
+
+// RunArgs are the docker run arguments of the containers of the image
+func (i e2eImage) RunArgs() []string {
+	args := []string{"--label", e2eContainerLabel}
+	if i.Platform != "" {
+		args = append(args, "--platform", i.Platform)
+	}
+	return args
+}
+
+// buildDockerImage builds the image for the platform of the Docker daemon
+func buildDockerImage(t *testing.T) e2eImage {
 	t.Log("Building Docker image for e2e tests...")
 
 	cmd := exec.Command("make", "VERSION=test-e2e", "docker-build")
 	cmd.Dir = ".." // Run this in the context of the root, where the Makefile is located.
 	output, err := cmd.CombinedOutput()
 	require.NoError(t, err, "expected to build Docker image successfully, output: %s", string(output))
+	return e2eImage{Tag: "terraform-mcp-server:test-e2e"}
+}
+
+// buildDockerImages builds the image for each of e2ePlatforms the Docker builder supports, e.g. arm64 through
+// QEMU on an amd64 host, or for the platform of the Docker daemon without buildx. E2E_PLATFORMS overrides the
+// platforms, e.g. E2E_PLATFORMS=linux/arm64.
+func buildDockerImages(t *testing.T) []e2eImage {
+	platforms := builderPlatforms(t)
+	if value := os.Getenv("E2E_PLATFORMS"); value != "" {
+		platforms = strings.Split(value, ",")
+	}
+	if len(platforms) == 0 {
+		return []e2eImage{buildDockerImage(t)}
+	}
+
+	images := []e2eImage{}
+	for _, platform := range platforms {
+		platform = strings.TrimSpace(platform)
+		version := "test-e2e-" + strings.ReplaceAll(strings.TrimPrefix(platform, "linux/"), "/", "-")
+		t.Logf("Building Docker image for e2e tests on %s...", platform)
+
+		cmd := exec.Command("make", "VERSION="+version, "DOCKER_PLATFORM="+platform, "docker-build")
+		cmd.Dir = ".."
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "expected to build Docker image for %s successfully, output: %s", platform, string(output))
+		images = append(images, e2eImage{Platform: platform, Tag: "terraform-mcp-server:" + version})
+	}
+	return images
+}
+
+// builderPlatforms returns the platforms of e2ePlatforms the buildx builder supports, none without buildx
+func builderPlatforms(t *testing.T) []string {
+	output, err := exec.Command("docker", "buildx", "inspect", "--bootstrap").Output()
+	if err != nil {
+		t.Logf("docker buildx is not available, testing the image of the Docker daemon platform only: %v", err)
+		return nil
+	}
+	supported := map[string]bool{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "Platforms:"); ok {
+			for _, platform := range strings.Split(value, ",") {
+				supported[strings.TrimSuffix(strings.TrimSpace(platform), "*")] = true
+			}
+		}
+	}
+	platforms := []string{}
+	for _, platform := range e2ePlatforms {
+		if supported[platform] {
+			platforms = append(platforms, platform)
+		} else {
+			t.Logf("The Docker builder does not support %s, skipping its image", platform)
+		}
+	}
+	return platforms
 }
@@ -234,30 +234,30 @@ var searchModulesTestCases = []RegistryTestCase{
 		},
 	},
 	{
-		TestName:        "empty_query_with_offset",
+		TestName:        "empty_query_with_page",
 		TestShouldFail:  false,
-		TestDescription: "Testing search_modules with module_query '' and current_offset 10",
+		TestDescription: "Testing search_modules with module_query '' and page 2",
 		TestPayload: map[string]interface{}{
-			"module_query":   "",
-			"current_offset": 10,
+			"module_query": "",
+			"page":         2,
 		},
 	},
 	{
-		TestName:        "offset_only",
+		TestName:        "page_size_only",
 		TestShouldFail:  false,
-		TestDescription: "Testing search_modules with current_offset 5 only - all modules",
+		TestDescription: "Testing search_modules with pageSize 5 only - all modules",
 		TestPayload: map[string]interface{}{
-			"module_query":   "",
-			"current_offset": 5,
+			"module_query": "",
+			"pageSize":     5,
 		},
 	},
 	{
-		TestName:        "negative_offset",
-		TestShouldFail:  false,
-		TestDescription: "Testing search_modules with invalid current_offset (negative)",
+		TestName:        "negative_page",
+		TestShouldFail:  true,
+		TestDescription: "Testing search_modules with invalid page (negative)",
 		TestPayload: map[string]interface{}{
-			"module_query":   "",
-			"current_offset": -1,
+			"module_query": "",
+			"page":         -1,
 		},
 	},
 	{
@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package e2e
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// liveRegistry makes the e2e tests call the live registry and refresh the recorded fixtures,
+// e.g. go test ./e2e -live
+var liveRegistry = flag.Bool("live", false, "call the live registry and refresh the recorded registry fixtures")
+
+const (
+	// publicRegistryURL is the registry the fixtures are recorded from
+	publicRegistryURL = "https://registry.terraform.io"
+	// registryFixturesDir holds one fixture per registry request of the e2e tests
+	registryFixturesDir = "testdata/registry"
+)
+
+// registryFixture is a recorded registry response
+type registryFixture struct {
+	Method      string `json:"method"`
+	URI         string `json:"uri"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// registryRecorder is the registry of the server under test. In replay mode, the default, it answers
+// every request with its recorded fixture, so that the tests are deterministic and run offline.
+// In live mode it forwards the requests to the upstream registry and records the responses.
+type registryRecorder struct {
+	upstream string
+	dir      string
+	live     bool
+	client   *http.Client
+	server   *httptest.Server
+
+	mu      sync.Mutex
+	missing []string
+}
+
+// startRegistryRecorder starts a recorder listening on every interface, so that the server under
+// test can reach it from a container
+func startRegistryRecorder(t testing.TB, upstream string, dir string, live bool) *registryRecorder {
+	t.Helper()
+	recorder := &registryRecorder{
+		upstream: strings.TrimRight(upstream, "/"),
+		dir:      dir,
+		live:     live,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+
+	listener, err := net.Listen("tcp", "0.0.0.0:0")
+	if err != nil {
+		t.Fatalf("listening for the registry recorder: %v", err)
+	}
+	recorder.server = httptest.NewUnstartedServer(recorder)
+	recorder.server.Listener.Close()
+	recorder.server.Listener = listener
+	recorder.server.Start()
+	t.Cleanup(recorder.server.Close)
+
+	mode := "replaying fixtures of " + dir
+	if live {
+		mode = "recording fixtures from " + recorder.upstream
+	}
+	t.Logf("Registry recorder listening on port %d, %s", recorder.port(), mode)
+	return recorder
+}
+
+func (r *registryRecorder) port() int {
+	return r.server.Listener.Addr().(*net.TCPAddr).Port
+}
+
+// ContainerAddress is the address of the recorder from a container started with
+// --add-host=host.docker.internal:host-gateway
+func (r *registryRecorder) ContainerAddress() string {
+	return fmt.Sprintf("http://host.docker.internal:%d", r.port())
+}
+
+// Missing lists the requests replayed without a fixture
+func (r *registryRecorder) Missing() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.missing...)
+}
+
+func (r *registryRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	uri := fixtureURI(req)
+	path := filepath.Join(r.dir, fixtureFileName(req.Method, uri))
+
+	var fixture registryFixture
+	var err error
+	if r.live {
+		fixture, err = r.record(req, uri, path)
+	} else {
+		fixture, err = loadFixture(path)
+		if errors.Is(err, os.ErrNotExist) {
+			r.mu.Lock()
+			r.missing = append(r.missing, req.Method+" "+uri)
+			r.mu.Unlock()
+			err = fmt.Errorf("no recorded fixture for %s %s, run the e2e tests with -live to record it", req.Method, uri)
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if fixture.ContentType != "" {
+		w.Header().Set("Content-Type", fixture.ContentType)
+	}
+	w.WriteHeader(fixture.Status)
+	_, _ = io.WriteString(w, fixture.Body)
+}
+
+// record forwards a request to the upstream registry and saves its response. Validators such as
+// ETag are not recorded, so that replayed responses are never revalidated.
+func (r *registryRecorder) record(req *http.Request, uri string, path string) (registryFixture, error) {
+	upstreamReq, err := http.NewRequestWithContext(req.Context(), req.Method, r.upstream+uri, nil)
+	if err != nil {
+		return registryFixture{}, err
+	}
+	upstreamReq.Header.Set("Accept", req.Header.Get("Accept"))
+	resp, err := r.client.Do(upstreamReq)
+	if err != nil {
+		return registryFixture{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return registryFixture{}, err
+	}
+
+	fixture := registryFixture{
+		Method:      req.Method,
+		URI:         uri,
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(body),
+	}
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return registryFixture{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return registryFixture{}, err
+	}
+	return fixture, os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func loadFixture(path string) (registryFixture, error) {
+	var fixture registryFixture
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fixture, err
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fixture, fmt.Errorf("reading fixture %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// fixtureURI is the path and the query of a request, with the query parameters sorted so that a
+// request matches its fixture whatever the order of its parameters
+func fixtureURI(req *http.Request) string {
+	uri := req.URL.EscapedPath()
+	if query := req.URL.Query(); len(query) > 0 {
+		uri += "?" + query.Encode()
+	}
+	return uri
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// fixtureFileName names a fixture after its request: a readable prefix and a hash of the whole
+// request, e.g. get_v1_providers_hashicorp_aws_3f2a9c1b7d4e5f60.json
+func fixtureFileName(method string, uri string) string {
+	sum := sha256.Sum256([]byte(method + " " + uri))
+	path, _, _ := strings.Cut(uri, "?")
+	prefix := strings.Trim(unsafeFileNameChars.ReplaceAllString(strings.ToLower(method+"_"+path), "_"), "_")
+	if len(prefix) > 80 {
+		prefix = prefix[:80]
+	}
+	return fmt.Sprintf("%s_%s.json", prefix, hex.EncodeToString(sum[:8]))
+}
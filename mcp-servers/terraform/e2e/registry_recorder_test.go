@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryRecorder(t *testing.T) {
+	upstreamCalls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+		if r.URL.Path == "/v1/providers/hashicorp/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintf(w, `{"path": %q}`, r.URL.RequestURI())
+	}))
+	defer upstream.Close()
+	dir := t.TempDir()
+
+	get := func(recorder *registryRecorder, uri string) (int, string) {
+		resp, err := http.Get(recorder.server.URL + uri)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	// Live mode records the responses, including errors
+	live := startRegistryRecorder(t, upstream.URL, dir, true)
+	status, body := get(live, "/v2/provider-docs?filter[slug]=index&filter[category]=overview")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "provider-docs")
+	status, _ = get(live, "/v1/providers/hashicorp/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, 2, upstreamCalls)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	// Replay mode answers from the fixtures, whatever the order of the query parameters
+	upstream.Close()
+	replay := startRegistryRecorder(t, upstream.URL, dir, false)
+	replayedStatus, replayedBody := get(replay, "/v2/provider-docs?filter[category]=overview&filter[slug]=index")
+	assert.Equal(t, http.StatusOK, replayedStatus)
+	assert.Equal(t, body, replayedBody)
+	status, _ = get(replay, "/v1/providers/hashicorp/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Empty(t, replay.Missing())
+
+	// Requests without a fixture fail and are reported
+	status, body = get(replay, "/v1/modules/search?q=vpc")
+	assert.Equal(t, http.StatusBadGateway, status)
+	assert.Contains(t, body, "-live")
+	assert.Equal(t, []string{"GET /v1/modules/search?q=vpc"}, replay.Missing())
+}
+
+func TestFixtureFileName(t *testing.T) {
+	name := fixtureFileName("GET", "/v1/providers/hashicorp/aws?include=provider-versions")
+	assert.Regexp(t, `^get_v1_providers_hashicorp_aws_[0-9a-f]{16}\.json$`, name)
+	assert.NotEqual(t, name, fixtureFileName("GET", "/v1/providers/hashicorp/aws"))
+}
@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package loadtest drives concurrent MCP sessions against a running StreamableHTTP server and
+// reports the latency, the rate limit rejections and the memory growth of the server
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	mcpClient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolCall is a tool call of the mix, picked in proportion to its weight
+type ToolCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+	Weight    int            `json:"weight"`
+}
+
+// DefaultCalls is a mix of registry tool calls, weighted towards the cheap lookups clients make most
+var DefaultCalls = []ToolCall{
+	{Name: "search_providers", Weight: 3, Arguments: map[string]any{
+		"provider_name": "aws", "provider_namespace": "hashicorp", "service_slug": "s3_bucket", "provider_data_type": "resources",
+	}},
+	{Name: "get_latest_provider_version", Weight: 3, Arguments: map[string]any{"namespace": "hashicorp", "name": "aws"}},
+	{Name: "search_modules", Weight: 2, Arguments: map[string]any{"module_query": "vpc"}},
+	{Name: "get_latest_module_version", Weight: 1, Arguments: map[string]any{
+		"module_publisher": "terraform-aws-modules", "module_name": "vpc", "module_provider": "aws",
+	}},
+	{Name: "search_policies", Weight: 1, Arguments: map[string]any{"policy_query": "cis"}},
+}
+
+// Config is the load to drive
+type Config struct {
+	// URL is the MCP endpoint of the server, e.g. http://localhost:8080/mcp
+	URL string
+	// Headers are sent with every request, e.g. a TFE token for the TFE tools
+	Headers map[string]string
+	// Sessions is the number of concurrent sessions, each calling tools one after the other
+	Sessions int
+	// Duration is how long the sessions call tools
+	Duration time.Duration
+	// Calls is the mix of tool calls, DefaultCalls when empty
+	Calls []ToolCall
+	// PID is the process of the server whose memory is sampled, 0 to skip memory sampling
+	PID int
+	// SampleInterval is the interval of the memory samples
+	SampleInterval time.Duration
+	// Seed makes the sequence of tool calls reproducible
+	Seed int64
+}
+
+// Outcome classifies a tool call
+type Outcome string
+
+const (
+	OutcomeOK          Outcome = "ok"
+	OutcomeToolError   Outcome = "tool_error"
+	OutcomeRateLimited Outcome = "rate_limited"
+	OutcomeFailed      Outcome = "failed"
+)
+
+type sample struct {
+	tool    string
+	latency time.Duration
+	outcome Outcome
+}
+
+// Run opens the sessions, calls tools until the duration elapses and returns the report.
+// It fails when no session could be initialized.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.Sessions < 1 {
+		return nil, fmt.Errorf("at least one session is required")
+	}
+	calls := cfg.Calls
+	if len(calls) == 0 {
+		calls = DefaultCalls
+	}
+	picker, err := newCallPicker(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	var memory *memorySampler
+	if cfg.PID > 0 {
+		memory = startMemorySampler(cfg.PID, cfg.SampleInterval)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+	start := time.Now()
+
+	var (
+		mu            sync.Mutex
+		samples       []sample
+		sessionErrors []error
+		wg            sync.WaitGroup
+	)
+	for i := 0; i < cfg.Sessions; i++ {
+		wg.Add(1)
+		go func(session int) {
+			defer wg.Done()
+			sessionSamples, err := runSession(ctx, cfg, picker, rand.New(rand.NewSource(cfg.Seed+int64(session))))
+			mu.Lock()
+			defer mu.Unlock()
+			samples = append(samples, sessionSamples...)
+			if err != nil {
+				sessionErrors = append(sessionErrors, fmt.Errorf("session %d: %w", session, err))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(sessionErrors) == cfg.Sessions {
+		return nil, errors.Join(sessionErrors...)
+	}
+	report := newReport(samples, cfg.Sessions, time.Since(start))
+	report.SessionErrors = len(sessionErrors)
+	if memory != nil {
+		report.Memory = memory.stop()
+	}
+	return report, nil
+}
+
+// runSession initializes a session and calls tools until ctx is done
+func runSession(ctx context.Context, cfg Config, picker *callPicker, random *rand.Rand) ([]sample, error) {
+	var options []transport.StreamableHTTPCOption
+	if len(cfg.Headers) > 0 {
+		options = append(options, transport.WithHTTPHeaders(cfg.Headers))
+	}
+	client, err := mcpClient.NewStreamableHttpClient(cfg.URL, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	if err := client.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	initialize := mcp.InitializeRequest{}
+	initialize.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initialize.Params.ClientInfo = mcp.Implementation{Name: "terraform-mcp-loadtest", Version: "0.0.1"}
+	if _, err := client.Initialize(ctx, initialize); err != nil {
+		return nil, fmt.Errorf("initializing: %w", err)
+	}
+
+	var samples []sample
+	for ctx.Err() == nil {
+		call := picker.pick(random)
+		request := mcp.CallToolRequest{}
+		request.Params.Name = call.Name
+		request.Params.Arguments = call.Arguments
+
+		callStart := time.Now()
+		result, err := client.CallTool(ctx, request)
+		latency := time.Since(callStart)
+		// The call cut short by the end of the run is not counted
+		if ctx.Err() != nil {
+			break
+		}
+		samples = append(samples, sample{tool: call.Name, latency: latency, outcome: classify(result, err)})
+	}
+	return samples, nil
+}
+
+// classify tells rate limit rejections, returned as RATE_LIMITED tool errors or HTTP 429, from
+// other tool errors and from transport failures
+func classify(result *mcp.CallToolResult, err error) Outcome {
+	if err != nil {
+		if strings.Contains(err.Error(), "429") {
+			return OutcomeRateLimited
+		}
+		return OutcomeFailed
+	}
+	if !result.IsError {
+		return OutcomeOK
+	}
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok && strings.HasPrefix(text.Text, "["+string(utils.ErrorCodeRateLimited)+"]") {
+			return OutcomeRateLimited
+		}
+	}
+	return OutcomeToolError
+}
+
+// callPicker picks the tool calls of the mix in proportion to their weight
+type callPicker struct {
+	calls       []ToolCall
+	totalWeight int
+}
+
+func newCallPicker(calls []ToolCall) (*callPicker, error) {
+	picker := &callPicker{calls: calls}
+	for _, call := range calls {
+		if call.Name == "" {
+			return nil, fmt.Errorf("a tool call of the mix has no name")
+		}
+		if call.Weight < 0 {
+			return nil, fmt.Errorf("the weight of %s is negative", call.Name)
+		}
+		picker.totalWeight += call.Weight
+	}
+	if picker.totalWeight == 0 {
+		return nil, fmt.Errorf("the tool calls of the mix have no weight")
+	}
+	return picker, nil
+}
+
+func (p *callPicker) pick(random *rand.Rand) ToolCall {
+	n := random.Intn(p.totalWeight)
+	for _, call := range p.calls {
+		if n < call.Weight {
+			return call
+		}
+		n -= call.Weight
+	}
+	return p.calls[len(p.calls)-1]
+}
@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) string {
+	mcpServer := server.NewMCPServer("test-mcp-server", "0.0.1", server.WithToolCapabilities(true))
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("limited"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeRateLimited, "rate limit exceeded"), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("broken"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeNotFound, "no such module"), nil
+	})
+	httpServer := httptest.NewServer(server.NewStreamableHTTPServer(mcpServer))
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL + "/mcp"
+}
+
+func TestRun(t *testing.T) {
+	report, err := Run(context.Background(), Config{
+		URL:      newTestServer(t),
+		Sessions: 3,
+		Duration: 300 * time.Millisecond,
+		Calls: []ToolCall{
+			{Name: "echo", Weight: 2},
+			{Name: "limited", Weight: 1},
+			{Name: "broken", Weight: 1},
+		},
+		PID:            os.Getpid(),
+		SampleInterval: 50 * time.Millisecond,
+		Seed:           1,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Sessions)
+	assert.Zero(t, report.SessionErrors)
+	require.Len(t, report.Tools, 3)
+	assert.Equal(t, []string{"broken", "echo", "limited"}, []string{report.Tools[0].Tool, report.Tools[1].Tool, report.Tools[2].Tool})
+
+	broken, echo, limited := report.Tools[0], report.Tools[1], report.Tools[2]
+	assert.Positive(t, echo.Calls)
+	assert.Equal(t, echo.Calls, echo.OK)
+	assert.Equal(t, limited.Calls, limited.RateLimited)
+	assert.Equal(t, broken.Calls, broken.ToolErrors)
+	assert.Equal(t, echo.Calls+limited.Calls+broken.Calls, report.Total.Calls)
+	assert.Zero(t, report.Total.Failed)
+	assert.LessOrEqual(t, report.Total.Latency.P50, report.Total.Latency.P99)
+	assert.Positive(t, report.Throughput)
+
+	require.NotNil(t, report.Memory)
+	if _, err := os.Stat("/proc/self/status"); err == nil {
+		assert.Empty(t, report.Memory.Error)
+		assert.GreaterOrEqual(t, report.Memory.Samples, 2)
+		assert.GreaterOrEqual(t, report.Memory.Peak, report.Memory.Start)
+	}
+
+	var out strings.Builder
+	report.Write(&out)
+	assert.Contains(t, out.String(), "3 sessions")
+	assert.Contains(t, out.String(), "rate limited")
+	assert.Contains(t, out.String(), "memory")
+}
+
+func TestRunWithoutServer(t *testing.T) {
+	_, err := Run(context.Background(), Config{URL: "http://127.0.0.1:1/mcp", Sessions: 2, Duration: time.Second})
+	assert.ErrorContains(t, err, "initializing")
+
+	_, err = Run(context.Background(), Config{URL: "http://127.0.0.1:1/mcp", Duration: time.Second})
+	assert.EqualError(t, err, "at least one session is required")
+
+	_, err = Run(context.Background(), Config{URL: "http://127.0.0.1:1/mcp", Sessions: 1, Duration: time.Second, Calls: []ToolCall{{Name: "echo"}}})
+	assert.EqualError(t, err, "the tool calls of the mix have no weight")
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, OutcomeOK, classify(mcp.NewToolResultText("ok"), nil))
+	assert.Equal(t, OutcomeRateLimited, classify(utils.NewToolResultErrorWithCode(utils.ErrorCodeRateLimited, "slow down"), nil))
+	assert.Equal(t, OutcomeToolError, classify(utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, "bad"), nil))
+	assert.Equal(t, OutcomeRateLimited, classify(nil, errors.New("request failed with status 429: Too Many Requests")))
+	assert.Equal(t, OutcomeFailed, classify(nil, errors.New("connection refused")))
+}
+
+func TestCallPicker(t *testing.T) {
+	picker, err := newCallPicker([]ToolCall{{Name: "a", Weight: 3}, {Name: "b", Weight: 0}, {Name: "c", Weight: 1}})
+	require.NoError(t, err)
+
+	counts := map[string]int{}
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 4000; i++ {
+		counts[picker.pick(random).Name]++
+	}
+	assert.Zero(t, counts["b"])
+	assert.InDelta(t, 3000, counts["a"], 200)
+	assert.InDelta(t, 1000, counts["c"], 200)
+
+	_, err = newCallPicker([]ToolCall{{Weight: 1}})
+	assert.Error(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	assert.Equal(t, 7*time.Millisecond, percentile([]time.Duration{7 * time.Millisecond}, 99))
+	assert.Zero(t, percentile(nil, 50))
+}
+
+func TestParseRSS(t *testing.T) {
+	rss, err := parseRSS(strings.NewReader("Name:\tterraform-mcp\nVmPeak:\t  20000 kB\nVmRSS:\t   12345 kB\n"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(12345*1024), rss)
+
+	_, err = parseRSS(strings.NewReader("Name:\tterraform-mcp\n"))
+	assert.Error(t, err)
+}
+
+func TestMemorySampler(t *testing.T) {
+	values := []int64{100, 300, 200}
+	reads := 0
+	sampler := startSampler(func() (int64, error) {
+		if reads >= len(values) {
+			return 0, errors.New("process exited")
+		}
+		reads++
+		return values[reads-1], nil
+	}, time.Hour)
+	sampler.sample()
+	sampler.sample()
+
+	report := sampler.stop()
+	assert.Equal(t, MemoryReport{Start: 100, Peak: 300, End: 200, Samples: 3}, *report)
+	assert.Equal(t, int64(100), report.Growth())
+	assert.Equal(t, "-1.5 KiB", formatBytes(-1536))
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package loadtest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSampleInterval is the interval of the memory samples when none is configured
+const defaultSampleInterval = time.Second
+
+// MemoryReport is the resident memory of the server over the load test, in bytes
+type MemoryReport struct {
+	Start   int64 `json:"start_bytes"`
+	Peak    int64 `json:"peak_bytes"`
+	End     int64 `json:"end_bytes"`
+	Samples int   `json:"samples"`
+	// Error is why the memory could not be sampled, e.g. a process that is not on this host
+	Error string `json:"error,omitempty"`
+}
+
+// Growth is the memory the server kept at the end of the load test
+func (m *MemoryReport) Growth() int64 {
+	return m.End - m.Start
+}
+
+// Write prints the memory samples of the server
+func (m *MemoryReport) Write(out io.Writer) {
+	if m.Error != "" {
+		fmt.Fprintf(out, "memory: not sampled, %s\n", m.Error)
+		return
+	}
+	fmt.Fprintf(out, "memory (RSS, %d samples): start %s, peak %s, end %s, growth %s\n",
+		m.Samples, formatBytes(m.Start), formatBytes(m.Peak), formatBytes(m.End), formatBytes(m.Growth()))
+}
+
+// memorySampler samples the resident memory of a process of this host until it is stopped
+type memorySampler struct {
+	read func() (int64, error)
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	mu     sync.Mutex
+	report MemoryReport
+}
+
+func startMemorySampler(pid int, interval time.Duration) *memorySampler {
+	return startSampler(func() (int64, error) { return readRSS(pid) }, interval)
+}
+
+func startSampler(read func() (int64, error), interval time.Duration) *memorySampler {
+	if interval <= 0 {
+		interval = defaultSampleInterval
+	}
+	sampler := &memorySampler{read: read, done: make(chan struct{})}
+	sampler.sample()
+
+	sampler.wg.Add(1)
+	go func() {
+		defer sampler.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sampler.done:
+				return
+			case <-ticker.C:
+				sampler.sample()
+			}
+		}
+	}()
+	return sampler
+}
+
+func (s *memorySampler) sample() {
+	rss, err := s.read()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A process that exits during the test keeps the samples taken before
+	if err != nil {
+		if s.report.Samples == 0 {
+			s.report.Error = err.Error()
+		}
+		return
+	}
+	if s.report.Samples == 0 {
+		s.report.Start, s.report.Error = rss, ""
+	}
+	s.report.Samples++
+	s.report.End = rss
+	if rss > s.report.Peak {
+		s.report.Peak = rss
+	}
+}
+
+// stop takes a last sample and returns the report
+func (s *memorySampler) stop() *MemoryReport {
+	close(s.done)
+	s.wg.Wait()
+	s.sample()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report := s.report
+	return &report
+}
+
+// readRSS reads the resident memory of a process from /proc, which is only available on Linux
+func readRSS(pid int) (int64, error) {
+	file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, fmt.Errorf("reading the memory of process %d: %w", pid, err)
+	}
+	defer file.Close()
+	return parseRSS(file)
+}
+
+// parseRSS finds the VmRSS line of a /proc/<pid>/status file, e.g. "VmRSS:	   12345 kB"
+func parseRSS(status io.Reader) (int64, error) {
+	scanner := bufio.NewScanner(status)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "VmRSS:")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) != 2 || fields[1] != "kB" {
+			return 0, fmt.Errorf("unexpected VmRSS line %q", scanner.Text())
+		}
+		kilobytes, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected VmRSS line %q", scanner.Text())
+		}
+		return kilobytes * 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no VmRSS in the process status")
+}
+
+func formatBytes(bytes int64) string {
+	sign := ""
+	if bytes < 0 {
+		sign, bytes = "-", -bytes
+	}
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%s%.1f GiB", sign, float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%s%.1f MiB", sign, float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%s%.1f KiB", sign, float64(bytes)/(1<<10))
+	}
+	return fmt.Sprintf("%s%d B", sign, bytes)
+}
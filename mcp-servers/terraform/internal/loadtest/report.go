@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package loadtest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Latency holds the latency percentiles of a set of tool calls
+type Latency struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// ToolReport is the outcome of the calls of one tool, or of all tools
+type ToolReport struct {
+	Tool        string  `json:"tool"`
+	Calls       int     `json:"calls"`
+	OK          int     `json:"ok"`
+	ToolErrors  int     `json:"tool_errors"`
+	RateLimited int     `json:"rate_limited"`
+	Failed      int     `json:"failed"`
+	Latency     Latency `json:"latency"`
+}
+
+// Report is the result of a load test
+type Report struct {
+	Sessions      int           `json:"sessions"`
+	SessionErrors int           `json:"session_errors"`
+	Duration      time.Duration `json:"duration"`
+	// Throughput is the number of tool calls per second
+	Throughput float64       `json:"throughput"`
+	Total      ToolReport    `json:"total"`
+	Tools      []ToolReport  `json:"tools"`
+	Memory     *MemoryReport `json:"memory,omitempty"`
+}
+
+func newReport(samples []sample, sessions int, duration time.Duration) *Report {
+	byTool := make(map[string][]sample)
+	for _, s := range samples {
+		byTool[s.tool] = append(byTool[s.tool], s)
+	}
+
+	report := &Report{
+		Sessions: sessions,
+		Duration: duration,
+		Total:    newToolReport("total", samples),
+	}
+	if duration > 0 {
+		report.Throughput = float64(len(samples)) / duration.Seconds()
+	}
+	for tool, toolSamples := range byTool {
+		report.Tools = append(report.Tools, newToolReport(tool, toolSamples))
+	}
+	sort.Slice(report.Tools, func(i, j int) bool { return report.Tools[i].Tool < report.Tools[j].Tool })
+	return report
+}
+
+func newToolReport(tool string, samples []sample) ToolReport {
+	report := ToolReport{Tool: tool, Calls: len(samples)}
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		latencies = append(latencies, s.latency)
+		switch s.outcome {
+		case OutcomeOK:
+			report.OK++
+		case OutcomeToolError:
+			report.ToolErrors++
+		case OutcomeRateLimited:
+			report.RateLimited++
+		case OutcomeFailed:
+			report.Failed++
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.Latency = Latency{
+		P50: percentile(latencies, 50),
+		P90: percentile(latencies, 90),
+		P99: percentile(latencies, 99),
+	}
+	if len(latencies) > 0 {
+		report.Latency.Max = latencies[len(latencies)-1]
+	}
+	return report
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Write prints the report as a table, one row per tool and a total row
+func (r *Report) Write(out io.Writer) {
+	fmt.Fprintf(out, "%d sessions for %s: %d tool calls, %.1f calls/s", r.Sessions, r.Duration.Round(time.Millisecond), r.Total.Calls, r.Throughput)
+	if r.SessionErrors > 0 {
+		fmt.Fprintf(out, ", %d sessions failed to initialize", r.SessionErrors)
+	}
+	fmt.Fprint(out, "\n\n")
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "tool\tcalls\tok\ttool errors\trate limited\tfailed\tp50\tp90\tp99\tmax\t")
+	for _, tool := range append(append([]ToolReport(nil), r.Tools...), r.Total) {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t\n", tool.Tool, tool.Calls, tool.OK, tool.ToolErrors, tool.RateLimited, tool.Failed,
+			roundLatency(tool.Latency.P50), roundLatency(tool.Latency.P90), roundLatency(tool.Latency.P99), roundLatency(tool.Latency.Max))
+	}
+	_ = table.Flush()
+
+	if r.Memory != nil {
+		fmt.Fprintln(out)
+		r.Memory.Write(out)
+	}
+}
+
+func roundLatency(latency time.Duration) time.Duration {
+	return latency.Round(100 * time.Microsecond)
+}
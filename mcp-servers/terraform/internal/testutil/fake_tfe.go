@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package testutil provides fakes of the upstream APIs for the unit tests of tool handlers
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/jsonapi"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// FakeTFEToken is the token of the clients of a FakeTFE
+	FakeTFEToken = "fake-tfe-token"
+
+	apiPrefix        = "/api/v2"
+	jsonAPIMediaType = "application/vnd.api+json"
+)
+
+// Request is a request received by a FakeTFE, its path is relative to /api/v2
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+}
+
+// FakeTFE is an httptest server standing in for HCP Terraform or TFE. Tests register the responses
+// of the API calls a handler makes, by method and path relative to /api/v2, and check the requests
+// the handler sent. Calls without a response get a JSON:API 404, like a missing resource.
+type FakeTFE struct {
+	Server *httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string]http.HandlerFunc
+	requests []Request
+}
+
+// NewFakeTFE starts a fake TFE server, closed at the end of the test
+func NewFakeTFE(t testing.TB) *FakeTFE {
+	t.Helper()
+	fake := &FakeTFE{routes: make(map[string]http.HandlerFunc)}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(fake.Server.Close)
+	return fake
+}
+
+func (f *FakeTFE) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+	// go-tfe pings the server when the client is created, to read the API version and rate limit
+	if path == "/ping" {
+		w.Header().Set("TFP-API-Version", "2.6")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, Request{Method: r.Method, Path: path, Query: r.URL.Query(), Body: body})
+	handler, ok := f.routes[r.Method+" "+path]
+	f.mu.Unlock()
+
+	if !ok {
+		writeJSONAPIError(w, http.StatusNotFound, "not found")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	handler(w, r)
+}
+
+// Handle registers the handler of an API call, e.g. Handle("GET", "/organizations/org/workspaces/app", ...)
+func (f *FakeTFE) Handle(method string, path string, handler http.HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[method+" "+path] = handler
+}
+
+// Respond registers a JSON:API response of an API call. model is a go-tfe resource, e.g. a
+// *tfe.Workspace, or a slice of resources for a list without pagination.
+func (f *FakeTFE) Respond(method string, path string, status int, model any) {
+	f.Handle(method, path, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSONAPI(w, status, model, nil)
+	})
+}
+
+// RespondSequence registers the responses of successive calls of the same API call, e.g. the runs
+// created one after the other. Calls past the end of models get the last one.
+func (f *FakeTFE) RespondSequence(method string, path string, status int, models ...any) {
+	var mu sync.Mutex
+	calls := 0
+	f.Handle(method, path, func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		model := models[min(calls, len(models)-1)]
+		calls++
+		mu.Unlock()
+		writeJSONAPI(w, status, model, nil)
+	})
+}
+
+// RespondList registers the response of a list call, with the pagination go-tfe reads from its meta
+func (f *FakeTFE) RespondList(method string, path string, models any, pagination *tfe.Pagination) {
+	f.Handle(method, path, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSONAPI(w, http.StatusOK, models, &jsonapi.Meta{"pagination": pagination})
+	})
+}
+
+// RespondError registers a JSON:API error response of an API call
+func (f *FakeTFE) RespondError(method string, path string, status int, detail string) {
+	f.Handle(method, path, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSONAPIError(w, status, detail)
+	})
+}
+
+// Requests returns the API calls received so far, without the pings of new clients
+func (f *FakeTFE) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Request(nil), f.requests...)
+}
+
+// Client creates a go-tfe client of the fake server
+func (f *FakeTFE) Client(t testing.TB) *tfe.Client {
+	t.Helper()
+	tfeClient, err := tfe.NewClient(&tfe.Config{Address: f.Server.URL, Token: FakeTFEToken})
+	if err != nil {
+		t.Fatalf("creating the client of the fake TFE server: %v", err)
+	}
+	return tfeClient
+}
+
+// Context returns the context of a tool call whose handler uses a client of the fake server
+func (f *FakeTFE) Context(t testing.TB) context.Context {
+	t.Helper()
+	return client.ContextWithTfeClientProvider(context.Background(), StaticClients{Tfe: f.Client(t)})
+}
+
+// StaticClients provides the same clients to every tool call
+type StaticClients struct {
+	Tfe      *tfe.Client
+	Registry *http.Client
+}
+
+// TfeClient returns the TFE client, or an error when there is none like a session without a token
+func (c StaticClients) TfeClient(_ context.Context, _ *log.Logger) (*tfe.Client, error) {
+	if c.Tfe == nil {
+		return nil, fmt.Errorf("no TFE client")
+	}
+	return c.Tfe, nil
+}
+
+// RegistryClient returns the registry HTTP client, or the default client when there is none
+func (c StaticClients) RegistryClient(_ context.Context, _ *log.Logger) (*http.Client, error) {
+	if c.Registry == nil {
+		return http.DefaultClient, nil
+	}
+	return c.Registry, nil
+}
+
+func writeJSONAPI(w http.ResponseWriter, status int, model any, meta *jsonapi.Meta) {
+	payload, err := jsonapi.Marshal(model)
+	if err != nil {
+		writeJSONAPIError(w, http.StatusInternalServerError, fmt.Sprintf("marshalling the fake response: %v", err))
+		return
+	}
+	if many, ok := payload.(*jsonapi.ManyPayload); ok && meta != nil {
+		many.Meta = meta
+	}
+	w.Header().Set("Content-Type", jsonAPIMediaType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func writeJSONAPIError(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", jsonAPIMediaType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]any{{"status": fmt.Sprint(status), "title": http.StatusText(status), "detail": detail}},
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"net"
+	"strings"
+)
+
+// AllowedHostsEnv is the comma-separated list of Host header values accepted by the StreamableHTTP server
+const AllowedHostsEnv = "MCP_ALLOWED_HOSTS"
+
+// loopbackHosts are accepted by default when the server is bound to a loopback address
+var loopbackHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// isLoopbackHost reports whether host is localhost or a loopback IP address
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// ResolveAllowedHosts returns the Host header values accepted by a server bound to bindHost.
+// Explicitly configured hosts take precedence, "*" accepts any host, and a server bound to
+// a loopback address only accepts loopback hosts by default to prevent DNS rebinding.
+func ResolveAllowedHosts(configured []string, bindHost string) []string {
+	if len(configured) > 0 {
+		for _, host := range configured {
+			if host == "*" {
+				return nil
+			}
+		}
+		return configured
+	}
+	if bindHost != "" && isLoopbackHost(bindHost) {
+		return loopbackHosts
+	}
+	return nil
+}
+
+// isHostAllowed checks the Host header of a request against the allowed hosts. An entry
+// without a port matches the host on any port, an empty list accepts every host.
+func isHostAllowed(hostHeader string, allowedHosts []string) bool {
+	if len(allowedHosts) == 0 {
+		return true
+	}
+
+	hostHeader = strings.ToLower(hostHeader)
+	hostname := hostHeader
+	if h, _, err := net.SplitHostPort(hostHeader); err == nil {
+		hostname = h
+	}
+	hostname = strings.Trim(hostname, "[]")
+
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if allowed == hostHeader || strings.Trim(allowed, "[]") == hostname {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAllowedHosts(t *testing.T) {
+	assert.Equal(t, loopbackHosts, ResolveAllowedHosts(nil, "127.0.0.1"))
+	assert.Equal(t, loopbackHosts, ResolveAllowedHosts(nil, "localhost"))
+	assert.Equal(t, loopbackHosts, ResolveAllowedHosts(nil, "::1"))
+	assert.Nil(t, ResolveAllowedHosts(nil, "0.0.0.0"))
+	assert.Nil(t, ResolveAllowedHosts(nil, ""))
+	assert.Equal(t, []string{"mcp.example.com"}, ResolveAllowedHosts([]string{"mcp.example.com"}, "127.0.0.1"))
+	assert.Nil(t, ResolveAllowedHosts([]string{"*"}, "127.0.0.1"))
+}
+
+func TestIsHostAllowed(t *testing.T) {
+	tests := []struct {
+		host         string
+		allowedHosts []string
+		expected     bool
+	}{
+		{host: "localhost:8080", allowedHosts: loopbackHosts, expected: true},
+		{host: "LOCALHOST:8080", allowedHosts: loopbackHosts, expected: true},
+		{host: "127.0.0.1", allowedHosts: loopbackHosts, expected: true},
+		{host: "[::1]:8080", allowedHosts: loopbackHosts, expected: true},
+		{host: "attacker.example.com:8080", allowedHosts: loopbackHosts, expected: false},
+		{host: "localhost.attacker.example.com", allowedHosts: loopbackHosts, expected: false},
+		{host: "mcp.example.com:8443", allowedHosts: []string{"mcp.example.com:8443"}, expected: true},
+		{host: "mcp.example.com:9443", allowedHosts: []string{"mcp.example.com:8443"}, expected: false},
+		{host: "anything.example.com", allowedHosts: nil, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isHostAllowed(tt.host, tt.allowedHosts))
+		})
+	}
+}
+
+func TestSecurityHandlerRejectsUnexpectedHost(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	handler := NewSecurityHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), nil, "strict", ResolveAllowedHosts(nil, "127.0.0.1"), logger)
+
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8080/mcp", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// A rebound DNS name resolving to 127.0.0.1 keeps its own Host header
+	req = httptest.NewRequest(http.MethodPost, "http://rebind.attacker.example.com:8080/mcp", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,13 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+const (
+	// PricingDataFile points at a JSON pricing dataset replacing the one bundled with the estimate_plan_cost
+	// tool, e.g. with negotiated prices or the prices of another region
+	PricingDataFile = "MCP_PRICING_DATA_FILE"
+	// NamingConventionsFile points at the JSON naming and tagging conventions checked by the
+	// lint_naming_and_tags tool
+	NamingConventionsFile = "MCP_NAMING_CONVENTIONS_FILE"
+)
@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// CancelledNotification is the notification a client sends to cancel one of its in-flight requests
+const CancelledNotification = "notifications/cancelled"
+
+// ErrRequestCancelled is the cause of the context of a tool call cancelled by the client
+var ErrRequestCancelled = errors.New("the request was cancelled by the client")
+
+// toolCallKey identifies an in-flight tool call by its session and JSON-RPC request ID
+type toolCallKey struct {
+	sessionID string
+	requestID string
+}
+
+func newToolCallKey(ctx context.Context, requestID any) toolCallKey {
+	key := toolCallKey{requestID: fmt.Sprint(requestID)}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		key.sessionID = session.SessionID()
+	}
+	return key
+}
+
+// ToolCallCanceller cancels the context of in-flight tool calls when the client sends
+// notifications/cancelled, so that upstream HTTP calls and pagination loops stop right away
+type ToolCallCanceller struct {
+	logger *log.Logger
+
+	// pending maps the _meta of a tool call to its key, from the BeforeCallTool hook, which sees the
+	// JSON-RPC request ID, to the middleware, which does not. The _meta pointer is shared by both.
+	pending sync.Map
+
+	mu       sync.Mutex
+	inFlight map[toolCallKey]context.CancelCauseFunc
+}
+
+// NewToolCallCanceller creates a canceller. Its hooks, middleware and notification handler must all
+// be registered with the server.
+func NewToolCallCanceller(logger *log.Logger) *ToolCallCanceller {
+	return &ToolCallCanceller{logger: logger, inFlight: make(map[toolCallKey]context.CancelCauseFunc)}
+}
+
+// BeforeCallTool records the request ID of a tool call
+func (c *ToolCallCanceller) BeforeCallTool(ctx context.Context, id any, request *mcp.CallToolRequest) {
+	if request.Params.Meta == nil {
+		request.Params.Meta = &mcp.Meta{}
+	}
+	c.pending.Store(request.Params.Meta, newToolCallKey(ctx, id))
+}
+
+// AfterCallTool forgets a tool call rejected before the middleware ran
+func (c *ToolCallCanceller) AfterCallTool(_ context.Context, _ any, request *mcp.CallToolRequest, _ *mcp.CallToolResult) {
+	c.pending.Delete(request.Params.Meta)
+}
+
+// OnError forgets a failed tool call
+func (c *ToolCallCanceller) OnError(_ context.Context, _ any, method mcp.MCPMethod, message any, _ error) {
+	if request, ok := message.(*mcp.CallToolRequest); ok && method == mcp.MethodToolsCall {
+		c.pending.Delete(request.Params.Meta)
+	}
+}
+
+// Middleware runs each tool call with a context cancelled by notifications/cancelled, and
+// returns a CANCELLED tool error when the call was cancelled
+func (c *ToolCallCanceller) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			value, ok := c.pending.LoadAndDelete(request.Params.Meta)
+			if !ok {
+				return next(ctx, request)
+			}
+			key := value.(toolCallKey)
+
+			ctx, cancel := context.WithCancelCause(ctx)
+			c.mu.Lock()
+			c.inFlight[key] = cancel
+			c.mu.Unlock()
+			defer func() {
+				c.mu.Lock()
+				delete(c.inFlight, key)
+				c.mu.Unlock()
+				cancel(nil)
+			}()
+
+			result, err := next(ctx, request)
+			if errors.Is(context.Cause(ctx), ErrRequestCancelled) {
+				return utils.NewToolResultErrorWithCode(utils.ErrorCodeCancelled, ErrRequestCancelled.Error()), nil
+			}
+			return result, err
+		}
+	}
+}
+
+// HandleCancelled cancels the tool call named by the requestId of a notifications/cancelled
+// notification. Requests of other sessions and requests that already completed are ignored.
+func (c *ToolCallCanceller) HandleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	requestID, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+	key := newToolCallKey(ctx, requestID)
+
+	c.mu.Lock()
+	cancel, ok := c.inFlight[key]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	fields := log.Fields{"request_id": key.requestID}
+	if reason, ok := notification.Params.AdditionalFields["reason"].(string); ok && reason != "" {
+		fields["reason"] = reason
+	}
+	c.logger.WithFields(fields).Info("Cancelling tool call at the request of the client")
+	cancel(ErrRequestCancelled)
+}
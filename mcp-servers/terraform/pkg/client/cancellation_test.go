@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolCallCanceller(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	canceller := NewToolCallCanceller(logger)
+
+	started := make(chan struct{})
+	handler := canceller.Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.GetString("wait", "") == "" {
+			return mcp.NewToolResultText("done"), nil
+		}
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	ctx := mcpServer.WithContext(t.Context(), testSession{id: "session-1"})
+	otherCtx := mcpServer.WithContext(t.Context(), testSession{id: "session-2"})
+	cancelled := func(ctx context.Context, requestID any) {
+		notification := mcp.JSONRPCNotification{}
+		notification.Method = CancelledNotification
+		notification.Params.AdditionalFields = map[string]any{"requestId": requestID, "reason": "user aborted"}
+		canceller.HandleCancelled(ctx, notification)
+	}
+
+	// A call that completes is not affected, and is forgotten
+	request := mcp.CallToolRequest{}
+	canceller.BeforeCallTool(ctx, 1, &request)
+	result, err := handler(ctx, request)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	cancelled(ctx, 1)
+
+	request = mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"wait": "true"}
+	canceller.BeforeCallTool(ctx, float64(2), &request)
+	done := make(chan *mcp.CallToolResult)
+	go func() {
+		result, err := handler(ctx, request)
+		assert.NoError(t, err)
+		done <- result
+	}()
+	<-started
+
+	// Only the session that sent the request can cancel it
+	cancelled(otherCtx, 2)
+	select {
+	case <-done:
+		t.Fatal("tool call cancelled by another session")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Numeric request IDs are decoded as float64, and match the ID seen by the hook
+	cancelled(ctx, float64(2))
+	select {
+	case result := <-done:
+		require.True(t, result.IsError)
+		assert.Equal(t, utils.ErrorCodeCancelled, result.StructuredContent.(utils.ToolErrorResult).Code)
+	case <-time.After(time.Second):
+		t.Fatal("tool call not cancelled")
+	}
+	assert.Empty(t, canceller.inFlight)
+}
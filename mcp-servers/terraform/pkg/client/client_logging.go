@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ClientLogLevel is the level from which server events are sent to a session as MCP log
+	// notifications, until the client picks another level with logging/setLevel
+	ClientLogLevel = "MCP_CLIENT_LOG_LEVEL"
+	// DefaultClientLogLevel forwards rate limit hits and upstream issues, but not run updates
+	DefaultClientLogLevel = mcp.LoggingLevelWarning
+)
+
+// Loggers naming the source of the events sent to clients
+const (
+	ClientLoggerRateLimit = "rate_limit"
+	ClientLoggerUpstream  = "upstream"
+	ClientLoggerRuns      = "runs"
+)
+
+var clientLogLevels = []mcp.LoggingLevel{
+	mcp.LoggingLevelDebug, mcp.LoggingLevelInfo, mcp.LoggingLevelNotice, mcp.LoggingLevelWarning,
+	mcp.LoggingLevelError, mcp.LoggingLevelCritical, mcp.LoggingLevelAlert, mcp.LoggingLevelEmergency,
+}
+
+// LoadClientLogLevelFromEnv returns the initial log notification level of every session
+func LoadClientLogLevelFromEnv() mcp.LoggingLevel {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(ClientLogLevel)))
+	if value == "" {
+		return DefaultClientLogLevel
+	}
+	for _, level := range clientLogLevels {
+		if string(level) == value {
+			return level
+		}
+	}
+	log.Warnf("Invalid %s value %q, using %s", ClientLogLevel, value, DefaultClientLogLevel)
+	return DefaultClientLogLevel
+}
+
+// NotifyClient sends a server event to the client of the tool call of ctx as a log notification,
+// when the level of the session lets it through. Sessions that cannot receive notifications, e.g.
+// in stateless mode, are skipped.
+func NotifyClient(ctx context.Context, level mcp.LoggingLevel, logger string, data map[string]any) {
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return
+	}
+	// Best effort: a full notification channel or a session without logging support drops the event
+	_ = mcpServer.SendLogMessageToClient(ctx, newClientLogNotification(level, logger, data))
+}
+
+// NotifyAllClients sends a server event, e.g. an upstream outage, to every session of sessionIDs
+func NotifyAllClients(mcpServer *server.MCPServer, sessionIDs []string, level mcp.LoggingLevel, logger string, data map[string]any) {
+	notification := newClientLogNotification(level, logger, data)
+	for _, sessionID := range sessionIDs {
+		_ = mcpServer.SendLogMessageToSpecificClient(sessionID, notification)
+	}
+}
+
+// newClientLogNotification builds the notification of an event, masking the secrets of its string values
+func newClientLogNotification(level mcp.LoggingLevel, logger string, data map[string]any) mcp.LoggingMessageNotification {
+	redacted := make(map[string]any, len(data))
+	for key, value := range data {
+		if text, ok := value.(string); ok {
+			value = utils.RedactSecrets(text)
+		}
+		redacted[key] = value
+	}
+	return mcp.NewLoggingMessageNotification(level, logger, redacted)
+}
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadClientLogLevelFromEnv(t *testing.T) {
+	t.Setenv(ClientLogLevel, "")
+	assert.Equal(t, mcp.LoggingLevelWarning, LoadClientLogLevelFromEnv())
+
+	t.Setenv(ClientLogLevel, " Info ")
+	assert.Equal(t, mcp.LoggingLevelInfo, LoadClientLogLevelFromEnv())
+
+	t.Setenv(ClientLogLevel, "verbose")
+	assert.Equal(t, mcp.LoggingLevelWarning, LoadClientLogLevelFromEnv())
+}
+
+func TestNewClientLogNotification(t *testing.T) {
+	notification := newClientLogNotification(mcp.LoggingLevelError, ClientLoggerUpstream, map[string]any{
+		"error":  "request with Bearer abcdEFGH123456.atlasv1.aBcD_eFgH-1234567890abcdefghij failed",
+		"status": 503,
+	})
+	assert.Equal(t, "notifications/message", notification.Method)
+	assert.Equal(t, mcp.LoggingLevelError, notification.Params.Level)
+	assert.Equal(t, map[string]any{"error": "request with Bearer [REDACTED] failed", "status": 503}, notification.Params.Data)
+}
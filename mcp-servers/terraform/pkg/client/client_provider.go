@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// TfeClientProvider resolves the HCP Terraform or TFE client a tool call uses
+type TfeClientProvider interface {
+	TfeClient(ctx context.Context, logger *log.Logger) (*tfe.Client, error)
+}
+
+// RegistryClientProvider resolves the HTTP client a tool call uses to reach the registry
+type RegistryClientProvider interface {
+	RegistryClient(ctx context.Context, logger *log.Logger) (*http.Client, error)
+}
+
+// SessionClients is the default provider of both clients: the clients created for the MCP session
+// of the tool call, created on demand from the request context when the session has none yet
+type SessionClients struct{}
+
+// TfeClient returns the TFE client of the session of ctx
+func (SessionClients) TfeClient(ctx context.Context, logger *log.Logger) (*tfe.Client, error) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, fmt.Errorf("no active session")
+	}
+
+	// Try to get existing client
+	client := GetTfeClient(session.SessionID())
+	if client != nil {
+		return client, nil
+	}
+
+	logger.Warnf("TFE client not found, creating a new one")
+	return CreateTfeClientForSession(ctx, session, logger)
+}
+
+// RegistryClient returns the registry HTTP client of the session of ctx
+func (SessionClients) RegistryClient(ctx context.Context, logger *log.Logger) (*http.Client, error) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, fmt.Errorf("no active session")
+	}
+
+	// Try to get existing client
+	client := GetHttpClient(session.SessionID())
+	if client != nil {
+		return client, nil
+	}
+
+	logger.Warnf("HTTP client not found, creating a new one")
+	return CreateHttpClientForSession(ctx, session, logger), nil
+}
+
+type tfeClientProviderKey struct{}
+
+type registryClientProviderKey struct{}
+
+// ContextWithTfeClientProvider makes the tool calls of ctx use the TFE client of provider instead of
+// the client of their session, e.g. a client of a fake TFE server in unit tests
+func ContextWithTfeClientProvider(ctx context.Context, provider TfeClientProvider) context.Context {
+	return context.WithValue(ctx, tfeClientProviderKey{}, provider)
+}
+
+// ContextWithRegistryClientProvider makes the tool calls of ctx use the registry HTTP client of
+// provider instead of the client of their session
+func ContextWithRegistryClientProvider(ctx context.Context, provider RegistryClientProvider) context.Context {
+	return context.WithValue(ctx, registryClientProviderKey{}, provider)
+}
+
+func tfeClientProviderFromContext(ctx context.Context) TfeClientProvider {
+	if provider, ok := ctx.Value(tfeClientProviderKey{}).(TfeClientProvider); ok {
+		return provider
+	}
+	return SessionClients{}
+}
+
+func registryClientProviderFromContext(ctx context.Context) RegistryClientProvider {
+	if provider, ok := ctx.Value(registryClientProviderKey{}).(RegistryClientProvider); ok {
+		return provider
+	}
+	return SessionClients{}
+}
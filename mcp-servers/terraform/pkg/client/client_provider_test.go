@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClients struct {
+	tfe      *tfe.Client
+	registry *http.Client
+}
+
+func (c testClients) TfeClient(context.Context, *log.Logger) (*tfe.Client, error) {
+	return c.tfe, nil
+}
+
+func (c testClients) RegistryClient(context.Context, *log.Logger) (*http.Client, error) {
+	return c.registry, nil
+}
+
+func TestClientProviders(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	// Without a provider, the clients come from the MCP session
+	_, err := GetTfeClientFromContext(context.Background(), logger)
+	assert.EqualError(t, err, "no active session")
+	_, err = GetHttpClientFromContext(context.Background(), logger)
+	assert.EqualError(t, err, "no active session")
+
+	clients := testClients{tfe: &tfe.Client{}, registry: &http.Client{}}
+	ctx := ContextWithRegistryClientProvider(ContextWithTfeClientProvider(context.Background(), clients), clients)
+
+	tfeClient, err := GetTfeClientFromContext(ctx, logger)
+	require.NoError(t, err)
+	assert.Same(t, clients.tfe, tfeClient)
+
+	httpClient, err := GetHttpClientFromContext(ctx, logger)
+	require.NoError(t, err)
+	assert.Same(t, clients.registry, httpClient)
+}
+
+func TestTfeAvailable(t *testing.T) {
+	t.Setenv(TerraformToken, "")
+	assert.False(t, TfeAvailable(context.Background()))
+
+	// A token sent in the HTTP headers of the session
+	ctx := context.WithValue(context.Background(), contextKey(TerraformToken), "header-token")
+	assert.True(t, TfeAvailable(ctx))
+
+	t.Setenv(TerraformToken, "env-token")
+	assert.True(t, TfeAvailable(context.Background()))
+}
@@ -5,7 +5,6 @@ package client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,15 +13,15 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func GetLatestProviderVersion(httpClient *http.Client, providerNamespace string, providerName string, logger *log.Logger) (string, error) {
+func GetLatestProviderVersion(ctx context.Context, httpClient *http.Client, providerNamespace string, providerName string, logger *log.Logger) (string, error) {
 	uri := fmt.Sprintf("providers/%s/%s", providerNamespace, providerName)
-	jsonData, err := SendRegistryCall(httpClient, "GET", uri, logger, "v1")
+	jsonData, err := SendRegistryCall(ctx, httpClient, "GET", uri, logger, "v1")
 	if err != nil {
 		return "", utils.LogAndReturnError(logger, "making the latest provider version API request", err)
 	}
 
 	var providerVersionLatest ProviderVersionLatest
-	if err := json.Unmarshal(jsonData, &providerVersionLatest); err != nil {
+	if err := DecodeRegistryResponse(jsonData, &providerVersionLatest, logger); err != nil {
 		return "", utils.LogAndReturnError(logger, "unmarshalling provider versions request", err)
 	}
 
@@ -30,16 +29,68 @@ func GetLatestProviderVersion(httpClient *http.Client, providerNamespace string,
 	return providerVersionLatest.Version, nil
 }
 
+// GetProviderRegistryVersions lists the releases of a provider with the plugin protocols each supports
+// https://registry.terraform.io/v1/providers/hashicorp/aws/versions
+func GetProviderRegistryVersions(ctx context.Context, httpClient *http.Client, providerNamespace string, providerName string, logger *log.Logger) ([]ProviderRegistryVersion, error) {
+	uri := fmt.Sprintf("providers/%s/%s/versions", providerNamespace, providerName)
+	jsonData, err := SendRegistryCall(ctx, httpClient, "GET", uri, logger, "v1")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "making the provider versions API request", err)
+	}
+
+	var providerVersions ProviderRegistryVersions
+	if err := DecodeRegistryResponse(jsonData, &providerVersions, logger); err != nil {
+		return nil, utils.LogAndReturnError(logger, "unmarshalling provider versions request", err)
+	}
+	return providerVersions.Versions, nil
+}
+
+// GetProviderPackage reads the package of a provider version for a platform, with its checksums and signing keys
+// https://registry.terraform.io/v1/providers/hashicorp/aws/5.31.0/download/linux/amd64
+func GetProviderPackage(ctx context.Context, httpClient *http.Client, namespace string, name string, version string, os string, arch string, logger *log.Logger) (*ProviderPackage, error) {
+	uri := fmt.Sprintf("providers/%s/%s/%s/download/%s/%s", namespace, name, version, os, arch)
+	jsonData, err := SendRegistryCall(ctx, httpClient, "GET", uri, logger, "v1")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "making the provider package API request", err)
+	}
+
+	var providerPackage ProviderPackage
+	if err := DecodeRegistryResponse(jsonData, &providerPackage, logger); err != nil {
+		return nil, utils.LogAndReturnError(logger, "unmarshalling provider package request", err)
+	}
+	return &providerPackage, nil
+}
+
+// GetModuleDownloadsSummary reads the downloads of a module over the last week, month and year
+// https://registry.terraform.io/v2/modules/terraform-aws-modules/vpc/aws/downloads/summary
+func GetModuleDownloadsSummary(ctx context.Context, httpClient *http.Client, namespace string, name string, provider string, logger *log.Logger) (*DownloadsSummary, error) {
+	uri := fmt.Sprintf("modules/%s/%s/%s/downloads/summary", namespace, name, provider)
+	jsonData, err := SendRegistryCall(ctx, httpClient, "GET", uri, logger, "v2")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "making the module downloads summary API request", err)
+	}
+
+	var summary struct {
+		Data struct {
+			Attributes DownloadsSummary `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := DecodeRegistryResponse(jsonData, &summary, logger); err != nil {
+		return nil, utils.LogAndReturnError(logger, "unmarshalling module downloads summary request", err)
+	}
+	return &summary.Data.Attributes, nil
+}
+
 // Every provider version has a unique ID, which is used to identify the provider version in the registry and its specific documentation
 // https://registry.terraform.io/v2/providers/hashicorp/aws?include=provider-versions
-func GetProviderVersionID(httpClient *http.Client, namespace string, name string, version string, logger *log.Logger) (string, error) {
+func GetProviderVersionID(ctx context.Context, httpClient *http.Client, namespace string, name string, version string, logger *log.Logger) (string, error) {
 	uri := fmt.Sprintf("providers/%s/%s?include=provider-versions", namespace, name)
-	response, err := SendRegistryCall(httpClient, "GET", uri, logger, "v2")
+	response, err := SendRegistryCall(ctx, httpClient, "GET", uri, logger, "v2")
 	if err != nil {
 		return "", utils.LogAndReturnError(logger, "making provider version ID request", err)
 	}
 	var providerVersionList ProviderVersionList
-	if err := json.Unmarshal(response, &providerVersionList); err != nil {
+	if err := DecodeRegistryResponse(response, &providerVersionList, logger); err != nil {
 		return "", utils.LogAndReturnError(logger, "unmarshalling provider version ID request", err)
 	}
 	for _, providerVersion := range providerVersionList.Included {
@@ -50,21 +101,21 @@ func GetProviderVersionID(httpClient *http.Client, namespace string, name string
 	return "", fmt.Errorf("provider version %s not found", version)
 }
 
-func GetProviderOverviewDocs(httpClient *http.Client, providerVersionID string, logger *log.Logger) (string, error) {
+func GetProviderOverviewDocs(ctx context.Context, httpClient *http.Client, providerVersionID string, logger *log.Logger) (string, error) {
 	// https://registry.terraform.io/v2/provider-docs?filter[provider-version]=21818&filter[category]=overview&filter[slug]=index
 	uri := fmt.Sprintf("provider-docs?filter[provider-version]=%s&filter[category]=overview&filter[slug]=index", providerVersionID)
-	response, err := SendRegistryCall(httpClient, "GET", uri, logger, "v2")
+	response, err := SendRegistryCall(ctx, httpClient, "GET", uri, logger, "v2")
 	if err != nil {
 		return "", utils.LogAndReturnError(logger, "getting provider docs overview", err)
 	}
 	var providerOverview ProviderOverviewStruct
-	if err := json.Unmarshal(response, &providerOverview); err != nil {
+	if err := DecodeRegistryResponse(response, &providerOverview, logger); err != nil {
 		return "", utils.LogAndReturnError(logger, "getting provider docs request unmarshalling", err)
 	}
 
 	resourceContent := ""
 	for _, providerOverviewPage := range providerOverview.Data {
-		resourceContentNew, err := GetProviderResourceDocs(httpClient, providerOverviewPage.ID, logger)
+		resourceContentNew, err := GetProviderResourceDocs(ctx, httpClient, providerOverviewPage.ID, logger)
 		resourceContent += resourceContentNew
 		if err != nil {
 			return "", utils.LogAndReturnError(logger, "getting provider resource docs looping", err)
@@ -74,15 +125,15 @@ func GetProviderOverviewDocs(httpClient *http.Client, providerVersionID string,
 	return resourceContent, nil
 }
 
-func GetProviderResourceDocs(httpClient *http.Client, providerDocsID string, logger *log.Logger) (string, error) {
+func GetProviderResourceDocs(ctx context.Context, httpClient *http.Client, providerDocsID string, logger *log.Logger) (string, error) {
 	// https://registry.terraform.io/v2/provider-docs/8862001
 	uri := fmt.Sprintf("provider-docs/%s", providerDocsID)
-	response, err := SendRegistryCall(httpClient, "GET", uri, logger, "v2")
+	response, err := SendRegistryCall(ctx, httpClient, "GET", uri, logger, "v2")
 	if err != nil {
 		return "", utils.LogAndReturnError(logger, "getting provider resource docs ", err)
 	}
 	var providerServiceDetails ProviderResourceDetails
-	if err := json.Unmarshal(response, &providerServiceDetails); err != nil {
+	if err := DecodeRegistryResponse(response, &providerServiceDetails, logger); err != nil {
 		return "", utils.LogAndReturnError(logger, "unmarshalling provider resource docs", err)
 	}
 	return providerServiceDetails.Data.Attributes.Content, nil
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RegistryStatusError is returned for registry and GitHub responses other than 200 OK
+type RegistryStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *RegistryStatusError) Error() string {
+	return fmt.Sprintf("error: %s", e.Status)
+}
+
+// ClassifyError returns the error code of err. Codes attached with utils.WithErrorCode take
+// precedence, then upstream HCP Terraform/TFE and registry errors and timeouts are recognized.
+func ClassifyError(err error) utils.ErrorCode {
+	if code := utils.ErrorCodeOf(err); code != "" {
+		return code
+	}
+
+	var statusErr *RegistryStatusError
+	if errors.As(err, &statusErr) {
+		if code := errorCodeForStatus(statusErr.StatusCode); code != "" {
+			return code
+		}
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, tfe.ErrResourceNotFound):
+		return utils.ErrorCodeNotFound
+	case errors.Is(err, tfe.ErrUnauthorized):
+		return utils.ErrorCodeUnauthorized
+	case errors.Is(err, context.Canceled):
+		return utils.ErrorCodeCancelled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return utils.ErrorCodeUpstreamTimeout
+	}
+	return utils.ErrorCodeInternal
+}
+
+func errorCodeForStatus(statusCode int) utils.ErrorCode {
+	switch statusCode {
+	case http.StatusNotFound:
+		return utils.ErrorCodeNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return utils.ErrorCodeUnauthorized
+	case http.StatusTooManyRequests:
+		return utils.ErrorCodeRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return utils.ErrorCodeUpstreamTimeout
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return utils.ErrorCodeInvalidInput
+	}
+	return ""
+}
+
+// NewErrorCodeMiddleware turns the errors returned by tool handlers into tool error results
+// carrying a machine-readable error code, and tells the client about upstream timeouts
+func NewErrorCodeMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil {
+				code := ClassifyError(err)
+				if code == utils.ErrorCodeUpstreamTimeout {
+					NotifyClient(ctx, mcp.LoggingLevelError, ClientLoggerUpstream, map[string]any{
+						"message": "The registry or HCP Terraform/TFE did not answer in time",
+						"tool":    request.Params.Name,
+						"error":   err.Error(),
+					})
+				}
+				return utils.NewToolResultErrorWithCode(code, err.Error()), nil
+			}
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected utils.ErrorCode
+	}{
+		{name: "explicit code", err: utils.WithErrorCode(utils.ErrorCodeInvalidInput, errors.New("bad input")), expected: utils.ErrorCodeInvalidInput},
+		{name: "tfe not found", err: fmt.Errorf("reading workspace details, %w", tfe.ErrResourceNotFound), expected: utils.ErrorCodeNotFound},
+		{name: "tfe unauthorized", err: fmt.Errorf("listing runs, %w", tfe.ErrUnauthorized), expected: utils.ErrorCodeUnauthorized},
+		{name: "registry not found", err: &RegistryStatusError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}, expected: utils.ErrorCodeNotFound},
+		{name: "registry throttled", err: &RegistryStatusError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}, expected: utils.ErrorCodeRateLimited},
+		{name: "registry gateway timeout", err: &RegistryStatusError{StatusCode: http.StatusGatewayTimeout, Status: "504 Gateway Timeout"}, expected: utils.ErrorCodeUpstreamTimeout},
+		{name: "registry server error", err: &RegistryStatusError{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error"}, expected: utils.ErrorCodeInternal},
+		{name: "deadline exceeded", err: fmt.Errorf("calling registry: %w", context.DeadlineExceeded), expected: utils.ErrorCodeUpstreamTimeout},
+		{name: "cancelled", err: fmt.Errorf("calling registry: %w", context.Canceled), expected: utils.ErrorCodeCancelled},
+		{name: "unknown", err: errors.New("boom"), expected: utils.ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ClassifyError(tt.err))
+		})
+	}
+}
+
+func TestRegistryStatusError(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "providers/hashicorp/private", logger, "v2", server.URL)
+	require.Error(t, err)
+	assert.Equal(t, utils.ErrorCodeUnauthorized, ClassifyError(err))
+	assert.Contains(t, err.Error(), "401 Unauthorized")
+}
+
+func TestErrorCodeMiddleware(t *testing.T) {
+	handler := NewErrorCodeMiddleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Name == "get_workspace_details" {
+			return nil, fmt.Errorf("reading workspace details, %w", tfe.ErrResourceNotFound)
+		}
+		return mcp.NewToolResultText("success"), nil
+	})
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_workspace_details"}})
+	require.NoError(t, err, "handler errors are returned as tool error results")
+	assert.True(t, result.IsError)
+	assert.Equal(t, "[NOT_FOUND] reading workspace details, resource not found", result.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, utils.ToolErrorResult{Error: "reading workspace details, resource not found", Code: utils.ErrorCodeNotFound}, result.StructuredContent)
+
+	result, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "search_providers"}})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+}
@@ -0,0 +1,235 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/version"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	GitHubToken         = "GITHUB_TOKEN"
+	GitHubAPIURL        = "GITHUB_API_URL"
+	DefaultGitHubAPIURL = "https://api.github.com"
+
+	// maxGitHubResponseSize bounds the body read from the contents API, which inlines files up to 1 MB
+	maxGitHubResponseSize = 4 << 20
+)
+
+// GitHubSourceToolsEnabled reports whether the tools reading module sources from GitHub are
+// registered. They are only offered when a token is configured, since anonymous calls to the
+// GitHub API are limited to 60 requests per hour.
+func GitHubSourceToolsEnabled() bool {
+	return strings.TrimSpace(os.Getenv(GitHubToken)) != ""
+}
+
+// gitHubAPIURL returns the base URL of the GitHub API, api.github.com or a GitHub Enterprise Server
+func gitHubAPIURL() string {
+	if apiURL := strings.TrimSpace(os.Getenv(GitHubAPIURL)); apiURL != "" {
+		return strings.TrimSuffix(apiURL, "/")
+	}
+	return DefaultGitHubAPIURL
+}
+
+// gitHubHost returns the host serving the repositories of the configured GitHub API
+func gitHubHost() string {
+	apiURL, err := url.Parse(gitHubAPIURL())
+	if err != nil || apiURL.Host == "" || apiURL.Host == "api.github.com" {
+		return "github.com"
+	}
+	return apiURL.Host
+}
+
+// GitHubRepository identifies a repository on the configured GitHub host
+type GitHubRepository struct {
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+}
+
+func (r GitHubRepository) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// ParseGitHubSource extracts the repository from the source address of a registry module, e.g.
+// "https://github.com/terraform-aws-modules/terraform-aws-vpc" or
+// "git::https://github.com/org/repo.git?ref=v1.0.0". It returns false for any other VCS.
+func ParseGitHubSource(source string) (GitHubRepository, bool) {
+	source = strings.TrimPrefix(strings.TrimSpace(source), "git::")
+	if !strings.Contains(source, "://") {
+		source = "https://" + source
+	}
+
+	sourceURL, err := url.Parse(source)
+	if err != nil || !strings.EqualFold(sourceURL.Hostname(), gitHubHost()) {
+		return GitHubRepository{}, false
+	}
+
+	// Drop a "//subdirectory" suffix before splitting the path
+	path, _, _ := strings.Cut(strings.TrimPrefix(sourceURL.Path, "/"), "//")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return GitHubRepository{}, false
+	}
+	return GitHubRepository{Owner: parts[0], Name: strings.TrimSuffix(parts[1], ".git")}, true
+}
+
+// GitHubContent is a file or directory entry returned by the GitHub contents API
+type GitHubContent struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	SHA      string `json:"sha"`
+	Content  string `json:"content,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// GetGitHubContents returns the entries of a directory, or a single entry with the base64
+// encoded content of a file. An empty ref selects the default branch of the repository.
+func GetGitHubContents(ctx context.Context, httpClient *http.Client, repository GitHubRepository, path string, ref string, logger *log.Logger) ([]GitHubContent, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/contents/%s", gitHubAPIURL(),
+		url.PathEscape(repository.Owner), url.PathEscape(repository.Name), escapeContentPath(path))
+	if ref != "" {
+		endpoint += "?ref=" + url.QueryEscape(ref)
+	}
+
+	logger.Debugf("Fetching GitHub contents: %s", endpoint)
+	body, err := getGitHub(ctx, httpClient, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Directories are returned as an array, files as a single object
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		var entries []GitHubContent
+		if err := json.Unmarshal(body, &entries); err != nil {
+			return nil, fmt.Errorf("unmarshalling GitHub contents: %w", err)
+		}
+		return entries, nil
+	}
+
+	var entry GitHubContent
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshalling GitHub contents: %w", err)
+	}
+	return []GitHubContent{entry}, nil
+}
+
+// GitHubRepositoryInfo is the metadata of a repository. The open issues count includes pull requests.
+type GitHubRepositoryInfo struct {
+	FullName        string    `json:"full_name"`
+	HTMLURL         string    `json:"html_url"`
+	Archived        bool      `json:"archived"`
+	OpenIssuesCount int       `json:"open_issues_count"`
+	StargazersCount int       `json:"stargazers_count"`
+	PushedAt        time.Time `json:"pushed_at"`
+}
+
+// GetGitHubRepository returns the metadata of a repository
+func GetGitHubRepository(ctx context.Context, httpClient *http.Client, repository GitHubRepository, logger *log.Logger) (*GitHubRepositoryInfo, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s", gitHubAPIURL(), url.PathEscape(repository.Owner), url.PathEscape(repository.Name))
+
+	logger.Debugf("Fetching GitHub repository: %s", endpoint)
+	body, err := getGitHub(ctx, httpClient, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var info GitHubRepositoryInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("unmarshalling GitHub repository: %w", err)
+	}
+	return &info, nil
+}
+
+// GitHubAdvisory is a published security advisory of a repository
+type GitHubAdvisory struct {
+	GHSAID          string                `json:"ghsa_id"`
+	CVEID           string                `json:"cve_id"`
+	Summary         string                `json:"summary"`
+	Severity        string                `json:"severity"`
+	HTMLURL         string                `json:"html_url"`
+	PublishedAt     time.Time             `json:"published_at"`
+	WithdrawnAt     *time.Time            `json:"withdrawn_at"`
+	Vulnerabilities []GitHubVulnerability `json:"vulnerabilities"`
+	CVSS            *GitHubAdvisoryCVSS   `json:"cvss"`
+}
+
+// GitHubVulnerability is a package affected by an advisory, with the range of its vulnerable versions
+// (e.g., ">= 1.0.0, < 1.2.3") and the versions fixing it
+type GitHubVulnerability struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	VulnerableVersionRange string `json:"vulnerable_version_range"`
+	PatchedVersions        string `json:"patched_versions"`
+}
+
+// GitHubAdvisoryCVSS is the CVSS score of an advisory
+type GitHubAdvisoryCVSS struct {
+	Score        float64 `json:"score"`
+	VectorString string  `json:"vector_string"`
+}
+
+// ListGitHubRepositoryAdvisories returns the published security advisories of a repository, at most
+// the 100 most recent ones
+func ListGitHubRepositoryAdvisories(ctx context.Context, httpClient *http.Client, repository GitHubRepository, logger *log.Logger) ([]GitHubAdvisory, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/security-advisories?state=published&per_page=100", gitHubAPIURL(),
+		url.PathEscape(repository.Owner), url.PathEscape(repository.Name))
+
+	logger.Debugf("Fetching GitHub security advisories: %s", endpoint)
+	body, err := getGitHub(ctx, httpClient, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var advisories []GitHubAdvisory
+	if err := json.Unmarshal(body, &advisories); err != nil {
+		return nil, fmt.Errorf("unmarshalling GitHub security advisories: %w", err)
+	}
+	return advisories, nil
+}
+
+// getGitHub sends a GET request to the GitHub API, authenticated with GITHUB_TOKEN when it is set
+func getGitHub(ctx context.Context, httpClient *http.Client, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", fmt.Sprintf("terraform-mcp-server/%s", version.GetHumanVersion()))
+	if token := strings.TrimSpace(os.Getenv(GitHubToken)); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &RegistryStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxGitHubResponseSize))
+}
+
+// escapeContentPath escapes every segment of a repository path but keeps the separators
+func escapeContentPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitHubSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   GitHubRepository
+		ok     bool
+	}{
+		{"https url", "https://github.com/terraform-aws-modules/terraform-aws-vpc", GitHubRepository{"terraform-aws-modules", "terraform-aws-vpc"}, true},
+		{"without scheme", "github.com/hashicorp/example", GitHubRepository{"hashicorp", "example"}, true},
+		{"git forced getter", "git::https://github.com/org/repo.git?ref=v1.0.0", GitHubRepository{"org", "repo"}, true},
+		{"subdirectory", "github.com/org/repo//modules/child", GitHubRepository{"org", "repo"}, true},
+		{"other host", "https://gitlab.com/org/repo", GitHubRepository{}, false},
+		{"missing repository", "https://github.com/org", GitHubRepository{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseGitHubSource(tt.source)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseGitHubSource_Enterprise(t *testing.T) {
+	t.Setenv(GitHubAPIURL, "https://github.example.com/api/v3")
+
+	got, ok := ParseGitHubSource("https://github.example.com/platform/network")
+	require.True(t, ok)
+	assert.Equal(t, "platform/network", got.String())
+
+	_, ok = ParseGitHubSource("https://github.com/platform/network")
+	assert.False(t, ok)
+}
+
+func TestGetGitHubContents(t *testing.T) {
+	var gotAuth, gotPath, gotRef string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		gotRef = r.URL.Query().Get("ref")
+		switch r.URL.Path {
+		case "/repos/org/repo/contents/modules":
+			_, _ = w.Write([]byte(`[{"type":"file","name":"main.tf","path":"modules/main.tf","size":12,"sha":"abc"},{"type":"dir","name":"child","path":"modules/child"}]`))
+		case "/repos/org/repo/contents/main.tf":
+			_, _ = w.Write([]byte(`{"type":"file","name":"main.tf","path":"main.tf","size":4,"sha":"def","content":"dGVzdA==\n","encoding":"base64"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(GitHubAPIURL, server.URL+"/")
+	t.Setenv(GitHubToken, "ghp_test")
+	repository := GitHubRepository{Owner: "org", Name: "repo"}
+
+	entries, err := GetGitHubContents(t.Context(), server.Client(), repository, "/modules/", "v1.2.0", log.New())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer ghp_test", gotAuth)
+	assert.Equal(t, "v1.2.0", gotRef)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "dir", entries[1].Type)
+
+	entries, err = GetGitHubContents(t.Context(), server.Client(), repository, "main.tf", "", log.New())
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/org/repo/contents/main.tf", gotPath)
+	assert.Empty(t, gotRef)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "base64", entries[0].Encoding)
+
+	_, err = GetGitHubContents(t.Context(), server.Client(), repository, "missing.tf", "", log.New())
+	require.Error(t, err)
+	assert.Equal(t, "NOT_FOUND", string(ClassifyError(err)))
+}
+
+func TestGetGitHubRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/org/repo" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"full_name":"org/repo","archived":false,"open_issues_count":12,"stargazers_count":800,"pushed_at":"2026-01-02T03:04:05Z"}`))
+	}))
+	defer server.Close()
+	t.Setenv(GitHubAPIURL, server.URL)
+
+	info, err := GetGitHubRepository(t.Context(), server.Client(), GitHubRepository{Owner: "org", Name: "repo"}, log.New())
+	require.NoError(t, err)
+	assert.Equal(t, "org/repo", info.FullName)
+	assert.Equal(t, 12, info.OpenIssuesCount)
+	assert.Equal(t, 800, info.StargazersCount)
+	assert.Equal(t, 2026, info.PushedAt.Year())
+
+	_, err = GetGitHubRepository(t.Context(), server.Client(), GitHubRepository{Owner: "org", Name: "missing"}, log.New())
+	assert.Error(t, err)
+}
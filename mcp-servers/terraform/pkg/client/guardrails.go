@@ -0,0 +1,333 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// GuardrailPolicyFile is a JSON file of rules restricting when tools may change workspaces
+const GuardrailPolicyFile = "MCP_GUARDRAIL_POLICY_FILE"
+
+// guardrailOperationArguments name the operation of a tool, matched by the qualifier of a
+// tool:operation entry, e.g. action_run:apply or create_run:destroy
+var guardrailOperationArguments = []string{"run_action", "run_type"}
+
+var guardrailDays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// GuardrailPolicy is the set of rules loaded from MCP_GUARDRAIL_POLICY_FILE
+type GuardrailPolicy struct {
+	Rules []GuardrailRule `json:"rules"`
+}
+
+// GuardrailRule blocks the listed tools on the matching workspaces outside of its allowed windows.
+// A rule without workspace_tags and workspace_names applies to every workspace, and a rule without
+// allowed_windows blocks the tools at all times.
+type GuardrailRule struct {
+	Name           string            `json:"name"`
+	Tools          []string          `json:"tools"`
+	WorkspaceTags  []string          `json:"workspace_tags,omitempty"`
+	WorkspaceNames []string          `json:"workspace_names,omitempty"`
+	AllowedWindows []GuardrailWindow `json:"allowed_windows,omitempty"`
+	Message        string            `json:"message,omitempty"`
+}
+
+// GuardrailWindow is a daily time range, e.g. 09:00 to 17:00 on weekdays. An end before the start
+// spans midnight.
+type GuardrailWindow struct {
+	Days     []string `json:"days,omitempty"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Timezone string   `json:"timezone,omitempty"`
+
+	location   *time.Location
+	start, end time.Duration
+}
+
+// GuardrailWorkspace is a workspace targeted by a tool call
+type GuardrailWorkspace struct {
+	Name string
+	Tags []string
+}
+
+// GuardrailWorkspaceResolver returns the workspaces a tool call targets. It is only called for
+// tools listed by a rule.
+type GuardrailWorkspaceResolver func(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) ([]GuardrailWorkspace, error)
+
+// LoadGuardrailPolicyFromEnv reads the policy of MCP_GUARDRAIL_POLICY_FILE. It returns nil when
+// the variable is not set.
+func LoadGuardrailPolicyFromEnv() (*GuardrailPolicy, error) {
+	policyPath := strings.TrimSpace(os.Getenv(GuardrailPolicyFile))
+	if policyPath == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(policyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", GuardrailPolicyFile, err)
+	}
+	return ParseGuardrailPolicy(data)
+}
+
+// ParseGuardrailPolicy parses and validates a JSON guardrail policy
+func ParseGuardrailPolicy(data []byte) (*GuardrailPolicy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	policy := &GuardrailPolicy{}
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("parsing guardrail policy: %w", err)
+	}
+
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if len(rule.Tools) == 0 {
+			return nil, fmt.Errorf("guardrail %q: tools is required", rule.Name)
+		}
+		for _, pattern := range rule.WorkspaceNames {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("guardrail %q: invalid workspace name pattern %q", rule.Name, pattern)
+			}
+		}
+		for j := range rule.AllowedWindows {
+			if err := rule.AllowedWindows[j].parse(); err != nil {
+				return nil, fmt.Errorf("guardrail %q: %w", rule.Name, err)
+			}
+		}
+	}
+	return policy, nil
+}
+
+func (w *GuardrailWindow) parse() error {
+	var err error
+	if w.start, err = parseClock(w.Start); err != nil {
+		return fmt.Errorf("invalid window start %q, expected HH:MM", w.Start)
+	}
+	if w.end, err = parseClock(w.End); err != nil {
+		return fmt.Errorf("invalid window end %q, expected HH:MM", w.End)
+	}
+	if w.location, err = time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("invalid window timezone %q", w.Timezone)
+	}
+	for _, day := range w.Days {
+		if _, ok := guardrailDays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid window day %q, expected one of mon, tue, wed, thu, fri, sat, sun", day)
+		}
+	}
+	return nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// contains reports whether now falls in the window. The day of a window spanning midnight is the
+// day it starts.
+func (w GuardrailWindow) contains(now time.Time) bool {
+	local := now.In(w.location)
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	day := local.Weekday()
+	if w.end <= w.start {
+		if clock < w.end {
+			day = (day + 6) % 7
+		} else if clock < w.start {
+			return false
+		}
+	} else if clock < w.start || clock >= w.end {
+		return false
+	}
+
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, name := range w.Days {
+		if guardrailDays[strings.ToLower(name)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (w GuardrailWindow) String() string {
+	days := "every day"
+	if len(w.Days) > 0 {
+		days = strings.Join(w.Days, ",")
+	}
+	return fmt.Sprintf("%s %s-%s %s", days, w.Start, w.End, w.location)
+}
+
+// matchesTool reports whether the rule lists the tool, with the operation named by its arguments
+func (r GuardrailRule) matchesTool(request mcp.CallToolRequest) bool {
+	arguments := request.GetArguments()
+	for _, entry := range r.Tools {
+		tool, operation, qualified := strings.Cut(entry, ":")
+		if tool != request.Params.Name {
+			continue
+		}
+		if !qualified {
+			return true
+		}
+		for _, argument := range guardrailOperationArguments {
+			if value, ok := arguments[argument].(string); ok && strings.EqualFold(value, operation) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r GuardrailRule) selectsWorkspaces() bool {
+	return len(r.WorkspaceTags) > 0 || len(r.WorkspaceNames) > 0
+}
+
+// matchWorkspace returns the tag or name pattern selecting the workspace, or an empty string
+func (r GuardrailRule) matchWorkspace(workspace GuardrailWorkspace) string {
+	for _, tag := range r.WorkspaceTags {
+		for _, workspaceTag := range workspace.Tags {
+			if tag == workspaceTag {
+				return fmt.Sprintf("tag %s", tag)
+			}
+		}
+	}
+	for _, pattern := range r.WorkspaceNames {
+		if matched, _ := path.Match(pattern, workspace.Name); matched {
+			return fmt.Sprintf("name %s", pattern)
+		}
+	}
+	return ""
+}
+
+func (r GuardrailRule) allowedAt(now time.Time) bool {
+	for _, window := range r.AllowedWindows {
+		if window.contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// GuardrailMiddleware blocks tool calls violating the guardrail policy
+type GuardrailMiddleware struct {
+	policy  *GuardrailPolicy
+	resolve GuardrailWorkspaceResolver
+	schemas ToolSchemaLookup
+	logger  *log.Logger
+	now     func() time.Time
+}
+
+// NewGuardrailMiddleware enforces policy, resolving the workspaces of a call with resolve and the
+// arguments the tools declare with schemas. A nil policy allows every call.
+func NewGuardrailMiddleware(policy *GuardrailPolicy, resolve GuardrailWorkspaceResolver, schemas ToolSchemaLookup, logger *log.Logger) *GuardrailMiddleware {
+	return &GuardrailMiddleware{policy: policy, resolve: resolve, schemas: schemas, logger: logger, now: time.Now}
+}
+
+// Middleware returns the tool handler middleware. Dry runs change nothing and are always allowed.
+// When the workspaces of a call cannot be resolved, the call is blocked.
+func (m *GuardrailMiddleware) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if m.policy == nil || m.isDryRun(ctx, request) {
+				return next(ctx, request)
+			}
+
+			// Only the rules listing the tool and not allowing it right now can block the call
+			now := m.now()
+			var rules []GuardrailRule
+			needsWorkspaces := false
+			for _, rule := range m.policy.Rules {
+				if rule.matchesTool(request) && !rule.allowedAt(now) {
+					rules = append(rules, rule)
+					needsWorkspaces = needsWorkspaces || rule.selectsWorkspaces()
+				}
+			}
+			if len(rules) == 0 {
+				return next(ctx, request)
+			}
+
+			var workspaces []GuardrailWorkspace
+			if needsWorkspaces {
+				var err error
+				if workspaces, err = m.resolve(ctx, request, m.logger); err != nil {
+					m.logger.WithField("tool", request.Params.Name).Warnf("Blocking the call, its workspaces could not be checked against the guardrail policy: %v", err)
+					return utils.NewToolResultErrorWithCode(utils.ErrorCodePolicyViolation,
+						fmt.Sprintf("The call was blocked because its workspaces could not be checked against the guardrail policy: %v", err)), nil
+				}
+			}
+
+			for _, rule := range rules {
+				if violation := rule.violation(request.Params.Name, workspaces); violation != "" {
+					m.logger.WithFields(log.Fields{"tool": request.Params.Name, "guardrail": rule.Name}).Warn(violation)
+					return utils.NewToolResultErrorWithCode(utils.ErrorCodePolicyViolation, violation), nil
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// isDryRun reports whether the call is a dry run of a tool declaring the dry_run argument. Unknown
+// arguments are not rejected, so a dry_run passed to another tool must not skip the policy.
+func (m *GuardrailMiddleware) isDryRun(ctx context.Context, request mcp.CallToolRequest) bool {
+	if !request.GetBool("dry_run", false) || m.schemas == nil {
+		return false
+	}
+	schema, ok := m.schemas(ctx, request.Params.Name)
+	if !ok {
+		return false
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	_, declared := properties["dry_run"]
+	return declared
+}
+
+// violation describes why a call of the tool on the workspaces breaks the rule outside of its
+// allowed windows, or returns an empty string when the rule does not apply to the workspaces
+func (r GuardrailRule) violation(tool string, workspaces []GuardrailWorkspace) string {
+	target := ""
+	if !r.selectsWorkspaces() {
+		target = "any workspace"
+	}
+	for _, workspace := range workspaces {
+		if match := r.matchWorkspace(workspace); match != "" {
+			target = fmt.Sprintf("workspace %s (%s)", workspace.Name, match)
+			break
+		}
+	}
+	if target == "" {
+		return ""
+	}
+
+	violation := fmt.Sprintf("Blocked by guardrail %q: %s is not allowed on %s", r.Name, tool, target)
+	if len(r.AllowedWindows) > 0 {
+		windows := make([]string, 0, len(r.AllowedWindows))
+		for _, window := range r.AllowedWindows {
+			windows = append(windows, window.String())
+		}
+		violation += fmt.Sprintf(" outside of %s", strings.Join(windows, "; "))
+	}
+	if r.Message != "" {
+		violation += ". " + r.Message
+	}
+	return violation
+}
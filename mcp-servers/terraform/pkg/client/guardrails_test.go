@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGuardrailPolicy = `{
+  "rules": [
+    {
+      "name": "prod-apply-window",
+      "tools": ["action_run:apply", "create_run"],
+      "workspace_tags": ["prod"],
+      "workspace_names": ["*-prod"],
+      "allowed_windows": [{"days": ["mon", "tue", "wed", "thu", "fri"], "start": "09:00", "end": "17:00", "timezone": "Europe/Berlin"}],
+      "message": "Production changes go through the change window."
+    },
+    {"name": "no-deletes", "tools": ["delete_workspace_safely"]}
+  ]
+}`
+
+func TestParseGuardrailPolicy(t *testing.T) {
+	policy, err := ParseGuardrailPolicy([]byte(testGuardrailPolicy))
+	require.NoError(t, err)
+	require.Len(t, policy.Rules, 2)
+
+	invalid := map[string]string{
+		"UnknownField": `{"rules": [{"tools": ["create_run"], "workspace_tag": ["prod"]}]}`,
+		"NoTools":      `{"rules": [{"name": "empty"}]}`,
+		"BadPattern":   `{"rules": [{"tools": ["create_run"], "workspace_names": ["[prod"]}]}`,
+		"BadClock":     `{"rules": [{"tools": ["create_run"], "allowed_windows": [{"start": "9am", "end": "17:00"}]}]}`,
+		"BadTimezone":  `{"rules": [{"tools": ["create_run"], "allowed_windows": [{"start": "09:00", "end": "17:00", "timezone": "Mars/Base"}]}]}`,
+		"BadDay":       `{"rules": [{"tools": ["create_run"], "allowed_windows": [{"days": ["monday"], "start": "09:00", "end": "17:00"}]}]}`,
+	}
+	for name, policy := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseGuardrailPolicy([]byte(policy))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadGuardrailPolicyFromEnv(t *testing.T) {
+	t.Setenv(GuardrailPolicyFile, "")
+	policy, err := LoadGuardrailPolicyFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	path := filepath.Join(t.TempDir(), "guardrails.json")
+	require.NoError(t, os.WriteFile(path, []byte(testGuardrailPolicy), 0o600))
+	t.Setenv(GuardrailPolicyFile, path)
+	policy, err = LoadGuardrailPolicyFromEnv()
+	require.NoError(t, err)
+	assert.Len(t, policy.Rules, 2)
+}
+
+func TestGuardrailWindowContains(t *testing.T) {
+	weekdays := GuardrailWindow{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "17:00", Timezone: "UTC"}
+	overnight := GuardrailWindow{Days: []string{"fri"}, Start: "22:00", End: "02:00", Timezone: "UTC"}
+	require.NoError(t, weekdays.parse())
+	require.NoError(t, overnight.parse())
+
+	// 2025-01-03 is a Friday
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC) }
+	assert.True(t, weekdays.contains(at(3, 9, 0)))
+	assert.True(t, weekdays.contains(at(3, 16, 59)))
+	assert.False(t, weekdays.contains(at(3, 17, 0)))
+	assert.False(t, weekdays.contains(at(4, 12, 0)))
+
+	assert.True(t, overnight.contains(at(3, 23, 0)))
+	// Saturday 01:00 belongs to the window starting on Friday
+	assert.True(t, overnight.contains(at(4, 1, 0)))
+	assert.False(t, overnight.contains(at(4, 23, 0)))
+	assert.False(t, overnight.contains(at(3, 12, 0)))
+}
+
+func TestGuardrailMiddleware(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	policy, err := ParseGuardrailPolicy([]byte(testGuardrailPolicy))
+	require.NoError(t, err)
+
+	workspaces := map[string]GuardrailWorkspace{
+		"run-prod":    {Name: "app", Tags: []string{"prod"}},
+		"run-pattern": {Name: "network-prod"},
+		"run-dev":     {Name: "app-dev", Tags: []string{"dev"}},
+	}
+	resolved := 0
+	resolve := func(_ context.Context, request mcp.CallToolRequest, _ *log.Logger) ([]GuardrailWorkspace, error) {
+		resolved++
+		workspace, ok := workspaces[request.GetString("run_id", "")]
+		if !ok {
+			return nil, errors.New("run not found")
+		}
+		return []GuardrailWorkspace{workspace}, nil
+	}
+	schemas := func(_ context.Context, toolName string) (map[string]any, bool) {
+		if toolName != "create_run" {
+			return map[string]any{"properties": map[string]any{}}, true
+		}
+		return map[string]any{"properties": map[string]any{"dry_run": map[string]any{"type": "boolean"}}}, true
+	}
+	middleware := NewGuardrailMiddleware(policy, resolve, schemas, logger)
+	handler := middleware.Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	call := func(tool string, arguments map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool
+		request.Params.Arguments = arguments
+		result, err := handler(context.Background(), request)
+		require.NoError(t, err)
+		return result
+	}
+	blocked := func(result *mcp.CallToolResult) bool {
+		if !result.IsError {
+			return false
+		}
+		return result.StructuredContent.(utils.ToolErrorResult).Code == utils.ErrorCodePolicyViolation
+	}
+
+	// Saturday noon in Berlin, outside the window
+	middleware.now = func() time.Time { return time.Date(2025, 1, 4, 11, 0, 0, 0, time.UTC) }
+
+	result := call("action_run", map[string]any{"run_action": "apply", "run_id": "run-prod"})
+	require.True(t, blocked(result))
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `Blocked by guardrail "prod-apply-window": action_run is not allowed on workspace app (tag prod) outside of mon,tue,wed,thu,fri 09:00-17:00 Europe/Berlin. Production changes go through the change window.`)
+	assert.True(t, blocked(call("action_run", map[string]any{"run_action": "apply", "run_id": "run-pattern"})))
+	assert.False(t, blocked(call("action_run", map[string]any{"run_action": "apply", "run_id": "run-dev"})))
+	// The workspaces of a call that cannot be resolved are not trusted
+	assert.True(t, blocked(call("action_run", map[string]any{"run_action": "apply", "run_id": "run-unknown"})))
+
+	// Other operations, other tools and dry runs are not checked
+	resolved = 0
+	assert.False(t, blocked(call("action_run", map[string]any{"run_action": "cancel", "run_id": "run-prod"})))
+	assert.False(t, blocked(call("list_workspaces", map[string]any{})))
+	assert.False(t, blocked(call("create_run", map[string]any{"run_id": "run-prod", "dry_run": true})))
+	assert.Equal(t, 0, resolved)
+
+	// A rule without workspace selectors blocks the tool everywhere, without resolving workspaces
+	assert.True(t, blocked(call("delete_workspace_safely", map[string]any{"workspace_id": "ws-1"})))
+	// A dry_run the tool does not declare changes nothing to the call
+	assert.True(t, blocked(call("delete_workspace_safely", map[string]any{"workspace_id": "ws-1", "dry_run": true})))
+	assert.Equal(t, 0, resolved)
+
+	// Wednesday 10:00 in Berlin, inside the window
+	middleware.now = func() time.Time { return time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC) }
+	assert.False(t, blocked(call("action_run", map[string]any{"run_action": "apply", "run_id": "run-prod"})))
+	assert.Equal(t, 0, resolved)
+
+	// Without a policy every call is allowed
+	handler = NewGuardrailMiddleware(nil, resolve, schemas, logger).Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done"), nil
+	})
+	assert.False(t, blocked(call("delete_workspace_safely", map[string]any{"workspace_id": "ws-1"})))
+}
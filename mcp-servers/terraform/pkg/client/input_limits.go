@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// InputLimitsConfig holds the limits applied to HTTP request bodies and tool arguments
+type InputLimitsConfig struct {
+	MaxBodyBytes     int64 // Maximum size of an HTTP request body
+	MaxArgumentBytes int   // Maximum size of a single string argument
+	MaxArgumentDepth int   // Maximum nesting depth of object and array arguments
+}
+
+// DefaultInputLimitsConfig returns a sensible default configuration.
+// String arguments may hold a whole state file for analyze_state, so the limits are generous.
+func DefaultInputLimitsConfig() InputLimitsConfig {
+	return InputLimitsConfig{
+		MaxBodyBytes:     4 << 20, // 4 MiB
+		MaxArgumentBytes: 1 << 20, // 1 MiB
+		MaxArgumentDepth: 16,
+	}
+}
+
+// LoadInputLimitsConfigFromEnv loads input limits from environment variables
+func LoadInputLimitsConfigFromEnv() InputLimitsConfig {
+	config := DefaultInputLimitsConfig()
+
+	if maxBody := os.Getenv("MCP_MAX_BODY_BYTES"); maxBody != "" {
+		if value, err := strconv.ParseInt(strings.TrimSpace(maxBody), 10, 64); err == nil && value > 0 {
+			config.MaxBodyBytes = value
+			log.Infof("Maximum request body size set to %d bytes", value)
+		} else {
+			log.Warnf("Invalid MCP_MAX_BODY_BYTES value, using default %d bytes", config.MaxBodyBytes)
+		}
+	}
+
+	if maxArgument := os.Getenv("MCP_MAX_ARGUMENT_BYTES"); maxArgument != "" {
+		if value, err := strconv.Atoi(strings.TrimSpace(maxArgument)); err == nil && value > 0 {
+			config.MaxArgumentBytes = value
+			log.Infof("Maximum argument size set to %d bytes", value)
+		} else {
+			log.Warnf("Invalid MCP_MAX_ARGUMENT_BYTES value, using default %d bytes", config.MaxArgumentBytes)
+		}
+	}
+
+	return config
+}
+
+// bodyLimitHandler rejects HTTP request bodies larger than the configured limit
+type bodyLimitHandler struct {
+	handler  http.Handler
+	maxBytes int64
+	logger   *log.Logger
+}
+
+// NewBodyLimitHandler wraps the StreamableHTTP handler with a request body size limit.
+// Oversized requests are rejected with 413 and a JSON-RPC error before they are parsed.
+func NewBodyLimitHandler(handler http.Handler, maxBytes int64, logger *log.Logger) http.Handler {
+	return &bodyLimitHandler{
+		handler:  handler,
+		maxBytes: maxBytes,
+		logger:   logger,
+	}
+}
+
+func (h *bodyLimitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil || r.Body == http.NoBody {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	if r.ContentLength > h.maxBytes {
+		h.reject(w, r)
+		return
+	}
+
+	// The Content-Length header is optional, so read up to one byte past the limit to detect oversized bodies
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBytes+1))
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > h.maxBytes {
+		h.reject(w, r)
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	h.handler.ServeHTTP(w, r)
+}
+
+func (h *bodyLimitHandler) reject(w http.ResponseWriter, r *http.Request) {
+	h.logger.Warnf("Rejected request body larger than %d bytes from client %v", h.maxBytes, r.RemoteAddr)
+
+	response := mcp.NewJSONRPCError(mcp.NewRequestId(nil), mcp.INVALID_REQUEST,
+		fmt.Sprintf("request body exceeds the maximum size of %d bytes", h.maxBytes), nil)
+	body, _ := json.Marshal(response)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	w.Write(body)
+}
+
+// ToolSchemaLookup returns the input schema of a registered tool, decoded from JSON
+type ToolSchemaLookup func(ctx context.Context, toolName string) (map[string]any, bool)
+
+// InputValidationMiddleware validates tool arguments before they reach the tool handlers
+type InputValidationMiddleware struct {
+	config InputLimitsConfig
+	lookup ToolSchemaLookup
+	logger *log.Logger
+}
+
+// NewInputValidationMiddleware creates a new input validation middleware.
+// lookup may be nil, in which case arguments are not checked against the tool schema.
+func NewInputValidationMiddleware(config InputLimitsConfig, lookup ToolSchemaLookup, logger *log.Logger) *InputValidationMiddleware {
+	return &InputValidationMiddleware{
+		config: config,
+		lookup: lookup,
+		logger: logger,
+	}
+}
+
+// Middleware returns the tool handler middleware function
+func (m *InputValidationMiddleware) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			toolName := request.Params.Name
+
+			var schema map[string]any
+			if m.lookup != nil {
+				schema, _ = m.lookup(ctx, toolName)
+			}
+
+			if err := validateArguments(request.Params.Arguments, schema, m.config); err != nil {
+				m.logger.WithFields(toolLogFields(ctx, toolName)).Warnf("Rejected invalid arguments: %v", err)
+				result := utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, fmt.Sprintf("invalid arguments for tool %s: %v", toolName, err))
+				var argumentsErr *ArgumentsError
+				if errors.As(err, &argumentsErr) {
+					structured := result.StructuredContent.(utils.ToolErrorResult)
+					structured.Fields = argumentsErr.Fields
+					result.StructuredContent = structured
+				}
+				return result, nil
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+// validateArguments checks that the arguments are an object of valid UTF-8 values within
+// the configured limits, and that they match the input schema of the tool
+func validateArguments(arguments any, schema map[string]any, config InputLimitsConfig) error {
+	args := map[string]any{}
+	if arguments != nil {
+		var ok bool
+		if args, ok = arguments.(map[string]any); !ok {
+			return fmt.Errorf("arguments must be an object")
+		}
+	}
+
+	for name, value := range args {
+		if err := validateValue(name, value, 1, config); err != nil {
+			return err
+		}
+	}
+	return validateAgainstSchema(args, schema)
+}
+
+func validateValue(name string, value any, depth int, config InputLimitsConfig) error {
+	if depth > config.MaxArgumentDepth {
+		return fmt.Errorf("argument '%s' is nested deeper than %d levels", name, config.MaxArgumentDepth)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if len(v) > config.MaxArgumentBytes {
+			return fmt.Errorf("argument '%s' exceeds the maximum length of %d bytes", name, config.MaxArgumentBytes)
+		}
+		if !utf8.ValidString(v) {
+			return fmt.Errorf("argument '%s' is not valid UTF-8", name)
+		}
+	case []any:
+		for _, item := range v {
+			if err := validateValue(name, item, depth+1, config); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for key, item := range v {
+			if !utf8.ValidString(key) {
+				return fmt.Errorf("argument '%s' has a key that is not valid UTF-8", name)
+			}
+			if err := validateValue(name, item, depth+1, config); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
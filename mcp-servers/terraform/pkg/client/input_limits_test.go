@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadInputLimitsConfigFromEnv(t *testing.T) {
+	t.Setenv("MCP_MAX_BODY_BYTES", "2048")
+	t.Setenv("MCP_MAX_ARGUMENT_BYTES", "invalid")
+
+	config := LoadInputLimitsConfigFromEnv()
+	assert.Equal(t, int64(2048), config.MaxBodyBytes)
+	assert.Equal(t, DefaultInputLimitsConfig().MaxArgumentBytes, config.MaxArgumentBytes)
+
+	os.Unsetenv("MCP_MAX_BODY_BYTES")
+	os.Unsetenv("MCP_MAX_ARGUMENT_BYTES")
+	assert.Equal(t, DefaultInputLimitsConfig(), LoadInputLimitsConfigFromEnv())
+}
+
+func TestBodyLimitHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	var received string
+	handler := NewBodyLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusOK)
+	}), 16, logger)
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		expected      int
+	}{
+		{name: "within limit", body: `{"id":1}`, contentLength: 8, expected: http.StatusOK},
+		{name: "at limit", body: strings.Repeat("a", 16), contentLength: 16, expected: http.StatusOK},
+		{name: "declared too large", body: strings.Repeat("a", 17), contentLength: 17, expected: http.StatusRequestEntityTooLarge},
+		// Chunked requests do not declare a length, the body itself must be measured
+		{name: "undeclared too large", body: strings.Repeat("a", 32), contentLength: -1, expected: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			received = ""
+			req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(test.body))
+			req.ContentLength = test.contentLength
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expected, rec.Code)
+			if test.expected == http.StatusOK {
+				assert.Equal(t, test.body, received)
+			} else {
+				assert.Empty(t, received)
+				assert.Contains(t, rec.Body.String(), `"code":-32600`)
+				assert.Contains(t, rec.Body.String(), "maximum size of 16 bytes")
+			}
+		})
+	}
+}
+
+func TestValidateArguments(t *testing.T) {
+	config := InputLimitsConfig{MaxBodyBytes: 1024, MaxArgumentBytes: 8, MaxArgumentDepth: 3}
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":      map[string]any{"type": "string"},
+			"page_size": map[string]any{"type": "number"},
+			"count":     map[string]any{"type": "integer"},
+			"enabled":   map[string]any{"type": "string", "enum": []any{"true", "false"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		arguments any
+		errMsg    string
+	}{
+		{name: "no arguments", arguments: nil},
+		{name: "valid arguments", arguments: map[string]any{"name": "ws", "page_size": 20.0, "count": 3.0, "enabled": "true", "extra": []any{"a"}}},
+		{name: "null for optional argument", arguments: map[string]any{"name": nil}},
+		{name: "not an object", arguments: []any{"a"}, errMsg: "arguments must be an object"},
+		{name: "string too long", arguments: map[string]any{"name": "123456789"}, errMsg: "maximum length of 8 bytes"},
+		{name: "nested string too long", arguments: map[string]any{"extra": []any{"123456789"}}, errMsg: "maximum length of 8 bytes"},
+		{name: "invalid UTF-8", arguments: map[string]any{"name": "\xff"}, errMsg: "not valid UTF-8"},
+		{name: "too deeply nested", arguments: map[string]any{"extra": []any{[]any{[]any{"a"}}}}, errMsg: "nested deeper than 3 levels"},
+		{name: "wrong type", arguments: map[string]any{"name": 1.0}, errMsg: "'name' must be of type string"},
+		{name: "fractional integer", arguments: map[string]any{"count": 1.5}, errMsg: "'count' must be of type integer"},
+		{name: "value outside enum", arguments: map[string]any{"enabled": "yes"}, errMsg: "'enabled' must be one of"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateArguments(test.arguments, schema, config)
+			if test.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.errMsg)
+			}
+		})
+	}
+}
+
+func TestInputValidationMiddleware(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	lookup := func(_ context.Context, toolName string) (map[string]any, bool) {
+		return map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"name": map[string]any{"type": "string"}},
+			"required":   []any{"name"},
+		}, toolName == "test_tool"
+	}
+	middleware := NewInputValidationMiddleware(DefaultInputLimitsConfig(), lookup, logger)
+
+	called := false
+	handler := middleware.Middleware()(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "test_tool"
+	request.Params.Arguments = map[string]any{"name": true}
+
+	result, err := handler(t.Context(), request)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.False(t, called)
+	structured, ok := result.StructuredContent.(utils.ToolErrorResult)
+	require.True(t, ok)
+	assert.Equal(t, utils.ErrorCodeInvalidInput, structured.Code)
+	assert.Equal(t, []utils.FieldError{{Field: "name", Message: "must be of type string"}}, structured.Fields)
+
+	request.Params.Arguments = nil
+	result, err = handler(t.Context(), request)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.False(t, called)
+	assert.Equal(t, []utils.FieldError{{Field: "name", Message: "is required"}}, result.StructuredContent.(utils.ToolErrorResult).Fields)
+
+	request.Params.Arguments = map[string]any{"name": "valid"}
+	result, err = handler(t.Context(), request)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.True(t, called)
+}
@@ -10,6 +10,7 @@ import (
 	"net/textproto"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
 	log "github.com/sirupsen/logrus"
@@ -18,7 +19,8 @@ import (
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
 	AllowedOrigins []string
-	Mode           string // "strict", "development", "disabled"
+	Mode           string   // "strict", "development", "disabled"
+	AllowedHosts   []string // Host header values accepted, empty to accept any host
 }
 
 // LoadCORSConfigFromEnv loads CORS configuration from environment variables
@@ -40,9 +42,17 @@ func LoadCORSConfigFromEnv() CORSConfig {
 		}
 	}
 
+	var hosts []string
+	for _, host := range strings.Split(os.Getenv(AllowedHostsEnv), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
 	return CORSConfig{
 		AllowedOrigins: origins,
 		Mode:           mode,
+		AllowedHosts:   hosts,
 	}
 }
 
@@ -53,9 +63,21 @@ func isOriginAllowed(origin string, allowedOrigins []string, mode string) bool {
 		return true
 	}
 
-	// Check if origin is in the allowed list
-	for _, allowed := range allowedOrigins {
-		if origin == allowed {
+	// Invalid entries are rejected at startup, here they are skipped
+	patterns, _ := compileOriginPatterns(allowedOrigins)
+	return originAllowed(origin, patterns, mode)
+}
+
+// originAllowed checks the origin against the compiled allowed origin patterns
+func originAllowed(origin string, patterns []originPattern, mode string) bool {
+	// If mode is disabled, allow all origins
+	if mode == "disabled" {
+		return true
+	}
+
+	// Check if origin matches the allowed list
+	for _, pattern := range patterns {
+		if pattern.matches(origin) {
 			return true
 		}
 	}
@@ -78,18 +100,47 @@ func isOriginAllowed(origin string, allowedOrigins []string, mode string) bool {
 // securityHandler wraps the StreamableHTTP handler with origin validation
 type securityHandler struct {
 	handler        http.Handler
-	allowedOrigins []string
+	mu             sync.RWMutex
+	allowedOrigins []originPattern
 	corsMode       string
+	allowedHosts   []string
 	logger         *log.Logger
 }
 
+// CORSConfigUpdater is implemented by the security handler to apply a new CORS configuration without a restart
+type CORSConfigUpdater interface {
+	UpdateCORSConfig(config CORSConfig)
+}
+
+// UpdateCORSConfig replaces the allowed origins, the CORS mode and the allowed hosts of the running handler
+func (h *securityHandler) UpdateCORSConfig(config CORSConfig) {
+	patterns := h.compile(config.AllowedOrigins)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.allowedOrigins = patterns
+	h.corsMode = config.Mode
+	h.allowedHosts = config.AllowedHosts
+}
+
 // ServeHTTP implements the http.Handler interface
 func (h *securityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	allowedOrigins, corsMode, allowedHosts := h.allowedOrigins, h.corsMode, h.allowedHosts
+	h.mu.RUnlock()
+
+	// Validate Host header to protect locally bound servers against DNS rebinding
+	if !isHostAllowed(r.Host, allowedHosts) {
+		h.logger.Warnf("Rejected request with unexpected Host header: %s", r.Host)
+		http.Error(w, "Host not allowed", http.StatusForbidden)
+		return
+	}
+
 	// Validate Origin header
 	origin := r.Header.Get("Origin")
 	if origin != "" {
-		if !isOriginAllowed(origin, h.allowedOrigins, h.corsMode) {
-			h.logger.Warnf("Rejected request from unauthorized origin: %s (CORS mode: %s)", origin, h.corsMode)
+		if !originAllowed(origin, allowedOrigins, corsMode) {
+			h.logger.Warnf("Rejected request from unauthorized origin: %s (CORS mode: %s)", origin, corsMode)
 			http.Error(w, "Origin not allowed", http.StatusForbidden)
 			return
 		}
@@ -115,14 +166,26 @@ func (h *securityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.handler.ServeHTTP(w, r)
 }
 
-// NewSecurityHandler creates a new security handler
-func NewSecurityHandler(handler http.Handler, allowedOrigins []string, corsMode string, logger *log.Logger) http.Handler {
-	return &securityHandler{
-		handler:        handler,
-		allowedOrigins: allowedOrigins,
-		corsMode:       corsMode,
-		logger:         logger,
+// NewSecurityHandler creates a new security handler. Requests whose Host header is not in
+// allowedHosts are rejected, an empty list accepts every host.
+func NewSecurityHandler(handler http.Handler, allowedOrigins []string, corsMode string, allowedHosts []string, logger *log.Logger) http.Handler {
+	h := &securityHandler{
+		handler:      handler,
+		corsMode:     corsMode,
+		allowedHosts: allowedHosts,
+		logger:       logger,
 	}
+	h.allowedOrigins = h.compile(allowedOrigins)
+	return h
+}
+
+// compile compiles the allowed origins, logging and skipping invalid entries
+func (h *securityHandler) compile(allowedOrigins []string) []originPattern {
+	patterns, err := compileOriginPatterns(allowedOrigins)
+	if err != nil {
+		h.logger.Errorf("Ignoring invalid allowed origins: %v", err)
+	}
+	return patterns
 }
 
 // TerraformContextMiddleware adds Terraform-related header values to the request context
@@ -163,6 +226,9 @@ func TerraformContextMiddleware(logger *log.Logger) func(http.Handler) http.Hand
 				}
 			}
 
+			// The caller's organization allowlist only narrows the server list, so it has no query or environment fallback
+			ctx = context.WithValue(ctx, contextKey(AllowedOrganizations), r.Header.Get(textproto.CanonicalMIMEHeaderKey(AllowedOrganizations)))
+
 			// Call the next handler with the enriched context
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
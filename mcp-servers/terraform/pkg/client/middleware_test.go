@@ -265,7 +265,7 @@ func TestSecurityHandler(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := NewSecurityHandler(mockHandler, tt.allowedOrigins, tt.mode, logger)
+			handler := NewSecurityHandler(mockHandler, tt.allowedOrigins, tt.mode, nil, logger)
 
 			req := httptest.NewRequest("GET", "/mcp", nil)
 			if tt.origin != "" {
@@ -302,7 +302,7 @@ func TestOptionsRequest(t *testing.T) {
 
 	// Test case: OPTIONS request (CORS preflight) should be handled by the security handler
 	// and should return 200 OK with appropriate CORS headers
-	handler := NewSecurityHandler(mockHandler, []string{"https://example.com"}, "strict", logger)
+	handler := NewSecurityHandler(mockHandler, []string{"https://example.com"}, "strict", nil, logger)
 
 	req := httptest.NewRequest("OPTIONS", "/mcp", nil)
 	req.Header.Set("Origin", "https://example.com")
@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// Lifecycle statuses of a private module version
+const (
+	ModuleVersionDeprecated   = "Deprecated"
+	ModuleVersionUndeprecated = "Undeprecated"
+	ModuleVersionRevoked      = "Revoked"
+	ModuleVersionUnrevoked    = "Unrevoked"
+)
+
+// errModuleLifecycleUnsupported is returned when the module version cannot be found through the lifecycle
+// endpoint, which older TFE releases do not have
+var errModuleLifecycleUnsupported = errors.New("the module version does not exist, or this HCP Terraform/TFE does not support deprecating and revoking module versions")
+
+// ModuleVersionDeprecation is the deprecation of a private module version, with the reason shown to its consumers
+type ModuleVersionDeprecation struct {
+	Status string `json:"deprecated-status"`
+	Reason string `json:"reason,omitempty"`
+	Link   string `json:"link,omitempty"`
+}
+
+// ModuleVersionRevocation is the revocation of a private module version, which blocks new runs from using it
+type ModuleVersionRevocation struct {
+	Status string `json:"revoked-status"`
+	Reason string `json:"reason,omitempty"`
+	Link   string `json:"link,omitempty"`
+}
+
+// ModuleVersionLifecycle is the deprecation and revocation of a private module version. go-tfe does not
+// expose them yet, so they are read and written with raw requests on the module version.
+type ModuleVersionLifecycle struct {
+	Version     string                    `json:"version,omitempty"`
+	Deprecation *ModuleVersionDeprecation `json:"deprecation,omitempty"`
+	Revocation  *ModuleVersionRevocation  `json:"revocation,omitempty"`
+}
+
+// Deprecated reports whether the version is deprecated
+func (l ModuleVersionLifecycle) Deprecated() bool {
+	return l.Deprecation != nil && l.Deprecation.Status == ModuleVersionDeprecated
+}
+
+// Revoked reports whether the version is revoked
+func (l ModuleVersionLifecycle) Revoked() bool {
+	return l.Revocation != nil && l.Revocation.Status == ModuleVersionRevoked
+}
+
+// ModuleVersionDocument is the JSON:API document of a module version lifecycle
+type ModuleVersionDocument struct {
+	Data struct {
+		Type       string                 `json:"type"`
+		Attributes ModuleVersionLifecycle `json:"attributes"`
+	} `json:"data"`
+}
+
+// NewModuleVersionDocument returns the request body changing the deprecation and revocation of a module version
+func NewModuleVersionDocument(lifecycle ModuleVersionLifecycle) *ModuleVersionDocument {
+	document := &ModuleVersionDocument{}
+	document.Data.Type = "registry-module-versions"
+	document.Data.Attributes = ModuleVersionLifecycle{Deprecation: lifecycle.Deprecation, Revocation: lifecycle.Revocation}
+	return document
+}
+
+// ModuleVersionPath is the API path of a version of a private registry module
+func ModuleVersionPath(moduleID tfe.RegistryModuleID, version string) string {
+	return fmt.Sprintf("organizations/%s/registry-modules/private/%s/%s/%s/%s",
+		url.PathEscape(moduleID.Organization), url.PathEscape(moduleID.Namespace), url.PathEscape(moduleID.Name),
+		url.PathEscape(moduleID.Provider), url.PathEscape(version))
+}
+
+// ReadModuleVersionLifecycle reads the deprecation and revocation of a private module version
+func ReadModuleVersionLifecycle(ctx context.Context, tfeClient *tfe.Client, moduleID tfe.RegistryModuleID, version string) (ModuleVersionLifecycle, error) {
+	req, err := tfeClient.NewRequest("GET", ModuleVersionPath(moduleID, version), nil)
+	if err != nil {
+		return ModuleVersionLifecycle{}, err
+	}
+	document, err := doLifecycleRequest(ctx, req)
+	if err != nil {
+		return ModuleVersionLifecycle{}, err
+	}
+	document.Data.Attributes.Version = version
+	return document.Data.Attributes, nil
+}
+
+// UpdateModuleVersionLifecycle deprecates or revokes a private module version, or reverts it. Only the
+// deprecation or revocation that is set is changed.
+func UpdateModuleVersionLifecycle(ctx context.Context, tfeClient *tfe.Client, moduleID tfe.RegistryModuleID, lifecycle ModuleVersionLifecycle) (ModuleVersionLifecycle, error) {
+	req, err := tfeClient.NewRequest("PATCH", ModuleVersionPath(moduleID, lifecycle.Version), NewModuleVersionDocument(lifecycle))
+	if err != nil {
+		return ModuleVersionLifecycle{}, err
+	}
+	updated, err := doLifecycleRequest(ctx, req)
+	if err != nil {
+		return ModuleVersionLifecycle{}, err
+	}
+	updated.Data.Attributes.Version = lifecycle.Version
+	return updated.Data.Attributes, nil
+}
+
+// doLifecycleRequest sends a module version request and decodes the lifecycle attributes of the response. The
+// raw body is read rather than unmarshalled as JSON:API, whose models go-tfe does not have for these attributes,
+// so that error responses are still mapped to the go-tfe errors.
+func doLifecycleRequest(ctx context.Context, req *tfe.ClientRequest) (ModuleVersionDocument, error) {
+	var document ModuleVersionDocument
+	var body bytes.Buffer
+	if err := req.Do(ctx, &body); err != nil {
+		if errors.Is(err, tfe.ErrResourceNotFound) {
+			return document, fmt.Errorf("%w: %w", errModuleLifecycleUnsupported, err)
+		}
+		return document, err
+	}
+	if err := json.Unmarshal(body.Bytes(), &document); err != nil {
+		return document, fmt.Errorf("decoding the module version: %w", err)
+	}
+	return document, nil
+}
@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// AllowedOrganizations is a comma-separated list of organization name patterns the tools may
+	// target. Set as an environment variable it applies to every caller; sent as an HTTP header it
+	// restricts the session further and can never widen the server list.
+	AllowedOrganizations = "MCP_ALLOWED_ORGANIZATIONS"
+	// DeniedOrganizations is a comma-separated list of organization name patterns no caller may target
+	DeniedOrganizations = "MCP_DENIED_ORGANIZATIONS"
+)
+
+// sessionAllowedOrganizations holds the allowlist sent in the headers of the request initializing each session
+var sessionAllowedOrganizations sync.Map
+
+// OrganizationAccess is the server-level allowlist and denylist of organization name patterns. A
+// pattern uses path.Match syntax, e.g. "acme-*", and is matched case-insensitively.
+type OrganizationAccess struct {
+	Allowed []string
+	Denied  []string
+}
+
+// OrganizationResolver returns the organization targeted by a tool call that only names a workspace
+// or run by ID, or an empty string when the call does not target a single organization.
+type OrganizationResolver func(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (string, error)
+
+// LoadOrganizationAccessFromEnv reads MCP_ALLOWED_ORGANIZATIONS and MCP_DENIED_ORGANIZATIONS
+func LoadOrganizationAccessFromEnv() (OrganizationAccess, error) {
+	access := OrganizationAccess{
+		Allowed: parseOrganizationPatterns(os.Getenv(AllowedOrganizations)),
+		Denied:  parseOrganizationPatterns(os.Getenv(DeniedOrganizations)),
+	}
+	for _, patterns := range [][]string{access.Allowed, access.Denied} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return OrganizationAccess{}, fmt.Errorf("invalid organization pattern %q", pattern)
+			}
+		}
+	}
+	return access, nil
+}
+
+func parseOrganizationPatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+func matchesOrganization(patterns []string, organization string) bool {
+	organization = strings.ToLower(organization)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, organization); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether the organization is allowed by the server lists
+func (a OrganizationAccess) allows(organization string) bool {
+	if matchesOrganization(a.Denied, organization) {
+		return false
+	}
+	return len(a.Allowed) == 0 || matchesOrganization(a.Allowed, organization)
+}
+
+func (a OrganizationAccess) restricted() bool {
+	return len(a.Allowed) > 0 || len(a.Denied) > 0
+}
+
+// callerAllowlists returns the allowlists sent by the caller, when the session was initialized and
+// with the current request
+func callerAllowlists(ctx context.Context) [][]string {
+	var allowlists [][]string
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		if allowed, ok := sessionAllowedOrganizations.Load(session.SessionID()); ok {
+			allowlists = append(allowlists, allowed.([]string))
+		}
+	}
+	if header, ok := ctx.Value(contextKey(AllowedOrganizations)).(string); ok {
+		if allowed := parseOrganizationPatterns(header); len(allowed) > 0 {
+			allowlists = append(allowlists, allowed)
+		}
+	}
+	return allowlists
+}
+
+// saveSessionAllowedOrganizations records the allowlist header of the request initializing the session
+func saveSessionAllowedOrganizations(ctx context.Context, session server.ClientSession) {
+	header, _ := ctx.Value(contextKey(AllowedOrganizations)).(string)
+	if allowed := parseOrganizationPatterns(header); len(allowed) > 0 {
+		sessionAllowedOrganizations.Store(session.SessionID(), allowed)
+	}
+}
+
+func deleteSessionAllowedOrganizations(sessionID string) {
+	sessionAllowedOrganizations.Delete(sessionID)
+}
+
+type organizationAccessKey struct{}
+
+// OrganizationAccessMiddleware rejects tool calls targeting organizations the caller may not touch
+type OrganizationAccessMiddleware struct {
+	access  OrganizationAccess
+	resolve OrganizationResolver
+	logger  *log.Logger
+}
+
+// NewOrganizationAccessMiddleware enforces the server-level access lists and the allowlists sent by
+// each caller, finding the organization of calls without a terraform_org_name argument with resolve.
+func NewOrganizationAccessMiddleware(access OrganizationAccess, resolve OrganizationResolver, logger *log.Logger) *OrganizationAccessMiddleware {
+	return &OrganizationAccessMiddleware{access: access, resolve: resolve, logger: logger}
+}
+
+// OrganizationAllowed reports whether the caller of ctx may target the organization, for tools
+// listing organizations. Every organization is allowed outside of the middleware.
+func OrganizationAllowed(ctx context.Context, organization string) bool {
+	if m, ok := ctx.Value(organizationAccessKey{}).(*OrganizationAccessMiddleware); ok {
+		return m.Allowed(ctx, organization)
+	}
+	return true
+}
+
+// Allowed reports whether the caller of ctx may target the organization
+func (m *OrganizationAccessMiddleware) Allowed(ctx context.Context, organization string) bool {
+	if !m.access.allows(organization) {
+		return false
+	}
+	for _, allowed := range callerAllowlists(ctx) {
+		if !matchesOrganization(allowed, organization) {
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware returns the tool handler middleware. Calls naming an organization are checked before
+// the tool makes any API call; calls only naming a workspace or run by ID are checked after reading
+// its organization. When the organization cannot be found, the call is rejected.
+func (m *OrganizationAccessMiddleware) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx = context.WithValue(ctx, organizationAccessKey{}, m)
+			if !m.access.restricted() && len(callerAllowlists(ctx)) == 0 {
+				return next(ctx, request)
+			}
+
+			organization := strings.TrimSpace(request.GetString("terraform_org_name", ""))
+			if organization == "" && m.resolve != nil {
+				var err error
+				if organization, err = m.resolve(ctx, request, m.logger); err != nil {
+					m.logger.WithField("tool", request.Params.Name).Warnf("Rejecting the call, its organization could not be checked: %v", err)
+					return utils.NewToolResultErrorWithCode(utils.ErrorCodeUnauthorized,
+						fmt.Sprintf("The call was rejected because its organization could not be checked against the allowed organizations: %v", err)), nil
+				}
+			}
+			if organization == "" {
+				return next(ctx, request)
+			}
+
+			if !m.Allowed(ctx, organization) {
+				m.logger.WithFields(log.Fields{"tool": request.Params.Name, "organization": organization}).Warn("Rejected a call targeting an organization outside the allowed organizations")
+				return utils.NewToolResultErrorWithCode(utils.ErrorCodeUnauthorized,
+					fmt.Sprintf("Organization %q is not in the organizations this session may access", organization)), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSession struct{ id string }
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return s.id }
+
+func TestLoadOrganizationAccessFromEnv(t *testing.T) {
+	t.Setenv(AllowedOrganizations, " Acme-*, platform ,")
+	t.Setenv(DeniedOrganizations, "acme-sandbox")
+	access, err := LoadOrganizationAccessFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme-*", "platform"}, access.Allowed)
+	assert.Equal(t, []string{"acme-sandbox"}, access.Denied)
+
+	assert.True(t, access.allows("acme-prod"))
+	assert.True(t, access.allows("PLATFORM"))
+	assert.False(t, access.allows("acme-sandbox"))
+	assert.False(t, access.allows("other"))
+
+	t.Setenv(AllowedOrganizations, "[acme")
+	_, err = LoadOrganizationAccessFromEnv()
+	assert.Error(t, err)
+}
+
+func TestOrganizationAccessMiddleware(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	resolved := 0
+	resolve := func(_ context.Context, request mcp.CallToolRequest, _ *log.Logger) (string, error) {
+		resolved++
+		switch request.GetString("run_id", "") {
+		case "":
+			return "", nil
+		case "run-acme":
+			return "acme-prod", nil
+		case "run-other":
+			return "other", nil
+		}
+		return "", errors.New("run not found")
+	}
+
+	access := OrganizationAccess{Allowed: []string{"acme-*", "platform"}, Denied: []string{"acme-sandbox"}}
+	called := 0
+	handler := NewOrganizationAccessMiddleware(access, resolve, logger).Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called++
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	call := func(ctx context.Context, arguments map[string]any) bool {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "list_workspaces"
+		request.Params.Arguments = arguments
+		result, err := handler(ctx, request)
+		require.NoError(t, err)
+		if result.IsError {
+			assert.Equal(t, utils.ErrorCodeUnauthorized, result.StructuredContent.(utils.ToolErrorResult).Code)
+		}
+		return !result.IsError
+	}
+	org := func(name string) map[string]any { return map[string]any{"terraform_org_name": name} }
+
+	ctx := context.Background()
+	assert.True(t, call(ctx, org("acme-prod")))
+	assert.True(t, call(ctx, org("Platform")))
+	assert.False(t, call(ctx, org("acme-sandbox")))
+	assert.False(t, call(ctx, org("other")))
+	assert.Equal(t, 0, resolved)
+
+	// Calls naming a run are checked against the organization of its workspace
+	assert.True(t, call(ctx, map[string]any{"run_id": "run-acme"}))
+	assert.False(t, call(ctx, map[string]any{"run_id": "run-other"}))
+	assert.False(t, call(ctx, map[string]any{"run_id": "run-unknown"}))
+	// Calls without an organization, e.g. registry tools, are not restricted
+	assert.True(t, call(ctx, map[string]any{}))
+	assert.Equal(t, 4, called)
+
+	// The allowlist of the session, and of the current request, narrow the server list
+	session := testSession{id: "session-1"}
+	saveSessionAllowedOrganizations(context.WithValue(ctx, contextKey(AllowedOrganizations), "acme-prod, other"), session)
+	defer deleteSessionAllowedOrganizations(session.id)
+	sessionCtx := server.NewMCPServer("test", "1.0.0").WithContext(ctx, session)
+	assert.True(t, call(sessionCtx, org("acme-prod")))
+	assert.False(t, call(sessionCtx, org("acme-staging")))
+	// The caller cannot allow an organization outside of the server list
+	assert.False(t, call(sessionCtx, org("other")))
+
+	requestCtx := context.WithValue(sessionCtx, contextKey(AllowedOrganizations), "acme-staging")
+	assert.False(t, call(requestCtx, org("acme-prod")))
+	assert.False(t, call(requestCtx, org("acme-staging")))
+
+	// Without server lists, the caller's allowlist is enforced on its own
+	handler = NewOrganizationAccessMiddleware(OrganizationAccess{}, resolve, logger).Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		assert.False(t, OrganizationAllowed(ctx, "platform"))
+		return mcp.NewToolResultText("done"), nil
+	})
+	assert.True(t, call(sessionCtx, org("other")))
+	assert.False(t, call(sessionCtx, org("platform")))
+
+	// Without any list, every organization is allowed
+	handler = NewOrganizationAccessMiddleware(OrganizationAccess{}, resolve, logger).Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done"), nil
+	})
+	assert.True(t, call(ctx, org("anything")))
+	assert.True(t, OrganizationAllowed(ctx, "anything"))
+}
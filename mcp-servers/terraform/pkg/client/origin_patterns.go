@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// originRegexPrefix marks an MCP_ALLOWED_ORIGINS entry as a regular expression
+const originRegexPrefix = "regex:"
+
+// originPattern is a compiled entry of MCP_ALLOWED_ORIGINS. An entry is either an exact
+// origin, a wildcard subdomain pattern like https://*.corp.example.com, or a regular
+// expression prefixed with "regex:" that must match the whole Origin header.
+type originPattern struct {
+	exact      string
+	normalized string // exact in canonical form, empty when it is not a valid http(s) origin
+
+	// Wildcard patterns match any subdomain of hostSuffix, but not hostSuffix itself
+	scheme     string
+	hostSuffix string
+	port       string
+
+	regex *regexp.Regexp
+}
+
+// parseOriginPattern compiles an MCP_ALLOWED_ORIGINS entry
+func parseOriginPattern(entry string) (originPattern, error) {
+	if expr, ok := strings.CutPrefix(entry, originRegexPrefix); ok {
+		regex, err := regexp.Compile(`^(?:` + expr + `)$`)
+		if err != nil {
+			return originPattern{}, fmt.Errorf("invalid origin regex %q: %w", expr, err)
+		}
+		return originPattern{regex: regex}, nil
+	}
+	if !strings.Contains(entry, "*") {
+		normalized, _ := normalizeOrigin(entry)
+		return originPattern{exact: entry, normalized: normalized}, nil
+	}
+
+	if entry == "*" {
+		return originPattern{}, errors.New("origin \"*\" is not supported, set MCP_CORS_MODE=disabled to allow every origin")
+	}
+	scheme, host, port, err := splitOrigin(strings.Replace(entry, "*", "wildcard", 1))
+	if err != nil {
+		return originPattern{}, fmt.Errorf("invalid origin pattern %q: %w", entry, err)
+	}
+	hostSuffix, ok := strings.CutPrefix(host, "wildcard.")
+	if !ok || strings.Count(entry, "*") > 1 {
+		return originPattern{}, fmt.Errorf("invalid origin pattern %q: the wildcard must be the leftmost label of the host, e.g. https://*.example.com", entry)
+	}
+	if !strings.Contains(hostSuffix, ".") {
+		return originPattern{}, fmt.Errorf("invalid origin pattern %q: the wildcard must be followed by at least two labels", entry)
+	}
+	return originPattern{scheme: scheme, hostSuffix: hostSuffix, port: port}, nil
+}
+
+// splitOrigin returns the lower-cased scheme and host and the port of an origin. The default
+// port of the scheme is returned as empty, since browsers omit it from the Origin header.
+func splitOrigin(origin string) (string, string, string, error) {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return "", "", "", err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", "", "", fmt.Errorf("scheme must be http or https")
+	}
+	if u.Hostname() == "" || u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return "", "", "", fmt.Errorf("must be of the form scheme://host[:port]")
+	}
+
+	port := u.Port()
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		port = ""
+	}
+	return scheme, strings.ToLower(u.Hostname()), port, nil
+}
+
+// normalizeOrigin returns the canonical form of an origin, so that https://Example.com:443 and https://example.com compare equal
+func normalizeOrigin(origin string) (string, error) {
+	scheme, host, port, err := splitOrigin(origin)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	return scheme + "://" + host, nil
+}
+
+// matches reports whether the origin is allowed by the pattern
+func (p originPattern) matches(origin string) bool {
+	switch {
+	case p.regex != nil:
+		return p.regex.MatchString(origin)
+	case p.hostSuffix == "":
+		if origin == p.exact {
+			return true
+		}
+		normalized, err := normalizeOrigin(origin)
+		return err == nil && p.normalized != "" && normalized == p.normalized
+	}
+
+	scheme, host, port, err := splitOrigin(origin)
+	if err != nil {
+		return false
+	}
+	return scheme == p.scheme && port == p.port && strings.HasSuffix(host, "."+p.hostSuffix)
+}
+
+// compileOriginPatterns compiles the allowed origins, returning the patterns that are valid
+// and an error joining the ones that are not
+func compileOriginPatterns(allowedOrigins []string) ([]originPattern, error) {
+	patterns := make([]originPattern, 0, len(allowedOrigins))
+	var errs []error
+	for _, entry := range allowedOrigins {
+		if entry == "" {
+			continue
+		}
+		pattern, err := parseOriginPattern(entry)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, errors.Join(errs...)
+}
+
+// Validate checks that every allowed origin is a valid exact origin, wildcard pattern or regex
+func (c CORSConfig) Validate() error {
+	_, err := compileOriginPatterns(c.AllowedOrigins)
+	return err
+}
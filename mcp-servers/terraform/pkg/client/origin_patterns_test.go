@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOriginPatternMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		origin   string
+		expected bool
+	}{
+		{name: "exact", pattern: "https://example.com", origin: "https://example.com", expected: true},
+		{name: "exact with explicit default port", pattern: "https://example.com:443", origin: "https://example.com", expected: true},
+		{name: "exact is case insensitive", pattern: "https://Example.COM", origin: "https://example.com", expected: true},
+		{name: "exact with different port", pattern: "https://example.com:8443", origin: "https://example.com", expected: false},
+		{name: "exact with different scheme", pattern: "https://example.com", origin: "http://example.com", expected: false},
+		{name: "exact null origin", pattern: "null", origin: "null", expected: true},
+
+		{name: "wildcard subdomain", pattern: "https://*.corp.example.com", origin: "https://app.corp.example.com", expected: true},
+		{name: "wildcard nested subdomain", pattern: "https://*.corp.example.com", origin: "https://a.b.corp.example.com", expected: true},
+		{name: "wildcard does not match apex", pattern: "https://*.corp.example.com", origin: "https://corp.example.com", expected: false},
+		{name: "wildcard label boundary", pattern: "https://*.corp.example.com", origin: "https://evilcorp.example.com", expected: false},
+		{name: "wildcard suffix attack", pattern: "https://*.corp.example.com", origin: "https://app.corp.example.com.evil.com", expected: false},
+		{name: "wildcard scheme mismatch", pattern: "https://*.corp.example.com", origin: "http://app.corp.example.com", expected: false},
+		{name: "wildcard without port", pattern: "https://*.corp.example.com", origin: "https://app.corp.example.com:8443", expected: false},
+		{name: "wildcard default port", pattern: "https://*.corp.example.com", origin: "https://app.corp.example.com:443", expected: true},
+		{name: "wildcard with port", pattern: "http://*.corp.example.com:8080", origin: "http://app.corp.example.com:8080", expected: true},
+		{name: "wildcard with other port", pattern: "http://*.corp.example.com:8080", origin: "http://app.corp.example.com:8081", expected: false},
+		{name: "wildcard with path", pattern: "https://*.corp.example.com", origin: "https://app.corp.example.com/path", expected: false},
+
+		{name: "regex", pattern: `regex:https://app-[0-9]+\.example\.com`, origin: "https://app-12.example.com", expected: true},
+		{name: "regex is anchored", pattern: `regex:https://app-[0-9]+\.example\.com`, origin: "https://app-12.example.com.evil.com", expected: false},
+		{name: "regex alternation is anchored", pattern: `regex:https://a\.example\.com|https://b\.example\.com`, origin: "https://b.example.com.evil.com", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := parseOriginPattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, pattern.matches(tt.origin))
+		})
+	}
+}
+
+func TestParseOriginPatternErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		message string
+	}{
+		{pattern: "*", message: "MCP_CORS_MODE=disabled"},
+		{pattern: "https://app.*.example.com", message: "leftmost label"},
+		{pattern: "https://*.*.example.com", message: "leftmost label"},
+		{pattern: "https://*app.example.com", message: "leftmost label"},
+		{pattern: "https://*.com", message: "at least two labels"},
+		{pattern: "ftp://*.example.com", message: "scheme must be http or https"},
+		{pattern: "https://*.example.com/path", message: "scheme://host[:port]"},
+		{pattern: "https://example.com:*", message: "invalid origin pattern"},
+		{pattern: "regex:https://(", message: "invalid origin regex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := parseOriginPattern(tt.pattern)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
+
+func TestCORSConfigValidate(t *testing.T) {
+	assert.NoError(t, CORSConfig{AllowedOrigins: []string{"https://example.com", "https://*.corp.example.com", `regex:https://[a-z]+\.example\.org`}}.Validate())
+
+	err := CORSConfig{AllowedOrigins: []string{"https://*.com", "https://example.com", "regex:("}}.Validate()
+	assert.ErrorContains(t, err, "https://*.com")
+	assert.ErrorContains(t, err, "invalid origin regex")
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	OutboundProxy = "MCP_OUTBOUND_PROXY"
+	CACertFile    = "MCP_CA_CERT_FILE"
+)
+
+// outboundProxy returns the proxy used for calls to the registry and HCP Terraform/TFE.
+// MCP_OUTBOUND_PROXY takes precedence over HTTPS_PROXY and HTTP_PROXY, and hosts listed
+// in NO_PROXY are always reached directly.
+func outboundProxy(logger *log.Logger) func(*http.Request) (*url.URL, error) {
+	proxy := strings.TrimSpace(os.Getenv(OutboundProxy))
+	if proxy == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyURL, err := parseOutboundProxy(proxy)
+	if err != nil {
+		logger.Warnf("%v, falling back to the proxy environment variables", err)
+		return http.ProxyFromEnvironment
+	}
+
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}
+}
+
+// parseOutboundProxy parses MCP_OUTBOUND_PROXY, accepting "proxy.internal:3128" the same way the
+// standard environment variables do
+func parseOutboundProxy(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		proxyURL, err = url.Parse("http://" + proxy)
+	}
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid %s value %q", OutboundProxy, proxy)
+	}
+	return proxyURL, nil
+}
+
+// OutboundProxyFor returns the proxy of the calls to target, nil when it is reached directly.
+// It fails when MCP_OUTBOUND_PROXY is invalid, which the clients ignore with a warning.
+func OutboundProxyFor(target string) (*url.URL, error) {
+	if proxy := strings.TrimSpace(os.Getenv(OutboundProxy)); proxy != "" {
+		if _, err := parseOutboundProxy(proxy); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	return outboundProxy(log.StandardLogger())(req)
+}
+
+// bypassProxy reports whether host matches one of the comma-separated NO_PROXY entries.
+// Entries can be "*", a domain which also matches its subdomains, an IP address or a CIDR range.
+func bypassProxy(host string, noProxy string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		// Ports in NO_PROXY entries are ignored, the proxy is bypassed for every port of the host
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCACertPool returns the system certificate pool with the PEM certificates in caFile added
+func loadCACertPool(caFile string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle %s: %w", caFile, err)
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", caFile)
+	}
+	return pool, nil
+}
+
+// outboundRootCAs returns the certificate pool configured with MCP_CA_CERT_FILE, or nil to use the system pool
+func outboundRootCAs(logger *log.Logger) *x509.CertPool {
+	caFile := strings.TrimSpace(os.Getenv(CACertFile))
+	if caFile == "" {
+		return nil
+	}
+	pool, err := loadCACertPool(caFile)
+	if err != nil {
+		logger.Errorf("Failed to load %s, using the system certificates: %v", CACertFile, err)
+		return nil
+	}
+	return pool
+}
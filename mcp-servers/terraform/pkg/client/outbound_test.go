@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBypassProxy(t *testing.T) {
+	noProxy := "localhost, .internal.example.com,corp.example:8443,10.0.0.0/8,192.168.1.5"
+
+	tests := []struct {
+		host     string
+		expected bool
+	}{
+		{host: "localhost", expected: true},
+		{host: "tfe.internal.example.com", expected: true},
+		{host: "internal.example.com", expected: true},
+		{host: "registry.corp.example", expected: true},
+		{host: "10.20.30.40", expected: true},
+		{host: "192.168.1.5", expected: true},
+		{host: "192.168.1.6", expected: false},
+		{host: "registry.terraform.io", expected: false},
+		{host: "notinternal.example.com", expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			assert.Equal(t, test.expected, bypassProxy(test.host, noProxy))
+		})
+	}
+
+	assert.True(t, bypassProxy("anything.example.com", "*"))
+	assert.False(t, bypassProxy("registry.terraform.io", ""))
+}
+
+func TestOutboundProxy(t *testing.T) {
+	logger := log.New()
+	t.Setenv("NO_PROXY", "tfe.internal")
+
+	t.Setenv(OutboundProxy, "proxy.example.com:3128")
+	proxy := outboundProxy(logger)
+
+	req := httptest.NewRequest(http.MethodGet, "https://registry.terraform.io/v1/providers", nil)
+	proxyURL, err := proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+
+	req = httptest.NewRequest(http.MethodGet, "https://tfe.internal/api/v2/ping", nil)
+	proxyURL, err = proxy(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxyURL)
+}
+
+func TestOutboundProxyFor(t *testing.T) {
+	t.Setenv("NO_PROXY", "tfe.internal")
+	t.Setenv(OutboundProxy, "proxy.example.com:3128")
+
+	proxyURL, err := OutboundProxyFor("https://registry.terraform.io/.well-known/terraform.json")
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+
+	proxyURL, err = OutboundProxyFor("https://tfe.internal/api/v2/ping")
+	require.NoError(t, err)
+	assert.Nil(t, proxyURL)
+
+	t.Setenv(OutboundProxy, "proxy example.com")
+	_, err = OutboundProxyFor("https://registry.terraform.io")
+	assert.EqualError(t, err, `invalid MCP_OUTBOUND_PROXY value "proxy example.com"`)
+}
+
+func TestLoadCACertPool(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, cert, 0600))
+
+	pool, err := loadCACertPool(caFile)
+	require.NoError(t, err)
+
+	// The test server certificate is only trusted through the CA bundle
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = loadCACertPool(filepath.Join(t.TempDir(), "missing.pem"))
+	assert.ErrorContains(t, err, "reading CA bundle")
+
+	invalidFile := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not a certificate"), 0600))
+	_, err = loadCACertPool(invalidFile)
+	assert.ErrorContains(t, err, "no PEM certificates")
+}
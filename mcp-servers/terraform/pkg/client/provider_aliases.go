@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const TerraformProviderAliases = "TERRAFORM_PROVIDER_ALIASES"
+
+// defaultProviderAliases maps common names of platforms to the registry provider, optionally
+// prefixed with its namespace when the provider is not published by HashiCorp
+var defaultProviderAliases = map[string]string{
+	"gcp":          "google",
+	"gce":          "google",
+	"gke":          "google",
+	"googlecloud":  "google",
+	"google-cloud": "google",
+	"k8s":          "kubernetes",
+	"kube":         "kubernetes",
+	"azure":        "azurerm",
+	"az":           "azurerm",
+	"entra":        "azuread",
+	"entra-id":     "azuread",
+	"amazon":       "aws",
+	"ec2":          "aws",
+	"oci":          "oracle/oci",
+	"oracle":       "oracle/oci",
+	"digitalocean": "digitalocean/digitalocean",
+	"cf":           "cloudflare/cloudflare",
+	"cloudflare":   "cloudflare/cloudflare",
+	"github":       "integrations/github",
+	"gh":           "integrations/github",
+	"gitlab":       "gitlabhq/gitlab",
+	"datadog":      "datadog/datadog",
+	"dd":           "datadog/datadog",
+	"pagerduty":    "pagerduty/pagerduty",
+	"snowflake":    "snowflake-labs/snowflake",
+	"okta":         "okta/okta",
+	"tfe":          "hashicorp/tfe",
+	"hcp":          "hashicorp/hcp",
+}
+
+// ProviderAliases returns the provider aliases, the built-in ones extended or overridden by
+// TERRAFORM_PROVIDER_ALIASES, a comma separated list of alias=name or alias=namespace/name
+func ProviderAliases(logger *log.Logger) map[string]string {
+	aliases := make(map[string]string, len(defaultProviderAliases))
+	for alias, provider := range defaultProviderAliases {
+		aliases[alias] = provider
+	}
+
+	for _, entry := range strings.Split(os.Getenv(TerraformProviderAliases), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		alias, provider, ok := strings.Cut(entry, "=")
+		alias, provider = strings.ToLower(strings.TrimSpace(alias)), strings.ToLower(strings.TrimSpace(provider))
+		if !ok || alias == "" || provider == "" || strings.Count(provider, "/") > 1 {
+			if logger != nil {
+				logger.Warnf("Invalid %s entry %q, expected alias=name or alias=namespace/name", TerraformProviderAliases, entry)
+			}
+			continue
+		}
+		aliases[alias] = provider
+	}
+	return aliases
+}
+
+// ResolveProviderAlias replaces an aliased provider name with the registry provider. The namespace
+// of the alias is only used when the caller did not ask for a namespace other than hashicorp.
+func ResolveProviderAlias(namespace, name string, logger *log.Logger) (string, string) {
+	provider, ok := ProviderAliases(logger)[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return namespace, name
+	}
+
+	aliasNamespace, aliasName, hasNamespace := strings.Cut(provider, "/")
+	if !hasNamespace {
+		aliasName = provider
+	} else if namespace == "" || strings.EqualFold(namespace, "hashicorp") {
+		namespace = aliasNamespace
+	}
+	if logger != nil {
+		logger.Debugf("Resolved provider alias %q to %s/%s", name, namespace, aliasName)
+	}
+	return namespace, aliasName
+}
+
+// ResolveProviderAliasesInQuery replaces aliased provider names among the words of a search query
+func ResolveProviderAliasesInQuery(query string, logger *log.Logger) string {
+	aliases := ProviderAliases(logger)
+	words := strings.Fields(query)
+	for i, word := range words {
+		if provider, ok := aliases[strings.ToLower(word)]; ok {
+			// Module searches match the provider name, not its namespace
+			_, name, hasNamespace := strings.Cut(provider, "/")
+			if !hasNamespace {
+				name = provider
+			}
+			words[i] = name
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveProviderAlias(t *testing.T) {
+	tests := []struct {
+		name              string
+		namespace         string
+		provider          string
+		expectedNamespace string
+		expectedName      string
+	}{
+		{"name alias keeps namespace", "hashicorp", "gcp", "hashicorp", "google"},
+		{"alias is case insensitive", "hashicorp", "K8s", "hashicorp", "kubernetes"},
+		{"namespaced alias replaces default namespace", "hashicorp", "github", "integrations", "github"},
+		{"namespaced alias with empty namespace", "", "cf", "cloudflare", "cloudflare"},
+		{"explicit namespace is kept", "myorg", "github", "myorg", "github"},
+		{"unknown name is unchanged", "hashicorp", "random", "hashicorp", "random"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace, name := ResolveProviderAlias(tt.namespace, tt.provider, log.New())
+			assert.Equal(t, tt.expectedNamespace, namespace)
+			assert.Equal(t, tt.expectedName, name)
+		})
+	}
+}
+
+func TestProviderAliases_FromEnv(t *testing.T) {
+	t.Setenv(TerraformProviderAliases, "corp=acme/internal, gcp = googlebeta ,invalid,too=many/slashes/here")
+
+	aliases := ProviderAliases(log.New())
+	assert.Equal(t, "acme/internal", aliases["corp"])
+	assert.Equal(t, "googlebeta", aliases["gcp"], "configured aliases override the built-in ones")
+	assert.Equal(t, "kubernetes", aliases["k8s"])
+	assert.NotContains(t, aliases, "invalid")
+	assert.NotContains(t, aliases, "too")
+}
+
+func TestResolveProviderAliasesInQuery(t *testing.T) {
+	assert.Equal(t, "google vpc", ResolveProviderAliasesInQuery("gcp vpc", log.New()))
+	assert.Equal(t, "cloudflare dns", ResolveProviderAliasesInQuery("cf  dns", log.New()))
+	assert.Equal(t, "vpc", ResolveProviderAliasesInQuery("vpc", log.New()))
+}
@@ -5,7 +5,7 @@ package client
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -24,6 +24,20 @@ type RateLimitConfig struct {
 	GlobalBurst     int        // Global burst capacity
 	PerSessionLimit rate.Limit // Per-session requests per second
 	PerSessionBurst int        // Per-session burst capacity
+
+	Adaptive         bool          // Tighten the global limit when the registry or HCP Terraform/TFE throttles the server
+	AdaptiveMinLimit rate.Limit    // Lowest global requests per second the adaptive limit goes down to
+	AdaptiveCooldown time.Duration // Time without upstream throttling before the global limit is relaxed again
+
+	ToolLimits map[string]ToolRateLimit // Per-tool overrides keyed by tool name
+}
+
+// ToolRateLimit holds the rate limit and concurrency cap of a single tool, shared by all sessions.
+// A zero Limit or MaxConcurrency leaves that aspect of the tool unlimited.
+type ToolRateLimit struct {
+	Limit          rate.Limit // Requests per second
+	Burst          int        // Burst capacity
+	MaxConcurrency int        // Maximum number of in-flight calls
 }
 
 // DefaultRateLimitConfig returns a sensible default configuration
@@ -33,6 +47,10 @@ func DefaultRateLimitConfig() RateLimitConfig {
 		GlobalBurst:     20,
 		PerSessionLimit: rate.Every(time.Second / 5), // 5 requests per second per session
 		PerSessionBurst: 10,
+
+		Adaptive:         true,
+		AdaptiveMinLimit: rate.Limit(1),
+		AdaptiveCooldown: time.Minute,
 	}
 }
 
@@ -62,9 +80,67 @@ func LoadRateLimitConfigFromEnv() RateLimitConfig {
 		}
 	}
 
+	// Adaptive rate limiting based on upstream throttling
+	if adaptive := os.Getenv("MCP_RATE_LIMIT_ADAPTIVE"); adaptive != "" {
+		if enabled, err := strconv.ParseBool(strings.TrimSpace(adaptive)); err == nil {
+			config.Adaptive = enabled
+		} else {
+			log.Warnf("Invalid MCP_RATE_LIMIT_ADAPTIVE value, using default %t", config.Adaptive)
+		}
+	}
+	if cooldown := os.Getenv("MCP_RATE_LIMIT_ADAPTIVE_COOLDOWN"); cooldown != "" {
+		if value, err := time.ParseDuration(strings.TrimSpace(cooldown)); err == nil && value > 0 {
+			config.AdaptiveCooldown = value
+			log.Infof("Adaptive rate limit cooldown set to %s", value)
+		} else {
+			log.Warnf("Invalid MCP_RATE_LIMIT_ADAPTIVE_COOLDOWN value, using default %s", config.AdaptiveCooldown)
+		}
+	}
+
+	config.ToolLimits = loadToolRateLimitsFromEnv(os.Environ())
+
 	return config
 }
 
+const (
+	toolRateLimitPrefix   = "MCP_RATE_LIMIT_TOOL_"
+	toolConcurrencyPrefix = "MCP_MAX_CONCURRENCY_TOOL_"
+)
+
+// loadToolRateLimitsFromEnv parses the per-tool overrides, e.g. MCP_RATE_LIMIT_TOOL_CREATE_RUN=0.2:1
+// and MCP_MAX_CONCURRENCY_TOOL_CREATE_RUN=2 for the create_run tool
+func loadToolRateLimitsFromEnv(environ []string) map[string]ToolRateLimit {
+	limits := make(map[string]ToolRateLimit)
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		switch {
+		case strings.HasPrefix(key, toolRateLimitPrefix):
+			toolName := strings.ToLower(strings.TrimPrefix(key, toolRateLimitPrefix))
+			rps, burst := parseRateLimit(value)
+			if toolName == "" || rps <= 0 || burst <= 0 {
+				log.Warnf("Invalid %s format, expected rps:burst", key)
+				continue
+			}
+			limit := limits[toolName]
+			limit.Limit, limit.Burst = rate.Limit(rps), burst
+			limits[toolName] = limit
+			log.Infof("Rate limit for tool %s set to %f rps with burst %d", toolName, rps, burst)
+		case strings.HasPrefix(key, toolConcurrencyPrefix):
+			toolName := strings.ToLower(strings.TrimPrefix(key, toolConcurrencyPrefix))
+			maxConcurrency, err := strconv.Atoi(strings.TrimSpace(value))
+			if toolName == "" || err != nil || maxConcurrency <= 0 {
+				log.Warnf("Invalid %s value, expected a positive number", key)
+				continue
+			}
+			limit := limits[toolName]
+			limit.MaxConcurrency = maxConcurrency
+			limits[toolName] = limit
+			log.Infof("Maximum concurrency for tool %s set to %d", toolName, maxConcurrency)
+		}
+	}
+	return limits
+}
+
 // parseRateLimit parses "rps:burst" format
 func parseRateLimit(limit string) (float64, int) {
 	parts := strings.Split(limit, ":")
@@ -89,16 +165,68 @@ type RateLimitMiddleware struct {
 	sessionLimiters map[string]*rate.Limiter
 	mu              sync.RWMutex
 	logger          *log.Logger
+
+	adaptiveMu     sync.Mutex
+	throttledUntil time.Time
+
+	toolMu       sync.RWMutex
+	toolLimiters map[string]*rate.Limiter
+	toolSlots    map[string]chan struct{}
 }
 
 // NewRateLimitMiddleware creates a new rate limiting middleware
 func NewRateLimitMiddleware(config RateLimitConfig, logger *log.Logger) *RateLimitMiddleware {
-	return &RateLimitMiddleware{
+	m := &RateLimitMiddleware{
 		config:          config,
 		globalLimiter:   rate.NewLimiter(config.GlobalLimit, config.GlobalBurst),
 		sessionLimiters: make(map[string]*rate.Limiter),
 		logger:          logger,
 	}
+	m.toolLimiters, m.toolSlots = newToolLimiters(config.ToolLimits)
+	return m
+}
+
+// newToolLimiters creates the per-tool limiters and concurrency slots
+func newToolLimiters(toolLimits map[string]ToolRateLimit) (map[string]*rate.Limiter, map[string]chan struct{}) {
+	limiters := make(map[string]*rate.Limiter)
+	slots := make(map[string]chan struct{})
+	for toolName, limit := range toolLimits {
+		if limit.Limit > 0 && limit.Burst > 0 {
+			limiters[toolName] = rate.NewLimiter(limit.Limit, limit.Burst)
+		}
+		if limit.MaxConcurrency > 0 {
+			slots[toolName] = make(chan struct{}, limit.MaxConcurrency)
+		}
+	}
+	return limiters, slots
+}
+
+// UpdateConfig applies a new configuration to the running middleware. Existing session
+// limiters keep their tokens, and calls in flight keep the concurrency slot they hold.
+func (m *RateLimitMiddleware) UpdateConfig(config RateLimitConfig) {
+	now := time.Now()
+
+	m.adaptiveMu.Lock()
+	m.config.Adaptive, m.config.AdaptiveMinLimit, m.config.AdaptiveCooldown = config.Adaptive, config.AdaptiveMinLimit, config.AdaptiveCooldown
+	m.config.GlobalLimit, m.config.GlobalBurst = config.GlobalLimit, config.GlobalBurst
+	m.throttledUntil = time.Time{}
+	m.globalLimiter.SetLimitAt(now, config.GlobalLimit)
+	m.globalLimiter.SetBurstAt(now, config.GlobalBurst)
+	m.adaptiveMu.Unlock()
+
+	m.mu.Lock()
+	m.config.PerSessionLimit, m.config.PerSessionBurst = config.PerSessionLimit, config.PerSessionBurst
+	for _, limiter := range m.sessionLimiters {
+		limiter.SetLimitAt(now, config.PerSessionLimit)
+		limiter.SetBurstAt(now, config.PerSessionBurst)
+	}
+	m.mu.Unlock()
+
+	toolLimiters, toolSlots := newToolLimiters(config.ToolLimits)
+	m.toolMu.Lock()
+	m.config.ToolLimits = config.ToolLimits
+	m.toolLimiters, m.toolSlots = toolLimiters, toolSlots
+	m.toolMu.Unlock()
 }
 
 // getSessionLimiter gets or creates a rate limiter for a session
@@ -129,21 +257,65 @@ func (m *RateLimitMiddleware) Middleware() server.ToolHandlerMiddleware {
 	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
 		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			toolName := request.Params.Name
+			now := time.Now()
+			m.relaxAdaptiveLimit(now)
+
+			// The limits are checked from the most specific to the global one, and the tokens taken
+			// by the limiters that accepted the call are given back when a later one rejects it, so
+			// that a rejected call does not use up the budget of the session, the tool or the server
+			checks := make([]limitCheck, 0, 3)
+			if sessionID := getSessionIDFromContext(ctx); sessionID != "" {
+				checks = append(checks, limitCheck{"session", m.getSessionLimiter(sessionID), "Session rate limit exceeded", "rate limit exceeded: too many requests from this session"})
+			}
 
-			// Check global rate limit
-			if !m.globalLimiter.Allow() {
-				m.logger.Warnf("Global rate limit exceeded for tool: %s", toolName)
-				return nil, errors.New("rate limit exceeded: too many requests globally")
+			m.toolMu.RLock()
+			toolLimiter, hasToolLimiter := m.toolLimiters[toolName]
+			slots, hasSlots := m.toolSlots[toolName]
+			m.toolMu.RUnlock()
+			if hasToolLimiter {
+				checks = append(checks, limitCheck{"tool", toolLimiter, "Tool rate limit exceeded", fmt.Sprintf("rate limit exceeded: too many requests for tool %s", toolName)})
 			}
+			checks = append(checks, limitCheck{"global", m.globalLimiter, "Global rate limit exceeded", "rate limit exceeded: too many requests globally"})
 
-			// Check per-session rate limit if we can get session ID from context
-			if sessionID := getSessionIDFromContext(ctx); sessionID != "" {
-				sessionLimiter := m.getSessionLimiter(sessionID)
-				if !sessionLimiter.Allow() {
-					m.logger.Warnf("Session rate limit exceeded for session: %s, tool: %s", sessionID, toolName)
-					return nil, errors.New("rate limit exceeded: too many requests from this session")
+			reservations := make([]*rate.Reservation, 0, len(checks))
+			cancelReservations := func() {
+				for _, reservation := range reservations {
+					reservation.CancelAt(now)
+				}
+			}
+			var status RateLimitStatus
+			for i, check := range checks {
+				reservation := check.limiter.ReserveN(now, 1)
+				if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+					reservation.CancelAt(now)
+					cancelReservations()
+					status := limiterStatus(check.scope, check.limiter, now)
+					recordRateLimitStatus(ctx, status)
+					m.logger.WithFields(toolLogFields(ctx, toolName)).Warn(check.logMessage)
+					return rateLimitedResult(ctx, toolName, check.message, status), nil
+				}
+				reservations = append(reservations, reservation)
+
+				// Report the limiter closest to rejecting calls
+				if checkStatus := limiterStatus(check.scope, check.limiter, now); i == 0 || checkStatus.Remaining < status.Remaining {
+					status = checkStatus
+				}
+			}
+
+			// Check the number of in-flight calls of the tool
+			if hasSlots {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				default:
+					cancelReservations()
+					status := RateLimitStatus{Scope: "tool_concurrency", Limit: cap(slots), RetryAfter: 1}
+					recordRateLimitStatus(ctx, status)
+					m.logger.WithFields(toolLogFields(ctx, toolName)).Warnf("Maximum concurrency of %d reached", cap(slots))
+					return rateLimitedResult(ctx, toolName, fmt.Sprintf("rate limit exceeded: too many concurrent calls of tool %s", toolName), status), nil
 				}
 			}
+			recordRateLimitStatus(ctx, status)
 
 			m.logger.Debugf("Rate limit check passed for tool: %s", toolName)
 			return next(ctx, request)
@@ -151,6 +323,14 @@ func (m *RateLimitMiddleware) Middleware() server.ToolHandlerMiddleware {
 	}
 }
 
+// limitCheck is a limiter a tool call must pass, with the log and error messages used when it rejects the call
+type limitCheck struct {
+	scope      string
+	limiter    *rate.Limiter
+	logMessage string
+	message    string
+}
+
 // getSessionIDFromContext extracts session ID from context
 // This is a helper function that tries to get session ID from the context
 func getSessionIDFromContext(ctx context.Context) string {
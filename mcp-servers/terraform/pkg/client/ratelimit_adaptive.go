@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// adaptiveFactor is applied to the global limit every time an upstream throttles the server
+const adaptiveFactor = 0.5
+
+// UpstreamThrottleListener is notified when the registry or HCP Terraform/TFE answers with 429 or 503
+type UpstreamThrottleListener func(host string, statusCode int, retryAfter time.Duration)
+
+var (
+	upstreamThrottleMu        sync.RWMutex
+	upstreamThrottleListeners = make(map[uint64]UpstreamThrottleListener)
+	upstreamThrottleNextID    uint64
+)
+
+// OnUpstreamThrottled registers a listener for throttled upstream responses. The returned
+// function unregisters it, e.g. when the server the listener belongs to shuts down.
+func OnUpstreamThrottled(listener UpstreamThrottleListener) (unregister func()) {
+	upstreamThrottleMu.Lock()
+	defer upstreamThrottleMu.Unlock()
+	id := upstreamThrottleNextID
+	upstreamThrottleNextID++
+	upstreamThrottleListeners[id] = listener
+	return func() {
+		upstreamThrottleMu.Lock()
+		defer upstreamThrottleMu.Unlock()
+		delete(upstreamThrottleListeners, id)
+	}
+}
+
+func notifyUpstreamThrottled(host string, statusCode int, retryAfter time.Duration) {
+	upstreamThrottleMu.RLock()
+	defer upstreamThrottleMu.RUnlock()
+	for _, listener := range upstreamThrottleListeners {
+		listener(host, statusCode, retryAfter)
+	}
+}
+
+// upstreamFeedbackTransport reports throttled responses of the registry and HCP Terraform/TFE
+type upstreamFeedbackTransport struct {
+	base http.RoundTripper
+}
+
+func (t *upstreamFeedbackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		notifyUpstreamThrottled(req.URL.Host, resp.StatusCode, parseRetryAfter(resp.Header, time.Now()))
+	}
+	return resp, err
+}
+
+// parseRetryAfter reads the Retry-After header, in seconds or as an HTTP date, falling
+// back to the x-ratelimit-reset Unix timestamp sent by the Terraform registry
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if date, err := http.ParseTime(value); err == nil && date.After(now) {
+			return date.Sub(now)
+		}
+	}
+	if value := header.Get("x-ratelimit-reset"); value != "" {
+		if reset, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if resetAt := time.Unix(reset, 0); resetAt.After(now) {
+				return resetAt.Sub(now)
+			}
+		}
+	}
+	return 0
+}
+
+// UpstreamThrottled tightens the global limiter after an upstream throttled the server, so that
+// the server does not keep hammering the registry or HCP Terraform/TFE during an incident
+func (m *RateLimitMiddleware) UpstreamThrottled(host string, statusCode int, retryAfter time.Duration) {
+	// The adaptive settings and the global limit are changed by UpdateConfig under adaptiveMu
+	m.adaptiveMu.Lock()
+	defer m.adaptiveMu.Unlock()
+
+	if !m.config.Adaptive || m.config.GlobalLimit == rate.Inf {
+		return
+	}
+
+	now := time.Now()
+	current := m.globalLimiter.Limit()
+	limit := max(current*adaptiveFactor, min(m.config.AdaptiveMinLimit, m.config.GlobalLimit))
+	if limit < current {
+		m.setGlobalLimit(now, limit)
+		m.logger.Warnf("Upstream %s returned %d, tightening global rate limit to %.2f rps with burst %d", host, statusCode, limit, m.globalLimiter.Burst())
+	}
+
+	if until := now.Add(max(m.config.AdaptiveCooldown, retryAfter)); until.After(m.throttledUntil) {
+		m.throttledUntil = until
+	}
+}
+
+// relaxAdaptiveLimit doubles the global limit back towards the configured limit every cooldown without upstream throttling
+func (m *RateLimitMiddleware) relaxAdaptiveLimit(now time.Time) {
+	m.adaptiveMu.Lock()
+	defer m.adaptiveMu.Unlock()
+
+	current := m.globalLimiter.Limit()
+	if current >= m.config.GlobalLimit || now.Before(m.throttledUntil) {
+		return
+	}
+
+	limit := min(current/adaptiveFactor, m.config.GlobalLimit)
+	m.setGlobalLimit(now, limit)
+	m.throttledUntil = now.Add(m.config.AdaptiveCooldown)
+	m.logger.Infof("Relaxing global rate limit to %.2f rps with burst %d", limit, m.globalLimiter.Burst())
+}
+
+// setGlobalLimit changes the global limit and scales the burst with it
+func (m *RateLimitMiddleware) setGlobalLimit(now time.Time, limit rate.Limit) {
+	burst := max(1, int(float64(m.config.GlobalBurst)*float64(limit/m.config.GlobalLimit)))
+	m.globalLimiter.SetLimitAt(now, limit)
+	m.globalLimiter.SetBurstAt(now, burst)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Now()
+
+	header := http.Header{}
+	assert.Zero(t, parseRetryAfter(header, now))
+
+	header.Set("Retry-After", "30")
+	assert.Equal(t, 30*time.Second, parseRetryAfter(header, now))
+
+	header.Set("Retry-After", now.Add(2*time.Minute).UTC().Format(http.TimeFormat))
+	assert.InDelta(t, 2*time.Minute, parseRetryAfter(header, now), float64(time.Second))
+
+	header = http.Header{}
+	header.Set("x-ratelimit-reset", strconv.FormatInt(now.Add(10*time.Second).Unix(), 10))
+	assert.InDelta(t, 10*time.Second, parseRetryAfter(header, now), float64(time.Second))
+}
+
+func TestAdaptiveRateLimit(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	config := DefaultRateLimitConfig()
+	config.GlobalLimit = rate.Limit(8)
+	config.GlobalBurst = 16
+	middleware := NewRateLimitMiddleware(config, logger)
+
+	middleware.UpstreamThrottled("registry.terraform.io", http.StatusTooManyRequests, 0)
+	assert.Equal(t, rate.Limit(4), middleware.globalLimiter.Limit())
+	assert.Equal(t, 8, middleware.globalLimiter.Burst())
+
+	// The limit never goes below the adaptive minimum
+	for range 5 {
+		middleware.UpstreamThrottled("app.terraform.io", http.StatusServiceUnavailable, 0)
+	}
+	assert.Equal(t, rate.Limit(1), middleware.globalLimiter.Limit())
+	assert.Equal(t, 2, middleware.globalLimiter.Burst())
+
+	// Nothing is relaxed during the cooldown
+	middleware.relaxAdaptiveLimit(time.Now())
+	assert.Equal(t, rate.Limit(1), middleware.globalLimiter.Limit())
+
+	// After the cooldown the limit doubles back towards the configured limit
+	for _, expected := range []rate.Limit{2, 4, 8, 8} {
+		middleware.relaxAdaptiveLimit(time.Now().Add(time.Hour * time.Duration(expected)))
+		assert.Equal(t, expected, middleware.globalLimiter.Limit())
+	}
+	assert.Equal(t, 16, middleware.globalLimiter.Burst())
+
+	config.Adaptive = false
+	disabled := NewRateLimitMiddleware(config, logger)
+	disabled.UpstreamThrottled("registry.terraform.io", http.StatusTooManyRequests, 0)
+	assert.Equal(t, rate.Limit(8), disabled.globalLimiter.Limit())
+}
+
+func TestUpstreamFeedbackTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/throttled" {
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var notified []time.Duration
+	unregister := OnUpstreamThrottled(func(host string, statusCode int, retryAfter time.Duration) {
+		if host == server.Listener.Addr().String() {
+			notified = append(notified, retryAfter)
+		}
+	})
+
+	httpClient := &http.Client{Transport: &upstreamFeedbackTransport{base: http.DefaultTransport}}
+	for _, path := range []string{"/ok", "/throttled"} {
+		resp, err := httpClient.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, []time.Duration{5 * time.Second}, notified)
+
+	// Unregistered listeners are no longer notified
+	unregister()
+	resp, err := httpClient.Get(server.URL + "/throttled")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Len(t, notified, 1)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitStatus describes the limiter that applied to a tool call, so that clients can back off
+type RateLimitStatus struct {
+	Scope        string `json:"scope"`                         // global or session
+	Limit        int    `json:"limit"`                         // Burst capacity of the limiter
+	Remaining    int    `json:"remaining"`                     // Calls that can be made right away
+	ResetSeconds int    `json:"reset_seconds"`                 // Seconds until the full burst capacity is available again
+	RetryAfter   int    `json:"retry_after_seconds,omitempty"` // Seconds until the next call is allowed, set when the call was rejected
+}
+
+// RateLimitedResult is the structured content of a tool result rejected by the rate limiter
+type RateLimitedResult struct {
+	Error     string          `json:"error"`
+	Code      utils.ErrorCode `json:"code"`
+	RateLimit RateLimitStatus `json:"rate_limit"`
+}
+
+// limiterStatus returns the status of limiter at now
+func limiterStatus(scope string, limiter *rate.Limiter, now time.Time) RateLimitStatus {
+	tokens := limiter.TokensAt(now)
+	status := RateLimitStatus{
+		Scope:     scope,
+		Limit:     limiter.Burst(),
+		Remaining: max(0, int(math.Floor(tokens))),
+	}
+
+	limit := float64(limiter.Limit())
+	if limit <= 0 || limiter.Limit() == rate.Inf {
+		return status
+	}
+	status.ResetSeconds = ceilSeconds((float64(limiter.Burst()) - tokens) / limit)
+	if tokens < 1 {
+		status.RetryAfter = max(1, ceilSeconds((1-tokens)/limit))
+	}
+	return status
+}
+
+func ceilSeconds(seconds float64) int {
+	return max(0, int(math.Ceil(seconds)))
+}
+
+// rateLimitedResult returns the tool error for a rejected call, with the retry hint as structured
+// content, and tells the client about the rejection with a log notification
+func rateLimitedResult(ctx context.Context, toolName, message string, status RateLimitStatus) *mcp.CallToolResult {
+	NotifyClient(ctx, mcp.LoggingLevelWarning, ClientLoggerRateLimit, map[string]any{
+		"message":             message,
+		"tool":                toolName,
+		"scope":               status.Scope,
+		"retry_after_seconds": status.RetryAfter,
+	})
+	result := mcp.NewToolResultError(fmt.Sprintf("[%s] %s, retry after %d seconds", utils.ErrorCodeRateLimited, message, status.RetryAfter))
+	result.StructuredContent = RateLimitedResult{
+		Error:     "rate_limit_exceeded",
+		Code:      utils.ErrorCodeRateLimited,
+		RateLimit: status,
+	}
+	return result
+}
+
+type rateLimitStatusKey struct{}
+
+// rateLimitRecorder carries the status of the last tool call of an HTTP request to the response headers
+type rateLimitRecorder struct {
+	mu     sync.Mutex
+	status *RateLimitStatus
+}
+
+func recordRateLimitStatus(ctx context.Context, status RateLimitStatus) {
+	if recorder, ok := ctx.Value(rateLimitStatusKey{}).(*rateLimitRecorder); ok {
+		recorder.mu.Lock()
+		recorder.status = &status
+		recorder.mu.Unlock()
+	}
+}
+
+// NewRateLimitHeaderHandler adds the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+// headers to HTTP responses of tool calls, and Retry-After when the call was rejected
+func NewRateLimitHeaderHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &rateLimitRecorder{}
+		ctx := context.WithValue(r.Context(), rateLimitStatusKey{}, recorder)
+		handler.ServeHTTP(&rateLimitHeaderWriter{ResponseWriter: w, recorder: recorder}, r.WithContext(ctx))
+	})
+}
+
+type rateLimitHeaderWriter struct {
+	http.ResponseWriter
+	recorder    *rateLimitRecorder
+	wroteHeader bool
+}
+
+func (w *rateLimitHeaderWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.recorder.mu.Lock()
+		status := w.recorder.status
+		w.recorder.mu.Unlock()
+		if status != nil {
+			header := w.Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(status.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(status.Remaining))
+			header.Set("X-RateLimit-Reset", strconv.Itoa(status.ResetSeconds))
+			if status.RetryAfter > 0 {
+				header.Set("Retry-After", strconv.Itoa(status.RetryAfter))
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *rateLimitHeaderWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush keeps SSE streams of the StreamableHTTP transport working
+func (w *rateLimitHeaderWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *rateLimitHeaderWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
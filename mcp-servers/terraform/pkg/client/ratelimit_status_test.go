@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestLimiterStatus(t *testing.T) {
+	now := time.Now()
+	limiter := rate.NewLimiter(rate.Limit(2), 4)
+
+	status := limiterStatus("global", limiter, now)
+	assert.Equal(t, RateLimitStatus{Scope: "global", Limit: 4, Remaining: 4}, status)
+
+	require.True(t, limiter.AllowN(now, 4))
+	status = limiterStatus("global", limiter, now)
+	assert.Equal(t, 0, status.Remaining)
+	assert.Equal(t, 2, status.ResetSeconds)
+	assert.Equal(t, 1, status.RetryAfter)
+
+	status = limiterStatus("global", limiter, now.Add(1500*time.Millisecond))
+	assert.Equal(t, 3, status.Remaining)
+	assert.Equal(t, 1, status.ResetSeconds)
+	assert.Zero(t, status.RetryAfter)
+}
+
+func TestRateLimitHeaderHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	middleware := NewRateLimitMiddleware(RateLimitConfig{
+		GlobalLimit:     rate.Every(10 * time.Second),
+		GlobalBurst:     2,
+		PerSessionLimit: rate.Every(10 * time.Second),
+		PerSessionBurst: 2,
+	}, logger)
+	toolHandler := middleware.Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("success"), nil
+	})
+
+	// Stands in for the StreamableHTTP server, which calls the tool with the request context
+	handler := NewRateLimitHeaderHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := toolHandler(r.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "test_tool"}})
+		require.NoError(t, err)
+		if result.IsError {
+			w.Write([]byte("limited"))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	call := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+		return recorder
+	}
+
+	response := call()
+	assert.Equal(t, "ok", response.Body.String())
+	assert.Equal(t, "2", response.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", response.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "10", response.Header().Get("X-RateLimit-Reset"))
+	assert.Empty(t, response.Header().Get("Retry-After"))
+
+	call()
+	response = call()
+	assert.Equal(t, "limited", response.Body.String())
+	assert.Equal(t, "0", response.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "10", response.Header().Get("Retry-After"))
+
+	// Requests without a tool call get no rate limit headers
+	recorder := httptest.NewRecorder()
+	NewRateLimitHeaderHandler(http.NotFoundHandler()).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+	assert.Empty(t, recorder.Header().Get("X-RateLimit-Limit"))
+}
@@ -58,12 +58,20 @@ func TestRateLimitMiddleware(t *testing.T) {
 	}
 
 	// Second request should be rate limited
-	_, err = rateLimitedHandler(ctx, request)
-	if err == nil {
+	result, err = rateLimitedHandler(ctx, request)
+	if err != nil {
+		t.Fatalf("Rate limited request should return a tool error, got error: %v", err)
+	}
+	if !result.IsError {
 		t.Fatal("Second request should be rate limited")
 	}
-	if err.Error() != "rate limit exceeded: too many requests globally" {
-		t.Fatalf("Expected global rate limit error, got: %v", err)
+	text := result.Content[0].(mcp.TextContent).Text
+	if text != "[RATE_LIMITED] rate limit exceeded: too many requests globally, retry after 1 seconds" {
+		t.Fatalf("Expected global rate limit error, got: %v", text)
+	}
+	limited, ok := result.StructuredContent.(RateLimitedResult)
+	if !ok || limited.RateLimit.Scope != "global" || limited.RateLimit.RetryAfter != 1 || limited.RateLimit.Remaining != 0 {
+		t.Fatalf("Expected structured rate limit status, got: %+v", result.StructuredContent)
 	}
 }
 
@@ -127,3 +135,159 @@ func TestLoadRateLimitConfigFromEnvWithCustomValues(t *testing.T) {
 		t.Errorf("Expected session burst of 16, got %d", config.PerSessionBurst)
 	}
 }
+
+func TestLoadToolRateLimitsFromEnv(t *testing.T) {
+	limits := loadToolRateLimitsFromEnv([]string{
+		"MCP_RATE_LIMIT_TOOL_CREATE_RUN=0.5:2",
+		"MCP_MAX_CONCURRENCY_TOOL_CREATE_RUN=1",
+		"MCP_MAX_CONCURRENCY_TOOL_SEARCH_PROVIDERS=4",
+		"MCP_RATE_LIMIT_TOOL_LIST_RUNS=invalid",
+		"MCP_RATE_LIMIT_GLOBAL=10:20",
+	})
+
+	if len(limits) != 2 {
+		t.Fatalf("Expected limits for 2 tools, got %v", limits)
+	}
+	if limits["create_run"] != (ToolRateLimit{Limit: rate.Limit(0.5), Burst: 2, MaxConcurrency: 1}) {
+		t.Errorf("Unexpected create_run limit: %+v", limits["create_run"])
+	}
+	if limits["search_providers"] != (ToolRateLimit{MaxConcurrency: 4}) {
+		t.Errorf("Unexpected search_providers limit: %+v", limits["search_providers"])
+	}
+}
+
+func TestToolRateLimitMiddleware(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	config := DefaultRateLimitConfig()
+	config.ToolLimits = map[string]ToolRateLimit{
+		"create_run": {Limit: rate.Every(time.Minute), Burst: 1},
+		"slow_tool":  {MaxConcurrency: 1},
+	}
+	middleware := NewRateLimitMiddleware(config, logger)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := middleware.Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Name == "slow_tool" {
+			started <- struct{}{}
+			<-release
+		}
+		return mcp.NewToolResultText("success"), nil
+	})
+	call := func(toolName string) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: toolName}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if call("create_run").IsError {
+		t.Fatal("First create_run call should succeed")
+	}
+	if result := call("create_run"); !result.IsError || result.StructuredContent.(RateLimitedResult).RateLimit.Scope != "tool" {
+		t.Fatalf("Second create_run call should be rate limited by the tool limit, got %+v", result)
+	}
+	if call("search_providers").IsError {
+		t.Fatal("Tools without overrides should not be affected")
+	}
+
+	done := make(chan *mcp.CallToolResult)
+	go func() { done <- call("slow_tool") }()
+	<-started
+	if result := call("slow_tool"); !result.IsError || result.StructuredContent.(RateLimitedResult).RateLimit.Scope != "tool_concurrency" {
+		t.Fatalf("Concurrent slow_tool call should be rejected, got %+v", result)
+	}
+	close(release)
+	if (<-done).IsError {
+		t.Fatal("First slow_tool call should succeed")
+	}
+
+	// The slot is released once the call completes
+	go func() { done <- call("slow_tool") }()
+	<-started
+	if (<-done).IsError {
+		t.Fatal("slow_tool call after the first completed should succeed")
+	}
+}
+
+func TestRateLimitMiddlewareRejectedCallsKeepGlobalBudget(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	config := DefaultRateLimitConfig()
+	config.GlobalLimit, config.GlobalBurst = rate.Every(time.Minute), 2
+	config.ToolLimits = map[string]ToolRateLimit{
+		"create_run": {Limit: rate.Every(time.Minute), Burst: 1},
+	}
+	middleware := NewRateLimitMiddleware(config, logger)
+	handler := middleware.Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("success"), nil
+	})
+	call := func(toolName string) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: toolName}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if call("create_run").IsError {
+		t.Fatal("First create_run call should succeed")
+	}
+	for range 3 {
+		if result := call("create_run"); !result.IsError || result.StructuredContent.(RateLimitedResult).RateLimit.Scope != "tool" {
+			t.Fatalf("create_run calls should be rate limited by the tool limit, got %+v", result)
+		}
+	}
+	// The calls rejected by the tool limit did not take global tokens
+	if call("search_providers").IsError {
+		t.Fatal("search_providers should use the global token left")
+	}
+	if result := call("search_providers"); !result.IsError || result.StructuredContent.(RateLimitedResult).RateLimit.Scope != "global" {
+		t.Fatalf("The global limit should be reached, got %+v", result)
+	}
+}
+
+func TestRateLimitMiddlewareUpdateConfig(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	config := DefaultRateLimitConfig()
+	config.ToolLimits = map[string]ToolRateLimit{
+		"create_run": {Limit: rate.Every(time.Minute), Burst: 1},
+	}
+	middleware := NewRateLimitMiddleware(config, logger)
+	handler := middleware.Middleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("success"), nil
+	})
+	call := func(toolName string) *mcp.CallToolResult {
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: toolName}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return result
+	}
+
+	call("create_run")
+	if !call("create_run").IsError {
+		t.Fatal("Second create_run call should be rate limited before the update")
+	}
+
+	updated := DefaultRateLimitConfig()
+	updated.GlobalLimit, updated.GlobalBurst = 2, 4
+	updated.PerSessionLimit, updated.PerSessionBurst = 1, 2
+	middleware.UpdateConfig(updated)
+
+	if call("create_run").IsError {
+		t.Fatal("create_run should no longer have a tool limit after the update")
+	}
+	if middleware.globalLimiter.Limit() != 2 || middleware.globalLimiter.Burst() != 4 {
+		t.Fatalf("Expected the global limit to be updated, got %v:%d", middleware.globalLimiter.Limit(), middleware.globalLimiter.Burst())
+	}
+	if limiter := middleware.getSessionLimiter("session-1"); limiter.Limit() != 1 || limiter.Burst() != 2 {
+		t.Fatalf("Expected new session limiters to use the updated limit, got %v:%d", limiter.Limit(), limiter.Burst())
+	}
+}
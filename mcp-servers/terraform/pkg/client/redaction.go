@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NewRedactionMiddleware masks the secrets found in the text and the structured content of tool
+// results, e.g. a token echoed in an upstream error or a variable value in a workspace configuration
+func NewRedactionMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if result != nil {
+				redactResult(result)
+			}
+			return result, err
+		}
+	}
+}
+
+func redactResult(result *mcp.CallToolResult) {
+	for i, content := range result.Content {
+		switch content := content.(type) {
+		case mcp.TextContent:
+			content.Text = utils.RedactSecrets(content.Text)
+			result.Content[i] = content
+		case mcp.EmbeddedResource:
+			if text, ok := content.Resource.(mcp.TextResourceContents); ok {
+				text.Text = utils.RedactSecrets(text.Text)
+				content.Resource = text
+				result.Content[i] = content
+			}
+		}
+	}
+
+	// Structured content is only decoded again when it holds a secret, so that its type is kept otherwise
+	if result.StructuredContent == nil {
+		return
+	}
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return
+	}
+	redacted := utils.RedactSecrets(string(data))
+	if redacted == string(data) {
+		return
+	}
+	var structured any
+	if err := json.Unmarshal([]byte(redacted), &structured); err == nil {
+		result.StructuredContent = structured
+	}
+}
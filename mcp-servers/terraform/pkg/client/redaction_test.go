@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTFEToken = "abcdEFGH123456.atlasv1.aBcD_eFgH-1234567890abcdefghijKLMNOPqrstuvwxyz"
+
+func TestRedactionMiddleware(t *testing.T) {
+	call := func(handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)) *mcp.CallToolResult {
+		result, err := NewRedactionMiddleware()(NewErrorCodeMiddleware()(handler))(context.Background(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultStructured(map[string]any{"token": testTFEToken, "name": "app"}, "token "+testTFEToken), nil
+	})
+	assert.Equal(t, "token [REDACTED]", result.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, map[string]any{"token": "[REDACTED]", "name": "app"}, result.StructuredContent)
+
+	// Errors are masked once turned into results
+	result = call(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("request with Authorization: Bearer " + testTFEToken + " failed")
+	})
+	require.True(t, result.IsError)
+	assert.Equal(t, "[INTERNAL] request with Authorization: Bearer [REDACTED] failed", result.Content[0].(mcp.TextContent).Text)
+	assert.Equal(t, map[string]any{"error": "request with Authorization: Bearer [REDACTED] failed", "code": "INTERNAL"}, result.StructuredContent)
+
+	// Results without secrets are left as they are
+	result = call(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeNotFound, "workspace not found"), nil
+	})
+	assert.Equal(t, utils.ErrorCodeNotFound, result.StructuredContent.(utils.ToolErrorResult).Code)
+}
@@ -6,12 +6,10 @@ package client
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
@@ -19,23 +17,32 @@ import (
 	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
 	"github.com/hashicorp/terraform-mcp-server/version"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 )
 
 const DefaultPublicRegistryURL = "https://registry.terraform.io"
 
 // createHTTPClient initializes a retryable HTTP client
 func createHTTPClient(insecureSkipVerify bool, logger *log.Logger) *http.Client {
+	return createHTTPClientWithTLS(&tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+		RootCAs:            outboundRootCAs(logger),
+	}, logger)
+}
+
+// createHTTPClientWithTLS initializes a retryable HTTP client with the given TLS configuration
+func createHTTPClientWithTLS(tlsConfig *tls.Config, logger *log.Logger) *http.Client {
 	retryClient := retryablehttp.NewClient()
 	retryClient.Logger = logger
 
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify},
+		TLSClientConfig: tlsConfig,
 	}
-	transport.Proxy = http.ProxyFromEnvironment
+	transport.Proxy = outboundProxy(logger)
 
 	retryClient.HTTPClient = cleanhttp.DefaultClient()
 	retryClient.HTTPClient.Timeout = 10 * time.Second
-	retryClient.HTTPClient.Transport = transport
+	retryClient.HTTPClient.Transport = &upstreamFeedbackTransport{base: transport}
 	retryClient.RetryMax = 3
 
 	retryClient.Backoff = func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
@@ -62,67 +69,163 @@ func createHTTPClient(insecureSkipVerify bool, logger *log.Logger) *http.Client
 	return retryClient.StandardClient()
 }
 
-func SendRegistryCall(client *http.Client, method string, uri string, logger *log.Logger, callOptions ...string) ([]byte, error) {
+func SendRegistryCall(ctx context.Context, client *http.Client, method string, uri string, logger *log.Logger, callOptions ...string) ([]byte, error) {
 	ver := "v1"
 	if len(callOptions) > 0 {
 		ver = callOptions[0] // API version will be the first optional arg to this function
 	}
 
-	url, err := url.Parse(fmt.Sprintf("%s/%s/%s", DefaultPublicRegistryURL, ver, uri))
+	// The registry base URL can be overridden with the second optional arg, otherwise the configured mirror or the public registry is used
+	baseURL, discover := RegistryAddress(), true
+	if len(callOptions) > 1 {
+		baseURL, discover = strings.TrimRight(callOptions[1], "/"), false
+	}
+	discover = discover && baseURL != DefaultPublicRegistryURL
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	url, err := registryRequestURL(client, baseURL, ver, uri, discover, logger)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing terraform registry URL: %w", err)
+		return nil, err
 	}
 	logger.Debugf("Requested URL: %s", url)
 
-	req, err := http.NewRequest(method, url.String(), nil)
+	if method != http.MethodGet {
+		return doRegistryCall(ctx, client, method, url.String(), logger)
+	}
+	// Serve a response validated less than MCP_REGISTRY_CACHE_TTL ago without calling the registry
+	if body, ok := getRegistryCache().request(url.String(), time.Now()); ok {
+		logger.Debugf("Using the cached registry response for %s", url)
+		return body, nil
+	}
+	// The shared call outlives a cancelled caller, so that the other callers still get the response
+	sharedCtx := context.WithoutCancel(ctx)
+	call := registryCalls.DoChan(url.String(), func() (any, error) {
+		return doRegistryCall(sharedCtx, client, method, url.String(), logger)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-call:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		if result.Shared {
+			logger.Debugf("Shared in-flight registry response for %s", url)
+		}
+		return result.Val.([]byte), nil
+	}
+}
+
+// registryCalls collapses identical in-flight GET requests, so that sessions asking
+// for the same provider docs at the same time share a single upstream call
+var registryCalls singleflight.Group
+
+func doRegistryCall(ctx context.Context, client *http.Client, method string, url string, logger *log.Logger) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("User-Agent", fmt.Sprintf("terraform-mcp-server/%s", version.GetHumanVersion()))
 
+	// Revalidate a cached response instead of downloading it again
+	cache := getRegistryCache()
+	cached, isCached := registryCacheEntry{}, false
+	if method == http.MethodGet {
+		cached, isCached = cache.get(url)
+	}
+	if isCached {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && isCached {
+		cache.validated(url, time.Now())
+		logger.Debugf("Registry response for %s not modified, using the cached response", url)
+		return cached.body, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error: %s", "404 Not Found")
+		return nil, &RegistryStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
-	defer resp.Body.Close()
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
+	body, err := readRegistryBody(resp.Body, resp.ContentLength, url, getMaxRegistryResponseBytes())
 	if err != nil {
 		return nil, err
 	}
 	logger.Debugf("Response status: %s", resp.Status)
-	logger.Tracef("Response body: %s", string(body))
+	// Converting a doc page to a string copies it, only do it when it is logged
+	if logger.IsLevelEnabled(log.TraceLevel) {
+		logger.Tracef("Response body: %s", string(body))
+	}
+
+	if method == http.MethodGet {
+		cache.put(url, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), body)
+	}
 	return body, nil
 }
 
-func SendPaginatedRegistryCall(client *http.Client, uriPrefix string, logger *log.Logger) ([]ProviderDocData, error) {
+// maxRegistryPages bounds paginated registry calls, since some registry mirrors ignore the page parameter
+const maxRegistryPages = 100
+
+func SendPaginatedRegistryCall(ctx context.Context, client *http.Client, uriPrefix string, logger *log.Logger) ([]ProviderDocData, error) {
 	var results []ProviderDocData
 	page := 1
+	previousFirstID := ""
 
-	for {
+	for page <= maxRegistryPages {
+		// Stop between pages once the tool call is cancelled, instead of fetching the remaining pages
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		uri := fmt.Sprintf("%s&page[number]=%d", uriPrefix, page)
-		resp, err := SendRegistryCall(client, "GET", uri, logger, "v2")
+		resp, err := SendRegistryCall(ctx, client, "GET", uri, logger, "v2")
 		if err != nil {
 			return nil, utils.LogAndReturnError(logger, fmt.Sprintf("calling paginated registry API (page %d)", page), err)
 		}
 
 		var wrapper struct {
 			Data []ProviderDocData `json:"data"`
+			Meta struct {
+				Pagination *struct {
+					NextPage *int `json:"next-page"`
+				} `json:"pagination"`
+			} `json:"meta"`
 		}
-		if err := json.Unmarshal(resp, &wrapper); err != nil {
+		if err := DecodeRegistryResponse(resp, &wrapper, logger); err != nil {
 			return nil, utils.LogAndReturnError(logger, fmt.Sprintf("unmarshalling page %d", page), err)
 		}
 
 		if len(wrapper.Data) == 0 {
 			break
 		}
+		// A mirror that does not paginate returns the same page again, stop instead of looping
+		if wrapper.Data[0].ID == previousFirstID {
+			logger.Debugf("Registry returned page %d again, stopping pagination", page-1)
+			break
+		}
+		previousFirstID = wrapper.Data[0].ID
 
 		results = append(results, wrapper.Data...)
+
+		// Follow the pagination metadata when the registry provides it
+		if pagination := wrapper.Meta.Pagination; pagination != nil {
+			if pagination.NextPage == nil || *pagination.NextPage <= page {
+				break
+			}
+			page = *pagination.NextPage
+			continue
+		}
 		page++
 	}
 
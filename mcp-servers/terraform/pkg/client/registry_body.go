@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultMaxRegistryResponseBytes bounds a registry response, the largest provider doc pages are a few MB
+	defaultMaxRegistryResponseBytes = 16 << 20
+	// maxPooledRegistryBufferBytes is the largest read buffer returned to the pool, so that a few huge
+	// responses do not keep their buffers alive for the lifetime of the process
+	maxPooledRegistryBufferBytes = 4 << 20
+)
+
+// RegistryResponseTooLargeError is returned for a registry response larger than MCP_REGISTRY_MAX_RESPONSE_BYTES
+type RegistryResponseTooLargeError struct {
+	URL   string
+	Limit int64
+}
+
+func (e *RegistryResponseTooLargeError) Error() string {
+	return fmt.Sprintf("registry response for %s is larger than %d bytes", e.URL, e.Limit)
+}
+
+var (
+	maxRegistryResponseOnce  sync.Once
+	maxRegistryResponseBytes int64
+)
+
+// getMaxRegistryResponseBytes returns the size limit of a registry response set with MCP_REGISTRY_MAX_RESPONSE_BYTES
+func getMaxRegistryResponseBytes() int64 {
+	maxRegistryResponseOnce.Do(func() {
+		maxRegistryResponseBytes = defaultMaxRegistryResponseBytes
+		if value := os.Getenv("MCP_REGISTRY_MAX_RESPONSE_BYTES"); value != "" {
+			if parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil && parsed > 0 {
+				maxRegistryResponseBytes = parsed
+			} else {
+				log.Warnf("Invalid MCP_REGISTRY_MAX_RESPONSE_BYTES value, using default %d", maxRegistryResponseBytes)
+			}
+		}
+	})
+	return maxRegistryResponseBytes
+}
+
+// registryBuffers are the read buffers of registry responses. io.ReadAll grows a new slice for every
+// response, which leaves several times the size of a large doc page as garbage under concurrent calls.
+var registryBuffers = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// readRegistryBody streams a response body into a pooled buffer and returns a copy of exactly its size.
+// A body larger than limit fails without being read to the end.
+func readRegistryBody(body io.Reader, contentLength int64, url string, limit int64) ([]byte, error) {
+	if contentLength > limit {
+		return nil, &RegistryResponseTooLargeError{URL: url, Limit: limit}
+	}
+
+	buffer := registryBuffers.Get().(*bytes.Buffer)
+	defer func() {
+		if buffer.Cap() <= maxPooledRegistryBufferBytes {
+			buffer.Reset()
+			registryBuffers.Put(buffer)
+		}
+	}()
+	buffer.Reset()
+	if contentLength > 0 {
+		buffer.Grow(int(contentLength))
+	}
+
+	// One byte more than the limit tells a body of exactly the limit from a larger one
+	if _, err := buffer.ReadFrom(io.LimitReader(body, limit+1)); err != nil {
+		return nil, err
+	}
+	if int64(buffer.Len()) > limit {
+		return nil, &RegistryResponseTooLargeError{URL: url, Limit: limit}
+	}
+	// The response outlives the buffer, it is shared by concurrent callers and cached
+	return bytes.Clone(buffer.Bytes()), nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRegistryBody(t *testing.T) {
+	body, err := readRegistryBody(strings.NewReader("0123456789"), 10, "provider-docs/1", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(body))
+
+	// The body is a copy, reading the next response into the pooled buffer leaves it intact
+	_, err = readRegistryBody(strings.NewReader("abcdefghij"), -1, "provider-docs/2", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(body))
+
+	// A body over the limit fails, whether or not its length is announced
+	var tooLarge *RegistryResponseTooLargeError
+	_, err = readRegistryBody(strings.NewReader("0123456789x"), 11, "provider-docs/3", 10)
+	require.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, "registry response for provider-docs/3 is larger than 10 bytes", err.Error())
+	_, err = readRegistryBody(strings.NewReader("0123456789x"), -1, "provider-docs/3", 10)
+	assert.True(t, errors.As(err, &tooLarge))
+
+	body, err = readRegistryBody(strings.NewReader(""), 0, "provider-docs/4", 10)
+	require.NoError(t, err)
+	assert.Empty(t, body)
+}
+
+func TestSendRegistryCallResponseLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A chunked response does not announce its length
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(strings.Repeat("x", int(getMaxRegistryResponseBytes())+1)))
+	}))
+	defer server.Close()
+
+	_, err := SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "provider-docs/huge", logger, "v2", server.URL)
+	var tooLarge *RegistryResponseTooLargeError
+	assert.True(t, errors.As(err, &tooLarge))
+}
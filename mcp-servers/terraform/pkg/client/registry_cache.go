@@ -0,0 +1,359 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RegistryCacheTTL             = "MCP_REGISTRY_CACHE_TTL"
+	RegistryCacheRefreshTop      = "MCP_REGISTRY_CACHE_REFRESH_TOP"
+	RegistryCacheRefreshInterval = "MCP_REGISTRY_CACHE_REFRESH_INTERVAL"
+	RegistryCacheRefreshHours    = "MCP_REGISTRY_CACHE_REFRESH_HOURS"
+)
+
+// defaultRegistryCacheSize is the number of registry responses kept for revalidation
+const defaultRegistryCacheSize = 512
+
+// registryCacheEntry is a registry response with the validators needed to revalidate it
+type registryCacheEntry struct {
+	url          string
+	etag         string
+	lastModified string
+	body         []byte
+	// validatedAt is when the response was last downloaded or revalidated
+	validatedAt time.Time
+	// requests counts the requests of the response, halved at every background refresh
+	requests int
+}
+
+// registryResponseCache keeps the most recently used registry responses that carry an ETag
+// or Last-Modified header, so that they are revalidated with a conditional request instead
+// of downloading large provider doc payloads again
+type registryResponseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+	// ttl is how long a validated response is served without asking the registry, 0 to always revalidate
+	ttl time.Duration
+	// disk keeps the responses across restarts when MCP_REGISTRY_CACHE_DIR is set
+	disk *registryDiskCache
+}
+
+func newRegistryResponseCache(maxEntries int) *registryResponseCache {
+	return &registryResponseCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+var (
+	registryCacheOnce sync.Once
+	registryCache     *registryResponseCache
+)
+
+// getRegistryCache returns the shared cache sized with MCP_REGISTRY_CACHE_SIZE, or nil when it is disabled with 0
+func getRegistryCache() *registryResponseCache {
+	registryCacheOnce.Do(func() {
+		size := defaultRegistryCacheSize
+		if value := os.Getenv("MCP_REGISTRY_CACHE_SIZE"); value != "" {
+			if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && parsed >= 0 {
+				size = parsed
+			} else {
+				log.Warnf("Invalid MCP_REGISTRY_CACHE_SIZE value, using default %d", size)
+			}
+		}
+		if size > 0 {
+			registryCache = newRegistryResponseCache(size)
+			registryCache.ttl = registryCacheTTL()
+			registryCache.disk = loadRegistryDiskCache()
+		}
+	})
+	return registryCache
+}
+
+// registryCacheTTL returns MCP_REGISTRY_CACHE_TTL, 0 when it is not set or invalid
+func registryCacheTTL() time.Duration {
+	value := strings.TrimSpace(os.Getenv(RegistryCacheTTL))
+	if value == "" {
+		return 0
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		log.Warnf("Invalid %s value, revalidating every registry response", RegistryCacheTTL)
+		return 0
+	}
+	return ttl
+}
+
+// RegistryCacheSize returns the number of registry responses kept for revalidation, 0 when the cache is disabled
+func RegistryCacheSize() int {
+	if cache := getRegistryCache(); cache != nil {
+		return cache.maxEntries
+	}
+	return 0
+}
+
+// get returns the response of url, from memory or else from the disk cache. A response read from disk has not
+// been validated since the server started and is revalidated before it is used.
+func (c *registryResponseCache) get(url string) (registryCacheEntry, bool) {
+	if c == nil {
+		return registryCacheEntry{}, false
+	}
+	c.mu.Lock()
+	element, ok := c.entries[url]
+	if ok {
+		c.order.MoveToFront(element)
+		entry := *element.Value.(*registryCacheEntry)
+		c.mu.Unlock()
+		return entry, true
+	}
+	c.mu.Unlock()
+
+	entry, ok := c.disk.get(url)
+	if !ok {
+		return registryCacheEntry{}, false
+	}
+	c.store(url, entry.etag, entry.lastModified, entry.body, time.Time{})
+	return entry, true
+}
+
+func (c *registryResponseCache) put(url string, etag string, lastModified string, body []byte) {
+	if c == nil {
+		return
+	}
+	c.store(url, etag, lastModified, body, time.Now())
+	c.disk.put(url, etag, lastModified, body)
+}
+
+// store keeps the response in memory
+func (c *registryResponseCache) store(url string, etag string, lastModified string, body []byte, validatedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Responses without validators cannot be revalidated, drop a stale entry instead
+	if etag == "" && lastModified == "" {
+		if element, ok := c.entries[url]; ok {
+			c.order.Remove(element)
+			delete(c.entries, url)
+		}
+		return
+	}
+
+	entry := &registryCacheEntry{url: url, etag: etag, lastModified: lastModified, body: body, validatedAt: validatedAt, requests: 1}
+	if element, ok := c.entries[url]; ok {
+		entry.requests = element.Value.(*registryCacheEntry).requests
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[url] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*registryCacheEntry).url)
+	}
+}
+
+// request counts a request of url and returns the cached response when it was validated less than the TTL ago
+func (c *registryResponseCache) request(url string, now time.Time) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[url]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*registryCacheEntry)
+	entry.requests++
+	if c.ttl <= 0 || now.Sub(entry.validatedAt) >= c.ttl {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.body, true
+}
+
+// validated records that the cached response of url was revalidated
+func (c *registryResponseCache) validated(url string, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[url]; ok {
+		element.Value.(*registryCacheEntry).validatedAt = now
+	}
+}
+
+// hottest returns the URLs of the n most requested responses, then halves the request counts so that
+// the ranking follows the recent requests
+func (c *registryResponseCache) hottest(n int) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]*registryCacheEntry, 0, len(c.entries))
+	for _, element := range c.entries {
+		entry := element.Value.(*registryCacheEntry)
+		if entry.requests > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].requests != entries[j].requests {
+			return entries[i].requests > entries[j].requests
+		}
+		return entries[i].url < entries[j].url
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, entry.url)
+	}
+	for _, element := range c.entries {
+		element.Value.(*registryCacheEntry).requests /= 2
+	}
+	return urls
+}
+
+// registryCacheRefresh keeps the most requested registry responses fresh in the background
+type registryCacheRefresh struct {
+	top      int
+	interval time.Duration
+	hours    *GuardrailWindow
+}
+
+// loadRegistryCacheRefresh reads MCP_REGISTRY_CACHE_REFRESH_TOP, MCP_REGISTRY_CACHE_REFRESH_INTERVAL and
+// MCP_REGISTRY_CACHE_REFRESH_HOURS. The interval defaults to half of the TTL, so that the refreshed
+// responses never expire.
+func loadRegistryCacheRefresh(ttl time.Duration) (registryCacheRefresh, error) {
+	var refresh registryCacheRefresh
+	if value := strings.TrimSpace(os.Getenv(RegistryCacheRefreshTop)); value != "" {
+		top, err := strconv.Atoi(value)
+		if err != nil || top < 0 {
+			return refresh, fmt.Errorf("%s %q is not a number of responses", RegistryCacheRefreshTop, value)
+		}
+		refresh.top = top
+	}
+	if refresh.top == 0 {
+		return refresh, nil
+	}
+	if ttl <= 0 {
+		return refresh, fmt.Errorf("%s requires %s, responses are revalidated at every call without it", RegistryCacheRefreshTop, RegistryCacheTTL)
+	}
+
+	refresh.interval = ttl / 2
+	if value := strings.TrimSpace(os.Getenv(RegistryCacheRefreshInterval)); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return refresh, fmt.Errorf("%s %q is not a positive duration", RegistryCacheRefreshInterval, value)
+		}
+		refresh.interval = interval
+	}
+	if value := strings.TrimSpace(os.Getenv(RegistryCacheRefreshHours)); value != "" {
+		hours, err := parseRefreshHours(value)
+		if err != nil {
+			return refresh, fmt.Errorf("invalid %s %q: %w", RegistryCacheRefreshHours, value, err)
+		}
+		refresh.hours = hours
+	}
+	return refresh, nil
+}
+
+// parseRefreshHours parses a daily time range with optional days and timezone, e.g. "mon,tue,wed,thu,fri 08:00-18:00 Europe/Paris"
+func parseRefreshHours(value string) (*GuardrailWindow, error) {
+	window := &GuardrailWindow{}
+	for _, field := range strings.Fields(value) {
+		start, end, isRange := strings.Cut(field, "-")
+		switch {
+		case isRange && strings.Contains(start, ":"):
+			window.Start, window.End = start, end
+		case window.Start == "" && window.Days == nil:
+			window.Days = strings.Split(field, ",")
+		default:
+			window.Timezone = field
+		}
+	}
+	if window.Start == "" {
+		return nil, fmt.Errorf("expected HH:MM-HH:MM")
+	}
+	if err := window.parse(); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// StartRegistryCacheRefresh revalidates the most requested registry responses in the background until ctx is
+// done, so that they are still fresh when they are requested again. It does nothing unless
+// MCP_REGISTRY_CACHE_REFRESH_TOP is set, and fails on an invalid configuration.
+func StartRegistryCacheRefresh(ctx context.Context, logger *log.Logger) error {
+	cache := getRegistryCache()
+	if cache == nil {
+		return nil
+	}
+	refresh, err := loadRegistryCacheRefresh(cache.ttl)
+	if err != nil {
+		return err
+	}
+	if refresh.top == 0 {
+		return nil
+	}
+	if refresh.interval >= cache.ttl {
+		logger.Warnf("%s is not shorter than %s, the refreshed responses expire between refreshes", RegistryCacheRefreshInterval, RegistryCacheTTL)
+	}
+	logger.Infof("Refreshing the %d most requested registry responses every %s", refresh.top, refresh.interval)
+
+	go func() {
+		ticker := time.NewTicker(refresh.interval)
+		defer ticker.Stop()
+		httpClient := createHTTPClient(false, logger)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if refresh.hours == nil || refresh.hours.contains(now) {
+					cache.refresh(ctx, httpClient, refresh.top, logger)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// refresh revalidates the top most requested responses one after the other
+func (c *registryResponseCache) refresh(ctx context.Context, httpClient *http.Client, top int, logger *log.Logger) {
+	urls := c.hottest(top)
+	failed := 0
+	for _, url := range urls {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := doRegistryCall(ctx, httpClient, http.MethodGet, url, logger); err != nil {
+			failed++
+			logger.WithError(err).Debugf("Failed to refresh the registry response for %s", url)
+		}
+	}
+	logger.Debugf("Refreshed %d registry responses, %d failed", len(urls)-failed, failed)
+}
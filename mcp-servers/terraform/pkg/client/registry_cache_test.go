@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryResponseCache(t *testing.T) {
+	cache := newRegistryResponseCache(2)
+
+	cache.put("a", `"a1"`, "", []byte("a"))
+	cache.put("b", "", "Mon, 02 Jan 2006 15:04:05 GMT", []byte("b"))
+	_, ok := cache.get("a")
+	require.True(t, ok)
+
+	// The least recently used entry is evicted
+	cache.put("c", `"c1"`, "", []byte("c"))
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+	entry, ok := cache.get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("a"), entry.body)
+
+	// Responses without validators replace nothing and drop the stale entry
+	cache.put("a", "", "", []byte("a2"))
+	_, ok = cache.get("a")
+	assert.False(t, ok)
+
+	var disabled *registryResponseCache
+	disabled.put("a", `"a1"`, "", []byte("a"))
+	_, ok = disabled.get("a")
+	assert.False(t, ok)
+}
+
+func TestSendRegistryCallRevalidatesWithETag(t *testing.T) {
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"data": "provider docs"}`)
+	}))
+	defer server.Close()
+
+	for range 3 {
+		body, err := SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "provider-docs/42", logger, "v2", server.URL)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"data": "provider docs"}`, string(body))
+	}
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 2, notModified)
+}
+
+func TestRegistryResponseCacheTTL(t *testing.T) {
+	cache := newRegistryResponseCache(10)
+	now := time.Now()
+
+	cache.put("a", `"a1"`, "", []byte("a"))
+	_, ok := cache.request("a", now)
+	assert.False(t, ok, "without a TTL every response is revalidated")
+
+	cache.ttl = time.Minute
+	body, ok := cache.request("a", now)
+	require.True(t, ok)
+	assert.Equal(t, []byte("a"), body)
+	_, ok = cache.request("a", now.Add(2*time.Minute))
+	assert.False(t, ok)
+
+	cache.validated("a", now.Add(2*time.Minute))
+	_, ok = cache.request("a", now.Add(2*time.Minute+time.Second))
+	assert.True(t, ok)
+	_, ok = cache.request("missing", now)
+	assert.False(t, ok)
+}
+
+func TestRegistryResponseCacheHottest(t *testing.T) {
+	cache := newRegistryResponseCache(10)
+	for _, url := range []string{"a", "b", "c"} {
+		cache.put(url, `"1"`, "", []byte(url))
+	}
+	for i := 0; i < 4; i++ {
+		cache.request("b", time.Now())
+	}
+	cache.request("c", time.Now())
+
+	assert.Equal(t, []string{"b", "c"}, cache.hottest(2))
+	// The request counts are halved, the responses no longer requested fall out of the ranking
+	assert.Equal(t, []string{"b", "c"}, cache.hottest(5))
+	assert.Equal(t, []string{"b"}, cache.hottest(5))
+
+	// Replacing a response keeps its request count
+	cache.request("b", time.Now())
+	cache.put("b", `"2"`, "", []byte("b"))
+	assert.Equal(t, []string{"b"}, cache.hottest(5))
+}
+
+func TestLoadRegistryCacheRefresh(t *testing.T) {
+	refresh, err := loadRegistryCacheRefresh(0)
+	require.NoError(t, err)
+	assert.Zero(t, refresh.top)
+
+	t.Setenv(RegistryCacheRefreshTop, "20")
+	_, err = loadRegistryCacheRefresh(0)
+	assert.Error(t, err, "refreshing requires a TTL")
+
+	refresh, err = loadRegistryCacheRefresh(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 20, refresh.top)
+	assert.Equal(t, 30*time.Minute, refresh.interval)
+	assert.Nil(t, refresh.hours)
+
+	t.Setenv(RegistryCacheRefreshInterval, "10m")
+	t.Setenv(RegistryCacheRefreshHours, "mon,tue,wed,thu,fri 08:00-18:00 UTC")
+	refresh, err = loadRegistryCacheRefresh(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, refresh.interval)
+	require.NotNil(t, refresh.hours)
+	assert.True(t, refresh.hours.contains(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)))
+	assert.False(t, refresh.hours.contains(time.Date(2024, 5, 1, 19, 0, 0, 0, time.UTC)))
+	assert.False(t, refresh.hours.contains(time.Date(2024, 5, 4, 9, 0, 0, 0, time.UTC)))
+
+	t.Setenv(RegistryCacheRefreshHours, "08:00-18:00")
+	refresh, err = loadRegistryCacheRefresh(time.Hour)
+	require.NoError(t, err)
+	assert.True(t, refresh.hours.contains(time.Date(2024, 5, 4, 9, 0, 0, 0, time.UTC)))
+
+	for name, env := range map[string]map[string]string{
+		"invalid top":      {RegistryCacheRefreshTop: "many"},
+		"invalid interval": {RegistryCacheRefreshInterval: "often"},
+		"invalid hours":    {RegistryCacheRefreshHours: "mon,tue"},
+		"invalid timezone": {RegistryCacheRefreshHours: "08:00-18:00 Mars/Olympus"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+			_, err := loadRegistryCacheRefresh(time.Hour)
+			assert.Error(t, err)
+		})
+	}
+}
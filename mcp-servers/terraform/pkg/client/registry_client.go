@@ -5,7 +5,6 @@ package client
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"sync"
 
@@ -25,6 +24,12 @@ func NewHttpClient(sessionId string, terraformSkipTLSVerify bool, logger *log.Lo
 	return client
 }
 
+// NewRegistryHTTPClient creates an HTTP client for the registry with the proxy and certificate authorities
+// of the session clients, e.g. for the readiness probe of the registry
+func NewRegistryHTTPClient(logger *log.Logger) *http.Client {
+	return createHTTPClient(false, logger)
+}
+
 // GetHttpClient retrieves the HTTP client for the given session
 func GetHttpClient(sessionId string) *http.Client {
 	if value, ok := activeHttpClients.Load(sessionId); ok {
@@ -38,21 +43,10 @@ func DeleteHttpClient(sessionId string) {
 	activeHttpClients.Delete(sessionId)
 }
 
-// GetHttpClientFromContext extracts HTTP client from the MCP context
+// GetHttpClientFromContext returns the registry HTTP client of the tool call of ctx, from the provider
+// set with ContextWithRegistryClientProvider or else from its MCP session
 func GetHttpClientFromContext(ctx context.Context, logger *log.Logger) (*http.Client, error) {
-	session := server.ClientSessionFromContext(ctx)
-	if session == nil {
-		return nil, fmt.Errorf("no active session")
-	}
-
-	// Try to get existing client
-	client := GetHttpClient(session.SessionID())
-	if client != nil {
-		return client, nil
-	}
-
-	logger.Warnf("HTTP client not found, creating a new one")
-	return CreateHttpClientForSession(ctx, session, logger), nil
+	return registryClientProviderFromContext(ctx).RegistryClient(ctx, logger)
 }
 
 // CreateHttpClientForSession creates only an HTTP client for the session
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	TerraformRegistryAddress = "TERRAFORM_REGISTRY_ADDRESS"
+
+	// registryDiscoveryPath is the service discovery document of the Terraform remote service discovery protocol
+	registryDiscoveryPath = "/.well-known/terraform.json"
+	// registryDiscoveryTTL is how long a discovery document, or the failure to fetch it, is reused
+	registryDiscoveryTTL = 10 * time.Minute
+)
+
+// registryServices maps the uri prefixes passed to SendRegistryCall to the v1 services of the discovery document
+var registryServices = map[string]string{
+	"modules":   "modules.v1",
+	"providers": "providers.v1",
+}
+
+type registryDiscovery struct {
+	services  map[string]string
+	fetchedAt time.Time
+}
+
+var registryDiscoveryCache sync.Map
+
+// RegistryAddress returns the base URL of the Terraform registry, either an internal
+// registry mirror configured with TERRAFORM_REGISTRY_ADDRESS or the public registry
+func RegistryAddress() string {
+	address := strings.TrimRight(strings.TrimSpace(os.Getenv(TerraformRegistryAddress)), "/")
+	if address == "" {
+		return DefaultPublicRegistryURL
+	}
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	return address
+}
+
+// registryRequestURL builds the URL of a registry API call. The public registry and explicit base
+// URLs follow the /<version>/<uri> layout, while the v1 module and provider services of a mirror
+// are located with its discovery document since mirrors often serve them under other paths.
+func registryRequestURL(client *http.Client, baseURL string, ver string, uri string, discover bool, logger *log.Logger) (*url.URL, error) {
+	defaultURL, err := url.Parse(fmt.Sprintf("%s/%s/%s", baseURL, ver, uri))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing terraform registry URL: %w", err)
+	}
+	if !discover || ver != "v1" {
+		return defaultURL, nil
+	}
+
+	prefix, rest, _ := strings.Cut(uri, "/")
+	service, ok := registryServices[prefix]
+	if !ok {
+		return defaultURL, nil
+	}
+	servicePath, ok := discoverRegistryServices(client, baseURL, logger)[service]
+	if !ok {
+		return defaultURL, nil
+	}
+
+	// Service URLs can be absolute or relative to the discovery document
+	base, err := url.Parse(baseURL + registryDiscoveryPath)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing terraform registry URL: %w", err)
+	}
+	serviceURL, err := base.Parse(servicePath)
+	if err != nil {
+		logger.Warnf("Invalid %s service URL %q in the registry discovery document, using the default path", service, servicePath)
+		return defaultURL, nil
+	}
+	requestURL, err := url.Parse(strings.TrimRight(serviceURL.String(), "/") + "/" + rest)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing terraform registry URL: %w", err)
+	}
+	return requestURL, nil
+}
+
+// discoverRegistryServices returns the services advertised in the discovery document of the registry.
+// When the document is unavailable an empty map is returned and the default paths are used.
+func discoverRegistryServices(client *http.Client, baseURL string, logger *log.Logger) map[string]string {
+	if cached, ok := registryDiscoveryCache.Load(baseURL); ok {
+		discovery := cached.(registryDiscovery)
+		if time.Since(discovery.fetchedAt) < registryDiscoveryTTL {
+			return discovery.services
+		}
+	}
+
+	services, err := fetchRegistryDiscovery(client, baseURL)
+	if err != nil {
+		logger.Warnf("Registry service discovery failed for %s, using the default API paths: %v", baseURL, err)
+		services = map[string]string{}
+	}
+	registryDiscoveryCache.Store(baseURL, registryDiscovery{services: services, fetchedAt: time.Now()})
+	return services
+}
+
+func fetchRegistryDiscovery(client *http.Client, baseURL string) (map[string]string, error) {
+	resp, err := client.Get(baseURL + registryDiscoveryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery document returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	// The document can advertise other services, e.g. login.v1 as an object, which are ignored
+	var document map[string]any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("parsing discovery document: %w", err)
+	}
+	services := make(map[string]string)
+	for name, value := range document {
+		if path, ok := value.(string); ok {
+			services[name] = path
+		}
+	}
+	return services, nil
+}
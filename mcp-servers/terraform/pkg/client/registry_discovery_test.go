@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryAddress(t *testing.T) {
+	t.Setenv(TerraformRegistryAddress, "")
+	assert.Equal(t, DefaultPublicRegistryURL, RegistryAddress())
+
+	t.Setenv(TerraformRegistryAddress, "https://artifactory.internal/")
+	assert.Equal(t, "https://artifactory.internal", RegistryAddress())
+
+	t.Setenv(TerraformRegistryAddress, "registry.internal:8443")
+	assert.Equal(t, "https://registry.internal:8443", RegistryAddress())
+}
+
+func TestSendRegistryCallMirrorDiscovery(t *testing.T) {
+	var discoveryCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/terraform.json":
+			discoveryCalls.Add(1)
+			fmt.Fprint(w, `{"modules.v1": "/api/terraform/modules/v1/", "providers.v1": "/api/terraform/providers/v1/", "login.v1": {"client": "terraform-cli"}}`)
+		case "/api/terraform/providers/v1/hashicorp/aws":
+			fmt.Fprint(w, `{"version": "5.0.0"}`)
+		case "/v2/providers/hashicorp/aws":
+			fmt.Fprint(w, `{"data": {}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv(TerraformRegistryAddress, server.URL)
+
+	body, err := SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "providers/hashicorp/aws", logger, "v1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": "5.0.0"}`, string(body))
+
+	// v2 endpoints are not part of the discovery protocol and keep the default layout
+	_, err = SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "providers/hashicorp/aws", logger, "v2")
+	require.NoError(t, err)
+
+	// The discovery document is cached
+	_, err = SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "providers/hashicorp/aws", logger, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), discoveryCalls.Load())
+}
+
+func TestSendRegistryCallMirrorWithoutDiscovery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/modules/hashicorp/consul/aws" {
+			fmt.Fprint(w, `{"id": "hashicorp/consul/aws"}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	t.Setenv(TerraformRegistryAddress, server.URL)
+
+	body, err := SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "modules/hashicorp/consul/aws", logger)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "hashicorp/consul/aws"}`, string(body))
+}
+
+func TestSendPaginatedRegistryCall(t *testing.T) {
+	tests := []struct {
+		name          string
+		page          func(page string) string
+		expectedIDs   []string
+		expectedCalls int32
+	}{
+		{
+			name: "EmptyPageEndsPagination",
+			page: func(page string) string {
+				if page == "3" {
+					return `{"data": []}`
+				}
+				return fmt.Sprintf(`{"data": [{"id": "doc-%s"}]}`, page)
+			},
+			expectedIDs:   []string{"doc-1", "doc-2"},
+			expectedCalls: 3,
+		},
+		{
+			name: "PaginationMetadata",
+			page: func(page string) string {
+				if page == "2" {
+					return `{"data": [{"id": "doc-2"}], "meta": {"pagination": {"current-page": 2, "next-page": null}}}`
+				}
+				return `{"data": [{"id": "doc-1"}], "meta": {"pagination": {"current-page": 1, "next-page": 2}}}`
+			},
+			expectedIDs:   []string{"doc-1", "doc-2"},
+			expectedCalls: 2,
+		},
+		{
+			name: "MirrorIgnoringPageParameter",
+			page: func(page string) string {
+				return `{"data": [{"id": "doc-1"}, {"id": "doc-2"}]}`
+			},
+			expectedIDs:   []string{"doc-1", "doc-2"},
+			expectedCalls: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				fmt.Fprint(w, tc.page(r.URL.Query().Get("page[number]")))
+			}))
+			defer server.Close()
+			t.Setenv(TerraformRegistryAddress, server.URL)
+
+			docs, err := SendPaginatedRegistryCall(t.Context(), server.Client(), "provider-docs?filter[provider-version]=1", logger)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, doc := range docs {
+				ids = append(ids, doc.ID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestSendPaginatedRegistryCallCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	// The registry never runs out of pages, the call is cancelled while the second one is fetched
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page[number]")
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+		fmt.Fprintf(w, `{"data": [{"id": "doc-%s"}]}`, page)
+	}))
+	defer server.Close()
+	t.Setenv(TerraformRegistryAddress, server.URL)
+
+	docs, err := SendPaginatedRegistryCall(ctx, server.Client(), "provider-docs?filter[provider-version]=cancelled", logger)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, docs)
+	assert.Equal(t, int32(2), calls.Load())
+}
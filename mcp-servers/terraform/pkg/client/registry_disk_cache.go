@@ -0,0 +1,330 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RegistryCacheDir      = "MCP_REGISTRY_CACHE_DIR"
+	RegistryCacheDirMaxMB = "MCP_REGISTRY_CACHE_DIR_MAX_MB"
+
+	// defaultRegistryDiskCacheMB is the size of the responses kept on disk
+	defaultRegistryDiskCacheMB = 1024
+	registryDiskCacheIndex     = "index.json"
+	registryDiskCacheObjects   = "objects"
+)
+
+// registryDiskEntry is a registry response in the index of the disk cache, its body is stored in the object
+// named after its SHA-256
+type registryDiskEntry struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	SHA256       string    `json:"sha256"`
+	Size         int64     `json:"size"`
+	UsedAt       time.Time `json:"used_at"`
+}
+
+// registryDiskCache keeps registry responses across restarts. Bodies are content addressed, so that identical
+// responses, e.g. the docs of unchanged provider versions, are stored once. The least recently used responses are
+// evicted above maxBytes. The index is saved registryDiskCacheSaveDelay after it changed, so that a burst of
+// responses writes it once, and when the server shuts down.
+type registryDiskCache struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	entries  map[string]registryDiskEntry
+	// refs counts the entries referring to each object, and total is the size of the objects
+	refs  map[string]int
+	total int64
+	// dirty is set when the index changed since it was saved, saveTimer is the pending save
+	dirty     bool
+	saveTimer *time.Timer
+
+	// saveMu orders the writes of the index, so that an older index never replaces a newer one
+	saveMu sync.Mutex
+}
+
+// registryDiskCacheSaveDelay is how long the index changes are batched before it is saved
+const registryDiskCacheSaveDelay = 5 * time.Second
+
+// loadRegistryDiskCache opens the disk cache in MCP_REGISTRY_CACHE_DIR, or returns nil when it is not set or
+// the directory cannot be used
+func loadRegistryDiskCache() *registryDiskCache {
+	dir := strings.TrimSpace(os.Getenv(RegistryCacheDir))
+	if dir == "" {
+		return nil
+	}
+	maxMB := defaultRegistryDiskCacheMB
+	if value := strings.TrimSpace(os.Getenv(RegistryCacheDirMaxMB)); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			maxMB = parsed
+		} else {
+			log.Warnf("Invalid %s value, using default %d", RegistryCacheDirMaxMB, maxMB)
+		}
+	}
+	cache, err := newRegistryDiskCache(dir, int64(maxMB)<<20)
+	if err != nil {
+		log.WithError(err).Warnf("Registry responses are not kept on disk")
+		return nil
+	}
+	log.Infof("Keeping up to %d MB of registry responses in %s", maxMB, dir)
+	return cache
+}
+
+// newRegistryDiskCache opens the cache in dir, dropping the index entries whose object is missing and the
+// objects no entry refers to
+func newRegistryDiskCache(dir string, maxBytes int64) (*registryDiskCache, error) {
+	if err := os.MkdirAll(filepath.Join(dir, registryDiskCacheObjects), 0o700); err != nil {
+		return nil, fmt.Errorf("creating the registry cache directory: %w", err)
+	}
+	cache := &registryDiskCache{dir: dir, maxBytes: maxBytes, entries: map[string]registryDiskEntry{}, refs: map[string]int{}}
+
+	data, err := os.ReadFile(filepath.Join(dir, registryDiskCacheIndex))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading the registry cache index: %w", err)
+	}
+	index := map[string]registryDiskEntry{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &index); err != nil {
+			log.WithError(err).Warnf("Ignoring the corrupted registry cache index in %s", dir)
+			index = map[string]registryDiskEntry{}
+		}
+	}
+
+	for url, entry := range index {
+		if _, err := os.Stat(cache.objectPath(entry.SHA256)); err == nil {
+			cache.add(url, entry)
+		}
+	}
+	objects, err := os.ReadDir(filepath.Join(dir, registryDiskCacheObjects))
+	if err != nil {
+		return nil, fmt.Errorf("reading the registry cache directory: %w", err)
+	}
+	for _, object := range objects {
+		if cache.refs[object.Name()] == 0 {
+			os.Remove(filepath.Join(dir, registryDiskCacheObjects, object.Name()))
+		}
+	}
+	cache.removeObjects(cache.evict())
+	cache.dirty = true
+	return cache, cache.save()
+}
+
+func (c *registryDiskCache) objectPath(sum string) string {
+	return filepath.Join(c.dir, registryDiskCacheObjects, sum)
+}
+
+// get returns the response of url, dropping it when its object is missing or does not match its hash. The object
+// is read and checked without holding the lock, objects are never changed once written.
+func (c *registryDiskCache) get(url string) (registryCacheEntry, bool) {
+	if c == nil {
+		return registryCacheEntry{}, false
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[url]
+	c.mu.Unlock()
+	if !ok {
+		return registryCacheEntry{}, false
+	}
+
+	body, err := os.ReadFile(c.objectPath(entry.SHA256))
+	valid := err == nil && contentHash(body) == entry.SHA256
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// The entry may have been replaced or evicted while its object was read
+	current, ok := c.entries[url]
+	if !ok || current.SHA256 != entry.SHA256 {
+		return registryCacheEntry{}, false
+	}
+	if !valid {
+		log.Warnf("Dropping the corrupted registry cache entry of %s", url)
+		// The object is removed even when other entries refer to it, their reads drop them as well
+		c.remove(url)
+		os.Remove(c.objectPath(entry.SHA256))
+		c.scheduleSave()
+		return registryCacheEntry{}, false
+	}
+	current.UsedAt = time.Now()
+	c.entries[url] = current
+	c.scheduleSave()
+	return registryCacheEntry{url: url, etag: current.ETag, lastModified: current.LastModified, body: body}, true
+}
+
+// put stores the response of url, or drops it when it cannot be revalidated
+func (c *registryDiskCache) put(url string, etag string, lastModified string, body []byte) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if (etag == "" && lastModified == "") || int64(len(body)) > c.maxBytes {
+		if _, ok := c.entries[url]; ok {
+			c.removeObjects([]string{c.remove(url)})
+			c.scheduleSave()
+		}
+		return
+	}
+
+	sum := contentHash(body)
+	if c.refs[sum] == 0 {
+		if err := writeFileAtomic(c.objectPath(sum), body); err != nil {
+			log.WithError(err).Warnf("Failed to store the registry response of %s", url)
+			return
+		}
+	}
+	orphans := []string{c.remove(url)}
+	c.add(url, registryDiskEntry{ETag: etag, LastModified: lastModified, SHA256: sum, Size: int64(len(body)), UsedAt: time.Now()})
+	c.removeObjects(append(orphans, c.evict()...))
+	c.scheduleSave()
+}
+
+// add indexes the entry of url, which must not be in the index
+func (c *registryDiskCache) add(url string, entry registryDiskEntry) {
+	c.entries[url] = entry
+	if c.refs[entry.SHA256] == 0 {
+		c.total += entry.Size
+	}
+	c.refs[entry.SHA256]++
+}
+
+// remove drops the entry of url from the index, and returns its object when no other entry refers to it
+func (c *registryDiskCache) remove(url string) string {
+	entry, ok := c.entries[url]
+	if !ok {
+		return ""
+	}
+	delete(c.entries, url)
+	c.refs[entry.SHA256]--
+	if c.refs[entry.SHA256] > 0 {
+		return ""
+	}
+	delete(c.refs, entry.SHA256)
+	c.total -= entry.Size
+	return entry.SHA256
+}
+
+// removeObjects deletes the objects no entry refers to anymore. An object may be referred to again since it was
+// orphaned, e.g. when a response is replaced by an identical body.
+func (c *registryDiskCache) removeObjects(sums []string) {
+	for _, sum := range sums {
+		if sum != "" && c.refs[sum] == 0 {
+			os.Remove(c.objectPath(sum))
+		}
+	}
+}
+
+// evict drops the least recently used entries until the objects fit in maxBytes, and returns the objects
+// no entry refers to anymore
+func (c *registryDiskCache) evict() []string {
+	if c.total <= c.maxBytes {
+		return nil
+	}
+	urls := make([]string, 0, len(c.entries))
+	for url := range c.entries {
+		urls = append(urls, url)
+	}
+	sort.Slice(urls, func(a, b int) bool { return c.entries[urls[a]].UsedAt.Before(c.entries[urls[b]].UsedAt) })
+	var orphans []string
+	for _, url := range urls {
+		if c.total <= c.maxBytes {
+			break
+		}
+		if sum := c.remove(url); sum != "" {
+			orphans = append(orphans, sum)
+		}
+	}
+	return orphans
+}
+
+// scheduleSave marks the index as changed and saves it after registryDiskCacheSaveDelay, unless a save is pending
+func (c *registryDiskCache) scheduleSave() {
+	c.dirty = true
+	if c.saveTimer == nil {
+		c.saveTimer = time.AfterFunc(registryDiskCacheSaveDelay, c.saveOrWarn)
+	}
+}
+
+func (c *registryDiskCache) saveOrWarn() {
+	if err := c.save(); err != nil {
+		log.WithError(err).Warn("Failed to save the registry cache index")
+	}
+}
+
+// save writes the index when it changed, through a temporary file so a crash never leaves a partial index
+func (c *registryDiskCache) save() error {
+	if c == nil {
+		return nil
+	}
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	c.mu.Lock()
+	if c.saveTimer != nil {
+		c.saveTimer.Stop()
+		c.saveTimer = nil
+	}
+	if !c.dirty {
+		c.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(c.entries)
+	c.dirty = false
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(filepath.Join(c.dir, registryDiskCacheIndex), data); err != nil {
+		c.mu.Lock()
+		c.dirty = true
+		c.mu.Unlock()
+		return fmt.Errorf("saving the registry cache index: %w", err)
+	}
+	return nil
+}
+
+// FlushRegistryCache saves the pending changes of the registry responses kept on disk, when the server shuts down
+func FlushRegistryCache() {
+	if cache := getRegistryCache(); cache != nil {
+		cache.disk.saveOrWarn()
+	}
+}
+
+func contentHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFileAtomic writes path through a temporary file in the same directory
+func writeFileAtomic(path string, data []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
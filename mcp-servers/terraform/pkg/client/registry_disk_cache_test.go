@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := newRegistryDiskCache(dir, 1<<20)
+	require.NoError(t, err)
+
+	// Identical bodies are stored once
+	cache.put("https://registry.terraform.io/v2/provider-docs/1", `"a1"`, "", []byte("docs"))
+	cache.put("https://registry.terraform.io/v2/provider-docs/2", `"a2"`, "", []byte("docs"))
+	cache.put("https://registry.terraform.io/v2/provider-docs/3", "", "", []byte("no validators"))
+	objects, err := os.ReadDir(filepath.Join(dir, registryDiskCacheObjects))
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+
+	// Storing a response again with an identical body keeps its object
+	cache.put("https://registry.terraform.io/v2/provider-docs/2", `"a3"`, "", []byte("docs"))
+	cache.put("https://registry.terraform.io/v2/modules/1", `"m1"`, "", []byte("module"))
+	cache.put("https://registry.terraform.io/v2/modules/1", `"m2"`, "", []byte("module"))
+	_, ok := cache.get("https://registry.terraform.io/v2/modules/1")
+	require.True(t, ok)
+	assert.Equal(t, 2, cache.refs[contentHash([]byte("docs"))])
+	assert.Equal(t, int64(10), cache.total)
+
+	// The index is saved after the changes are batched, or when the server shuts down
+	index, err := os.ReadFile(filepath.Join(dir, registryDiskCacheIndex))
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(index))
+	require.True(t, cache.dirty)
+	require.NoError(t, cache.save())
+	assert.False(t, cache.dirty)
+	assert.Nil(t, cache.saveTimer)
+
+	// The responses survive a restart
+	cache, err = newRegistryDiskCache(dir, 1<<20)
+	require.NoError(t, err)
+	entry, ok := cache.get("https://registry.terraform.io/v2/provider-docs/2")
+	require.True(t, ok)
+	assert.Equal(t, `"a3"`, entry.etag)
+	assert.Equal(t, []byte("docs"), entry.body)
+	_, ok = cache.get("https://registry.terraform.io/v2/provider-docs/3")
+	assert.False(t, ok)
+
+	// A corrupted object is dropped
+	require.NoError(t, os.WriteFile(filepath.Join(dir, registryDiskCacheObjects, contentHash([]byte("docs"))), []byte("tampered"), 0o600))
+	_, ok = cache.get("https://registry.terraform.io/v2/provider-docs/1")
+	assert.False(t, ok)
+}
+
+func TestRegistryDiskCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := newRegistryDiskCache(dir, 10)
+	require.NoError(t, err)
+
+	cache.put("a", `"a"`, "", []byte("aaaa"))
+	cache.put("b", `"b"`, "", []byte("bbbb"))
+	_, ok := cache.get("a")
+	require.True(t, ok)
+
+	// The least recently used response is evicted, larger responses than the cache are not stored
+	cache.put("c", `"c"`, "", []byte("cccc"))
+	cache.put("d", `"d"`, "", []byte(strings.Repeat("d", 11)))
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+	_, ok = cache.get("d")
+	assert.False(t, ok)
+	_, ok = cache.get("a")
+	assert.True(t, ok)
+	objects, err := os.ReadDir(filepath.Join(dir, registryDiskCacheObjects))
+	require.NoError(t, err)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, int64(8), cache.total)
+
+	// A smaller cache evicts on start, and unreferenced objects are removed
+	require.NoError(t, cache.save())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, registryDiskCacheObjects, "orphan"), []byte("x"), 0o600))
+	cache, err = newRegistryDiskCache(dir, 5)
+	require.NoError(t, err)
+	assert.Len(t, cache.entries, 1)
+	objects, err = os.ReadDir(filepath.Join(dir, registryDiskCacheObjects))
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+}
+
+func TestRegistryResponseCacheReadsDisk(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := newRegistryDiskCache(dir, 1<<20)
+	require.NoError(t, err)
+	memory := newRegistryResponseCache(10)
+	memory.disk = disk
+	memory.put("a", `"a1"`, "", []byte("a"))
+	require.NoError(t, disk.save())
+
+	// A new server has an empty memory cache and reads the response from disk
+	disk, err = newRegistryDiskCache(dir, 1<<20)
+	require.NoError(t, err)
+	memory = newRegistryResponseCache(10)
+	memory.disk = disk
+	entry, ok := memory.get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("a"), entry.body)
+	assert.True(t, entry.validatedAt.IsZero(), "responses read from disk are revalidated")
+	assert.Len(t, memory.entries, 1)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SelfTestCheck is the result of calling one registry endpoint and comparing its response with the
+// type the server decodes it into
+type SelfTestCheck struct {
+	Name  string
+	URI   string
+	Err   error
+	Drift SchemaDrift
+}
+
+// Failed reports whether the tools relying on the endpoint would fail or return incomplete results.
+// Unknown and missing fields are only reported: the registry adds fields and omits optional ones,
+// e.g. the previous page of the first page.
+func (c SelfTestCheck) Failed() bool {
+	return c.Err != nil || len(c.Drift.Mismatched) > 0
+}
+
+// RunRegistrySelfTest calls the registry endpoints the tools rely on, with well-known modules,
+// providers and policies, and compares each response with its type
+func RunRegistrySelfTest(ctx context.Context, httpClient *http.Client, logger *log.Logger) []SelfTestCheck {
+	var checks []SelfTestCheck
+	check := func(name string, version string, uri string, v any) bool {
+		result := SelfTestCheck{Name: name, URI: version + "/" + uri}
+		response, err := SendRegistryCall(ctx, httpClient, http.MethodGet, uri, logger, version)
+		if err == nil {
+			result.Drift, err = CompareRegistryResponse(response, v)
+		}
+		if err == nil {
+			// The response is decoded as well, so that the next checks can follow its IDs
+			err = DecodeRegistryResponse(response, v, logger)
+		}
+		result.Err = err
+		checks = append(checks, result)
+		return err == nil
+	}
+
+	check("module search", "v1", "modules/search?q=vpc&limit=1", &TerraformModules{})
+	check("module details", "v1", "modules/terraform-aws-modules/vpc/aws", &TerraformModuleVersionDetails{})
+	check("latest provider version", "v1", "providers/hashicorp/aws", &ProviderVersionLatest{})
+	check("provider versions", "v1", "providers/hashicorp/aws/versions", &ProviderRegistryVersions{})
+	check("provider list", "v2", "providers?filter[tier]=official&page[size]=1", &ProviderList{})
+	check("policy list", "v2", "policies?page[size]=1&include=latest-version", &TerraformPolicyList{})
+
+	var providerVersions ProviderVersionList
+	if !check("provider version IDs", "v2", "providers/hashicorp/aws?include=provider-versions", &providerVersions) || len(providerVersions.Included) == 0 {
+		return checks
+	}
+	var overview ProviderOverviewStruct
+	versionID := providerVersions.Included[len(providerVersions.Included)-1].ID
+	if !check("provider docs", "v2", fmt.Sprintf("provider-docs?filter[provider-version]=%s&filter[category]=overview&filter[slug]=index", versionID), &overview) || len(overview.Data) == 0 {
+		return checks
+	}
+	check("provider doc content", "v2", "provider-docs/"+overview.Data[0].ID, &ProviderResourceDetails{})
+	return checks
+}
+
+// WriteSelfTestReport writes one line per check, followed by the fields that differ, and reports
+// whether every check passed
+func WriteSelfTestReport(out io.Writer, checks []SelfTestCheck) bool {
+	passed := true
+	for _, check := range checks {
+		status := "OK"
+		switch {
+		case check.Failed():
+			status, passed = "FAIL", false
+		case !check.Drift.Empty():
+			status = "WARN"
+		}
+		fmt.Fprintf(out, "%-4s %s (%s)\n", status, check.Name, check.URI)
+		if check.Err != nil {
+			fmt.Fprintf(out, "     error: %v\n", check.Err)
+		}
+		for _, fields := range []struct {
+			label string
+			paths []string
+		}{
+			{"missing fields", check.Drift.Missing},
+			{"mismatched fields", check.Drift.Mismatched},
+			{"unknown fields", check.Drift.Unknown},
+		} {
+			if len(fields.paths) > 0 {
+				fmt.Fprintf(out, "     %s: %s\n", fields.label, strings.Join(fields.paths, ", "))
+			}
+		}
+	}
+	return passed
+}
+
+// RegistrySelfTest is the --selftest of the server, it checks the configured registry or mirror
+func RegistrySelfTest(ctx context.Context, logger *log.Logger, out io.Writer) error {
+	fmt.Fprintf(out, "Checking the registry API at %s\n", RegistryAddress())
+	checks := RunRegistrySelfTest(ctx, createHTTPClient(false, logger), logger)
+	if !WriteSelfTestReport(out, checks) {
+		return fmt.Errorf("the registry API does not match the schema expected by the server")
+	}
+	return nil
+}
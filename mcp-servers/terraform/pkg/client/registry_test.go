@@ -8,7 +8,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -102,7 +105,7 @@ func TestSendRegistryCall(t *testing.T) {
 			}))
 			defer server.Close()
 
-			_, err := SendRegistryCall(server.Client(), tc.httpMethod, tc.uri, logger, tc.apiVersion, server.URL)
+			_, err := SendRegistryCall(t.Context(), server.Client(), tc.httpMethod, tc.uri, logger, tc.apiVersion, server.URL)
 
 			if tc.expectErrContent == "" {
 				require.NoError(t, err, "TestSendRegistryCall (%s)", tc.name)
@@ -113,3 +116,42 @@ func TestSendRegistryCall(t *testing.T) {
 		})
 	}
 }
+
+func TestSendRegistryCallSingleFlight(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		fmt.Fprint(w, `{"data": "shared"}`)
+	}))
+	defer server.Close()
+
+	const callers = 5
+	var wg sync.WaitGroup
+	bodies := make([][]byte, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "provider-docs/1", logger, "v2", server.URL)
+			assert.NoError(t, err)
+			bodies[i] = body
+		}()
+	}
+
+	// Give every caller time to join the in-flight request before it completes
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), hits.Load())
+	for _, body := range bodies {
+		assert.JSONEq(t, `{"data": "shared"}`, string(body))
+	}
+
+	// Later calls are not served from the completed request
+	_, err := SendRegistryCall(t.Context(), server.Client(), http.MethodGet, "provider-docs/1", logger, "v2", server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), hits.Load())
+}
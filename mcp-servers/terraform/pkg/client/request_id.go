@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// RequestIDHeader carries the correlation ID of a request in HTTP mode and in the gRPC metadata
+	RequestIDHeader = "X-Request-Id"
+	// requestIDMetaKey is the key of the correlation ID in the _meta of tool results
+	requestIDMetaKey = "request_id"
+
+	maxRequestIDLength = 128
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the correlation ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the correlation ID of the request, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// ValidRequestID reports whether an inbound request ID is safe to log and echo back.
+// IDs are limited to 128 letters, digits and the characters . _ : -
+func ValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.', c == '_', c == ':', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewRequestIDHandler adds the X-Request-Id of the request, or a generated ID when it is
+// missing or invalid, to the request context and echoes it in the response headers
+func NewRequestIDHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !ValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		handler.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), requestID)))
+	})
+}
+
+// NewRequestIDMiddleware assigns a correlation ID to every tool call that does not have one
+// from the transport yet, and adds it to the _meta of the result and to tool error messages
+func NewRequestIDMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			requestID := RequestIDFromContext(ctx)
+			if requestID == "" {
+				requestID = uuid.NewString()
+				ctx = ContextWithRequestID(ctx, requestID)
+			}
+
+			result, err := next(ctx, request)
+			if err != nil {
+				return result, fmt.Errorf("%w (request ID: %s)", err, requestID)
+			}
+			if result != nil {
+				annotateResult(result, requestID)
+			}
+			return result, nil
+		}
+	}
+}
+
+// annotateResult adds the correlation ID to the _meta of the result, and to the message of an error result
+func annotateResult(result *mcp.CallToolResult, requestID string) {
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[requestIDMetaKey] = requestID
+
+	if !result.IsError {
+		return
+	}
+	for i, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			text.Text = fmt.Sprintf("%s (request ID: %s)", text.Text, requestID)
+			result.Content[i] = text
+			return
+		}
+	}
+}
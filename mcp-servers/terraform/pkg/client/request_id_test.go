@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("5f0c2a9e-4d1b-4b7e-9a55-2f3c1d8e7b60"))
+	assert.True(t, ValidRequestID("agent:run_42.call-7"))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("id with spaces"))
+	assert.False(t, ValidRequestID("id\ninjected=true"))
+	assert.False(t, ValidRequestID(strings.Repeat("a", maxRequestIDLength+1)))
+}
+
+func TestRequestIDHandler(t *testing.T) {
+	var seen string
+	handler := NewRequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set(RequestIDHeader, "agent-call-1")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, "agent-call-1", seen)
+	assert.Equal(t, "agent-call-1", rr.Header().Get(RequestIDHeader))
+
+	// Invalid IDs are replaced by a generated one
+	req = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set(RequestIDHeader, "not valid")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.NotEqual(t, "not valid", seen)
+	assert.True(t, ValidRequestID(seen))
+	assert.Equal(t, seen, rr.Header().Get(RequestIDHeader))
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := NewRequestIDMiddleware()(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		seen = RequestIDFromContext(ctx)
+		switch request.Params.Name {
+		case "failing_tool":
+			return nil, errors.New("boom")
+		case "error_tool":
+			return mcp.NewToolResultError("workspace not found"), nil
+		}
+		return mcp.NewToolResultText("success"), nil
+	})
+
+	result, err := handler(ContextWithRequestID(context.Background(), "agent-call-1"), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "search_providers"}})
+	require.NoError(t, err)
+	assert.Equal(t, "agent-call-1", seen)
+	assert.Equal(t, "agent-call-1", result.Meta.AdditionalFields[requestIDMetaKey])
+	assert.Equal(t, "success", result.Content[0].(mcp.TextContent).Text)
+
+	// A request ID is generated when the transport did not provide one
+	result, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "error_tool"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, seen)
+	assert.Equal(t, seen, result.Meta.AdditionalFields[requestIDMetaKey])
+	assert.Equal(t, "workspace not found (request ID: "+seen+")", result.Content[0].(mcp.TextContent).Text)
+
+	_, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "failing_tool"}})
+	assert.EqualError(t, err, "boom (request ID: "+seen+")")
+}
@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	MaxToolResponseBytes = "MCP_MAX_TOOL_RESPONSE_BYTES"
+
+	// MaxResponseBytesParam is the argument added to every tool to lower the response budget of a call
+	MaxResponseBytesParam = "max_response_bytes"
+	// minResponseBytes is the smallest budget, below which no useful content would be left
+	minResponseBytes = 1024
+	// truncatedMetaKey is the key of the truncation details in the _meta of tool results
+	truncatedMetaKey = "truncated"
+)
+
+// LoadMaxToolResponseBytesFromEnv returns the global response budget, 0 when responses are not limited
+func LoadMaxToolResponseBytesFromEnv() int {
+	value := strings.TrimSpace(os.Getenv(MaxToolResponseBytes))
+	if value == "" {
+		return 0
+	}
+	maxBytes, err := strconv.Atoi(value)
+	if err != nil || maxBytes < 0 {
+		log.Warnf("Invalid %s value %q, tool responses are not limited", MaxToolResponseBytes, value)
+		return 0
+	}
+	if maxBytes > 0 && maxBytes < minResponseBytes {
+		log.Warnf("%s value %d is below the minimum, using %d bytes", MaxToolResponseBytes, maxBytes, minResponseBytes)
+		return minResponseBytes
+	}
+	return maxBytes
+}
+
+// WithResponseBudgetArgument adds the max_response_bytes argument to every listed tool
+func WithResponseBudgetArgument() server.ToolFilterFunc {
+	return func(_ context.Context, tools []mcp.Tool) []mcp.Tool {
+		for i, tool := range tools {
+			if tool.RawInputSchema != nil {
+				continue
+			}
+			// The properties map is shared with the registered tool, so it is copied before it is extended
+			properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+			maps.Copy(properties, tool.InputSchema.Properties)
+			properties[MaxResponseBytesParam] = map[string]any{
+				"type":        "integer",
+				"minimum":     minResponseBytes,
+				"description": "Maximum size of the response in bytes. Larger responses are truncated, dropping READMEs and examples before argument tables, and end with a marker naming the omitted sections.",
+			}
+			tools[i].InputSchema.Properties = properties
+		}
+		return tools
+	}
+}
+
+// NewResponseBudgetMiddleware truncates tool results larger than the budget of the call: the
+// max_response_bytes argument, capped by maxBytes when a global budget is configured
+func NewResponseBudgetMiddleware(maxBytes int, logger *log.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			budget := maxBytes
+			if arguments, ok := request.Params.Arguments.(map[string]any); ok {
+				if value, ok := arguments[MaxResponseBytesParam]; ok {
+					if requested, ok := value.(float64); ok && requested > 0 {
+						perCall := max(int(requested), minResponseBytes)
+						if budget == 0 || perCall < budget {
+							budget = perCall
+						}
+					}
+					// The argument belongs to the server, the tool handlers never see it
+					arguments = maps.Clone(arguments)
+					delete(arguments, MaxResponseBytesParam)
+					request.Params.Arguments = arguments
+				}
+			}
+
+			result, err := next(ctx, request)
+			if err != nil || result == nil || budget == 0 || result.IsError {
+				return result, err
+			}
+
+			for i, content := range result.Content {
+				text, ok := content.(mcp.TextContent)
+				if !ok || len(text.Text) <= budget {
+					continue
+				}
+				originalBytes := len(text.Text)
+				text.Text = truncateToBudget(text.Text, budget)
+				result.Content[i] = text
+
+				logger.WithFields(log.Fields{"tool": request.Params.Name, "bytes": originalBytes, "budget": budget}).Debug("Truncated tool response")
+				if result.Meta == nil {
+					result.Meta = &mcp.Meta{}
+				}
+				if result.Meta.AdditionalFields == nil {
+					result.Meta.AdditionalFields = make(map[string]any)
+				}
+				result.Meta.AdditionalFields[truncatedMetaKey] = map[string]any{
+					"original_bytes": originalBytes,
+					"returned_bytes": len(text.Text),
+				}
+			}
+			return result, nil
+		}
+	}
+}
+
+// markdownSection is a heading with its content, or the text before the first heading
+type markdownSection struct {
+	heading string
+	text    string
+}
+
+var (
+	// essentialSection matches the headings of the sections kept the longest: arguments, inputs and outputs
+	essentialSection = regexp.MustCompile(`(?i)argument|attribute|input|output|variable|required|schema|import`)
+	// verboseSection matches the headings of the sections dropped first: READMEs and examples
+	verboseSection = regexp.MustCompile(`(?i)readme|example|usage|description|overview|changelog|notes?\b`)
+)
+
+// truncateToBudget shortens text to at most budget bytes. Markdown is shortened by dropping whole
+// sections, READMEs and examples first and argument tables last, and any text is cut at a line
+// boundary as a last resort. A marker lists what was left out.
+func truncateToBudget(text string, budget int) string {
+	sections := splitMarkdownSections(text)
+
+	// Drop verbose sections, then sections that are neither verbose nor essential, from the end
+	kept := make([]bool, len(sections))
+	for i := range kept {
+		kept[i] = true
+	}
+	var omitted []string
+	size := len(text)
+	for _, pass := range []func(markdownSection) bool{
+		func(s markdownSection) bool { return verboseSection.MatchString(s.heading) },
+		func(s markdownSection) bool { return !essentialSection.MatchString(s.heading) },
+	} {
+		for i := len(sections) - 1; i > 0 && size+markerSize(omitted) > budget; i-- {
+			if kept[i] && sections[i].heading != "" && pass(sections[i]) {
+				kept[i] = false
+				size -= len(sections[i].text)
+				omitted = append([]string{sections[i].heading}, omitted...)
+			}
+		}
+	}
+
+	var builder strings.Builder
+	for i, section := range sections {
+		if kept[i] {
+			builder.WriteString(section.text)
+		}
+	}
+	remaining := builder.String()
+
+	limit := budget - markerSize(omitted)
+	cut := len(remaining) > limit
+	if cut {
+		remaining = cutAtLine(remaining, max(limit, 0))
+	}
+	return remaining + truncationMarker(len(text), omitted, cut)
+}
+
+// splitMarkdownSections splits markdown at its headings. Lines in fenced code blocks are never headings.
+func splitMarkdownSections(text string) []markdownSection {
+	var sections []markdownSection
+	current := markdownSection{}
+	inFence := false
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(trimmed, "#") && current.text != "" {
+			sections = append(sections, current)
+			current = markdownSection{}
+		}
+		if !inFence && strings.HasPrefix(trimmed, "#") && current.text == "" {
+			current.heading = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		}
+		current.text += line
+	}
+	return append(sections, current)
+}
+
+// cutAtLine cuts text to at most limit bytes, at the last line break when there is one
+func cutAtLine(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	text = text[:limit]
+	for len(text) > 0 && !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	if i := strings.LastIndex(text, "\n"); i > 0 {
+		text = text[:i+1]
+	}
+	return text
+}
+
+// maxMarkerSize reserves room for the numbers of the marker, which are only known at the end
+const maxMarkerSize = 200
+
+func markerSize(omitted []string) int {
+	size := maxMarkerSize
+	for _, heading := range omitted {
+		size += len(heading) + 4
+	}
+	return size
+}
+
+func truncationMarker(originalBytes int, omitted []string, cut bool) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("\n\n[truncated: the response of %d bytes exceeds the response budget", originalBytes))
+	if len(omitted) > 0 {
+		builder.WriteString(fmt.Sprintf("; omitted sections: %q", omitted))
+	}
+	if cut {
+		builder.WriteString("; the remaining content was cut")
+	}
+	builder.WriteString(". Request a specific section, or call the tool again with a larger max_response_bytes, for more]")
+	return builder.String()
+}
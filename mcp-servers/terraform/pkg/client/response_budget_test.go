@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMaxToolResponseBytesFromEnv(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{value: "", expected: 0},
+		{value: "65536", expected: 65536},
+		{value: "10", expected: minResponseBytes},
+		{value: "invalid", expected: 0},
+		{value: "-1", expected: 0},
+	}
+	for _, test := range tests {
+		t.Setenv(MaxToolResponseBytes, test.value)
+		assert.Equal(t, test.expected, LoadMaxToolResponseBytesFromEnv(), test.value)
+	}
+}
+
+func TestWithResponseBudgetArgument(t *testing.T) {
+	tool := mcp.NewTool("get_module_details", mcp.WithString("module_id", mcp.Required()))
+	properties := tool.InputSchema.Properties
+
+	tools := WithResponseBudgetArgument()(context.Background(), []mcp.Tool{tool})
+	require.Len(t, tools, 1)
+	assert.Contains(t, tools[0].InputSchema.Properties, "module_id")
+	assert.Contains(t, tools[0].InputSchema.Properties, MaxResponseBytesParam)
+	// The registered tool keeps its own schema
+	assert.NotContains(t, properties, MaxResponseBytesParam)
+}
+
+func moduleDetails() string {
+	return "# hashicorp/consul/aws\n\nConsul cluster\n\n" +
+		"## Inputs\n\n| Name | Type |\n| ---- | ---- |\n| cluster_name | string |\n\n" +
+		"## Outputs\n\n| Name | Description |\n| ---- | ----------- |\n| asg_name | The ASG |\n\n" +
+		"## Examples\n\n" + strings.Repeat("An example of the module.\n", 100) +
+		"## README\n\n" + strings.Repeat("The readme of the module.\n", 100)
+}
+
+func TestTruncateToBudget(t *testing.T) {
+	text := moduleDetails()
+
+	truncated := truncateToBudget(text, 1024)
+	assert.LessOrEqual(t, len(truncated), 1024)
+	assert.Contains(t, truncated, "| cluster_name | string |")
+	assert.Contains(t, truncated, "| asg_name | The ASG |")
+	assert.NotContains(t, truncated, "The readme of the module.")
+	assert.NotContains(t, truncated, "An example of the module.")
+	assert.Contains(t, truncated, `omitted sections: ["Examples" "README"]`)
+	assert.NotContains(t, truncated, "remaining content was cut")
+
+	// Text without headings is cut at a line boundary
+	plain := strings.Repeat("a line of text\n", 200)
+	truncated = truncateToBudget(plain, 1024)
+	assert.LessOrEqual(t, len(truncated), 1024)
+	assert.Contains(t, truncated, "remaining content was cut")
+	assert.True(t, strings.HasPrefix(truncated, "a line of text\n"))
+
+	// Headings in code blocks do not start sections
+	sections := splitMarkdownSections("intro\n```\n# comment\n```\n## Inputs\nx\n")
+	require.Len(t, sections, 2)
+	assert.Equal(t, "Inputs", sections[1].heading)
+}
+
+func TestResponseBudgetMiddleware(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	var received map[string]any
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		received = request.GetArguments()
+		return mcp.NewToolResultText(moduleDetails()), nil
+	}
+
+	call := func(maxBytes int, arguments map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "get_module_details"
+		request.Params.Arguments = arguments
+		result, err := NewResponseBudgetMiddleware(maxBytes, logger)(handler)(context.Background(), request)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		result := call(0, map[string]any{"module_id": "hashicorp/consul/aws/0.1.0"})
+		assert.Equal(t, moduleDetails(), result.Content[0].(mcp.TextContent).Text)
+		assert.Nil(t, result.Meta)
+	})
+
+	t.Run("per call budget", func(t *testing.T) {
+		arguments := map[string]any{"module_id": "hashicorp/consul/aws/0.1.0", MaxResponseBytesParam: float64(2048)}
+		result := call(0, arguments)
+		assert.LessOrEqual(t, len(result.Content[0].(mcp.TextContent).Text), 2048)
+		assert.NotContains(t, received, MaxResponseBytesParam)
+		assert.Contains(t, arguments, MaxResponseBytesParam)
+		require.NotNil(t, result.Meta)
+		assert.Contains(t, result.Meta.AdditionalFields, truncatedMetaKey)
+	})
+
+	t.Run("global budget caps the per call budget", func(t *testing.T) {
+		result := call(1024, map[string]any{MaxResponseBytesParam: float64(1 << 20)})
+		assert.LessOrEqual(t, len(result.Content[0].(mcp.TextContent).Text), 1024)
+	})
+}
@@ -0,0 +1,271 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	WebhookToken = "MCP_WEBHOOK_TOKEN"
+
+	// TFEWebhookPath receives the notifications of HCP Terraform/TFE generic notification configurations
+	TFEWebhookPath = "/webhooks/tfe"
+	// AtlantisWebhookPath receives the apply results of the Atlantis http webhooks
+	AtlantisWebhookPath = "/webhooks/atlantis"
+
+	// MaxRunEvents bounds the events kept in memory, the oldest events are dropped first
+	MaxRunEvents = 500
+	// maxWebhookBodyBytes bounds the payload of a webhook
+	maxWebhookBodyBytes = 1 << 20
+)
+
+// Sources of the run events
+const (
+	RunEventSourceTFE      = "tfe"
+	RunEventSourceAtlantis = "atlantis"
+)
+
+// RunEvent is a plan or apply event received from HCP Terraform/TFE or Atlantis
+type RunEvent struct {
+	Source       string    `json:"source"`
+	Trigger      string    `json:"trigger"`
+	Status       string    `json:"status"`
+	Message      string    `json:"message,omitempty"`
+	Organization string    `json:"organization,omitempty"`
+	Workspace    string    `json:"workspace,omitempty"`
+	WorkspaceID  string    `json:"workspace_id,omitempty"`
+	RunID        string    `json:"run_id,omitempty"`
+	RunURL       string    `json:"run_url,omitempty"`
+	Repository   string    `json:"repository,omitempty"`
+	PullRequest  int       `json:"pull_request,omitempty"`
+	PullURL      string    `json:"pull_request_url,omitempty"`
+	Directory    string    `json:"directory,omitempty"`
+	Project      string    `json:"project,omitempty"`
+	User         string    `json:"user,omitempty"`
+	OccurredAt   time.Time `json:"occurred_at"`
+	ReceivedAt   time.Time `json:"received_at"`
+}
+
+// RunEventStore keeps the most recent run events in memory
+type RunEventStore struct {
+	mu     sync.Mutex
+	events []RunEvent
+	limit  int
+}
+
+// NewRunEventStore creates a store keeping up to limit events
+func NewRunEventStore(limit int) *RunEventStore {
+	return &RunEventStore{limit: limit}
+}
+
+var (
+	runEventStore     *RunEventStore
+	runEventStoreOnce sync.Once
+)
+
+// RunEventWebhooksEnabled reports whether the webhook endpoints are served, which requires a token to authenticate them
+func RunEventWebhooksEnabled() bool {
+	return strings.TrimSpace(os.Getenv(WebhookToken)) != ""
+}
+
+// GetRunEventStore returns the store shared by the webhook endpoints and the tools
+func GetRunEventStore() *RunEventStore {
+	runEventStoreOnce.Do(func() {
+		runEventStore = NewRunEventStore(MaxRunEvents)
+	})
+	return runEventStore
+}
+
+// Add records events, dropping the oldest ones beyond the limit of the store
+func (s *RunEventStore) Add(events ...RunEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	if overflow := len(s.events) - s.limit; overflow > 0 {
+		s.events = append([]RunEvent(nil), s.events[overflow:]...)
+	}
+}
+
+// Recent returns the events matching keep, the most recently received first
+func (s *RunEventStore) Recent(keep func(RunEvent) bool) []RunEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []RunEvent
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if keep == nil || keep(s.events[i]) {
+			events = append(events, s.events[i])
+		}
+	}
+	return events
+}
+
+// tfeNotificationPayload is the payload of a generic notification configuration
+type tfeNotificationPayload struct {
+	RunID            string `json:"run_id"`
+	RunURL           string `json:"run_url"`
+	RunCreatedBy     string `json:"run_created_by"`
+	WorkspaceID      string `json:"workspace_id"`
+	WorkspaceName    string `json:"workspace_name"`
+	OrganizationName string `json:"organization_name"`
+	Notifications    []struct {
+		Message      string    `json:"message"`
+		Trigger      string    `json:"trigger"`
+		RunStatus    string    `json:"run_status"`
+		RunUpdatedAt time.Time `json:"run_updated_at"`
+		RunUpdatedBy string    `json:"run_updated_by"`
+	} `json:"notifications"`
+}
+
+// atlantisApplyResult is the payload of an Atlantis http webhook, sent after each apply
+type atlantisApplyResult struct {
+	Workspace   string
+	Directory   string
+	ProjectName string
+	Success     bool
+	Repo        struct {
+		FullName string
+	}
+	Pull struct {
+		Num int
+		URL string
+	}
+	User struct {
+		Username string
+	}
+}
+
+// NewRunEventWebhookHandler receives the webhooks of HCP Terraform/TFE and Atlantis into store. HCP Terraform/TFE
+// payloads must be signed with the token, Atlantis requests must send it as a bearer token.
+func NewRunEventWebhookHandler(store *RunEventStore, token string, logger *log.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(TFEWebhookPath, webhookHandler(logger, func(r *http.Request, body []byte) ([]RunEvent, error) {
+		mac := hmac.New(sha512.New, []byte(token))
+		mac.Write(body)
+		signature, err := hex.DecodeString(r.Header.Get("X-TFE-Notification-Signature"))
+		if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, errWebhookUnauthorized
+		}
+		return parseTFENotification(body, time.Now())
+	}, store))
+	mux.HandleFunc(AtlantisWebhookPath, webhookHandler(logger, func(r *http.Request, body []byte) ([]RunEvent, error) {
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			return nil, errWebhookUnauthorized
+		}
+		return parseAtlantisApplyResult(body, time.Now())
+	}, store))
+	return mux
+}
+
+var errWebhookUnauthorized = errors.New("invalid webhook token")
+
+// webhookHandler reads the body of a webhook, authenticates and parses it with parse, then stores its events
+func webhookHandler(logger *log.Logger, parse func(r *http.Request, body []byte) ([]RunEvent, error), store *RunEventStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+		if err != nil {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		events, err := parse(r, body)
+		if err == errWebhookUnauthorized {
+			logger.WithField("path", r.URL.Path).Warn("Rejected a webhook with an invalid token")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			logger.WithField("path", r.URL.Path).Warnf("Rejected an invalid webhook payload: %v", err)
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		store.Add(events...)
+		logger.WithField("path", r.URL.Path).Debugf("Received %d run events", len(events))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// parseTFENotification returns an event per notification of the payload. The verification request sent when
+// a notification configuration is created or enabled has no run and returns no event.
+func parseTFENotification(body []byte, receivedAt time.Time) ([]RunEvent, error) {
+	var payload tfeNotificationPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.RunID == "" {
+		return nil, nil
+	}
+	events := make([]RunEvent, 0, len(payload.Notifications))
+	for _, notification := range payload.Notifications {
+		event := RunEvent{
+			Source:       RunEventSourceTFE,
+			Trigger:      notification.Trigger,
+			Status:       notification.RunStatus,
+			Message:      notification.Message,
+			Organization: payload.OrganizationName,
+			Workspace:    payload.WorkspaceName,
+			WorkspaceID:  payload.WorkspaceID,
+			RunID:        payload.RunID,
+			RunURL:       payload.RunURL,
+			User:         notification.RunUpdatedBy,
+			OccurredAt:   notification.RunUpdatedAt,
+			ReceivedAt:   receivedAt,
+		}
+		if event.User == "" {
+			event.User = payload.RunCreatedBy
+		}
+		if event.OccurredAt.IsZero() {
+			event.OccurredAt = receivedAt
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// parseAtlantisApplyResult returns the apply event of an Atlantis webhook
+func parseAtlantisApplyResult(body []byte, receivedAt time.Time) ([]RunEvent, error) {
+	var result atlantisApplyResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if result.Repo.FullName == "" {
+		return nil, errors.New("the payload has no repository")
+	}
+	status := "applied"
+	if !result.Success {
+		status = "errored"
+	}
+	return []RunEvent{{
+		Source:      RunEventSourceAtlantis,
+		Trigger:     "apply",
+		Status:      status,
+		Workspace:   result.Workspace,
+		Repository:  result.Repo.FullName,
+		PullRequest: result.Pull.Num,
+		PullURL:     result.Pull.URL,
+		Directory:   result.Directory,
+		Project:     result.ProjectName,
+		User:        result.User.Username,
+		OccurredAt:  receivedAt,
+		ReceivedAt:  receivedAt,
+	}}, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTFENotification = `{
+  "payload_version": 1,
+  "run_url": "https://app.terraform.io/app/acme/workspaces/network/runs/run-123",
+  "run_id": "run-123",
+  "run_created_by": "alice",
+  "workspace_id": "ws-123",
+  "workspace_name": "network",
+  "organization_name": "acme",
+  "notifications": [
+    {"message": "Run Errored", "trigger": "run:errored", "run_status": "errored", "run_updated_at": "2024-05-01T10:00:00Z", "run_updated_by": null}
+  ]
+}`
+
+const testAtlantisApplyResult = `{
+  "Workspace": "default",
+  "Directory": "network",
+  "ProjectName": "network",
+  "Success": true,
+  "Repo": {"FullName": "acme/infrastructure"},
+  "Pull": {"Num": 42, "URL": "https://github.com/acme/infrastructure/pull/42"},
+  "User": {"Username": "bob"}
+}`
+
+func TestRunEventWebhookHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	store := NewRunEventStore(10)
+	handler := NewRunEventWebhookHandler(store, "secret", logger)
+
+	send := func(path, body string, headers map[string]string) int {
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		for name, value := range headers {
+			request.Header.Set(name, value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	sign := func(body, token string) map[string]string {
+		mac := hmac.New(sha512.New, []byte(token))
+		mac.Write([]byte(body))
+		return map[string]string{"X-TFE-Notification-Signature": hex.EncodeToString(mac.Sum(nil))}
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send(TFEWebhookPath, testTFENotification, nil))
+	assert.Equal(t, http.StatusUnauthorized, send(TFEWebhookPath, testTFENotification, sign(testTFENotification, "other")))
+	assert.Equal(t, http.StatusUnauthorized, send(AtlantisWebhookPath, testAtlantisApplyResult, map[string]string{"Authorization": "Bearer other"}))
+	assert.Empty(t, store.Recent(nil))
+
+	assert.Equal(t, http.StatusNoContent, send(TFEWebhookPath, testTFENotification, sign(testTFENotification, "secret")))
+	assert.Equal(t, http.StatusNoContent, send(AtlantisWebhookPath, testAtlantisApplyResult, map[string]string{"Authorization": "Bearer secret"}))
+	assert.Equal(t, http.StatusBadRequest, send(AtlantisWebhookPath, `{}`, map[string]string{"Authorization": "Bearer secret"}))
+
+	// The verification request of a new notification configuration has no run
+	verification := `{"payload_version": 1, "notifications": [{"trigger": "verification"}]}`
+	assert.Equal(t, http.StatusNoContent, send(TFEWebhookPath, verification, sign(verification, "secret")))
+
+	events := store.Recent(nil)
+	require.Len(t, events, 2)
+	assert.Equal(t, RunEventSourceAtlantis, events[0].Source)
+	assert.Equal(t, "applied", events[0].Status)
+	assert.Equal(t, "acme/infrastructure", events[0].Repository)
+	assert.Equal(t, 42, events[0].PullRequest)
+	assert.Equal(t, RunEventSourceTFE, events[1].Source)
+	assert.Equal(t, "run:errored", events[1].Trigger)
+	assert.Equal(t, "acme", events[1].Organization)
+	assert.Equal(t, "alice", events[1].User)
+	assert.Equal(t, 2024, events[1].OccurredAt.Year())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, TFEWebhookPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestRunEventStoreLimit(t *testing.T) {
+	store := NewRunEventStore(3)
+	for i := 0; i < 5; i++ {
+		store.Add(RunEvent{RunID: fmt.Sprintf("run-%d", i)})
+	}
+	events := store.Recent(nil)
+	require.Len(t, events, 3)
+	assert.Equal(t, "run-4", events[0].RunID)
+	assert.Equal(t, "run-2", events[2].RunID)
+
+	assert.Len(t, store.Recent(func(event RunEvent) bool { return event.RunID == "run-3" }), 1)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// SummarizeParam is the argument of documentation tools asking for a summary of large documents
+	SummarizeParam = "summarize"
+	// minSummarizeBytes is the size below which documents are returned as is, even when a summary is asked
+	minSummarizeBytes = 4096
+	// summaryMaxTokens is the length of the summary requested from the client's model
+	summaryMaxTokens = 1024
+
+	summarySystemPrompt = "You summarize Terraform documentation for another model that writes Terraform configuration. " +
+		"Keep the required and most used arguments with their types and defaults, the attributes, the usage " +
+		"examples in HCL and any warning about deprecations or breaking changes. Drop introductions and badges. " +
+		"Answer with the summary only, in markdown."
+)
+
+// WithSummarizeArgument adds the summarize argument to a tool returning a README or a documentation page
+func WithSummarizeArgument() mcp.ToolOption {
+	return mcp.WithBoolean(SummarizeParam,
+		mcp.Description("Summarize large documents with the client's model, through MCP sampling, before returning them. Ignored when the client does not support sampling"),
+		mcp.DefaultBool(false),
+	)
+}
+
+// samplingSession returns the session of the current tool call when its client declared the sampling capability
+func samplingSession(ctx context.Context) (server.SessionWithSampling, bool) {
+	session := server.ClientSessionFromContext(ctx)
+	sampler, ok := session.(server.SessionWithSampling)
+	if !ok {
+		return nil, false
+	}
+	withClientInfo, ok := session.(server.SessionWithClientInfo)
+	if !ok || withClientInfo.GetClientCapabilities().Sampling == nil {
+		return nil, false
+	}
+	return sampler, true
+}
+
+// SummarizeDocument returns a summary of the document written by the client's model when the call sets summarize
+// and the document is large. The server has no model of its own: the document is sent to the client with an MCP
+// sampling request. The document is returned unchanged when no summary was asked, the client does not support
+// sampling or the request fails.
+func SummarizeDocument(ctx context.Context, request mcp.CallToolRequest, title string, document string, logger *log.Logger) string {
+	if !request.GetBool(SummarizeParam, false) || len(document) < minSummarizeBytes {
+		return document
+	}
+	session, ok := samplingSession(ctx)
+	if !ok {
+		logger.Debugf("Not summarizing %s, the client does not support sampling", title)
+		return document
+	}
+
+	result, err := session.RequestSampling(ctx, mcp.CreateMessageRequest{
+		CreateMessageParams: mcp.CreateMessageParams{
+			Messages: []mcp.SamplingMessage{{
+				Role:    mcp.RoleUser,
+				Content: mcp.NewTextContent(fmt.Sprintf("Summarize the documentation of %s:\n\n%s", title, document)),
+			}},
+			SystemPrompt:   summarySystemPrompt,
+			IncludeContext: "none",
+			MaxTokens:      summaryMaxTokens,
+		},
+	})
+	if err != nil || result == nil {
+		logger.WithError(err).Warnf("Summarizing %s through sampling failed, returning the full document", title)
+		return document
+	}
+	text, ok := mcp.AsTextContent(result.Content)
+	if !ok || strings.TrimSpace(text.Text) == "" {
+		logger.Warnf("The client returned no text summary of %s, returning the full document", title)
+		return document
+	}
+
+	model := ""
+	if result.Model != "" {
+		model = fmt.Sprintf(" (%s)", result.Model)
+	}
+	return fmt.Sprintf("> Summary of %s written by the client's model%s from a %d byte document. Call the tool again without summarize for the full document.\n\n%s",
+		title, model, len(document), strings.TrimSpace(text.Text))
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// samplingFunc answers the sampling requests of an in-process client
+type samplingFunc func(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+
+func (f samplingFunc) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return f(ctx, request)
+}
+
+func samplingContext(t *testing.T, capable bool, answer samplingFunc) context.Context {
+	session := server.NewInProcessSessionWithHandlers("sampling-session", answer, nil, nil)
+	if capable {
+		session.SetClientCapabilities(mcp.ClientCapabilities{Sampling: &struct{}{}})
+	}
+	return server.NewMCPServer("test", "0.0.0").WithContext(t.Context(), session)
+}
+
+func TestSummarizeDocument(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	document := "# aws_instance\n\n" + strings.Repeat("Provides an EC2 instance resource. ", 200)
+	summarize := mcp.CallToolRequest{}
+	summarize.Params.Arguments = map[string]any{SummarizeParam: true}
+
+	var requests []mcp.CreateMessageRequest
+	answer := func(_ context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		requests = append(requests, request)
+		return &mcp.CreateMessageResult{
+			SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("Creates an EC2 instance.")},
+			Model:           "client-model",
+		}, nil
+	}
+
+	t.Run("summarized", func(t *testing.T) {
+		requests = nil
+		summary := SummarizeDocument(samplingContext(t, true, answer), summarize, "aws_instance (resources)", document, logger)
+		assert.True(t, strings.HasPrefix(summary, "> Summary of aws_instance (resources) written by the client's model (client-model)"))
+		assert.True(t, strings.HasSuffix(summary, "\n\nCreates an EC2 instance."))
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, summaryMaxTokens, requests[0].MaxTokens)
+		assert.Contains(t, requests[0].Messages[0].Content.(mcp.TextContent).Text, document)
+	})
+
+	t.Run("returned unchanged", func(t *testing.T) {
+		requests = nil
+		notAsked := mcp.CallToolRequest{}
+		failing := func(context.Context, mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			return nil, errors.New("sampling rejected by the user")
+		}
+
+		assert.Equal(t, document, SummarizeDocument(samplingContext(t, true, answer), notAsked, "doc", document, logger), "summarize not set")
+		assert.Equal(t, "short", SummarizeDocument(samplingContext(t, true, answer), summarize, "doc", "short", logger), "small document")
+		assert.Equal(t, document, SummarizeDocument(samplingContext(t, false, answer), summarize, "doc", document, logger), "client without sampling")
+		assert.Equal(t, document, SummarizeDocument(t.Context(), summarize, "doc", document, logger), "no session")
+		assert.Empty(t, requests)
+
+		assert.Equal(t, document, SummarizeDocument(samplingContext(t, true, failing), summarize, "doc", document, logger), "failed request")
+	})
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SchemaDrift lists the differences between a registry response and the type it is decoded into
+type SchemaDrift struct {
+	// Unknown are the fields of the response the type does not declare, e.g. a field added by the registry
+	Unknown []string `json:"unknown,omitempty"`
+	// Missing are the fields of the type absent from the response, e.g. a field renamed or removed by the registry
+	Missing []string `json:"missing,omitempty"`
+	// Mismatched are the fields whose JSON value does not fit the type of the field
+	Mismatched []string `json:"mismatched,omitempty"`
+}
+
+// Empty reports whether the response matches its type
+func (d SchemaDrift) Empty() bool {
+	return len(d.Unknown) == 0 && len(d.Missing) == 0 && len(d.Mismatched) == 0
+}
+
+// checkedResponseTypes records the response types whose drift was already logged, so that every
+// response type is compared once per process instead of on every call
+var checkedResponseTypes sync.Map
+
+// DecodeRegistryResponse decodes a registry response into v, tolerating the fields whose type changed:
+// they are left empty and the rest of the response is still decoded. The first response of each type
+// is compared with the type and the differences are logged, so that a registry API change shows up
+// in the logs before it surfaces as a confusing tool failure.
+func DecodeRegistryResponse(data []byte, v any, logger *log.Logger) error {
+	err := json.Unmarshal(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		logger.WithFields(log.Fields{
+			"type":  reflect.TypeOf(v).String(),
+			"field": typeErr.Field,
+			"value": typeErr.Value,
+		}).Warn("Registry response field has an unexpected type, decoding the rest of the response")
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, checked := checkedResponseTypes.LoadOrStore(reflect.TypeOf(v), true); checked {
+		return nil
+	}
+	drift, err := CompareRegistryResponse(data, v)
+	if err != nil || drift.Empty() {
+		return nil
+	}
+	logger.WithFields(log.Fields{
+		"type":       reflect.TypeOf(v).String(),
+		"unknown":    drift.Unknown,
+		"missing":    drift.Missing,
+		"mismatched": drift.Mismatched,
+	}).Warn("Registry response does not match the expected schema")
+	return nil
+}
+
+// CompareRegistryResponse compares a registry response with the type of v. Fields are named by their
+// JSON path, e.g. "modules[].downloads". A field of the type is only missing when it is absent from
+// every element of an array, fields tagged omitempty are never missing.
+func CompareRegistryResponse(data []byte, v any) (SchemaDrift, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return SchemaDrift{}, err
+	}
+	walker := &driftWalker{}
+	walker.walk("", value, reflect.TypeOf(v))
+	for _, paths := range [][]string{walker.drift.Unknown, walker.drift.Missing, walker.drift.Mismatched} {
+		sort.Strings(paths)
+	}
+	return walker.drift, nil
+}
+
+type driftWalker struct {
+	drift SchemaDrift
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (w *driftWalker) walk(path string, value any, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil || t.Kind() == reflect.Interface {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == timeType {
+			w.expect(path, value, isString)
+			return
+		}
+		object, ok := value.(map[string]any)
+		if !ok {
+			w.drift.Mismatched = append(w.drift.Mismatched, displayPath(path))
+			return
+		}
+		w.walkStruct(path, object, t)
+	case reflect.Slice, reflect.Array:
+		// A []byte is encoded as a base64 string
+		if t.Elem().Kind() == reflect.Uint8 {
+			w.expect(path, value, isString)
+			return
+		}
+		elements, ok := value.([]any)
+		if !ok {
+			w.drift.Mismatched = append(w.drift.Mismatched, displayPath(path))
+			return
+		}
+		// The elements are merged so that a field is only reported once, and only missing from every element
+		var merged any
+		for _, element := range elements {
+			merged = mergeJSON(merged, element)
+		}
+		w.walk(path+"[]", merged, t.Elem())
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			w.drift.Mismatched = append(w.drift.Mismatched, displayPath(path))
+			return
+		}
+		var merged any
+		for _, element := range object {
+			merged = mergeJSON(merged, element)
+		}
+		w.walk(path+".*", merged, t.Elem())
+	case reflect.String:
+		w.expect(path, value, isString)
+	case reflect.Bool:
+		w.expect(path, value, func(value any) bool { _, ok := value.(bool); return ok })
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		w.expect(path, value, func(value any) bool { _, ok := value.(float64); return ok })
+	}
+}
+
+func (w *driftWalker) walkStruct(path string, object map[string]any, t reflect.Type) {
+	known := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		known[strings.ToLower(name)] = true
+
+		value, present := lookupJSONField(object, name)
+		if !present {
+			if !strings.Contains(","+options+",", ",omitempty,") {
+				w.drift.Missing = append(w.drift.Missing, displayPath(path+"."+name))
+			}
+			continue
+		}
+		w.walk(path+"."+name, value, field.Type)
+	}
+	for key := range object {
+		if !known[strings.ToLower(key)] {
+			w.drift.Unknown = append(w.drift.Unknown, displayPath(path+"."+key))
+		}
+	}
+}
+
+func (w *driftWalker) expect(path string, value any, matches func(any) bool) {
+	if !matches(value) {
+		w.drift.Mismatched = append(w.drift.Mismatched, displayPath(path))
+	}
+}
+
+func isString(value any) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+// lookupJSONField finds a field like encoding/json does, preferring an exact match of the key
+func lookupJSONField(object map[string]any, name string) (any, bool) {
+	if value, ok := object[name]; ok {
+		return value, true
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// mergeJSON merges two decoded JSON values: objects get the union of their fields, arrays the
+// elements of both and any other value is kept unless it is null
+func mergeJSON(a, b any) any {
+	switch a := a.(type) {
+	case nil:
+		return b
+	case map[string]any:
+		object, ok := b.(map[string]any)
+		if !ok {
+			return a
+		}
+		merged := make(map[string]any, len(a))
+		for key, value := range a {
+			merged[key] = value
+		}
+		for key, value := range object {
+			merged[key] = mergeJSON(merged[key], value)
+		}
+		return merged
+	case []any:
+		if elements, ok := b.([]any); ok {
+			return append(append([]any{}, a...), elements...)
+		}
+	}
+	return a
+}
+
+func displayPath(path string) string {
+	if path = strings.TrimPrefix(path, "."); path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareRegistryResponse(t *testing.T) {
+	// A renamed field is missing, a new one unknown and a number sent as a string mismatched
+	response := []byte(`{
+		"meta": {"limit": 2, "current_offset": 0, "next_offset": 2, "next_url": "/next"},
+		"modules": [
+			{"id": "a/vpc/aws/1.0.0", "owner": "", "namespace": "a", "name": "vpc", "version": "1.0.0", "provider": "aws",
+			 "description": "", "source": "", "tag": "", "published_at": "2024-01-01T00:00:00Z", "downloads": "12", "verified": true, "trusted": true},
+			{"id": "b/vpc/aws/1.0.0", "owner": "", "namespace": "b", "name": "vpc", "version": "1.0.0", "provider": "aws",
+			 "description": "", "source": "", "tag": "", "published_at": "2024-01-01T00:00:00Z", "download_count": 3, "verified": false}
+		]
+	}`)
+	drift, err := CompareRegistryResponse(response, &TerraformModules{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"modules[].download_count", "modules[].trusted"}, drift.Unknown)
+	assert.Equal(t, []string{"meta.prev_offset", "meta.prev_url"}, drift.Missing)
+	assert.Equal(t, []string{"modules[].downloads"}, drift.Mismatched)
+
+	// A field is only missing when no element of an array has it, and null values are present
+	drift, err = CompareRegistryResponse([]byte(`{"versions": [{"version": "1.0.0"}, {"version": "2.0.0", "protocols": null}]}`), &ProviderRegistryVersions{})
+	require.NoError(t, err)
+	assert.True(t, drift.Empty())
+
+	_, err = CompareRegistryResponse([]byte(`not json`), &ProviderRegistryVersions{})
+	assert.Error(t, err)
+}
+
+func TestDecodeRegistryResponse(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&buffer)
+
+	// The field with an unexpected type is left empty and the rest of the response is decoded
+	var versions ProviderRegistryVersions
+	checkedResponseTypes.Delete(reflect.TypeOf(&versions))
+	err := DecodeRegistryResponse([]byte(`{"versions": [{"version": "1.0.0", "protocols": "5.0"}], "warnings": null}`), &versions, logger)
+	require.NoError(t, err)
+	require.Len(t, versions.Versions, 1)
+	assert.Equal(t, "1.0.0", versions.Versions[0].Version)
+	assert.Empty(t, versions.Versions[0].Protocols)
+	assert.Contains(t, buffer.String(), "unexpected type")
+	assert.Contains(t, buffer.String(), "does not match the expected schema")
+
+	// The drift of a response type is only logged once
+	buffer.Reset()
+	require.NoError(t, DecodeRegistryResponse([]byte(`{"versions": [], "warnings": null}`), &versions, logger))
+	assert.Empty(t, buffer.String())
+
+	// Syntax errors still fail
+	assert.Error(t, DecodeRegistryResponse([]byte(`{"versions": [`), &versions, logger))
+}
+
+func TestWriteSelfTestReport(t *testing.T) {
+	var out strings.Builder
+	passed := WriteSelfTestReport(&out, []SelfTestCheck{
+		{Name: "module search", URI: "v1/modules/search?q=vpc&limit=1"},
+		{Name: "provider versions", URI: "v1/providers/hashicorp/aws/versions", Drift: SchemaDrift{Unknown: []string{"warnings"}}},
+		{Name: "policy list", URI: "v2/policies", Drift: SchemaDrift{Mismatched: []string{"data[].attributes.downloads"}}},
+		{Name: "provider docs", URI: "v2/provider-docs", Err: errors.New("registry returned status 500")},
+	})
+	assert.False(t, passed)
+	assert.Equal(t, `OK   module search (v1/modules/search?q=vpc&limit=1)
+WARN provider versions (v1/providers/hashicorp/aws/versions)
+     unknown fields: warnings
+FAIL policy list (v2/policies)
+     mismatched fields: data[].attributes.downloads
+FAIL provider docs (v2/provider-docs)
+     error: registry returned status 500
+`, out.String())
+
+	assert.True(t, WriteSelfTestReport(&strings.Builder{}, []SelfTestCheck{{Name: "module search", Drift: SchemaDrift{Missing: []string{"meta.prev_url"}}}}))
+}
+
+func FuzzDecodeRegistryResponse(f *testing.F) {
+	f.Add([]byte(`{"modules": [{"id": "a/b/c/1.0.0", "downloads": 10, "published_at": "2024-01-01T00:00:00Z"}], "meta": {"limit": 1}}`))
+	f.Add([]byte(`{"versions": [{"version": "1.0.0", "protocols": "5.0"}], "warnings": null}`))
+	f.Add([]byte(`{"data": [{"id": "1", "attributes": {"slug": "s3_bucket", "downloads": "many"}}], "links": {"next": 2}}`))
+	f.Add([]byte(`[[[{"": {}}]]]`))
+	f.Add([]byte(`{"Data": {"DATA": [null, 1, "x", {"a": [1, [2]]}]}}`))
+	responseTypes := []func() any{
+		func() any { return &TerraformModules{} },
+		func() any { return &TerraformModuleVersionDetails{} },
+		func() any { return &ProviderRegistryVersions{} },
+		func() any { return &ProviderVersionLatest{} },
+		func() any { return &ProviderDocs{} },
+		func() any { return &ProviderResourceDetails{} },
+		func() any { return &ProviderVersionList{} },
+		func() any { return &TerraformPolicyList{} },
+		func() any { return &TerraformPolicyDetails{} },
+		func() any { return &map[string][]ProviderDocData{} },
+	}
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, newResponse := range responseTypes {
+			// A malformed response fails to decode but never panics
+			_ = DecodeRegistryResponse(data, newResponse(), logger)
+			_, _ = CompareRegistryResponse(data, newResponse())
+		}
+	})
+}
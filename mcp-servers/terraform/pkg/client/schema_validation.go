@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+)
+
+// ArgumentsError lists every argument of a tool call that does not match the tool's input schema
+type ArgumentsError struct {
+	Fields []utils.FieldError
+}
+
+func (e *ArgumentsError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, fmt.Sprintf("argument '%s' %s", field.Field, field.Message))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// schemaValidator validates decoded JSON values against the subset of JSON schema used by the tool
+// definitions: type, enum, required, properties, items, minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, minLength, maxLength, pattern, minItems and maxItems
+type schemaValidator struct {
+	fields []utils.FieldError
+}
+
+func (v *schemaValidator) fail(field string, format string, args ...any) {
+	v.fields = append(v.fields, utils.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateObject checks the properties of an object and reports missing required properties
+func (v *schemaValidator) validateObject(path string, object map[string]any, schema map[string]any) {
+	properties, _ := schema["properties"].(map[string]any)
+
+	for _, name := range requiredProperties(schema) {
+		if value, ok := object[name]; !ok || value == nil {
+			v.fail(joinField(path, name), "is required")
+		}
+	}
+
+	// Sort the names so that the errors are reported in a stable order
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if propertySchema, ok := properties[name].(map[string]any); ok {
+			v.validateValue(joinField(path, name), object[name], propertySchema)
+		}
+	}
+}
+
+// validateValue checks a value against its schema. Null is accepted for any type since the
+// tools treat it the same as an omitted optional argument.
+func (v *schemaValidator) validateValue(field string, value any, schema map[string]any) {
+	if value == nil {
+		return
+	}
+
+	expected, _ := schema["type"].(string)
+	valid := true
+	switch expected {
+	case "string":
+		_, valid = value.(string)
+	case "number":
+		_, valid = value.(float64)
+	case "integer":
+		number, ok := value.(float64)
+		valid = ok && number == math.Trunc(number)
+	case "boolean":
+		_, valid = value.(bool)
+	case "array":
+		_, valid = value.([]any)
+	case "object":
+		_, valid = value.(map[string]any)
+	}
+	if !valid {
+		v.fail(field, "must be of type %s", expected)
+		return
+	}
+
+	// Only scalar values can match an enum, and comparing two maps or slices would panic
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 && expected != "array" && expected != "object" {
+		matched := false
+		for _, allowed := range enum {
+			if value == allowed {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(field, "must be one of %v", enum)
+			return
+		}
+	}
+
+	switch value := value.(type) {
+	case float64:
+		v.validateNumber(field, value, schema)
+	case string:
+		v.validateString(field, value, schema)
+	case []any:
+		v.validateArray(field, value, schema)
+	case map[string]any:
+		v.validateObject(field, value, schema)
+	}
+}
+
+func (v *schemaValidator) validateNumber(field string, value float64, schema map[string]any) {
+	if minimum, ok := schema["minimum"].(float64); ok && value < minimum {
+		v.fail(field, "must be at least %v", minimum)
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && value > maximum {
+		v.fail(field, "must be at most %v", maximum)
+	}
+	if minimum, ok := schema["exclusiveMinimum"].(float64); ok && value <= minimum {
+		v.fail(field, "must be greater than %v", minimum)
+	}
+	if maximum, ok := schema["exclusiveMaximum"].(float64); ok && value >= maximum {
+		v.fail(field, "must be less than %v", maximum)
+	}
+}
+
+func (v *schemaValidator) validateString(field string, value string, schema map[string]any) {
+	length := float64(utf8.RuneCountInString(value))
+	if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+		v.fail(field, "must be at least %v characters long", minLength)
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+		v.fail(field, "must be at most %v characters long", maxLength)
+	}
+	if pattern, ok := schema["pattern"].(string); ok && pattern != "" {
+		// A pattern the server cannot compile is a bug in the tool definition, not in the arguments
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(value) {
+			v.fail(field, "must match the pattern %q", pattern)
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(field string, value []any, schema map[string]any) {
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(value)) < minItems {
+		v.fail(field, "must have at least %v items", minItems)
+	}
+	if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(value)) > maxItems {
+		v.fail(field, "must have at most %v items", maxItems)
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range value {
+			v.validateValue(fmt.Sprintf("%s[%d]", field, i), item, items)
+		}
+	}
+}
+
+func requiredProperties(schema map[string]any) []string {
+	var required []string
+	switch names := schema["required"].(type) {
+	case []any:
+		for _, name := range names {
+			if name, ok := name.(string); ok {
+				required = append(required, name)
+			}
+		}
+	case []string:
+		required = names
+	}
+	return required
+}
+
+func joinField(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// validateAgainstSchema validates tool arguments against the input schema of the tool
+func validateAgainstSchema(args map[string]any, schema map[string]any) error {
+	if schema == nil {
+		return nil
+	}
+	validator := &schemaValidator{}
+	validator.validateObject("", args, schema)
+	if len(validator.fields) > 0 {
+		return &ArgumentsError{Fields: validator.fields}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAgainstSchema(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":      map[string]any{"type": "string", "minLength": 2.0, "maxLength": 5.0, "pattern": "^[a-z]+$"},
+			"page_size": map[string]any{"type": "number", "minimum": 1.0, "maximum": 100.0},
+			"ratio":     map[string]any{"type": "number", "exclusiveMinimum": 0.0, "exclusiveMaximum": 1.0},
+			"tags":      map[string]any{"type": "array", "minItems": 1.0, "maxItems": 2.0, "items": map[string]any{"type": "string"}},
+			"filter": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"status": map[string]any{"type": "string", "enum": []any{"applied", "errored"}}},
+				"required":   []any{"status"},
+			},
+		},
+		"required": []any{"name"},
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		fields    []utils.FieldError
+	}{
+		{name: "valid", arguments: map[string]any{"name": "abc", "page_size": 100.0, "ratio": 0.5, "tags": []any{"a"}, "filter": map[string]any{"status": "applied"}}},
+		{name: "missing required", arguments: map[string]any{}, fields: []utils.FieldError{{Field: "name", Message: "is required"}}},
+		{name: "null required", arguments: map[string]any{"name": nil}, fields: []utils.FieldError{{Field: "name", Message: "is required"}}},
+		{name: "string too short", arguments: map[string]any{"name": "a"}, fields: []utils.FieldError{{Field: "name", Message: "must be at least 2 characters long"}}},
+		{name: "string too long", arguments: map[string]any{"name": "abcdef"}, fields: []utils.FieldError{{Field: "name", Message: "must be at most 5 characters long"}}},
+		{name: "pattern", arguments: map[string]any{"name": "AB"}, fields: []utils.FieldError{{Field: "name", Message: `must match the pattern "^[a-z]+$"`}}},
+		{name: "below minimum", arguments: map[string]any{"name": "ab", "page_size": 0.0}, fields: []utils.FieldError{{Field: "page_size", Message: "must be at least 1"}}},
+		{name: "above maximum", arguments: map[string]any{"name": "ab", "page_size": 101.0}, fields: []utils.FieldError{{Field: "page_size", Message: "must be at most 100"}}},
+		{name: "exclusive minimum", arguments: map[string]any{"name": "ab", "ratio": 0.0}, fields: []utils.FieldError{{Field: "ratio", Message: "must be greater than 0"}}},
+		{name: "exclusive maximum", arguments: map[string]any{"name": "ab", "ratio": 1.0}, fields: []utils.FieldError{{Field: "ratio", Message: "must be less than 1"}}},
+		{name: "too few items", arguments: map[string]any{"name": "ab", "tags": []any{}}, fields: []utils.FieldError{{Field: "tags", Message: "must have at least 1 items"}}},
+		{name: "too many items", arguments: map[string]any{"name": "ab", "tags": []any{"a", "b", "c"}}, fields: []utils.FieldError{{Field: "tags", Message: "must have at most 2 items"}}},
+		{name: "item type", arguments: map[string]any{"name": "ab", "tags": []any{"a", 1.0}}, fields: []utils.FieldError{{Field: "tags[1]", Message: "must be of type string"}}},
+		{name: "nested required", arguments: map[string]any{"name": "ab", "filter": map[string]any{}}, fields: []utils.FieldError{{Field: "filter.status", Message: "is required"}}},
+		{name: "nested enum", arguments: map[string]any{"name": "ab", "filter": map[string]any{"status": "queued"}}, fields: []utils.FieldError{{Field: "filter.status", Message: "must be one of [applied errored]"}}},
+		{
+			name:      "every invalid field is reported",
+			arguments: map[string]any{"page_size": "ten", "ratio": 2.0},
+			fields: []utils.FieldError{
+				{Field: "name", Message: "is required"},
+				{Field: "page_size", Message: "must be of type number"},
+				{Field: "ratio", Message: "must be less than 1"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateAgainstSchema(test.arguments, schema)
+			if test.fields == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var argumentsErr *ArgumentsError
+			require.ErrorAs(t, err, &argumentsErr)
+			assert.Equal(t, test.fields, argumentsErr.Fields)
+		})
+	}
+}
+
+func TestArgumentsErrorMessage(t *testing.T) {
+	err := &ArgumentsError{Fields: []utils.FieldError{
+		{Field: "name", Message: "is required"},
+		{Field: "tags[0]", Message: "must be of type string"},
+	}}
+	assert.Equal(t, "argument 'name' is required; argument 'tags[0]' must be of type string", err.Error())
+}
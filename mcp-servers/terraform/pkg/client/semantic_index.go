@@ -0,0 +1,377 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/hashicorp/terraform-mcp-server/version"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EmbeddingsURL         = "MCP_EMBEDDINGS_URL"
+	EmbeddingsModel       = "MCP_EMBEDDINGS_MODEL"
+	EmbeddingsAPIKey      = "MCP_EMBEDDINGS_API_KEY"
+	SemanticIndexPath     = "MCP_SEMANTIC_INDEX_PATH"
+	DefaultEmbeddingModel = "text-embedding-3-small"
+
+	// maxEmbeddingInputBytes bounds the text sent to the embedding provider for one document
+	maxEmbeddingInputBytes = 8 << 10
+	// maxSemanticSnippetBytes bounds the snippet stored with a document and shown in results
+	maxSemanticSnippetBytes = 300
+	// maxSemanticIndexDocuments bounds the index, the least recently indexed documents are dropped first
+	maxSemanticIndexDocuments = 5000
+	// maxEmbeddingResponseSize bounds the response of the embedding provider
+	maxEmbeddingResponseSize = 16 << 20
+)
+
+// Kinds of the documents in the semantic index
+const (
+	SemanticKindProviderDoc = "provider_doc"
+	SemanticKindModule      = "module"
+)
+
+// EmbeddingProvider turns texts into embedding vectors, one per text and in the same order
+type EmbeddingProvider interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// SemanticDocument is a provider or module document to add to the semantic index
+type SemanticDocument struct {
+	// ID identifies the document for the tool that fetches it, e.g. a provider_doc_id or a module_id
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	Text  string `json:"-"`
+}
+
+// SemanticMatch is a document of the index ranked by its similarity to a query
+type SemanticMatch struct {
+	ID      string  `json:"id"`
+	Kind    string  `json:"kind"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+}
+
+// indexedDocument is a document of the semantic index with its embedding
+type indexedDocument struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Title     string    `json:"title"`
+	Snippet   string    `json:"snippet"`
+	Vector    []float32 `json:"vector"`
+	IndexedAt time.Time `json:"indexed_at"`
+}
+
+// SemanticIndex holds the embeddings of the provider and module docs fetched by the tools, so
+// that they can be searched by meaning. It is kept in memory and optionally saved to a file.
+type SemanticIndex struct {
+	mu        sync.RWMutex
+	provider  EmbeddingProvider
+	path      string
+	documents map[string]indexedDocument
+}
+
+// NewSemanticIndex creates an index, loading the documents saved at path when it is not empty
+func NewSemanticIndex(provider EmbeddingProvider, path string, logger *log.Logger) *SemanticIndex {
+	index := &SemanticIndex{
+		provider:  provider,
+		path:      path,
+		documents: make(map[string]indexedDocument),
+	}
+	if path == "" {
+		return index
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("Failed to read the semantic index at %s, starting with an empty index: %v", path, err)
+		}
+		return index
+	}
+	var documents []indexedDocument
+	if err := json.Unmarshal(data, &documents); err != nil {
+		logger.Warnf("Failed to parse the semantic index at %s, starting with an empty index: %v", path, err)
+		return index
+	}
+	for _, document := range documents {
+		index.documents[document.ID] = document
+	}
+	logger.Infof("Loaded %d documents from the semantic index at %s", len(documents), path)
+	return index
+}
+
+var (
+	semanticIndexOnce sync.Once
+	semanticIndex     *SemanticIndex
+)
+
+// SemanticSearchEnabled reports whether an embedding provider is configured with MCP_EMBEDDINGS_URL
+func SemanticSearchEnabled() bool {
+	return strings.TrimSpace(os.Getenv(EmbeddingsURL)) != ""
+}
+
+// GetSemanticIndex returns the shared index, or nil when semantic search is not configured
+func GetSemanticIndex(logger *log.Logger) *SemanticIndex {
+	semanticIndexOnce.Do(func() {
+		if !SemanticSearchEnabled() {
+			return
+		}
+		provider := &openAIEmbeddingProvider{
+			url:        strings.TrimSpace(os.Getenv(EmbeddingsURL)),
+			model:      strings.TrimSpace(utils.GetEnv(EmbeddingsModel, DefaultEmbeddingModel)),
+			apiKey:     strings.TrimSpace(os.Getenv(EmbeddingsAPIKey)),
+			httpClient: createHTTPClient(false, logger),
+		}
+		semanticIndex = NewSemanticIndex(provider, strings.TrimSpace(os.Getenv(SemanticIndexPath)), logger)
+	})
+	return semanticIndex
+}
+
+// IndexInBackground adds documents to the shared index without delaying the calling tool
+func IndexInBackground(ctx context.Context, logger *log.Logger, documents ...SemanticDocument) {
+	index := GetSemanticIndex(logger)
+	if index == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := index.Add(ctx, documents...); err != nil {
+			logger.WithError(err).Warn("Failed to add documents to the semantic index")
+		}
+	}()
+}
+
+// Len returns the number of documents in the index
+func (i *SemanticIndex) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.documents)
+}
+
+// Add embeds the documents and adds them to the index, replacing documents with the same ID
+func (i *SemanticIndex) Add(ctx context.Context, documents ...SemanticDocument) error {
+	if len(documents) == 0 {
+		return nil
+	}
+	inputs := make([]string, len(documents))
+	for n, document := range documents {
+		inputs[n] = truncateUTF8(document.Title+"\n\n"+document.Text, maxEmbeddingInputBytes)
+	}
+	vectors, err := i.provider.Embed(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("embedding documents: %w", err)
+	}
+	if len(vectors) != len(documents) {
+		return fmt.Errorf("embedding documents: expected %d embeddings, got %d", len(documents), len(vectors))
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	now := time.Now()
+	for n, document := range documents {
+		i.documents[document.ID] = indexedDocument{
+			ID:        document.ID,
+			Kind:      document.Kind,
+			Title:     document.Title,
+			Snippet:   semanticSnippet(document.Text),
+			Vector:    vectors[n],
+			IndexedAt: now,
+		}
+	}
+	i.evict()
+	return i.save()
+}
+
+// Search returns the documents most similar to the query, only of the given kind when it is not empty
+func (i *SemanticIndex) Search(ctx context.Context, query string, kind string, limit int) ([]SemanticMatch, error) {
+	vectors, err := i.provider.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embedding the query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedding the query: expected 1 embedding, got %d", len(vectors))
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var matches []SemanticMatch
+	for _, document := range i.documents {
+		if kind != "" && document.Kind != kind {
+			continue
+		}
+		matches = append(matches, SemanticMatch{
+			ID:      document.ID,
+			Kind:    document.Kind,
+			Title:   document.Title,
+			Snippet: document.Snippet,
+			Score:   cosineSimilarity(vectors[0], document.Vector),
+		})
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return matches[a].ID < matches[b].ID
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// evict drops the least recently indexed documents above the size of the index
+func (i *SemanticIndex) evict() {
+	if len(i.documents) <= maxSemanticIndexDocuments {
+		return
+	}
+	documents := make([]indexedDocument, 0, len(i.documents))
+	for _, document := range i.documents {
+		documents = append(documents, document)
+	}
+	sort.Slice(documents, func(a, b int) bool { return documents[a].IndexedAt.Before(documents[b].IndexedAt) })
+	for _, document := range documents[:len(documents)-maxSemanticIndexDocuments] {
+		delete(i.documents, document.ID)
+	}
+}
+
+// save writes the index to its file, through a temporary file so a crash never leaves a partial index
+func (i *SemanticIndex) save() error {
+	if i.path == "" {
+		return nil
+	}
+	documents := make([]indexedDocument, 0, len(i.documents))
+	for _, document := range i.documents {
+		documents = append(documents, document)
+	}
+	sort.Slice(documents, func(a, b int) bool { return documents[a].ID < documents[b].ID })
+	data, err := json.Marshal(documents)
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(i.path), filepath.Base(i.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("saving the semantic index: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("saving the semantic index: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("saving the semantic index: %w", err)
+	}
+	if err := os.Rename(temp.Name(), i.path); err != nil {
+		return fmt.Errorf("saving the semantic index: %w", err)
+	}
+	return nil
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for n := range a {
+		dot += float64(a[n]) * float64(b[n])
+		normA += float64(a[n]) * float64(a[n])
+		normB += float64(b[n]) * float64(b[n])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// semanticSnippet returns the start of a document on a single line
+func semanticSnippet(text string) string {
+	snippet := strings.Join(strings.Fields(text), " ")
+	if len(snippet) <= maxSemanticSnippetBytes {
+		return snippet
+	}
+	return truncateUTF8(snippet, maxSemanticSnippetBytes) + "..."
+}
+
+// truncateUTF8 cuts text to at most limit bytes without splitting a character
+func truncateUTF8(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	text = text[:limit]
+	for len(text) > 0 && !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+// openAIEmbeddingProvider calls an OpenAI compatible embeddings endpoint, which is also served
+// by Azure OpenAI, Ollama and most self-hosted inference servers
+type openAIEmbeddingProvider struct {
+	url        string
+	model      string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func (p *openAIEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(map[string]any{"model": p.model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("terraform-mcp-server/%s", version.GetHumanVersion()))
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &RegistryStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	var response struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxEmbeddingResponseSize)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("unmarshalling embeddings: %w", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, item := range response.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, fmt.Errorf("unexpected embedding index %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
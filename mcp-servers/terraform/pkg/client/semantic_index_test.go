@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordEmbeddings embeds texts as counts of a fixed vocabulary, enough to rank documents by topic
+type wordEmbeddings struct{}
+
+var testVocabulary = []string{"serverless", "container", "database", "postgres", "network", "bucket"}
+
+func (wordEmbeddings) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(testVocabulary))
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			for j, term := range testVocabulary {
+				if strings.Trim(word, ".,") == term {
+					vectors[i][j]++
+				}
+			}
+		}
+	}
+	return vectors, nil
+}
+
+func TestSemanticIndex(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	index := NewSemanticIndex(wordEmbeddings{}, path, logger)
+	require.NoError(t, index.Add(context.Background(),
+		SemanticDocument{ID: "101", Kind: SemanticKindProviderDoc, Title: "aws_ecs_service (resources)", Text: "Runs a serverless container on Fargate."},
+		SemanticDocument{ID: "102", Kind: SemanticKindProviderDoc, Title: "aws_db_instance (resources)", Text: "A postgres database instance."},
+		SemanticDocument{ID: "terraform-aws-modules/vpc/aws/5.0.0", Kind: SemanticKindModule, Title: "vpc", Text: "A network for the database."},
+	))
+	assert.Equal(t, 3, index.Len())
+
+	matches, err := index.Search(context.Background(), "serverless container", "", 2)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "101", matches[0].ID)
+	assert.InDelta(t, 1.0, matches[0].Score, 0.01)
+
+	matches, err = index.Search(context.Background(), "database", SemanticKindModule, 5)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "terraform-aws-modules/vpc/aws/5.0.0", matches[0].ID)
+
+	// The index is saved and loaded again
+	reloaded := NewSemanticIndex(wordEmbeddings{}, path, logger)
+	assert.Equal(t, 3, reloaded.Len())
+	matches, err = reloaded.Search(context.Background(), "postgres", "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "102", matches[0].ID)
+	assert.Equal(t, "A postgres database instance.", matches[0].Snippet)
+}
+
+func TestOpenAIEmbeddingProvider(t *testing.T) {
+	var request struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		// Embeddings may be returned in any order, the index field places them
+		_, _ = w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer server.Close()
+
+	provider := &openAIEmbeddingProvider{url: server.URL, model: DefaultEmbeddingModel, apiKey: "secret", httpClient: server.Client()}
+	vectors, err := provider.Embed(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+	assert.Equal(t, DefaultEmbeddingModel, request.Model)
+	assert.Equal(t, []string{"a", "b"}, request.Input)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+	provider.url = failing.URL
+	_, err = provider.Embed(context.Background(), []string{"a"})
+	var statusErr *RegistryStatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode)
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, cosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0.0, cosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.Equal(t, 0.0, cosineSimilarity([]float32{1}, []float32{1, 2}))
+	assert.Equal(t, 0.0, cosineSimilarity([]float32{0, 0}, []float32{1, 2}))
+}
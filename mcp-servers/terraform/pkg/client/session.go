@@ -22,6 +22,7 @@ func NewSessionHandler(ctx context.Context, session server.ClientSession, logger
 	}
 
 	CreateHttpClientForSession(ctx, session, logger)
+	saveSessionAllowedOrganizations(ctx, session)
 
 	// Check if the session has a valid TFE client and register with dynamic tool registry
 	if tfeClient != nil {
@@ -45,6 +46,7 @@ func EndSessionHandler(_ context.Context, session server.ClientSession, logger *
 
 	DeleteTfeClient(session.SessionID())
 	DeleteHttpClient(session.SessionID())
+	deleteSessionAllowedOrganizations(session.SessionID())
 	logger.WithField("session_id", session.SessionID()).Info("Cleaned up clients for session")
 }
 
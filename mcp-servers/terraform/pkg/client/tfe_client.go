@@ -5,7 +5,8 @@ package client
 
 import (
 	"context"
-	"fmt"
+	"net/http"
+	"os"
 	"sync"
 
 	"github.com/hashicorp/go-tfe"
@@ -27,7 +28,7 @@ var activeTfeClients sync.Map
 func NewTfeClient(sessionId string, terraformAddress string, terraformSkipTLSVerify bool, terraformToken string, logger *log.Logger) (*tfe.Client, error) {
 	if terraformToken == "" {
 		logger.Warn("No Terraform token provided, TFE client will not be available")
-		return nil, utils.LogAndReturnError(logger, "required input: no Terraform token provided", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeUnauthorized, "required input: no Terraform token provided", nil)
 	}
 
 	config := &tfe.Config{
@@ -36,7 +37,11 @@ func NewTfeClient(sessionId string, terraformAddress string, terraformSkipTLSVer
 		RetryServerErrors: true,
 	}
 
-	config.HTTPClient = createHTTPClient(terraformSkipTLSVerify, logger)
+	httpClient, err := NewTfeHTTPClient(terraformSkipTLSVerify, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "configuring TLS for the TFE client", err)
+	}
+	config.HTTPClient = httpClient
 
 	client, err := tfe.NewClient(config)
 	if err != nil {
@@ -49,6 +54,34 @@ func NewTfeClient(sessionId string, terraformAddress string, terraformSkipTLSVer
 	return client, nil
 }
 
+// VerifyTfeToken reads the user of a token with the TLS and proxy settings of the session clients,
+// so that the doctor command fails on the same certificate or token errors as the tools
+func VerifyTfeToken(ctx context.Context, terraformAddress string, terraformSkipTLSVerify bool, terraformToken string, logger *log.Logger) (*tfe.User, error) {
+	httpClient, err := NewTfeHTTPClient(terraformSkipTLSVerify, logger)
+	if err != nil {
+		return nil, err
+	}
+	client, err := tfe.NewClient(&tfe.Config{
+		Address:    terraformAddress,
+		Token:      terraformToken,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.Users.ReadCurrent(ctx)
+}
+
+// NewTfeHTTPClient creates an HTTP client for HCP Terraform/TFE with the proxy and the TLS settings of the
+// TFE client: its certificate authorities, client certificate and minimum TLS version
+func NewTfeHTTPClient(terraformSkipTLSVerify bool, logger *log.Logger) (*http.Client, error) {
+	tlsConfig, err := LoadTfeTLSOptionsFromEnv(terraformSkipTLSVerify).TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return createHTTPClientWithTLS(tlsConfig, logger), nil
+}
+
 // GetTfeClient retrieves the TFE client for the given session
 func GetTfeClient(sessionId string) *tfe.Client {
 	if value, ok := activeTfeClients.Load(sessionId); ok {
@@ -62,21 +95,22 @@ func DeleteTfeClient(sessionId string) {
 	activeTfeClients.Delete(sessionId)
 }
 
-// GetTfeClientFromContext extracts TFE client from the MCP context
-func GetTfeClientFromContext(ctx context.Context, logger *log.Logger) (*tfe.Client, error) {
-	session := server.ClientSessionFromContext(ctx)
-	if session == nil {
-		return nil, fmt.Errorf("no active session")
+// TfeAvailable reports whether the HCP Terraform/TFE tools can be used in the session of ctx: the session has a
+// TFE client, or a token is set in its HTTP headers or in the environment. The token is not verified.
+func TfeAvailable(ctx context.Context) bool {
+	if session := server.ClientSessionFromContext(ctx); session != nil && GetTfeClient(session.SessionID()) != nil {
+		return true
 	}
-
-	// Try to get existing client
-	client := GetTfeClient(session.SessionID())
-	if client != nil {
-		return client, nil
+	if token, ok := ctx.Value(contextKey(TerraformToken)).(string); ok && token != "" {
+		return true
 	}
+	return os.Getenv(TerraformToken) != ""
+}
 
-	logger.Warnf("TFE client not found, creating a new one")
-	return CreateTfeClientForSession(ctx, session, logger)
+// GetTfeClientFromContext returns the TFE client of the tool call of ctx, from the provider set with
+// ContextWithTfeClientProvider or else from its MCP session
+func GetTfeClientFromContext(ctx context.Context, logger *log.Logger) (*tfe.Client, error) {
+	return tfeClientProviderFromContext(ctx).TfeClient(ctx, logger)
 }
 
 // CreateTfeClientForSession creates only a TFE client for the session
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	TerraformCACertFile     = "TFE_CA_CERT_FILE"
+	TerraformClientCertFile = "TFE_CLIENT_CERT_FILE"
+	TerraformClientKeyFile  = "TFE_CLIENT_KEY_FILE"
+	TerraformTLSMinVersion  = "TFE_TLS_MIN_VERSION"
+)
+
+// tlsVersions maps the accepted TFE_TLS_MIN_VERSION values to their TLS versions
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TfeTLSOptions holds the TLS settings of the TFE client. They are read from the server environment
+// only, since file paths supplied by remote clients in HTTP headers must not be trusted.
+type TfeTLSOptions struct {
+	SkipVerify     bool
+	CACertFile     string
+	ClientCertFile string
+	ClientKeyFile  string
+	MinVersion     string
+}
+
+// LoadTfeTLSOptionsFromEnv loads the TFE TLS options from environment variables.
+// The CA bundle falls back to MCP_CA_CERT_FILE so a single bundle can serve both the proxy and TFE.
+func LoadTfeTLSOptionsFromEnv(skipVerify bool) TfeTLSOptions {
+	caFile := strings.TrimSpace(os.Getenv(TerraformCACertFile))
+	if caFile == "" {
+		caFile = strings.TrimSpace(os.Getenv(CACertFile))
+	}
+	return TfeTLSOptions{
+		SkipVerify:     skipVerify,
+		CACertFile:     caFile,
+		ClientCertFile: strings.TrimSpace(os.Getenv(TerraformClientCertFile)),
+		ClientKeyFile:  strings.TrimSpace(os.Getenv(TerraformClientKeyFile)),
+		MinVersion:     strings.TrimSpace(os.Getenv(TerraformTLSMinVersion)),
+	}
+}
+
+// TLSConfig builds the TLS configuration of the TFE client from the options
+func (o TfeTLSOptions) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: o.SkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if o.MinVersion != "" {
+		version, ok := tlsVersions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported %s %q, must be 1.2 or 1.3", TerraformTLSMinVersion, o.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if o.CACertFile != "" {
+		pool, err := loadCACertPool(o.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	if (o.ClientCertFile == "") != (o.ClientKeyFile == "") {
+		return nil, fmt.Errorf("%s and %s must be set together", TerraformClientCertFile, TerraformClientKeyFile)
+	}
+	if o.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading TFE client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeClientCertificate writes a self-signed client certificate and its key to dir
+func writeClientCertificate(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform-mcp-server"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600))
+	return cert, certFile, keyFile
+}
+
+func TestLoadTfeTLSOptionsFromEnv(t *testing.T) {
+	t.Setenv(CACertFile, "/etc/ssl/mcp.pem")
+	t.Setenv(TerraformCACertFile, "")
+	t.Setenv(TerraformTLSMinVersion, "1.3")
+
+	options := LoadTfeTLSOptionsFromEnv(true)
+	assert.True(t, options.SkipVerify)
+	assert.Equal(t, "/etc/ssl/mcp.pem", options.CACertFile)
+	assert.Equal(t, "1.3", options.MinVersion)
+
+	t.Setenv(TerraformCACertFile, "/etc/ssl/tfe.pem")
+	assert.Equal(t, "/etc/ssl/tfe.pem", LoadTfeTLSOptionsFromEnv(false).CACertFile)
+}
+
+func TestTfeTLSOptionsTLSConfig(t *testing.T) {
+	config, err := TfeTLSOptions{}.TLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Nil(t, config.RootCAs)
+	assert.False(t, config.InsecureSkipVerify)
+
+	config, err = TfeTLSOptions{MinVersion: "1.3", SkipVerify: true}.TLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+	assert.True(t, config.InsecureSkipVerify)
+
+	_, err = TfeTLSOptions{MinVersion: "1.0"}.TLSConfig()
+	assert.ErrorContains(t, err, "unsupported TFE_TLS_MIN_VERSION")
+
+	_, err = TfeTLSOptions{ClientCertFile: "client.pem"}.TLSConfig()
+	assert.ErrorContains(t, err, "must be set together")
+
+	_, err = TfeTLSOptions{ClientCertFile: "missing.pem", ClientKeyFile: "missing-key.pem"}.TLSConfig()
+	assert.ErrorContains(t, err, "loading TFE client certificate")
+}
+
+func TestTfeTLSOptionsMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, certFile, keyFile := writeClientCertificate(t, dir)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	config, err := TfeTLSOptions{CACertFile: caFile, ClientCertFile: certFile, ClientKeyFile: keyFile}.TLSConfig()
+	require.NoError(t, err)
+
+	// The server certificate is trusted through the CA bundle and the server requires the client certificate
+	resp, err := createHTTPClientWithTLS(config, log.New()).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	config, err = TfeTLSOptions{CACertFile: caFile}.TLSConfig()
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get(server.URL)
+	assert.Error(t, err)
+}
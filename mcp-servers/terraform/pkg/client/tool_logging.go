@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// toolLogFields returns the tool, session and request ID fields of a log entry about a tool call
+func toolLogFields(ctx context.Context, toolName string) log.Fields {
+	fields := log.Fields{"tool": toolName}
+	if sessionID := getSessionIDFromContext(ctx); sessionID != "" {
+		fields["session"] = sessionID
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields["request_id"] = requestID
+	}
+	return fields
+}
+
+// NewToolLoggingMiddleware logs every tool call with the tool and session fields and its duration
+func NewToolLoggingMiddleware(logger *log.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			entry := logger.WithFields(toolLogFields(ctx, request.Params.Name))
+			entry.Debug("Tool call started")
+
+			start := time.Now()
+			result, err := next(ctx, request)
+			entry = entry.WithField("duration_ms", time.Since(start).Milliseconds())
+
+			switch {
+			case err != nil:
+				entry.WithError(err).Warn("Tool call failed")
+			case result != nil && result.IsError:
+				entry.Info("Tool call returned an error result")
+			default:
+				entry.Debug("Tool call completed")
+			}
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolLoggingMiddleware(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(log.DebugLevel)
+
+	handler := NewToolLoggingMiddleware(logger)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Name == "failing_tool" {
+			return nil, errors.New("boom")
+		}
+		return mcp.NewToolResultText("success"), nil
+	})
+
+	_, err := handler(ContextWithRequestID(context.Background(), "agent-call-1"), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "search_providers"}})
+	require.NoError(t, err)
+	require.Len(t, hook.AllEntries(), 2)
+	assert.Equal(t, "search_providers", hook.LastEntry().Data["tool"])
+	assert.Equal(t, "agent-call-1", hook.LastEntry().Data["request_id"])
+	assert.Contains(t, hook.LastEntry().Data, "duration_ms")
+	assert.Equal(t, "Tool call completed", hook.LastEntry().Message)
+
+	hook.Reset()
+	_, err = handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "failing_tool"}})
+	require.Error(t, err)
+	assert.Equal(t, log.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "failing_tool", hook.LastEntry().Data["tool"])
+}
@@ -0,0 +1,304 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	NotifyWebhookURL    = "MCP_NOTIFY_WEBHOOK_URL"
+	NotifyWebhookFormat = "MCP_NOTIFY_WEBHOOK_FORMAT"
+	NotifyTools         = "MCP_NOTIFY_TOOLS"
+
+	// Formats of the notifications
+	NotificationFormatSlack = "slack"
+	NotificationFormatJSON  = "json"
+
+	// notificationTimeout bounds the delivery of a notification, including its retries
+	notificationTimeout = 30 * time.Second
+	// maxNotifiedArgumentLength truncates the arguments included in a notification
+	maxNotifiedArgumentLength = 200
+	// maxNotifiedErrorLength truncates the error of a failed call
+	maxNotifiedErrorLength = 1000
+)
+
+// ToolNotificationConfig selects the tool calls posted to a Slack incoming webhook or a generic HTTP endpoint
+type ToolNotificationConfig struct {
+	URL    string
+	Format string
+	// Tools are tool name patterns with * wildcards, optionally qualified by an operation, e.g. action_run:apply.
+	// Without patterns, the calls of the destructive tools are notified.
+	Tools []string
+}
+
+// Enabled reports whether tool calls are notified
+func (c ToolNotificationConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+// LoadToolNotificationConfigFromEnv reads MCP_NOTIFY_WEBHOOK_URL, MCP_NOTIFY_WEBHOOK_FORMAT and MCP_NOTIFY_TOOLS.
+// The format defaults to slack for Slack incoming webhooks and to json otherwise.
+func LoadToolNotificationConfigFromEnv() (ToolNotificationConfig, error) {
+	config := ToolNotificationConfig{URL: strings.TrimSpace(os.Getenv(NotifyWebhookURL))}
+	if config.URL == "" {
+		return config, nil
+	}
+	target, err := url.Parse(config.URL)
+	if err != nil || target.Host == "" {
+		return config, fmt.Errorf("%s is not a URL", NotifyWebhookURL)
+	}
+	if target.Scheme != "https" {
+		return config, fmt.Errorf("%s must use https", NotifyWebhookURL)
+	}
+
+	config.Format = strings.ToLower(strings.TrimSpace(os.Getenv(NotifyWebhookFormat)))
+	switch config.Format {
+	case "":
+		config.Format = NotificationFormatJSON
+		if target.Hostname() == "hooks.slack.com" {
+			config.Format = NotificationFormatSlack
+		}
+	case NotificationFormatSlack, NotificationFormatJSON:
+	default:
+		return config, fmt.Errorf("%s %q is neither slack nor json", NotifyWebhookFormat, config.Format)
+	}
+
+	for _, pattern := range strings.Split(os.Getenv(NotifyTools), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(pattern, ":")
+		if _, err := path.Match(name, ""); err != nil {
+			return config, fmt.Errorf("invalid %s pattern %q: %w", NotifyTools, pattern, err)
+		}
+		config.Tools = append(config.Tools, pattern)
+	}
+	return config, nil
+}
+
+// ToolAnnotationsLookup returns the annotations of a registered tool
+type ToolAnnotationsLookup func(ctx context.Context, toolName string) (mcp.ToolAnnotation, bool)
+
+// ToolEvent is the notification of a tool call
+type ToolEvent struct {
+	Tool       string            `json:"tool"`
+	Status     string            `json:"status"`
+	Error      string            `json:"error,omitempty"`
+	Arguments  map[string]string `json:"arguments,omitempty"`
+	Client     string            `json:"client,omitempty"`
+	Session    string            `json:"session,omitempty"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Time       time.Time         `json:"time"`
+	DurationMS int64             `json:"duration_ms"`
+}
+
+// ToolNotifier posts the selected tool calls once they complete
+type ToolNotifier struct {
+	config     ToolNotificationConfig
+	lookup     ToolAnnotationsLookup
+	httpClient *http.Client
+	logger     *log.Logger
+}
+
+// NewToolNotifier creates a notifier delivering through the outbound proxy and certificates of the other clients
+func NewToolNotifier(config ToolNotificationConfig, lookup ToolAnnotationsLookup, logger *log.Logger) *ToolNotifier {
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = nil
+	retryClient.RetryMax = 2
+	retryClient.HTTPClient.Timeout = 10 * time.Second
+	retryClient.HTTPClient.Transport = &http.Transport{
+		Proxy:           outboundProxy(logger),
+		TLSClientConfig: &tls.Config{RootCAs: outboundRootCAs(logger)},
+	}
+	return &ToolNotifier{config: config, lookup: lookup, httpClient: retryClient.StandardClient(), logger: logger}
+}
+
+// selects reports whether the call of a tool is notified
+func (n *ToolNotifier) selects(ctx context.Context, request mcp.CallToolRequest) bool {
+	if len(n.config.Tools) == 0 {
+		annotations, ok := n.lookup(ctx, request.Params.Name)
+		return ok && annotations.DestructiveHint != nil && *annotations.DestructiveHint
+	}
+	arguments := request.GetArguments()
+	for _, pattern := range n.config.Tools {
+		name, operation, qualified := strings.Cut(pattern, ":")
+		if matched, _ := path.Match(name, request.Params.Name); !matched {
+			continue
+		}
+		if !qualified {
+			return true
+		}
+		for _, argument := range guardrailOperationArguments {
+			if value, ok := arguments[argument].(string); ok && strings.EqualFold(value, operation) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Middleware returns the tool handler middleware. Dry runs and calls waiting for a confirmation change nothing
+// and are not notified. Notifications are sent in the background and never delay or fail the call.
+func (n *ToolNotifier) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if dryRun, _ := request.GetArguments()["dry_run"].(bool); dryRun || !n.selects(ctx, request) {
+				return next(ctx, request)
+			}
+
+			start := time.Now()
+			result, err := next(ctx, request)
+			if awaitsConfirmation(result) {
+				return result, err
+			}
+
+			event := ToolEvent{
+				Tool:       request.Params.Name,
+				Status:     "succeeded",
+				Arguments:  notifiedArguments(request.GetArguments()),
+				Client:     clientName(ctx),
+				Session:    getSessionIDFromContext(ctx),
+				RequestID:  RequestIDFromContext(ctx),
+				Time:       start.UTC(),
+				DurationMS: time.Since(start).Milliseconds(),
+			}
+			switch {
+			case err != nil:
+				event.Status = "failed"
+				event.Error = truncateUTF8(utils.RedactSecrets(err.Error()), maxNotifiedErrorLength)
+			case result != nil && result.IsError:
+				event.Status = "failed"
+				event.Error = truncateUTF8(utils.RedactSecrets(resultText(result)), maxNotifiedErrorLength)
+			}
+			go n.send(context.WithoutCancel(ctx), event)
+			return result, err
+		}
+	}
+}
+
+// send posts the event, logging the failures
+func (n *ToolNotifier) send(ctx context.Context, event ToolEvent) {
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	var payload any = event
+	if n.config.Format == NotificationFormatSlack {
+		payload = map[string]string{"text": slackToolEventText(event)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		n.logger.WithError(err).Warn("Failed to encode a tool notification")
+		return
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		n.logger.WithError(err).Warn("Failed to create a tool notification")
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		n.logger.WithField("tool", event.Tool).Warnf("Failed to send a tool notification: %v", err)
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		n.logger.WithField("tool", event.Tool).Warnf("The tool notification endpoint answered %s", response.Status)
+	}
+}
+
+// awaitsConfirmation reports whether the result asks the caller to confirm the call, which has not changed anything yet
+func awaitsConfirmation(result *mcp.CallToolResult) bool {
+	if result == nil || result.IsError {
+		return false
+	}
+	var confirmation struct {
+		ConfirmationRequired bool `json:"confirmation_required"`
+	}
+	return json.Unmarshal([]byte(resultText(result)), &confirmation) == nil && confirmation.ConfirmationRequired
+}
+
+// resultText returns the first text content of a result
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// notifiedArguments keeps the arguments identifying what the call changed, i.e. the names, IDs and operations.
+// Other arguments, e.g. variable values, are left out.
+func notifiedArguments(arguments map[string]any) map[string]string {
+	notified := map[string]string{}
+	for name, value := range arguments {
+		text, ok := value.(string)
+		if !ok || text == "" {
+			continue
+		}
+		if !strings.HasSuffix(name, "_name") && !strings.HasSuffix(name, "_id") && !slices.Contains(guardrailOperationArguments, name) {
+			continue
+		}
+		notified[name] = truncateUTF8(utils.RedactSecrets(text), maxNotifiedArgumentLength)
+	}
+	return notified
+}
+
+// clientName returns the name and version the MCP client sent when initializing its session
+func clientName(ctx context.Context) string {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return ""
+	}
+	info := session.GetClientInfo()
+	return strings.TrimSpace(info.Name + " " + info.Version)
+}
+
+// slackToolEventText formats the event as the text of a Slack message
+func slackToolEventText(event ToolEvent) string {
+	var builder strings.Builder
+	icon := ":white_check_mark:"
+	if event.Status != "succeeded" {
+		icon = ":x:"
+	}
+	fmt.Fprintf(&builder, "%s `%s` %s", icon, event.Tool, event.Status)
+	if event.Client != "" {
+		fmt.Fprintf(&builder, " for %s", event.Client)
+	}
+	names := make([]string, 0, len(event.Arguments))
+	for name := range event.Arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&builder, "\n• %s: `%s`", name, event.Arguments[name])
+	}
+	if event.Error != "" {
+		fmt.Fprintf(&builder, "\n> %s", truncateUTF8(strings.ReplaceAll(event.Error, "\n", " "), 500))
+	}
+	if event.Session != "" || event.RequestID != "" {
+		fmt.Fprintf(&builder, "\nsession `%s`, request `%s`", event.Session, event.RequestID)
+	}
+	return builder.String()
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadToolNotificationConfigFromEnv(t *testing.T) {
+	config, err := LoadToolNotificationConfigFromEnv()
+	require.NoError(t, err)
+	assert.False(t, config.Enabled())
+
+	t.Setenv(NotifyWebhookURL, "https://hooks.slack.com/services/T000/B000/XXXX")
+	t.Setenv(NotifyTools, "delete_*, action_run:apply")
+	config, err = LoadToolNotificationConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, NotificationFormatSlack, config.Format)
+	assert.Equal(t, []string{"delete_*", "action_run:apply"}, config.Tools)
+
+	t.Setenv(NotifyWebhookURL, "https://events.corp.example.com/terraform")
+	config, err = LoadToolNotificationConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, NotificationFormatJSON, config.Format)
+
+	for name, env := range map[string]map[string]string{
+		"plain http":      {NotifyWebhookURL: "http://events.corp.example.com/terraform"},
+		"unknown format":  {NotifyWebhookFormat: "teams"},
+		"invalid pattern": {NotifyTools: "delete_["},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+			_, err := LoadToolNotificationConfigFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestToolNotifierSelects(t *testing.T) {
+	destructive := true
+	lookup := func(_ context.Context, toolName string) (mcp.ToolAnnotation, bool) {
+		if toolName == "delete_workspace_safely" {
+			return mcp.ToolAnnotation{DestructiveHint: &destructive}, true
+		}
+		return mcp.ToolAnnotation{}, toolName == "list_runs"
+	}
+	request := func(name string, arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name, Arguments: arguments}}
+	}
+
+	notifier := NewToolNotifier(ToolNotificationConfig{URL: "https://example.com"}, lookup, log.New())
+	assert.True(t, notifier.selects(t.Context(), request("delete_workspace_safely", nil)))
+	assert.False(t, notifier.selects(t.Context(), request("list_runs", nil)))
+	assert.False(t, notifier.selects(t.Context(), request("unknown", nil)))
+
+	notifier = NewToolNotifier(ToolNotificationConfig{URL: "https://example.com", Tools: []string{"delete_*", "action_run:apply"}}, lookup, log.New())
+	assert.True(t, notifier.selects(t.Context(), request("delete_workspace_safely", nil)))
+	assert.True(t, notifier.selects(t.Context(), request("action_run", map[string]any{"run_action": "Apply"})))
+	assert.False(t, notifier.selects(t.Context(), request("action_run", map[string]any{"run_action": "discard"})))
+	assert.False(t, notifier.selects(t.Context(), request("list_runs", nil)))
+}
+
+func TestToolNotifierMiddleware(t *testing.T) {
+	received := make(chan []byte, 10)
+	endpoint := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+	}))
+	defer endpoint.Close()
+
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	newNotifier := func(format string) *ToolNotifier {
+		notifier := NewToolNotifier(ToolNotificationConfig{URL: endpoint.URL, Format: format, Tools: []string{"delete_*"}}, nil, logger)
+		notifier.httpClient = endpoint.Client()
+		return notifier
+	}
+	call := func(notifier *ToolNotifier, arguments map[string]any, result *mcp.CallToolResult, err error) {
+		handler := notifier.Middleware()(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return result, err
+		})
+		_, _ = handler(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "delete_workspace_safely", Arguments: arguments}})
+	}
+	next := func() []byte {
+		select {
+		case body := <-received:
+			return body
+		case <-time.After(5 * time.Second):
+			t.Fatal("no notification received")
+			return nil
+		}
+	}
+
+	notifier := newNotifier(NotificationFormatJSON)
+	arguments := map[string]any{"terraform_org_name": "acme", "workspace_name": "network", "value": "secret", "force": true}
+
+	// Dry runs and calls waiting for a confirmation are not notified
+	call(notifier, map[string]any{"workspace_name": "network", "dry_run": true}, mcp.NewToolResultText("{}"), nil)
+	call(notifier, arguments, mcp.NewToolResultText(`{"confirmation_required": true, "confirmation_token": "abc"}`), nil)
+
+	call(notifier, arguments, mcp.NewToolResultText(`{"deleted": true}`), nil)
+	var event ToolEvent
+	require.NoError(t, json.Unmarshal(next(), &event))
+	assert.Equal(t, "delete_workspace_safely", event.Tool)
+	assert.Equal(t, "succeeded", event.Status)
+	assert.Equal(t, map[string]string{"terraform_org_name": "acme", "workspace_name": "network"}, event.Arguments)
+
+	call(notifier, arguments, nil, errors.New("workspace has resources"))
+	require.NoError(t, json.Unmarshal(next(), &event))
+	assert.Equal(t, "failed", event.Status)
+	assert.Equal(t, "workspace has resources", event.Error)
+
+	call(newNotifier(NotificationFormatSlack), arguments, mcp.NewToolResultError("locked"), nil)
+	var message map[string]string
+	require.NoError(t, json.Unmarshal(next(), &message))
+	assert.Contains(t, message["text"], ":x: `delete_workspace_safely` failed")
+	assert.Contains(t, message["text"], "• workspace_name: `network`")
+	assert.Contains(t, message["text"], "> locked")
+
+	assert.Empty(t, received)
+}
@@ -14,6 +14,7 @@ type ProviderDetail struct {
 	ProviderNamespace string
 	ProviderVersion   string
 	ProviderDataType  string
+	Language          string
 }
 
 type ModuleDetail struct {
@@ -124,6 +125,64 @@ type TerraformModuleVersionDetails struct {
 	Deprecation     any          `json:"deprecation"` // Assuming it can be null or an object
 }
 
+// DownloadsSummary is the number of downloads of a module or provider over the last week, month and year,
+// and in total
+type DownloadsSummary struct {
+	Week  int64 `json:"week"`
+	Month int64 `json:"month"`
+	Year  int64 `json:"year"`
+	Total int64 `json:"total"`
+}
+
+// ProviderRegistryVersions represents the structure of the provider versions response of the registry protocol.
+// https://registry.terraform.io/v1/providers/hashicorp/aws/versions
+type ProviderRegistryVersions struct {
+	Versions []ProviderRegistryVersion `json:"versions"`
+}
+
+// ProviderRegistryVersion is a provider release with the plugin protocols and platforms it supports
+type ProviderRegistryVersion struct {
+	Version   string             `json:"version"`
+	Protocols []string           `json:"protocols"`
+	Platforms []ProviderPlatform `json:"platforms,omitempty"`
+}
+
+// ProviderPlatform is an operating system and architecture a provider release is built for
+type ProviderPlatform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+// ProviderPackage represents the structure of the provider package response of the registry protocol, with
+// the checksums file of the release, its detached signature and the public keys to verify it with.
+// https://registry.terraform.io/v1/providers/hashicorp/aws/5.31.0/download/linux/amd64
+type ProviderPackage struct {
+	Protocols           []string            `json:"protocols"`
+	OS                  string              `json:"os"`
+	Arch                string              `json:"arch"`
+	Filename            string              `json:"filename"`
+	DownloadURL         string              `json:"download_url"`
+	ShasumsURL          string              `json:"shasums_url"`
+	ShasumsSignatureURL string              `json:"shasums_signature_url"`
+	Shasum              string              `json:"shasum"`
+	SigningKeys         ProviderSigningKeys `json:"signing_keys"`
+}
+
+// ProviderSigningKeys lists the GPG public keys a provider release may be signed with
+type ProviderSigningKeys struct {
+	GPGPublicKeys []ProviderGPGPublicKey `json:"gpg_public_keys"`
+}
+
+// ProviderGPGPublicKey is an ASCII-armored GPG public key of a provider namespace. Partner keys carry
+// a trust signature made with the HashiCorp partners key.
+type ProviderGPGPublicKey struct {
+	KeyID          string `json:"key_id"`
+	ASCIIArmor     string `json:"ascii_armor"`
+	TrustSignature string `json:"trust_signature"`
+	Source         string `json:"source"`
+	SourceURL      string `json:"source_url"`
+}
+
 // ProviderLatest represents the structure of the latest provider response.
 // https://registry.terraform.io/v1/providers/hashicorp/consul/latest
 type ProviderVersionLatest struct {
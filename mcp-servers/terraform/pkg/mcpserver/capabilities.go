@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// CapabilitiesResourceURI is the resource clients read the feature flags of the server from
+const CapabilitiesResourceURI = "server://capabilities"
+
+// capabilitiesMetaKey is the key of the feature flags in the _meta of the initialize result, e.g.
+// terraform-mcp-server/capabilities
+func capabilitiesMetaKey(cfg Config) string {
+	return cfg.Name + "/capabilities"
+}
+
+// advertiseCapabilities adds the feature flags of the session to the _meta of the initialize result, so
+// that clients can adapt to the server without reading the capabilities resource first
+func advertiseCapabilities(cfg Config, logger *log.Logger) server.OnAfterInitializeFunc {
+	return func(ctx context.Context, _ any, _ *mcp.InitializeRequest, result *mcp.InitializeResult) {
+		if result.Meta == nil {
+			result.Meta = &mcp.Meta{}
+		}
+		if result.Meta.AdditionalFields == nil {
+			result.Meta.AdditionalFields = make(map[string]any)
+		}
+		result.Meta.AdditionalFields[capabilitiesMetaKey(cfg)] = cfg.Capabilities(ctx, logger)
+	}
+}
+
+// capabilitiesResource returns the resource reporting the feature flags of the session reading it, as JSON
+func capabilitiesResource(cfg Config, logger *log.Logger) (mcp.Resource, server.ResourceHandlerFunc) {
+	description := fmt.Sprintf("Feature flags of the %s for the current session", cfg.Title)
+	return mcp.NewResource(
+			CapabilitiesResourceURI,
+			"Server capabilities",
+			mcp.WithMIMEType("application/json"),
+			mcp.WithResourceDescription(description),
+		),
+		func(ctx context.Context, _ mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			capabilities, err := json.MarshalIndent(cfg.Capabilities(ctx, logger), "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("encoding the server capabilities: %w", err)
+			}
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{
+					MIMEType: "application/json",
+					URI:      CapabilitiesResourceURI,
+					Text:     string(capabilities),
+				},
+			}, nil
+		}
+}
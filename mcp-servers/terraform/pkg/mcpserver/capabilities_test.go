@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerCapabilities(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	cfg := Config{
+		Name:    "test-mcp-server",
+		Title:   "Test MCP Server",
+		Version: "0.0.1",
+		Capabilities: func(context.Context, *log.Logger) any {
+			return map[string]any{"read_only": true, "toolsets": map[string]bool{"registry": true}}
+		},
+	}
+	hcServer := NewServer(t.Context(), cfg, logger)
+
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+	response, ok := hcServer.HandleMessage(t.Context(), json.RawMessage(initialize)).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	encoded, err := json.Marshal(response.Result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"read_only":true,"toolsets":{"registry":true}}`, jsonField(t, encoded, "_meta", "test-mcp-server/capabilities"))
+
+	read := `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"server://capabilities"}}`
+	response, ok = hcServer.HandleMessage(t.Context(), json.RawMessage(read)).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok := response.Result.(mcp.ReadResourceResult)
+	require.True(t, ok)
+	require.Len(t, result.Contents, 1)
+	contents, ok := result.Contents[0].(mcp.TextResourceContents)
+	require.True(t, ok)
+	assert.Equal(t, "application/json", contents.MIMEType)
+	assert.JSONEq(t, `{"read_only":true,"toolsets":{"registry":true}}`, contents.Text)
+}
+
+func TestServerWithoutCapabilities(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	hcServer := NewServer(t.Context(), Config{Name: "test-mcp-server", Version: "0.0.1"}, logger)
+
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+	response, ok := hcServer.HandleMessage(t.Context(), json.RawMessage(initialize)).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	encoded, err := json.Marshal(response.Result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "_meta")
+
+	read := `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"server://capabilities"}}`
+	_, isError := hcServer.HandleMessage(t.Context(), json.RawMessage(read)).(mcp.JSONRPCError)
+	assert.True(t, isError)
+}
+
+// jsonField returns the JSON of the field at path in the encoded object
+func jsonField(t *testing.T, encoded []byte, path ...string) string {
+	t.Helper()
+	for _, key := range path {
+		var object map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(encoded, &object))
+		require.Contains(t, object, key)
+		encoded = object[key]
+	}
+	return string(encoded)
+}
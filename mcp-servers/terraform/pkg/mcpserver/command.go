@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	stdlog "log"
+	"os"
+	"slices"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Execute runs the server described by cfg. The transport is selected from the
+// environment first (TRANSPORT_MODE, TRANSPORT_PORT, ...) and then from the command line.
+func Execute(cfg Config) {
+	rootCmd := NewRootCommand(cfg)
+
+	// Check environment variables first - they override command line args.
+	// The doctor, tools, healthcheck and manifest commands do not start a transport, so they are not overridden.
+	offline := len(os.Args) > 1 && slices.Contains([]string{doctorCommand, toolsCommand, healthcheckCommand, manifestCommand}, os.Args[1])
+	if !offline && ShouldUseGRPCMode() {
+		logFile, _ := rootCmd.PersistentFlags().GetString("log-file")
+		logger, err := InitLogger(logFile)
+		if err != nil {
+			stdlog.Fatal("Failed to initialize logger:", err)
+		}
+
+		if err := RunGRPCServer(cfg, logger, GetHTTPHost(), GetGRPCPort(), LoadGRPCTLSConfigFromEnv()); err != nil {
+			stdlog.Fatal("failed to run gRPC server:", err)
+		}
+		return
+	}
+
+	if !offline && ShouldUseStreamableHTTPMode() {
+		port := GetHTTPPort()
+		host := GetHTTPHost()
+		endpointPath := GetEndpointPath(nil)
+
+		logFile, _ := rootCmd.PersistentFlags().GetString("log-file")
+		logger, err := InitLogger(logFile)
+		if err != nil {
+			stdlog.Fatal("Failed to initialize logger:", err)
+		}
+
+		if err := RunHTTPServer(cfg, logger, host, port, endpointPath); err != nil {
+			stdlog.Fatal("failed to run StreamableHTTP server:", err)
+		}
+		return
+	}
+
+	// Fall back to normal CLI behavior
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// NewRootCommand creates the root command with the stdio, streamable-http and grpc subcommands
+func NewRootCommand(cfg Config) *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:     cfg.Name,
+		Short:   cfg.Title,
+		Long:    cfg.Description,
+		Version: cfg.BuildInfo,
+		// Default to stdio mode when no subcommand is provided
+		Run: func(cmd *cobra.Command, _ []string) {
+			runStdioCommand(cfg, cmd)
+		},
+	}
+
+	stdioCmd := &cobra.Command{
+		Use:   "stdio",
+		Short: "Start stdio server",
+		Long:  `Start a server that communicates via standard input/output streams using JSON-RPC messages.`,
+		Run: func(_ *cobra.Command, _ []string) {
+			runStdioCommand(cfg, rootCmd)
+		},
+	}
+
+	streamableHTTPCmd := &cobra.Command{
+		Use:   "streamable-http",
+		Short: "Start StreamableHTTP server",
+		Long:  `Start a server that communicates via StreamableHTTP transport on port 8080 at /mcp endpoint.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			logger := commandLogger(rootCmd)
+
+			port, err := cmd.Flags().GetString("transport-port")
+			if err != nil {
+				stdlog.Fatal("Failed to get streamableHTTP port:", err)
+			}
+			host, err := cmd.Flags().GetString("transport-host")
+			if err != nil {
+				stdlog.Fatal("Failed to get streamableHTTP host:", err)
+			}
+
+			endpointPath, err := cmd.Flags().GetString("mcp-endpoint")
+			if err != nil {
+				stdlog.Fatal("Failed to get endpoint path:", err)
+			}
+
+			sseCompat, err := cmd.Flags().GetBool("sse-compat")
+			if err != nil {
+				stdlog.Fatal("Failed to get SSE compatibility mode:", err)
+			}
+			socketPath, err := cmd.Flags().GetString("transport-socket")
+			if err != nil {
+				stdlog.Fatal("Failed to get streamableHTTP socket:", err)
+			}
+			cfg := cfg
+			cfg.SSECompat = cfg.SSECompat || sseCompat
+			if socketPath != "" {
+				cfg.TransportSocket = socketPath
+			}
+
+			if err := RunHTTPServer(cfg, logger, host, port, endpointPath); err != nil {
+				stdlog.Fatal("failed to run streamableHTTP server:", err)
+			}
+		},
+	}
+
+	// Create an alias for backward compatibility
+	httpCmdAlias := &cobra.Command{
+		Use:        "http",
+		Short:      "Start StreamableHTTP server (deprecated, use 'streamable-http' instead)",
+		Long:       `This command is deprecated. Please use 'streamable-http' instead.`,
+		Deprecated: "Use 'streamable-http' instead",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Forward to the new command
+			streamableHTTPCmd.Run(cmd, args)
+		},
+	}
+
+	grpcCmd := &cobra.Command{
+		Use:   "grpc",
+		Short: "Start gRPC server",
+		Long:  `Start a server that exposes the MCP protocol over gRPC on port 9090, with optional TLS and mutual TLS.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			logger := commandLogger(rootCmd)
+
+			port, err := cmd.Flags().GetString("transport-port")
+			if err != nil {
+				stdlog.Fatal("Failed to get gRPC port:", err)
+			}
+			host, err := cmd.Flags().GetString("transport-host")
+			if err != nil {
+				stdlog.Fatal("Failed to get gRPC host:", err)
+			}
+
+			// Flags take precedence over the environment
+			tlsConfig := LoadGRPCTLSConfigFromEnv()
+			for flag, value := range map[string]*string{
+				"tls-cert-file":  &tlsConfig.CertFile,
+				"tls-key-file":   &tlsConfig.KeyFile,
+				"client-ca-file": &tlsConfig.ClientCAFile,
+			} {
+				if cmd.Flags().Changed(flag) {
+					*value, _ = cmd.Flags().GetString(flag)
+				}
+			}
+
+			if err := RunGRPCServer(cfg, logger, host, port, tlsConfig); err != nil {
+				stdlog.Fatal("failed to run gRPC server:", err)
+			}
+		},
+	}
+	doctorCmd := &cobra.Command{
+		Use:   doctorCommand,
+		Short: "Check the configuration and the dependencies of the server",
+		Long:  `Check the transport environment variables, the connectivity to the upstream services and the credentials and certificates of the server, and print a readiness report. Exits non-zero when a check fails.`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			logger := commandLogger(rootCmd)
+			// The report tells what failed, the logs of the HTTP clients only repeat it
+			if os.Getenv(LogLevel) == "" {
+				logger.SetLevel(log.WarnLevel)
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), doctorTimeout)
+			defer cancel()
+			os.Exit(runDoctor(ctx, cfg, logger, cmd.OutOrStdout()))
+		},
+	}
+
+	grpcCmd.Flags().String("transport-host", "127.0.0.1", "Host to bind to")
+	grpcCmd.Flags().StringP("transport-port", "p", "9090", "Port to listen on")
+	grpcCmd.Flags().String("tls-cert-file", "", "Server certificate file, enables TLS")
+	grpcCmd.Flags().String("tls-key-file", "", "Server private key file")
+	grpcCmd.Flags().String("client-ca-file", "", "CA bundle to verify client certificates, enables mutual TLS")
+
+	cobra.OnInitialize(initConfig)
+	rootCmd.SetVersionTemplate("{{.Short}}\n{{.Version}}\n")
+	rootCmd.PersistentFlags().String("log-file", "", "Path to log file")
+	if cfg.SelfTest != nil {
+		rootCmd.PersistentFlags().Bool("selftest", false, "Check the upstream APIs for schema mismatches and exit")
+		rootCmd.PersistentPreRun = func(cmd *cobra.Command, _ []string) {
+			if selfTest, _ := cmd.Flags().GetBool("selftest"); selfTest {
+				os.Exit(runSelfTest(cfg, cmd.Root()))
+			}
+		}
+	}
+
+	// Add StreamableHTTP command flags (avoid 'h' shorthand conflict with help)
+	// The same flags are added to the alias command for backward compatibility
+	for _, cmd := range []*cobra.Command{streamableHTTPCmd, httpCmdAlias} {
+		cmd.Flags().String("transport-host", "127.0.0.1", "Host to bind to")
+		cmd.Flags().StringP("transport-port", "p", "8080", "Port to listen on")
+		cmd.Flags().String("mcp-endpoint", "/mcp", "Path for streamable HTTP endpoint")
+		cmd.Flags().Bool("sse-compat", false, "Also serve the legacy HTTP+SSE transport at /sse and /message")
+		cmd.Flags().String("transport-socket", "", "Unix domain socket to listen on instead of host and port")
+	}
+
+	rootCmd.AddCommand(stdioCmd)
+	rootCmd.AddCommand(streamableHTTPCmd)
+	rootCmd.AddCommand(httpCmdAlias) // Add the alias for backward compatibility
+	rootCmd.AddCommand(grpcCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(newToolsCommand(cfg, rootCmd))
+	rootCmd.AddCommand(newHealthcheckCommand())
+	rootCmd.AddCommand(newManifestCommand(cfg))
+
+	return rootCmd
+}
+
+func initConfig() {
+	viper.AutomaticEnv()
+}
+
+func runStdioCommand(cfg Config, rootCmd *cobra.Command) {
+	logger := commandLogger(rootCmd)
+	if err := RunStdioServer(cfg, logger); err != nil {
+		stdlog.Fatal("failed to run stdio server:", err)
+	}
+}
+
+// doctorTimeout bounds the checks of the doctor command
+const doctorTimeout = time.Minute
+
+// selfTestTimeout bounds the calls of the self-test, so that an unreachable upstream fails it
+const selfTestTimeout = time.Minute
+
+// runSelfTest runs the self-test of the server and returns the exit code of the process
+func runSelfTest(cfg Config, rootCmd *cobra.Command) int {
+	logger := commandLogger(rootCmd)
+	ctx, cancel := context.WithTimeout(context.Background(), selfTestTimeout)
+	defer cancel()
+	if err := cfg.SelfTest(ctx, logger, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "Self-test failed:", err)
+		return 1
+	}
+	fmt.Println("Self-test passed")
+	return 0
+}
+
+func commandLogger(rootCmd *cobra.Command) *log.Logger {
+	logFile, err := rootCmd.PersistentFlags().GetString("log-file")
+	if err != nil {
+		stdlog.Fatal("Failed to get log file:", err)
+	}
+	logger, err := InitLogger(logFile)
+	if err != nil {
+		stdlog.Fatal("Failed to initialize logger:", err)
+	}
+	return logger
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ShouldUseStreamableHTTPMode checks if environment variables indicate HTTP mode
+func ShouldUseStreamableHTTPMode() bool {
+	transportMode := os.Getenv("TRANSPORT_MODE")
+	return transportMode == "http" || transportMode == "streamable-http" ||
+		os.Getenv("TRANSPORT_PORT") != "" ||
+		os.Getenv("TRANSPORT_HOST") != "" ||
+		os.Getenv("TRANSPORT_SOCKET") != "" ||
+		os.Getenv("MCP_ENDPOINT") != ""
+}
+
+// GetHTTPSocket returns the Unix domain socket path from environment variables, empty to listen on TCP
+func GetHTTPSocket() string {
+	return os.Getenv("TRANSPORT_SOCKET")
+}
+
+// GetHTTPSocketMode returns the file mode of the Unix domain socket from TRANSPORT_SOCKET_MODE, e.g. "0600"
+func GetHTTPSocketMode() os.FileMode {
+	if mode := os.Getenv("TRANSPORT_SOCKET_MODE"); mode != "" {
+		if value, err := strconv.ParseUint(mode, 8, 32); err == nil && value <= 0o777 {
+			return os.FileMode(value)
+		}
+	}
+	return 0o660
+}
+
+// ShouldUseGRPCMode checks if the TRANSPORT_MODE environment variable selects the gRPC transport
+func ShouldUseGRPCMode() bool {
+	return os.Getenv("TRANSPORT_MODE") == "grpc"
+}
+
+// GetGRPCPort returns the gRPC port from environment variables or default
+func GetGRPCPort() string {
+	if port := os.Getenv("TRANSPORT_PORT"); port != "" {
+		return port
+	}
+	return "9090"
+}
+
+// ShouldUseStatelessMode returns true if the MCP_SESSION_MODE environment variable is set to "stateless"
+func ShouldUseStatelessMode() bool {
+	mode := strings.ToLower(os.Getenv("MCP_SESSION_MODE"))
+
+	// Explicitly check for "stateless" value
+	if mode == "stateless" {
+		return true
+	}
+
+	// All other values (including empty string, "stateful", or any other value) default to stateful mode
+	return false
+}
+
+// ShouldUseSSECompat returns true if the MCP_SSE_COMPAT environment variable enables the legacy HTTP+SSE endpoints
+func ShouldUseSSECompat() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("MCP_SSE_COMPAT"))
+	return err == nil && enabled
+}
+
+// GetHTTPPort returns the port from environment variables or default
+func GetHTTPPort() string {
+	if port := os.Getenv("TRANSPORT_PORT"); port != "" {
+		return port
+	}
+	return "8080"
+}
+
+// GetHTTPHost returns the host from environment variables or default
+func GetHTTPHost() string {
+	if host := os.Getenv("TRANSPORT_HOST"); host != "" {
+		return host
+	}
+	return "127.0.0.1"
+}
+
+// GetEndpointPath returns the endpoint path from the environment or the mcp-endpoint flag
+func GetEndpointPath(cmd *cobra.Command) string {
+	// First check environment variable
+	if envPath := os.Getenv("MCP_ENDPOINT"); envPath != "" {
+		return envPath
+	}
+
+	// Fall back to command line flag
+	if cmd != nil {
+		if path, err := cmd.Flags().GetString("mcp-endpoint"); err == nil && path != "" {
+			return path
+		}
+	}
+
+	return "/mcp"
+}
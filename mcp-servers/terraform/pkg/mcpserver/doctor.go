@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	log "github.com/sirupsen/logrus"
+)
+
+// doctorCommand is the name of the command reporting on the configuration of the server
+const doctorCommand = "doctor"
+
+// CheckStatus is the outcome of a preflight check
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "OK"
+	CheckWarn CheckStatus = "WARN"
+	CheckFail CheckStatus = "FAIL"
+)
+
+// Check is a preflight check of the doctor command. A failed check means the server will not work
+// as configured, a warning that a setting is ignored or likely not what was intended.
+type Check struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+}
+
+func okCheck(name, detail string) Check {
+	return Check{Name: name, Status: CheckOK, Detail: detail}
+}
+
+func warnCheck(name, format string, args ...any) Check {
+	return Check{Name: name, Status: CheckWarn, Detail: fmt.Sprintf(format, args...)}
+}
+
+func failCheck(name, format string, args ...any) Check {
+	return Check{Name: name, Status: CheckFail, Detail: fmt.Sprintf(format, args...)}
+}
+
+// transportModes are the accepted values of TRANSPORT_MODE, empty selects stdio unless other TRANSPORT_* variables are set
+var transportModes = []string{"", "stdio", "http", "streamable-http", "grpc"}
+
+// transportChecks checks the transport environment variables, which override the subcommands and their flags
+func transportChecks() []Check {
+	mode := os.Getenv("TRANSPORT_MODE")
+	if !slices.Contains(transportModes, mode) {
+		return []Check{failCheck("transport", "TRANSPORT_MODE %q is not one of stdio, streamable-http or grpc", mode)}
+	}
+
+	var checks []Check
+	httpVariables := setVariables("TRANSPORT_PORT", "TRANSPORT_HOST", "TRANSPORT_SOCKET", "MCP_ENDPOINT")
+	switch {
+	case ShouldUseGRPCMode():
+		checks = append(checks, okCheck("transport", fmt.Sprintf("gRPC on %s:%s, selected by TRANSPORT_MODE; the subcommands and their flags are ignored", GetHTTPHost(), GetGRPCPort())))
+		if ignored := setVariables("TRANSPORT_SOCKET", "MCP_ENDPOINT", "MCP_SSE_COMPAT", "MCP_SESSION_MODE", "MCP_WEBHOOK_TOKEN"); len(ignored) > 0 {
+			checks = append(checks, warnCheck("transport", "gRPC ignores the StreamableHTTP settings %s", strings.Join(ignored, ", ")))
+		}
+	case ShouldUseStreamableHTTPMode():
+		address := GetHTTPHost() + ":" + GetHTTPPort()
+		if GetHTTPSocket() != "" {
+			address = "unix:" + GetHTTPSocket()
+		}
+		checks = append(checks, okCheck("transport", fmt.Sprintf("StreamableHTTP on %s%s, selected by the environment; the subcommands and their flags are ignored", address, GetEndpointPath(nil))))
+		if mode == "stdio" {
+			checks = append(checks, failCheck("transport", "TRANSPORT_MODE is stdio but StreamableHTTP is selected by %s", strings.Join(httpVariables, ", ")))
+		}
+		if GetHTTPSocket() != "" {
+			if ignored := setVariables("TRANSPORT_HOST", "TRANSPORT_PORT"); len(ignored) > 0 {
+				checks = append(checks, warnCheck("transport", "TRANSPORT_SOCKET is set, the server ignores %s", strings.Join(ignored, ", ")))
+			}
+		}
+	default:
+		checks = append(checks, okCheck("transport", "selected by the subcommand, stdio by default"))
+	}
+
+	if port := os.Getenv("TRANSPORT_PORT"); port != "" {
+		if value, err := strconv.Atoi(port); err != nil || value < 1 || value > 65535 {
+			checks = append(checks, failCheck("transport", "TRANSPORT_PORT %q is not a port number", port))
+		}
+	}
+	if endpoint := os.Getenv("MCP_ENDPOINT"); endpoint != "" && !strings.HasPrefix(endpoint, "/") {
+		checks = append(checks, failCheck("transport", "MCP_ENDPOINT %q must start with /", endpoint))
+	}
+	if mode := os.Getenv("TRANSPORT_SOCKET_MODE"); mode != "" {
+		if value, err := strconv.ParseUint(mode, 8, 32); err != nil || value > 0o777 {
+			checks = append(checks, warnCheck("transport", "TRANSPORT_SOCKET_MODE %q is not an octal file mode, using 0660", mode))
+		}
+	}
+	if sessionMode := strings.ToLower(os.Getenv("MCP_SESSION_MODE")); sessionMode != "" && sessionMode != "stateless" && sessionMode != "stateful" {
+		checks = append(checks, warnCheck("transport", "MCP_SESSION_MODE %q is neither stateless nor stateful, using stateful", os.Getenv("MCP_SESSION_MODE")))
+	}
+	if value := os.Getenv("MCP_SSE_COMPAT"); value != "" {
+		if _, err := strconv.ParseBool(value); err != nil {
+			checks = append(checks, warnCheck("transport", "MCP_SSE_COMPAT %q is not a boolean, the SSE endpoints are disabled", value))
+		}
+	}
+	if _, err := LoadGRPCTLSConfigFromEnv().credentials(); err != nil {
+		checks = append(checks, failCheck("grpc tls", "%v", err))
+	}
+	return checks
+}
+
+// notificationChecks checks the tool notifications, an invalid configuration stops the server
+func notificationChecks() []Check {
+	config, err := client.LoadToolNotificationConfigFromEnv()
+	if err != nil {
+		return []Check{failCheck("notifications", "%v", err)}
+	}
+	if !config.Enabled() {
+		return nil
+	}
+	tools := "the destructive tools"
+	if len(config.Tools) > 0 {
+		tools = strings.Join(config.Tools, ", ")
+	}
+	return []Check{okCheck("notifications", fmt.Sprintf("posting the calls of %s in %s format", tools, config.Format))}
+}
+
+// setVariables returns the environment variables of names that are set
+func setVariables(names ...string) []string {
+	var set []string
+	for _, name := range names {
+		if os.Getenv(name) != "" {
+			set = append(set, name)
+		}
+	}
+	return set
+}
+
+// probeChecks runs the readiness probes of the server concurrently
+func probeChecks(ctx context.Context, probes []Probe) []Check {
+	checks := make([]Check, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+			defer cancel()
+			if err := probe.Check(probeCtx); err != nil {
+				checks[i] = failCheck(probe.Name, "unreachable: %v", err)
+				return
+			}
+			checks[i] = okCheck(probe.Name, "reachable")
+		}()
+	}
+	wg.Wait()
+	return checks
+}
+
+// writeDoctorReport prints the checks, one line each with its detail indented below, and
+// reports whether none of them failed
+func writeDoctorReport(out io.Writer, checks []Check) bool {
+	failed := 0
+	for _, check := range checks {
+		fmt.Fprintf(out, "%-4s %s\n", check.Status, check.Name)
+		if check.Detail != "" {
+			fmt.Fprintf(out, "     %s\n", check.Detail)
+		}
+		if check.Status == CheckFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(out, "\nNot ready: %d of %d checks failed\n", failed, len(checks))
+		return false
+	}
+	fmt.Fprintln(out, "\nReady")
+	return true
+}
+
+// runDoctor checks the configuration and the dependencies of the server, and returns the exit code of the process
+func runDoctor(ctx context.Context, cfg Config, logger *log.Logger, out io.Writer) int {
+	checks := transportChecks()
+	checks = append(checks, notificationChecks()...)
+	checks = append(checks, probeChecks(ctx, readinessProbes(cfg, logger))...)
+	if cfg.Preflight != nil {
+		checks = append(checks, cfg.Preflight(ctx, logger)...)
+	}
+	if !writeDoctorReport(out, checks) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// clearTransportEnv unsets the transport environment variables for the duration of the test
+func clearTransportEnv(t *testing.T) {
+	for _, name := range []string{"TRANSPORT_MODE", "TRANSPORT_PORT", "TRANSPORT_HOST", "TRANSPORT_SOCKET", "TRANSPORT_SOCKET_MODE",
+		"MCP_ENDPOINT", "MCP_SESSION_MODE", "MCP_SSE_COMPAT", "MCP_GRPC_TLS_CERT_FILE", "MCP_GRPC_TLS_KEY_FILE", "MCP_GRPC_CLIENT_CA_FILE"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestTransportChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected []Check
+	}{
+		{
+			name:     "stdio",
+			expected: []Check{okCheck("transport", "selected by the subcommand, stdio by default")},
+		},
+		{
+			name: "streamable-http",
+			env:  map[string]string{"TRANSPORT_MODE": "streamable-http", "TRANSPORT_PORT": "9000", "MCP_SESSION_MODE": "stateles"},
+			expected: []Check{
+				okCheck("transport", "StreamableHTTP on 127.0.0.1:9000/mcp, selected by the environment; the subcommands and their flags are ignored"),
+				warnCheck("transport", `MCP_SESSION_MODE "stateles" is neither stateless nor stateful, using stateful`),
+			},
+		},
+		{
+			name: "stdio overridden by TRANSPORT_PORT",
+			env:  map[string]string{"TRANSPORT_MODE": "stdio", "TRANSPORT_PORT": "http"},
+			expected: []Check{
+				okCheck("transport", "StreamableHTTP on 127.0.0.1:http/mcp, selected by the environment; the subcommands and their flags are ignored"),
+				failCheck("transport", "TRANSPORT_MODE is stdio but StreamableHTTP is selected by TRANSPORT_PORT"),
+				failCheck("transport", `TRANSPORT_PORT "http" is not a port number`),
+			},
+		},
+		{
+			name: "socket",
+			env:  map[string]string{"TRANSPORT_SOCKET": "/run/mcp.sock", "TRANSPORT_HOST": "0.0.0.0", "MCP_ENDPOINT": "mcp"},
+			expected: []Check{
+				okCheck("transport", "StreamableHTTP on unix:/run/mcp.sockmcp, selected by the environment; the subcommands and their flags are ignored"),
+				warnCheck("transport", "TRANSPORT_SOCKET is set, the server ignores TRANSPORT_HOST"),
+				failCheck("transport", `MCP_ENDPOINT "mcp" must start with /`),
+			},
+		},
+		{
+			name: "grpc",
+			env:  map[string]string{"TRANSPORT_MODE": "grpc", "MCP_SSE_COMPAT": "yes", "MCP_GRPC_CLIENT_CA_FILE": "/etc/ca.pem"},
+			expected: []Check{
+				okCheck("transport", "gRPC on 127.0.0.1:9090, selected by TRANSPORT_MODE; the subcommands and their flags are ignored"),
+				warnCheck("transport", "gRPC ignores the StreamableHTTP settings MCP_SSE_COMPAT"),
+				warnCheck("transport", `MCP_SSE_COMPAT "yes" is not a boolean, the SSE endpoints are disabled`),
+				failCheck("grpc tls", "MCP_GRPC_CLIENT_CA_FILE requires MCP_GRPC_TLS_CERT_FILE and MCP_GRPC_TLS_KEY_FILE"),
+			},
+		},
+		{
+			name:     "unknown mode",
+			env:      map[string]string{"TRANSPORT_MODE": "sse"},
+			expected: []Check{failCheck("transport", `TRANSPORT_MODE "sse" is not one of stdio, streamable-http or grpc`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTransportEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			assert.Equal(t, tt.expected, transportChecks())
+		})
+	}
+}
+
+func TestRunDoctor(t *testing.T) {
+	clearTransportEnv(t)
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	probes := []Probe{
+		{Name: "registry", Check: func(context.Context) error { return nil }},
+		{Name: "tfe", Check: func(context.Context) error { return errors.New("connection refused") }},
+	}
+	cfg := Config{
+		ReadinessProbes: func(*log.Logger) []Probe { return probes },
+		Preflight: func(context.Context, *log.Logger) []Check {
+			return []Check{warnCheck("tfe token", "not verified")}
+		},
+	}
+
+	var out strings.Builder
+	assert.Equal(t, 1, runDoctor(context.Background(), cfg, logger, &out))
+	assert.Equal(t, `OK   transport
+     selected by the subcommand, stdio by default
+OK   registry
+     reachable
+FAIL tfe
+     unreachable: connection refused
+WARN tfe token
+     not verified
+
+Not ready: 1 of 4 checks failed
+`, out.String())
+
+	// Warnings do not fail the readiness report
+	probes = probes[:1]
+	out.Reset()
+	assert.Equal(t, 0, runDoctor(context.Background(), cfg, logger, &out))
+	assert.True(t, strings.HasSuffix(out.String(), "\nReady\n"))
+}
@@ -0,0 +1,393 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// grpcServiceName is the name of the MCP service described in mcp.proto
+const grpcServiceName = "mcp.v1.MCP"
+
+// GRPCTLSConfig holds the certificate files of the gRPC transport
+type GRPCTLSConfig struct {
+	CertFile     string // Server certificate
+	KeyFile      string // Server private key
+	ClientCAFile string // CA bundle used to verify client certificates, which enables mutual TLS
+}
+
+// LoadGRPCTLSConfigFromEnv loads the gRPC TLS configuration from environment variables
+func LoadGRPCTLSConfigFromEnv() GRPCTLSConfig {
+	return GRPCTLSConfig{
+		CertFile:     os.Getenv("MCP_GRPC_TLS_CERT_FILE"),
+		KeyFile:      os.Getenv("MCP_GRPC_TLS_KEY_FILE"),
+		ClientCAFile: os.Getenv("MCP_GRPC_CLIENT_CA_FILE"),
+	}
+}
+
+// credentials returns the transport credentials, or nil when TLS is not configured
+func (c GRPCTLSConfig) credentials() (credentials.TransportCredentials, error) {
+	if c.CertFile == "" && c.KeyFile == "" {
+		if c.ClientCAFile != "" {
+			return nil, fmt.Errorf("MCP_GRPC_CLIENT_CA_FILE requires MCP_GRPC_TLS_CERT_FILE and MCP_GRPC_TLS_KEY_FILE")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading gRPC server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading gRPC client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in gRPC client CA bundle %s", c.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// RunGRPCServer runs the server on the gRPC transport until it receives SIGINT or SIGTERM
+func RunGRPCServer(cfg Config, logger *log.Logger, host string, port string, tlsConfig GRPCTLSConfig) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	withTransportField(logger, "grpc")
+	if err := startConfigReload(ctx, logger); err != nil {
+		return err
+	}
+	if err := client.StartRegistryCacheRefresh(ctx, logger); err != nil {
+		return err
+	}
+	defer client.FlushRegistryCache()
+	addr := net.JoinHostPort(host, port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("gRPC server error: %w", err)
+	}
+	return ServeGRPC(ctx, cfg, NewServer(ctx, cfg, logger), logger, listener, tlsConfig)
+}
+
+// ServeGRPC serves hcServer on the gRPC transport until ctx is done
+func ServeGRPC(ctx context.Context, cfg Config, hcServer *server.MCPServer, logger *log.Logger, listener net.Listener, tlsConfig GRPCTLSConfig) error {
+	grpcServer, err := NewGRPCServer(cfg, hcServer, logger, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	// Start server in goroutine
+	errC := make(chan error, 1)
+	go func() {
+		logger.Infof("Starting gRPC server on %s", listener.Addr())
+		errC <- grpcServer.Serve(listener)
+	}()
+
+	// Wait for shutdown signal
+	select {
+	case <-ctx.Done():
+		logger.Infof("Shutting down gRPC server...")
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			grpcServer.Stop()
+		}
+		return nil
+	case err := <-errC:
+		if err != nil && err != grpc.ErrServerStopped {
+			return fmt.Errorf("gRPC server error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// NewGRPCServer creates the gRPC server exposing hcServer as the MCP service, with the
+// standard gRPC health service for load balancers. Message sizes are limited like HTTP
+// request bodies and the context middleware reads its headers from the gRPC metadata.
+func NewGRPCServer(cfg Config, hcServer *server.MCPServer, logger *log.Logger, tlsConfig GRPCTLSConfig) (*grpc.Server, error) {
+	inputLimits := client.LoadInputLimitsConfigFromEnv()
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(int(inputLimits.MaxBodyBytes)),
+	}
+
+	creds, err := tlsConfig.credentials()
+	if err != nil {
+		return nil, err
+	}
+	if creds != nil {
+		opts = append(opts, grpc.Creds(creds))
+		logger.Infof("gRPC TLS enabled, mutual TLS: %v", tlsConfig.ClientCAFile != "")
+	} else {
+		logger.Warnf("gRPC TLS is not configured, serving plaintext. This is not recommended for production.")
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	service := &grpcService{hcServer: hcServer, logger: logger}
+	if cfg.ContextMiddleware != nil {
+		service.contextMiddleware = cfg.ContextMiddleware(logger)
+	}
+	grpcServer.RegisterService(&grpcServiceDesc, service)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(grpcServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	return grpcServer, nil
+}
+
+// mcpGRPCService is the handler type of the MCP service
+type mcpGRPCService interface {
+	call(ctx context.Context, message []byte) ([]byte, error)
+	session(stream grpc.ServerStream) error
+}
+
+// grpcServiceDesc describes the service in mcp.proto. Messages are wrapped in the
+// well-known BytesValue type, so no generated code is needed on either side.
+var grpcServiceDesc = grpc.ServiceDesc{
+	ServiceName: grpcServiceName,
+	HandlerType: (*mcpGRPCService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Call",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := &wrapperspb.BytesValue{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				out, err := srv.(mcpGRPCService).call(ctx, in.GetValue())
+				if err != nil {
+					return nil, err
+				}
+				return wrapperspb.Bytes(out), nil
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Session",
+			ServerStreams: true,
+			ClientStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				return srv.(mcpGRPCService).session(stream)
+			},
+		},
+	},
+	Metadata: "mcp.proto",
+}
+
+type grpcService struct {
+	hcServer          *server.MCPServer
+	contextMiddleware func(http.Handler) http.Handler
+	logger            *log.Logger
+}
+
+// call handles a single message in a session that is registered for the duration of the call
+func (s *grpcService) call(ctx context.Context, message []byte) ([]byte, error) {
+	ctx, err := s.requestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	session := newGRPCSession()
+	if err := s.hcServer.RegisterSession(ctx, session); err != nil {
+		return nil, status.Errorf(codes.Internal, "registering session: %v", err)
+	}
+	defer s.hcServer.UnregisterSession(ctx, session.SessionID())
+
+	response := s.hcServer.HandleMessage(s.hcServer.WithContext(ctx, session), message)
+	if response == nil {
+		return []byte{}, nil
+	}
+	return json.Marshal(response)
+}
+
+// session serves a long-lived MCP session. Messages are handled concurrently like on the
+// StreamableHTTP transport, so a slow tool call does not block the rest of the session.
+func (s *grpcService) session(stream grpc.ServerStream) error {
+	ctx, err := s.requestContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	session := newGRPCSession()
+	if err := s.hcServer.RegisterSession(ctx, session); err != nil {
+		return status.Errorf(codes.Internal, "registering session: %v", err)
+	}
+	defer s.hcServer.UnregisterSession(ctx, session.SessionID())
+	ctx = s.hcServer.WithContext(ctx, session)
+	s.logger.Debugf("Started gRPC session %s", session.SessionID())
+
+	var sendMu sync.Mutex
+	send := func(message any) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			s.logger.Errorf("Failed to marshal gRPC message: %v", err)
+			return
+		}
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		if err := stream.SendMsg(wrapperspb.Bytes(data)); err != nil {
+			s.logger.Debugf("Failed to send gRPC message: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		for {
+			select {
+			case notification := <-session.notifications:
+				send(notification)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		in := &wrapperspb.BytesValue{}
+		if err := stream.RecvMsg(in); err != nil {
+			// The client closing its side of the stream ends the session after the pending messages
+			if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if response := s.hcServer.HandleMessage(ctx, in.GetValue()); response != nil {
+				send(response)
+			}
+		}()
+	}
+}
+
+// requestContext runs the context middleware of the server on the gRPC metadata, so that
+// credentials are passed the same way as the HTTP headers of the StreamableHTTP transport
+func (s *grpcService) requestContext(ctx context.Context) (context.Context, error) {
+	if ids := metadata.ValueFromIncomingContext(ctx, client.RequestIDHeader); len(ids) > 0 && client.ValidRequestID(ids[0]) {
+		ctx = client.ContextWithRequestID(ctx, ids[0])
+	}
+	if s.contextMiddleware == nil {
+		return ctx, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "creating request context: %v", err)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		// Skip pseudo headers such as :authority
+		if strings.HasPrefix(key, ":") {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	var requestCtx context.Context
+	recorder := &grpcResponseRecorder{header: http.Header{}}
+	s.contextMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		requestCtx = r.Context()
+	})).ServeHTTP(recorder, req)
+
+	if requestCtx == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "request rejected: %s", strings.TrimSpace(recorder.body.String()))
+	}
+	return requestCtx, nil
+}
+
+// grpcResponseRecorder captures the response of a context middleware that rejects a request
+type grpcResponseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *grpcResponseRecorder) Header() http.Header         { return r.header }
+func (r *grpcResponseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *grpcResponseRecorder) WriteHeader(int)             {}
+
+// grpcSession is the MCP client session of a gRPC call or stream
+type grpcSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+	initialized   atomic.Bool
+	logLevel      atomic.Value
+}
+
+func newGRPCSession() *grpcSession {
+	return &grpcSession{
+		id:            "grpc-" + uuid.NewString(),
+		notifications: make(chan mcp.JSONRPCNotification, 100),
+	}
+}
+
+func (s *grpcSession) SessionID() string { return s.id }
+
+func (s *grpcSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+
+func (s *grpcSession) Initialize() { s.initialized.Store(true) }
+
+func (s *grpcSession) Initialized() bool { return s.initialized.Load() }
+
+func (s *grpcSession) SetLogLevel(level mcp.LoggingLevel) { s.logLevel.Store(level) }
+
+func (s *grpcSession) GetLogLevel() mcp.LoggingLevel {
+	if level, ok := s.logLevel.Load().(mcp.LoggingLevel); ok {
+		return level
+	}
+	return mcp.LoggingLevelError
+}
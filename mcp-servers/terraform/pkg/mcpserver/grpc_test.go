@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type tenantKey struct{}
+
+// startGRPCServer serves a server with a whoami tool returning the X-Tenant header passed through the context middleware
+func startGRPCServer(t *testing.T) *grpc.ClientConn {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	cfg := Config{
+		Name:    "test-mcp-server",
+		Version: "0.0.1",
+		Register: func(hcServer *server.MCPServer, _ *log.Logger) {
+			hcServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				tenant, _ := ctx.Value(tenantKey{}).(string)
+				return mcp.NewToolResultText(tenant), nil
+			})
+		},
+		ContextMiddleware: func(_ *log.Logger) func(http.Handler) http.Handler {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("X-Tenant") == "rejected" {
+						http.Error(w, "tenant not allowed", http.StatusBadRequest)
+						return
+					}
+					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantKey{}, r.Header.Get("X-Tenant"))))
+				})
+			}
+		},
+	}
+
+	grpcServer, err := NewGRPCServer(cfg, NewServer(t.Context(), cfg, logger), logger, GRPCTLSConfig{})
+	require.NoError(t, err)
+	listener := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func toolText(t *testing.T, message []byte) string {
+	var response struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(message, &response))
+	require.Len(t, response.Result.Content, 1)
+	return response.Result.Content[0].Text
+}
+
+const whoamiCall = `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"whoami"}}`
+
+func TestGRPCCall(t *testing.T) {
+	conn := startGRPCServer(t)
+	ctx := metadata.AppendToOutgoingContext(t.Context(), "x-tenant", "platform")
+
+	out := &wrapperspb.BytesValue{}
+	err := conn.Invoke(ctx, "/mcp.v1.MCP/Call", wrapperspb.Bytes([]byte(whoamiCall)), out)
+	require.NoError(t, err)
+	assert.Equal(t, "platform", toolText(t, out.GetValue()))
+
+	// A request rejected by the context middleware fails with InvalidArgument
+	ctx = metadata.AppendToOutgoingContext(t.Context(), "x-tenant", "rejected")
+	err = conn.Invoke(ctx, "/mcp.v1.MCP/Call", wrapperspb.Bytes([]byte(whoamiCall)), out)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "tenant not allowed")
+}
+
+func TestGRPCSession(t *testing.T) {
+	conn := startGRPCServer(t)
+	ctx := metadata.AppendToOutgoingContext(t.Context(), "x-tenant", "platform")
+
+	stream, err := conn.NewStream(ctx, &grpcServiceDesc.Streams[0], "/mcp.v1.MCP/Session")
+	require.NoError(t, err)
+
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+	require.NoError(t, stream.SendMsg(wrapperspb.Bytes([]byte(initialize))))
+	out := &wrapperspb.BytesValue{}
+	require.NoError(t, stream.RecvMsg(out))
+	assert.Contains(t, string(out.GetValue()), `"serverInfo"`)
+
+	require.NoError(t, stream.SendMsg(wrapperspb.Bytes([]byte(whoamiCall))))
+	require.NoError(t, stream.RecvMsg(out))
+	assert.Equal(t, "platform", toolText(t, out.GetValue()))
+
+	require.NoError(t, stream.CloseSend())
+}
+
+func TestGRPCHealth(t *testing.T) {
+	conn := startGRPCServer(t)
+
+	response, err := healthpb.NewHealthClient(conn).Check(t.Context(), &healthpb.HealthCheckRequest{Service: grpcServiceName})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.GetStatus())
+}
+
+func TestGRPCTLSConfig(t *testing.T) {
+	creds, err := GRPCTLSConfig{}.credentials()
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+
+	_, err = GRPCTLSConfig{ClientCAFile: "ca.pem"}.credentials()
+	assert.ErrorContains(t, err, "requires MCP_GRPC_TLS_CERT_FILE")
+
+	_, err = GRPCTLSConfig{CertFile: "missing.pem", KeyFile: "missing-key.pem"}.credentials()
+	assert.ErrorContains(t, err, "loading gRPC server certificate")
+}
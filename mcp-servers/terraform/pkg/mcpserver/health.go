@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultReadinessCacheTTL is how long probe results are reused before the dependencies are probed again
+	defaultReadinessCacheTTL = 30 * time.Second
+	// probeTimeout bounds a single dependency probe
+	probeTimeout = 5 * time.Second
+)
+
+// Probe checks that a dependency of the server, e.g. the Terraform registry, is available
+type Probe struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// ProbeResult is the outcome of a single dependency probe
+type ProbeResult struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMs int64     `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// ReadinessReport is the body returned by the readiness endpoint
+type ReadinessReport struct {
+	Status       string                 `json:"status"`
+	Service      string                 `json:"service"`
+	Dependencies map[string]ProbeResult `json:"dependencies"`
+}
+
+// HTTPProbe creates a probe that succeeds when a GET request to url returns a status below 500. The request
+// is sent with httpClient, which should be configured like the client of the dependency, e.g. with its proxy
+// and certificate authorities, so that the probe fails when the server cannot reach it.
+func HTTPProbe(name string, url string, httpClient *http.Client) Probe {
+	return Probe{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("%s returned %s", url, resp.Status)
+			}
+			return nil
+		},
+	}
+}
+
+// readinessProbes returns the readiness probes of the server, if any
+func readinessProbes(cfg Config, logger *log.Logger) []Probe {
+	if cfg.ReadinessProbes == nil {
+		return nil
+	}
+	return cfg.ReadinessProbes(logger)
+}
+
+// readinessChecker runs the dependency probes and caches their results so that
+// frequent readiness checks do not put load on the dependencies
+type readinessChecker struct {
+	probes []Probe
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	results   map[string]ProbeResult
+	checkedAt time.Time
+}
+
+func newReadinessChecker(probes []Probe, ttl time.Duration) *readinessChecker {
+	return &readinessChecker{
+		probes: probes,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// check returns the cached probe results, probing the dependencies again once the cache expired
+func (c *readinessChecker) check(ctx context.Context) (map[string]ProbeResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.results == nil || c.now().Sub(c.checkedAt) >= c.ttl {
+		// The results are shared between requests, so a client disconnecting must not fail the probes
+		c.results = c.probe(context.WithoutCancel(ctx))
+		c.checkedAt = c.now()
+	}
+
+	ready := true
+	for _, result := range c.results {
+		if result.Status != "ok" {
+			ready = false
+		}
+	}
+	return c.results, ready
+}
+
+// probe runs all probes concurrently
+func (c *readinessChecker) probe(ctx context.Context) map[string]ProbeResult {
+	results := make(map[string]ProbeResult, len(c.probes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, probe := range c.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+			defer cancel()
+
+			start := c.now()
+			err := probe.Check(probeCtx)
+			result := ProbeResult{
+				Status:    "ok",
+				LatencyMs: c.now().Sub(start).Milliseconds(),
+				CheckedAt: start.UTC(),
+			}
+			if err != nil {
+				result.Status = "error"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[probe.Name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// readinessCacheTTL returns the cache duration from MCP_READINESS_CACHE_TTL, e.g. "1m"
+func readinessCacheTTL(logger *log.Logger) time.Duration {
+	value := os.Getenv("MCP_READINESS_CACHE_TTL")
+	if value == "" {
+		return defaultReadinessCacheTTL
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		logger.Warnf("Invalid MCP_READINESS_CACHE_TTL %q, using %s", value, defaultReadinessCacheTTL)
+		return defaultReadinessCacheTTL
+	}
+	return ttl
+}
+
+// livenessHandler reports that the process is up, without checking any dependency
+func livenessHandler(cfg Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf(`{"status":"ok","service":"%s"}`, cfg.Name)))
+	}
+}
+
+// readinessHandler reports the status of every dependency and fails with 503 when one of them is unavailable
+func readinessHandler(cfg Config, checker *readinessChecker, logger *log.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		results, ready := checker.check(r.Context())
+
+		report := ReadinessReport{
+			Status:       "ready",
+			Service:      cfg.Name,
+			Dependencies: results,
+		}
+		statusCode := http.StatusOK
+		if !ready {
+			report.Status = "not_ready"
+			statusCode = http.StatusServiceUnavailable
+			logger.Warnf("Readiness check failed: %v", results)
+		}
+
+		body, err := json.Marshal(report)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		w.Write(body)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessCheckerCachesResults(t *testing.T) {
+	var calls atomic.Int32
+	var failing atomic.Bool
+	probe := Probe{
+		Name: "dependency",
+		Check: func(_ context.Context) error {
+			calls.Add(1)
+			if failing.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	checker := newReadinessChecker([]Probe{probe}, time.Minute)
+	checker.now = func() time.Time { return now }
+
+	results, ready := checker.check(t.Context())
+	assert.True(t, ready)
+	assert.Equal(t, "ok", results["dependency"].Status)
+
+	// A failure within the cache duration is not seen yet
+	failing.Store(true)
+	_, ready = checker.check(t.Context())
+	assert.True(t, ready)
+	assert.Equal(t, int32(1), calls.Load())
+
+	now = now.Add(time.Minute)
+	results, ready = checker.check(t.Context())
+	assert.False(t, ready)
+	assert.Equal(t, "error", results["dependency"].Status)
+	assert.Equal(t, "connection refused", results["dependency"].Error)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestHTTPProbe(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	probe := HTTPProbe("test", server.URL, server.Client())
+	assert.NoError(t, probe.Check(t.Context()))
+
+	// The dependency is reachable, the request is just not authorized
+	status = http.StatusUnauthorized
+	assert.NoError(t, probe.Check(t.Context()))
+
+	status = http.StatusBadGateway
+	assert.ErrorContains(t, probe.Check(t.Context()), "502")
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	cfg := Config{
+		Name: "test-mcp-server",
+		ReadinessProbes: func(*log.Logger) []Probe {
+			return []Probe{
+				{Name: "up", Check: func(_ context.Context) error { return nil }},
+				{Name: "down", Check: func(_ context.Context) error { return errors.New("timeout") }},
+			}
+		},
+	}
+	handler := NewHTTPHandler(t.Context(), cfg, NewServer(t.Context(), cfg, logger), logger, "/mcp")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok","service":"test-mcp-server"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var report ReadinessReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "not_ready", report.Status)
+	assert.Equal(t, "ok", report.Dependencies["up"].Status)
+	assert.Equal(t, "timeout", report.Dependencies["down"].Error)
+}
+
+func TestReadinessCacheTTL(t *testing.T) {
+	logger := log.New()
+
+	t.Setenv("MCP_READINESS_CACHE_TTL", "")
+	assert.Equal(t, defaultReadinessCacheTTL, readinessCacheTTL(logger))
+
+	t.Setenv("MCP_READINESS_CACHE_TTL", "2m")
+	assert.Equal(t, 2*time.Minute, readinessCacheTTL(logger))
+
+	t.Setenv("MCP_READINESS_CACHE_TTL", "soon")
+	assert.Equal(t, defaultReadinessCacheTTL, readinessCacheTTL(logger))
+}
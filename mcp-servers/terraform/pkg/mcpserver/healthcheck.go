@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthcheckCommand is the name of the command checking a running server, used by the HEALTHCHECK of the image
+// where no curl or shell is available
+const healthcheckCommand = "healthcheck"
+
+// healthcheckTimeout bounds the check of the running server
+const healthcheckTimeout = 5 * time.Second
+
+// healthcheckTarget is the server to check, selected like the transport from the TRANSPORT_* environment variables
+type healthcheckTarget struct {
+	transport string
+	// address is the health URL of the StreamableHTTP transport or the host:port of the gRPC transport
+	address string
+	socket  string
+	tls     bool
+}
+
+func (t healthcheckTarget) String() string {
+	switch {
+	case t.socket != "":
+		return fmt.Sprintf("%s server on unix:%s", t.transport, t.socket)
+	case t.address != "":
+		return fmt.Sprintf("%s server at %s", t.transport, t.address)
+	}
+	return t.transport + " server"
+}
+
+// newHealthcheckCommand creates the healthcheck command, which exits non-zero when the server is not healthy
+func newHealthcheckCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   healthcheckCommand,
+		Short: "Check that the server running with the same environment is healthy",
+		Long:  `Check the /health endpoint of the StreamableHTTP transport or the health service of the gRPC transport, as selected by the TRANSPORT_* environment variables. A stdio server has nothing to check. Exits non-zero when the server is not healthy, e.g. for a Docker HEALTHCHECK.`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			target := healthcheckTargetFromEnv()
+			if url, _ := cmd.Flags().GetString("url"); url != "" {
+				target = healthcheckTarget{transport: "StreamableHTTP", address: url}
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), healthcheckTimeout)
+			defer cancel()
+			os.Exit(runHealthcheck(ctx, target, cmd.OutOrStdout()))
+		},
+	}
+	cmd.Flags().String("url", "", "Health URL to check instead of the one selected by the environment, e.g. http://127.0.0.1:8080/health")
+	return cmd
+}
+
+// healthcheckTargetFromEnv returns the server started by the same environment, reached on the loopback
+// interface when it listens on all interfaces
+func healthcheckTargetFromEnv() healthcheckTarget {
+	switch {
+	case ShouldUseGRPCMode():
+		tlsConfig := LoadGRPCTLSConfigFromEnv()
+		return healthcheckTarget{
+			transport: "gRPC",
+			address:   net.JoinHostPort(loopbackHost(GetHTTPHost()), GetGRPCPort()),
+			tls:       tlsConfig.CertFile != "" || tlsConfig.KeyFile != "",
+		}
+	case ShouldUseStreamableHTTPMode():
+		if socket := GetHTTPSocket(); socket != "" {
+			return healthcheckTarget{transport: "StreamableHTTP", address: "http://localhost/health", socket: socket}
+		}
+		return healthcheckTarget{transport: "StreamableHTTP", address: fmt.Sprintf("http://%s/health", net.JoinHostPort(loopbackHost(GetHTTPHost()), GetHTTPPort()))}
+	}
+	return healthcheckTarget{transport: "stdio"}
+}
+
+func loopbackHost(host string) string {
+	switch host {
+	case "", "0.0.0.0":
+		return "127.0.0.1"
+	case "::", "[::]":
+		return "::1"
+	}
+	return host
+}
+
+// runHealthcheck checks the target and prints the outcome, returning the exit code of the command
+func runHealthcheck(ctx context.Context, target healthcheckTarget, out io.Writer) int {
+	var err error
+	switch target.transport {
+	case "stdio":
+		// The server runs as long as the process of the container, there is no endpoint to check
+		fmt.Fprintf(out, "%s: %s, nothing to check\n", CheckOK, target)
+		return 0
+	case "gRPC":
+		err = checkGRPCHealth(ctx, target)
+	default:
+		err = checkHTTPHealth(ctx, target)
+	}
+	if err != nil {
+		fmt.Fprintf(out, "%s: %s is not healthy: %v\n", CheckFail, target, err)
+		return 1
+	}
+	fmt.Fprintf(out, "%s: %s is healthy\n", CheckOK, target)
+	return 0
+}
+
+func checkHTTPHealth(ctx context.Context, target healthcheckTarget) error {
+	transport := &http.Transport{}
+	if target.socket != "" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", target.socket)
+		}
+	}
+	httpClient := &http.Client{Transport: transport}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.address, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", target.address, resp.Status)
+	}
+	return nil
+}
+
+// checkGRPCHealth calls the gRPC health service. With TLS, the server may require a client certificate the
+// command does not have, so only the port is checked.
+func checkGRPCHealth(ctx context.Context, target healthcheckTarget) error {
+	if target.tls {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", target.address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	conn, err := grpc.NewClient(target.address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: grpcServiceName})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("the health service reports %s", resp.GetStatus())
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthcheckTargetFromEnv(t *testing.T) {
+	for _, name := range []string{"TRANSPORT_MODE", "TRANSPORT_PORT", "TRANSPORT_HOST", "TRANSPORT_SOCKET", "MCP_ENDPOINT", "MCP_GRPC_TLS_CERT_FILE", "MCP_GRPC_TLS_KEY_FILE"} {
+		t.Setenv(name, "")
+	}
+	assert.Equal(t, healthcheckTarget{transport: "stdio"}, healthcheckTargetFromEnv())
+
+	t.Setenv("TRANSPORT_MODE", "streamable-http")
+	t.Setenv("TRANSPORT_HOST", "0.0.0.0")
+	assert.Equal(t, "http://127.0.0.1:8080/health", healthcheckTargetFromEnv().address)
+
+	t.Setenv("TRANSPORT_SOCKET", "/run/mcp.sock")
+	assert.Equal(t, "/run/mcp.sock", healthcheckTargetFromEnv().socket)
+
+	t.Setenv("TRANSPORT_MODE", "grpc")
+	t.Setenv("TRANSPORT_HOST", "::")
+	t.Setenv("MCP_GRPC_TLS_CERT_FILE", "/etc/mcp/tls.crt")
+	assert.Equal(t, healthcheckTarget{transport: "gRPC", address: "[::1]:9090", tls: true}, healthcheckTargetFromEnv())
+}
+
+func TestRunHealthcheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	assert.Equal(t, 0, runHealthcheck(t.Context(), healthcheckTarget{transport: "stdio"}, &out))
+	assert.Equal(t, 0, runHealthcheck(t.Context(), healthcheckTarget{transport: "StreamableHTTP", address: server.URL + "/health"}, &out))
+	assert.Contains(t, out.String(), "is healthy")
+
+	status = http.StatusServiceUnavailable
+	out.Reset()
+	assert.Equal(t, 1, runHealthcheck(t.Context(), healthcheckTarget{transport: "StreamableHTTP", address: server.URL + "/health"}, &out))
+	assert.Contains(t, out.String(), "503")
+}
+
+func TestRunHealthcheckGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(grpcServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	target := healthcheckTarget{transport: "gRPC", address: listener.Addr().String()}
+	var out bytes.Buffer
+	assert.Equal(t, 0, runHealthcheck(t.Context(), target, &out))
+	target.tls = true
+	assert.Equal(t, 0, runHealthcheck(t.Context(), target, &out))
+
+	healthServer.SetServingStatus(grpcServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	target.tls = false
+	assert.Equal(t, 1, runHealthcheck(t.Context(), target, &out))
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	LogLevel      = "MCP_LOG_LEVEL"
+	LogFormat     = "MCP_LOG_FORMAT"
+	LogMaxSizeMB  = "MCP_LOG_MAX_SIZE_MB"
+	LogMaxAgeDays = "MCP_LOG_MAX_AGE_DAYS"
+	LogMaxBackups = "MCP_LOG_MAX_BACKUPS"
+)
+
+// LogConfig holds the logger configuration
+type LogConfig struct {
+	Level      log.Level // Zero keeps the default, info on stderr and debug in a log file
+	Format     string    // "text" or "json"
+	MaxSizeMB  int       // Size at which the log file is rotated, 0 disables rotation by size
+	MaxAgeDays int       // Days rotated log files are kept, 0 keeps them forever
+	MaxBackups int       // Number of rotated log files kept, 0 keeps all of them
+}
+
+// LoadLogConfigFromEnv loads the logger configuration from environment variables
+func LoadLogConfigFromEnv() LogConfig {
+	config := LogConfig{Format: "text"}
+
+	if level := strings.TrimSpace(os.Getenv(LogLevel)); level != "" {
+		if parsed, err := log.ParseLevel(level); err == nil {
+			config.Level = parsed
+		} else {
+			log.Warnf("Invalid %s value %q, using the default level", LogLevel, level)
+		}
+	}
+
+	if format := strings.ToLower(strings.TrimSpace(os.Getenv(LogFormat))); format != "" {
+		if format == "json" || format == "text" {
+			config.Format = format
+		} else {
+			log.Warnf("Invalid %s value %q, using text", LogFormat, format)
+		}
+	}
+
+	config.MaxSizeMB = nonNegativeIntFromEnv(LogMaxSizeMB)
+	config.MaxAgeDays = nonNegativeIntFromEnv(LogMaxAgeDays)
+	config.MaxBackups = nonNegativeIntFromEnv(LogMaxBackups)
+	return config
+}
+
+func nonNegativeIntFromEnv(name string) int {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return 0
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Warnf("Invalid %s value %q, ignoring it", name, value)
+		return 0
+	}
+	return parsed
+}
+
+// newLogger creates a logger writing to outPath, or to stderr when outPath is empty
+func newLogger(outPath string, config LogConfig) (*log.Logger, error) {
+	logger := log.New()
+	logger.AddHook(redactionHook{})
+
+	if config.Format == "json" {
+		logger.SetFormatter(&log.JSONFormatter{})
+	}
+
+	if outPath != "" {
+		output, err := logFileWriter(outPath, config)
+		if err != nil {
+			return nil, err
+		}
+		logger.SetOutput(output)
+		logger.SetLevel(log.DebugLevel)
+	}
+
+	// log.PanicLevel is the zero value, so it cannot be selected explicitly and means "not set"
+	if config.Level != log.PanicLevel {
+		logger.SetLevel(config.Level)
+	}
+	return logger, nil
+}
+
+// logFileWriter opens the log file, rotating it when a maximum size or age of rotated files is configured
+func logFileWriter(outPath string, config LogConfig) (io.Writer, error) {
+	if config.MaxSizeMB == 0 && config.MaxAgeDays == 0 {
+		file, err := os.OpenFile(outPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		return file, nil
+	}
+
+	// lumberjack rotates by size only, at 100 MB when no size is configured
+	return &lumberjack.Logger{
+		Filename:   outPath,
+		MaxSize:    config.MaxSizeMB,
+		MaxAge:     config.MaxAgeDays,
+		MaxBackups: config.MaxBackups,
+	}, nil
+}
+
+// componentFieldsHook adds fixed fields, e.g. the transport, to every entry of a logger
+type componentFieldsHook log.Fields
+
+func (h componentFieldsHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h componentFieldsHook) Fire(entry *log.Entry) error {
+	for key, value := range h {
+		if _, ok := entry.Data[key]; !ok {
+			entry.Data[key] = value
+		}
+	}
+	return nil
+}
+
+// withTransportField tags every entry written by logger with the transport serving the server
+func withTransportField(logger *log.Logger, transport string) {
+	logger.AddHook(componentFieldsHook{"transport": transport})
+}
+
+// redactionHook masks the secrets found in the message and the string and error fields of every entry
+type redactionHook struct{}
+
+func (h redactionHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h redactionHook) Fire(entry *log.Entry) error {
+	entry.Message = utils.RedactSecrets(entry.Message)
+	for key, value := range entry.Data {
+		switch value := value.(type) {
+		case string:
+			entry.Data[key] = utils.RedactSecrets(value)
+		case error:
+			if redacted := utils.RedactSecrets(value.Error()); redacted != value.Error() {
+				entry.Data[key] = redacted
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func TestLoadLogConfigFromEnv(t *testing.T) {
+	t.Setenv(LogLevel, "")
+	t.Setenv(LogFormat, "")
+	t.Setenv(LogMaxSizeMB, "")
+	t.Setenv(LogMaxAgeDays, "")
+	t.Setenv(LogMaxBackups, "")
+	assert.Equal(t, LogConfig{Format: "text"}, LoadLogConfigFromEnv())
+
+	t.Setenv(LogLevel, "warn")
+	t.Setenv(LogFormat, "JSON")
+	t.Setenv(LogMaxSizeMB, "50")
+	t.Setenv(LogMaxAgeDays, "7")
+	t.Setenv(LogMaxBackups, "3")
+	assert.Equal(t, LogConfig{Level: log.WarnLevel, Format: "json", MaxSizeMB: 50, MaxAgeDays: 7, MaxBackups: 3}, LoadLogConfigFromEnv())
+
+	t.Setenv(LogLevel, "verbose")
+	t.Setenv(LogFormat, "xml")
+	t.Setenv(LogMaxSizeMB, "-1")
+	assert.Equal(t, LogConfig{Format: "text", MaxAgeDays: 7, MaxBackups: 3}, LoadLogConfigFromEnv())
+}
+
+func TestNewLogger(t *testing.T) {
+	logger, err := newLogger("", LogConfig{Format: "text"})
+	require.NoError(t, err)
+	assert.Equal(t, log.InfoLevel, logger.GetLevel())
+
+	// Log files default to debug, MCP_LOG_LEVEL overrides it
+	path := filepath.Join(t.TempDir(), "server.log")
+	logger, err = newLogger(path, LogConfig{Format: "json"})
+	require.NoError(t, err)
+	assert.Equal(t, log.DebugLevel, logger.GetLevel())
+	_, rotated := logger.Out.(*lumberjack.Logger)
+	assert.False(t, rotated)
+
+	withTransportField(logger, "streamable-http")
+	logger.WithField("tool", "search_providers").Debug("Tool call started")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, "streamable-http", entry["transport"])
+	assert.Equal(t, "search_providers", entry["tool"])
+	assert.Equal(t, "Tool call started", entry["msg"])
+
+	logger, err = newLogger(path, LogConfig{Level: log.ErrorLevel, Format: "text", MaxSizeMB: 10, MaxBackups: 2})
+	require.NoError(t, err)
+	assert.Equal(t, log.ErrorLevel, logger.GetLevel())
+	writer, rotated := logger.Out.(*lumberjack.Logger)
+	require.True(t, rotated)
+	assert.Equal(t, 10, writer.MaxSize)
+	assert.Equal(t, 2, writer.MaxBackups)
+}
+
+func TestNewLoggerRedactsSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	logger, err := newLogger(path, LogConfig{Format: "json"})
+	require.NoError(t, err)
+
+	token := "abcdEFGH123456.atlasv1.aBcD_eFgH-1234567890abcdefghijKLMNOPqrstuvwxyz"
+	logger.WithFields(log.Fields{
+		"header": "Bearer " + token,
+		"error":  errors.New("invalid token " + token),
+		"count":  3,
+	}).Warnf("Request with token %s failed", token)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), token)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, "Request with token [REDACTED] failed", entry["msg"])
+	assert.Equal(t, "Bearer [REDACTED]", entry["header"])
+	assert.Equal(t, "invalid token [REDACTED]", entry["error"])
+	assert.Equal(t, float64(3), entry["count"])
+}
@@ -0,0 +1,347 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestCommand is the name of the command printing the Kubernetes manifests of the server
+const manifestCommand = "manifest"
+
+// manifestCacheDir is where the pod keeps the registry responses, in an emptyDir volume
+const manifestCacheDir = "/var/cache/mcp"
+
+// manifestOptions are the flags of the manifest command
+type manifestOptions struct {
+	name         string
+	namespace    string
+	image        string
+	replicas     int
+	port         int
+	secretName   string
+	ingressHost  string
+	ingressClass string
+	tlsSecret    string
+	env          []string
+	fromEnv      bool
+}
+
+// manifestEnvPrefixes select the variables of the server copied from the environment with --from-env
+var manifestEnvPrefixes = []string{"MCP_", "TFE_", "TERRAFORM_", "GITHUB_", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"}
+
+// manifestManagedEnv are set by the manifests themselves and never copied from the environment
+var manifestManagedEnv = map[string]bool{
+	"TRANSPORT_MODE":         true,
+	"TRANSPORT_HOST":         true,
+	"TRANSPORT_PORT":         true,
+	"TRANSPORT_SOCKET":       true,
+	"MCP_SESSION_MODE":       true,
+	"MCP_REGISTRY_CACHE_DIR": true,
+}
+
+// newManifestCommand creates the manifest command
+func newManifestCommand(cfg Config) *cobra.Command {
+	options := manifestOptions{}
+	cmd := &cobra.Command{
+		Use:   manifestCommand,
+		Short: "Print Kubernetes manifests to deploy the server",
+		Long:  `Print a Deployment, a Service and optionally an Ingress running the server on the StreamableHTTP transport, with the liveness and readiness probes on /healthz and /readyz, a non-root read-only container and a disk cache of the registry responses. Tokens and keys are read from a Secret, never written in the manifests.`,
+		Args:  cobra.NoArgs,
+		// An invalid --env is not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			env, err := manifestEnv(options, os.Environ(), cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			return writeManifests(cmd.OutOrStdout(), options, env)
+		},
+	}
+	cmd.Flags().StringVar(&options.name, "name", cfg.Name, "Name of the Kubernetes objects")
+	cmd.Flags().StringVar(&options.namespace, "namespace", "", "Namespace of the Kubernetes objects, the namespace of the kubectl context when empty")
+	cmd.Flags().StringVar(&options.image, "image", fmt.Sprintf("hashicorp/%s:%s", cfg.Name, cfg.Version), "Image of the server")
+	cmd.Flags().IntVar(&options.replicas, "replicas", 1, "Number of replicas, more than one selects stateless sessions")
+	cmd.Flags().IntVar(&options.port, "port", 8080, "Port of the StreamableHTTP transport")
+	cmd.Flags().StringVar(&options.secretName, "secret-name", "", "Secret holding the tokens and keys copied with --from-env, <name>-secrets when empty")
+	cmd.Flags().StringVar(&options.ingressHost, "ingress-host", "", "Host of an Ingress to the server, no Ingress when empty")
+	cmd.Flags().StringVar(&options.ingressClass, "ingress-class", "", "Ingress class of the Ingress")
+	cmd.Flags().StringVar(&options.tlsSecret, "tls-secret", "", "TLS Secret of the Ingress host")
+	cmd.Flags().StringArrayVar(&options.env, "env", nil, "Environment variable of the server as NAME=VALUE, repeatable")
+	cmd.Flags().BoolVar(&options.fromEnv, "from-env", false, "Copy the MCP_*, TFE_*, TERRAFORM_*, GITHUB_* and proxy variables of the current environment, tokens and keys as Secret references")
+	return cmd
+}
+
+// manifestEnvVar is an environment variable of the container, with a value or read from a Secret
+type manifestEnvVar struct {
+	Name      string                `yaml:"name"`
+	Value     *string               `yaml:"value,omitempty"`
+	ValueFrom *manifestEnvVarSource `yaml:"valueFrom,omitempty"`
+}
+
+type manifestEnvVarSource struct {
+	SecretKeyRef manifestSecretKeyRef `yaml:"secretKeyRef"`
+}
+
+type manifestSecretKeyRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+// isSecretVariable reports whether a variable holds a credential, e.g. TFE_TOKEN or MCP_EMBEDDINGS_API_KEY,
+// rather than a path to one
+func isSecretVariable(name string) bool {
+	if strings.HasSuffix(name, "_FILE") || strings.HasSuffix(name, "_PATH") {
+		return false
+	}
+	for _, marker := range []string{"TOKEN", "SECRET", "PASSWORD", "KEY"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// manifestEnv returns the variables of the container: the transport settings, then the variables copied from
+// environ with --from-env, then the --env flags, which override the others
+func manifestEnv(options manifestOptions, environ []string, warnings io.Writer) ([]manifestEnvVar, error) {
+	sessionMode := "stateful"
+	if options.replicas > 1 {
+		// Stateful sessions live in one replica, the requests of a session may reach another one
+		sessionMode = "stateless"
+	}
+	values := map[string]string{
+		"TRANSPORT_MODE":         "streamable-http",
+		"TRANSPORT_HOST":         "0.0.0.0",
+		"TRANSPORT_PORT":         strconv.Itoa(options.port),
+		"MCP_SESSION_MODE":       sessionMode,
+		"MCP_REGISTRY_CACHE_DIR": manifestCacheDir,
+	}
+	order := []string{"TRANSPORT_MODE", "TRANSPORT_HOST", "TRANSPORT_PORT", "MCP_SESSION_MODE", "MCP_REGISTRY_CACHE_DIR"}
+	secrets := map[string]bool{}
+
+	if options.fromEnv {
+		copied := []string{}
+		for _, entry := range environ {
+			name, value, _ := strings.Cut(entry, "=")
+			if manifestManagedEnv[name] || value == "" || !hasAnyPrefix(name, manifestEnvPrefixes) {
+				continue
+			}
+			if isSecretVariable(name) {
+				secrets[name] = true
+			} else {
+				values[name] = value
+			}
+			if strings.HasSuffix(name, "_FILE") || strings.HasSuffix(name, "_PATH") {
+				fmt.Fprintf(warnings, "%s refers to %s, mount the file in the pod at the same path\n", name, value)
+			}
+			copied = append(copied, name)
+		}
+		sort.Strings(copied)
+		order = append(order, copied...)
+	}
+
+	for _, entry := range options.env {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --env %q, expected NAME=VALUE", entry)
+		}
+		if manifestManagedEnv[name] {
+			return nil, fmt.Errorf("%s is set by the manifests, use the --port and --replicas flags", name)
+		}
+		if _, exists := values[name]; !exists && !secrets[name] {
+			order = append(order, name)
+		}
+		delete(secrets, name)
+		values[name] = value
+	}
+
+	secretName := options.secretName
+	if secretName == "" {
+		secretName = options.name + "-secrets"
+	}
+	env := make([]manifestEnvVar, 0, len(order))
+	for _, name := range order {
+		if secrets[name] {
+			env = append(env, manifestEnvVar{Name: name, ValueFrom: &manifestEnvVarSource{SecretKeyRef: manifestSecretKeyRef{Name: secretName, Key: name}}})
+			continue
+		}
+		value := values[name]
+		env = append(env, manifestEnvVar{Name: name, Value: &value})
+	}
+	return env, nil
+}
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeManifests writes the objects as a multi-document YAML stream, starting with the command creating the
+// Secret when variables are read from one
+func writeManifests(out io.Writer, options manifestOptions, env []manifestEnvVar) error {
+	secretKeys := []string{}
+	secretName := ""
+	for _, variable := range env {
+		if variable.ValueFrom != nil {
+			secretName = variable.ValueFrom.SecretKeyRef.Name
+			secretKeys = append(secretKeys, variable.Name)
+		}
+	}
+	if len(secretKeys) > 0 {
+		namespace := ""
+		if options.namespace != "" {
+			namespace = " --namespace " + options.namespace
+		}
+		fmt.Fprintf(out, "# Create the Secret before applying the manifests:\n#   kubectl create secret generic %s%s", secretName, namespace)
+		for _, key := range secretKeys {
+			fmt.Fprintf(out, " \\\n#     --from-literal=%s=...", key)
+		}
+		fmt.Fprintln(out)
+	}
+
+	objects := []any{deploymentManifest(options, env), serviceManifest(options)}
+	if options.ingressHost != "" {
+		objects = append(objects, ingressManifest(options))
+	}
+	for _, object := range objects {
+		fmt.Fprintln(out, "---")
+		encoder := yaml.NewEncoder(out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(object); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestMetadata returns the metadata of the objects, labelled to be selected by the Service
+func manifestMetadata(options manifestOptions) map[string]any {
+	metadata := map[string]any{
+		"name":   options.name,
+		"labels": manifestLabels(options),
+	}
+	if options.namespace != "" {
+		metadata["namespace"] = options.namespace
+	}
+	return metadata
+}
+
+func manifestLabels(options manifestOptions) map[string]string {
+	return map[string]string{"app.kubernetes.io/name": options.name}
+}
+
+func manifestProbe(path string, periodSeconds int) map[string]any {
+	return map[string]any{
+		"httpGet":          map[string]any{"path": path, "port": "http"},
+		"periodSeconds":    periodSeconds,
+		"timeoutSeconds":   5,
+		"failureThreshold": 3,
+	}
+}
+
+func deploymentManifest(options manifestOptions, env []manifestEnvVar) map[string]any {
+	// Give the server 30 seconds to start before the liveness probe applies
+	startupProbe := manifestProbe("/healthz", 2)
+	startupProbe["failureThreshold"] = 15
+	container := map[string]any{
+		"name":            "server",
+		"image":           options.image,
+		"imagePullPolicy": "IfNotPresent",
+		"ports":           []map[string]any{{"name": "http", "containerPort": options.port, "protocol": "TCP"}},
+		"env":             env,
+		// /healthz only fails when the server is stuck, /readyz also when the registry or TFE is unreachable
+		"livenessProbe":  manifestProbe("/healthz", 10),
+		"readinessProbe": manifestProbe("/readyz", 10),
+		"startupProbe":   startupProbe,
+		"resources": map[string]any{
+			"requests": map[string]string{"cpu": "100m", "memory": "128Mi"},
+			"limits":   map[string]string{"memory": "512Mi"},
+		},
+		"securityContext": map[string]any{
+			"allowPrivilegeEscalation": false,
+			"readOnlyRootFilesystem":   true,
+			"capabilities":             map[string]any{"drop": []string{"ALL"}},
+		},
+		"volumeMounts": []map[string]string{{"name": "registry-cache", "mountPath": manifestCacheDir}},
+	}
+	return map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   manifestMetadata(options),
+		"spec": map[string]any{
+			"replicas": options.replicas,
+			"selector": map[string]any{"matchLabels": manifestLabels(options)},
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": manifestLabels(options)},
+				"spec": map[string]any{
+					// The image runs as the unprivileged user of the distroless images
+					"securityContext": map[string]any{
+						"runAsNonRoot":   true,
+						"runAsUser":      65532,
+						"runAsGroup":     65532,
+						"fsGroup":        65532,
+						"seccompProfile": map[string]string{"type": "RuntimeDefault"},
+					},
+					"containers": []map[string]any{container},
+					"volumes":    []map[string]any{{"name": "registry-cache", "emptyDir": map[string]string{"sizeLimit": "1Gi"}}},
+				},
+			},
+		},
+	}
+}
+
+func serviceManifest(options manifestOptions) map[string]any {
+	return map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   manifestMetadata(options),
+		"spec": map[string]any{
+			"selector": manifestLabels(options),
+			"ports":    []map[string]any{{"name": "http", "port": 80, "targetPort": "http", "protocol": "TCP"}},
+		},
+	}
+}
+
+func ingressManifest(options manifestOptions) map[string]any {
+	spec := map[string]any{
+		"rules": []map[string]any{{
+			"host": options.ingressHost,
+			"http": map[string]any{
+				"paths": []map[string]any{{
+					"path":     "/",
+					"pathType": "Prefix",
+					"backend":  map[string]any{"service": map[string]any{"name": options.name, "port": map[string]string{"name": "http"}}},
+				}},
+			},
+		}},
+	}
+	if options.ingressClass != "" {
+		spec["ingressClassName"] = options.ingressClass
+	}
+	if options.tlsSecret != "" {
+		spec["tls"] = []map[string]any{{"hosts": []string{options.ingressHost}, "secretName": options.tlsSecret}}
+	}
+	return map[string]any{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "Ingress",
+		"metadata":   manifestMetadata(options),
+		"spec":       spec,
+	}
+}
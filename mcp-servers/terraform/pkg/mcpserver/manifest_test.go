@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestManifestEnv(t *testing.T) {
+	options := manifestOptions{name: "mcp", replicas: 2, port: 9000, fromEnv: true, env: []string{"MCP_LOG_LEVEL=debug", "TFE_TOKEN=inline"}}
+	environ := []string{
+		"HOME=/root",
+		"TRANSPORT_MODE=stdio",
+		"TFE_ADDRESS=https://tfe.example.com",
+		"TFE_TOKEN=secret",
+		"GITHUB_TOKEN=secret",
+		"MCP_CA_CERT_FILE=/etc/ssl/corp.pem",
+		"MCP_LOG_LEVEL=info",
+	}
+	var warnings bytes.Buffer
+	env, err := manifestEnv(options, environ, &warnings)
+	require.NoError(t, err)
+	assert.Contains(t, warnings.String(), "MCP_CA_CERT_FILE refers to /etc/ssl/corp.pem")
+
+	values := map[string]string{}
+	secrets := map[string]string{}
+	for _, variable := range env {
+		if variable.ValueFrom != nil {
+			secrets[variable.Name] = variable.ValueFrom.SecretKeyRef.Name
+			continue
+		}
+		values[variable.Name] = *variable.Value
+	}
+	assert.Equal(t, map[string]string{
+		"TRANSPORT_MODE":         "streamable-http",
+		"TRANSPORT_HOST":         "0.0.0.0",
+		"TRANSPORT_PORT":         "9000",
+		"MCP_SESSION_MODE":       "stateless",
+		"MCP_REGISTRY_CACHE_DIR": manifestCacheDir,
+		"TFE_ADDRESS":            "https://tfe.example.com",
+		"MCP_CA_CERT_FILE":       "/etc/ssl/corp.pem",
+		"MCP_LOG_LEVEL":          "debug",
+		"TFE_TOKEN":              "inline",
+	}, values)
+	assert.Equal(t, map[string]string{"GITHUB_TOKEN": "mcp-secrets"}, secrets)
+	assert.Equal(t, "TRANSPORT_MODE", env[0].Name)
+
+	// Without --from-env, only the transport settings are set
+	env, err = manifestEnv(manifestOptions{name: "mcp", replicas: 1, port: 8080}, environ, io.Discard)
+	require.NoError(t, err)
+	assert.Len(t, env, 5)
+	assert.Equal(t, "stateful", *env[3].Value)
+
+	for _, flag := range []string{"NOVALUE", "=value", "TRANSPORT_PORT=9090"} {
+		_, err := manifestEnv(manifestOptions{env: []string{flag}}, nil, io.Discard)
+		assert.Error(t, err, flag)
+	}
+}
+
+func TestWriteManifests(t *testing.T) {
+	options := manifestOptions{name: "mcp", namespace: "tools", image: "example/mcp:1.0", replicas: 1, port: 8080, ingressHost: "mcp.example.com", ingressClass: "nginx", tlsSecret: "mcp-tls", fromEnv: true}
+	env, err := manifestEnv(options, []string{"TFE_TOKEN=secret"}, io.Discard)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, writeManifests(&out, options, env))
+	assert.Contains(t, out.String(), "kubectl create secret generic mcp-secrets --namespace tools")
+	assert.Contains(t, out.String(), "--from-literal=TFE_TOKEN=...")
+	assert.NotContains(t, out.String(), "value: secret")
+
+	kinds := []string{}
+	var deployment map[string]any
+	decoder := yaml.NewDecoder(&out)
+	for {
+		var object map[string]any
+		if err := decoder.Decode(&object); errors.Is(err, io.EOF) {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+		kinds = append(kinds, object["kind"].(string))
+		assert.Equal(t, "tools", object["metadata"].(map[string]any)["namespace"])
+		if object["kind"] == "Deployment" {
+			deployment = object
+		}
+	}
+	assert.Equal(t, []string{"Deployment", "Service", "Ingress"}, kinds)
+
+	container := deployment["spec"].(map[string]any)["template"].(map[string]any)["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)
+	assert.Equal(t, "example/mcp:1.0", container["image"])
+	assert.Equal(t, "/healthz", container["livenessProbe"].(map[string]any)["httpGet"].(map[string]any)["path"])
+	assert.Equal(t, "/readyz", container["readinessProbe"].(map[string]any)["httpGet"].(map[string]any)["path"])
+
+	// Without an Ingress host, only the Deployment and the Service are printed
+	out.Reset()
+	options.ingressHost = ""
+	require.NoError(t, writeManifests(&out, options, env))
+	assert.NotContains(t, out.String(), "kind: Ingress")
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// upstreamNotifyInterval is the minimum time between two notifications about the same throttled upstream
+const upstreamNotifyInterval = time.Minute
+
+// clientNotifier sets the initial log notification level of the sessions and keeps track of them,
+// to send server events that are not tied to a tool call to every client
+type clientNotifier struct {
+	level mcp.LoggingLevel
+	now   func() time.Time
+
+	mu               sync.Mutex
+	hcServer         *server.MCPServer
+	sessionIDs       map[string]struct{}
+	upstreamNotified map[string]time.Time
+}
+
+func newClientNotifier(level mcp.LoggingLevel) *clientNotifier {
+	return &clientNotifier{
+		level:            level,
+		now:              time.Now,
+		sessionIDs:       make(map[string]struct{}),
+		upstreamNotified: make(map[string]time.Time),
+	}
+}
+
+func (n *clientNotifier) registerSession(_ context.Context, session server.ClientSession) {
+	if logging, ok := session.(server.SessionWithLogging); ok {
+		logging.SetLogLevel(n.level)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sessionIDs[session.SessionID()] = struct{}{}
+}
+
+func (n *clientNotifier) unregisterSession(_ context.Context, session server.ClientSession) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.sessionIDs, session.SessionID())
+}
+
+// upstreamThrottled tells every client that the registry or HCP Terraform/TFE throttles the server,
+// at most once per upstreamNotifyInterval and host
+func (n *clientNotifier) upstreamThrottled(host string, statusCode int, retryAfter time.Duration) {
+	now := n.now()
+	n.mu.Lock()
+	if last, ok := n.upstreamNotified[host]; ok && now.Sub(last) < upstreamNotifyInterval {
+		n.mu.Unlock()
+		return
+	}
+	n.upstreamNotified[host] = now
+	hcServer := n.hcServer
+	sessionIDs := make([]string, 0, len(n.sessionIDs))
+	for sessionID := range n.sessionIDs {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	n.mu.Unlock()
+
+	if hcServer == nil {
+		return
+	}
+	client.NotifyAllClients(hcServer, sessionIDs, mcp.LoggingLevelWarning, client.ClientLoggerUpstream, map[string]any{
+		"message":             "The upstream is throttling the server, tool calls are rate limited more strictly",
+		"host":                host,
+		"status_code":         statusCode,
+		"retry_after_seconds": int(retryAfter.Seconds()),
+	})
+}
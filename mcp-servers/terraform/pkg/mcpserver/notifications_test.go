@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receivedLogs drains the log notifications sent to the session
+func receivedLogs(session *grpcSession) []map[string]any {
+	var logs []map[string]any
+	for {
+		select {
+		case notification := <-session.notifications:
+			if notification.Method == "notifications/message" {
+				logs = append(logs, notification.Params.AdditionalFields)
+			}
+		default:
+			return logs
+		}
+	}
+}
+
+func TestClientLogNotifications(t *testing.T) {
+	t.Setenv(client.ClientLogLevel, "")
+	cfg := Config{
+		Name:    "test-mcp-server",
+		Version: "0.0.1",
+		Register: func(hcServer *server.MCPServer, _ *log.Logger) {
+			hcServer.AddTool(mcp.NewTool("run"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				client.NotifyClient(ctx, mcp.LoggingLevelInfo, client.ClientLoggerRuns, map[string]any{"message": "Run queued"})
+				client.NotifyClient(ctx, mcp.LoggingLevelWarning, client.ClientLoggerUpstream, map[string]any{"message": "Slow upstream"})
+				return mcp.NewToolResultText("done"), nil
+			})
+		},
+	}
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	hcServer := NewServer(t.Context(), cfg, logger)
+
+	session := newGRPCSession()
+	require.NoError(t, hcServer.RegisterSession(t.Context(), session))
+	session.Initialize()
+	ctx := hcServer.WithContext(t.Context(), session)
+	send := func(message string) {
+		hcServer.HandleMessage(ctx, json.RawMessage(message))
+	}
+
+	// Sessions start at MCP_CLIENT_LOG_LEVEL, warning by default
+	assert.Equal(t, mcp.LoggingLevelWarning, session.GetLogLevel())
+	send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"run","arguments":{}}}`)
+	logs := receivedLogs(session)
+	require.Len(t, logs, 1)
+	assert.Equal(t, mcp.LoggingLevelWarning, logs[0]["level"])
+	assert.Equal(t, client.ClientLoggerUpstream, logs[0]["logger"])
+
+	// The client can lower the level
+	send(`{"jsonrpc":"2.0","id":2,"method":"logging/setLevel","params":{"level":"info"}}`)
+	send(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"run","arguments":{}}}`)
+	logs = receivedLogs(session)
+	require.Len(t, logs, 2)
+	assert.Equal(t, map[string]any{"message": "Run queued"}, logs[0]["data"])
+}
+
+func TestClientNotifierUpstreamThrottled(t *testing.T) {
+	hcServer := server.NewMCPServer("test-mcp-server", "0.0.1", server.WithLogging())
+	notifier := newClientNotifier(mcp.LoggingLevelWarning)
+	notifier.hcServer = hcServer
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	sessions := []*grpcSession{newGRPCSession(), newGRPCSession()}
+	for _, session := range sessions {
+		require.NoError(t, hcServer.RegisterSession(t.Context(), session))
+		session.Initialize()
+		notifier.registerSession(t.Context(), session)
+	}
+	notifier.unregisterSession(t.Context(), sessions[1])
+
+	notifier.upstreamThrottled("app.terraform.io", 429, 30*time.Second)
+	logs := receivedLogs(sessions[0])
+	require.Len(t, logs, 1)
+	data := logs[0]["data"].(map[string]any)
+	assert.Equal(t, "app.terraform.io", data["host"])
+	assert.Equal(t, 30, data["retry_after_seconds"])
+	assert.Empty(t, receivedLogs(sessions[1]))
+
+	// The same host is reported at most once a minute
+	now = now.Add(30 * time.Second)
+	notifier.upstreamThrottled("app.terraform.io", 429, 0)
+	assert.Empty(t, receivedLogs(sessions[0]))
+	notifier.upstreamThrottled("registry.terraform.io", 503, 0)
+	assert.Len(t, receivedLogs(sessions[0]), 1)
+	now = now.Add(time.Minute)
+	notifier.upstreamThrottled("app.terraform.io", 429, 0)
+	assert.Len(t, receivedLogs(sessions[0]), 1)
+}
@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ConfigFile is a KEY=VALUE file holding the settings that can be changed without a restart
+	ConfigFile = "MCP_CONFIG_FILE"
+	// ConfigWatchInterval is how often the configuration file is checked for changes
+	ConfigWatchInterval = "MCP_CONFIG_WATCH_INTERVAL"
+
+	defaultConfigWatchInterval = 5 * time.Second
+)
+
+// reloadHook is a function called after a configuration change, identified to be unregistered
+type reloadHook struct {
+	id int
+	fn func()
+}
+
+var (
+	reloadHooksMu    sync.Mutex
+	reloadHooks      []reloadHook
+	reloadHookNextID int
+)
+
+// onConfigReload registers fn to be called after a configuration change has been applied. The
+// returned function unregisters it, e.g. when the server holding the settings fn updates stops.
+func onConfigReload(fn func()) (unregister func()) {
+	reloadHooksMu.Lock()
+	defer reloadHooksMu.Unlock()
+	id := reloadHookNextID
+	reloadHookNextID++
+	reloadHooks = append(reloadHooks, reloadHook{id: id, fn: fn})
+	return func() {
+		reloadHooksMu.Lock()
+		defer reloadHooksMu.Unlock()
+		reloadHooks = slices.DeleteFunc(reloadHooks, func(hook reloadHook) bool { return hook.id == id })
+	}
+}
+
+func runReloadHooks() {
+	reloadHooksMu.Lock()
+	hooks := slices.Clone(reloadHooks)
+	reloadHooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook.fn()
+	}
+}
+
+// isReloadableKey reports whether a setting can be changed without a restart.
+// Only the CORS settings and the rate limits are read again when the file changes.
+func isReloadableKey(key string) bool {
+	switch key {
+	case "MCP_ALLOWED_ORIGINS", "MCP_CORS_MODE", "MCP_ALLOWED_HOSTS":
+		return true
+	}
+	return strings.HasPrefix(key, "MCP_RATE_LIMIT_") || strings.HasPrefix(key, "MCP_MAX_CONCURRENCY_TOOL_")
+}
+
+// parseConfigFile reads KEY=VALUE lines. Blank lines and lines starting with # are
+// ignored, and values may be wrapped in single or double quotes.
+func parseConfigFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNumber)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// envValue is the value of an environment variable before the configuration file was applied
+type envValue struct {
+	value string
+	set   bool
+}
+
+// configReloader applies the configuration file on top of the environment of the process
+type configReloader struct {
+	path   string
+	logger *log.Logger
+
+	mu       sync.Mutex
+	original map[string]envValue // environment before the file was applied, by key
+	applied  map[string]string   // values taken from the file, by key
+	modTime  time.Time
+}
+
+func newConfigReloader(path string, logger *log.Logger) *configReloader {
+	return &configReloader{
+		path:     path,
+		logger:   logger,
+		original: make(map[string]envValue),
+		applied:  make(map[string]string),
+	}
+}
+
+// configChange is a setting changed by a reload
+type configChange struct {
+	key      string
+	oldValue string
+	newValue string
+}
+
+// load reads the configuration file and updates the environment. A key removed from the
+// file gets back the value it had at startup. It returns the settings that changed.
+func (r *configReloader) load() ([]configChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if info, err := os.Stat(r.path); err == nil {
+		r.modTime = info.ModTime()
+	}
+	values, err := parseConfigFile(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration file: %w", err)
+	}
+
+	next := make(map[string]string)
+	for key, value := range values {
+		if !isReloadableKey(key) {
+			r.logger.Warnf("Ignoring %s in %s, only the CORS settings and rate limits can be reloaded", key, r.path)
+			continue
+		}
+		next[key] = value
+	}
+
+	var changes []configChange
+	for key, value := range next {
+		if _, ok := r.original[key]; !ok {
+			original, set := os.LookupEnv(key)
+			r.original[key] = envValue{value: original, set: set}
+		}
+		if current := os.Getenv(key); current != value {
+			changes = append(changes, configChange{key: key, oldValue: current, newValue: value})
+			os.Setenv(key, value)
+		}
+	}
+	for key := range r.applied {
+		if _, ok := next[key]; ok {
+			continue
+		}
+		original := r.original[key]
+		if current := os.Getenv(key); current != original.value {
+			changes = append(changes, configChange{key: key, oldValue: current, newValue: original.value})
+		}
+		if original.set {
+			os.Setenv(key, original.value)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+	r.applied = next
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].key < changes[j].key })
+	return changes, nil
+}
+
+// reload applies the configuration file to the running server and writes an audit log entry for each change
+func (r *configReloader) reload(trigger string) {
+	changes, err := r.load()
+	if err != nil {
+		r.logger.Errorf("Failed to reload %s, keeping the current configuration: %v", r.path, err)
+		return
+	}
+	if len(changes) == 0 {
+		r.logger.Debugf("Configuration file %s reloaded without changes", r.path)
+		return
+	}
+
+	runReloadHooks()
+	for _, change := range changes {
+		r.logger.WithFields(log.Fields{
+			"audit":   "config_reload",
+			"trigger": trigger,
+			"file":    r.path,
+			"key":     change.key,
+			"old":     change.oldValue,
+			"new":     change.newValue,
+		}).Info("Applied configuration change")
+	}
+}
+
+// modified reports whether the configuration file changed since it was last read
+func (r *configReloader) modified() bool {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !info.ModTime().Equal(r.modTime)
+}
+
+// watch reloads the configuration file on SIGHUP and when its modification time changes, until ctx is done
+func (r *configReloader) watch(ctx context.Context, interval time.Duration) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighup:
+			r.logger.Infof("Received SIGHUP, reloading %s", r.path)
+			r.reload("sighup")
+		case <-ticker.C:
+			if r.modified() {
+				r.logger.Infof("Configuration file %s changed, reloading", r.path)
+				r.reload("file_watch")
+			}
+		}
+	}
+}
+
+// configWatchInterval returns the poll interval of the configuration file from MCP_CONFIG_WATCH_INTERVAL
+func configWatchInterval(logger *log.Logger) time.Duration {
+	value := strings.TrimSpace(os.Getenv(ConfigWatchInterval))
+	if value == "" {
+		return defaultConfigWatchInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		logger.Warnf("Invalid %s value %q, using default %s", ConfigWatchInterval, value, defaultConfigWatchInterval)
+		return defaultConfigWatchInterval
+	}
+	return interval
+}
+
+// startConfigReload applies MCP_CONFIG_FILE before the server is created and then watches it
+// until ctx is done. It does nothing when no configuration file is set.
+func startConfigReload(ctx context.Context, logger *log.Logger) error {
+	path := strings.TrimSpace(os.Getenv(ConfigFile))
+	if path == "" {
+		return nil
+	}
+
+	reloader := newConfigReloader(path, logger)
+	if _, err := reloader.load(); err != nil {
+		return err
+	}
+	logger.Infof("Loaded configuration file %s, send SIGHUP or edit it to apply changes", path)
+
+	go reloader.watch(ctx, configWatchInterval(logger))
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.env")
+	require.NoError(t, os.WriteFile(path, []byte(`
+# CORS
+MCP_ALLOWED_ORIGINS="https://a.example.com,https://b.example.com"
+MCP_CORS_MODE = 'strict'
+MCP_RATE_LIMIT_GLOBAL=5:10
+`), 0600))
+
+	values, err := parseConfigFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"MCP_ALLOWED_ORIGINS":   "https://a.example.com,https://b.example.com",
+		"MCP_CORS_MODE":         "strict",
+		"MCP_RATE_LIMIT_GLOBAL": "5:10",
+	}, values)
+
+	require.NoError(t, os.WriteFile(path, []byte("MCP_CORS_MODE\n"), 0600))
+	_, err = parseConfigFile(path)
+	assert.ErrorContains(t, err, "expected KEY=VALUE")
+}
+
+func TestConfigReloaderLoad(t *testing.T) {
+	t.Setenv("MCP_CORS_MODE", "development")
+	t.Setenv("MCP_RATE_LIMIT_SESSION", "")
+	os.Unsetenv("MCP_RATE_LIMIT_SESSION")
+
+	var logs bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&logs)
+
+	path := filepath.Join(t.TempDir(), "mcp.env")
+	require.NoError(t, os.WriteFile(path, []byte("MCP_CORS_MODE=strict\nMCP_RATE_LIMIT_SESSION=2:4\nTFE_TOKEN=secret\n"), 0600))
+
+	reloader := newConfigReloader(path, logger)
+	changes, err := reloader.load()
+	require.NoError(t, err)
+	assert.Equal(t, []configChange{
+		{key: "MCP_CORS_MODE", oldValue: "development", newValue: "strict"},
+		{key: "MCP_RATE_LIMIT_SESSION", oldValue: "", newValue: "2:4"},
+	}, changes)
+	assert.Equal(t, "strict", os.Getenv("MCP_CORS_MODE"))
+	assert.Empty(t, os.Getenv("TFE_TOKEN"), "settings that cannot be reloaded are ignored")
+	assert.Contains(t, logs.String(), "Ignoring TFE_TOKEN")
+
+	// Keys removed from the file get back the value they had at startup
+	require.NoError(t, os.WriteFile(path, []byte("MCP_CORS_MODE=strict\n"), 0600))
+	changes, err = reloader.load()
+	require.NoError(t, err)
+	assert.Equal(t, []configChange{{key: "MCP_RATE_LIMIT_SESSION", oldValue: "2:4", newValue: ""}}, changes)
+	_, set := os.LookupEnv("MCP_RATE_LIMIT_SESSION")
+	assert.False(t, set)
+
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+	changes, err = reloader.load()
+	require.NoError(t, err)
+	assert.Equal(t, []configChange{{key: "MCP_CORS_MODE", oldValue: "strict", newValue: "development"}}, changes)
+}
+
+func TestConfigReloadUpdatesCORS(t *testing.T) {
+	t.Setenv("MCP_CORS_MODE", "strict")
+	t.Setenv("MCP_ALLOWED_ORIGINS", "https://a.example.com")
+
+	var logs bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&logs)
+
+	registered := false
+	cfg := testConfig(&registered)
+	handler := NewHTTPHandler(t.Context(), cfg, NewServer(t.Context(), cfg, logger), logger, "/mcp")
+
+	status := func(origin string) int {
+		req := httptest.NewRequest(http.MethodOptions, "/mcp", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	require.Equal(t, http.StatusForbidden, status("https://b.example.com"))
+
+	path := filepath.Join(t.TempDir(), "mcp.env")
+	require.NoError(t, os.WriteFile(path, []byte("MCP_ALLOWED_ORIGINS=https://b.example.com\n"), 0600))
+	newConfigReloader(path, logger).reload("sighup")
+
+	assert.Equal(t, http.StatusOK, status("https://b.example.com"))
+	assert.Equal(t, http.StatusForbidden, status("https://a.example.com"))
+	assert.Contains(t, logs.String(), "audit=config_reload")
+	assert.Contains(t, logs.String(), "key=MCP_ALLOWED_ORIGINS")
+}
+
+func TestConfigReloadHooksUnregister(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	// The hooks of the servers of the other tests may still be going away, only the new ones are counted
+	reloadHooksMu.Lock()
+	firstID := reloadHookNextID
+	reloadHooksMu.Unlock()
+	hookCount := func() int {
+		reloadHooksMu.Lock()
+		defer reloadHooksMu.Unlock()
+		count := 0
+		for _, hook := range reloadHooks {
+			if hook.id >= firstID {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The hooks of the rate limiter and of the security handlers go away with the server
+	ctx, cancel := context.WithCancel(context.Background())
+	registered := false
+	cfg := testConfig(&registered)
+	NewHTTPHandler(ctx, cfg, NewServer(ctx, cfg, logger), logger, "/mcp")
+	assert.Equal(t, 2, hookCount())
+	cancel()
+	assert.Eventually(t, func() bool { return hookCount() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestConfigWatchInterval(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	t.Setenv(ConfigWatchInterval, "")
+	assert.Equal(t, defaultConfigWatchInterval, configWatchInterval(logger))
+	t.Setenv(ConfigWatchInterval, "30s")
+	assert.Equal(t, 30*time.Second, configWatchInterval(logger))
+	t.Setenv(ConfigWatchInterval, "never")
+	assert.Equal(t, defaultConfigWatchInterval, configWatchInterval(logger))
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolSchemaCache provides the input schemas of the registered tools to the input validation middleware.
+// Tools can be registered after the server starts, e.g. the HCP Terraform tools once a session has a
+// token, so an unknown tool refreshes the cache from the server's own tool list.
+type toolSchemaCache struct {
+	mu       sync.Mutex
+	hcServer *server.MCPServer
+	schemas  map[string]map[string]any
+}
+
+func (c *toolSchemaCache) lookup(ctx context.Context, toolName string) (map[string]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if schema, ok := c.schemas[toolName]; ok {
+		return schema, true
+	}
+	if c.hcServer == nil {
+		return nil, false
+	}
+
+	c.schemas = listToolSchemas(context.WithoutCancel(ctx), c.hcServer)
+	schema, ok := c.schemas[toolName]
+	return schema, ok
+}
+
+// annotations returns the annotations of a registered tool, preferring the tools of the session
+func (c *toolSchemaCache) annotations(ctx context.Context, toolName string) (mcp.ToolAnnotation, bool) {
+	if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithTools); ok {
+		if tool, ok := session.GetSessionTools()[toolName]; ok {
+			return tool.Tool.Annotations, true
+		}
+	}
+	if c.hcServer == nil {
+		return mcp.ToolAnnotation{}, false
+	}
+	if tool := c.hcServer.GetTool(toolName); tool != nil {
+		return tool.Tool.Annotations, true
+	}
+	return mcp.ToolAnnotation{}, false
+}
+
+// listTools returns the tools of the server as they are listed to clients, after the tool filters
+func listTools(ctx context.Context, hcServer *server.MCPServer) []mcp.Tool {
+	request := mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(0),
+		Request: mcp.Request{Method: string(mcp.MethodToolsList)},
+	}
+	message, err := json.Marshal(request)
+	if err != nil {
+		return nil
+	}
+
+	response, ok := hcServer.HandleMessage(ctx, message).(mcp.JSONRPCResponse)
+	if !ok {
+		return nil
+	}
+	result, ok := response.Result.(mcp.ListToolsResult)
+	if !ok {
+		return nil
+	}
+	return result.Tools
+}
+
+// listToolSchemas returns the input schema of every tool registered with the server
+func listToolSchemas(ctx context.Context, hcServer *server.MCPServer) map[string]map[string]any {
+	tools := listTools(ctx, hcServer)
+	schemas := make(map[string]map[string]any, len(tools))
+	for _, tool := range tools {
+		// Round-trip through JSON so the schemas have the same shape as decoded tool arguments
+		raw, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			continue
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			continue
+		}
+		schemas[tool.Name] = decoded
+	}
+	return schemas
+}
@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolSchemaCache(t *testing.T) {
+	hcServer := server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true))
+	hcServer.AddTool(mcp.NewTool("first", mcp.WithString("name", mcp.Enum("a", "b"))), nil)
+	cache := &toolSchemaCache{hcServer: hcServer}
+
+	schema, ok := cache.lookup(t.Context(), "first")
+	require.True(t, ok)
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"a", "b"}}, schema["properties"].(map[string]any)["name"])
+
+	_, ok = cache.lookup(t.Context(), "second")
+	assert.False(t, ok)
+
+	// Tools registered later are picked up on the next lookup
+	hcServer.AddTool(mcp.NewTool("second", mcp.WithNumber("limit")), nil)
+	schema, ok = cache.lookup(t.Context(), "second")
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"type": "number"}, schema["properties"].(map[string]any)["limit"])
+}
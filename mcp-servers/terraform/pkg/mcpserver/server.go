@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package mcpserver contains the bootstrap shared by the MCP servers in this repository:
+// logger initialization, transport selection, the CORS/security handler, rate limiting,
+// the health endpoints and signal handling. A server only describes itself with a Config.
+package mcpserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// SessionHandler is called when an MCP client session is registered or unregistered
+type SessionHandler func(ctx context.Context, session server.ClientSession, logger *log.Logger)
+
+// Config describes an MCP server built on the shared bootstrap
+type Config struct {
+	// Name is the name of the server and its binary, e.g. terraform-mcp-server
+	Name string
+	// Title is the human readable name of the server, e.g. Terraform MCP Server
+	Title string
+	// Description is the long description of the root command
+	Description string
+	// Version is the version reported to MCP clients
+	Version string
+	// BuildInfo is printed by --version, e.g. the version, commit and build date
+	BuildInfo string
+
+	// Register adds the tools, resources and prompts of the server
+	Register func(hcServer *server.MCPServer, logger *log.Logger)
+	// ContextMiddleware adds per-request configuration, e.g. credentials from HTTP headers, to the request context
+	ContextMiddleware func(logger *log.Logger) func(http.Handler) http.Handler
+	// OnRegisterSession and OnUnregisterSession manage the per-session clients
+	OnRegisterSession   SessionHandler
+	OnUnregisterSession SessionHandler
+	// ReadinessProbes returns the probes checking the dependencies of the server for the /readyz endpoint
+	ReadinessProbes func(logger *log.Logger) []Probe
+	// SelfTest checks the upstream APIs of the server and writes a report to out. It is run by the
+	// --selftest flag, which exits non-zero when it returns an error.
+	SelfTest func(ctx context.Context, logger *log.Logger, out io.Writer) error
+	// Preflight checks the settings of the server for the doctor command, e.g. its credentials and
+	// certificates, after the transport settings and the readiness probes are checked
+	Preflight func(ctx context.Context, logger *log.Logger) []Check
+	// Capabilities reports the feature flags of the server for the session of ctx, e.g. the toolsets
+	// enabled. They are served as JSON by the server://capabilities resource and in the _meta of the
+	// initialize result.
+	Capabilities func(ctx context.Context, logger *log.Logger) any
+
+	// ServerOptions are appended to the default MCP server options
+	ServerOptions []server.ServerOption
+	// ToolMiddleware wraps the tool handlers inside the shared middlewares, after the arguments are
+	// validated, e.g. to enforce the policies of the server. schemas returns the input schemas of the tools.
+	ToolMiddleware func(logger *log.Logger, schemas client.ToolSchemaLookup) server.ToolHandlerMiddleware
+
+	// SSECompat additionally mounts the legacy HTTP+SSE endpoints in StreamableHTTP mode.
+	// It is set by the --sse-compat flag, MCP_SSE_COMPAT=true enables it as well.
+	SSECompat bool
+	// TransportSocket is the path of a Unix domain socket the StreamableHTTP server listens on
+	// instead of TCP. It is set by the --transport-socket flag or TRANSPORT_SOCKET.
+	TransportSocket string
+
+	// bindHost is the address the StreamableHTTP server listens on, it selects the default allowed hosts
+	bindHost string
+}
+
+// InitLogger creates the logger, writing debug logs to outPath when it is set.
+// The level, the format and the rotation of the log file are read from MCP_LOG_*.
+func InitLogger(outPath string) (*log.Logger, error) {
+	return newLogger(outPath, LoadLogConfigFromEnv())
+}
+
+// NewServer creates the MCP server with rate limiting and session hooks and registers its tools.
+// The server stops listening to the throttled responses of the upstream services and to the
+// configuration reloads when ctx is done.
+func NewServer(ctx context.Context, cfg Config, logger *log.Logger) *server.MCPServer {
+	// Create rate limiting middleware with environment-based configuration
+	rateLimitConfig := client.LoadRateLimitConfigFromEnv()
+	rateLimitMiddleware := client.NewRateLimitMiddleware(rateLimitConfig, logger)
+	context.AfterFunc(ctx, client.OnUpstreamThrottled(rateLimitMiddleware.UpstreamThrottled))
+
+	// Cancel the context of tool calls when the client sends notifications/cancelled
+	canceller := client.NewToolCallCanceller(logger)
+
+	// Forward server events to the clients as MCP log notifications
+	notifier := newClientNotifier(client.LoadClientLogLevelFromEnv())
+	context.AfterFunc(ctx, client.OnUpstreamThrottled(notifier.upstreamThrottled))
+	context.AfterFunc(ctx, onConfigReload(func() {
+		rateLimitMiddleware.UpdateConfig(client.LoadRateLimitConfigFromEnv())
+	}))
+
+	// Validate tool arguments against the size limits and the tool schemas before dispatch
+	schemas := &toolSchemaCache{}
+	inputValidationMiddleware := client.NewInputValidationMiddleware(client.LoadInputLimitsConfigFromEnv(), schemas.lookup, logger)
+
+	// Post the calls of the selected tools to a Slack or generic HTTP webhook. An invalid configuration
+	// stops the server, so that the changes it should report are never made silently.
+	notificationConfig, err := client.LoadToolNotificationConfigFromEnv()
+	if err != nil {
+		logger.Fatalf("Invalid tool notifications: %v", err)
+	}
+
+	// Add default options. The response budget sits inside the error code middleware so that
+	// truncation never touches error results, and every tool accepts max_response_bytes.
+	// Secrets are masked in every result, including the errors turned into results.
+	opts := []server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(true, true),
+		server.WithLogging(),
+		server.WithElicitation(),
+		server.WithToolHandlerMiddleware(client.NewRequestIDMiddleware()),
+		server.WithToolHandlerMiddleware(canceller.Middleware()),
+		server.WithToolHandlerMiddleware(client.NewRedactionMiddleware()),
+		server.WithToolHandlerMiddleware(client.NewErrorCodeMiddleware()),
+		server.WithToolHandlerMiddleware(client.NewResponseBudgetMiddleware(client.LoadMaxToolResponseBytesFromEnv(), logger)),
+		server.WithToolHandlerMiddleware(client.NewToolLoggingMiddleware(logger)),
+		server.WithToolHandlerMiddleware(rateLimitMiddleware.Middleware()),
+		server.WithToolHandlerMiddleware(inputValidationMiddleware.Middleware()),
+		server.WithToolFilter(client.WithResponseBudgetArgument()),
+	}
+	if notificationConfig.Enabled() {
+		opts = append(opts, server.WithToolHandlerMiddleware(client.NewToolNotifier(notificationConfig, schemas.annotations, logger).Middleware()))
+		logger.Infof("Posting tool notifications in %s format", notificationConfig.Format)
+	}
+	if cfg.ToolMiddleware != nil {
+		opts = append(opts, server.WithToolHandlerMiddleware(cfg.ToolMiddleware(logger, schemas.lookup)))
+	}
+	opts = append(opts, cfg.ServerOptions...)
+
+	// Create hooks for session management
+	hooks := &server.Hooks{}
+	hooks.AddOnRegisterSession(notifier.registerSession)
+	hooks.AddOnUnregisterSession(notifier.unregisterSession)
+	hooks.AddBeforeCallTool(canceller.BeforeCallTool)
+	hooks.AddAfterCallTool(canceller.AfterCallTool)
+	hooks.AddOnError(canceller.OnError)
+	if cfg.OnRegisterSession != nil {
+		hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+			cfg.OnRegisterSession(ctx, session, logger)
+		})
+	}
+	if cfg.OnUnregisterSession != nil {
+		hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+			cfg.OnUnregisterSession(ctx, session, logger)
+		})
+	}
+
+	if cfg.Capabilities != nil {
+		hooks.AddAfterInitialize(advertiseCapabilities(cfg, logger))
+	}
+
+	// Add hooks to options
+	opts = append(opts, server.WithHooks(hooks))
+
+	// Create a new MCP server
+	hcServer := server.NewMCPServer(cfg.Name, cfg.Version, opts...)
+	schemas.hcServer = hcServer
+	notifier.mu.Lock()
+	notifier.hcServer = hcServer
+	notifier.mu.Unlock()
+	hcServer.AddNotificationHandler(client.CancelledNotification, canceller.HandleCancelled)
+	if cfg.Register != nil {
+		cfg.Register(hcServer, logger)
+	}
+	if cfg.Capabilities != nil {
+		hcServer.AddResource(capabilitiesResource(cfg, logger))
+	}
+	return hcServer
+}
@@ -0,0 +1,247 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig(registered *bool) Config {
+	return Config{
+		Name:    "test-mcp-server",
+		Title:   "Test MCP Server",
+		Version: "0.0.1",
+		Register: func(_ *server.MCPServer, _ *log.Logger) {
+			*registered = true
+		},
+	}
+}
+
+func TestNewServerRegistersTools(t *testing.T) {
+	registered := false
+	hcServer := NewServer(t.Context(), testConfig(&registered), log.New())
+	require.NotNil(t, hcServer)
+	assert.True(t, registered)
+}
+
+func TestHTTPHandlerHealthEndpoint(t *testing.T) {
+	registered := false
+	cfg := testConfig(&registered)
+	middlewareApplied := false
+	cfg.ContextMiddleware = func(_ *log.Logger) func(http.Handler) http.Handler {
+		middlewareApplied = true
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	handler := NewHTTPHandler(t.Context(), cfg, NewServer(t.Context(), cfg, logger), logger, "custom")
+	assert.True(t, middlewareApplied)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok","service":"test-mcp-server","transport":"streamable-http","endpoint":"/custom"}`, rec.Body.String())
+}
+
+func TestNewRootCommand(t *testing.T) {
+	registered := false
+	cmd := NewRootCommand(testConfig(&registered))
+	assert.Equal(t, "test-mcp-server", cmd.Use)
+
+	names := []string{}
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"stdio", "streamable-http", "http", "grpc", "doctor", "tools", "healthcheck", "manifest"}, names)
+
+	streamable, _, err := cmd.Find([]string{"streamable-http"})
+	require.NoError(t, err)
+	port, err := streamable.Flags().GetString("transport-port")
+	require.NoError(t, err)
+	assert.Equal(t, "8080", port)
+}
+
+func TestNewServerValidatesToolArguments(t *testing.T) {
+	cfg := Config{
+		Name:    "test-mcp-server",
+		Version: "0.0.1",
+		Register: func(hcServer *server.MCPServer, _ *log.Logger) {
+			hcServer.AddTool(mcp.NewTool("echo", mcp.WithString("message")), func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText(request.GetString("message", "")), nil
+			})
+		},
+	}
+	hcServer := NewServer(t.Context(), cfg, log.New())
+
+	call := func(arguments string) mcp.CallToolResult {
+		message := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":` + arguments + `}}`
+		response, ok := hcServer.HandleMessage(t.Context(), json.RawMessage(message)).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result, ok := response.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		return result
+	}
+
+	assert.False(t, call(`{"message":"hello"}`).IsError)
+	assert.True(t, call(`{"message":42}`).IsError)
+}
+
+func TestNewServerToolMiddleware(t *testing.T) {
+	var seen []string
+	cfg := Config{
+		Name:    "test-mcp-server",
+		Version: "0.0.1",
+		Register: func(hcServer *server.MCPServer, _ *log.Logger) {
+			hcServer.AddTool(mcp.NewTool("echo", mcp.WithString("message")), func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText(request.GetString("message", "")), nil
+			})
+		},
+		ToolMiddleware: func(_ *log.Logger, _ client.ToolSchemaLookup) server.ToolHandlerMiddleware {
+			return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+				return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					seen = append(seen, request.GetString("message", ""))
+					return next(ctx, request)
+				}
+			}
+		},
+	}
+	hcServer := NewServer(t.Context(), cfg, log.New())
+
+	for _, arguments := range []string{`{"message":"hello"}`, `{"message":42}`} {
+		message := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":` + arguments + `}}`
+		hcServer.HandleMessage(t.Context(), json.RawMessage(message))
+	}
+	// Invalid arguments are rejected before the server middleware runs
+	assert.Equal(t, []string{"hello"}, seen)
+}
+
+func TestHTTPHandlerSSECompat(t *testing.T) {
+	registered := false
+	cfg := testConfig(&registered)
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	// The legacy endpoints are only mounted in compatibility mode
+	rec := httptest.NewRecorder()
+	NewHTTPHandler(t.Context(), cfg, NewServer(t.Context(), cfg, logger), logger, "/mcp").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sse", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	cfg.SSECompat = true
+	ts := httptest.NewServer(NewHTTPHandler(t.Context(), cfg, NewServer(t.Context(), cfg, logger), logger, "/mcp"))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/sse")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The first event tells the client where to post its messages
+	reader := bufio.NewReader(resp.Body)
+	event, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: endpoint\n", event)
+	data, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	endpoint := strings.TrimSpace(strings.TrimPrefix(data, "data: "))
+	assert.True(t, strings.HasPrefix(endpoint, "/message?sessionId="), endpoint)
+
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	postResp, err := http.Post(ts.URL+endpoint, "application/json", strings.NewReader(ping))
+	require.NoError(t, err)
+	postResp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, postResp.StatusCode)
+
+	// The response is delivered on the SSE stream
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, "data: ") && strings.Contains(line, `"id":1`) {
+			assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{}}`, strings.TrimPrefix(strings.TrimSpace(line), "data: "))
+			break
+		}
+	}
+}
+
+func TestHTTPHandlerRunEventWebhooks(t *testing.T) {
+	registered := false
+	cfg := testConfig(&registered)
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	// The webhook endpoints are only mounted with a token to authenticate them
+	rec := httptest.NewRecorder()
+	NewHTTPHandler(t.Context(), cfg, NewServer(t.Context(), cfg, logger), logger, "/mcp").ServeHTTP(rec, httptest.NewRequest(http.MethodPost, client.AtlantisWebhookPath, strings.NewReader("{}")))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	t.Setenv(client.WebhookToken, "secret")
+	rec = httptest.NewRecorder()
+	NewHTTPHandler(t.Context(), cfg, NewServer(t.Context(), cfg, logger), logger, "/mcp").ServeHTTP(rec, httptest.NewRequest(http.MethodPost, client.AtlantisWebhookPath, strings.NewReader("{}")))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestServeStreamableHTTPUnixSocket(t *testing.T) {
+	registered := false
+	cfg := testConfig(&registered)
+	cfg.TransportSocket = filepath.Join(t.TempDir(), "mcp.sock")
+	t.Setenv("TRANSPORT_SOCKET_MODE", "0600")
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	// A stale socket file from a previous run is replaced
+	stale, err := net.Listen("unix", cfg.TransportSocket)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errC := make(chan error, 1)
+	go func() {
+		errC <- ServeStreamableHTTP(ctx, cfg, NewServer(t.Context(), cfg, logger), logger, "", "", "/mcp")
+	}()
+
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", cfg.TransportSocket)
+		},
+	}}
+	require.Eventually(t, func() bool {
+		resp, err := httpClient.Get("http://unix/healthz")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	info, err := os.Stat(cfg.TransportSocket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// A socket that is still served is not taken over
+	_, _, err = listenHTTP(cfg, "", "")
+	assert.ErrorContains(t, err, "already in use")
+
+	cancel()
+	require.NoError(t, <-errC)
+	_, err = os.Stat(cfg.TransportSocket)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// contextHeaderKey is the context key of a setting read by ContextHeaders
+type contextHeaderKey string
+
+// ContextHeaders are the settings of the upstream service a server reads for each StreamableHTTP request,
+// e.g. its address and credentials. Each setting is read from the HTTP header of the same name, then the
+// query parameter, then the environment variable.
+type ContextHeaders struct {
+	// Service names the upstream service in the logs and errors, e.g. Vault
+	Service string
+	// Address is the setting holding the address of the service
+	Address string
+	// Token is the setting holding the credentials of the service, which is never read from the query
+	// parameters: URLs end up in access logs and browser histories
+	Token string
+	// Others are the other settings, e.g. the namespace
+	Others []string
+}
+
+// Middleware adds the settings to the request context, to be read with ContextValue. It is the
+// ContextMiddleware of the Config.
+func (h ContextHeaders) Middleware(logger *log.Logger) func(http.Handler) http.Handler {
+	headers := append([]string{h.Address, h.Token}, h.Others...)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			for _, header := range headers {
+				value := r.Header.Get(textproto.CanonicalMIMEHeaderKey(header))
+				if value == "" {
+					value = r.URL.Query().Get(header)
+					if header == h.Token && value != "" {
+						logger.Infof("%s was provided in query parameters by client %v, terminating request", h.Token, r.RemoteAddr)
+						http.Error(w, fmt.Sprintf("%s should not be provided in query parameters for security reasons, use the %s header", h.Token, strings.ToLower(h.Token)), http.StatusBadRequest)
+						return
+					}
+				}
+				if value == "" {
+					value = utils.GetEnv(header, "")
+				}
+				ctx = context.WithValue(ctx, contextHeaderKey(header), value)
+
+				// The values are not logged, they may be secrets
+				if header == h.Token && value != "" {
+					logger.Debugf("%s credentials provided via request context", h.Service)
+				} else if header == h.Address && value != "" {
+					logger.Debugf("%s address configured via request context", h.Service)
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ContextValue returns the setting key added to ctx by ContextHeaders, falling back to the environment
+// variable of the same name and then to defaultValue, e.g. on the stdio transport
+func ContextValue(ctx context.Context, key string, defaultValue string) string {
+	if value, ok := ctx.Value(contextHeaderKey(key)).(string); ok && value != "" {
+		return value
+	}
+	return utils.GetEnv(key, defaultValue)
+}
+
+// SessionClients holds the client of the upstream service of each MCP session. The client is created
+// from the settings of the request context when the session is registered, and dropped when it ends.
+type SessionClients[T any] struct {
+	// Service names the upstream service in the logs, e.g. Vault
+	Service string
+	// Create creates the client of a session, typically from the settings read with ContextValue
+	Create func(ctx context.Context, logger *log.Logger) (T, error)
+	// Missing tells what to configure when a session starts without a valid client
+	Missing string
+
+	clients sync.Map
+}
+
+// Get returns the client of the session
+func (c *SessionClients[T]) Get(sessionID string) (T, bool) {
+	value, ok := c.clients.Load(sessionID)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return value.(T), true
+}
+
+// FromContext returns the client of the session of ctx, creating it when the session has none yet
+func (c *SessionClients[T]) FromContext(ctx context.Context, logger *log.Logger) (T, error) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		var zero T
+		return zero, fmt.Errorf("no active session")
+	}
+	if client, ok := c.Get(session.SessionID()); ok {
+		return client, nil
+	}
+	logger.Warnf("%s client not found, creating a new one", c.Service)
+	return c.create(ctx, session.SessionID(), logger)
+}
+
+func (c *SessionClients[T]) create(ctx context.Context, sessionID string, logger *log.Logger) (T, error) {
+	client, err := c.Create(ctx, logger)
+	if err != nil {
+		return client, err
+	}
+	c.clients.Store(sessionID, client)
+	logger.WithField("session_id", sessionID).Infof("Created %s client", c.Service)
+	return client, nil
+}
+
+// OnRegisterSession creates the client of the session. It is the OnRegisterSession of the Config.
+func (c *SessionClients[T]) OnRegisterSession(ctx context.Context, session server.ClientSession, logger *log.Logger) {
+	if _, err := c.create(ctx, session.SessionID(), logger); err != nil {
+		logger.WithError(err).Warnf("Session has no valid %s client - %s tools will fail until %s", c.Service, c.Service, c.Missing)
+	}
+}
+
+// OnUnregisterSession drops the client of the session. It is the OnUnregisterSession of the Config.
+func (c *SessionClients[T]) OnUnregisterSession(_ context.Context, session server.ClientSession, logger *log.Logger) {
+	c.clients.Delete(session.SessionID())
+	logger.WithField("session_id", session.SessionID()).Info("Cleaned up clients for session")
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testContextHeaders = ContextHeaders{
+	Service: "Test",
+	Address: "TEST_ADDR",
+	Token:   "TEST_TOKEN",
+	Others:  []string{"TEST_NAMESPACE"},
+}
+
+func TestContextHeadersMiddleware(t *testing.T) {
+	t.Setenv("TEST_ADDR", "https://env.example.com")
+	t.Setenv("TEST_TOKEN", "env-token")
+
+	var got map[string]string
+	handler := testContextHeaders.Middleware(log.New())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = map[string]string{
+			"TEST_ADDR":      ContextValue(r.Context(), "TEST_ADDR", ""),
+			"TEST_TOKEN":     ContextValue(r.Context(), "TEST_TOKEN", ""),
+			"TEST_NAMESPACE": ContextValue(r.Context(), "TEST_NAMESPACE", "root"),
+		}
+	}))
+
+	t.Run("header then query then env", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/mcp?TEST_ADDR=https://query.example.com&TEST_NAMESPACE=team-a", nil)
+		req.Header.Set("TEST_TOKEN", "header-token")
+		req.Header.Set("TEST_NAMESPACE", "team-b")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, map[string]string{
+			"TEST_ADDR":      "https://query.example.com",
+			"TEST_TOKEN":     "header-token",
+			"TEST_NAMESPACE": "team-b",
+		}, got)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://env.example.com", got["TEST_ADDR"])
+		assert.Equal(t, "env-token", got["TEST_TOKEN"])
+		assert.Equal(t, "root", got["TEST_NAMESPACE"])
+	})
+
+	t.Run("token in query", func(t *testing.T) {
+		got = nil
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp?TEST_TOKEN=secret", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "use the test_token header")
+		assert.Nil(t, got)
+	})
+}
+
+func TestSessionClients(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	created := 0
+	clients := &SessionClients[*string]{
+		Service: "Test",
+		Create: func(ctx context.Context, _ *log.Logger) (*string, error) {
+			address := ContextValue(ctx, "TEST_ADDR", "")
+			if address == "" {
+				return nil, errors.New("TEST_ADDR is required")
+			}
+			created++
+			return &address, nil
+		},
+		Missing: "TEST_ADDR is set",
+	}
+
+	mcpServer := server.NewMCPServer("test", "0.0.0")
+	session := server.NewInProcessSession("session-1", nil)
+	ctx := mcpServer.WithContext(context.WithValue(t.Context(), contextHeaderKey("TEST_ADDR"), "https://test.example.com"), session)
+
+	_, err := clients.FromContext(t.Context(), logger)
+	assert.EqualError(t, err, "no active session")
+
+	clients.OnRegisterSession(ctx, session, logger)
+	client, ok := clients.Get("session-1")
+	require.True(t, ok)
+	assert.Equal(t, "https://test.example.com", *client)
+
+	fromContext, err := clients.FromContext(ctx, logger)
+	require.NoError(t, err)
+	assert.Same(t, client, fromContext)
+	assert.Equal(t, 1, created)
+
+	clients.OnUnregisterSession(ctx, session, logger)
+	_, ok = clients.Get("session-1")
+	assert.False(t, ok)
+
+	// The client is created again when a tool is called after the session lost it
+	fromContext, err = clients.FromContext(ctx, logger)
+	require.NoError(t, err)
+	assert.Equal(t, "https://test.example.com", *fromContext)
+	assert.Equal(t, 2, created)
+
+	// A session without a valid client is still registered
+	other := server.NewInProcessSession("session-2", nil)
+	clients.OnRegisterSession(mcpServer.WithContext(t.Context(), other), other, logger)
+	_, ok = clients.Get("session-2")
+	assert.False(t, ok)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket activation, after stdin, stdout and stderr
+const listenFDsStart = 3
+
+// activatedListener returns the socket passed by systemd socket activation, or nil when the server was not
+// started by a socket unit. LISTEN_PID, LISTEN_FDS and LISTEN_FDNAMES are unset, so that the processes started
+// by the server do not take the socket for theirs.
+func activatedListener() (net.Listener, string, error) {
+	pid, fds, names := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getenv("LISTEN_FDNAMES")
+	if fds == "" {
+		return nil, "", nil
+	}
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+	// The sockets were passed to another process, e.g. the shell of an ExecStart= command line
+	if pid != strconv.Itoa(os.Getpid()) {
+		return nil, "", nil
+	}
+
+	count, err := strconv.Atoi(fds)
+	if err != nil || count < 1 {
+		return nil, "", fmt.Errorf("invalid LISTEN_FDS %q", fds)
+	}
+	if count > 1 {
+		return nil, "", fmt.Errorf("systemd passed %d sockets, the server listens on one: the socket unit must have a single ListenStream=", count)
+	}
+
+	name := names
+	if name == "" {
+		name = "systemd socket"
+	}
+	file := os.NewFile(listenFDsStart, name)
+	// FileListener duplicates the descriptor, the original one is closed so that it does not leak to child processes
+	listener, err := net.FileListener(file)
+	file.Close()
+	if err != nil {
+		return nil, "", fmt.Errorf("using the socket passed by systemd: %w", err)
+	}
+
+	addr := listener.Addr().String()
+	if listener.Addr().Network() == "unix" {
+		addr = "unix:" + addr
+	}
+	return listener, addr, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivatedListenerEnv(t *testing.T) {
+	t.Setenv("LISTEN_FDS", "")
+	listener, _, err := activatedListener()
+	require.NoError(t, err)
+	assert.Nil(t, listener)
+
+	// The sockets of another process are ignored, and not passed on
+	t.Setenv("LISTEN_PID", "1")
+	t.Setenv("LISTEN_FDS", "1")
+	listener, _, err = activatedListener()
+	require.NoError(t, err)
+	assert.Nil(t, listener)
+	_, set := os.LookupEnv("LISTEN_FDS")
+	assert.False(t, set)
+
+	for _, fds := range []string{"0", "two", "2"} {
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		t.Setenv("LISTEN_FDS", fds)
+		_, _, err := activatedListener()
+		assert.Error(t, err, fds)
+	}
+}
+
+// TestActivatedListener passes a listening socket as file descriptor 3 to a child process, like systemd does
+func TestActivatedListener(t *testing.T) {
+	if os.Getenv("MCP_TEST_SOCKET_ACTIVATION") == "1" {
+		// systemd sets LISTEN_PID to the PID of the server, which the parent does not know before starting it
+		os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		listener, addr, err := activatedListener()
+		if err != nil || listener == nil {
+			fmt.Fprintf(os.Stderr, "no activated listener: %v\n", err)
+			os.Exit(1)
+		}
+		conn, err := listener.Accept()
+		if err != nil {
+			os.Exit(1)
+		}
+		fmt.Fprintf(conn, "%s %s", addr, os.Getenv("LISTEN_FDS"))
+		conn.Close()
+		os.Exit(0)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	file, err := listener.(*net.TCPListener).File()
+	require.NoError(t, err)
+	defer file.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestActivatedListener$")
+	cmd.Env = append(os.Environ(), "MCP_TEST_SOCKET_ACTIVATION=1", "LISTEN_FDS=1", "LISTEN_FDNAMES=mcp.socket")
+	cmd.ExtraFiles = []*os.File{file}
+	cmd.Stderr = os.Stderr
+	require.NoError(t, cmd.Start())
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	response, err := io.ReadAll(conn)
+	conn.Close()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Wait())
+	// The variables are unset once the socket is taken
+	assert.Equal(t, listener.Addr().String()+" ", string(response))
+}
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// toolsCommand is the name of the command printing the tools of the server without starting a transport
+const toolsCommand = "tools"
+
+// newToolsCommand creates the tools command with its list and describe subcommands. The tools are the
+// ones a client would list with the current environment, e.g. the module source tools need a GitHub token.
+func newToolsCommand(cfg Config, rootCmd *cobra.Command) *cobra.Command {
+	toolsCmd := &cobra.Command{
+		Use:   toolsCommand,
+		Short: "Print the tools of the server",
+		Long:  `Print the names, input schemas and annotations of the tools of the server without starting a transport, e.g. to diff the tools of two versions or to generate client configuration.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the tools of the server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			tools := serverTools(cfg, toolsCommandLogger(rootCmd))
+			if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+				return writeJSON(cmd.OutOrStdout(), tools)
+			}
+			writeToolList(cmd.OutOrStdout(), tools)
+			return nil
+		},
+	}
+	listCmd.Flags().Bool("json", false, "Print the tools as JSON, with their input schemas and annotations")
+
+	describeCmd := &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Print the description, input schema and annotations of a tool as JSON",
+		Args:  cobra.ExactArgs(1),
+		// An unknown tool is not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tools := serverTools(cfg, toolsCommandLogger(rootCmd))
+			for _, tool := range tools {
+				if tool.Name == args[0] {
+					return writeJSON(cmd.OutOrStdout(), tool)
+				}
+			}
+			return fmt.Errorf("unknown tool %q, run '%s tools list' for the tools of the server", args[0], cfg.Name)
+		},
+	}
+
+	toolsCmd.AddCommand(listCmd, describeCmd)
+	return toolsCmd
+}
+
+// toolsCommandLogger only logs warnings unless MCP_LOG_LEVEL is set, the tools are the output of the command
+func toolsCommandLogger(rootCmd *cobra.Command) *log.Logger {
+	logger := commandLogger(rootCmd)
+	if os.Getenv(LogLevel) == "" {
+		logger.SetLevel(log.WarnLevel)
+	}
+	return logger
+}
+
+// serverTools creates the server and returns its tools sorted by name, as listed to clients
+func serverTools(cfg Config, logger *log.Logger) []mcp.Tool {
+	tools := listTools(context.Background(), NewServer(context.Background(), cfg, logger))
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// writeToolList prints one tool per line with its annotations and the first line of its description
+func writeToolList(out io.Writer, tools []mcp.Tool) {
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tANNOTATIONS\tDESCRIPTION")
+	for _, tool := range tools {
+		description, _, _ := strings.Cut(strings.TrimSpace(tool.Description), "\n")
+		fmt.Fprintf(table, "%s\t%s\t%s\n", tool.Name, toolHints(tool.Annotations), description)
+	}
+	_ = table.Flush()
+}
+
+// toolHints lists the hints of a tool that are set to true, e.g. "read-only,idempotent". The destructive
+// hint only applies to tools that are not read-only.
+func toolHints(annotations mcp.ToolAnnotation) string {
+	if annotations.ReadOnlyHint != nil && *annotations.ReadOnlyHint {
+		annotations.DestructiveHint = nil
+	}
+	var hints []string
+	for _, hint := range []struct {
+		name  string
+		value *bool
+	}{
+		{"read-only", annotations.ReadOnlyHint},
+		{"destructive", annotations.DestructiveHint},
+		{"idempotent", annotations.IdempotentHint},
+		{"open-world", annotations.OpenWorldHint},
+	} {
+		if hint.value != nil && *hint.value {
+			hints = append(hints, hint.name)
+		}
+	}
+	if len(hints) == 0 {
+		return "-"
+	}
+	return strings.Join(hints, ",")
+}
+
+func writeJSON(out io.Writer, value any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
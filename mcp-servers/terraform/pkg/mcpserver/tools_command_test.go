@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func toolsTestConfig() Config {
+	return Config{
+		Name:    "test-mcp-server",
+		Version: "0.0.1",
+		Register: func(hcServer *server.MCPServer, _ *log.Logger) {
+			noop := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) { return nil, nil }
+			hcServer.AddTool(mcp.NewTool("search_modules",
+				mcp.WithDescription("Searches the registry for modules.\nMore details."),
+				mcp.WithString("module_query", mcp.Required()),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithOpenWorldHintAnnotation(true),
+			), noop)
+			hcServer.AddTool(mcp.NewTool("delete_workspace",
+				mcp.WithDescription("Deletes a workspace."),
+				mcp.WithReadOnlyHintAnnotation(false),
+				mcp.WithDestructiveHintAnnotation(true),
+			), noop)
+		},
+	}
+}
+
+func runToolsCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewRootCommand(toolsTestConfig())
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(append([]string{toolsCommand}, args...))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestToolsListCommand(t *testing.T) {
+	out, err := runToolsCommand(t, "list")
+	require.NoError(t, err)
+	assert.Equal(t, `NAME              ANNOTATIONS             DESCRIPTION
+delete_workspace  destructive,open-world  Deletes a workspace.
+search_modules    read-only,open-world    Searches the registry for modules.
+`, out)
+
+	out, err = runToolsCommand(t, "list", "--json")
+	require.NoError(t, err)
+	var tools []mcp.Tool
+	require.NoError(t, json.Unmarshal([]byte(out), &tools))
+	require.Len(t, tools, 2)
+	assert.Equal(t, "delete_workspace", tools[0].Name)
+	// The tools are listed as clients see them, with the arguments added by the shared middlewares
+	assert.Contains(t, tools[1].InputSchema.Properties, "max_response_bytes")
+	assert.Equal(t, []string{"module_query"}, tools[1].InputSchema.Required)
+}
+
+func TestToolsDescribeCommand(t *testing.T) {
+	out, err := runToolsCommand(t, "describe", "search_modules")
+	require.NoError(t, err)
+	var tool mcp.Tool
+	require.NoError(t, json.Unmarshal([]byte(out), &tool))
+	assert.Equal(t, "Searches the registry for modules.\nMore details.", tool.Description)
+	require.NotNil(t, tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.InputSchema.Properties, "module_query")
+
+	_, err = runToolsCommand(t, "describe", "apply_run")
+	assert.EqualError(t, err, `unknown tool "apply_run", run 'test-mcp-server tools list' for the tools of the server`)
+}
@@ -0,0 +1,315 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	stdlog "log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// RunStdioServer runs the server on stdio until it receives SIGINT or SIGTERM
+func RunStdioServer(cfg Config, logger *log.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	withTransportField(logger, "stdio")
+	if err := startConfigReload(ctx, logger); err != nil {
+		return err
+	}
+	if err := client.StartRegistryCacheRefresh(ctx, logger); err != nil {
+		return err
+	}
+	defer client.FlushRegistryCache()
+	return ServeStdio(ctx, cfg, NewServer(ctx, cfg, logger), logger)
+}
+
+// RunHTTPServer runs the server on the StreamableHTTP transport until it receives SIGINT or SIGTERM
+func RunHTTPServer(cfg Config, logger *log.Logger, host string, port string, endpointPath string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	withTransportField(logger, "streamable-http")
+	if err := startConfigReload(ctx, logger); err != nil {
+		return err
+	}
+	if err := client.StartRegistryCacheRefresh(ctx, logger); err != nil {
+		return err
+	}
+	defer client.FlushRegistryCache()
+	return ServeStreamableHTTP(ctx, cfg, NewServer(ctx, cfg, logger), logger, host, port, endpointPath)
+}
+
+// ServeStdio serves hcServer on stdin/stdout until ctx is done
+func ServeStdio(ctx context.Context, cfg Config, hcServer *server.MCPServer, logger *log.Logger) error {
+	stdioServer := server.NewStdioServer(hcServer)
+	stdLogger := stdlog.New(logger.Writer(), "stdioserver", 0)
+	stdioServer.SetErrorLogger(stdLogger)
+
+	// Start listening for messages
+	errC := make(chan error, 1)
+	go func() {
+		in, out := io.Reader(os.Stdin), io.Writer(os.Stdout)
+		errC <- stdioServer.Listen(ctx, in, out)
+	}()
+
+	_, _ = fmt.Fprintf(os.Stderr, "%s running on stdio\n", cfg.Title)
+
+	// Wait for shutdown signal
+	select {
+	case <-ctx.Done():
+		logger.Infof("shutting down server...")
+	case err := <-errC:
+		if err != nil {
+			return fmt.Errorf("error running server: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ServeStreamableHTTP serves hcServer on the StreamableHTTP transport until ctx is done
+func ServeStreamableHTTP(ctx context.Context, cfg Config, hcServer *server.MCPServer, logger *log.Logger, host string, port string, endpointPath string) error {
+	if err := client.LoadCORSConfigFromEnv().Validate(); err != nil {
+		return fmt.Errorf("invalid MCP_ALLOWED_ORIGINS: %w", err)
+	}
+	// A socket passed by systemd socket activation replaces the configured host, port and socket
+	listener, addr, err := activatedListener()
+	if err != nil {
+		return fmt.Errorf("StreamableHTTP server error: %w", err)
+	}
+
+	// Host headers are only checked for TCP listeners, a Unix domain socket cannot be reached through DNS rebinding
+	switch {
+	case listener != nil:
+		if tcpAddr, ok := listener.Addr().(*net.TCPAddr); ok {
+			cfg.bindHost = tcpAddr.IP.String()
+		}
+	case cfg.TransportSocket == "" && GetHTTPSocket() == "":
+		cfg.bindHost = host
+	}
+	mux := NewHTTPHandler(ctx, cfg, hcServer, logger, endpointPath)
+
+	if listener == nil {
+		listener, addr, err = listenHTTP(cfg, host, port)
+		if err != nil {
+			return fmt.Errorf("StreamableHTTP server error: %w", err)
+		}
+	} else {
+		logger.Infof("Using the socket passed by systemd, ignoring the configured address")
+	}
+
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	// Start server in goroutine
+	errC := make(chan error, 1)
+	go func() {
+		logger.Infof("Starting StreamableHTTP server on %s%s", addr, path.Join("/", endpointPath))
+		errC <- httpServer.Serve(listener)
+	}()
+
+	// Wait for shutdown signal
+	select {
+	case <-ctx.Done():
+		logger.Infof("Shutting down StreamableHTTP server...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	case err := <-errC:
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("StreamableHTTP server error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// listenHTTP listens on the Unix domain socket of the server when one is configured, otherwise on host:port
+func listenHTTP(cfg Config, host string, port string) (net.Listener, string, error) {
+	socketPath := cfg.TransportSocket
+	if socketPath == "" {
+		socketPath = GetHTTPSocket()
+	}
+	if socketPath == "" {
+		addr := net.JoinHostPort(host, port)
+		listener, err := net.Listen("tcp", addr)
+		return listener, addr, err
+	}
+
+	// Remove a socket left behind by a previous run, but never one that is still served
+	if info, err := os.Lstat(socketPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, "", fmt.Errorf("%s exists and is not a socket", socketPath)
+		}
+		if conn, err := net.Dial("unix", socketPath); err == nil {
+			conn.Close()
+			return nil, "", fmt.Errorf("socket %s is already in use", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, "", fmt.Errorf("removing stale socket %s: %w", socketPath, err)
+		}
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := os.Chmod(socketPath, GetHTTPSocketMode()); err != nil {
+		listener.Close()
+		return nil, "", fmt.Errorf("setting permissions of socket %s: %w", socketPath, err)
+	}
+	return listener, "unix:" + socketPath, nil
+}
+
+// NewHTTPHandler creates the HTTP handler serving the MCP endpoint behind the security handler and the health endpoints.
+// The handler stops applying the CORS changes of the configuration reloads when ctx is done.
+func NewHTTPHandler(ctx context.Context, cfg Config, hcServer *server.MCPServer, logger *log.Logger, endpointPath string) *http.ServeMux {
+	// Ensure endpoint path starts with /
+	endpointPath = path.Join("/", endpointPath)
+	// Create StreamableHTTP server which implements the new streamable-http transport
+	// This is the modern MCP transport that supports both direct HTTP responses and SSE streams
+	opts := []server.StreamableHTTPOption{
+		server.WithEndpointPath(endpointPath), // Default MCP endpoint path
+		server.WithLogger(logger),
+	}
+
+	// Log the endpoint path being used
+	logger.Infof("Using endpoint path: %s", endpointPath)
+
+	// Check if stateless mode is enabled
+	isStateless := ShouldUseStatelessMode()
+	opts = append(opts, server.WithStateLess(isStateless))
+	logger.Infof("Running with stateless mode: %v", isStateless)
+
+	baseStreamableServer := server.NewStreamableHTTPServer(hcServer, opts...)
+
+	// Load CORS configuration
+	corsConfig := client.LoadCORSConfigFromEnv()
+
+	// Log CORS configuration
+	logger.Infof("CORS Mode: %s", corsConfig.Mode)
+	if len(corsConfig.AllowedOrigins) > 0 {
+		logger.Infof("Allowed Origins: %s", strings.Join(corsConfig.AllowedOrigins, ", "))
+	} else if corsConfig.Mode == "strict" {
+		logger.Warnf("No allowed origins configured in strict mode. All cross-origin requests will be rejected.")
+	} else if corsConfig.Mode == "development" {
+		logger.Infof("Development mode: localhost origins are automatically allowed")
+	} else if corsConfig.Mode == "disabled" {
+		logger.Warnf("CORS validation is disabled. This is not recommended for production.")
+	}
+	corsConfig.AllowedHosts = client.ResolveAllowedHosts(corsConfig.AllowedHosts, cfg.bindHost)
+	if len(corsConfig.AllowedHosts) > 0 {
+		logger.Infof("Allowed Hosts: %s", strings.Join(corsConfig.AllowedHosts, ", "))
+	}
+
+	// Limit the size of request bodies before they are parsed
+	inputLimits := client.LoadInputLimitsConfigFromEnv()
+	limitedServer := client.NewBodyLimitHandler(client.NewRateLimitHeaderHandler(baseStreamableServer), inputLimits.MaxBodyBytes, logger)
+
+	// Create a security wrapper around the streamable server
+	streamableServer := client.NewSecurityHandler(limitedServer, corsConfig.AllowedOrigins, corsConfig.Mode, corsConfig.AllowedHosts, logger)
+	securityHandlers := []http.Handler{streamableServer}
+
+	mux := http.NewServeMux()
+
+	// Apply middleware
+	if cfg.ContextMiddleware != nil {
+		streamableServer = cfg.ContextMiddleware(logger)(streamableServer)
+	}
+
+	// Correlate the tool calls of a request with the X-Request-Id header
+	streamableServer = client.NewRequestIDHandler(streamableServer)
+
+	// Handle the /mcp endpoint with the streamable server (with security wrapper). Tool calls such as
+	// retry_run with auto_retry answer after the write timeout of the HTTP server, like the SSE streams.
+	streamableServer = withoutWriteDeadline(streamableServer)
+	mux.Handle(endpointPath, streamableServer)
+	mux.Handle(endpointPath+"/", streamableServer)
+
+	// Mount the legacy HTTP+SSE endpoints for clients that do not support StreamableHTTP yet
+	if cfg.SSECompat || ShouldUseSSECompat() {
+		sseServer := server.NewSSEServer(hcServer,
+			server.WithSSEEndpoint(legacySSEEndpoint),
+			server.WithMessageEndpoint(legacyMessageEndpoint),
+			server.WithKeepAlive(true),
+		)
+		var sseHandler http.Handler = client.NewSecurityHandler(client.NewBodyLimitHandler(sseServer, inputLimits.MaxBodyBytes, logger), corsConfig.AllowedOrigins, corsConfig.Mode, corsConfig.AllowedHosts, logger)
+		securityHandlers = append(securityHandlers, sseHandler)
+		if cfg.ContextMiddleware != nil {
+			sseHandler = cfg.ContextMiddleware(logger)(sseHandler)
+		}
+		sseHandler = client.NewRequestIDHandler(sseHandler)
+		mux.Handle(legacySSEEndpoint, withoutWriteDeadline(sseHandler))
+		mux.Handle(legacyMessageEndpoint, sseHandler)
+		logger.Infof("Legacy SSE endpoints enabled at %s and %s", legacySSEEndpoint, legacyMessageEndpoint)
+	}
+
+	// Apply CORS changes from the configuration file to the security handlers
+	context.AfterFunc(ctx, onConfigReload(func() {
+		corsConfig := client.LoadCORSConfigFromEnv()
+		if err := corsConfig.Validate(); err != nil {
+			logger.Errorf("Keeping the current CORS configuration, invalid MCP_ALLOWED_ORIGINS: %v", err)
+			return
+		}
+		corsConfig.AllowedHosts = client.ResolveAllowedHosts(corsConfig.AllowedHosts, cfg.bindHost)
+		for _, handler := range securityHandlers {
+			if updater, ok := handler.(client.CORSConfigUpdater); ok {
+				updater.UpdateCORSConfig(corsConfig)
+			}
+		}
+	}))
+
+	// Receive the plan and apply events of HCP Terraform/TFE and Atlantis for the list_recent_run_events tool
+	if client.RunEventWebhooksEnabled() {
+		webhooks := client.NewRunEventWebhookHandler(client.GetRunEventStore(), strings.TrimSpace(os.Getenv(client.WebhookToken)), logger)
+		mux.Handle(client.TFEWebhookPath, webhooks)
+		mux.Handle(client.AtlantisWebhookPath, webhooks)
+		logger.Infof("Run event webhooks enabled at %s and %s", client.TFEWebhookPath, client.AtlantisWebhookPath)
+	}
+
+	// Add health check endpoints. /health is kept for existing deployments, /healthz is the
+	// liveness endpoint and /readyz the readiness endpoint that probes the dependencies
+	mux.HandleFunc("/healthz", livenessHandler(cfg))
+	mux.HandleFunc("/readyz", readinessHandler(cfg, newReadinessChecker(readinessProbes(cfg, logger), readinessCacheTTL(logger)), logger))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		response := fmt.Sprintf(`{"status":"ok","service":"%s","transport":"streamable-http","endpoint":"%s"}`, cfg.Name, endpointPath)
+		w.Write([]byte(response))
+	})
+
+	return mux
+}
+
+const (
+	legacySSEEndpoint     = "/sse"
+	legacyMessageEndpoint = "/message"
+)
+
+// withoutWriteDeadline lifts the write timeout of the HTTP server for long-lived SSE streams and tool calls
+func withoutWriteDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,312 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// lockFileHeader is the comment Terraform writes at the top of the dependency lock file
+	lockFileHeader = "# This file is maintained automatically by \"terraform init\".\n# Manual edits may be lost in future updates.\n"
+	// lockLookupConcurrency bounds the providers whose releases are read from the registry at the same time
+	lockLookupConcurrency = 4
+)
+
+// LockFileReport is the result of the analyze_lock_file tool
+type LockFileReport struct {
+	Platforms       []string             `json:"platforms"`
+	Providers       []LockedProviderInfo `json:"providers"`
+	UpgradeCount    int                  `json:"upgrade_count"`
+	MissingHashes   int                  `json:"missing_hashes"`
+	UpdatedLockFile string               `json:"updated_lock_file"`
+}
+
+// LockedProviderInfo is a provider of the lock file with its upgrades and the platforms its hashes do not cover
+type LockedProviderInfo struct {
+	Address              string   `json:"address"`
+	Version              string   `json:"version"`
+	Constraints          string   `json:"constraints,omitempty"`
+	LatestMatching       string   `json:"latest_matching,omitempty"`
+	Latest               string   `json:"latest,omitempty"`
+	UpgradeAvailable     bool     `json:"upgrade_available"`
+	UpdatedVersion       string   `json:"updated_version,omitempty"`
+	MissingPlatforms     []string `json:"missing_platforms,omitempty"`
+	UnavailablePlatforms []string `json:"unavailable_platforms,omitempty"`
+	Error                string   `json:"error,omitempty"`
+}
+
+// lockedProvider is a provider block of a dependency lock file
+type lockedProvider struct {
+	Address     string
+	Version     string
+	Constraints string
+	Hashes      []string
+}
+
+// AnalyzeLockFile creates a tool that reports the upgrades and missing platform hashes of a dependency lock file.
+func AnalyzeLockFile(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("analyze_lock_file",
+			mcp.WithDescription(`Analyzes a '.terraform.lock.hcl' dependency lock file against the public Terraform registry. For each provider it reports the newest version allowed by its version constraints and the newest version overall, and the platforms whose package checksum ('zh:' hash) is missing, which makes 'terraform init' fail on those platforms.
+Returns the updated lock file content with the missing hashes added and, when 'upgrade' is true, the providers upgraded to the newest version allowed by their constraints. Lock files recording only 'h1:' hashes, e.g. of providers installed from a mirror, have all their platforms reported as missing: their 'zh:' hashes are added. The 'h1:' hashes of other platforms can only be computed by downloading the packages with 'terraform providers lock'.`),
+			mcp.WithTitleAnnotation("Analyze and update a dependency lock file"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("lock_file",
+				mcp.Required(),
+				mcp.Description("The content of the .terraform.lock.hcl file"),
+			),
+			mcp.WithString("configuration",
+				mcp.Description("The Terraform configuration of the lock file, whose required_providers constraints take precedence over the ones recorded in the lock file"),
+			),
+			mcp.WithString("platforms",
+				mcp.Description("Comma-separated list of the platforms the configuration runs on, e.g. 'linux_amd64, darwin_arm64'"),
+				mcp.DefaultString(defaultMirrorPlatforms),
+			),
+			mcp.WithBoolean("upgrade",
+				mcp.Description("If true, the updated lock file upgrades each provider to the newest version allowed by its constraints"),
+				mcp.DefaultBool(false),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return analyzeLockFileHandler(ctx, request, logger)
+		},
+	}
+}
+
+func analyzeLockFileHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	lockFile, err := request.RequireString("lock_file")
+	if err != nil || strings.TrimSpace(lockFile) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: lock_file is required", err)
+	}
+	locked, err := parseLockFile(lockFile)
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing lock_file", err)
+	}
+	if configuration := request.GetString("configuration", ""); configuration != "" {
+		required, _, err := parseConfigurationDependencies(configuration, "")
+		if err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing configuration", err)
+		}
+		for i := range locked {
+			for _, provider := range required {
+				if provider.Address == locked[i].Address && provider.Constraint != "" {
+					locked[i].Constraints = provider.Constraint
+				}
+			}
+		}
+	}
+
+	var platforms []string
+	for _, platform := range splitNames(request.GetString("platforms", defaultMirrorPlatforms)) {
+		platform = strings.ToLower(platform)
+		if !mirrorPlatform.MatchString(platform) {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "reading platforms", fmt.Errorf("invalid platform %q, expected e.g. 'linux_amd64'", platform))
+		}
+		if !slices.Contains(platforms, platform) {
+			platforms = append(platforms, platform)
+		}
+	}
+	if len(platforms) == 0 {
+		platforms = []string{defaultMirrorPlatforms}
+	}
+	upgrade := request.GetBool("upgrade", false)
+
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting http client for the Terraform registry", err)
+	}
+
+	report := LockFileReport{Platforms: platforms, Providers: make([]LockedProviderInfo, len(locked))}
+	semaphore := make(chan struct{}, lockLookupConcurrency)
+	var wg sync.WaitGroup
+	for i := range locked {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			report.Providers[i] = analyzeLockedProvider(ctx, httpClient, &locked[i], platforms, upgrade, logger)
+		}()
+	}
+	wg.Wait()
+
+	for _, provider := range report.Providers {
+		if provider.UpgradeAvailable {
+			report.UpgradeCount++
+		}
+		report.MissingHashes += len(provider.MissingPlatforms)
+	}
+	report.UpdatedLockFile = renderLockFile(locked)
+
+	resultJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling lock file report", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// parseLockFile reads the provider blocks of a dependency lock file, sorted by address
+func parseLockFile(lockFile string) ([]lockedProvider, error) {
+	body, err := parseHCLBody(lockFile, ".terraform.lock.hcl")
+	if err != nil {
+		return nil, err
+	}
+	var providers []lockedProvider
+	for _, block := range body.Blocks {
+		if block.Type != "provider" || len(block.Labels) != 1 {
+			continue
+		}
+		provider := lockedProvider{Address: normalizeProviderAddress(block.Labels[0])}
+		if attribute, ok := block.Body.Attributes["version"]; ok {
+			provider.Version = literalString(attribute.Expr)
+		}
+		if attribute, ok := block.Body.Attributes["constraints"]; ok {
+			provider.Constraints = literalString(attribute.Expr)
+		}
+		if attribute, ok := block.Body.Attributes["hashes"]; ok {
+			value, diags := attribute.Expr.Value(nil)
+			if diags.HasErrors() || !value.CanIterateElements() {
+				return nil, fmt.Errorf("the hashes of provider %q must be a list of strings", provider.Address)
+			}
+			for it := value.ElementIterator(); it.Next(); {
+				_, hash := it.Element()
+				if hash.IsNull() || hash.Type() != cty.String {
+					return nil, fmt.Errorf("the hashes of provider %q must be a list of strings", provider.Address)
+				}
+				provider.Hashes = append(provider.Hashes, hash.AsString())
+			}
+		}
+		if provider.Version == "" {
+			return nil, fmt.Errorf("provider %q has no version", provider.Address)
+		}
+		providers = append(providers, provider)
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no provider block found in the lock file")
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Address < providers[j].Address })
+	return providers, nil
+}
+
+// analyzeLockedProvider reads the releases of a provider from the registry, records its upgrades and adds the
+// missing hashes of the platforms to the locked provider, upgrading it when upgrade is set
+func analyzeLockedProvider(ctx context.Context, httpClient *http.Client, provider *lockedProvider, platforms []string, upgrade bool, logger *log.Logger) LockedProviderInfo {
+	info := LockedProviderInfo{Address: provider.Address, Version: provider.Version, Constraints: provider.Constraints}
+	parts := strings.Split(provider.Address, "/")
+	if len(parts) != 3 || parts[0] != defaultProviderHost {
+		info.Error = "only the providers of the public registry can be analyzed"
+		return info
+	}
+
+	versions, err := client.GetProviderRegistryVersions(ctx, httpClient, parts[1], parts[2], logger)
+	if err != nil {
+		info.Error = fmt.Sprintf("reading the versions of the provider: %v", err)
+		return info
+	}
+	latest, latestMatching := latestProviderVersions(versions, provider.Constraints)
+	info.Latest = latest.Version
+	info.LatestMatching = latestMatching.Version
+	info.UpgradeAvailable = latestMatching.Version != "" && compareModuleVersions(latestMatching.Version, provider.Version) > 0
+
+	target := provider.Version
+	if upgrade && info.UpgradeAvailable {
+		target = latestMatching.Version
+		info.UpdatedVersion = target
+		// The hashes of the locked version do not apply to the new one
+		provider.Version, provider.Hashes = target, nil
+	}
+	var published []client.ProviderPlatform
+	for _, release := range versions {
+		if release.Version == target {
+			published = release.Platforms
+		}
+	}
+
+	for _, platform := range platforms {
+		platformOS, arch, _ := strings.Cut(platform, "_")
+		if !slices.Contains(published, client.ProviderPlatform{OS: platformOS, Arch: arch}) {
+			info.UnavailablePlatforms = append(info.UnavailablePlatforms, platform)
+			continue
+		}
+		providerPackage, err := client.GetProviderPackage(ctx, httpClient, parts[1], parts[2], target, platformOS, arch, logger)
+		if err != nil {
+			info.Error = fmt.Sprintf("reading the %s package of version %s: %v", platform, target, err)
+			continue
+		}
+		hash := "zh:" + providerPackage.Shasum
+		if !slices.Contains(provider.Hashes, hash) {
+			provider.Hashes = append(provider.Hashes, hash)
+			if target == info.Version {
+				info.MissingPlatforms = append(info.MissingPlatforms, platform)
+			}
+		}
+	}
+	return info
+}
+
+// latestProviderVersions returns the newest release and the newest release matching the constraints, without
+// pre-releases unless the constraints name them
+func latestProviderVersions(versions []client.ProviderRegistryVersion, constraints string) (client.ProviderRegistryVersion, client.ProviderRegistryVersion) {
+	constraint, err := goversion.NewConstraint(constraints)
+	if err != nil {
+		constraint = nil
+	}
+	var latest, latestMatching client.ProviderRegistryVersion
+	for _, release := range versions {
+		parsed, err := goversion.NewVersion(release.Version)
+		if err != nil {
+			continue
+		}
+		if parsed.Prerelease() == "" && compareModuleVersions(release.Version, latest.Version) > 0 {
+			latest = release
+		}
+		if constraint != nil && constraint.Check(parsed) && compareModuleVersions(release.Version, latestMatching.Version) > 0 {
+			latestMatching = release
+		}
+	}
+	if constraint == nil {
+		latestMatching = latest
+	}
+	return latest, latestMatching
+}
+
+// renderLockFile writes the providers in the format of terraform init, with sorted hashes
+func renderLockFile(providers []lockedProvider) string {
+	var builder strings.Builder
+	builder.WriteString(lockFileHeader)
+	for _, provider := range providers {
+		fmt.Fprintf(&builder, "\nprovider %q {\n", provider.Address)
+		if provider.Constraints != "" {
+			fmt.Fprintf(&builder, "  version     = %q\n  constraints = %q\n", provider.Version, provider.Constraints)
+		} else {
+			fmt.Fprintf(&builder, "  version = %q\n", provider.Version)
+		}
+		hashes := slices.Clone(provider.Hashes)
+		sort.Strings(hashes)
+		builder.WriteString("  hashes = [\n")
+		for _, hash := range slices.Compact(hashes) {
+			fmt.Fprintf(&builder, "    %q,\n", hash)
+		}
+		builder.WriteString("  ]\n}\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockFile = `
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "h1:local=",
+    "zh:aws-5.31.0-linux_amd64",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.6.0"
+  hashes = [
+    "zh:random-3.6.0-linux_amd64",
+  ]
+}
+`
+
+// newTestRegistry serves the versions and packages of the aws and random providers, whose checksums are named
+// after the provider, version and platform
+func newTestRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+	releases := map[string][]string{
+		"aws":    {"4.67.0", "5.31.0", "5.40.0", "6.0.0", "6.1.0-beta1"},
+		"random": {"3.6.0", "3.6.2"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/providers/hashicorp/"), "/")
+		switch {
+		case len(parts) == 2 && parts[1] == "versions":
+			var versions []string
+			for _, version := range releases[parts[0]] {
+				versions = append(versions, fmt.Sprintf(`{"version": %q, "protocols": ["5.0"], "platforms": [{"os": "linux", "arch": "amd64"}, {"os": "darwin", "arch": "arm64"}]}`, version))
+			}
+			fmt.Fprintf(w, `{"versions": [%s]}`, strings.Join(versions, ","))
+		case len(parts) == 5 && parts[2] == "download":
+			fmt.Fprintf(w, `{"os": %q, "arch": %q, "shasum": "%s-%s-%s_%s"}`, parts[3], parts[4], parts[0], parts[1], parts[3], parts[4])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(client.TerraformRegistryAddress, server.URL)
+	return server
+}
+
+func TestParseLockFile(t *testing.T) {
+	providers, err := parseLockFile(testLockFile)
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	assert.Equal(t, lockedProvider{
+		Address:     "registry.terraform.io/hashicorp/aws",
+		Version:     "5.31.0",
+		Constraints: "~> 5.0",
+		Hashes:      []string{"h1:local=", "zh:aws-5.31.0-linux_amd64"},
+	}, providers[0])
+
+	// The updated lock file keeps the format of terraform init
+	assert.Equal(t, lockFileHeader+`
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "h1:local=",
+    "zh:aws-5.31.0-linux_amd64",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.6.0"
+  hashes = [
+    "zh:random-3.6.0-linux_amd64",
+  ]
+}
+`, renderLockFile(providers))
+
+	_, err = parseLockFile(`provider "hashicorp/aws" {}`)
+	assert.Error(t, err)
+	_, err = parseLockFile(`terraform {}`)
+	assert.Error(t, err)
+}
+
+func TestLatestProviderVersions(t *testing.T) {
+	versions := []client.ProviderRegistryVersion{{Version: "5.40.0"}, {Version: "6.1.0-beta1"}, {Version: "6.0.0"}, {Version: "5.31.0"}}
+	latest, latestMatching := latestProviderVersions(versions, "~> 5.0")
+	assert.Equal(t, "6.0.0", latest.Version)
+	assert.Equal(t, "5.40.0", latestMatching.Version)
+
+	_, latestMatching = latestProviderVersions(versions, "")
+	assert.Equal(t, "6.0.0", latestMatching.Version)
+}
+
+func TestAnalyzeLockFile(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := AnalyzeLockFile(logger)
+	assert.Equal(t, "analyze_lock_file", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "analyze_lock_file", Arguments: arguments}}
+	}
+	analyze := func(t *testing.T, arguments map[string]any) LockFileReport {
+		registry := newTestRegistry(t)
+		ctx := client.ContextWithRegistryClientProvider(t.Context(), testutil.StaticClients{Registry: registry.Client()})
+		result, err := analyzeLockFileHandler(ctx, request(arguments), logger)
+		require.NoError(t, err)
+
+		var report LockFileReport
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &report))
+		return report
+	}
+
+	t.Run("reports upgrades and adds the missing hashes", func(t *testing.T) {
+		report := analyze(t, map[string]any{"lock_file": testLockFile, "platforms": "linux_amd64, darwin_arm64"})
+		require.Len(t, report.Providers, 2)
+		aws := report.Providers[0]
+		assert.Equal(t, "5.40.0", aws.LatestMatching)
+		assert.Equal(t, "6.0.0", aws.Latest)
+		assert.True(t, aws.UpgradeAvailable)
+		assert.Equal(t, []string{"darwin_arm64"}, aws.MissingPlatforms)
+		assert.Equal(t, 2, report.UpgradeCount)
+		assert.Equal(t, 2, report.MissingHashes)
+		assert.Contains(t, report.UpdatedLockFile, "  version     = \"5.31.0\"\n")
+		assert.Contains(t, report.UpdatedLockFile, `"zh:aws-5.31.0-darwin_arm64",`)
+		assert.Contains(t, report.UpdatedLockFile, `"zh:random-3.6.0-darwin_arm64",`)
+	})
+
+	t.Run("upgrades within the constraints of the configuration", func(t *testing.T) {
+		report := analyze(t, map[string]any{
+			"lock_file": testLockFile,
+			"configuration": `
+terraform {
+  required_providers {
+    aws = { source = "hashicorp/aws", version = ">= 5.0, < 5.35" }
+  }
+}`,
+			"upgrade": true,
+		})
+		aws := report.Providers[0]
+		assert.Equal(t, ">= 5.0, < 5.35", aws.Constraints)
+		assert.Equal(t, "5.31.0", aws.LatestMatching)
+		assert.False(t, aws.UpgradeAvailable)
+		assert.Equal(t, "3.6.2", report.Providers[1].UpdatedVersion)
+		assert.Contains(t, report.UpdatedLockFile, "provider \"registry.terraform.io/hashicorp/random\" {\n  version = \"3.6.2\"\n  hashes = [\n    \"zh:random-3.6.2-linux_amd64\",\n  ]\n}\n")
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		for name, arguments := range map[string]map[string]any{
+			"missing lock file": {},
+			"invalid lock file": {"lock_file": `provider "hashicorp/aws" {`},
+			"invalid platform":  {"lock_file": testLockFile, "platforms": "linux"},
+		} {
+			_, err := analyzeLockFileHandler(t.Context(), request(arguments), logger)
+			assert.Equal(t, utils.ErrorCodeInvalidInput, utils.ErrorCodeOf(err), name)
+		}
+	})
+}
@@ -0,0 +1,309 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	tfeTools "github.com/hashicorp/terraform-mcp-server/pkg/tools/tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultLargeResourceBytes is the serialized attribute size above which a resource instance is reported as large.
+const defaultLargeResourceBytes = 32 * 1024
+
+// terraformState represents the subset of the Terraform state (format version 4) used for analysis.
+type terraformState struct {
+	Version          int             `json:"version"`
+	TerraformVersion string          `json:"terraform_version"`
+	Serial           int64           `json:"serial"`
+	Lineage          string          `json:"lineage"`
+	Resources        []stateResource `json:"resources"`
+}
+
+type stateResource struct {
+	Module    string          `json:"module"`
+	Mode      string          `json:"mode"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Provider  string          `json:"provider"`
+	Instances []stateInstance `json:"instances"`
+}
+
+type stateInstance struct {
+	IndexKey      any             `json:"index_key"`
+	SchemaVersion int             `json:"schema_version"`
+	Attributes    json.RawMessage `json:"attributes"`
+	Dependencies  []string        `json:"dependencies"`
+}
+
+// StateAnalysis is the report returned by the analyze_state tool.
+type StateAnalysis struct {
+	TerraformVersion    string               `json:"terraform_version"`
+	Serial              int64                `json:"serial"`
+	TotalResources      int                  `json:"total_resources"`
+	TotalInstances      int                  `json:"total_instances"`
+	ManagedResources    int                  `json:"managed_resources"`
+	DataSources         int                  `json:"data_sources"`
+	ResourcesByType     map[string]int       `json:"resources_by_type"`
+	ResourcesByProvider map[string]int       `json:"resources_by_provider"`
+	ResourcesByModule   map[string]int       `json:"resources_by_module"`
+	OrphanedDataSources []string             `json:"orphaned_data_sources"`
+	Providers           []ProviderUsage      `json:"providers"`
+	LargeResources      []LargeStateResource `json:"large_resources"`
+}
+
+// ProviderUsage summarizes a provider referenced by the state: its address, the required_providers source
+// it maps to, the resources using it and the highest resource schema version recorded for it. The state
+// does not record provider versions, the version and constraints pinned by a dependency lock file are
+// reported when one is given.
+type ProviderUsage struct {
+	Address          string `json:"address"`
+	Source           string `json:"source"`
+	Resources        int    `json:"resources"`
+	MaxSchemaVersion int    `json:"max_schema_version"`
+	Version          string `json:"version,omitempty"`
+	Constraints      string `json:"constraints,omitempty"`
+}
+
+// LargeStateResource describes a resource instance whose attributes exceed the size threshold.
+type LargeStateResource struct {
+	Address        string `json:"address"`
+	AttributeBytes int    `json:"attribute_bytes"`
+}
+
+// AnalyzeState creates a tool that summarizes a Terraform state file for migration and refactoring planning.
+func AnalyzeState(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("analyze_state",
+			mcp.WithDescription(`Analyzes a Terraform state file and reports resource counts by type, provider and module, data sources that no managed resource depends on, the providers referenced by the state with the versions pinned by the 'lock_file' when given, and unusually large resource instances.
+Provide either the raw state JSON in 'state_json', or 'workspace_id' or 'terraform_org_name' and 'workspace_name' to pull the current state version from HCP Terraform/Terraform Enterprise (requires a valid TFE_TOKEN).
+Use this report to plan migrations, module refactors or workspace splits.`),
+			mcp.WithTitleAnnotation("Analyze a Terraform state file"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("state_json",
+				mcp.Description("The raw Terraform state file content (format version 4)"),
+			),
+			tfeTools.WithWorkspace("The name of the workspace to pull the current state from, when 'state_json' is not set"),
+			mcp.WithString("lock_file",
+				mcp.Description("Optional content of the .terraform.lock.hcl file of the configuration, to report the version and constraints each provider is pinned to"),
+			),
+			mcp.WithNumber("large_resource_bytes",
+				mcp.Description("Attribute size in bytes above which a resource instance is reported as large (default: 32768)"),
+				mcp.Min(1),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return analyzeStateHandler(ctx, request, logger)
+		},
+	}
+}
+
+func analyzeStateHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	stateJSON := request.GetString("state_json", "")
+	workspaceID := strings.TrimSpace(request.GetString("workspace_id", ""))
+	terraformOrgName := strings.TrimSpace(request.GetString("terraform_org_name", ""))
+	workspaceName := strings.TrimSpace(request.GetString("workspace_name", ""))
+	threshold := request.GetInt("large_resource_bytes", defaultLargeResourceBytes)
+
+	var locked []lockedProvider
+	if lockFile := request.GetString("lock_file", ""); strings.TrimSpace(lockFile) != "" {
+		var err error
+		if locked, err = parseLockFile(lockFile); err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing the lock file", err)
+		}
+	}
+
+	if stateJSON == "" {
+		if workspaceID == "" && (terraformOrgName == "" || workspaceName == "") {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: either 'state_json', 'workspace_id', or both 'terraform_org_name' and 'workspace_name' must be provided", nil)
+		}
+
+		tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+		}
+
+		workspace, err := tfeTools.ResolveWorkspace(ctx, tfeClient, request, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		stateVersion, err := tfeClient.StateVersions.ReadCurrent(ctx, workspace.ID)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading current state version", err)
+		}
+
+		raw, err := tfeClient.StateVersions.Download(ctx, stateVersion.DownloadURL)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "downloading current state version", err)
+		}
+		stateJSON = string(raw)
+	}
+
+	analysis, err := analyzeState([]byte(stateJSON), threshold, locked)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "analyzing state", err)
+	}
+
+	resultJSON, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling state analysis", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// analyzeState parses the raw state and builds the analysis report, with the provider versions of the locked providers.
+func analyzeState(raw []byte, largeResourceBytes int, locked []lockedProvider) (*StateAnalysis, error) {
+	var state terraformState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("state is not valid JSON: %w", err)
+	}
+	if state.Version != 0 && state.Version < 4 {
+		return nil, fmt.Errorf("unsupported state format version %d, only version 4 and above are supported", state.Version)
+	}
+	if largeResourceBytes <= 0 {
+		largeResourceBytes = defaultLargeResourceBytes
+	}
+
+	analysis := &StateAnalysis{
+		TerraformVersion:    state.TerraformVersion,
+		Serial:              state.Serial,
+		ResourcesByType:     make(map[string]int),
+		ResourcesByProvider: make(map[string]int),
+		ResourcesByModule:   make(map[string]int),
+		OrphanedDataSources: []string{},
+		Providers:           []ProviderUsage{},
+		LargeResources:      []LargeStateResource{},
+	}
+
+	dependedOn := make(map[string]bool)
+	providers := make(map[string]*ProviderUsage)
+	var dataSources []string
+
+	for _, resource := range state.Resources {
+		address := resourceAddress(resource)
+		module := resource.Module
+		if module == "" {
+			module = "root"
+		}
+
+		analysis.TotalResources++
+		analysis.TotalInstances += len(resource.Instances)
+		analysis.ResourcesByType[resource.Type]++
+		analysis.ResourcesByModule[module]++
+
+		providerSource := providerSourceFromAddress(resource.Provider)
+		analysis.ResourcesByProvider[providerSource]++
+
+		provider, ok := providers[resource.Provider]
+		if !ok {
+			provider = &ProviderUsage{Address: resource.Provider, Source: providerSource}
+			providers[resource.Provider] = provider
+		}
+		provider.Resources++
+
+		if resource.Mode == "data" {
+			analysis.DataSources++
+			dataSources = append(dataSources, address)
+		} else {
+			analysis.ManagedResources++
+		}
+
+		for _, instance := range resource.Instances {
+			if instance.SchemaVersion > provider.MaxSchemaVersion {
+				provider.MaxSchemaVersion = instance.SchemaVersion
+			}
+			for _, dep := range instance.Dependencies {
+				dependedOn[dep] = true
+			}
+			if size := len(instance.Attributes); size > largeResourceBytes {
+				analysis.LargeResources = append(analysis.LargeResources, LargeStateResource{
+					Address:        instanceAddress(address, instance.IndexKey),
+					AttributeBytes: size,
+				})
+			}
+		}
+	}
+
+	for _, address := range dataSources {
+		if !dependedOn[address] {
+			analysis.OrphanedDataSources = append(analysis.OrphanedDataSources, address)
+		}
+	}
+
+	lockedByAddress := make(map[string]lockedProvider, len(locked))
+	for _, provider := range locked {
+		lockedByAddress[provider.Address] = provider
+	}
+	for _, provider := range providers {
+		if lock, ok := lockedByAddress[normalizeProviderAddress(provider.Source)]; ok {
+			provider.Version, provider.Constraints = lock.Version, lock.Constraints
+		}
+		analysis.Providers = append(analysis.Providers, *provider)
+	}
+	// The configurations of a provider share its source and are ordered by their alias
+	sort.Slice(analysis.Providers, func(i, j int) bool {
+		if analysis.Providers[i].Source != analysis.Providers[j].Source {
+			return analysis.Providers[i].Source < analysis.Providers[j].Source
+		}
+		return analysis.Providers[i].Address < analysis.Providers[j].Address
+	})
+	sort.Slice(analysis.LargeResources, func(i, j int) bool {
+		return analysis.LargeResources[i].AttributeBytes > analysis.LargeResources[j].AttributeBytes
+	})
+	sort.Strings(analysis.OrphanedDataSources)
+
+	return analysis, nil
+}
+
+// resourceAddress builds the Terraform address of a state resource, e.g. module.vpc.data.aws_ami.ubuntu
+func resourceAddress(resource stateResource) string {
+	parts := []string{}
+	if resource.Module != "" {
+		parts = append(parts, resource.Module)
+	}
+	if resource.Mode == "data" {
+		parts = append(parts, "data")
+	}
+	parts = append(parts, resource.Type, resource.Name)
+	return strings.Join(parts, ".")
+}
+
+// instanceAddress appends the count or for_each key of an instance to the resource address
+func instanceAddress(address string, indexKey any) string {
+	switch key := indexKey.(type) {
+	case nil:
+		return address
+	case string:
+		return fmt.Sprintf("%s[%q]", address, key)
+	case float64:
+		return fmt.Sprintf("%s[%d]", address, int(key))
+	default:
+		return fmt.Sprintf("%s[%v]", address, key)
+	}
+}
+
+// providerSourceFromAddress converts a state provider address such as
+// provider["registry.terraform.io/hashicorp/aws"].east into the source "hashicorp/aws"
+func providerSourceFromAddress(address string) string {
+	start := strings.Index(address, `["`)
+	end := strings.Index(address, `"]`)
+	if start == -1 || end == -1 || end < start {
+		return address
+	}
+	source := address[start+2 : end]
+	return strings.TrimPrefix(source, "registry.terraform.io/")
+}
@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testState = `{
+  "version": 4,
+  "terraform_version": "1.9.5",
+  "serial": 12,
+  "lineage": "3f1c",
+  "resources": [
+    {
+      "mode": "data",
+      "type": "aws_ami",
+      "name": "ubuntu",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [{"schema_version": 0, "attributes": {"id": "ami-1"}}]
+    },
+    {
+      "mode": "data",
+      "type": "aws_caller_identity",
+      "name": "current",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [{"schema_version": 0, "attributes": {"id": "123"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "web",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {"index_key": 0, "schema_version": 1, "attributes": {"id": "i-1", "user_data": "0123456789012345678901234567890123456789"}, "dependencies": ["data.aws_ami.ubuntu"]},
+        {"index_key": 1, "schema_version": 1, "attributes": {"id": "i-2"}, "dependencies": ["data.aws_ami.ubuntu"]}
+      ]
+    },
+    {
+      "module": "module.network",
+      "mode": "managed",
+      "type": "aws_vpc",
+      "name": "this",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"].east",
+      "instances": [{"schema_version": 1, "attributes": {"id": "vpc-1"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "random_id",
+      "name": "suffix",
+      "provider": "provider[\"registry.terraform.io/hashicorp/random\"]",
+      "instances": [{"schema_version": 0, "attributes": {"id": "abc"}}]
+    }
+  ]
+}`
+
+func TestAnalyzeState(t *testing.T) {
+	locked, err := parseLockFile(`provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.62.0"
+  constraints = "~> 5.0"
+}`)
+	require.NoError(t, err)
+	analysis, err := analyzeState([]byte(testState), 50, locked)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.9.5", analysis.TerraformVersion)
+	assert.Equal(t, int64(12), analysis.Serial)
+	assert.Equal(t, 5, analysis.TotalResources)
+	assert.Equal(t, 6, analysis.TotalInstances)
+	assert.Equal(t, 3, analysis.ManagedResources)
+	assert.Equal(t, 2, analysis.DataSources)
+
+	assert.Equal(t, 1, analysis.ResourcesByType["aws_instance"])
+	assert.Equal(t, 4, analysis.ResourcesByProvider["hashicorp/aws"])
+	assert.Equal(t, 1, analysis.ResourcesByProvider["hashicorp/random"])
+	assert.Equal(t, 4, analysis.ResourcesByModule["root"])
+	assert.Equal(t, 1, analysis.ResourcesByModule["module.network"])
+
+	assert.Equal(t, []string{"data.aws_caller_identity.current"}, analysis.OrphanedDataSources)
+
+	require.Len(t, analysis.Providers, 3)
+	assert.Equal(t, ProviderUsage{Address: `provider["registry.terraform.io/hashicorp/aws"]`, Source: "hashicorp/aws", Resources: 3, MaxSchemaVersion: 1, Version: "5.62.0", Constraints: "~> 5.0"}, analysis.Providers[0])
+	assert.Equal(t, `provider["registry.terraform.io/hashicorp/aws"].east`, analysis.Providers[1].Address)
+	assert.Equal(t, "5.62.0", analysis.Providers[1].Version)
+	assert.Equal(t, "hashicorp/random", analysis.Providers[2].Source)
+	assert.Empty(t, analysis.Providers[2].Version)
+
+	require.Len(t, analysis.LargeResources, 1)
+	assert.Equal(t, "aws_instance.web[0]", analysis.LargeResources[0].Address)
+}
+
+func TestAnalyzeStateErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		state string
+	}{
+		{name: "invalid json", state: "not json"},
+		{name: "legacy format", state: `{"version": 3, "modules": []}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := analyzeState([]byte(tc.state), 0, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProviderSourceFromAddress(t *testing.T) {
+	tests := map[string]string{
+		`provider["registry.terraform.io/hashicorp/aws"]`:      "hashicorp/aws",
+		`provider["registry.terraform.io/hashicorp/aws"].east`: "hashicorp/aws",
+		`provider["example.com/acme/widget"]`:                  "example.com/acme/widget",
+		"unexpected":                                           "unexpected",
+	}
+	for address, expected := range tests {
+		assert.Equal(t, expected, providerSourceFromAddress(address), address)
+	}
+}
+
+func TestAnalyzeStateTool(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := AnalyzeState(logger)
+	assert.Equal(t, "analyze_state", tool.Tool.Name)
+	assert.NotNil(t, tool.Handler)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+}
+
+func TestAnalyzeStateWorkspace(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	fake := testutil.NewFakeTFE(t)
+	fake.Respond("GET", "/workspaces/ws-123", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Organization: &tfe.Organization{Name: "acme"}})
+	fake.Respond("GET", "/workspaces/ws-123/current-state-version", http.StatusOK, &tfe.StateVersion{ID: "sv-1", DownloadURL: fake.Server.URL + "/api/v2/state-versions/sv-1/download"})
+	fake.Handle("GET", "/state-versions/sv-1/download", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(testState))
+	})
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "analyze_state", Arguments: arguments}}
+	}
+	result, err := analyzeStateHandler(fake.Context(t), request(map[string]any{"workspace_id": "ws-123"}), logger)
+	require.NoError(t, err)
+	var analysis StateAnalysis
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &analysis))
+	assert.Equal(t, int64(12), analysis.Serial)
+
+	// The organization given with a workspace_id must be the one of the workspace
+	_, err = analyzeStateHandler(fake.Context(t), request(map[string]any{"workspace_id": "ws-123", "terraform_org_name": "other"}), logger)
+	assert.ErrorContains(t, err, "not other")
+
+	_, err = analyzeStateHandler(fake.Context(t), request(map[string]any{"workspace_name": "staging"}), logger)
+	assert.ErrorContains(t, err, "required input")
+}
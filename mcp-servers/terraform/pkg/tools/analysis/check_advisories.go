@@ -0,0 +1,402 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	tfeTools "github.com/hashicorp/terraform-mcp-server/pkg/tools/tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxAdvisoryDependencies bounds the dependencies checked by a call, each costing a registry and a GitHub call
+	maxAdvisoryDependencies = 50
+	// advisoryLookupConcurrency is the number of dependencies checked at once
+	advisoryLookupConcurrency = 4
+)
+
+// Statuses of an advisory finding
+const (
+	advisoryAffected       = "affected"
+	advisoryVersionUnknown = "version_unknown"
+)
+
+// advisorySeverityRank orders findings from the most to the least severe
+var advisorySeverityRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// AdvisoryReport is the result of the check_advisories tool
+type AdvisoryReport struct {
+	Subject      string               `json:"subject"`
+	Affected     int                  `json:"affected"`
+	Findings     []AdvisoryFinding    `json:"findings"`
+	Dependencies []AdvisoryDependency `json:"dependencies"`
+	Truncated    bool                 `json:"truncated,omitempty"`
+}
+
+// AdvisoryDependency is a provider or module checked for advisories, with the GitHub repository they were
+// read from, or why it was skipped
+type AdvisoryDependency struct {
+	Kind       string `json:"kind"`
+	Source     string `json:"source"`
+	Version    string `json:"version,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Advisories int    `json:"advisories"`
+	Skipped    string `json:"skipped,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// AdvisoryFinding is an advisory of the repository of a dependency. Its status is affected when the version
+// in use is in the vulnerable range, and version_unknown when only a version constraint is known.
+type AdvisoryFinding struct {
+	Kind               string  `json:"kind"`
+	Source             string  `json:"source"`
+	Version            string  `json:"version,omitempty"`
+	Status             string  `json:"status"`
+	GHSAID             string  `json:"ghsa_id"`
+	CVEID              string  `json:"cve_id,omitempty"`
+	Severity           string  `json:"severity"`
+	CVSSScore          float64 `json:"cvss_score,omitempty"`
+	Summary            string  `json:"summary"`
+	URL                string  `json:"url"`
+	VulnerableVersions string  `json:"vulnerable_versions,omitempty"`
+	PatchedVersions    string  `json:"patched_versions,omitempty"`
+}
+
+// CheckAdvisories creates a tool that reports the security advisories affecting the providers and modules of a configuration or workspace.
+func CheckAdvisories(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("check_advisories",
+			mcp.WithDescription(fmt.Sprintf(`Reports the known vulnerabilities of the providers and modules used by a Terraform configuration or an HCP Terraform workspace, from the GitHub Security Advisories published on their source repositories.
+Provide either the 'configuration' (the content of its .tf files) with its '.terraform.lock.hcl' in 'lock_file' for the exact provider versions, or 'workspace_id' or 'terraform_org_name' and 'workspace_name' to read the versions in use from the Explorer of the organization.
+Findings are 'affected' when the version in use is in the vulnerable range, and 'version_unknown' when only a version constraint is known. Dependencies not hosted on GitHub, e.g. local modules or private registry ones, are skipped. At most %d dependencies are checked.`, maxAdvisoryDependencies)),
+			mcp.WithTitleAnnotation("Check providers and modules for security advisories"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("configuration",
+				mcp.Description("The Terraform configuration to check, i.e. the content of its .tf files, which may be concatenated"),
+			),
+			mcp.WithString("lock_file",
+				mcp.Description("The content of the .terraform.lock.hcl file of the configuration"),
+			),
+			tfeTools.WithWorkspace("The name of the workspace to check, when no configuration is set"),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return checkAdvisoriesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func checkAdvisoriesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	configuration := request.GetString("configuration", "")
+	lockFile := request.GetString("lock_file", "")
+	workspaceID := strings.TrimSpace(request.GetString("workspace_id", ""))
+	terraformOrgName := strings.TrimSpace(request.GetString("terraform_org_name", ""))
+	workspaceName := strings.TrimSpace(request.GetString("workspace_name", ""))
+
+	var subject string
+	var dependencies []AdvisoryDependency
+	switch {
+	case configuration != "" || lockFile != "":
+		providers, modules, err := parseConfigurationDependencies(configuration, lockFile)
+		if err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing configuration", err)
+		}
+		subject = "configuration"
+		dependencies = configurationAdvisoryDependencies(providers, modules)
+
+	case workspaceID != "" || (terraformOrgName != "" && workspaceName != ""):
+		tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+		}
+		workspace, err := tfeTools.ResolveWorkspace(ctx, tfeClient, request, logger)
+		if err != nil {
+			return nil, err
+		}
+		terraformOrgName, workspaceName = workspace.Organization.Name, workspace.Name
+		providers, _, err := readExplorerInventory(ctx, tfeClient, terraformOrgName, "providers")
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading the providers of the workspace (the Explorer is only available in HCP Terraform, submit the configuration instead on Terraform Enterprise)", err)
+		}
+		modules, _, err := readExplorerInventory(ctx, tfeClient, terraformOrgName, "modules")
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading the modules of the workspace", err)
+		}
+		subject = terraformOrgName + "/" + workspaceName
+		dependencies = workspaceAdvisoryDependencies(workspaceName, providers, modules)
+
+	default:
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: either 'configuration' and/or 'lock_file', 'workspace_id', or both 'terraform_org_name' and 'workspace_name' must be provided", nil)
+	}
+
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting http client for the Terraform registry", err)
+	}
+
+	report := AdvisoryReport{Subject: subject, Findings: []AdvisoryFinding{}}
+	if len(dependencies) > maxAdvisoryDependencies {
+		dependencies, report.Truncated = dependencies[:maxAdvisoryDependencies], true
+	}
+
+	findings := make([][]AdvisoryFinding, len(dependencies))
+	semaphore := make(chan struct{}, advisoryLookupConcurrency)
+	var wg sync.WaitGroup
+	for i := range dependencies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			findings[i] = checkDependencyAdvisories(ctx, httpClient, &dependencies[i], logger)
+		}(i)
+	}
+	wg.Wait()
+
+	report.Dependencies = dependencies
+	for _, dependencyFindings := range findings {
+		report.Findings = append(report.Findings, dependencyFindings...)
+	}
+	sortAdvisoryFindings(report.Findings)
+	for _, finding := range report.Findings {
+		if finding.Status == advisoryAffected {
+			report.Affected++
+		}
+	}
+
+	resultJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling advisory report", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// configurationAdvisoryDependencies lists the providers and modules of a configuration, with the locked
+// provider versions
+func configurationAdvisoryDependencies(providers []configProvider, modules []configModule) []AdvisoryDependency {
+	dependencies := make([]AdvisoryDependency, 0, len(providers)+len(modules))
+	for _, provider := range providers {
+		dependency := AdvisoryDependency{Kind: "provider", Source: provider.Address, Version: provider.Locked, Constraint: provider.Constraint}
+		if dependency.Version == "" && exactVersion.MatchString(provider.Constraint) {
+			dependency.Version = strings.TrimSpace(strings.TrimPrefix(provider.Constraint, "="))
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	for _, module := range modules {
+		dependency := AdvisoryDependency{Kind: "module", Source: module.Source, Constraint: module.Version}
+		if exactVersion.MatchString(module.Version) {
+			dependency.Version = strings.TrimSpace(strings.TrimPrefix(module.Version, "="))
+		} else if moduleSourceType(module.Source) == "git" {
+			dependency.Version = gitRef(module.Source)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	sortAdvisoryDependencies(dependencies)
+	return dependencies
+}
+
+// workspaceAdvisoryDependencies lists the providers and modules the Explorer reports for a workspace
+func workspaceAdvisoryDependencies(workspace string, providers []explorerInventoryRow, modules []explorerInventoryRow) []AdvisoryDependency {
+	var dependencies []AdvisoryDependency
+	for kind, rows := range map[string][]explorerInventoryRow{"provider": providers, "module": modules} {
+		for _, row := range rows {
+			if !usedByWorkspace(row, workspace) {
+				continue
+			}
+			dependency := AdvisoryDependency{Kind: kind, Source: row.Source, Version: row.Version}
+			if kind == "provider" {
+				dependency.Source = normalizeProviderAddress(row.Source)
+			}
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	sortAdvisoryDependencies(dependencies)
+	return dependencies
+}
+
+func usedByWorkspace(row explorerInventoryRow, workspace string) bool {
+	for _, name := range strings.Split(row.Workspaces, ",") {
+		if strings.TrimSpace(name) == workspace {
+			return true
+		}
+	}
+	return false
+}
+
+func sortAdvisoryDependencies(dependencies []AdvisoryDependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Kind != dependencies[j].Kind {
+			return dependencies[i].Kind > dependencies[j].Kind
+		}
+		return dependencies[i].Source < dependencies[j].Source
+	})
+}
+
+// checkDependencyAdvisories looks up the GitHub repository of a dependency and matches its advisories with
+// the version in use. Lookup failures are recorded on the dependency.
+func checkDependencyAdvisories(ctx context.Context, httpClient *http.Client, dependency *AdvisoryDependency, logger *log.Logger) []AdvisoryFinding {
+	repository, skipped, err := dependencyRepository(ctx, httpClient, *dependency, logger)
+	if err != nil {
+		dependency.Error = err.Error()
+		return nil
+	}
+	if skipped != "" {
+		dependency.Skipped = skipped
+		return nil
+	}
+	dependency.Repository = repository.String()
+
+	advisories, err := client.ListGitHubRepositoryAdvisories(ctx, httpClient, repository, logger)
+	if err != nil {
+		dependency.Error = fmt.Sprintf("listing the advisories of %s: %v", repository, err)
+		return nil
+	}
+	findings := matchAdvisories(*dependency, advisories)
+	dependency.Advisories = len(findings)
+	return findings
+}
+
+// dependencyRepository returns the GitHub repository of a dependency, or why it is skipped
+func dependencyRepository(ctx context.Context, httpClient *http.Client, dependency AdvisoryDependency, logger *log.Logger) (client.GitHubRepository, string, error) {
+	if dependency.Kind == "provider" {
+		parts := strings.Split(dependency.Source, "/")
+		if len(parts) != 3 || parts[0] != defaultProviderHost {
+			return client.GitHubRepository{}, "the provider is not published on the public registry", nil
+		}
+		response, err := client.SendRegistryCall(ctx, httpClient, "GET", fmt.Sprintf("providers/%s/%s", parts[1], parts[2]), logger)
+		if err != nil {
+			return client.GitHubRepository{}, "", fmt.Errorf("reading provider %s/%s: %w", parts[1], parts[2], err)
+		}
+		var provider client.ProviderVersionLatest
+		if err := client.DecodeRegistryResponse(response, &provider, logger); err != nil {
+			return client.GitHubRepository{}, "", fmt.Errorf("unmarshalling provider %s/%s: %w", parts[1], parts[2], err)
+		}
+		return repositoryOf(provider.Source)
+	}
+
+	switch moduleSourceType(dependency.Source) {
+	case "git":
+		return repositoryOf(dependency.Source)
+	case "registry":
+		address := strings.SplitN(dependency.Source, "//", 2)[0]
+		address = strings.TrimPrefix(address, defaultProviderHost+"/")
+		if strings.Count(address, "/") != 2 {
+			return client.GitHubRepository{}, "the module is not published on the public registry", nil
+		}
+		response, err := client.SendRegistryCall(ctx, httpClient, "GET", "modules/"+address, logger)
+		if err != nil {
+			return client.GitHubRepository{}, "", fmt.Errorf("reading module %s: %w", address, err)
+		}
+		var module client.TerraformModuleVersionDetails
+		if err := client.DecodeRegistryResponse(response, &module, logger); err != nil {
+			return client.GitHubRepository{}, "", fmt.Errorf("unmarshalling module %s: %w", address, err)
+		}
+		return repositoryOf(module.Source)
+	case "local":
+		return client.GitHubRepository{}, "local modules are part of the configuration", nil
+	}
+	return client.GitHubRepository{}, "the module is not hosted on GitHub", nil
+}
+
+func repositoryOf(source string) (client.GitHubRepository, string, error) {
+	repository, ok := client.ParseGitHubSource(source)
+	if !ok {
+		return client.GitHubRepository{}, fmt.Sprintf("the source repository %q is not on GitHub", source), nil
+	}
+	return repository, "", nil
+}
+
+// matchAdvisories returns the advisories affecting the version of a dependency, or all of them when the
+// version is unknown. Withdrawn advisories are left out.
+func matchAdvisories(dependency AdvisoryDependency, advisories []client.GitHubAdvisory) []AdvisoryFinding {
+	var current *goversion.Version
+	if dependency.Version != "" {
+		current, _ = goversion.NewVersion(dependency.Version)
+	}
+
+	var findings []AdvisoryFinding
+	for _, advisory := range advisories {
+		if advisory.WithdrawnAt != nil {
+			continue
+		}
+		finding := AdvisoryFinding{
+			Kind:     dependency.Kind,
+			Source:   dependency.Source,
+			Version:  dependency.Version,
+			Status:   advisoryVersionUnknown,
+			GHSAID:   advisory.GHSAID,
+			CVEID:    advisory.CVEID,
+			Severity: advisory.Severity,
+			Summary:  advisory.Summary,
+			URL:      advisory.HTMLURL,
+		}
+		if advisory.CVSS != nil {
+			finding.CVSSScore = advisory.CVSS.Score
+		}
+
+		matched := current == nil
+		for _, vulnerability := range advisory.Vulnerabilities {
+			finding.VulnerableVersions = vulnerability.VulnerableVersionRange
+			finding.PatchedVersions = vulnerability.PatchedVersions
+			if current == nil {
+				break
+			}
+			if vulnerableVersion(current, vulnerability.VulnerableVersionRange) {
+				finding.Status = advisoryAffected
+				matched = true
+				break
+			}
+		}
+		if matched {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// vulnerableVersion reports whether a version is in a vulnerable range such as ">= 1.0.0, < 1.2.3". An empty
+// or unreadable range is taken to include every version.
+func vulnerableVersion(current *goversion.Version, vulnerableRange string) bool {
+	if strings.TrimSpace(vulnerableRange) == "" {
+		return true
+	}
+	constraints, err := goversion.NewConstraint(vulnerableRange)
+	if err != nil {
+		return true
+	}
+	return constraints.Check(current)
+}
+
+func sortAdvisoryFindings(findings []AdvisoryFinding) {
+	rank := func(severity string) int {
+		if r, ok := advisorySeverityRank[strings.ToLower(severity)]; ok {
+			return r
+		}
+		return len(advisorySeverityRank)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Status != findings[j].Status {
+			return findings[i].Status == advisoryAffected
+		}
+		if rank(findings[i].Severity) != rank(findings[j].Severity) {
+			return rank(findings[i].Severity) < rank(findings[j].Severity)
+		}
+		return findings[i].Source < findings[j].Source
+	})
+}
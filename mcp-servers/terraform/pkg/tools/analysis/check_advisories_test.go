@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurationAdvisoryDependencies(t *testing.T) {
+	providers, modules, err := parseConfigurationDependencies(testInventoryConfiguration, testInventoryLockFile)
+	require.NoError(t, err)
+	dependencies := configurationAdvisoryDependencies(providers, modules)
+
+	require.Len(t, dependencies, 5)
+	assert.Equal(t, AdvisoryDependency{Kind: "provider", Source: "registry.terraform.io/hashicorp/aws", Version: "5.31.0", Constraint: "~> 5.0"}, dependencies[0])
+	assert.Equal(t, AdvisoryDependency{Kind: "provider", Source: "registry.terraform.io/hashicorp/random", Version: "3.6.0", Constraint: "3.6.0"}, dependencies[1])
+	assert.Equal(t, "./modules/app", dependencies[2].Source)
+	assert.Empty(t, dependencies[2].Version)
+	assert.Equal(t, "v1.2.0", dependencies[3].Version)
+	assert.Equal(t, "5.8.1", dependencies[4].Version)
+}
+
+func TestWorkspaceAdvisoryDependencies(t *testing.T) {
+	providers := []explorerInventoryRow{
+		{Name: "aws", Source: "hashicorp/aws", Version: "5.31.0", Workspaces: "network, app"},
+		{Name: "google", Source: "hashicorp/google", Version: "6.0.0", Workspaces: "data"},
+	}
+	modules := []explorerInventoryRow{{Name: "vpc", Source: "terraform-aws-modules/vpc/aws", Version: "5.8.1", Workspaces: "app-legacy"}}
+
+	dependencies := workspaceAdvisoryDependencies("app", providers, modules)
+	assert.Equal(t, []AdvisoryDependency{{Kind: "provider", Source: "registry.terraform.io/hashicorp/aws", Version: "5.31.0"}}, dependencies)
+}
+
+func TestMatchAdvisories(t *testing.T) {
+	withdrawn := time.Now()
+	advisories := []client.GitHubAdvisory{
+		{
+			GHSAID:   "GHSA-aaaa",
+			CVEID:    "CVE-2024-0001",
+			Severity: "high",
+			Summary:  "Credentials logged",
+			CVSS:     &client.GitHubAdvisoryCVSS{Score: 7.5},
+			Vulnerabilities: []client.GitHubVulnerability{
+				{VulnerableVersionRange: ">= 5.0.0, < 5.32.0", PatchedVersions: "5.32.0"},
+			},
+		},
+		{
+			GHSAID:          "GHSA-bbbb",
+			Severity:        "low",
+			Vulnerabilities: []client.GitHubVulnerability{{VulnerableVersionRange: "< 4.0.0"}},
+		},
+		{GHSAID: "GHSA-cccc", Severity: "critical", WithdrawnAt: &withdrawn},
+	}
+
+	t.Run("reports the advisories affecting the version", func(t *testing.T) {
+		findings := matchAdvisories(AdvisoryDependency{Kind: "provider", Source: "registry.terraform.io/hashicorp/aws", Version: "5.31.0"}, advisories)
+		require.Len(t, findings, 1)
+		assert.Equal(t, advisoryAffected, findings[0].Status)
+		assert.Equal(t, "GHSA-aaaa", findings[0].GHSAID)
+		assert.Equal(t, "CVE-2024-0001", findings[0].CVEID)
+		assert.Equal(t, 7.5, findings[0].CVSSScore)
+		assert.Equal(t, "5.32.0", findings[0].PatchedVersions)
+	})
+
+	t.Run("accepts a v prefix", func(t *testing.T) {
+		findings := matchAdvisories(AdvisoryDependency{Kind: "module", Source: "github.com/acme/vpc", Version: "v3.1.0"}, advisories)
+		require.Len(t, findings, 1)
+		assert.Equal(t, "GHSA-bbbb", findings[0].GHSAID)
+	})
+
+	t.Run("reports every advisory when the version is unknown", func(t *testing.T) {
+		findings := matchAdvisories(AdvisoryDependency{Kind: "provider", Source: "registry.terraform.io/hashicorp/aws", Constraint: "~> 5.0"}, advisories)
+		require.Len(t, findings, 2)
+		for _, finding := range findings {
+			assert.Equal(t, advisoryVersionUnknown, finding.Status)
+		}
+	})
+
+	t.Run("orders findings by status and severity", func(t *testing.T) {
+		findings := []AdvisoryFinding{
+			{Source: "b", Status: advisoryVersionUnknown, Severity: "critical"},
+			{Source: "a", Status: advisoryAffected, Severity: "low"},
+			{Source: "c", Status: advisoryAffected, Severity: "high"},
+		}
+		sortAdvisoryFindings(findings)
+		assert.Equal(t, "c", findings[0].Source)
+		assert.Equal(t, "a", findings[1].Source)
+		assert.Equal(t, "b", findings[2].Source)
+	})
+}
+
+func TestCheckAdvisories(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := CheckAdvisories(logger)
+	assert.Equal(t, "check_advisories", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "check_advisories", Arguments: arguments}}
+	}
+	_, err := checkAdvisoriesHandler(t.Context(), request(map[string]any{}), logger)
+	assert.Error(t, err)
+	_, err = checkAdvisoriesHandler(t.Context(), request(map[string]any{"terraform_org_name": "acme"}), logger)
+	assert.Error(t, err)
+
+	// The workspace is read by the shared resolver, which checks the organization given with a workspace_id
+	fake := testutil.NewFakeTFE(t)
+	fake.Respond("GET", "/workspaces/ws-123", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Organization: &tfe.Organization{Name: "acme"}})
+	_, err = checkAdvisoriesHandler(fake.Context(t), request(map[string]any{"workspace_id": "ws-123", "terraform_org_name": "other"}), logger)
+	assert.ErrorContains(t, err, "not other")
+}
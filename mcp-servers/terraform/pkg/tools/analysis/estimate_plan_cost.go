@@ -0,0 +1,342 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+//go:embed static/pricing.json
+var bundledPricing []byte
+
+// pricingDataset is the format of the bundled pricing dataset, and of the file MCP_PRICING_DATA_FILE points at
+type pricingDataset struct {
+	Description   string                     `json:"description"`
+	Currency      string                     `json:"currency"`
+	HoursPerMonth float64                    `json:"hours_per_month"`
+	UsageBased    []string                   `json:"usage_based"`
+	Resources     map[string]resourcePricing `json:"resources"`
+}
+
+type resourcePricing struct {
+	Service    string             `json:"service"`
+	Components []pricingComponent `json:"components"`
+}
+
+// pricingComponent prices a resource, at a flat price or at the price of the value of an attribute, per hour,
+// month or GB-month. The quantity is 1 unless read from an attribute, e.g. the size of a volume. Attributes
+// of nested blocks are addressed with dots, e.g. 'settings.tier'.
+type pricingComponent struct {
+	Name              string             `json:"name"`
+	Unit              string             `json:"unit"`
+	Price             float64            `json:"price,omitempty"`
+	PriceAttribute    string             `json:"price_attribute,omitempty"`
+	Prices            map[string]float64 `json:"prices,omitempty"`
+	DefaultKey        string             `json:"default_key,omitempty"`
+	QuantityAttribute string             `json:"quantity_attribute,omitempty"`
+	DefaultQuantity   float64            `json:"default_quantity,omitempty"`
+}
+
+// CostEstimate is the result of the estimate_plan_cost tool
+type CostEstimate struct {
+	Currency                 string         `json:"currency"`
+	Pricing                  string         `json:"pricing"`
+	TotalMonthlyCost         float64        `json:"total_monthly_cost"`
+	PreviousMonthlyCost      float64        `json:"previous_monthly_cost"`
+	MonthlyCostDiff          float64        `json:"monthly_cost_diff"`
+	Services                 []ServiceCost  `json:"services"`
+	Resources                []ResourceCost `json:"resources"`
+	UsageBasedResources      []string       `json:"usage_based_resources,omitempty"`
+	UnsupportedResourceTypes map[string]int `json:"unsupported_resource_types,omitempty"`
+}
+
+// ServiceCost is the monthly cost of the resources of a service after the plan is applied
+type ServiceCost struct {
+	Service     string  `json:"service"`
+	MonthlyCost float64 `json:"monthly_cost"`
+}
+
+// ResourceCost is the monthly cost of a resource after and before the plan is applied
+type ResourceCost struct {
+	Address             string          `json:"address"`
+	Type                string          `json:"type"`
+	Service             string          `json:"service"`
+	Actions             string          `json:"actions"`
+	MonthlyCost         float64         `json:"monthly_cost"`
+	PreviousMonthlyCost float64         `json:"previous_monthly_cost"`
+	Components          []CostComponent `json:"components"`
+	Unpriced            []string        `json:"unpriced,omitempty"`
+}
+
+// CostComponent is a priced part of a resource
+type CostComponent struct {
+	Name        string  `json:"name"`
+	Unit        string  `json:"unit"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	MonthlyCost float64 `json:"monthly_cost"`
+}
+
+// EstimatePlanCost creates a tool that estimates the monthly cost of the resources of a plan.
+func EstimatePlanCost(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("estimate_plan_cost",
+			mcp.WithDescription(`Estimates the monthly cost of the resources of a Terraform plan from a pricing dataset, without HCP Terraform cost estimation: provide the 'plan_json' output of 'terraform show -json <planfile>'.
+Returns the monthly cost after the plan is applied, the cost before it and the difference, broken down by service and by resource, the resources billed by usage, and the resource types the dataset has no price for.
+The bundled dataset holds approximate on-demand list prices of common aws, google and azurerm resources in one region per provider; MCP_PRICING_DATA_FILE may point the server at another dataset. Treat the result as an order of magnitude, not a quote.`),
+			mcp.WithTitleAnnotation("Estimate the monthly cost of a plan"),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("plan_json",
+				mcp.Required(),
+				mcp.Description("The JSON plan to estimate, i.e. the output of 'terraform show -json <planfile>'"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return estimatePlanCostHandler(ctx, request, logger)
+		},
+	}
+}
+
+func estimatePlanCostHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	planJSON, err := request.RequireString("plan_json")
+	if err != nil || strings.TrimSpace(planJSON) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: plan_json is required", err)
+	}
+
+	pricing, source, err := loadPricingDataset()
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "loading the pricing dataset", err)
+	}
+	estimate, err := estimatePlanCost([]byte(planJSON), pricing)
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing plan", err)
+	}
+	estimate.Pricing = source
+
+	resultJSON, err := json.Marshal(estimate)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling cost estimate", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// loadPricingDataset reads the dataset MCP_PRICING_DATA_FILE points at, or the bundled one, and describes it
+func loadPricingDataset() (*pricingDataset, string, error) {
+	raw := bundledPricing
+	path := strings.TrimSpace(os.Getenv(client.PricingDataFile))
+	if path != "" {
+		var err error
+		if raw, err = os.ReadFile(path); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var pricing pricingDataset
+	if err := json.Unmarshal(raw, &pricing); err != nil {
+		return nil, "", fmt.Errorf("invalid pricing dataset: %w", err)
+	}
+	if pricing.HoursPerMonth <= 0 {
+		pricing.HoursPerMonth = 730
+	}
+	if pricing.Currency == "" {
+		pricing.Currency = "USD"
+	}
+	source := "bundled"
+	if path != "" {
+		source = path
+	}
+	if pricing.Description != "" {
+		source += ": " + pricing.Description
+	}
+	return &pricing, source, nil
+}
+
+// estimatePlanCost prices the managed resources of a plan before and after it is applied
+func estimatePlanCost(raw []byte, pricing *pricingDataset) (*CostEstimate, error) {
+	var plan terraformPlan
+	if err := json.Unmarshal(raw, &plan); err != nil {
+		return nil, fmt.Errorf("plan is not valid JSON: %w", err)
+	}
+	if plan.FormatVersion == "" {
+		return nil, fmt.Errorf("plan has no format_version, expected the output of 'terraform show -json <planfile>'")
+	}
+
+	usageBased := map[string]bool{}
+	for _, resourceType := range pricing.UsageBased {
+		usageBased[resourceType] = true
+	}
+
+	estimate := &CostEstimate{Currency: pricing.Currency, Services: []ServiceCost{}, Resources: []ResourceCost{}}
+	services := map[string]float64{}
+	for _, change := range plan.ResourceChanges {
+		if change.Mode == "data" {
+			continue
+		}
+		resourcePricing, ok := pricing.Resources[change.Type]
+		if !ok {
+			if usageBased[change.Type] {
+				estimate.UsageBasedResources = append(estimate.UsageBasedResources, change.Address)
+			} else {
+				if estimate.UnsupportedResourceTypes == nil {
+					estimate.UnsupportedResourceTypes = map[string]int{}
+				}
+				estimate.UnsupportedResourceTypes[change.Type]++
+			}
+			continue
+		}
+
+		resource := ResourceCost{
+			Address:    change.Address,
+			Type:       change.Type,
+			Service:    resourcePricing.Service,
+			Actions:    strings.Join(change.Change.Actions, ","),
+			Components: []CostComponent{},
+		}
+		if change.Change.After != nil {
+			after, _ := markUnknown(change.Change.After, change.Change.AfterUnknown).(map[string]any)
+			resource.Components, resource.Unpriced = priceResource(resourcePricing, after, pricing.HoursPerMonth)
+			for _, component := range resource.Components {
+				resource.MonthlyCost += component.MonthlyCost
+			}
+		}
+		if change.Change.Before != nil {
+			before, _ := priceResource(resourcePricing, change.Change.Before, pricing.HoursPerMonth)
+			for _, component := range before {
+				resource.PreviousMonthlyCost += component.MonthlyCost
+			}
+		}
+		resource.MonthlyCost = roundCost(resource.MonthlyCost)
+		resource.PreviousMonthlyCost = roundCost(resource.PreviousMonthlyCost)
+
+		estimate.TotalMonthlyCost += resource.MonthlyCost
+		estimate.PreviousMonthlyCost += resource.PreviousMonthlyCost
+		services[resource.Service] += resource.MonthlyCost
+		estimate.Resources = append(estimate.Resources, resource)
+	}
+
+	estimate.TotalMonthlyCost = roundCost(estimate.TotalMonthlyCost)
+	estimate.PreviousMonthlyCost = roundCost(estimate.PreviousMonthlyCost)
+	estimate.MonthlyCostDiff = roundCost(estimate.TotalMonthlyCost - estimate.PreviousMonthlyCost)
+	for service, cost := range services {
+		estimate.Services = append(estimate.Services, ServiceCost{Service: service, MonthlyCost: roundCost(cost)})
+	}
+	sort.Slice(estimate.Services, func(i, j int) bool {
+		if estimate.Services[i].MonthlyCost != estimate.Services[j].MonthlyCost {
+			return estimate.Services[i].MonthlyCost > estimate.Services[j].MonthlyCost
+		}
+		return estimate.Services[i].Service < estimate.Services[j].Service
+	})
+	sort.SliceStable(estimate.Resources, func(i, j int) bool {
+		return estimate.Resources[i].MonthlyCost > estimate.Resources[j].MonthlyCost
+	})
+	return estimate, nil
+}
+
+// priceResource prices the components of a resource from its values, and explains the components it could
+// not price
+func priceResource(resourcePricing resourcePricing, values map[string]any, hoursPerMonth float64) ([]CostComponent, []string) {
+	components := []CostComponent{}
+	var unpriced []string
+	for _, pricingComponent := range resourcePricing.Components {
+		unitPrice := pricingComponent.Price
+		if pricingComponent.PriceAttribute != "" {
+			key, known := priceKey(attributeValue(values, pricingComponent.PriceAttribute))
+			if !known {
+				unpriced = append(unpriced, fmt.Sprintf("%s: %s is not known until apply", pricingComponent.Name, pricingComponent.PriceAttribute))
+				continue
+			}
+			if key == "" {
+				key = pricingComponent.DefaultKey
+			}
+			price, ok := pricingComponent.Prices[key]
+			if !ok {
+				unpriced = append(unpriced, fmt.Sprintf("%s: no price for %s %q", pricingComponent.Name, pricingComponent.PriceAttribute, key))
+				continue
+			}
+			unitPrice = price
+		}
+
+		quantity := 1.0
+		if pricingComponent.QuantityAttribute != "" {
+			number, ok := attributeValue(values, pricingComponent.QuantityAttribute).(float64)
+			switch {
+			case ok:
+				quantity = number
+			case pricingComponent.DefaultQuantity > 0:
+				quantity = pricingComponent.DefaultQuantity
+			default:
+				unpriced = append(unpriced, fmt.Sprintf("%s: %s is not known until apply", pricingComponent.Name, pricingComponent.QuantityAttribute))
+				continue
+			}
+		}
+
+		monthlyCost := unitPrice * quantity
+		if pricingComponent.Unit == "hour" {
+			monthlyCost *= hoursPerMonth
+		}
+		components = append(components, CostComponent{
+			Name:        pricingComponent.Name,
+			Unit:        pricingComponent.Unit,
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			MonthlyCost: roundCost(monthlyCost),
+		})
+	}
+	return components, unpriced
+}
+
+// attributeValue reads an attribute at a dotted path, taking the first block of nested blocks
+func attributeValue(values map[string]any, path string) any {
+	var current any = values
+	for _, name := range strings.Split(path, ".") {
+		if list, ok := current.([]any); ok {
+			if len(list) == 0 {
+				return nil
+			}
+			current = list[0]
+		}
+		object, ok := current.(map[string]any)
+		if !ok {
+			return current
+		}
+		current = object[name]
+	}
+	return current
+}
+
+// priceKey returns the string a price is looked up by, the first one of a list, and whether it is known
+func priceKey(value any) (string, bool) {
+	switch value := value.(type) {
+	case unknownValue:
+		return "", false
+	case string:
+		return value, true
+	case []any:
+		if len(value) == 0 {
+			return "", true
+		}
+		return priceKey(value[0])
+	}
+	return "", true
+}
+
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
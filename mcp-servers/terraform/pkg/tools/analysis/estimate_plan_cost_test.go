@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCostPlan = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {
+      "address": "aws_instance.web[0]",
+      "mode": "managed",
+      "type": "aws_instance",
+      "change": {"actions": ["update"], "before": {"instance_type": "t3.micro"}, "after": {"instance_type": "t3.large", "root_block_device": []}, "after_unknown": {"root_block_device": true}}
+    },
+    {
+      "address": "aws_db_instance.main",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "change": {"actions": ["create"], "before": null, "after": {"instance_class": "db.t3.medium", "allocated_storage": 100, "storage_type": "gp3"}, "after_unknown": {}}
+    },
+    {
+      "address": "aws_nat_gateway.main",
+      "mode": "managed",
+      "type": "aws_nat_gateway",
+      "change": {"actions": ["delete"], "before": {"subnet_id": "subnet-1"}, "after": null}
+    },
+    {
+      "address": "aws_ebs_volume.data",
+      "mode": "managed",
+      "type": "aws_ebs_volume",
+      "change": {"actions": ["create"], "before": null, "after": {"type": null}, "after_unknown": {"size": true}}
+    },
+    {
+      "address": "google_sql_database_instance.main",
+      "mode": "managed",
+      "type": "google_sql_database_instance",
+      "change": {"actions": ["create"], "before": null, "after": {"settings": [{"tier": "db-custom-2-7680"}]}, "after_unknown": {}}
+    },
+    {"address": "aws_s3_bucket.logs", "mode": "managed", "type": "aws_s3_bucket", "change": {"actions": ["create"], "after": {}}},
+    {"address": "aws_iam_role.app", "mode": "managed", "type": "aws_iam_role", "change": {"actions": ["create"], "after": {}}},
+    {"address": "aws_iam_role.ci", "mode": "managed", "type": "aws_iam_role", "change": {"actions": ["no-op"], "before": {}, "after": {}}},
+    {"address": "data.aws_ami.ubuntu", "mode": "data", "type": "aws_ami", "change": {"actions": ["read"], "after": {}}}
+  ]
+}`
+
+func TestEstimatePlanCost(t *testing.T) {
+	pricing, source, err := loadPricingDataset()
+	require.NoError(t, err)
+	assert.Contains(t, source, "bundled")
+
+	estimate, err := estimatePlanCost([]byte(testCostPlan), pricing)
+	require.NoError(t, err)
+
+	resources := map[string]ResourceCost{}
+	for _, resource := range estimate.Resources {
+		resources[resource.Address] = resource
+	}
+	require.Len(t, resources, 5)
+
+	web := resources["aws_instance.web[0]"]
+	assert.Equal(t, "update", web.Actions)
+	// t3.large for 730 hours and the default 8 GB root volume
+	assert.Equal(t, 61.38, web.MonthlyCost)
+	// t3.micro for 730 hours and the default root volume
+	assert.Equal(t, 8.23, web.PreviousMonthlyCost)
+
+	database := resources["aws_db_instance.main"]
+	require.Len(t, database.Components, 2)
+	assert.Equal(t, 49.64, database.Components[0].MonthlyCost)
+	assert.Equal(t, 11.5, database.Components[1].MonthlyCost)
+	assert.Equal(t, 61.14, database.MonthlyCost)
+
+	nat := resources["aws_nat_gateway.main"]
+	assert.Equal(t, 0.0, nat.MonthlyCost)
+	assert.Equal(t, 32.85, nat.PreviousMonthlyCost)
+
+	assert.Equal(t, []string{"Storage: size is not known until apply"}, resources["aws_ebs_volume.data"].Unpriced)
+	assert.Equal(t, []string{`Database instance: no price for settings.tier "db-custom-2-7680"`}, resources["google_sql_database_instance.main"].Unpriced)
+
+	assert.Equal(t, 122.52, estimate.TotalMonthlyCost)
+	assert.Equal(t, 41.08, estimate.PreviousMonthlyCost)
+	assert.Equal(t, 81.44, estimate.MonthlyCostDiff)
+	assert.Equal(t, "Amazon EC2", estimate.Services[0].Service)
+	assert.Equal(t, []string{"aws_s3_bucket.logs"}, estimate.UsageBasedResources)
+	assert.Equal(t, map[string]int{"aws_iam_role": 2}, estimate.UnsupportedResourceTypes)
+	assert.Equal(t, "aws_instance.web[0]", estimate.Resources[0].Address)
+}
+
+func TestEstimatePlanCostHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := EstimatePlanCost(logger)
+	assert.Equal(t, "estimate_plan_cost", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "estimate_plan_cost", Arguments: arguments}}
+	}
+
+	t.Run("uses the configured dataset", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pricing.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"currency": "EUR", "resources": {"aws_nat_gateway": {"service": "VPC", "components": [{"name": "NAT", "unit": "month", "price": 40}]}}}`), 0o600))
+		t.Setenv(client.PricingDataFile, path)
+
+		result, err := estimatePlanCostHandler(t.Context(), request(map[string]any{"plan_json": testCostPlan}), logger)
+		require.NoError(t, err)
+		var estimate CostEstimate
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &estimate))
+		assert.Equal(t, "EUR", estimate.Currency)
+		assert.Equal(t, path, estimate.Pricing)
+		assert.Equal(t, 40.0, estimate.PreviousMonthlyCost)
+		assert.Equal(t, -40.0, estimate.MonthlyCostDiff)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		for _, arguments := range []map[string]any{{}, {"plan_json": "{}"}, {"plan_json": "not json"}} {
+			_, err := estimatePlanCostHandler(t.Context(), request(arguments), logger)
+			assert.Error(t, err, arguments)
+		}
+	})
+}
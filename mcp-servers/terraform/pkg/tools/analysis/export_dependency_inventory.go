@@ -0,0 +1,494 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/hashicorp/terraform-mcp-server/version"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultProviderHost is the registry host of provider sources without one
+	defaultProviderHost = "registry.terraform.io"
+	// inventoryPageSize and maxInventoryPages bound the Explorer pages read for an organization
+	inventoryPageSize = 100
+	maxInventoryPages = 20
+)
+
+var (
+	// exactVersion matches a version constraint pinning a single version, e.g. "1.2.3" or "= 1.2.3"
+	exactVersion = regexp.MustCompile(`^=?\s*v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+	// registryModuleSource matches a registry module source, e.g. "terraform-aws-modules/vpc/aws" or
+	// "app.terraform.io/acme/vpc/aws", with an optional subdirectory
+	registryModuleSource = regexp.MustCompile(`^([a-z0-9.-]+\.[a-z0-9.-]+(:\d+)?/)?[0-9A-Za-z_-]+/[0-9A-Za-z_-]+/[0-9a-z]+(//.*)?$`)
+)
+
+// DependencyInventory is a CycloneDX-style bill of materials of the providers and modules used by an
+// organization or a configuration. Terraform specifics are kept in properties prefixed with "terraform:".
+type DependencyInventory struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    InventoryMetadata    `json:"metadata"`
+	Components  []InventoryComponent `json:"components"`
+	Properties  []InventoryProperty  `json:"properties,omitempty"`
+}
+
+// InventoryMetadata describes when and from what the inventory was produced
+type InventoryMetadata struct {
+	Timestamp time.Time            `json:"timestamp"`
+	Tools     []InventoryComponent `json:"tools"`
+	Component InventoryComponent   `json:"component"`
+}
+
+// InventoryComponent is a provider or module of the inventory
+type InventoryComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Group      string              `json:"group,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Properties []InventoryProperty `json:"properties,omitempty"`
+}
+
+// InventoryProperty is a name and value pair of a component or of the inventory
+type InventoryProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// configProvider is a provider required by a configuration or locked by its dependency lock file
+type configProvider struct {
+	Address    string
+	Constraint string
+	Locked     string
+}
+
+// configModule is a module call of a configuration
+type configModule struct {
+	Name    string
+	Source  string
+	Version string
+}
+
+// explorerInventoryRow is a row of the providers and modules views of the Explorer API
+type explorerInventoryRow struct {
+	Name           string `json:"name"`
+	Source         string `json:"source"`
+	Version        string `json:"version"`
+	WorkspaceCount int    `json:"workspace-count"`
+	Workspaces     string `json:"workspaces"`
+}
+
+// ExportDependencyInventory creates a tool that exports the providers and modules of an organization or a configuration as a bill of materials.
+func ExportDependencyInventory(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("export_dependency_inventory",
+			mcp.WithDescription(`Exports a machine-readable inventory of the providers and modules in use, with their versions and sources, as CycloneDX-style JSON for supply-chain reviews.
+Provide either 'terraform_org_name' to scan every workspace of an HCP Terraform organization through its Explorer (requires a valid TFE_TOKEN), or the Terraform 'configuration' to inventory, optionally with its '.terraform.lock.hcl' in 'lock_file' for the exact provider versions.
+Each component has 'terraform:*' properties: its kind, full source, version constraint, whether its version is pinned, and for an organization the workspaces using it.`),
+			mcp.WithTitleAnnotation("Export a provider and module inventory"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Description("The HCP Terraform organization whose workspaces are scanned"),
+			),
+			mcp.WithString("configuration",
+				mcp.Description("The Terraform configuration to inventory, i.e. the content of its .tf files, which may be concatenated"),
+			),
+			mcp.WithString("lock_file",
+				mcp.Description("The content of the .terraform.lock.hcl file of the configuration"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return exportDependencyInventoryHandler(ctx, request, logger)
+		},
+	}
+}
+
+func exportDependencyInventoryHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName := strings.TrimSpace(request.GetString("terraform_org_name", ""))
+	configuration := request.GetString("configuration", "")
+	lockFile := request.GetString("lock_file", "")
+	if (terraformOrgName == "") == (configuration == "" && lockFile == "") {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: either 'terraform_org_name' or 'configuration' and/or 'lock_file' must be provided", nil)
+	}
+
+	var inventory *DependencyInventory
+	if terraformOrgName != "" {
+		tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+		}
+		providers, truncated, err := readExplorerInventory(ctx, tfeClient, terraformOrgName, "providers")
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading the providers of the organization (the Explorer is only available in HCP Terraform, submit the configuration instead on Terraform Enterprise)", err)
+		}
+		modules, modulesTruncated, err := readExplorerInventory(ctx, tfeClient, terraformOrgName, "modules")
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading the modules of the organization", err)
+		}
+		inventory = organizationInventory(terraformOrgName, providers, modules, truncated || modulesTruncated)
+	} else {
+		providers, modules, err := parseConfigurationDependencies(configuration, lockFile)
+		if err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing configuration", err)
+		}
+		inventory = configurationInventory(providers, modules)
+	}
+
+	resultJSON, err := json.Marshal(inventory)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling dependency inventory", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// readExplorerInventory reads every row of the providers or modules Explorer view of an organization, and
+// whether rows were left out past maxInventoryPages
+func readExplorerInventory(ctx context.Context, tfeClient *tfe.Client, organization string, view string) ([]explorerInventoryRow, bool, error) {
+	var rows []explorerInventoryRow
+	for page := 1; page <= maxInventoryPages; page++ {
+		query := url.Values{
+			"type":         {view},
+			"page[number]": {strconv.Itoa(page)},
+			"page[size]":   {strconv.Itoa(inventoryPageSize)},
+		}
+		req, err := tfeClient.NewRequestWithAdditionalQueryParams("GET", fmt.Sprintf("organizations/%s/explorer", url.PathEscape(organization)), nil, query)
+		if err != nil {
+			return nil, false, err
+		}
+		var response struct {
+			Data []struct {
+				Attributes explorerInventoryRow `json:"attributes"`
+			} `json:"data"`
+			Meta struct {
+				Pagination *tfe.Pagination `json:"pagination"`
+			} `json:"meta"`
+		}
+		if err := req.DoJSON(ctx, &response); err != nil {
+			return nil, false, err
+		}
+		for _, row := range response.Data {
+			rows = append(rows, row.Attributes)
+		}
+		if response.Meta.Pagination == nil || response.Meta.Pagination.NextPage == 0 {
+			return rows, false, nil
+		}
+	}
+	return rows, true, nil
+}
+
+// organizationInventory builds the inventory of the Explorer rows of an organization
+func organizationInventory(organization string, providers []explorerInventoryRow, modules []explorerInventoryRow, truncated bool) *DependencyInventory {
+	inventory := newInventory(organization)
+	for _, row := range providers {
+		address := normalizeProviderAddress(row.Source)
+		component := providerComponent(address, row.Version)
+		component.Properties = append(component.Properties, workspaceProperties(row)...)
+		inventory.Components = append(inventory.Components, component)
+	}
+	for _, row := range modules {
+		component := moduleComponent(row.Name, row.Source, row.Version)
+		component.Properties = append(component.Properties, workspaceProperties(row)...)
+		inventory.Components = append(inventory.Components, component)
+	}
+	if truncated {
+		inventory.Properties = append(inventory.Properties, InventoryProperty{
+			Name:  "terraform:truncated",
+			Value: fmt.Sprintf("only the first %d providers and modules of each kind are listed", maxInventoryPages*inventoryPageSize),
+		})
+	}
+	sortComponents(inventory.Components)
+	return inventory
+}
+
+// configurationInventory builds the inventory of the providers and modules of a configuration
+func configurationInventory(providers []configProvider, modules []configModule) *DependencyInventory {
+	inventory := newInventory("configuration")
+	for _, provider := range providers {
+		component := providerComponent(provider.Address, provider.Locked)
+		if provider.Constraint != "" {
+			component.Properties = append(component.Properties, InventoryProperty{Name: "terraform:version_constraint", Value: provider.Constraint})
+		}
+		pinned := provider.Locked != "" || exactVersion.MatchString(provider.Constraint)
+		component.Properties = append(component.Properties, InventoryProperty{Name: "terraform:pinned", Value: strconv.FormatBool(pinned)})
+		inventory.Components = append(inventory.Components, component)
+	}
+	for _, module := range modules {
+		component := moduleComponent(module.Name, module.Source, module.Version)
+		if pinned, ok := modulePinned(module); ok {
+			component.Properties = append(component.Properties, InventoryProperty{Name: "terraform:pinned", Value: strconv.FormatBool(pinned)})
+		}
+		inventory.Components = append(inventory.Components, component)
+	}
+	sortComponents(inventory.Components)
+	return inventory
+}
+
+func newInventory(subject string) *DependencyInventory {
+	return &DependencyInventory{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: InventoryMetadata{
+			Timestamp: time.Now().UTC().Truncate(time.Second),
+			Tools:     []InventoryComponent{{Type: "application", Name: "terraform-mcp-server", Version: version.Version}},
+			Component: InventoryComponent{Type: "application", Name: subject},
+		},
+		Components: []InventoryComponent{},
+	}
+}
+
+// providerComponent describes a provider by its full address, e.g. registry.terraform.io/hashicorp/aws
+func providerComponent(address string, providerVersion string) InventoryComponent {
+	component := InventoryComponent{Type: "library", Name: address}
+	if parts := strings.Split(address, "/"); len(parts) == 3 {
+		component.Group = parts[1]
+		component.Name = parts[2]
+	}
+	component.Version = strings.TrimPrefix(providerVersion, "v")
+	component.BOMRef = "provider:" + address
+	if component.Version != "" {
+		component.BOMRef += "@" + component.Version
+	}
+	component.Properties = []InventoryProperty{
+		{Name: "terraform:kind", Value: "provider"},
+		{Name: "terraform:source", Value: address},
+	}
+	return component
+}
+
+// moduleComponent describes a module by its source, e.g. terraform-aws-modules/vpc/aws or a git URL
+func moduleComponent(name string, source string, moduleVersion string) InventoryComponent {
+	sourceType := moduleSourceType(source)
+	component := InventoryComponent{Type: "library", Name: name, Version: moduleVersion}
+	if sourceType == "registry" {
+		address := strings.SplitN(source, "//", 2)[0]
+		parts := strings.Split(address, "/")
+		component.Group = parts[len(parts)-3]
+		component.Name = parts[len(parts)-2] + "/" + parts[len(parts)-1]
+	}
+	if component.Version == "" && sourceType == "git" {
+		component.Version = gitRef(source)
+	}
+	component.BOMRef = "module:" + source
+	if component.Version != "" && sourceType == "registry" {
+		component.BOMRef += "@" + component.Version
+	}
+	component.Properties = []InventoryProperty{
+		{Name: "terraform:kind", Value: "module"},
+		{Name: "terraform:source", Value: source},
+		{Name: "terraform:source_type", Value: sourceType},
+	}
+	return component
+}
+
+func workspaceProperties(row explorerInventoryRow) []InventoryProperty {
+	properties := []InventoryProperty{{Name: "terraform:workspace_count", Value: strconv.Itoa(row.WorkspaceCount)}}
+	if row.Workspaces != "" {
+		properties = append(properties, InventoryProperty{Name: "terraform:workspaces", Value: row.Workspaces})
+	}
+	return properties
+}
+
+// sortComponents orders the components by reference, which groups providers before modules
+func sortComponents(components []InventoryComponent) {
+	sort.Slice(components, func(i, j int) bool { return components[i].BOMRef < components[j].BOMRef })
+}
+
+// moduleSourceType classifies a module source as local, registry, git or archive
+func moduleSourceType(source string) string {
+	switch {
+	case strings.HasPrefix(source, "./"), strings.HasPrefix(source, "../"):
+		return "local"
+	case strings.HasPrefix(source, "git::"), strings.HasPrefix(source, "git@"),
+		strings.HasPrefix(source, "github.com/"), strings.HasPrefix(source, "bitbucket.org/"):
+		return "git"
+	case registryModuleSource.MatchString(source):
+		return "registry"
+	default:
+		return "archive"
+	}
+}
+
+// gitRef returns the ref argument of a git module source, e.g. v1.2.0 in git::https://example.com/vpc.git?ref=v1.2.0
+func gitRef(source string) string {
+	_, query, found := strings.Cut(source, "?")
+	if !found {
+		return ""
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	return values.Get("ref")
+}
+
+// modulePinned reports whether a module call is pinned to a single version, and false for local modules,
+// which have no version of their own
+func modulePinned(module configModule) (bool, bool) {
+	switch moduleSourceType(module.Source) {
+	case "registry":
+		return exactVersion.MatchString(module.Version), true
+	case "git":
+		return gitRef(module.Source) != "", true
+	case "archive":
+		return false, true
+	}
+	return false, false
+}
+
+// normalizeProviderAddress expands a provider source into its full address, e.g. hashicorp/aws into
+// registry.terraform.io/hashicorp/aws
+func normalizeProviderAddress(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	switch strings.Count(source, "/") {
+	case 0:
+		return defaultProviderHost + "/hashicorp/" + source
+	case 1:
+		return defaultProviderHost + "/" + source
+	}
+	return source
+}
+
+// parseConfigurationDependencies extracts the required providers and module calls of a configuration, and
+// the provider versions of its dependency lock file
+func parseConfigurationDependencies(configuration string, lockFile string) ([]configProvider, []configModule, error) {
+	providers := make(map[string]*configProvider)
+	provider := func(address string) *configProvider {
+		if providers[address] == nil {
+			providers[address] = &configProvider{Address: address}
+		}
+		return providers[address]
+	}
+	var modules []configModule
+
+	if configuration != "" {
+		body, err := parseHCLBody(configuration, "main.tf")
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, block := range body.Blocks {
+			switch block.Type {
+			case "terraform":
+				for _, inner := range block.Body.Blocks {
+					if inner.Type != "required_providers" {
+						continue
+					}
+					for name, attribute := range inner.Body.Attributes {
+						source, constraint := requiredProvider(name, attribute.Expr)
+						required := provider(normalizeProviderAddress(source))
+						required.Constraint = constraint
+					}
+				}
+			case "module":
+				if len(block.Labels) != 1 {
+					return nil, nil, errors.New("module block must have exactly one label")
+				}
+				module := configModule{Name: block.Labels[0]}
+				if attribute, ok := block.Body.Attributes["source"]; ok {
+					module.Source = literalString(attribute.Expr)
+				}
+				if attribute, ok := block.Body.Attributes["version"]; ok {
+					module.Version = literalString(attribute.Expr)
+				}
+				if module.Source == "" {
+					return nil, nil, fmt.Errorf("module %q has no literal source", module.Name)
+				}
+				modules = append(modules, module)
+			}
+		}
+	}
+
+	if lockFile != "" {
+		body, err := parseHCLBody(lockFile, ".terraform.lock.hcl")
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "provider" || len(block.Labels) != 1 {
+				continue
+			}
+			locked := provider(normalizeProviderAddress(block.Labels[0]))
+			if attribute, ok := block.Body.Attributes["version"]; ok {
+				locked.Locked = literalString(attribute.Expr)
+			}
+			if attribute, ok := block.Body.Attributes["constraints"]; ok && locked.Constraint == "" {
+				locked.Constraint = literalString(attribute.Expr)
+			}
+		}
+	}
+
+	result := make([]configProvider, 0, len(providers))
+	for _, provider := range providers {
+		result = append(result, *provider)
+	}
+	return result, modules, nil
+}
+
+func parseHCLBody(src string, filename string) (*hclsyntax.Body, error) {
+	file, diags := hclsyntax.ParseConfig([]byte(src), filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, errors.New("unexpected configuration body")
+	}
+	return body, nil
+}
+
+// requiredProvider reads the source and version constraint of a required_providers entry, in its object
+// form or in the legacy form of a bare version constraint of a hashicorp provider
+func requiredProvider(name string, expr hclsyntax.Expression) (string, string) {
+	object, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return name, literalString(expr)
+	}
+	source, constraint := name, ""
+	for _, item := range object.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || key.Type() != cty.String {
+			continue
+		}
+		switch key.AsString() {
+		case "source":
+			source = literalString(item.ValueExpr)
+		case "version":
+			constraint = literalString(item.ValueExpr)
+		}
+	}
+	return source, constraint
+}
+
+// literalString returns the value of an expression made of a literal string, and "" otherwise
+func literalString(expr hcl.Expression) string {
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || !value.IsKnown() || value.Type() != cty.String {
+		return ""
+	}
+	return strings.TrimSpace(value.AsString())
+}
@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testInventoryConfiguration = `
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+    random = "3.6.0"
+  }
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.8.1"
+}
+
+module "dns" {
+  source = "git::https://github.com/acme/terraform-dns.git?ref=v1.2.0"
+}
+
+module "app" {
+  source = "./modules/app"
+}
+`
+
+const testInventoryLockFile = `
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes      = ["h1:abc="]
+}
+`
+
+// property returns the value of a property of a component, or "" when it has none
+func property(component InventoryComponent, name string) string {
+	for _, property := range component.Properties {
+		if property.Name == name {
+			return property.Value
+		}
+	}
+	return ""
+}
+
+func TestConfigurationInventory(t *testing.T) {
+	providers, modules, err := parseConfigurationDependencies(testInventoryConfiguration, testInventoryLockFile)
+	require.NoError(t, err)
+	inventory := configurationInventory(providers, modules)
+
+	assert.Equal(t, "CycloneDX", inventory.BOMFormat)
+	refs := []string{}
+	for _, component := range inventory.Components {
+		refs = append(refs, component.BOMRef)
+	}
+	assert.Equal(t, []string{
+		"module:./modules/app",
+		"module:git::https://github.com/acme/terraform-dns.git?ref=v1.2.0",
+		"module:terraform-aws-modules/vpc/aws@5.8.1",
+		"provider:registry.terraform.io/hashicorp/aws@5.31.0",
+		"provider:registry.terraform.io/hashicorp/random",
+	}, refs)
+
+	app, dns, vpc, aws, random := inventory.Components[0], inventory.Components[1], inventory.Components[2], inventory.Components[3], inventory.Components[4]
+	assert.Equal(t, "local", property(app, "terraform:source_type"))
+	assert.Empty(t, property(app, "terraform:pinned"))
+	assert.Equal(t, "v1.2.0", dns.Version)
+	assert.Equal(t, "true", property(dns, "terraform:pinned"))
+	assert.Equal(t, "terraform-aws-modules", vpc.Group)
+	assert.Equal(t, "vpc/aws", vpc.Name)
+	assert.Equal(t, "registry", property(vpc, "terraform:source_type"))
+
+	assert.Equal(t, "hashicorp", aws.Group)
+	assert.Equal(t, "aws", aws.Name)
+	assert.Equal(t, "5.31.0", aws.Version)
+	assert.Equal(t, "~> 5.0", property(aws, "terraform:version_constraint"))
+	assert.Equal(t, "true", property(aws, "terraform:pinned"))
+	assert.Equal(t, "3.6.0", property(random, "terraform:version_constraint"))
+	assert.Equal(t, "true", property(random, "terraform:pinned"))
+}
+
+func TestModuleSourceType(t *testing.T) {
+	tests := map[string]string{
+		"./modules/app":                          "local",
+		"../shared":                              "local",
+		"terraform-aws-modules/vpc/aws":          "registry",
+		"app.terraform.io/acme/vpc/aws//subnet":  "registry",
+		"github.com/acme/terraform-vpc":          "git",
+		"git@github.com:acme/vpc.git":            "git",
+		"https://example.com/vpc.zip":            "archive",
+		"s3::https://s3.amazonaws.com/b/vpc.zip": "archive",
+	}
+	for source, expected := range tests {
+		assert.Equal(t, expected, moduleSourceType(source), source)
+	}
+}
+
+func TestExportDependencyInventory(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := ExportDependencyInventory(logger)
+	assert.Equal(t, "export_dependency_inventory", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "export_dependency_inventory", Arguments: arguments}}
+	}
+
+	t.Run("scans an organization through the Explorer", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Handle("GET", "/organizations/acme/explorer", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			if r.URL.Query().Get("type") == "providers" {
+				_, _ = w.Write([]byte(`{"data": [{"attributes": {"name": "aws", "source": "hashicorp/aws", "version": "5.31.0", "workspace-count": 2, "workspaces": "network,app"}}], "meta": {"pagination": {"current-page": 1}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": [{"attributes": {"name": "vpc", "source": "terraform-aws-modules/vpc/aws", "version": "5.8.1", "workspace-count": 1, "workspaces": "network"}}], "meta": {"pagination": {"current-page": 1}}}`))
+		})
+
+		result, err := exportDependencyInventoryHandler(fake.Context(t), request(map[string]any{"terraform_org_name": "acme"}), logger)
+		require.NoError(t, err)
+
+		var inventory DependencyInventory
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &inventory))
+		assert.Equal(t, "acme", inventory.Metadata.Component.Name)
+		require.Len(t, inventory.Components, 2)
+		assert.Equal(t, "module:terraform-aws-modules/vpc/aws@5.8.1", inventory.Components[0].BOMRef)
+		assert.Equal(t, "provider:registry.terraform.io/hashicorp/aws@5.31.0", inventory.Components[1].BOMRef)
+		assert.Equal(t, "network,app", property(inventory.Components[1], "terraform:workspaces"))
+		assert.Equal(t, "2", property(inventory.Components[1], "terraform:workspace_count"))
+		assert.Empty(t, inventory.Properties)
+	})
+
+	t.Run("needs an organization or a configuration", func(t *testing.T) {
+		_, err := exportDependencyInventoryHandler(t.Context(), request(map[string]any{}), logger)
+		assert.Error(t, err)
+		_, err = exportDependencyInventoryHandler(t.Context(), request(map[string]any{"terraform_org_name": "acme", "configuration": testInventoryConfiguration}), logger)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// lifecycleLookupConcurrency bounds the module versions whose lifecycle is read at the same time
+const lifecycleLookupConcurrency = 4
+
+// DeprecatedConsumersReport lists the workspaces still calling deprecated or revoked versions of a private module
+type DeprecatedConsumersReport struct {
+	Organization   string                       `json:"organization"`
+	Module         string                       `json:"module"`
+	LatestVersion  string                       `json:"latest_version,omitempty"`
+	WorkspaceCount int                          `json:"workspace_count"`
+	Versions       []DeprecatedVersionConsumers `json:"versions"`
+	Truncated      bool                         `json:"truncated,omitempty"`
+}
+
+// DeprecatedVersionConsumers is a deprecated or revoked version of the module with the workspaces calling it
+type DeprecatedVersionConsumers struct {
+	Version    string   `json:"version"`
+	Status     string   `json:"status"`
+	Reason     string   `json:"reason,omitempty"`
+	Link       string   `json:"link,omitempty"`
+	Workspaces []string `json:"workspaces"`
+}
+
+// FindDeprecatedModuleConsumers creates a tool that reports the workspaces still using deprecated or revoked versions of a private module.
+func FindDeprecatedModuleConsumers(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("find_deprecated_module_consumers",
+			mcp.WithDescription(`Reports the workspaces of an HCP Terraform organization still calling deprecated or revoked versions of a module of its private registry, with the reason and link of each deprecation and the latest version to upgrade to.
+The versions in use are read from the modules view of the Explorer (requires a valid TFE_TOKEN), and their status from the private registry. Versions are deprecated and revoked with 'set_module_version_status'.`),
+			mcp.WithTitleAnnotation("Find the workspaces using deprecated module versions"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The HCP Terraform organization whose workspaces are searched"),
+			),
+			mcp.WithString("private_module_id",
+				mcp.Required(),
+				mcp.Description("The private module ID in the format 'module-namespace/module-name/module-provider-name', e.g. 'my-tfc-org/vpc/aws'"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return findDeprecatedModuleConsumersHandler(ctx, request, logger)
+		},
+	}
+}
+
+func findDeprecatedModuleConsumersHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil || strings.TrimSpace(terraformOrgName) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: terraform_org_name is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+	moduleID, err := request.RequireString("private_module_id")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: private_module_id is required", err)
+	}
+	moduleID = strings.TrimSpace(moduleID)
+	parts := strings.Split(moduleID, "/")
+	if len(parts) != 3 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "private_module_id must be in the format 'module-namespace/module-name/module-provider-name'", nil)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+	modules, truncated, err := readExplorerInventory(ctx, tfeClient, terraformOrgName, "modules")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "reading the modules of the organization (the Explorer is only available in HCP Terraform)", err)
+	}
+
+	source := fmt.Sprintf("%s/%s", tfeClient.BaseURL().Host, moduleID)
+	consumers := moduleConsumers(terraformOrgName, source, modules, nil)
+	registryModuleID := tfe.RegistryModuleID{
+		Organization: terraformOrgName,
+		Namespace:    parts[0],
+		Name:         parts[1],
+		Provider:     parts[2],
+		RegistryName: tfe.PrivateRegistry,
+	}
+	lifecycles, err := readVersionLifecycles(ctx, tfeClient, registryModuleID, consumers.Versions)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading the status of the versions of module %s", moduleID), err)
+	}
+
+	report := deprecatedConsumers(consumers, lifecycles)
+	report.Module = moduleID
+	report.Truncated = truncated
+	report.LatestVersion = latestModuleVersion(ctx, tfeClient, source, logger)
+
+	resultJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling deprecated module consumers", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// readVersionLifecycles reads the lifecycle of each version in use, by version
+func readVersionLifecycles(ctx context.Context, tfeClient *tfe.Client, moduleID tfe.RegistryModuleID, versions []ModuleVersionConsumers) (map[string]client.ModuleVersionLifecycle, error) {
+	lifecycles := make(map[string]client.ModuleVersionLifecycle)
+	var inUse []string
+	for _, consumers := range versions {
+		if _, seen := lifecycles[consumers.Version]; !seen && consumers.Version != "" {
+			inUse = append(inUse, consumers.Version)
+		}
+		lifecycles[consumers.Version] = client.ModuleVersionLifecycle{Version: consumers.Version}
+	}
+
+	var mu sync.Mutex
+	var firstErr error
+	semaphore := make(chan struct{}, lifecycleLookupConcurrency)
+	var wg sync.WaitGroup
+	for _, version := range inUse {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			lifecycle, err := client.ReadModuleVersionLifecycle(ctx, tfeClient, moduleID, version)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("version %s: %w", version, err)
+				}
+				return
+			}
+			lifecycles[version] = lifecycle
+		}()
+	}
+	wg.Wait()
+	return lifecycles, firstErr
+}
+
+// deprecatedConsumers keeps the versions of the consumers report that are deprecated or revoked
+func deprecatedConsumers(consumers ModuleConsumersReport, lifecycles map[string]client.ModuleVersionLifecycle) DeprecatedConsumersReport {
+	report := DeprecatedConsumersReport{Organization: consumers.Organization, Versions: []DeprecatedVersionConsumers{}}
+	byVersion := make(map[string]int)
+	workspaces := make(map[string]bool)
+	for _, version := range consumers.Versions {
+		lifecycle := lifecycles[version.Version]
+		entry := DeprecatedVersionConsumers{Version: version.Version}
+		switch {
+		case lifecycle.Revoked():
+			entry.Status, entry.Reason, entry.Link = "revoked", lifecycle.Revocation.Reason, lifecycle.Revocation.Link
+		case lifecycle.Deprecated():
+			entry.Status, entry.Reason, entry.Link = "deprecated", lifecycle.Deprecation.Reason, lifecycle.Deprecation.Link
+		default:
+			continue
+		}
+
+		// Submodule calls of the same version are merged into one entry
+		i, ok := byVersion[version.Version]
+		if !ok {
+			i = len(report.Versions)
+			byVersion[version.Version] = i
+			entry.Workspaces = []string{}
+			report.Versions = append(report.Versions, entry)
+		}
+		for _, workspace := range version.Workspaces {
+			if !workspaces[workspace] {
+				workspaces[workspace] = true
+				report.WorkspaceCount++
+			}
+			if !slices.Contains(report.Versions[i].Workspaces, workspace) {
+				report.Versions[i].Workspaces = append(report.Versions[i].Workspaces, workspace)
+			}
+		}
+	}
+	for _, version := range report.Versions {
+		sort.Strings(version.Workspaces)
+	}
+	return report
+}
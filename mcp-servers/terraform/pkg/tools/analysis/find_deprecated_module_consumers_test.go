@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDeprecatedModuleConsumers(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := FindDeprecatedModuleConsumers(logger)
+	assert.Equal(t, "find_deprecated_module_consumers", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "find_deprecated_module_consumers", Arguments: arguments}}
+	}
+	lifecycle := func(attributes string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = fmt.Fprintf(w, `{"data": {"type": "registry-module-versions", "attributes": %s}}`, attributes)
+		}
+	}
+
+	t.Run("reports the workspaces on deprecated and revoked versions", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		source := fmt.Sprintf("%s/acme/vpc/aws", fake.Client(t).BaseURL().Host)
+		fake.Handle("GET", "/organizations/acme/explorer", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = fmt.Fprintf(w, `{"data": [
+				{"attributes": {"name": "vpc", "source": %q, "version": "2.0.0", "workspace-count": 1, "workspaces": "app"}},
+				{"attributes": {"name": "vpc", "source": %q, "version": "1.4.0", "workspace-count": 2, "workspaces": "network,legacy"}},
+				{"attributes": {"name": "subnet", "source": "%s//modules/subnet", "version": "1.4.0", "workspace-count": 1, "workspaces": "edge"}},
+				{"attributes": {"name": "vpc", "source": %q, "version": "1.0.0", "workspace-count": 1, "workspaces": "old"}}
+			], "meta": {"pagination": {"current-page": 1}}}`, source, source, source, source)
+		})
+		fake.Handle("GET", "/organizations/acme/registry-modules/private/acme/vpc/aws/2.0.0", lifecycle(`{}`))
+		fake.Handle("GET", "/organizations/acme/registry-modules/private/acme/vpc/aws/1.4.0",
+			lifecycle(`{"deprecation": {"deprecated-status": "Deprecated", "reason": "Use 2.x", "link": "https://example.com/upgrade"}}`))
+		fake.Handle("GET", "/organizations/acme/registry-modules/private/acme/vpc/aws/1.0.0",
+			lifecycle(`{"revocation": {"revoked-status": "Revoked", "reason": "Leaks the state bucket"}}`))
+		fake.Respond("GET", "/organizations/acme/registry-modules/private/acme/vpc/aws", http.StatusOK, &tfe.RegistryModule{
+			ID: "mod-123", Name: "vpc", Namespace: "acme", Provider: "aws",
+			VersionStatuses: []tfe.RegistryModuleVersionStatuses{
+				{Version: "1.0.0", Status: tfe.RegistryModuleVersionStatusOk},
+				{Version: "1.4.0", Status: tfe.RegistryModuleVersionStatusOk},
+				{Version: "2.0.0", Status: tfe.RegistryModuleVersionStatusOk},
+			},
+		})
+
+		result, err := findDeprecatedModuleConsumersHandler(fake.Context(t), request(map[string]any{
+			"terraform_org_name": "acme",
+			"private_module_id":  "acme/vpc/aws",
+		}), logger)
+		require.NoError(t, err)
+
+		var report DeprecatedConsumersReport
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &report))
+		assert.Equal(t, "acme/vpc/aws", report.Module)
+		assert.Equal(t, "2.0.0", report.LatestVersion)
+		assert.Equal(t, 4, report.WorkspaceCount)
+		assert.Equal(t, []DeprecatedVersionConsumers{
+			{Version: "1.4.0", Status: "deprecated", Reason: "Use 2.x", Link: "https://example.com/upgrade", Workspaces: []string{"edge", "legacy", "network"}},
+			{Version: "1.0.0", Status: "revoked", Reason: "Leaks the state bucket", Workspaces: []string{"old"}},
+		}, report.Versions)
+	})
+
+	t.Run("reports servers without module version lifecycle", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		source := fmt.Sprintf("%s/acme/vpc/aws", fake.Client(t).BaseURL().Host)
+		fake.Handle("GET", "/organizations/acme/explorer", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = fmt.Fprintf(w, `{"data": [
+				{"attributes": {"name": "vpc", "source": %q, "version": "1.4.0", "workspace-count": 1, "workspaces": "app"}}
+			], "meta": {"pagination": {"current-page": 1}}}`, source)
+		})
+
+		_, err := findDeprecatedModuleConsumersHandler(fake.Context(t), request(map[string]any{
+			"terraform_org_name": "acme",
+			"private_module_id":  "acme/vpc/aws",
+		}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support deprecating and revoking module versions")
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		for name, arguments := range map[string]map[string]any{
+			"missing organization": {"private_module_id": "acme/vpc/aws"},
+			"missing module":       {"terraform_org_name": "acme"},
+			"invalid module":       {"terraform_org_name": "acme", "private_module_id": "vpc/aws"},
+		} {
+			_, err := findDeprecatedModuleConsumersHandler(t.Context(), request(arguments), logger)
+			assert.Error(t, err, name)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// ModuleConsumersReport lists the workspaces of an organization calling a module, grouped by version
+type ModuleConsumersReport struct {
+	Organization      string                   `json:"organization"`
+	ModuleSource      string                   `json:"module_source"`
+	LatestVersion     string                   `json:"latest_version,omitempty"`
+	VersionConstraint string                   `json:"version_constraint,omitempty"`
+	WorkspaceCount    int                      `json:"workspace_count"`
+	Versions          []ModuleVersionConsumers `json:"versions"`
+	Workspaces        []ModuleConsumer         `json:"workspaces"`
+	Truncated         bool                     `json:"truncated,omitempty"`
+}
+
+// ModuleVersionConsumers is a version of the module with the workspaces calling it. Source differs from the
+// module source of the report when workspaces call a submodule or pin a git ref.
+type ModuleVersionConsumers struct {
+	Version        string   `json:"version"`
+	Source         string   `json:"source"`
+	WorkspaceCount int      `json:"workspace_count"`
+	Workspaces     []string `json:"workspaces"`
+	Latest         bool     `json:"latest,omitempty"`
+}
+
+// ModuleConsumer is a workspace calling the module with the versions it uses
+type ModuleConsumer struct {
+	Workspace string   `json:"workspace"`
+	Versions  []string `json:"versions"`
+}
+
+// FindModuleConsumers creates a tool that reports which workspaces of an organization call a module, and with which versions.
+func FindModuleConsumers(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("find_module_consumers",
+			mcp.WithDescription(`Reports which workspaces of an HCP Terraform organization call a module, and with which versions, from the modules view of its Explorer (requires a valid TFE_TOKEN).
+Use it before publishing a breaking change of a module to know who will have to upgrade: set 'version_constraint' to only keep the workspaces on the versions that need attention, e.g. '< 6.0.0'.
+Submodules (e.g. 'terraform-aws-modules/vpc/aws//modules/vpc-endpoints') count as calls of the module. The latest version is read from the public or private registry when the module is published there.`),
+			mcp.WithTitleAnnotation("Find the workspaces calling a module"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The HCP Terraform organization whose workspaces are searched"),
+			),
+			mcp.WithString("module_source",
+				mcp.Required(),
+				mcp.Description("The source of the module as written in module blocks, e.g. 'terraform-aws-modules/vpc/aws', 'app.terraform.io/acme/vpc/aws' or 'git::https://github.com/acme/terraform-vpc.git'"),
+			),
+			mcp.WithString("version_constraint",
+				mcp.Description("Only report the versions matching this constraint, e.g. '< 6.0.0'. Versions that cannot be compared, like git branches, are always reported"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return findModuleConsumersHandler(ctx, request, logger)
+		},
+	}
+}
+
+func findModuleConsumersHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil || strings.TrimSpace(terraformOrgName) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: terraform_org_name is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+	moduleSource, err := request.RequireString("module_source")
+	if err != nil || strings.TrimSpace(moduleSource) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: module_source is required", err)
+	}
+	moduleSource = strings.TrimSpace(moduleSource)
+	if moduleSourceType(moduleSource) == "local" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "checking module_source",
+			fmt.Errorf("local module %q is part of the configuration calling it, give a registry or git source", moduleSource))
+	}
+
+	var constraint goversion.Constraints
+	constraintValue := strings.TrimSpace(request.GetString("version_constraint", ""))
+	if constraintValue != "" {
+		if constraint, err = goversion.NewConstraint(constraintValue); err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing version_constraint", err)
+		}
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+	modules, truncated, err := readExplorerInventory(ctx, tfeClient, terraformOrgName, "modules")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "reading the modules of the organization (the Explorer is only available in HCP Terraform)", err)
+	}
+
+	report := moduleConsumers(terraformOrgName, moduleSource, modules, constraint)
+	report.VersionConstraint = constraintValue
+	report.Truncated = truncated
+	report.LatestVersion = latestModuleVersion(ctx, tfeClient, moduleSource, logger)
+	for i := range report.Versions {
+		report.Versions[i].Latest = report.LatestVersion != "" && report.Versions[i].Version == report.LatestVersion
+	}
+
+	resultJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling module consumers", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// moduleConsumers groups the Explorer rows of the module by version, keeping the versions matching the constraint
+func moduleConsumers(organization string, moduleSource string, modules []explorerInventoryRow, constraint goversion.Constraints) ModuleConsumersReport {
+	report := ModuleConsumersReport{
+		Organization: organization,
+		ModuleSource: moduleSource,
+		Versions:     []ModuleVersionConsumers{},
+		Workspaces:   []ModuleConsumer{},
+	}
+
+	wanted := moduleSourceKey(moduleSource)
+	workspaceVersions := make(map[string][]string)
+	for _, row := range modules {
+		if moduleSourceKey(row.Source) != wanted {
+			continue
+		}
+		version := row.Version
+		if version == "" {
+			version = gitRef(row.Source)
+		}
+		if constraint != nil {
+			if parsed, err := goversion.NewVersion(version); err == nil && !constraint.Check(parsed) {
+				continue
+			}
+		}
+
+		consumers := ModuleVersionConsumers{Version: version, Source: row.Source, WorkspaceCount: row.WorkspaceCount, Workspaces: []string{}}
+		for _, workspace := range strings.Split(row.Workspaces, ",") {
+			if workspace = strings.TrimSpace(workspace); workspace != "" {
+				consumers.Workspaces = append(consumers.Workspaces, workspace)
+				workspaceVersions[workspace] = append(workspaceVersions[workspace], version)
+			}
+		}
+		sort.Strings(consumers.Workspaces)
+		report.Versions = append(report.Versions, consumers)
+	}
+
+	sort.SliceStable(report.Versions, func(i, j int) bool {
+		return compareModuleVersions(report.Versions[i].Version, report.Versions[j].Version) > 0
+	})
+	for workspace, versions := range workspaceVersions {
+		sort.Strings(versions)
+		report.Workspaces = append(report.Workspaces, ModuleConsumer{Workspace: workspace, Versions: versions})
+	}
+	sort.Slice(report.Workspaces, func(i, j int) bool { return report.Workspaces[i].Workspace < report.Workspaces[j].Workspace })
+	report.WorkspaceCount = len(report.Workspaces)
+	return report
+}
+
+// moduleSourceKey identifies the module package of a source, without its subdirectory, git ref and the default
+// registry host, so that all the calls of a module compare equal
+func moduleSourceKey(source string) string {
+	key := strings.ToLower(strings.TrimSpace(source))
+	key = strings.TrimPrefix(key, "git::")
+	key, _, _ = strings.Cut(key, "?")
+	if scheme, rest, found := strings.Cut(key, "://"); found {
+		rest, _, _ = strings.Cut(rest, "//")
+		key = scheme + "://" + rest
+	} else {
+		key, _, _ = strings.Cut(key, "//")
+	}
+	key = strings.TrimPrefix(key, defaultProviderHost+"/")
+	return strings.TrimSuffix(key, ".git")
+}
+
+// compareModuleVersions orders versions semantically, with the versions that cannot be parsed last
+func compareModuleVersions(a, b string) int {
+	va, errA := goversion.NewVersion(a)
+	vb, errB := goversion.NewVersion(b)
+	switch {
+	case errA == nil && errB == nil:
+		return va.Compare(vb)
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	}
+	return strings.Compare(b, a)
+}
+
+// latestModuleVersion returns the latest version of a registry module, from the private registry of the
+// organization when the source has the host of the TFE client, or from the public registry. It returns an
+// empty version for other sources and when the module cannot be read.
+func latestModuleVersion(ctx context.Context, tfeClient *tfe.Client, moduleSource string, logger *log.Logger) string {
+	if moduleSourceType(moduleSource) != "registry" {
+		return ""
+	}
+	address := strings.SplitN(moduleSource, "//", 2)[0]
+	parts := strings.Split(address, "/")
+
+	if len(parts) == 4 && parts[0] != defaultProviderHost {
+		if tfeClient.BaseURL().Host != parts[0] {
+			return ""
+		}
+		module, err := tfeClient.RegistryModules.Read(ctx, tfe.RegistryModuleID{
+			Organization: parts[1],
+			Namespace:    parts[1],
+			Name:         parts[2],
+			Provider:     parts[3],
+			RegistryName: tfe.PrivateRegistry,
+		})
+		if err != nil {
+			logger.WithError(err).Debugf("Reading the latest version of private module %s", address)
+			return ""
+		}
+		latest := ""
+		for _, status := range module.VersionStatuses {
+			if status.Status == tfe.RegistryModuleVersionStatusOk && compareModuleVersions(status.Version, latest) > 0 {
+				latest = status.Version
+			}
+		}
+		return latest
+	}
+
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		logger.WithError(err).Debug("Getting http client for the Terraform registry")
+		return ""
+	}
+	return publicModuleVersion(ctx, httpClient, strings.TrimPrefix(address, defaultProviderHost+"/"), logger)
+}
+
+// publicModuleVersion returns the latest version of a module of the public registry
+func publicModuleVersion(ctx context.Context, httpClient *http.Client, address string, logger *log.Logger) string {
+	response, err := client.SendRegistryCall(ctx, httpClient, "GET", "modules/"+address, logger)
+	if err != nil {
+		logger.WithError(err).Debugf("Reading the latest version of module %s", address)
+		return ""
+	}
+	var module client.TerraformModuleVersionDetails
+	if err := client.DecodeRegistryResponse(response, &module, logger); err != nil {
+		logger.WithError(err).Debugf("Unmarshalling module %s", address)
+		return ""
+	}
+	return module.Version
+}
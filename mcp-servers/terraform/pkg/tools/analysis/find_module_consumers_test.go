@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	goversion "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleSourceKey(t *testing.T) {
+	for source, key := range map[string]string{
+		"terraform-aws-modules/vpc/aws":                                  "terraform-aws-modules/vpc/aws",
+		"registry.terraform.io/terraform-aws-modules/vpc/aws":            "terraform-aws-modules/vpc/aws",
+		"terraform-aws-modules/vpc/aws//modules/vpc-endpoints":           "terraform-aws-modules/vpc/aws",
+		"app.terraform.io/Acme/vpc/aws":                                  "app.terraform.io/acme/vpc/aws",
+		"git::https://github.com/acme/terraform-vpc.git?ref=v1.2.0":      "https://github.com/acme/terraform-vpc",
+		"git::https://github.com/acme/terraform-vpc.git//modules/subnet": "https://github.com/acme/terraform-vpc",
+		"github.com/acme/terraform-vpc?ref=main":                         "github.com/acme/terraform-vpc",
+	} {
+		assert.Equal(t, key, moduleSourceKey(source), source)
+	}
+}
+
+func TestModuleConsumers(t *testing.T) {
+	modules := []explorerInventoryRow{
+		{Name: "vpc", Source: "terraform-aws-modules/vpc/aws", Version: "5.8.1", WorkspaceCount: 2, Workspaces: "network, app"},
+		{Name: "vpc", Source: "terraform-aws-modules/vpc/aws", Version: "4.0.2", WorkspaceCount: 1, Workspaces: "legacy"},
+		{Name: "vpc-endpoints", Source: "terraform-aws-modules/vpc/aws//modules/vpc-endpoints", Version: "5.8.1", WorkspaceCount: 1, Workspaces: "app"},
+		{Name: "eks", Source: "terraform-aws-modules/eks/aws", Version: "20.0.0", WorkspaceCount: 1, Workspaces: "cluster"},
+	}
+
+	report := moduleConsumers("acme", "terraform-aws-modules/vpc/aws", modules, nil)
+	require.Len(t, report.Versions, 3)
+	assert.Equal(t, "5.8.1", report.Versions[0].Version)
+	assert.Equal(t, []string{"app", "network"}, report.Versions[0].Workspaces)
+	assert.Equal(t, "terraform-aws-modules/vpc/aws//modules/vpc-endpoints", report.Versions[1].Source)
+	assert.Equal(t, "4.0.2", report.Versions[2].Version)
+	assert.Equal(t, 3, report.WorkspaceCount)
+	assert.Equal(t, []ModuleConsumer{
+		{Workspace: "app", Versions: []string{"5.8.1", "5.8.1"}},
+		{Workspace: "legacy", Versions: []string{"4.0.2"}},
+		{Workspace: "network", Versions: []string{"5.8.1"}},
+	}, report.Workspaces)
+
+	constraint, err := goversion.NewConstraint("< 5.0.0")
+	require.NoError(t, err)
+	report = moduleConsumers("acme", "terraform-aws-modules/vpc/aws", modules, constraint)
+	require.Len(t, report.Versions, 1)
+	assert.Equal(t, []string{"legacy"}, report.Versions[0].Workspaces)
+
+	// Git refs stand for the version of git sources and are kept when they cannot be compared
+	git := []explorerInventoryRow{
+		{Source: "git::https://github.com/acme/terraform-vpc.git?ref=v1.2.0", Workspaces: "a"},
+		{Source: "git::https://github.com/acme/terraform-vpc.git?ref=main", Workspaces: "b"},
+	}
+	constraint, err = goversion.NewConstraint(">= 2.0.0")
+	require.NoError(t, err)
+	report = moduleConsumers("acme", "git::https://github.com/acme/terraform-vpc.git", git, constraint)
+	require.Len(t, report.Versions, 1)
+	assert.Equal(t, "main", report.Versions[0].Version)
+}
+
+func TestFindModuleConsumers(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := FindModuleConsumers(logger)
+	assert.Equal(t, "find_module_consumers", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "find_module_consumers", Arguments: arguments}}
+	}
+
+	t.Run("reports the workspaces of a private module", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		host := fake.Client(t).BaseURL().Host
+		source := fmt.Sprintf("%s/acme/vpc/aws", host)
+		fake.Handle("GET", "/organizations/acme/explorer", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = fmt.Fprintf(w, `{"data": [
+				{"attributes": {"name": "vpc", "source": %q, "version": "2.0.0", "workspace-count": 1, "workspaces": "app"}},
+				{"attributes": {"name": "vpc", "source": %q, "version": "1.4.0", "workspace-count": 2, "workspaces": "network,legacy"}}
+			], "meta": {"pagination": {"current-page": 1}}}`, source, source)
+		})
+		fake.Respond("GET", "/organizations/acme/registry-modules/private/acme/vpc/aws", http.StatusOK, &tfe.RegistryModule{
+			ID: "mod-123", Name: "vpc", Namespace: "acme", Provider: "aws",
+			VersionStatuses: []tfe.RegistryModuleVersionStatuses{
+				{Version: "1.4.0", Status: tfe.RegistryModuleVersionStatusOk},
+				{Version: "2.0.0", Status: tfe.RegistryModuleVersionStatusOk},
+				{Version: "2.1.0-beta", Status: tfe.RegistryModuleVersionStatusRegIngressFailed},
+			},
+		})
+
+		result, err := findModuleConsumersHandler(fake.Context(t), request(map[string]any{
+			"terraform_org_name": "acme",
+			"module_source":      source,
+			"version_constraint": "< 3.0.0",
+		}), logger)
+		require.NoError(t, err)
+
+		var report ModuleConsumersReport
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &report))
+		assert.Equal(t, "2.0.0", report.LatestVersion)
+		assert.Equal(t, "< 3.0.0", report.VersionConstraint)
+		assert.Equal(t, 3, report.WorkspaceCount)
+		require.Len(t, report.Versions, 2)
+		assert.True(t, report.Versions[0].Latest)
+		assert.False(t, report.Versions[1].Latest)
+		assert.Equal(t, []string{"legacy", "network"}, report.Versions[1].Workspaces)
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		for name, arguments := range map[string]map[string]any{
+			"missing source":     {"terraform_org_name": "acme"},
+			"local source":       {"terraform_org_name": "acme", "module_source": "./modules/vpc"},
+			"invalid constraint": {"terraform_org_name": "acme", "module_source": "terraform-aws-modules/vpc/aws", "version_constraint": "newest"},
+		} {
+			_, err := findModuleConsumersHandler(t.Context(), request(arguments), logger)
+			assert.Error(t, err, name)
+		}
+	})
+}
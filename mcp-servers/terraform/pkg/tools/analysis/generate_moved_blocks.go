@@ -0,0 +1,313 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MovedBlock is a single from/to pair rendered as a Terraform moved block.
+type MovedBlock struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason"`
+}
+
+// MovedBlocksResult is the report returned by the generate_moved_blocks tool.
+type MovedBlocksResult struct {
+	MovedBlocks    []MovedBlock `json:"moved_blocks"`
+	HCL            string       `json:"hcl"`
+	UnmatchedOld   []string     `json:"unmatched_old"`
+	UnmatchedNew   []string     `json:"unmatched_new"`
+	NoMoveNeeded   []string     `json:"no_move_needed"`
+	UnchangedCount int          `json:"unchanged_count"`
+}
+
+// GenerateMovedBlocks creates a tool that produces moved blocks for a refactor between two address layouts.
+func GenerateMovedBlocks(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("generate_moved_blocks",
+			mcp.WithDescription(`Compares the resource addresses of a configuration before and after a refactor and generates the Terraform 'moved' blocks required to avoid destroying and recreating resources.
+Addresses can be taken from 'terraform state list' (old layout) and the planned configuration (new layout). Explicit module renames can be supplied to move whole modules with a single block.
+Addresses that cannot be matched are reported so they can be reviewed manually. Data sources are read again on every plan and cannot be moved, they are reported as needing no moved block.`),
+			mcp.WithTitleAnnotation("Generate moved blocks for a Terraform refactor"),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("old_addresses",
+				mcp.Required(),
+				mcp.Description("Resource addresses in the current layout, separated by newlines or commas (e.g. the output of 'terraform state list')"),
+			),
+			mcp.WithString("new_addresses",
+				mcp.Required(),
+				mcp.Description("Resource addresses in the refactored layout, separated by newlines or commas"),
+			),
+			mcp.WithString("module_moves",
+				mcp.Description("Optional explicit module renames as 'old=new' pairs separated by newlines or commas, e.g. 'module.vpc=module.network'"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return generateMovedBlocksHandler(ctx, request, logger)
+		},
+	}
+}
+
+func generateMovedBlocksHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	oldAddressesStr, err := request.RequireString("old_addresses")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'old_addresses' parameter is required", err)
+	}
+	newAddressesStr, err := request.RequireString("new_addresses")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'new_addresses' parameter is required", err)
+	}
+
+	moduleMoves := make(map[string]string)
+	for _, pair := range splitAddressList(request.GetString("module_moves", "")) {
+		from, to, ok := strings.Cut(pair, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("invalid module move %q, expected 'old=new'", pair), nil)
+		}
+		moduleMoves[from] = to
+	}
+
+	result := generateMovedBlocks(splitAddressList(oldAddressesStr), splitAddressList(newAddressesStr), moduleMoves)
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling moved blocks", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// generateMovedBlocks matches old and new addresses and returns the moved blocks needed to connect them.
+// Matching happens in order of confidence: explicit module moves, identical resource type and name in a
+// different module, and finally a single remaining resource of the same type on both sides.
+func generateMovedBlocks(oldAddresses, newAddresses []string, moduleMoves map[string]string) *MovedBlocksResult {
+	result := &MovedBlocksResult{
+		MovedBlocks:  []MovedBlock{},
+		UnmatchedOld: []string{},
+		UnmatchedNew: []string{},
+		NoMoveNeeded: []string{},
+	}
+
+	newSet := make(map[string]bool, len(newAddresses))
+	for _, address := range newAddresses {
+		newSet[address] = true
+	}
+
+	var removed []string
+	for _, address := range oldAddresses {
+		if newSet[address] {
+			result.UnchangedCount++
+			delete(newSet, address)
+			continue
+		}
+		removed = append(removed, address)
+	}
+
+	// Explicit module moves are emitted once per module and consume every address they cover.
+	moduleSources := make([]string, 0, len(moduleMoves))
+	for from := range moduleMoves {
+		moduleSources = append(moduleSources, from)
+	}
+	sort.Strings(moduleSources)
+	for _, from := range moduleSources {
+		to := moduleMoves[from]
+		used := false
+		remaining := removed[:0:0]
+		for _, address := range removed {
+			if rewritten, ok := rewriteModulePrefix(address, from, to); ok && newSet[rewritten] {
+				delete(newSet, rewritten)
+				used = true
+				continue
+			}
+			remaining = append(remaining, address)
+		}
+		removed = remaining
+		if used {
+			result.MovedBlocks = append(result.MovedBlocks, MovedBlock{From: from, To: to, Reason: "explicit module move"})
+		}
+	}
+
+	added := sortedKeys(newSet)
+
+	// Moved blocks cannot refer to data sources, which are read again under their new address.
+	removed = filterDataSources(result, removed)
+	added = filterDataSources(result, added)
+
+	// Same resource type and name, different module path.
+	removed, added = matchAddresses(result, removed, added, "same resource in a different module", func(address string) string {
+		_, resource := splitModulePath(address)
+		return resource
+	})
+
+	// A single remaining resource of a type on both sides within the same module is treated as a rename.
+	removed, added = matchAddresses(result, removed, added, "only resource of this type in the module", func(address string) string {
+		module, resource := splitModulePath(address)
+		return module + "|" + resourceType(resource)
+	})
+
+	result.UnmatchedOld = append(result.UnmatchedOld, removed...)
+	result.UnmatchedNew = append(result.UnmatchedNew, added...)
+	result.HCL = renderMovedBlocks(result.MovedBlocks)
+	return result
+}
+
+// matchAddresses pairs removed and added addresses that share a key and are unique on both sides.
+func matchAddresses(result *MovedBlocksResult, removed, added []string, reason string, key func(string) string) ([]string, []string) {
+	removedByKey := groupByKey(removed, key)
+	addedByKey := groupByKey(added, key)
+
+	matched := make(map[string]bool)
+	for _, from := range removed {
+		k := key(from)
+		if len(removedByKey[k]) != 1 || len(addedByKey[k]) != 1 {
+			continue
+		}
+		to := addedByKey[k][0]
+		result.MovedBlocks = append(result.MovedBlocks, MovedBlock{From: from, To: to, Reason: reason})
+		matched[from] = true
+		matched[to] = true
+	}
+
+	return filterOut(removed, matched), filterOut(added, matched)
+}
+
+// filterDataSources reports the data sources of addresses as needing no moved block and returns the others.
+func filterDataSources(result *MovedBlocksResult, addresses []string) []string {
+	remaining := []string{}
+	for _, address := range addresses {
+		if _, resource := splitModulePath(address); strings.HasPrefix(resource, "data.") {
+			result.NoMoveNeeded = append(result.NoMoveNeeded, address)
+			continue
+		}
+		remaining = append(remaining, address)
+	}
+	return remaining
+}
+
+func groupByKey(addresses []string, key func(string) string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, address := range addresses {
+		k := key(address)
+		groups[k] = append(groups[k], address)
+	}
+	return groups
+}
+
+func filterOut(addresses []string, exclude map[string]bool) []string {
+	remaining := []string{}
+	for _, address := range addresses {
+		if !exclude[address] {
+			remaining = append(remaining, address)
+		}
+	}
+	return remaining
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rewriteModulePrefix replaces the module path prefix of an address, e.g. module.vpc.aws_subnet.a -> module.network.aws_subnet.a
+func rewriteModulePrefix(address, from, to string) (string, bool) {
+	if !strings.HasPrefix(address, from+".") {
+		return "", false
+	}
+	return to + strings.TrimPrefix(address, from), true
+}
+
+// splitModulePath separates the module path from the resource part of an address.
+// For module.a.module.b["x"].aws_s3_bucket.this it returns (module.a.module.b["x"], aws_s3_bucket.this).
+func splitModulePath(address string) (string, string) {
+	parts := splitAddressSteps(address)
+	i := 0
+	for i+1 < len(parts) && parts[i] == "module" {
+		i += 2
+	}
+	return strings.Join(parts[:i], "."), strings.Join(parts[i:], ".")
+}
+
+// resourceType returns the resource type of the resource part of an address.
+func resourceType(resource string) string {
+	parts := splitAddressSteps(resource)
+	if len(parts) > 0 {
+		return parts[0]
+	}
+	return resource
+}
+
+// splitAddressSteps splits an address on dots that are not inside an index key.
+func splitAddressSteps(address string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range address {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				parts = append(parts, address[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, address[start:])
+}
+
+// splitAddressList splits newline or comma separated addresses, ignoring separators inside index keys.
+func splitAddressList(input string) []string {
+	var addresses []string
+	depth, start := 0, 0
+	flush := func(end int) {
+		if address := strings.TrimSpace(input[start:end]); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	for i, r := range input {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',', '\n':
+			if depth == 0 {
+				flush(i)
+				start = i + 1
+			}
+		}
+	}
+	flush(len(input))
+	return addresses
+}
+
+func renderMovedBlocks(blocks []MovedBlock) string {
+	var builder strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(&builder, "# %s\nmoved {\n  from = %s\n  to   = %s\n}\n", block.Reason, block.From, block.To)
+	}
+	return builder.String()
+}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMovedBlocks(t *testing.T) {
+	tests := []struct {
+		name          string
+		old           []string
+		new           []string
+		moduleMoves   map[string]string
+		expected      []MovedBlock
+		unmatchedOld  []string
+		unmatchedNew  []string
+		noMoveNeeded  []string
+		unchangedSize int
+	}{
+		{
+			name:          "no changes",
+			old:           []string{"aws_vpc.main", "aws_subnet.a"},
+			new:           []string{"aws_subnet.a", "aws_vpc.main"},
+			expected:      []MovedBlock{},
+			unmatchedOld:  []string{},
+			unmatchedNew:  []string{},
+			unchangedSize: 2,
+		},
+		{
+			name: "explicit module move",
+			old:  []string{"module.vpc.aws_vpc.this", "module.vpc.aws_subnet.private[0]"},
+			new:  []string{"module.network.aws_vpc.this", "module.network.aws_subnet.private[0]"},
+			moduleMoves: map[string]string{
+				"module.vpc": "module.network",
+			},
+			expected: []MovedBlock{
+				{From: "module.vpc", To: "module.network", Reason: "explicit module move"},
+			},
+			unmatchedOld: []string{},
+			unmatchedNew: []string{},
+		},
+		{
+			name: "resource moved into a module",
+			old:  []string{"aws_s3_bucket.logs"},
+			new:  []string{"module.storage.aws_s3_bucket.logs"},
+			expected: []MovedBlock{
+				{From: "aws_s3_bucket.logs", To: "module.storage.aws_s3_bucket.logs", Reason: "same resource in a different module"},
+			},
+			unmatchedOld: []string{},
+			unmatchedNew: []string{},
+		},
+		{
+			name: "renamed resource",
+			old:  []string{"aws_instance.web", "data.aws_ami.old"},
+			new:  []string{"aws_instance.frontend", "data.aws_ami.ubuntu"},
+			expected: []MovedBlock{
+				{From: "aws_instance.web", To: "aws_instance.frontend", Reason: "only resource of this type in the module"},
+			},
+			unmatchedOld: []string{},
+			unmatchedNew: []string{},
+			noMoveNeeded: []string{"data.aws_ami.old", "data.aws_ami.ubuntu"},
+		},
+		{
+			name:         "data sources moved into a module are not moved",
+			old:          []string{"data.aws_caller_identity.current"},
+			new:          []string{"module.account.data.aws_caller_identity.current"},
+			expected:     []MovedBlock{},
+			unmatchedOld: []string{},
+			unmatchedNew: []string{},
+			noMoveNeeded: []string{"data.aws_caller_identity.current", "module.account.data.aws_caller_identity.current"},
+		},
+		{
+			name:         "ambiguous renames are left unmatched",
+			old:          []string{"aws_instance.a", "aws_instance.b"},
+			new:          []string{"aws_instance.c", "aws_instance.d"},
+			expected:     []MovedBlock{},
+			unmatchedOld: []string{"aws_instance.a", "aws_instance.b"},
+			unmatchedNew: []string{"aws_instance.c", "aws_instance.d"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := generateMovedBlocks(tc.old, tc.new, tc.moduleMoves)
+			assert.Equal(t, tc.expected, result.MovedBlocks)
+			assert.Equal(t, tc.unmatchedOld, result.UnmatchedOld)
+			assert.Equal(t, tc.unmatchedNew, result.UnmatchedNew)
+			assert.ElementsMatch(t, tc.noMoveNeeded, result.NoMoveNeeded)
+			assert.Equal(t, tc.unchangedSize, result.UnchangedCount)
+		})
+	}
+}
+
+func TestGenerateMovedBlocksHCL(t *testing.T) {
+	result := generateMovedBlocks([]string{"aws_vpc.main"}, []string{"aws_vpc.this"}, nil)
+	require.Len(t, result.MovedBlocks, 1)
+	assert.Contains(t, result.HCL, "moved {\n  from = aws_vpc.main\n  to   = aws_vpc.this\n}")
+}
+
+func TestSplitAddressList(t *testing.T) {
+	input := "aws_instance.web[\"a,b\"]\n module.vpc.aws_vpc.this , \n\naws_s3_bucket.logs"
+	assert.Equal(t, []string{`aws_instance.web["a,b"]`, "module.vpc.aws_vpc.this", "aws_s3_bucket.logs"}, splitAddressList(input))
+}
+
+func TestSplitModulePath(t *testing.T) {
+	module, resource := splitModulePath(`module.a.module.b["x.y"].aws_s3_bucket.this`)
+	assert.Equal(t, `module.a.module.b["x.y"]`, module)
+	assert.Equal(t, "aws_s3_bucket.this", resource)
+}
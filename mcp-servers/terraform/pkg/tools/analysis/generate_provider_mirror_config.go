@@ -0,0 +1,343 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMirrorPlatforms = "linux_amd64"
+	defaultMirrorURL       = "https://terraform-mirror.example.com/providers/"
+	defaultMirrorPath      = "/usr/share/terraform/providers"
+	// mirrorDirectory is the directory the terraform providers mirror commands write to
+	mirrorDirectory = "terraform-providers"
+)
+
+// mirrorPlatform matches a Terraform platform, e.g. linux_amd64 or darwin_arm64
+var mirrorPlatform = regexp.MustCompile(`^[a-z0-9]+_[a-z0-9]+$`)
+
+// mirroredProvider is a provider to mirror with its versions, exact versions or constraints, the newest first
+type mirroredProvider struct {
+	Address  string
+	Versions []string
+}
+
+// GenerateProviderMirrorConfig creates a tool that produces the commands and CLI configuration to populate and use an internal provider mirror.
+func GenerateProviderMirrorConfig(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("generate_provider_mirror_config",
+			mcp.WithDescription(`Produces what is needed to run Terraform without access to the public registry: the configurations and 'terraform providers mirror' commands that download the providers into a mirror directory on a connected machine, and the 'provider_installation' CLI configuration that makes Terraform install them from the internal network or filesystem mirror.
+Provide either the Terraform 'configuration', optionally with its '.terraform.lock.hcl' in 'lock_file' to mirror the exact locked versions, or 'terraform_org_name' to mirror every provider version used by the workspaces of an HCP Terraform organization, read from its Explorer (requires a valid TFE_TOKEN), optionally narrowed with 'workspace_names'.`),
+			mcp.WithTitleAnnotation("Generate the configuration of an internal provider mirror"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("configuration",
+				mcp.Description("The Terraform configuration whose providers are mirrored, i.e. the content of its .tf files, which may be concatenated"),
+			),
+			mcp.WithString("lock_file",
+				mcp.Description("The content of the .terraform.lock.hcl file of the configuration"),
+			),
+			mcp.WithString("terraform_org_name",
+				mcp.Description("The HCP Terraform organization whose providers are mirrored"),
+			),
+			mcp.WithString("workspace_names",
+				mcp.Description("Comma-separated list of the workspaces of the organization whose providers are mirrored (default: all)"),
+			),
+			mcp.WithString("platforms",
+				mcp.Description("Comma-separated list of the platforms to mirror, e.g. 'linux_amd64, darwin_arm64'"),
+				mcp.DefaultString(defaultMirrorPlatforms),
+			),
+			mcp.WithString("mirror_type",
+				mcp.Description("How Terraform reads the mirror: 'network' from an HTTPS server, or 'filesystem' from a local directory"),
+				mcp.Enum("network", "filesystem"),
+				mcp.DefaultString("network"),
+			),
+			mcp.WithString("mirror_url",
+				mcp.Description("The HTTPS URL the mirror directory is served from, for a network mirror"),
+				mcp.DefaultString(defaultMirrorURL),
+			),
+			mcp.WithString("mirror_path",
+				mcp.Description("The directory the mirror is copied to on the machines running Terraform, for a filesystem mirror"),
+				mcp.DefaultString(defaultMirrorPath),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return generateProviderMirrorConfigHandler(ctx, request, logger)
+		},
+	}
+}
+
+func generateProviderMirrorConfigHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName := strings.TrimSpace(request.GetString("terraform_org_name", ""))
+	configuration := request.GetString("configuration", "")
+	lockFile := request.GetString("lock_file", "")
+	if (terraformOrgName == "") == (configuration == "" && lockFile == "") {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: either 'terraform_org_name' or 'configuration' and/or 'lock_file' must be provided", nil)
+	}
+
+	var platforms []string
+	for _, platform := range strings.Split(request.GetString("platforms", defaultMirrorPlatforms), ",") {
+		if platform = strings.ToLower(strings.TrimSpace(platform)); platform == "" || slices.Contains(platforms, platform) {
+			continue
+		}
+		if !mirrorPlatform.MatchString(platform) {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "reading platforms", fmt.Errorf("invalid platform %q, expected e.g. 'linux_amd64'", platform))
+		}
+		platforms = append(platforms, platform)
+	}
+	if len(platforms) == 0 {
+		platforms = []string{defaultMirrorPlatforms}
+	}
+
+	mirrorType := strings.TrimSpace(request.GetString("mirror_type", "network"))
+	location := strings.TrimSpace(request.GetString("mirror_path", defaultMirrorPath))
+	switch mirrorType {
+	case "network":
+		location = strings.TrimSpace(request.GetString("mirror_url", defaultMirrorURL))
+		parsed, err := url.Parse(location)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "reading mirror_url", fmt.Errorf("network mirrors are only supported over HTTPS, got %q", location))
+		}
+		if !strings.HasSuffix(location, "/") {
+			location += "/"
+		}
+	case "filesystem":
+		if location == "" {
+			location = defaultMirrorPath
+		}
+	default:
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("invalid mirror_type %q: must be 'network' or 'filesystem'", mirrorType), nil)
+	}
+
+	var providers []mirroredProvider
+	if terraformOrgName != "" {
+		tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+		}
+		rows, truncated, err := readExplorerInventory(ctx, tfeClient, terraformOrgName, "providers")
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading the providers of the organization (the Explorer is only available in HCP Terraform, submit the configuration instead on Terraform Enterprise)", err)
+		}
+		if truncated {
+			logger.Warnf("Only the first %d provider versions of organization %s are mirrored", maxInventoryPages*inventoryPageSize, terraformOrgName)
+		}
+		providers = explorerMirrorProviders(rows, splitNames(request.GetString("workspace_names", "")))
+	} else {
+		required, _, err := parseConfigurationDependencies(configuration, lockFile)
+		if err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing configuration", err)
+		}
+		providers = configurationMirrorProviders(required)
+	}
+	if len(providers) == 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "finding providers to mirror", fmt.Errorf("no provider installed from a registry was found"))
+	}
+
+	return mcp.NewToolResultText(renderMirrorPlan(providers, platforms, mirrorType, location)), nil
+}
+
+// splitNames splits a comma-separated list, dropping empty entries
+func splitNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// configurationMirrorProviders mirrors the locked version of each provider of a configuration, or the versions
+// matching its constraint when it is not locked
+func configurationMirrorProviders(required []configProvider) []mirroredProvider {
+	var providers []mirroredProvider
+	for _, provider := range required {
+		if builtinProvider(provider.Address) {
+			continue
+		}
+		mirrored := mirroredProvider{Address: provider.Address}
+		switch {
+		case provider.Locked != "":
+			mirrored.Versions = []string{provider.Locked}
+		case provider.Constraint != "":
+			mirrored.Versions = []string{provider.Constraint}
+		default:
+			mirrored.Versions = []string{""}
+		}
+		providers = append(providers, mirrored)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Address < providers[j].Address })
+	return providers
+}
+
+// explorerMirrorProviders groups the provider versions of the Explorer rows by provider, keeping the rows of the
+// given workspaces when there are some
+func explorerMirrorProviders(rows []explorerInventoryRow, workspaces []string) []mirroredProvider {
+	versions := make(map[string][]string)
+	for _, row := range rows {
+		address := normalizeProviderAddress(row.Source)
+		if builtinProvider(address) || !rowUsedBy(row, workspaces) {
+			continue
+		}
+		if !slices.Contains(versions[address], row.Version) {
+			versions[address] = append(versions[address], row.Version)
+		}
+	}
+
+	providers := make([]mirroredProvider, 0, len(versions))
+	for address, providerVersions := range versions {
+		sort.Slice(providerVersions, func(i, j int) bool { return compareModuleVersions(providerVersions[i], providerVersions[j]) > 0 })
+		providers = append(providers, mirroredProvider{Address: address, Versions: providerVersions})
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Address < providers[j].Address })
+	return providers
+}
+
+// rowUsedBy reports whether one of the workspaces uses the Explorer row, or true without workspaces
+func rowUsedBy(row explorerInventoryRow, workspaces []string) bool {
+	if len(workspaces) == 0 {
+		return true
+	}
+	for _, workspace := range strings.Split(row.Workspaces, ",") {
+		if slices.Contains(workspaces, strings.TrimSpace(workspace)) {
+			return true
+		}
+	}
+	return false
+}
+
+// builtinProvider reports whether a provider is built into Terraform and cannot be mirrored
+func builtinProvider(address string) bool {
+	return strings.HasPrefix(address, "terraform.io/builtin/")
+}
+
+// mirrorConfigurations returns the required_providers blocks of the configurations to run terraform providers
+// mirror in. A configuration can only require one version of a provider, so the nth configuration requires the
+// nth version of each provider.
+func mirrorConfigurations(providers []mirroredProvider) []string {
+	localNames := make(map[string]string)
+	taken := make(map[string]int)
+	for _, provider := range providers {
+		taken[provider.Address[strings.LastIndex(provider.Address, "/")+1:]]++
+	}
+	for _, provider := range providers {
+		parts := strings.Split(provider.Address, "/")
+		name := parts[len(parts)-1]
+		if taken[name] > 1 {
+			name = parts[len(parts)-2] + "-" + name
+		}
+		localNames[provider.Address] = name
+	}
+
+	var configurations []string
+	for i := 0; ; i++ {
+		var builder strings.Builder
+		for _, provider := range providers {
+			if i >= len(provider.Versions) {
+				continue
+			}
+			fmt.Fprintf(&builder, "    %s = {\n      source  = %q\n", localNames[provider.Address], provider.Address)
+			if provider.Versions[i] != "" {
+				fmt.Fprintf(&builder, "      version = %q\n", provider.Versions[i])
+			}
+			builder.WriteString("    }\n")
+		}
+		if builder.Len() == 0 {
+			return configurations
+		}
+		configurations = append(configurations, "terraform {\n  required_providers {\n"+builder.String()+"  }\n}\n")
+	}
+}
+
+// providerInstallation renders the CLI configuration installing the providers from the mirror, and from their
+// registry for every other provider
+func providerInstallation(providers []mirroredProvider, mirrorType string, location string) string {
+	addresses := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		addresses = append(addresses, fmt.Sprintf("%q", provider.Address))
+	}
+	include := strings.Join(addresses, ",\n      ")
+
+	var builder strings.Builder
+	builder.WriteString("provider_installation {\n")
+	if mirrorType == "network" {
+		fmt.Fprintf(&builder, "  network_mirror {\n    url     = %q\n", location)
+	} else {
+		fmt.Fprintf(&builder, "  filesystem_mirror {\n    path    = %q\n", location)
+	}
+	fmt.Fprintf(&builder, "    include = [\n      %s,\n    ]\n  }\n", include)
+	fmt.Fprintf(&builder, "  direct {\n    exclude = [\n      %s,\n    ]\n  }\n}\n", include)
+	return builder.String()
+}
+
+// renderMirrorPlan renders the configurations, commands and CLI configuration of the mirror as markdown
+func renderMirrorPlan(providers []mirroredProvider, platforms []string, mirrorType string, location string) string {
+	var builder strings.Builder
+	builder.WriteString("# Provider mirror\n\n")
+
+	builder.WriteString("## Providers\n\n| Provider | Versions |\n|---|---|\n")
+	for _, provider := range providers {
+		versions := make([]string, 0, len(provider.Versions))
+		for _, version := range provider.Versions {
+			if version == "" {
+				version = "latest"
+			}
+			versions = append(versions, "`"+version+"`")
+		}
+		fmt.Fprintf(&builder, "| %s | %s |\n", provider.Address, strings.Join(versions, ", "))
+	}
+
+	platformFlags := make([]string, 0, len(platforms))
+	for _, platform := range platforms {
+		platformFlags = append(platformFlags, "-platform="+platform)
+	}
+	flags := strings.Join(platformFlags, " ")
+
+	configurations := mirrorConfigurations(providers)
+	builder.WriteString("\n## Mirror configurations\n\n")
+	if len(configurations) > 1 {
+		builder.WriteString("A configuration can only require one version of each provider, so the versions are split across several configurations.\n\n")
+	}
+	for i, configuration := range configurations {
+		fmt.Fprintf(&builder, "`mirror-%d/main.tf`:\n\n```hcl\n%s```\n\n", i+1, configuration)
+	}
+
+	builder.WriteString("## Populating the mirror\n\nOn a machine with access to the registries, in the directory of the configurations:\n\n```shell\n")
+	for i := range configurations {
+		fmt.Fprintf(&builder, "(cd mirror-%d && terraform providers mirror %s ../%s)\n", i+1, flags, mirrorDirectory)
+	}
+	builder.WriteString("```\n\n")
+	if mirrorType == "network" {
+		fmt.Fprintf(&builder, "Publish the content of `%s` on an HTTPS server with a certificate trusted by the machines running Terraform, so that it is served at `%s`. The directory already has the JSON index files of the provider network mirror protocol.\n\n", mirrorDirectory, location)
+	} else {
+		fmt.Fprintf(&builder, "Copy the content of `%s` to `%s` on the machines running Terraform.\n\n", mirrorDirectory, location)
+	}
+
+	builder.WriteString("## CLI configuration\n\nAdd this block to the CLI configuration of the machines running Terraform, i.e. `~/.terraformrc`, `%APPDATA%/terraform.rc` or the file `TF_CLI_CONFIG_FILE` points at. The mirrored providers are only installed from the mirror, the other providers from their registry:\n\n```hcl\n")
+	builder.WriteString(providerInstallation(providers, mirrorType, location))
+	builder.WriteString("```\n\n")
+
+	builder.WriteString("## Lock files\n\n")
+	if mirrorType == "network" {
+		fmt.Fprintf(&builder, "The dependency lock file of each configuration must list the checksums of every platform it runs on. Update them from the mirror in each configuration:\n\n```shell\nterraform providers lock -net-mirror=%s %s\n```\n", location, flags)
+	} else {
+		fmt.Fprintf(&builder, "The dependency lock file of each configuration must list the checksums of every platform it runs on. Update them from the mirror in each configuration:\n\n```shell\nterraform providers lock -fs-mirror=%s %s\n```\n", location, flags)
+	}
+	return builder.String()
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplorerMirrorProviders(t *testing.T) {
+	rows := []explorerInventoryRow{
+		{Source: "hashicorp/aws", Version: "5.31.0", Workspaces: "app, network"},
+		{Source: "registry.terraform.io/hashicorp/aws", Version: "5.40.0", Workspaces: "edge"},
+		{Source: "hashicorp/aws", Version: "4.67.0", Workspaces: "legacy"},
+		{Source: "terraform.io/builtin/terraform", Version: "", Workspaces: "app"},
+		{Source: "acme/aws", Version: "1.0.0", Workspaces: "app"},
+	}
+
+	providers := explorerMirrorProviders(rows, nil)
+	assert.Equal(t, []mirroredProvider{
+		{Address: "registry.terraform.io/acme/aws", Versions: []string{"1.0.0"}},
+		{Address: "registry.terraform.io/hashicorp/aws", Versions: []string{"5.40.0", "5.31.0", "4.67.0"}},
+	}, providers)
+
+	assert.Equal(t, []mirroredProvider{
+		{Address: "registry.terraform.io/hashicorp/aws", Versions: []string{"5.31.0", "4.67.0"}},
+	}, explorerMirrorProviders(rows, []string{"network", "legacy"}))
+
+	// Each configuration requires one version of each provider, with local names that do not collide
+	configurations := mirrorConfigurations(providers)
+	require.Len(t, configurations, 3)
+	assert.Contains(t, configurations[0], `acme-aws = {`)
+	assert.Contains(t, configurations[0], `version = "5.40.0"`)
+	assert.Contains(t, configurations[1], `version = "5.31.0"`)
+	assert.NotContains(t, configurations[1], "acme")
+	assert.Contains(t, configurations[2], `version = "4.67.0"`)
+}
+
+func TestGenerateProviderMirrorConfig(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := GenerateProviderMirrorConfig(logger)
+	assert.Equal(t, "generate_provider_mirror_config", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "generate_provider_mirror_config", Arguments: arguments}}
+	}
+
+	t.Run("mirrors the locked versions of a configuration", func(t *testing.T) {
+		result, err := generateProviderMirrorConfigHandler(t.Context(), request(map[string]any{
+			"configuration": testInventoryConfiguration,
+			"lock_file":     testInventoryLockFile,
+			"platforms":     "linux_amd64, darwin_arm64",
+			"mirror_url":    "https://mirror.corp.example.com/terraform",
+		}), logger)
+		require.NoError(t, err)
+
+		plan := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, plan, "| registry.terraform.io/hashicorp/aws | `5.31.0` |")
+		assert.Contains(t, plan, "(cd mirror-1 && terraform providers mirror -platform=linux_amd64 -platform=darwin_arm64 ../terraform-providers)")
+		assert.Contains(t, plan, `url     = "https://mirror.corp.example.com/terraform/"`)
+		assert.Contains(t, plan, "direct {\n    exclude = [\n      \"registry.terraform.io/hashicorp/aws\",")
+		assert.Contains(t, plan, "terraform providers lock -net-mirror=https://mirror.corp.example.com/terraform/ -platform=linux_amd64 -platform=darwin_arm64")
+	})
+
+	t.Run("mirrors the providers of an organization to a filesystem mirror", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Handle("GET", "/organizations/acme/explorer", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = w.Write([]byte(`{"data": [
+				{"attributes": {"name": "aws", "source": "hashicorp/aws", "version": "5.31.0", "workspace-count": 1, "workspaces": "app"}},
+				{"attributes": {"name": "aws", "source": "hashicorp/aws", "version": "4.67.0", "workspace-count": 1, "workspaces": "legacy"}}
+			], "meta": {"pagination": {"current-page": 1}}}`))
+		})
+
+		result, err := generateProviderMirrorConfigHandler(fake.Context(t), request(map[string]any{
+			"terraform_org_name": "acme",
+			"mirror_type":        "filesystem",
+		}), logger)
+		require.NoError(t, err)
+
+		plan := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, plan, "`mirror-2/main.tf`")
+		assert.Contains(t, plan, `path    = "/usr/share/terraform/providers"`)
+		assert.Contains(t, plan, "terraform providers lock -fs-mirror=/usr/share/terraform/providers -platform=linux_amd64")
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		for name, arguments := range map[string]map[string]any{
+			"no source":        {},
+			"both sources":     {"terraform_org_name": "acme", "configuration": testInventoryConfiguration},
+			"invalid platform": {"configuration": testInventoryConfiguration, "platforms": "linux/amd64"},
+			"plain http":       {"configuration": testInventoryConfiguration, "mirror_url": "http://mirror.internal/"},
+		} {
+			_, err := generateProviderMirrorConfigHandler(t.Context(), request(arguments), logger)
+			assert.Equal(t, utils.ErrorCodeInvalidInput, utils.ErrorCodeOf(err), name)
+		}
+	})
+}
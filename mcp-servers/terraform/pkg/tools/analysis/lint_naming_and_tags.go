@@ -0,0 +1,411 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultNamePattern is the snake_case convention of the Terraform style guide
+const defaultNamePattern = `^[a-z][a-z0-9_]*$`
+
+// NamingConventions are the conventions checked by the lint_naming_and_tags tool, read from the
+// 'conventions' argument, the MCP_NAMING_CONVENTIONS_FILE file or the defaults. Names and tags are matched
+// with regular expressions, an empty pattern disabling the check.
+type NamingConventions struct {
+	ResourceName   string            `json:"resource_name"`
+	ResourceTypes  map[string]string `json:"resource_types,omitempty"`
+	DataSourceName string            `json:"data_source_name"`
+	VariableName   string            `json:"variable_name"`
+	OutputName     string            `json:"output_name"`
+	ModuleName     string            `json:"module_name"`
+	// RequiredTags must be set on every resource declaring one of the TagAttributes, and on the resources
+	// whose type matches one of the TaggedResourceTypes patterns
+	RequiredTags        []string          `json:"required_tags,omitempty"`
+	TaggedResourceTypes []string          `json:"tagged_resource_types,omitempty"`
+	TagAttributes       []string          `json:"tag_attributes,omitempty"`
+	TagKey              string            `json:"tag_key,omitempty"`
+	TagValues           map[string]string `json:"tag_values,omitempty"`
+
+	patterns map[string]*regexp.Regexp
+}
+
+// NamingLintReport is the result of the lint_naming_and_tags tool
+type NamingLintReport struct {
+	Conventions   string            `json:"conventions"`
+	BlocksChecked int               `json:"blocks_checked"`
+	Violations    []NamingViolation `json:"violations"`
+	// Unchecked lists the resources whose tags are not literals, so that required tags could not be checked
+	Unchecked []string `json:"unchecked,omitempty"`
+}
+
+// NamingViolation is a name or a tag breaking a convention, with a fix when one can be suggested
+type NamingViolation struct {
+	Rule       string `json:"rule"`
+	Address    string `json:"address"`
+	Location   string `json:"location"`
+	Value      string `json:"value,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// LintNamingAndTags creates a tool that checks the names and tags of a configuration against the naming conventions of the organization.
+func LintNamingAndTags(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("lint_naming_and_tags",
+			mcp.WithDescription(`Checks the names of the resources, data sources, variables, outputs and modules of a Terraform configuration, and the tags of its resources, against naming and tagging conventions: regular expressions for names, tag keys and tag values, and the tags every resource must carry.
+The conventions are read from the 'conventions' argument, or from the JSON file MCP_NAMING_CONVENTIONS_FILE points at, and otherwise default to snake_case names without tag requirements. Tags set by the default_tags of the aws provider count as set on aws resources.
+Returns each violation with its address, location and a suggested fix when one matches the convention.`),
+			mcp.WithTitleAnnotation("Lint the naming and tagging of a configuration"),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("configuration",
+				mcp.Required(),
+				mcp.Description("The Terraform configuration to lint, i.e. the content of its .tf files, which may be concatenated"),
+			),
+			mcp.WithString("conventions",
+				mcp.Description(`The conventions as JSON, overriding the configured ones, e.g. {"resource_name": "^[a-z][a-z0-9_]*$", "required_tags": ["owner", "environment"], "tag_key": "^[a-z][a-z0-9-]*$", "tag_values": {"environment": "^(dev|staging|prod)$"}}`),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return lintNamingAndTagsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func lintNamingAndTagsHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	configuration, err := request.RequireString("configuration")
+	if err != nil || strings.TrimSpace(configuration) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: configuration is required", err)
+	}
+
+	conventions, source, err := loadNamingConventions(request.GetString("conventions", ""))
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "loading naming conventions", err)
+	}
+	body, err := parseHCLBody(configuration, "main.tf")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing configuration", err)
+	}
+
+	report := lintNamingAndTags(body, conventions)
+	report.Conventions = source
+	resultJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling lint report", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// loadNamingConventions reads the conventions of the argument, of MCP_NAMING_CONVENTIONS_FILE or the
+// defaults, and describes where they come from
+func loadNamingConventions(argument string) (*NamingConventions, string, error) {
+	raw, source := []byte(strings.TrimSpace(argument)), "argument"
+	if len(raw) == 0 {
+		path := strings.TrimSpace(os.Getenv(client.NamingConventionsFile))
+		if path == "" {
+			return defaultNamingConventions(), "default", nil
+		}
+		var err error
+		if raw, err = os.ReadFile(path); err != nil {
+			return nil, "", fmt.Errorf("reading %s: %w", client.NamingConventionsFile, err)
+		}
+		source = path
+	}
+	conventions, err := parseNamingConventions(raw)
+	return conventions, source, err
+}
+
+func defaultNamingConventions() *NamingConventions {
+	conventions, _ := parseNamingConventions([]byte("{}"))
+	return conventions
+}
+
+// parseNamingConventions parses JSON conventions, the names left out defaulting to snake_case, and compiles
+// their patterns
+func parseNamingConventions(raw []byte) (*NamingConventions, error) {
+	conventions := &NamingConventions{
+		ResourceName:   defaultNamePattern,
+		DataSourceName: defaultNamePattern,
+		VariableName:   defaultNamePattern,
+		OutputName:     defaultNamePattern,
+		ModuleName:     defaultNamePattern,
+		TagAttributes:  []string{"tags", "labels"},
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(conventions); err != nil {
+		return nil, fmt.Errorf("parsing naming conventions: %w", err)
+	}
+
+	patterns := map[string]string{
+		"resource_name":    conventions.ResourceName,
+		"data_source_name": conventions.DataSourceName,
+		"variable_name":    conventions.VariableName,
+		"output_name":      conventions.OutputName,
+		"module_name":      conventions.ModuleName,
+		"tag_key":          conventions.TagKey,
+	}
+	for resourceType, pattern := range conventions.ResourceTypes {
+		patterns["resource_types."+resourceType] = pattern
+	}
+	for key, pattern := range conventions.TagValues {
+		patterns["tag_values."+key] = pattern
+	}
+	for i, pattern := range conventions.TaggedResourceTypes {
+		patterns[fmt.Sprintf("tagged_resource_types[%d]", i)] = pattern
+	}
+
+	conventions.patterns = map[string]*regexp.Regexp{}
+	for name, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", name, pattern, err)
+		}
+		conventions.patterns[name] = compiled
+	}
+	return conventions, nil
+}
+
+// lintNamingAndTags checks the blocks of a configuration against the conventions
+func lintNamingAndTags(body *hclsyntax.Body, conventions *NamingConventions) *NamingLintReport {
+	report := &NamingLintReport{Violations: []NamingViolation{}}
+	defaultTags, defaultTagsComplete := awsDefaultTags(body)
+
+	for _, block := range body.Blocks {
+		location := fmt.Sprintf("%s:%d", block.DefRange().Filename, block.DefRange().Start.Line)
+		switch {
+		case block.Type == "resource" && len(block.Labels) == 2:
+			resourceType, name := block.Labels[0], block.Labels[1]
+			address := resourceType + "." + name
+			rule := "resource_name"
+			if _, ok := conventions.patterns["resource_types."+resourceType]; ok {
+				rule = "resource_types." + resourceType
+			}
+			report.checkName(conventions, rule, address, location, name)
+			if strings.HasPrefix(resourceType, "aws_") {
+				report.checkTags(conventions, block, address, location, defaultTags, defaultTagsComplete)
+			} else {
+				report.checkTags(conventions, block, address, location, nil, true)
+			}
+		case block.Type == "data" && len(block.Labels) == 2:
+			report.checkName(conventions, "data_source_name", "data."+block.Labels[0]+"."+block.Labels[1], location, block.Labels[1])
+		case block.Type == "variable" && len(block.Labels) == 1:
+			report.checkName(conventions, "variable_name", "var."+block.Labels[0], location, block.Labels[0])
+		case block.Type == "output" && len(block.Labels) == 1:
+			report.checkName(conventions, "output_name", "output."+block.Labels[0], location, block.Labels[0])
+		case block.Type == "module" && len(block.Labels) == 1:
+			report.checkName(conventions, "module_name", "module."+block.Labels[0], location, block.Labels[0])
+		default:
+			continue
+		}
+		report.BlocksChecked++
+	}
+	return report
+}
+
+func (report *NamingLintReport) checkName(conventions *NamingConventions, rule string, address string, location string, name string) {
+	pattern, ok := conventions.patterns[rule]
+	if !ok || pattern.MatchString(name) {
+		return
+	}
+	violation := NamingViolation{
+		Rule:     rule,
+		Address:  address,
+		Location: location,
+		Value:    name,
+		Message:  fmt.Sprintf("the name %q does not match %s", name, pattern),
+	}
+	if suggestion := suggestName(name, pattern); suggestion != "" {
+		violation.Suggestion = fmt.Sprintf("rename to %q", suggestion)
+	}
+	report.Violations = append(report.Violations, violation)
+}
+
+// checkTags checks the keys and values of the tags of a resource, and that it carries the required tags
+func (report *NamingLintReport) checkTags(conventions *NamingConventions, block *hclsyntax.Block, address string, location string, defaultTags map[string]string, defaultTagsComplete bool) {
+	var attribute *hclsyntax.Attribute
+	for _, name := range conventions.TagAttributes {
+		if attribute = block.Body.Attributes[name]; attribute != nil {
+			break
+		}
+	}
+	requiresTags := attribute != nil || conventions.taggedType(block.Labels[0])
+	if !requiresTags {
+		return
+	}
+
+	tags, complete := map[string]string{}, true
+	if attribute != nil {
+		tags, complete = tagKeys(attribute.Expr)
+		location = fmt.Sprintf("%s:%d", attribute.SrcRange.Filename, attribute.SrcRange.Start.Line)
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tagKeyPattern := conventions.patterns["tag_key"]
+	for _, key := range keys {
+		if tagKeyPattern != nil && !tagKeyPattern.MatchString(key) {
+			violation := NamingViolation{
+				Rule:     "tag_key",
+				Address:  address,
+				Location: location,
+				Value:    key,
+				Message:  fmt.Sprintf("the tag key %q does not match %s", key, tagKeyPattern),
+			}
+			if suggestion := suggestName(key, tagKeyPattern); suggestion != "" {
+				violation.Suggestion = fmt.Sprintf("rename the tag to %q", suggestion)
+			}
+			report.Violations = append(report.Violations, violation)
+		}
+		if valuePattern := conventions.patterns["tag_values."+key]; valuePattern != nil && tags[key] != "" && !valuePattern.MatchString(tags[key]) {
+			report.Violations = append(report.Violations, NamingViolation{
+				Rule:     "tag_values." + key,
+				Address:  address,
+				Location: location,
+				Value:    tags[key],
+				Message:  fmt.Sprintf("the value %q of the tag %q does not match %s", tags[key], key, valuePattern),
+			})
+		}
+	}
+
+	if !complete || !defaultTagsComplete {
+		if len(conventions.RequiredTags) > 0 {
+			report.Unchecked = append(report.Unchecked, address)
+		}
+		return
+	}
+	for _, required := range conventions.RequiredTags {
+		if _, ok := tags[required]; ok {
+			continue
+		}
+		if _, ok := defaultTags[required]; ok {
+			continue
+		}
+		violation := NamingViolation{
+			Rule:       "required_tags",
+			Address:    address,
+			Location:   location,
+			Value:      required,
+			Message:    fmt.Sprintf("the required tag %q is not set", required),
+			Suggestion: fmt.Sprintf("add the tag %q", required),
+		}
+		for _, key := range keys {
+			if strings.EqualFold(key, required) || normalizedName(key, '_') == normalizedName(required, '_') {
+				violation.Suggestion = fmt.Sprintf("rename the tag %q to %q", key, required)
+			}
+		}
+		report.Violations = append(report.Violations, violation)
+	}
+}
+
+func (conventions *NamingConventions) taggedType(resourceType string) bool {
+	for i := range conventions.TaggedResourceTypes {
+		if pattern := conventions.patterns[fmt.Sprintf("tagged_resource_types[%d]", i)]; pattern != nil && pattern.MatchString(resourceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// awsDefaultTags returns the tags set by the default_tags blocks of the aws providers of a configuration
+func awsDefaultTags(body *hclsyntax.Body) (map[string]string, bool) {
+	tags, complete := map[string]string{}, true
+	for _, block := range body.Blocks {
+		if block.Type != "provider" || len(block.Labels) != 1 || block.Labels[0] != "aws" {
+			continue
+		}
+		for _, nested := range block.Body.Blocks {
+			if nested.Type != "default_tags" || nested.Body.Attributes["tags"] == nil {
+				continue
+			}
+			defaultTags, defaultComplete := tagKeys(nested.Body.Attributes["tags"].Expr)
+			for key, value := range defaultTags {
+				tags[key] = value
+			}
+			complete = complete && defaultComplete
+		}
+	}
+	return tags, complete
+}
+
+// tagKeys returns the literal keys of a tags expression with their literal values, and whether all the keys
+// are known: an object, or a merge of objects
+func tagKeys(expr hclsyntax.Expression) (map[string]string, bool) {
+	tags := map[string]string{}
+	switch expr := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		complete := true
+		for _, item := range expr.Items {
+			key := hcl.ExprAsKeyword(item.KeyExpr)
+			if key == "" {
+				key = literalString(item.KeyExpr)
+			}
+			if key == "" {
+				complete = false
+				continue
+			}
+			tags[key] = literalString(item.ValueExpr)
+		}
+		return tags, complete
+	case *hclsyntax.FunctionCallExpr:
+		if expr.Name != "merge" {
+			return tags, false
+		}
+		complete := true
+		for _, argument := range expr.Args {
+			argumentTags, argumentComplete := tagKeys(argument)
+			for key, value := range argumentTags {
+				tags[key] = value
+			}
+			complete = complete && argumentComplete
+		}
+		return tags, complete
+	}
+	return tags, false
+}
+
+var nameWordBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// normalizedName splits a name into lowercase words joined by the separator
+func normalizedName(name string, separator rune) string {
+	name = nameWordBoundary.ReplaceAllString(name, "${1} ${2}")
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	return strings.Join(words, string(separator))
+}
+
+// suggestName returns the snake_case, kebab-case or lowercase form of a name matching the pattern, or ""
+func suggestName(name string, pattern *regexp.Regexp) string {
+	for _, candidate := range []string{normalizedName(name, '_'), normalizedName(name, '-'), strings.ToLower(name)} {
+		if candidate != "" && candidate != name && pattern.MatchString(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLintConfiguration = `
+provider "aws" {
+  default_tags {
+    tags = {
+      owner = "platform"
+    }
+  }
+}
+
+variable "instanceType" {}
+
+resource "aws_s3_bucket" "LogsBucket" {
+  tags = {
+    Environment   = "production"
+    "cost-center" = "42"
+  }
+}
+
+resource "aws_instance" "web" {
+  tags = merge(local.common_tags, { environment = "prod" })
+}
+
+resource "google_storage_bucket" "assets" {
+  labels = {
+    environment = "dev"
+  }
+}
+
+resource "aws_vpc" "main" {}
+
+resource "aws_iam_role" "app" {}
+
+module "Network" {
+  source = "./network"
+}
+`
+
+const testLintConventions = `{
+  "module_name": "",
+  "required_tags": ["owner", "environment"],
+  "tagged_resource_types": ["^aws_vpc$"],
+  "tag_key": "^[a-z][a-z0-9_]*$",
+  "tag_values": {"environment": "^(dev|staging|prod)$"}
+}`
+
+func TestLintNamingAndTags(t *testing.T) {
+	body, err := parseHCLBody(testLintConfiguration, "main.tf")
+	require.NoError(t, err)
+
+	t.Run("checks snake_case names by default", func(t *testing.T) {
+		report := lintNamingAndTags(body, defaultNamingConventions())
+		assert.Equal(t, 7, report.BlocksChecked)
+		assert.Equal(t, []NamingViolation{
+			{Rule: "variable_name", Address: "var.instanceType", Location: "main.tf:10", Value: "instanceType", Message: `the name "instanceType" does not match ^[a-z][a-z0-9_]*$`, Suggestion: `rename to "instance_type"`},
+			{Rule: "resource_name", Address: "aws_s3_bucket.LogsBucket", Location: "main.tf:12", Value: "LogsBucket", Message: `the name "LogsBucket" does not match ^[a-z][a-z0-9_]*$`, Suggestion: `rename to "logs_bucket"`},
+			{Rule: "module_name", Address: "module.Network", Location: "main.tf:33", Value: "Network", Message: `the name "Network" does not match ^[a-z][a-z0-9_]*$`, Suggestion: `rename to "network"`},
+		}, report.Violations)
+		assert.Empty(t, report.Unchecked)
+	})
+
+	t.Run("checks the configured tags", func(t *testing.T) {
+		conventions, err := parseNamingConventions([]byte(testLintConventions))
+		require.NoError(t, err)
+		report := lintNamingAndTags(body, conventions)
+
+		violations := []string{}
+		for _, violation := range report.Violations {
+			violations = append(violations, violation.Address+" "+violation.Rule+" "+violation.Value+" "+violation.Suggestion)
+		}
+		assert.Equal(t, []string{
+			`var.instanceType variable_name instanceType rename to "instance_type"`,
+			`aws_s3_bucket.LogsBucket resource_name LogsBucket rename to "logs_bucket"`,
+			`aws_s3_bucket.LogsBucket tag_key Environment rename the tag to "environment"`,
+			`aws_s3_bucket.LogsBucket tag_key cost-center rename the tag to "cost_center"`,
+			`aws_s3_bucket.LogsBucket required_tags environment rename the tag "Environment" to "environment"`,
+			`google_storage_bucket.assets required_tags owner add the tag "owner"`,
+			`aws_vpc.main required_tags environment add the tag "environment"`,
+		}, violations)
+		assert.Equal(t, "main.tf:13", report.Violations[2].Location)
+		assert.Equal(t, []string{"aws_instance.web"}, report.Unchecked)
+	})
+
+	t.Run("checks tag values", func(t *testing.T) {
+		body, err := parseHCLBody(`resource "aws_instance" "web" { tags = { environment = "production" } }`, "main.tf")
+		require.NoError(t, err)
+		conventions, err := parseNamingConventions([]byte(testLintConventions))
+		require.NoError(t, err)
+
+		report := lintNamingAndTags(body, conventions)
+		require.Len(t, report.Violations, 2)
+		assert.Equal(t, "tag_values.environment", report.Violations[0].Rule)
+		assert.Equal(t, "production", report.Violations[0].Value)
+		assert.Equal(t, "required_tags", report.Violations[1].Rule)
+	})
+}
+
+func TestLoadNamingConventions(t *testing.T) {
+	t.Setenv(client.NamingConventionsFile, "")
+	conventions, source, err := loadNamingConventions("")
+	require.NoError(t, err)
+	assert.Equal(t, "default", source)
+	assert.Equal(t, defaultNamePattern, conventions.ResourceName)
+
+	path := filepath.Join(t.TempDir(), "conventions.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"required_tags": ["owner"]}`), 0o600))
+	t.Setenv(client.NamingConventionsFile, path)
+	conventions, source, err = loadNamingConventions("")
+	require.NoError(t, err)
+	assert.Equal(t, path, source)
+	assert.Equal(t, []string{"owner"}, conventions.RequiredTags)
+
+	conventions, source, err = loadNamingConventions(`{"resource_name": "^[a-z-]+$"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "argument", source)
+	assert.Empty(t, conventions.RequiredTags)
+
+	_, _, err = loadNamingConventions(`{"resource_name": "^[a-z"}`)
+	assert.ErrorContains(t, err, "invalid resource_name pattern")
+	_, _, err = loadNamingConventions(`{"resource_names": "^[a-z]+$"}`)
+	assert.ErrorContains(t, err, "unknown field")
+}
+
+func TestLintNamingAndTagsHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	t.Setenv(client.NamingConventionsFile, "")
+
+	tool := LintNamingAndTags(logger)
+	assert.Equal(t, "lint_naming_and_tags", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "lint_naming_and_tags", Arguments: arguments}}
+	}
+
+	result, err := lintNamingAndTagsHandler(t.Context(), request(map[string]any{"configuration": testLintConfiguration, "conventions": testLintConventions}), logger)
+	require.NoError(t, err)
+	var report NamingLintReport
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &report))
+	assert.Equal(t, "argument", report.Conventions)
+	assert.Len(t, report.Violations, 7)
+
+	for _, arguments := range []map[string]any{{}, {"configuration": `resource "x" {`}, {"configuration": testLintConfiguration, "conventions": "["}} {
+		_, err := lintNamingAndTagsHandler(t.Context(), request(arguments), logger)
+		assert.Error(t, err, arguments)
+	}
+}
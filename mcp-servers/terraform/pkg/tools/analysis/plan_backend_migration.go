@@ -0,0 +1,360 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sensitiveBackendAttributes are never echoed back in the migration plan.
+var sensitiveBackendAttributes = map[string]bool{
+	"access_key":                  true,
+	"secret_key":                  true,
+	"token":                       true,
+	"password":                    true,
+	"sas_token":                   true,
+	"client_secret":               true,
+	"client_certificate_password": true,
+	"credentials":                 true,
+	"access_token":                true,
+	"conn_str":                    true,
+	"encryption_key":              true,
+}
+
+// backendNotes explains how each backend stores non-default workspaces and what changes after the migration.
+var backendNotes = map[string]string{
+	"local":   "State is stored on disk (default `terraform.tfstate`, other workspaces under `terraform.tfstate.d/`). Keep a copy of these files until the migration is verified.",
+	"s3":      "Non-default workspaces are stored under `workspace_key_prefix` (default `env:`). The DynamoDB lock table is no longer needed once HCP Terraform manages locking.",
+	"gcs":     "Each workspace is stored as `<prefix>/<workspace>.tfstate`. Bucket object versioning can be kept as an additional backup.",
+	"azurerm": "Non-default workspaces are stored as `<key>env:<workspace>`. Blob lease locking is replaced by HCP Terraform workspace locking.",
+	"consul":  "Non-default workspaces are stored under `<path>-env:<workspace>`. Consul session locks are replaced by HCP Terraform workspace locking.",
+	"pg":      "Each workspace is a row in the `states` table of the configured schema. Advisory locks are replaced by HCP Terraform workspace locking.",
+	"http":    "Ensure the HTTP backend is reachable during `terraform init` so the existing state can be read for migration.",
+	"remote":  "The `remote` backend already targets HCP Terraform/Terraform Enterprise. Replacing it with a `cloud` block keeps the same workspaces; `prefix` maps to a tag or name strategy.",
+}
+
+// backendConfig is the backend type and attributes extracted from a submitted configuration.
+type backendConfig struct {
+	Type       string
+	Attributes map[string]string
+}
+
+// PlanBackendMigration creates a tool that produces a step-by-step plan for moving state to HCP Terraform or Terraform Enterprise.
+func PlanBackendMigration(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("plan_backend_migration",
+			mcp.WithDescription(`Inspects a Terraform backend configuration (for example a 'terraform { backend "s3" { ... } }' block) and produces the 'cloud' block and step-by-step commands to migrate state to HCP Terraform or Terraform Enterprise.
+Supports single workspace and multi-workspace naming strategies. Nothing is changed: the plan lists the 'create_workspace' calls that pre-create the target workspaces.
+Sensitive backend attributes are never included in the output.`),
+			mcp.WithTitleAnnotation("Plan a state migration to HCP Terraform or Terraform Enterprise"),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("backend_config",
+				mcp.Required(),
+				mcp.Description("The Terraform configuration containing the current backend block"),
+			),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization to migrate state into"),
+			),
+			mcp.WithString("workspace_name",
+				mcp.Description("The target workspace name, or the name prefix when several CLI workspaces are migrated (default: derived from the backend configuration)"),
+			),
+			mcp.WithString("cli_workspaces",
+				mcp.Description("Comma-separated list of the CLI workspaces that exist in the current backend (default: 'default')"),
+			),
+			mcp.WithString("naming_strategy",
+				mcp.Description("How target workspaces are selected: 'name' (single workspace) or 'tags' (one workspace per CLI workspace, selected by tag). Default: 'name' for one CLI workspace, 'tags' otherwise"),
+				mcp.Enum("name", "tags"),
+			),
+			mcp.WithString("project_id",
+				mcp.Description("Optional project ID to create the target workspaces in"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return planBackendMigrationHandler(ctx, request, logger)
+		},
+	}
+}
+
+func planBackendMigrationHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	backendConfigStr, err := request.RequireString("backend_config")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'backend_config' parameter is required", err)
+	}
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	workspaceName := strings.TrimSpace(request.GetString("workspace_name", ""))
+	projectID := strings.TrimSpace(request.GetString("project_id", ""))
+
+	cliWorkspaces := []string{}
+	for _, name := range strings.Split(request.GetString("cli_workspaces", "default"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cliWorkspaces = append(cliWorkspaces, name)
+		}
+	}
+	if len(cliWorkspaces) == 0 {
+		cliWorkspaces = []string{"default"}
+	}
+
+	strategy := strings.TrimSpace(request.GetString("naming_strategy", ""))
+	if strategy == "" {
+		strategy = "name"
+		if len(cliWorkspaces) > 1 {
+			strategy = "tags"
+		}
+	}
+	if strategy == "name" && len(cliWorkspaces) > 1 {
+		return nil, utils.LogAndReturnError(logger, "the 'name' naming strategy supports a single CLI workspace, use 'tags' to migrate several workspaces", nil)
+	}
+
+	backend, err := parseBackendConfig(backendConfigStr)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "parsing backend configuration", err)
+	}
+	if backend.Type == "cloud" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "the configuration already uses a 'cloud' block, no migration is required", nil)
+	}
+
+	if workspaceName == "" {
+		workspaceName = defaultWorkspaceName(backend)
+	}
+
+	hostname := ""
+	if address := utils.GetEnv(client.TerraformAddress, client.DefaultTerraformAddress); address != client.DefaultTerraformAddress {
+		if u, err := url.Parse(address); err == nil {
+			hostname = u.Host
+		}
+	}
+
+	targets := migrationTargets(strategy, workspaceName, cliWorkspaces)
+	plan := renderMigrationPlan(backend, terraformOrgName, hostname, projectID, strategy, workspaceName, targets)
+	return mcp.NewToolResultText(plan), nil
+}
+
+// parseBackendConfig extracts the backend block from a Terraform configuration snippet.
+// Both a full 'terraform { backend "x" {} }' block and a bare 'backend "x" {}' block are accepted.
+func parseBackendConfig(src string) (*backendConfig, error) {
+	file, diags := hclsyntax.ParseConfig([]byte(src), "backend.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, errors.New("unexpected configuration body")
+	}
+
+	blocks := body.Blocks
+	for _, block := range body.Blocks {
+		if block.Type == "terraform" {
+			blocks = block.Body.Blocks
+			break
+		}
+	}
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "backend":
+			if len(block.Labels) != 1 {
+				return nil, errors.New("backend block must have exactly one label")
+			}
+			return &backendConfig{Type: block.Labels[0], Attributes: backendAttributes(block.Body, src)}, nil
+		case "cloud":
+			return &backendConfig{Type: "cloud", Attributes: map[string]string{}}, nil
+		}
+	}
+	return nil, errors.New("no backend block found in the configuration")
+}
+
+// backendAttributes renders the attributes of a backend block, evaluating literals and falling back to the source text.
+func backendAttributes(body *hclsyntax.Body, src string) map[string]string {
+	attributes := make(map[string]string, len(body.Attributes))
+	for name, attr := range body.Attributes {
+		if sensitiveBackendAttributes[name] {
+			attributes[name] = "(sensitive)"
+			continue
+		}
+		value, diags := attr.Expr.Value(nil)
+		if !diags.HasErrors() && value.IsKnown() && !value.IsNull() && value.Type() == cty.String {
+			attributes[name] = value.AsString()
+			continue
+		}
+		rng := attr.Expr.Range()
+		attributes[name] = string(rng.SliceBytes([]byte(src)))
+	}
+	for _, block := range body.Blocks {
+		// The remote backend nests its workspace settings in a block.
+		if block.Type == "workspaces" {
+			for name, value := range backendAttributes(block.Body, src) {
+				attributes["workspaces."+name] = value
+			}
+		}
+	}
+	return attributes
+}
+
+// defaultWorkspaceName derives a workspace name from the backend settings that identify the state.
+func defaultWorkspaceName(backend *backendConfig) string {
+	candidates := []string{"workspaces.name", "workspaces.prefix", "key", "prefix", "path", "schema_name"}
+	for _, candidate := range candidates {
+		if value, ok := backend.Attributes[candidate]; ok && value != "" {
+			return sanitizeWorkspaceName(value)
+		}
+	}
+	return "migrated-state"
+}
+
+// sanitizeWorkspaceName converts a state key or path into a valid workspace name.
+func sanitizeWorkspaceName(value string) string {
+	value = strings.TrimSuffix(value, ".tfstate")
+	var builder strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('-')
+		}
+	}
+	name := strings.Trim(builder.String(), "-_")
+	if name == "" {
+		return "migrated-state"
+	}
+	return name
+}
+
+// migrationTarget maps a CLI workspace to the workspace it is migrated into.
+type migrationTarget struct {
+	CLIWorkspace string
+	Workspace    string
+}
+
+func migrationTargets(strategy, workspaceName string, cliWorkspaces []string) []migrationTarget {
+	if strategy == "name" {
+		return []migrationTarget{{CLIWorkspace: cliWorkspaces[0], Workspace: workspaceName}}
+	}
+	targets := make([]migrationTarget, 0, len(cliWorkspaces))
+	for _, cliWorkspace := range cliWorkspaces {
+		targets = append(targets, migrationTarget{
+			CLIWorkspace: cliWorkspace,
+			Workspace:    fmt.Sprintf("%s-%s", strings.TrimSuffix(workspaceName, "-"), cliWorkspace),
+		})
+	}
+	return targets
+}
+
+func migrationTag(workspaceName string) string {
+	return strings.ToLower(sanitizeWorkspaceName(workspaceName))
+}
+
+func renderMigrationPlan(backend *backendConfig, orgName, hostname, projectID, strategy, workspaceName string, targets []migrationTarget) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("# Backend migration plan: `%s` to HCP Terraform/Terraform Enterprise\n\n", backend.Type))
+
+	builder.WriteString("## Current backend\n\n")
+	names := make([]string, 0, len(backend.Attributes))
+	for name := range backend.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		builder.WriteString("No backend attributes set.\n")
+	}
+	for _, name := range names {
+		builder.WriteString(fmt.Sprintf("- `%s`: %s\n", name, backend.Attributes[name]))
+	}
+	if note, ok := backendNotes[backend.Type]; ok {
+		builder.WriteString(fmt.Sprintf("\n%s\n", note))
+	}
+
+	builder.WriteString("\n## Target workspaces\n\n")
+	builder.WriteString("| CLI workspace | Target workspace |\n|---|---|\n")
+	for _, target := range targets {
+		builder.WriteString(fmt.Sprintf("| %s | %s |\n", target.CLIWorkspace, target.Workspace))
+	}
+
+	builder.WriteString("\n## Replacement configuration\n\n```hcl\nterraform {\n  cloud {\n")
+	if hostname != "" {
+		builder.WriteString(fmt.Sprintf("    hostname     = %q\n", hostname))
+	}
+	builder.WriteString(fmt.Sprintf("    organization = %q\n\n    workspaces {\n", orgName))
+	if strategy == "tags" {
+		builder.WriteString(fmt.Sprintf("      tags = [%q]\n", migrationTag(workspaceName)))
+	} else {
+		builder.WriteString(fmt.Sprintf("      name = %q\n", workspaceName))
+	}
+	builder.WriteString("    }\n  }\n}\n```\n")
+
+	builder.WriteString("\n## Steps\n\n")
+	step := 1
+	writeStep := func(text string) {
+		builder.WriteString(fmt.Sprintf("%d. %s\n", step, text))
+		step++
+	}
+	writeStep("Ensure Terraform CLI 1.1 or later is installed, the `cloud` block is not supported by earlier versions.")
+	writeStep("Back up every CLI workspace state before changing the backend:\n   ```shell\n" + backupCommands(targets) + "   ```")
+	if hostname != "" {
+		writeStep(fmt.Sprintf("Authenticate the CLI: `terraform login %s`", hostname))
+	} else {
+		writeStep("Authenticate the CLI: `terraform login`")
+	}
+	writeStep("Optionally pre-create the target workspaces with the `create_workspace` tool, otherwise `terraform init` creates them:\n" + createWorkspaceCalls(orgName, projectID, strategy, workspaceName, targets))
+	writeStep(fmt.Sprintf("Replace the `backend \"%s\"` block with the `cloud` block above.", backend.Type))
+	if strategy == "tags" {
+		writeStep(fmt.Sprintf("Run `terraform init` and confirm the state migration. When prompted for a workspace naming pattern, enter `%s-*` so each CLI workspace maps to the target names above.", strings.TrimSuffix(workspaceName, "-")))
+	} else {
+		writeStep("Run `terraform init` and confirm the state migration when prompted.")
+	}
+	writeStep("Move any credentials or variables the configuration relied on locally into workspace variables or variable sets.")
+	writeStep("Run `terraform plan` in each workspace and confirm it reports no changes.")
+	writeStep(fmt.Sprintf("Once verified, retire the old `%s` state storage and remove the local backups.", backend.Type))
+
+	return builder.String()
+}
+
+func backupCommands(targets []migrationTarget) string {
+	var builder strings.Builder
+	for _, target := range targets {
+		builder.WriteString(fmt.Sprintf("   terraform workspace select %s && terraform state pull > backup-%s.tfstate\n", target.CLIWorkspace, target.CLIWorkspace))
+	}
+	return builder.String()
+}
+
+// createWorkspaceCalls lists the create_workspace arguments of each target workspace
+func createWorkspaceCalls(orgName, projectID, strategy, workspaceName string, targets []migrationTarget) string {
+	var builder strings.Builder
+	for _, target := range targets {
+		arguments := fmt.Sprintf("`terraform_org_name`: `%s`, `workspace_name`: `%s`", orgName, target.Workspace)
+		if strategy == "tags" {
+			arguments += fmt.Sprintf(", `tags`: `%s`", migrationTag(workspaceName))
+		}
+		if projectID != "" {
+			arguments += fmt.Sprintf(", `project_id`: `%s`", projectID)
+		}
+		builder.WriteString(fmt.Sprintf("   - %s\n", arguments))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackendConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		expectError  bool
+		expectedType string
+		expectedAttr map[string]string
+	}{
+		{
+			name: "s3 backend in terraform block",
+			config: `terraform {
+  required_version = ">= 1.5"
+  backend "s3" {
+    bucket     = "my-state"
+    key        = "network/prod.tfstate"
+    region     = "us-east-1"
+    secret_key = "shh"
+  }
+}`,
+			expectedType: "s3",
+			expectedAttr: map[string]string{
+				"bucket":     "my-state",
+				"key":        "network/prod.tfstate",
+				"region":     "us-east-1",
+				"secret_key": "(sensitive)",
+			},
+		},
+		{
+			name: "bare remote backend with workspaces block",
+			config: `backend "remote" {
+  organization = "acme"
+  workspaces {
+    prefix = "app-"
+  }
+}`,
+			expectedType: "remote",
+			expectedAttr: map[string]string{
+				"organization":      "acme",
+				"workspaces.prefix": "app-",
+			},
+		},
+		{
+			name:         "non literal expressions are kept as source",
+			config:       `backend "local" { path = var.state_path }`,
+			expectedType: "local",
+			expectedAttr: map[string]string{"path": "var.state_path"},
+		},
+		{
+			name:        "missing backend",
+			config:      `terraform { required_version = ">= 1.5" }`,
+			expectError: true,
+		},
+		{
+			name:        "invalid hcl",
+			config:      `terraform {`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			backend, err := parseBackendConfig(tc.config)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedType, backend.Type)
+			assert.Equal(t, tc.expectedAttr, backend.Attributes)
+		})
+	}
+}
+
+func TestDefaultWorkspaceName(t *testing.T) {
+	backend := &backendConfig{Type: "s3", Attributes: map[string]string{"key": "network/prod.tfstate"}}
+	assert.Equal(t, "network-prod", defaultWorkspaceName(backend))
+
+	backend = &backendConfig{Type: "local", Attributes: map[string]string{}}
+	assert.Equal(t, "migrated-state", defaultWorkspaceName(backend))
+}
+
+func TestRenderMigrationPlan(t *testing.T) {
+	backend := &backendConfig{Type: "s3", Attributes: map[string]string{"bucket": "my-state"}}
+
+	t.Run("single workspace", func(t *testing.T) {
+		targets := migrationTargets("name", "network", []string{"default"})
+		plan := renderMigrationPlan(backend, "acme", "", "", "name", "network", targets)
+		assert.Contains(t, plan, `organization = "acme"`)
+		assert.Contains(t, plan, `name = "network"`)
+		assert.Contains(t, plan, "`terraform login`")
+		assert.NotContains(t, plan, "hostname")
+		assert.Contains(t, plan, "   - `terraform_org_name`: `acme`, `workspace_name`: `network`\n")
+	})
+
+	t.Run("tagged workspaces on a custom host", func(t *testing.T) {
+		targets := migrationTargets("tags", "network", []string{"staging", "prod"})
+		require.Len(t, targets, 2)
+		assert.Equal(t, "network-prod", targets[1].Workspace)
+
+		plan := renderMigrationPlan(backend, "acme", "tfe.example.com", "prj-1", "tags", "network", targets)
+		assert.Contains(t, plan, `hostname     = "tfe.example.com"`)
+		assert.Contains(t, plan, `tags = ["network"]`)
+		assert.Contains(t, plan, "`network-*`")
+		assert.Contains(t, plan, "`workspace_name`: `network-staging`, `tags`: `network`, `project_id`: `prj-1`")
+	})
+}
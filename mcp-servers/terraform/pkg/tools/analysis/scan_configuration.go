@@ -0,0 +1,359 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Severities of a scan finding, from the least to the most severe
+var scanSeverities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// unknownValue stands for a value that is not known before apply, or not a literal in the configuration.
+// Rules never report a misconfiguration on an unknown value.
+type unknownValue struct{}
+
+// scanEvalContext evaluates the functions commonly used to build literal values, such as IAM policies with
+// jsonencode
+var scanEvalContext = &hcl.EvalContext{
+	Functions: map[string]function.Function{
+		"jsonencode": stdlib.JSONEncodeFunc,
+		"tolist":     stdlib.MakeToFunc(cty.List(cty.DynamicPseudoType)),
+		"toset":      stdlib.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
+	},
+}
+
+// scanResource is a resource, data source or provider block with its attribute values, nested blocks being
+// lists of objects as in the plan JSON
+type scanResource struct {
+	Address  string
+	Type     string
+	Location string
+	Values   map[string]any
+}
+
+// scanRule is a misconfiguration check of the embedded rule set
+type scanRule struct {
+	ID          string
+	Severity    string
+	Title       string
+	Remediation string
+	// Types lists the resource types the rule applies to, data sources being prefixed with "data.". A rule
+	// without types applies to every block.
+	Types []string
+	// ConfigurationOnly rules need the configuration to tell literals from references, and are skipped on plans
+	ConfigurationOnly bool
+	// Check reports whether the values of a resource are misconfigured
+	Check func(values map[string]any) bool
+}
+
+// ScanReport is the result of the scan_configuration tool
+type ScanReport struct {
+	Input            string         `json:"input"`
+	ResourcesScanned int            `json:"resources_scanned"`
+	RulesEvaluated   int            `json:"rules_evaluated"`
+	Summary          map[string]int `json:"summary"`
+	Findings         []ScanFinding  `json:"findings"`
+}
+
+// ScanFinding is a misconfiguration found by a rule on a resource
+type ScanFinding struct {
+	RuleID      string `json:"rule_id"`
+	Severity    string `json:"severity"`
+	Title       string `json:"title"`
+	Resource    string `json:"resource"`
+	Location    string `json:"location,omitempty"`
+	Remediation string `json:"remediation"`
+}
+
+// ScanConfiguration creates a tool that scans a configuration or a plan for security misconfigurations.
+func ScanConfiguration(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("scan_configuration",
+			mcp.WithDescription(fmt.Sprintf(`Scans a Terraform configuration or plan for security misconfigurations with the embedded rule set of the server, in the spirit of tfsec and trivy: public storage and databases, ingress open to the internet, missing encryption, IMDSv1, wildcard IAM policies or credentials in plain text, for the aws, azurerm and google providers.
+Provide either the 'configuration' (the content of its .tf files) or the 'plan_json' output of 'terraform show -json'. Values that are not literals in the configuration, or not known until apply in the plan, are not reported.
+Returns the findings with their rule ID, severity, resource address and a remediation hint. Nothing is sent outside the server. %d rules are available.`, len(scanRules))),
+			mcp.WithTitleAnnotation("Scan a configuration for security misconfigurations"),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("configuration",
+				mcp.Description("The Terraform configuration to scan, i.e. the content of its .tf files, which may be concatenated"),
+			),
+			mcp.WithString("plan_json",
+				mcp.Description("The JSON plan to scan, i.e. the output of 'terraform show -json <planfile>'"),
+			),
+			mcp.WithString("min_severity",
+				mcp.Description("The lowest severity to report"),
+				mcp.Enum(scanSeverities...),
+				mcp.DefaultString("LOW"),
+			),
+			mcp.WithString("skip_rules",
+				mcp.Description("A comma-separated list of rule IDs not to evaluate, e.g. 'aws-kms-auto-rotate-keys'"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return scanConfigurationHandler(ctx, request, logger)
+		},
+	}
+}
+
+func scanConfigurationHandler(_ context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	configuration := request.GetString("configuration", "")
+	planJSON := request.GetString("plan_json", "")
+	minSeverity := strings.ToUpper(strings.TrimSpace(request.GetString("min_severity", "LOW")))
+	if severityRank(minSeverity) < 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("invalid min_severity %q, expected one of %s", minSeverity, strings.Join(scanSeverities, ", ")), nil)
+	}
+	skipped := map[string]bool{}
+	for _, id := range strings.Split(request.GetString("skip_rules", ""), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			skipped[id] = true
+		}
+	}
+
+	var input string
+	var resources []scanResource
+	var err error
+	switch {
+	case configuration != "" && planJSON != "":
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "only one of 'configuration' and 'plan_json' can be provided", nil)
+	case configuration != "":
+		input = "configuration"
+		resources, err = configurationScanResources(configuration)
+	case planJSON != "":
+		input = "plan"
+		resources, err = planScanResources([]byte(planJSON))
+	default:
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: either 'configuration' or 'plan_json' must be provided", nil)
+	}
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing "+input, err)
+	}
+
+	report := scanResources(input, resources, minSeverity, skipped)
+	resultJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling scan report", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// scanResources evaluates the rules at or above a severity on the resources
+func scanResources(input string, resources []scanResource, minSeverity string, skipped map[string]bool) *ScanReport {
+	report := &ScanReport{Input: input, ResourcesScanned: len(resources), Summary: map[string]int{}, Findings: []ScanFinding{}}
+	for _, rule := range scanRules {
+		if skipped[rule.ID] || severityRank(rule.Severity) < severityRank(minSeverity) || (rule.ConfigurationOnly && input != "configuration") {
+			continue
+		}
+		report.RulesEvaluated++
+		for _, resource := range resources {
+			if !ruleApplies(rule, resource.Type) || !rule.Check(resource.Values) {
+				continue
+			}
+			report.Findings = append(report.Findings, ScanFinding{
+				RuleID:      rule.ID,
+				Severity:    rule.Severity,
+				Title:       rule.Title,
+				Resource:    resource.Address,
+				Location:    resource.Location,
+				Remediation: rule.Remediation,
+			})
+			report.Summary[rule.Severity]++
+		}
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Severity != b.Severity {
+			return severityRank(a.Severity) > severityRank(b.Severity)
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.RuleID < b.RuleID
+	})
+	return report
+}
+
+func severityRank(severity string) int {
+	for i, s := range scanSeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+func ruleApplies(rule scanRule, resourceType string) bool {
+	if len(rule.Types) == 0 {
+		return true
+	}
+	for _, t := range rule.Types {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// configurationScanResources reads the resource, data and provider blocks of a configuration
+func configurationScanResources(configuration string) ([]scanResource, error) {
+	body, err := parseHCLBody(configuration, "main.tf")
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []scanResource
+	for _, block := range body.Blocks {
+		resource := scanResource{
+			Location: fmt.Sprintf("%s:%d", block.DefRange().Filename, block.DefRange().Start.Line),
+			Values:   blockValues(block.Body),
+		}
+		switch {
+		case block.Type == "resource" && len(block.Labels) == 2:
+			resource.Type, resource.Address = block.Labels[0], block.Labels[0]+"."+block.Labels[1]
+		case block.Type == "data" && len(block.Labels) == 2:
+			resource.Type, resource.Address = "data."+block.Labels[0], "data."+block.Labels[0]+"."+block.Labels[1]
+		case block.Type == "provider" && len(block.Labels) == 1:
+			resource.Type, resource.Address = "provider."+block.Labels[0], "provider."+block.Labels[0]
+		default:
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// blockValues converts the literal attributes of a block to their JSON values, other expressions being
+// unknown, and its nested blocks to lists of objects
+func blockValues(body *hclsyntax.Body) map[string]any {
+	values := map[string]any{}
+	for name, attribute := range body.Attributes {
+		values[name] = expressionValue(attribute.Expr)
+	}
+	for _, block := range body.Blocks {
+		if block.Type == "dynamic" {
+			if len(block.Labels) == 1 {
+				values[block.Labels[0]] = unknownValue{}
+			}
+			continue
+		}
+		if _, unknown := values[block.Type].(unknownValue); unknown {
+			continue
+		}
+		nested, _ := values[block.Type].([]any)
+		values[block.Type] = append(nested, blockValues(block.Body))
+	}
+	return values
+}
+
+func expressionValue(expr hclsyntax.Expression) any {
+	value, diags := expr.Value(scanEvalContext)
+	if diags.HasErrors() || !value.IsWhollyKnown() {
+		return unknownValue{}
+	}
+	raw, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return unknownValue{}
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return unknownValue{}
+	}
+	return decoded
+}
+
+// terraformPlan is the subset of the JSON plan format used by the scan
+type terraformPlan struct {
+	FormatVersion   string `json:"format_version"`
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Mode    string `json:"mode"`
+		Type    string `json:"type"`
+		Change  struct {
+			Actions      []string       `json:"actions"`
+			Before       map[string]any `json:"before"`
+			After        map[string]any `json:"after"`
+			AfterUnknown any            `json:"after_unknown"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// planScanResources reads the values resources will have after the plan is applied, values known after
+// apply being unknown. Resources being destroyed are left out.
+func planScanResources(raw []byte) ([]scanResource, error) {
+	var plan terraformPlan
+	if err := json.Unmarshal(raw, &plan); err != nil {
+		return nil, fmt.Errorf("plan is not valid JSON: %w", err)
+	}
+	if plan.FormatVersion == "" {
+		return nil, fmt.Errorf("plan has no format_version, expected the output of 'terraform show -json <planfile>'")
+	}
+
+	var resources []scanResource
+	for _, change := range plan.ResourceChanges {
+		if change.Change.After == nil {
+			continue
+		}
+		resourceType := change.Type
+		if change.Mode == "data" {
+			resourceType = "data." + change.Type
+		}
+		values, _ := markUnknown(change.Change.After, change.Change.AfterUnknown).(map[string]any)
+		resources = append(resources, scanResource{Address: change.Address, Type: resourceType, Values: values})
+	}
+	return resources, nil
+}
+
+// markUnknown replaces the values flagged in the after_unknown structure of a change with unknownValue
+func markUnknown(value any, unknown any) any {
+	switch unknown := unknown.(type) {
+	case bool:
+		if unknown {
+			return unknownValue{}
+		}
+	case map[string]any:
+		object, ok := value.(map[string]any)
+		if !ok && value != nil {
+			return value
+		}
+		if object == nil {
+			object = map[string]any{}
+		}
+		for key, nested := range unknown {
+			if marked := markUnknown(object[key], nested); marked != nil {
+				object[key] = marked
+			}
+		}
+		if value == nil && len(object) == 0 {
+			return nil
+		}
+		return object
+	case []any:
+		list, _ := value.([]any)
+		for i, nested := range unknown {
+			if i < len(list) {
+				list[i] = markUnknown(list[i], nested)
+			}
+		}
+		return list
+	}
+	return value
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScanConfiguration = `
+provider "aws" {
+  region     = "eu-west-1"
+  secret_key = "wJalrXUtnFEMI"
+}
+
+resource "aws_security_group" "web" {
+  ingress {
+    from_port   = 443
+    to_port     = 443
+    protocol    = "tcp"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+resource "aws_security_group_rule" "ssh" {
+  type        = "ingress"
+  cidr_blocks = [var.office_cidr]
+}
+
+resource "aws_db_instance" "main" {
+  publicly_accessible = true
+  storage_encrypted   = true
+  password            = random_password.db.result
+}
+
+resource "aws_instance" "app" {
+  metadata_options {
+    http_tokens = "required"
+  }
+}
+
+resource "aws_kms_key" "main" {}
+
+data "aws_iam_policy_document" "admin" {
+  statement {
+    actions   = ["*"]
+    resources = ["*"]
+  }
+}
+
+resource "aws_iam_policy" "admin" {
+  policy = jsonencode({
+    Version   = "2012-10-17"
+    Statement = [{ Effect = "Allow", Action = "*", Resource = "*" }]
+  })
+}
+`
+
+const testScanPlan = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {
+      "address": "aws_ebs_volume.data",
+      "mode": "managed",
+      "type": "aws_ebs_volume",
+      "change": {"actions": ["create"], "after": {"encrypted": false, "size": 10}, "after_unknown": {"id": true}}
+    },
+    {
+      "address": "aws_instance.app",
+      "mode": "managed",
+      "type": "aws_instance",
+      "change": {"actions": ["create"], "after": {"metadata_options": []}, "after_unknown": {"metadata_options": true}}
+    },
+    {
+      "address": "aws_lb_listener.http",
+      "mode": "managed",
+      "type": "aws_lb_listener",
+      "change": {"actions": ["update"], "after": {"protocol": "HTTP", "default_action": [{"type": "forward"}]}, "after_unknown": {"default_action": [{}]}}
+    },
+    {
+      "address": "aws_s3_bucket_public_access_block.old",
+      "mode": "managed",
+      "type": "aws_s3_bucket_public_access_block",
+      "change": {"actions": ["delete"], "after": null}
+    },
+    {
+      "address": "azurerm_storage_account.logs",
+      "mode": "managed",
+      "type": "azurerm_storage_account",
+      "change": {"actions": ["create"], "after": {"https_traffic_only_enabled": true, "allow_nested_items_to_be_public": true, "access_key": "literal-in-plan"}, "after_unknown": {}}
+    }
+  ]
+}`
+
+// findingKeys returns the resource and rule ID of the findings
+func findingKeys(report *ScanReport) []string {
+	keys := []string{}
+	for _, finding := range report.Findings {
+		keys = append(keys, finding.Resource+" "+finding.RuleID)
+	}
+	return keys
+}
+
+func TestScanConfiguration(t *testing.T) {
+	resources, err := configurationScanResources(testScanConfiguration)
+	require.NoError(t, err)
+	require.Len(t, resources, 8)
+
+	report := scanResources("configuration", resources, "LOW", nil)
+	assert.Equal(t, []string{
+		"aws_db_instance.main aws-rds-no-public-db-access",
+		"aws_security_group.web aws-ec2-no-public-ingress-sgr",
+		"provider.aws general-secrets-no-plaintext-exposure",
+		"aws_iam_policy.admin aws-iam-no-policy-wildcards",
+		"data.aws_iam_policy_document.admin aws-iam-no-policy-wildcards",
+		"aws_kms_key.main aws-kms-auto-rotate-keys",
+	}, findingKeys(report))
+	assert.Equal(t, "main.tf:7", report.Findings[1].Location)
+	assert.Equal(t, map[string]int{"CRITICAL": 3, "HIGH": 2, "MEDIUM": 1}, report.Summary)
+	assert.Equal(t, len(scanRules), report.RulesEvaluated)
+
+	t.Run("filters by severity and rule", func(t *testing.T) {
+		report := scanResources("configuration", resources, "HIGH", map[string]bool{"general-secrets-no-plaintext-exposure": true})
+		assert.Equal(t, []string{
+			"aws_db_instance.main aws-rds-no-public-db-access",
+			"aws_security_group.web aws-ec2-no-public-ingress-sgr",
+			"aws_iam_policy.admin aws-iam-no-policy-wildcards",
+			"data.aws_iam_policy_document.admin aws-iam-no-policy-wildcards",
+		}, findingKeys(report))
+	})
+}
+
+func TestScanPlan(t *testing.T) {
+	resources, err := planScanResources([]byte(testScanPlan))
+	require.NoError(t, err)
+	require.Len(t, resources, 4)
+
+	report := scanResources("plan", resources, "LOW", nil)
+	assert.Equal(t, []string{
+		"aws_ebs_volume.data aws-ebs-enable-volume-encryption",
+		"aws_lb_listener.http aws-elb-http-not-used",
+		"azurerm_storage_account.logs azure-storage-no-public-access",
+	}, findingKeys(report))
+	assert.Equal(t, len(scanRules)-1, report.RulesEvaluated)
+
+	_, err = planScanResources([]byte(`{"version": 4, "resources": []}`))
+	assert.ErrorContains(t, err, "format_version")
+}
+
+func TestScanConfigurationHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := ScanConfiguration(logger)
+	assert.Equal(t, "scan_configuration", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "scan_configuration", Arguments: arguments}}
+	}
+
+	result, err := scanConfigurationHandler(t.Context(), request(map[string]any{"plan_json": testScanPlan, "min_severity": "high"}), logger)
+	require.NoError(t, err)
+	var report ScanReport
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &report))
+	assert.Equal(t, "plan", report.Input)
+	assert.Len(t, report.Findings, 3)
+
+	for _, arguments := range []map[string]any{
+		{},
+		{"configuration": testScanConfiguration, "plan_json": testScanPlan},
+		{"configuration": testScanConfiguration, "min_severity": "SEVERE"},
+		{"configuration": `resource "aws_instance" {`},
+	} {
+		_, err := scanConfigurationHandler(t.Context(), request(arguments), logger)
+		assert.Error(t, err, arguments)
+	}
+}
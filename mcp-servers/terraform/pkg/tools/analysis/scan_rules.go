@@ -0,0 +1,362 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// scanRules is the embedded rule set of the scan_configuration tool. Rule IDs follow the tfsec naming so that
+// findings can be matched with the documentation of the same checks in tfsec and trivy.
+var scanRules = []scanRule{
+	{
+		ID:          "aws-s3-block-public-access",
+		Severity:    "HIGH",
+		Title:       "S3 public access block does not block all public access",
+		Remediation: "Set block_public_acls, block_public_policy, ignore_public_acls and restrict_public_buckets to true.",
+		Types:       []string{"aws_s3_bucket_public_access_block"},
+		Check: func(values map[string]any) bool {
+			return notTrue(values, "block_public_acls") || notTrue(values, "block_public_policy") ||
+				notTrue(values, "ignore_public_acls") || notTrue(values, "restrict_public_buckets")
+		},
+	},
+	{
+		ID:          "aws-s3-no-public-acl",
+		Severity:    "HIGH",
+		Title:       "S3 bucket has a public canned ACL",
+		Remediation: "Use the private ACL, or drop the ACL and grant access through bucket policies.",
+		Types:       []string{"aws_s3_bucket", "aws_s3_bucket_acl"},
+		Check: func(values map[string]any) bool {
+			return oneOf(values, "acl", "public-read", "public-read-write", "authenticated-read")
+		},
+	},
+	{
+		ID:          "aws-ec2-no-public-ingress-sgr",
+		Severity:    "CRITICAL",
+		Title:       "Security group allows ingress from the internet",
+		Remediation: "Restrict the ingress CIDR blocks to the networks that need access instead of 0.0.0.0/0 or ::/0.",
+		Types:       []string{"aws_security_group", "aws_security_group_rule", "aws_vpc_security_group_ingress_rule"},
+		Check: func(values map[string]any) bool {
+			if values["type"] != nil && !oneOf(values, "type", "ingress") {
+				return false
+			}
+			if publicCIDR(stringList(values, "cidr_blocks")...) || publicCIDR(stringList(values, "ipv6_cidr_blocks")...) {
+				return true
+			}
+			if publicCIDR(stringList(values, "cidr_ipv4")...) || publicCIDR(stringList(values, "cidr_ipv6")...) {
+				return true
+			}
+			for _, ingress := range nestedBlocks(values, "ingress") {
+				if publicCIDR(stringList(ingress, "cidr_blocks")...) || publicCIDR(stringList(ingress, "ipv6_cidr_blocks")...) {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		ID:          "aws-ec2-enforce-http-token-imds",
+		Severity:    "HIGH",
+		Title:       "Instance metadata service does not require session tokens (IMDSv2)",
+		Remediation: "Add a metadata_options block with http_tokens = \"required\".",
+		Types:       []string{"aws_instance", "aws_launch_template"},
+		Check: func(values map[string]any) bool {
+			if isUnknown(values["metadata_options"]) {
+				return false
+			}
+			options := nestedBlocks(values, "metadata_options")
+			if len(options) == 0 {
+				return true
+			}
+			return !isUnknown(options[0]["http_tokens"]) && !oneOf(options[0], "http_tokens", "required")
+		},
+	},
+	{
+		ID:          "aws-ebs-enable-volume-encryption",
+		Severity:    "HIGH",
+		Title:       "EBS volume is not encrypted",
+		Remediation: "Set encrypted to true, or enable EBS encryption by default in the region.",
+		Types:       []string{"aws_ebs_volume"},
+		Check: func(values map[string]any) bool {
+			return notTrue(values, "encrypted")
+		},
+	},
+	{
+		ID:          "aws-rds-no-public-db-access",
+		Severity:    "CRITICAL",
+		Title:       "Database instance is publicly accessible",
+		Remediation: "Set publicly_accessible to false and reach the database from within the VPC.",
+		Types:       []string{"aws_db_instance", "aws_rds_cluster_instance"},
+		Check: func(values map[string]any) bool {
+			return values["publicly_accessible"] == true
+		},
+	},
+	{
+		ID:          "aws-rds-encrypt-instance-storage-data",
+		Severity:    "HIGH",
+		Title:       "Database storage is not encrypted",
+		Remediation: "Set storage_encrypted to true, optionally with a customer managed kms_key_id.",
+		Types:       []string{"aws_db_instance", "aws_rds_cluster"},
+		Check: func(values map[string]any) bool {
+			return notTrue(values, "storage_encrypted")
+		},
+	},
+	{
+		ID:          "aws-kms-auto-rotate-keys",
+		Severity:    "MEDIUM",
+		Title:       "KMS key rotation is not enabled",
+		Remediation: "Set enable_key_rotation to true.",
+		Types:       []string{"aws_kms_key"},
+		Check: func(values map[string]any) bool {
+			if values["customer_master_key_spec"] != nil && !oneOf(values, "customer_master_key_spec", "SYMMETRIC_DEFAULT") {
+				// Only symmetric keys can be rotated
+				return false
+			}
+			return notTrue(values, "enable_key_rotation")
+		},
+	},
+	{
+		ID:          "aws-cloudtrail-enable-log-validation",
+		Severity:    "MEDIUM",
+		Title:       "CloudTrail log file validation is not enabled",
+		Remediation: "Set enable_log_file_validation to true so that tampering with the logs can be detected.",
+		Types:       []string{"aws_cloudtrail"},
+		Check: func(values map[string]any) bool {
+			return notTrue(values, "enable_log_file_validation")
+		},
+	},
+	{
+		ID:          "aws-elb-http-not-used",
+		Severity:    "HIGH",
+		Title:       "Load balancer listener serves plain HTTP",
+		Remediation: "Use an HTTPS listener, or redirect HTTP to HTTPS with a default_action of type redirect.",
+		Types:       []string{"aws_lb_listener", "aws_alb_listener"},
+		Check: func(values map[string]any) bool {
+			if !oneOf(values, "protocol", "HTTP") || isUnknown(values["default_action"]) {
+				return false
+			}
+			for _, action := range nestedBlocks(values, "default_action") {
+				if isUnknown(action["type"]) || oneOf(action, "type", "redirect") {
+					return false
+				}
+			}
+			return true
+		},
+	},
+	{
+		ID:          "aws-iam-no-policy-wildcards",
+		Severity:    "HIGH",
+		Title:       "IAM policy allows all actions",
+		Remediation: "Grant the specific actions the principal needs instead of \"*\".",
+		Types:       []string{"data.aws_iam_policy_document", "aws_iam_policy", "aws_iam_role_policy", "aws_iam_user_policy", "aws_iam_group_policy"},
+		Check: func(values map[string]any) bool {
+			for _, statement := range nestedBlocks(values, "statement") {
+				if !oneOf(statement, "effect", "Deny") && contains(stringList(statement, "actions"), "*") {
+					return true
+				}
+			}
+			policy, ok := values["policy"].(string)
+			return ok && policyAllowsAllActions(policy)
+		},
+	},
+	{
+		ID:          "google-storage-enable-ubla",
+		Severity:    "MEDIUM",
+		Title:       "Storage bucket does not use uniform bucket-level access",
+		Remediation: "Set uniform_bucket_level_access to true and manage access with IAM only.",
+		Types:       []string{"google_storage_bucket"},
+		Check: func(values map[string]any) bool {
+			return notTrue(values, "uniform_bucket_level_access")
+		},
+	},
+	{
+		ID:          "google-compute-no-public-ingress",
+		Severity:    "CRITICAL",
+		Title:       "Firewall allows ingress from the internet",
+		Remediation: "Restrict source_ranges to the networks that need access instead of 0.0.0.0/0.",
+		Types:       []string{"google_compute_firewall"},
+		Check: func(values map[string]any) bool {
+			if values["direction"] != nil && !oneOf(values, "direction", "INGRESS") {
+				return false
+			}
+			return len(nestedBlocks(values, "allow")) > 0 && publicCIDR(stringList(values, "source_ranges")...)
+		},
+	},
+	{
+		ID:          "azure-storage-enforce-https",
+		Severity:    "HIGH",
+		Title:       "Storage account accepts plain HTTP traffic",
+		Remediation: "Set https_traffic_only_enabled (enable_https_traffic_only before azurerm 4.0) to true.",
+		Types:       []string{"azurerm_storage_account"},
+		Check: func(values map[string]any) bool {
+			return values["https_traffic_only_enabled"] == false || values["enable_https_traffic_only"] == false
+		},
+	},
+	{
+		ID:          "azure-storage-no-public-access",
+		Severity:    "HIGH",
+		Title:       "Storage account allows public access to blobs",
+		Remediation: "Set allow_nested_items_to_be_public to false.",
+		Types:       []string{"azurerm_storage_account"},
+		Check: func(values map[string]any) bool {
+			return values["allow_nested_items_to_be_public"] == true
+		},
+	},
+	{
+		ID:          "azure-network-no-public-ingress",
+		Severity:    "CRITICAL",
+		Title:       "Network security rule allows ingress from the internet",
+		Remediation: "Restrict the source address prefixes to the networks that need access instead of *, Internet or 0.0.0.0/0.",
+		Types:       []string{"azurerm_network_security_rule", "azurerm_network_security_group"},
+		Check: func(values map[string]any) bool {
+			rules := append([]map[string]any{values}, nestedBlocks(values, "security_rule")...)
+			for _, rule := range rules {
+				if oneOf(rule, "direction", "Inbound") && oneOf(rule, "access", "Allow") &&
+					(publicAddressPrefix(stringList(rule, "source_address_prefix")...) || publicAddressPrefix(stringList(rule, "source_address_prefixes")...)) {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		ID:                "general-secrets-no-plaintext-exposure",
+		Severity:          "CRITICAL",
+		Title:             "Credential set as a literal in the configuration",
+		Remediation:       "Pass the credential through a sensitive variable, a secrets manager data source or the environment instead of committing it.",
+		ConfigurationOnly: true,
+		Check: func(values map[string]any) bool {
+			for name, value := range values {
+				if text, ok := value.(string); ok && text != "" && secretAttributes[name] {
+					return true
+				}
+			}
+			return false
+		},
+	},
+}
+
+// secretAttributes are the attribute names the general-secrets-no-plaintext-exposure rule checks
+var secretAttributes = map[string]bool{
+	"password":          true,
+	"master_password":   true,
+	"admin_password":    true,
+	"access_key":        true,
+	"secret_key":        true,
+	"client_secret":     true,
+	"token":             true,
+	"api_key":           true,
+	"private_key":       true,
+	"connection_string": true,
+}
+
+func isUnknown(value any) bool {
+	_, ok := value.(unknownValue)
+	return ok
+}
+
+// notTrue reports whether an attribute is known not to be true, an unset attribute being false
+func notTrue(values map[string]any, name string) bool {
+	value := values[name]
+	return value == nil || value == false
+}
+
+// oneOf reports whether an attribute is known to be one of the strings
+func oneOf(values map[string]any, name string, options ...string) bool {
+	value, ok := values[name].(string)
+	if !ok {
+		return false
+	}
+	for _, option := range options {
+		if strings.EqualFold(value, option) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList returns the known strings of an attribute holding a string or a list of strings
+func stringList(values map[string]any, name string) []string {
+	switch value := values[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		var list []string
+		for _, item := range value {
+			if text, ok := item.(string); ok {
+				list = append(list, text)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// nestedBlocks returns the known blocks of a nested block type
+func nestedBlocks(values map[string]any, name string) []map[string]any {
+	list, _ := values[name].([]any)
+	var blocks []map[string]any
+	for _, item := range list {
+		if block, ok := item.(map[string]any); ok {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func publicCIDR(cidrs ...string) bool {
+	for _, cidr := range cidrs {
+		if cidr == "0.0.0.0/0" || cidr == "::/0" {
+			return true
+		}
+	}
+	return false
+}
+
+func publicAddressPrefix(prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if prefix == "*" || strings.EqualFold(prefix, "Internet") || strings.EqualFold(prefix, "Any") || publicCIDR(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyAllowsAllActions reports whether a JSON IAM policy has an Allow statement on the "*" action
+func policyAllowsAllActions(policy string) bool {
+	var document struct {
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return false
+	}
+	type policyStatement struct {
+		Effect string `json:"Effect"`
+		Action any    `json:"Action"`
+	}
+	var statements []policyStatement
+	if err := json.Unmarshal(document.Statement, &statements); err != nil {
+		var statement policyStatement
+		if err := json.Unmarshal(document.Statement, &statement); err != nil {
+			return false
+		}
+		statements = []policyStatement{statement}
+	}
+	for _, statement := range statements {
+		if statement.Effect == "Allow" && contains(stringList(map[string]any{"action": statement.Action}, "action"), "*") {
+			return true
+		}
+	}
+	return false
+}
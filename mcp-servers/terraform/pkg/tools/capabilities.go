@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	log "github.com/sirupsen/logrus"
+)
+
+// Capabilities are the feature flags of the server for a session, so that clients can adapt their
+// prompting without calling tools that are not available to them
+type Capabilities struct {
+	// Toolsets reports which groups of tools are enabled, e.g. the module source tools need a GitHub token
+	Toolsets Toolsets `json:"toolsets"`
+	// ReadOnly is true when the session can only call tools that do not change infrastructure, i.e.
+	// the HCP Terraform/TFE tools are not available to it
+	ReadOnly bool `json:"read_only"`
+	// TFEAvailable is true when the session has an HCP Terraform/TFE token, which is not verified
+	TFEAvailable bool `json:"tfe_available"`
+	// RegistryCache reports the cache of the registry responses revalidated with conditional requests
+	RegistryCache RegistryCacheStatus `json:"registry_cache"`
+	// Guardrails is true when MCP_GUARDRAIL_POLICY_FILE restricts when the TFE tools may change workspaces
+	Guardrails bool `json:"guardrails"`
+}
+
+// Toolsets are the groups of tools of the server
+type Toolsets struct {
+	Providers      bool `json:"providers"`
+	Modules        bool `json:"modules"`
+	Policies       bool `json:"policies"`
+	Analysis       bool `json:"analysis"`
+	ModuleSource   bool `json:"module_source"`
+	SemanticSearch bool `json:"semantic_search"`
+	TFE            bool `json:"tfe"`
+}
+
+// RegistryCacheStatus is the status of the registry response cache, disabled with MCP_REGISTRY_CACHE_SIZE=0
+type RegistryCacheStatus struct {
+	Enabled    bool `json:"enabled"`
+	MaxEntries int  `json:"max_entries"`
+}
+
+// ServerCapabilities returns the capabilities of the session of ctx, served by the server://capabilities
+// resource and in the initialize result
+func ServerCapabilities(ctx context.Context, _ *log.Logger) any {
+	tfeAvailable := client.TfeAvailable(ctx)
+	cacheSize := client.RegistryCacheSize()
+	return Capabilities{
+		Toolsets: Toolsets{
+			Providers:      true,
+			Modules:        true,
+			Policies:       true,
+			Analysis:       true,
+			ModuleSource:   client.GitHubSourceToolsEnabled(),
+			SemanticSearch: client.SemanticSearchEnabled(),
+			TFE:            tfeAvailable,
+		},
+		ReadOnly:      !tfeAvailable,
+		TFEAvailable:  tfeAvailable,
+		RegistryCache: RegistryCacheStatus{Enabled: cacheSize > 0, MaxEntries: cacheSize},
+		Guardrails:    strings.TrimSpace(os.Getenv(client.GuardrailPolicyFile)) != "",
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerCapabilities(t *testing.T) {
+	t.Setenv(client.TerraformToken, "")
+	t.Setenv(client.GitHubToken, "")
+	t.Setenv(client.EmbeddingsURL, "")
+	t.Setenv(client.GuardrailPolicyFile, "")
+
+	capabilities := ServerCapabilities(context.Background(), nil).(Capabilities)
+	assert.True(t, capabilities.ReadOnly)
+	assert.False(t, capabilities.TFEAvailable)
+	assert.Equal(t, Toolsets{Providers: true, Modules: true, Policies: true, Analysis: true}, capabilities.Toolsets)
+	assert.Equal(t, capabilities.RegistryCache.MaxEntries > 0, capabilities.RegistryCache.Enabled)
+
+	t.Setenv(client.TerraformToken, "token")
+	t.Setenv(client.GitHubToken, "token")
+	t.Setenv(client.GuardrailPolicyFile, "/etc/mcp/guardrails.json")
+	capabilities = ServerCapabilities(context.Background(), nil).(Capabilities)
+	assert.False(t, capabilities.ReadOnly)
+	assert.True(t, capabilities.TFEAvailable)
+	assert.True(t, capabilities.Toolsets.TFE)
+	assert.True(t, capabilities.Toolsets.ModuleSource)
+	assert.False(t, capabilities.Toolsets.SemanticSearch)
+	assert.True(t, capabilities.Guardrails)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/client"
 	tfeTools "github.com/hashicorp/terraform-mcp-server/pkg/tools/tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
@@ -101,6 +102,25 @@ func (r *DynamicToolRegistry) registerTFETools() {
 	listTerraformProjectsTool := r.createDynamicTFETool("list_terraform_projects", tfeTools.ListTerraformProjects)
 	r.mcpServer.AddTool(listTerraformProjectsTool.Tool, listTerraformProjectsTool.Handler)
 
+	getOrgEntitlementsTool := r.createDynamicTFETool("get_org_entitlements", tfeTools.GetOrgEntitlements)
+	r.mcpServer.AddTool(getOrgEntitlementsTool.Tool, getOrgEntitlementsTool.Handler)
+
+	listOrgMembershipsTool := r.createDynamicTFETool("list_org_memberships", tfeTools.ListOrgMemberships)
+	r.mcpServer.AddTool(listOrgMembershipsTool.Tool, listOrgMembershipsTool.Handler)
+
+	getAuditTrailTool := r.createDynamicTFETool("get_audit_trail", tfeTools.GetAuditTrail)
+	r.mcpServer.AddTool(getAuditTrailTool.Tool, getAuditTrailTool.Handler)
+
+	// Policy set tools
+	listPolicySetsTool := r.createDynamicTFETool("list_policy_sets", tfeTools.ListPolicySets)
+	r.mcpServer.AddTool(listPolicySetsTool.Tool, listPolicySetsTool.Handler)
+
+	createPolicySetTool := r.createDynamicTFETool("create_policy_set", tfeTools.CreatePolicySet)
+	r.mcpServer.AddTool(createPolicySetTool.Tool, createPolicySetTool.Handler)
+
+	attachPolicySetToWorkspacesTool := r.createDynamicTFETool("attach_policy_set_to_workspaces", tfeTools.AttachPolicySetToWorkspaces)
+	r.mcpServer.AddTool(attachPolicySetToWorkspacesTool.Tool, attachPolicySetToWorkspacesTool.Handler)
+
 	// Workspace management tools
 	ListWorkspacesTool := r.createDynamicTFETool("list_workspaces", tfeTools.ListWorkspaces)
 	r.mcpServer.AddTool(ListWorkspacesTool.Tool, ListWorkspacesTool.Handler)
@@ -108,15 +128,51 @@ func (r *DynamicToolRegistry) registerTFETools() {
 	getWorkspaceDetailsTool := r.createDynamicTFETool("get_workspace_details", tfeTools.GetWorkspaceDetails)
 	r.mcpServer.AddTool(getWorkspaceDetailsTool.Tool, getWorkspaceDetailsTool.Handler)
 
+	reportOrgWorkspacesTool := r.createDynamicTFETool("report_org_workspaces", tfeTools.ReportOrgWorkspaces)
+	r.mcpServer.AddTool(reportOrgWorkspacesTool.Tool, reportOrgWorkspacesTool.Handler)
+
+	explorerQueryTool := r.createDynamicTFETool("explorer_query", tfeTools.ExplorerQuery)
+	r.mcpServer.AddTool(explorerQueryTool.Tool, explorerQueryTool.Handler)
+
+	getWorkspaceAssessmentTool := r.createDynamicTFETool("get_workspace_assessment", tfeTools.GetWorkspaceAssessment)
+	r.mcpServer.AddTool(getWorkspaceAssessmentTool.Tool, getWorkspaceAssessmentTool.Handler)
+
+	listWorkspaceCheckResultsTool := r.createDynamicTFETool("list_workspace_check_results", tfeTools.ListWorkspaceCheckResults)
+	r.mcpServer.AddTool(listWorkspaceCheckResultsTool.Tool, listWorkspaceCheckResultsTool.Handler)
+
+	findStaleWorkspacesTool := r.createDynamicTFETool("find_stale_workspaces", tfeTools.FindStaleWorkspaces)
+	r.mcpServer.AddTool(findStaleWorkspacesTool.Tool, findStaleWorkspacesTool.Handler)
+
+	planTerraformVersionUpgradeTool := r.createDynamicTFETool("plan_terraform_version_upgrade", tfeTools.PlanTerraformVersionUpgrade)
+	r.mcpServer.AddTool(planTerraformVersionUpgradeTool.Tool, planTerraformVersionUpgradeTool.Handler)
+
 	createWorkspaceTool := r.createDynamicTFETool("create_workspace", tfeTools.CreateWorkspace)
 	r.mcpServer.AddTool(createWorkspaceTool.Tool, createWorkspaceTool.Handler)
 
 	updateWorkspaceTool := r.createDynamicTFETool("update_workspace", tfeTools.UpdateWorkspace)
 	r.mcpServer.AddTool(updateWorkspaceTool.Tool, updateWorkspaceTool.Handler)
 
+	bulkTagWorkspacesTool := r.createDynamicTFETool("bulk_tag_workspaces", tfeTools.BulkTagWorkspaces)
+	r.mcpServer.AddTool(bulkTagWorkspacesTool.Tool, bulkTagWorkspacesTool.Handler)
+
 	deleteWorkspaceSafelyTool := r.createDynamicTFETool("delete_workspace_safely", tfeTools.DeleteWorkspaceSafely)
 	r.mcpServer.AddTool(deleteWorkspaceSafelyTool.Tool, deleteWorkspaceSafelyTool.Handler)
 
+	lockWorkspaceTool := r.createDynamicTFETool("lock_workspace", tfeTools.LockWorkspace)
+	r.mcpServer.AddTool(lockWorkspaceTool.Tool, lockWorkspaceTool.Handler)
+
+	unlockWorkspaceTool := r.createDynamicTFETool("unlock_workspace", tfeTools.UnlockWorkspace)
+	r.mcpServer.AddTool(unlockWorkspaceTool.Tool, unlockWorkspaceTool.Handler)
+
+	exportWorkspaceVariablesTool := r.createDynamicTFETool("export_workspace_variables", tfeTools.ExportWorkspaceVariables)
+	r.mcpServer.AddTool(exportWorkspaceVariablesTool.Tool, exportWorkspaceVariablesTool.Handler)
+
+	importWorkspaceVariablesTool := r.createDynamicTFETool("import_workspace_variables", tfeTools.ImportWorkspaceVariables)
+	r.mcpServer.AddTool(importWorkspaceVariablesTool.Tool, importWorkspaceVariablesTool.Handler)
+
+	cloneWorkspaceTool := r.createDynamicTFETool("clone_workspace", tfeTools.CloneWorkspace)
+	r.mcpServer.AddTool(cloneWorkspaceTool.Tool, cloneWorkspaceTool.Handler)
+
 	// Private provider tools
 	searchPrivateProvidersTool := r.createDynamicTFETool("search_private_providers", tfeTools.SearchPrivateProviders)
 	r.mcpServer.AddTool(searchPrivateProvidersTool.Tool, searchPrivateProvidersTool.Handler)
@@ -124,6 +180,15 @@ func (r *DynamicToolRegistry) registerTFETools() {
 	getPrivateProviderDetailsTool := r.createDynamicTFETool("get_private_provider_details", tfeTools.GetPrivateProviderDetails)
 	r.mcpServer.AddTool(getPrivateProviderDetailsTool.Tool, getPrivateProviderDetailsTool.Handler)
 
+	addProviderPlatformTool := r.createDynamicTFETool("add_provider_platform", tfeTools.AddProviderPlatform)
+	r.mcpServer.AddTool(addProviderPlatformTool.Tool, addProviderPlatformTool.Handler)
+
+	listGPGKeysTool := r.createDynamicTFETool("list_gpg_keys", tfeTools.ListGPGKeys)
+	r.mcpServer.AddTool(listGPGKeysTool.Tool, listGPGKeysTool.Handler)
+
+	addGPGKeyTool := r.createDynamicTFETool("add_gpg_key", tfeTools.AddGPGKey)
+	r.mcpServer.AddTool(addGPGKeyTool.Tool, addGPGKeyTool.Handler)
+
 	// Private module tools
 	searchPrivateModulesTool := r.createDynamicTFETool("search_private_modules", tfeTools.SearchPrivateModules)
 	r.mcpServer.AddTool(searchPrivateModulesTool.Tool, searchPrivateModulesTool.Handler)
@@ -131,6 +196,12 @@ func (r *DynamicToolRegistry) registerTFETools() {
 	getPrivateModuleDetailsTool := r.createDynamicTFETool("get_private_module_details", tfeTools.GetPrivateModuleDetails)
 	r.mcpServer.AddTool(getPrivateModuleDetailsTool.Tool, getPrivateModuleDetailsTool.Handler)
 
+	setModuleVersionStatusTool := r.createDynamicTFETool("set_module_version_status", tfeTools.SetModuleVersionStatus)
+	r.mcpServer.AddTool(setModuleVersionStatusTool.Tool, setModuleVersionStatusTool.Handler)
+
+	pruneModuleVersionsTool := r.createDynamicTFETool("prune_module_versions", tfeTools.PruneModuleVersions)
+	r.mcpServer.AddTool(pruneModuleVersionsTool.Tool, pruneModuleVersionsTool.Handler)
+
 	// Terraform run tools
 	listRunsTool := r.createDynamicTFETool("list_runs", tfeTools.ListRuns)
 	r.mcpServer.AddTool(listRunsTool.Tool, listRunsTool.Handler)
@@ -138,9 +209,24 @@ func (r *DynamicToolRegistry) registerTFETools() {
 	createRunTool := r.createDynamicTFETool("create_run", tfeTools.CreateRun)
 	r.mcpServer.AddTool(createRunTool.Tool, createRunTool.Handler)
 
+	createRunsBulkTool := r.createDynamicTFETool("create_runs_bulk", tfeTools.CreateRunsBulk)
+	r.mcpServer.AddTool(createRunsBulkTool.Tool, createRunsBulkTool.Handler)
+
+	listPendingRunsForOrgTool := r.createDynamicTFETool("list_pending_runs_for_org", tfeTools.ListPendingRunsForOrg)
+	r.mcpServer.AddTool(listPendingRunsForOrgTool.Tool, listPendingRunsForOrgTool.Handler)
+
+	listRunTriggersTool := r.createDynamicTFETool("list_run_triggers", tfeTools.ListRunTriggers)
+	r.mcpServer.AddTool(listRunTriggersTool.Tool, listRunTriggersTool.Handler)
+
+	createRunTriggerTool := r.createDynamicTFETool("create_run_trigger", tfeTools.CreateRunTrigger)
+	r.mcpServer.AddTool(createRunTriggerTool.Tool, createRunTriggerTool.Handler)
+
 	actionRunTool := r.createDynamicTFETool("action_run", tfeTools.ActionRun)
 	r.mcpServer.AddTool(actionRunTool.Tool, actionRunTool.Handler)
 
+	retryRunTool := r.createDynamicTFETool("retry_run", tfeTools.RetryRun)
+	r.mcpServer.AddTool(retryRunTool.Tool, retryRunTool.Handler)
+
 	getRunDetailsTool := r.createDynamicTFETool("get_run_details", tfeTools.GetRunDetails)
 	r.mcpServer.AddTool(getRunDetailsTool.Tool, getRunDetailsTool.Handler)
 
@@ -157,7 +243,7 @@ func (r *DynamicToolRegistry) createDynamicTFETool(toolName string, toolFactory
 		session := server.ClientSessionFromContext(ctx)
 		if session == nil {
 			r.logger.WithField("tool", toolName).Warn("TFE tool called without session context")
-			return mcp.NewToolResultError("This tool requires an active session with valid Terraform Cloud/Enterprise configuration."), nil
+			return utils.NewToolResultErrorWithCode(utils.ErrorCodeUnauthorized, "This tool requires an active session with valid Terraform Cloud/Enterprise configuration."), nil
 		}
 
 		// Check if this session has a valid TFE client
@@ -170,7 +256,7 @@ func (r *DynamicToolRegistry) createDynamicTFETool(toolName string, toolFactory
 					"tool": toolName,
 				}).Warn("TFE tool called but session has no valid TFE client")
 
-				return mcp.NewToolResultError("This tool is not available. This tool requires a valid Terraform Cloud/Enterprise token and configuration. Please ensure TFE_TOKEN and TFE_ADDRESS environment variables are properly set."), nil
+				return utils.NewToolResultErrorWithCode(utils.ErrorCodeUnauthorized, "This tool is not available. This tool requires a valid Terraform Cloud/Enterprise token and configuration. Please ensure TFE_TOKEN and TFE_ADDRESS environment variables are properly set."), nil
 			}
 			// If we found a valid client that wasn't registered, register it now
 			r.RegisterSessionWithTFE(sessionID)
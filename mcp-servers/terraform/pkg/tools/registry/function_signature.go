@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FunctionSignature is the signature of a provider-defined function, parsed from its documentation
+type FunctionSignature struct {
+	Name        string              `json:"name"`
+	Call        string              `json:"call,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Parameters  []FunctionParameter `json:"parameters"`
+	ReturnType  string              `json:"return_type"`
+	Example     string              `json:"example,omitempty"`
+}
+
+// FunctionParameter is a parameter of a provider-defined function. A variadic parameter takes any number of arguments.
+type FunctionParameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Variadic    bool   `json:"variadic,omitempty"`
+}
+
+var (
+	// functionHeadingRegex matches the title of a function document, capturing the function name
+	functionHeadingRegex = regexp.MustCompile(`^#\s+(?:Function:\s*)?([A-Za-z0-9_]+)\s*$`)
+	// signatureRegex matches a signature line such as "arn_parse(arn string) object"
+	signatureRegex = regexp.MustCompile(`^([A-Za-z0-9_]+)\((.*)\)\s*(.*)$`)
+	// argumentRegex matches an item of the Arguments list such as "1. `arn` (String) ARN to parse."
+	argumentRegex = regexp.MustCompile("^(?:\\d+\\.|[-*])\\s+`(?:\\.\\.\\.)?([A-Za-z0-9_]+)`\\s*(?:\\([^)]*\\))?\\s*(.*)$")
+)
+
+// parseFunctionSignature extracts the signature of a provider-defined function from its sanitized
+// documentation: the "Signature" section gives the parameters and the return type, the "Arguments"
+// section the description of each parameter and the "Example Usage" section the example.
+func parseFunctionSignature(content string) (FunctionSignature, error) {
+	var signature FunctionSignature
+	var section, signatureLine string
+	var example, description []string
+	argumentDescriptions := make(map[string]string)
+	inCode, codeBlocks := false, 0
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			if !inCode {
+				codeBlocks++
+			}
+			continue
+		}
+
+		if inCode {
+			switch {
+			case section == "signature" && signatureLine == "" && trimmed != "":
+				signatureLine = trimmed
+			case section == "example usage" && codeBlocks == 0:
+				example = append(example, line)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "## ") {
+			section = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(trimmed, "## ")))
+			codeBlocks = 0
+			continue
+		}
+		if match := functionHeadingRegex.FindStringSubmatch(trimmed); match != nil && signature.Name == "" {
+			signature.Name = match[1]
+			section = "description"
+			continue
+		}
+
+		switch section {
+		case "description":
+			// The first paragraph after the title describes the function
+			if trimmed == "" && len(description) > 0 {
+				section = ""
+			} else if trimmed != "" {
+				description = append(description, trimmed)
+			}
+		case "arguments":
+			if match := argumentRegex.FindStringSubmatch(trimmed); match != nil {
+				argumentDescriptions[match[1]] = strings.TrimSpace(match[2])
+			}
+		}
+	}
+
+	match := signatureRegex.FindStringSubmatch(signatureLine)
+	if match == nil {
+		return signature, fmt.Errorf("no function signature found in the documentation")
+	}
+	if signature.Name == "" {
+		signature.Name = match[1]
+	}
+	signature.ReturnType = strings.TrimSpace(match[3])
+	signature.Description = strings.Join(description, " ")
+	signature.Example = strings.TrimSpace(strings.Join(example, "\n"))
+
+	signature.Parameters = []FunctionParameter{}
+	for _, parameter := range splitParameters(match[2]) {
+		name, parameterType, _ := strings.Cut(parameter, " ")
+		variadic := strings.HasPrefix(name, "...")
+		name = strings.TrimPrefix(name, "...")
+		signature.Parameters = append(signature.Parameters, FunctionParameter{
+			Name:        name,
+			Type:        strings.TrimSpace(parameterType),
+			Description: argumentDescriptions[name],
+			Variadic:    variadic,
+		})
+	}
+
+	// The example shows how the function is called, with the local name of its provider
+	if callMatch := regexp.MustCompile(`provider::[A-Za-z0-9_-]+::` + regexp.QuoteMeta(signature.Name) + `\b`).FindString(signature.Example); callMatch != "" {
+		signature.Call = callMatch
+	}
+	return signature, nil
+}
+
+// splitParameters splits the parameter list of a signature on the commas outside of type
+// constructors, e.g. "names list(string), values map(string)"
+func splitParameters(parameters string) []string {
+	var result []string
+	depth, start := 0, 0
+	for i, r := range parameters {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, parameters[start:i])
+				start = i + 1
+			}
+		}
+	}
+	result = append(result, parameters[start:])
+
+	trimmed := result[:0]
+	for _, parameter := range result {
+		if parameter = strings.TrimSpace(parameter); parameter != "" {
+			trimmed = append(trimmed, parameter)
+		}
+	}
+	return trimmed
+}
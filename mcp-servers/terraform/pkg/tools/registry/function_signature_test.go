@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+)
+
+func TestParseFunctionSignature(t *testing.T) {
+	doc := "---\nsubcategory: \"\"\npage_title: \"AWS: arn_build\"\n---\n\n" +
+		"# Function: arn_build\n\n" +
+		"Builds an ARN from its constituent parts.\n\n" +
+		"## Example Usage\n\n" +
+		"```terraform\n# result: arn:aws:iam::444455556666:role/example\noutput \"example\" {\n  value = provider::aws::arn_build(\"aws\", \"iam\", \"\", \"444455556666\", \"role/example\")\n}\n```\n\n" +
+		"## Signature\n\n" +
+		"```text\narn_build(partition string, service string, region string, account_id string, resource string) string\n```\n\n" +
+		"## Arguments\n\n" +
+		"1. `partition` (String) Partition in which the resource is located.\n" +
+		"1. `service` (String) Service namespace.\n" +
+		"1. `region` (String) Region code.\n" +
+		"1. `account_id` (String) AWS account identifier.\n" +
+		"1. `resource` (String) Resource section, typically composed of a resource type and identifier.\n"
+	content := utils.SanitizeMarkdown(doc, utils.MarkdownOptions{TopHeadingLevel: 1})
+
+	signature, err := parseFunctionSignature(content)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if signature.Name != "arn_build" || signature.ReturnType != "string" || signature.Call != "provider::aws::arn_build" {
+		t.Errorf("unexpected signature %+v", signature)
+	}
+	if signature.Description != "Builds an ARN from its constituent parts." {
+		t.Errorf("unexpected description %q", signature.Description)
+	}
+	if len(signature.Parameters) != 5 {
+		t.Fatalf("expected 5 parameters, got %+v", signature.Parameters)
+	}
+	want := FunctionParameter{Name: "account_id", Type: "string", Description: "AWS account identifier."}
+	if !reflect.DeepEqual(signature.Parameters[3], want) {
+		t.Errorf("parameter = %+v, want %+v", signature.Parameters[3], want)
+	}
+	if signature.Example == "" || signature.Example[:10] != "# result: " {
+		t.Errorf("unexpected example %q", signature.Example)
+	}
+
+	if _, err := parseFunctionSignature("# Function: broken\n\nNo signature here.\n"); err == nil {
+		t.Error("expected an error without a signature")
+	}
+}
+
+func TestSplitParameters(t *testing.T) {
+	got := splitParameters("names list(string), values map(object({a = string, b = number})), ...rest string")
+	want := []string{"names list(string)", "values map(object({a = string, b = number}))", "...rest string"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitParameters() = %v, want %v", got, want)
+	}
+	if got := splitParameters(""); len(got) != 0 {
+		t.Errorf("expected no parameter, got %v", got)
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CDKTFArgument is a top-level argument of a resource, taken from its Argument Reference
+type CDKTFArgument struct {
+	Name        string `json:"name"`
+	Property    string `json:"property"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// CDKTFSnippet is the result of the generate_cdktf_snippet tool
+type CDKTFSnippet struct {
+	ResourceType  string          `json:"resource_type"`
+	Kind          string          `json:"kind"`
+	Language      string          `json:"language"`
+	ProviderDocID string          `json:"provider_doc_id"`
+	ClassName     string          `json:"class_name"`
+	Import        string          `json:"import"`
+	Snippet       string          `json:"snippet"`
+	Arguments     []CDKTFArgument `json:"arguments"`
+	Notes         []string        `json:"notes"`
+}
+
+func GenerateCDKTFSnippet(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("generate_cdktf_snippet",
+			mcp.WithDescription(`Generates a CDK for Terraform (CDKTF) construct snippet in TypeScript or Python for a provider resource or data source.
+The arguments are read from the Argument Reference of the resource documentation in the registry: required arguments are set with placeholder values and optional arguments are listed as comments.
+Use it instead of translating HCL examples by hand when the project uses CDKTF.`),
+			mcp.WithTitleAnnotation("Generate a CDKTF construct snippet for a Terraform resource"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("provider_name",
+				mcp.Required(),
+				mcp.Description("The name of the Terraform provider, e.g. 'aws', 'google' or 'azurerm'"),
+			),
+			mcp.WithString("provider_namespace",
+				mcp.Description("The publisher of the Terraform provider"),
+				mcp.DefaultString("hashicorp"),
+			),
+			mcp.WithString("resource_type",
+				mcp.Required(),
+				mcp.Description("The full resource or data source type, e.g. 'aws_s3_bucket'"),
+			),
+			mcp.WithString("kind",
+				mcp.Description("Whether resource_type is a resource or a data source"),
+				mcp.Enum("resources", "data-sources"),
+				mcp.DefaultString("resources"),
+			),
+			mcp.WithString("language",
+				mcp.Description("The CDKTF language of the snippet"),
+				mcp.Enum("typescript", "python"),
+				mcp.DefaultString("typescript"),
+			),
+			mcp.WithString("provider_version",
+				mcp.Description("The version of the Terraform provider in the format 'x.y.z', or 'latest'"),
+				mcp.DefaultString("latest"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return generateCDKTFSnippetHandler(ctx, request, logger)
+		},
+	}
+}
+
+func generateCDKTFSnippetHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	providerName, err := request.RequireString("provider_name")
+	if err != nil || providerName == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: provider_name is required", err)
+	}
+	resourceType, err := request.RequireString("resource_type")
+	if err != nil || resourceType == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: resource_type is required", err)
+	}
+	namespace, providerName := client.ResolveProviderAlias(strings.ToLower(request.GetString("provider_namespace", "hashicorp")), strings.ToLower(providerName), logger)
+	resourceType = strings.ToLower(strings.TrimSpace(resourceType))
+	kind := request.GetString("kind", "resources")
+	language := request.GetString("language", "typescript")
+	version := strings.ToLower(request.GetString("provider_version", "latest"))
+
+	slug, ok := strings.CutPrefix(resourceType, providerName+"_")
+	if !ok {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid resource_type",
+			fmt.Errorf("resource_type %q must start with the provider name %q, e.g. %s_instance", resourceType, providerName, providerName))
+	}
+
+	// Get a simple http client to access the public Terraform registry from context
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		logger.WithError(err).Error("failed to get http client for public Terraform registry")
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+	}
+
+	if !utils.IsValidProviderVersionFormat(version) {
+		version, err = client.GetLatestProviderVersion(ctx, httpClient, namespace, providerName, logger)
+		if err != nil {
+			message := fmt.Sprintf("getting the latest version of the %s/%s provider", namespace, providerName)
+			if client.ClassifyError(err) == utils.ErrorCodeNotFound {
+				message += didYouMean(suggestProviders(ctx, httpClient, namespace, providerName, logger))
+			}
+			return nil, utils.LogAndReturnError(logger, message, err)
+		}
+	}
+
+	docID, content, err := resourceDocContent(ctx, httpClient, namespace, providerName, version, kind, slug, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("fetching the documentation of %s", resourceType), err)
+	}
+
+	arguments := parseArgumentReference(content)
+	snippet := renderCDKTFSnippet(providerName, resourceType, kind, language, arguments)
+	snippet.ProviderDocID = docID
+	if len(arguments) == 0 {
+		snippet.Notes = append(snippet.Notes, "No arguments were found in the Argument Reference of the documentation, check the provider docs with get_provider_details.")
+	}
+
+	snippetJSON, err := json.Marshal(snippet)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling CDKTF snippet", err)
+	}
+	return mcp.NewToolResultText(string(snippetJSON)), nil
+}
+
+// resourceDocContent returns the ID and the markdown of the HCL documentation of a resource or data source
+func resourceDocContent(ctx context.Context, httpClient *http.Client, namespace, name, version, kind, slug string, logger *log.Logger) (string, string, error) {
+	response, err := client.SendRegistryCall(ctx, httpClient, http.MethodGet, path.Join("providers", namespace, name, version), logger)
+	if err != nil {
+		return "", "", err
+	}
+	var providerDocs client.ProviderDocs
+	if err := client.DecodeRegistryResponse(response, &providerDocs, logger); err != nil {
+		return "", "", fmt.Errorf("unmarshalling provider docs: %w", err)
+	}
+
+	for _, doc := range providerDocs.Docs {
+		if doc.Language != "hcl" || doc.Category != kind || doc.Slug != slug {
+			continue
+		}
+		detailResp, err := client.SendRegistryCall(ctx, httpClient, http.MethodGet, path.Join("provider-docs", doc.ID), logger, "v2")
+		if err != nil {
+			return "", "", err
+		}
+		var details client.ProviderResourceDetails
+		if err := client.DecodeRegistryResponse(detailResp, &details, logger); err != nil {
+			return "", "", fmt.Errorf("unmarshalling provider-docs/%s: %w", doc.ID, err)
+		}
+		return doc.ID, details.Data.Attributes.Content, nil
+	}
+	return "", "", utils.WithErrorCode(utils.ErrorCodeNotFound,
+		fmt.Errorf("no %s named %s_%s in %s/%s %s, use search_providers to find the resource", kind, name, slug, namespace, name, version))
+}
+
+var (
+	// argumentLine matches "* `name` - (Required) Description" in an Argument Reference
+	argumentLine = regexp.MustCompile("^[*-] +`([a-z0-9_]+)`[ \\-–:]+\\((Required|Optional)[^)]*\\)\\s*(.*)$")
+	// nestedBlockLine matches the introduction of the arguments of a nested block, e.g. "The `versioning` block supports:"
+	nestedBlockLine = regexp.MustCompile("(?i)`[a-z0-9_]+`.*\\bblocks? supports?\\b")
+)
+
+// parseArgumentReference reads the top-level arguments of the "Argument Reference" section of a
+// resource doc. Arguments of nested blocks, which follow a sub-heading or a "The `x` block
+// supports:" line, are skipped.
+func parseArgumentReference(markdown string) []CDKTFArgument {
+	var arguments []CDKTFArgument
+	seen := make(map[string]bool)
+	inSection := false
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			if inSection {
+				return arguments
+			}
+			inSection = strings.Contains(strings.ToLower(trimmed), "argument reference")
+			continue
+		case !inSection:
+			continue
+		case strings.HasPrefix(trimmed, "### "), nestedBlockLine.MatchString(trimmed):
+			return arguments
+		}
+
+		// Indented list items belong to a nested block
+		if line != strings.TrimLeft(line, " \t") {
+			continue
+		}
+		match := argumentLine.FindStringSubmatch(trimmed)
+		if match == nil || seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		arguments = append(arguments, CDKTFArgument{
+			Name:        match[1],
+			Required:    match[2] == "Required",
+			Description: strings.TrimSpace(match[3]),
+		})
+	}
+	return arguments
+}
+
+// renderCDKTFSnippet renders the construct of a resource or data source in TypeScript or Python
+func renderCDKTFSnippet(providerName, resourceType, kind, language string, arguments []CDKTFArgument) CDKTFSnippet {
+	// Data sources are generated with a Data prefix and keep the provider name, e.g. DataAwsS3Bucket
+	typeName := strings.TrimPrefix(resourceType, providerName+"_")
+	if kind == "data-sources" {
+		typeName = "data_" + resourceType
+	}
+	className := pascalCase(typeName)
+
+	snippet := CDKTFSnippet{
+		ResourceType: resourceType,
+		Kind:         kind,
+		Language:     language,
+		ClassName:    className,
+		Arguments:    make([]CDKTFArgument, 0, len(arguments)),
+	}
+
+	var builder strings.Builder
+	switch language {
+	case "python":
+		snippet.Import = fmt.Sprintf("from imports.%s.%s import %s", providerName, typeName, className)
+		builder.WriteString(snippet.Import + "\n\n")
+		builder.WriteString(fmt.Sprintf("%s(self, \"example\",\n", className))
+		for _, argument := range arguments {
+			argument.Property = argument.Name
+			snippet.Arguments = append(snippet.Arguments, argument)
+			if argument.Required {
+				builder.WriteString(fmt.Sprintf("    %s=\"<%s>\",\n", argument.Property, argument.Name))
+			} else {
+				builder.WriteString(fmt.Sprintf("    # %s=...,\n", argument.Property))
+			}
+		}
+		builder.WriteString(")\n")
+		snippet.Notes = append(snippet.Notes, "Run 'cdktf get' to generate the provider bindings under imports/, or use the prebuilt package cdktf-cdktf-provider-"+providerName+".")
+	default:
+		snippet.Import = fmt.Sprintf("import { %s } from \"./.gen/providers/%s/%s\";", className, providerName, strings.ReplaceAll(typeName, "_", "-"))
+		builder.WriteString(snippet.Import + "\n\n")
+		builder.WriteString(fmt.Sprintf("new %s(this, \"example\", {\n", className))
+		for _, argument := range arguments {
+			argument.Property = camelCase(argument.Name)
+			snippet.Arguments = append(snippet.Arguments, argument)
+			if argument.Required {
+				builder.WriteString(fmt.Sprintf("  %s: \"<%s>\",\n", argument.Property, argument.Name))
+			} else {
+				builder.WriteString(fmt.Sprintf("  // %s: ...,\n", argument.Property))
+			}
+		}
+		builder.WriteString("});\n")
+		snippet.Notes = append(snippet.Notes, fmt.Sprintf("Run 'cdktf get' to generate the provider bindings under .gen/, or import from the prebuilt package @cdktf/provider-%s/lib/%s.", providerName, strings.ReplaceAll(typeName, "_", "-")))
+	}
+	snippet.Notes = append(snippet.Notes, "Placeholder values are strings; replace them with values of the type given in the provider documentation. Nested blocks are not included.")
+	snippet.Snippet = builder.String()
+	return snippet
+}
+
+// pascalCase converts a snake_case name to PascalCase, e.g. s3_bucket to S3Bucket
+func pascalCase(name string) string {
+	var builder strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return builder.String()
+}
+
+// camelCase converts a snake_case name to camelCase, e.g. force_destroy to forceDestroy
+func camelCase(name string) string {
+	pascal := pascalCase(name)
+	if pascal == "" {
+		return ""
+	}
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const s3BucketDoc = "---\n" +
+	"subcategory: \"S3 (Simple Storage)\"\n" +
+	"---\n\n" +
+	"# Resource: aws_s3_bucket\n\n" +
+	"## Example Usage\n\n" +
+	"* `ignored` - (Required) Not an argument.\n\n" +
+	"## Argument Reference\n\n" +
+	"This resource supports the following arguments:\n\n" +
+	"* `bucket` - (Required) Name of the bucket.\n" +
+	"* `force_destroy` - (Optional, Default:`false`) Delete all objects when the bucket is destroyed.\n" +
+	"  * `nested_in_list` - (Optional) Indented items belong to a nested block.\n" +
+	"* `tags` - (Optional) Map of tags to assign to the bucket.\n\n" +
+	"The `versioning` block supports:\n\n" +
+	"* `enabled` - (Optional) Enable versioning.\n\n" +
+	"## Attribute Reference\n\n" +
+	"* `arn` - (Required) Not an argument either.\n"
+
+func TestParseArgumentReference(t *testing.T) {
+	arguments := parseArgumentReference(s3BucketDoc)
+
+	var names []string
+	for _, argument := range arguments {
+		names = append(names, argument.Name)
+	}
+	if strings.Join(names, ",") != "bucket,force_destroy,tags" {
+		t.Fatalf("unexpected arguments %v", names)
+	}
+	if !arguments[0].Required || arguments[1].Required {
+		t.Errorf("unexpected required flags %+v", arguments)
+	}
+	if arguments[0].Description != "Name of the bucket." {
+		t.Errorf("unexpected description %q", arguments[0].Description)
+	}
+
+	if got := parseArgumentReference("# No reference\n\n* `bucket` - (Required) Name."); len(got) != 0 {
+		t.Errorf("expected no arguments without an Argument Reference section, got %v", got)
+	}
+}
+
+func TestRenderCDKTFSnippet_TypeScript(t *testing.T) {
+	snippet := renderCDKTFSnippet("aws", "aws_s3_bucket", "resources", "typescript", parseArgumentReference(s3BucketDoc))
+
+	if snippet.ClassName != "S3Bucket" {
+		t.Errorf("unexpected class name %q", snippet.ClassName)
+	}
+	if snippet.Import != `import { S3Bucket } from "./.gen/providers/aws/s3-bucket";` {
+		t.Errorf("unexpected import %q", snippet.Import)
+	}
+	for _, want := range []string{`new S3Bucket(this, "example", {`, `bucket: "<bucket>",`, `// forceDestroy: ...,`, "});"} {
+		if !strings.Contains(snippet.Snippet, want) {
+			t.Errorf("expected the snippet to contain %q, got:\n%s", want, snippet.Snippet)
+		}
+	}
+	if snippet.Arguments[1].Property != "forceDestroy" {
+		t.Errorf("unexpected property %q", snippet.Arguments[1].Property)
+	}
+}
+
+func TestRenderCDKTFSnippet_PythonDataSource(t *testing.T) {
+	snippet := renderCDKTFSnippet("aws", "aws_s3_bucket", "data-sources", "python", []CDKTFArgument{{Name: "bucket", Required: true}})
+
+	if snippet.ClassName != "DataAwsS3Bucket" {
+		t.Errorf("unexpected class name %q", snippet.ClassName)
+	}
+	if snippet.Import != "from imports.aws.data_aws_s3_bucket import DataAwsS3Bucket" {
+		t.Errorf("unexpected import %q", snippet.Import)
+	}
+	if !strings.Contains(snippet.Snippet, `DataAwsS3Bucket(self, "example",`) || !strings.Contains(snippet.Snippet, `bucket="<bucket>",`) {
+		t.Errorf("unexpected snippet:\n%s", snippet.Snippet)
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	tests := map[string]string{"force_destroy": "forceDestroy", "bucket": "bucket", "s3_bucket": "s3Bucket", "": ""}
+	for input, want := range tests {
+		if got := camelCase(input); got != want {
+			t.Errorf("camelCase(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
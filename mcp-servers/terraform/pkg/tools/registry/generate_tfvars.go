@@ -0,0 +1,392 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TfvarsVariable is a variable of the module, as written to the tfvars skeleton
+type TfvarsVariable struct {
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	Required  bool   `json:"required"`
+	Sensitive bool   `json:"sensitive,omitempty"`
+	Answered  bool   `json:"answered"`
+}
+
+// TfvarsSkeleton is the result of the generate_tfvars tool
+type TfvarsSkeleton struct {
+	Source          string           `json:"source"`
+	Tfvars          string           `json:"tfvars"`
+	Variables       []TfvarsVariable `json:"variables"`
+	MissingRequired []string         `json:"missing_required,omitempty"`
+}
+
+// moduleVariable is a variable declared by a module, with its type and default as HCL source
+type moduleVariable struct {
+	Name        string
+	Type        string
+	Description string
+	Default     string
+	Required    bool
+	Sensitive   bool
+}
+
+func GenerateTfvars(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("generate_tfvars",
+			mcp.WithDescription(`Generates a terraform.tfvars skeleton for a module, from a registry module_id or the content of its variables.tf.
+Each variable is written with its description and type as comments: required variables are set with a placeholder matching their type and optional variables are commented out with their default.
+Values supplied in 'answers' are written instead of the placeholders and defaults. The result lists the required variables that still need a value.`),
+			mcp.WithTitleAnnotation("Generate a tfvars skeleton for the variables of a Terraform module"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("module_id",
+				mcp.Description("The registry module_id retrieved from search_modules, e.g. 'terraform-aws-modules/vpc/aws/5.8.1'. Exclusive with variables"),
+			),
+			mcp.WithString("submodule",
+				mcp.Description("Generate the variables of this submodule of module_id instead of the root module, by name or path"),
+			),
+			mcp.WithString("variables",
+				mcp.Description("The content of the variables.tf file of the module. Exclusive with module_id"),
+			),
+			mcp.WithObject("answers",
+				mcp.Description("The values already known, by variable name, e.g. {\"name\": \"main\", \"azs\": [\"eu-west-1a\"]}"),
+			),
+			mcp.WithBoolean("include_optional",
+				mcp.Description("Whether to include the optional variables, commented out with their default"),
+				mcp.DefaultBool(true),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return generateTfvarsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func generateTfvarsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	moduleID := strings.ToLower(strings.TrimSpace(request.GetString("module_id", "")))
+	submodule := strings.TrimSpace(request.GetString("submodule", ""))
+	source := request.GetString("variables", "")
+	if (moduleID == "") == (strings.TrimSpace(source) == "") {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: exactly one of module_id and variables is required", nil)
+	}
+	if submodule != "" && moduleID == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid input: submodule requires module_id", nil)
+	}
+	answers, err := parseTfvarsAnswers(request.GetArguments()["answers"])
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid answers", err)
+	}
+
+	var variables []moduleVariable
+	skeleton := TfvarsSkeleton{Source: "variables.tf"}
+	if moduleID != "" {
+		// Get a simple http client to access the public Terraform registry from context
+		httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+		if err != nil {
+			logger.WithError(err).Error("failed to get http client for public Terraform registry")
+			return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+		}
+		response, err := getModuleDetails(ctx, httpClient, moduleID, 0, logger)
+		if err != nil {
+			errMsg := fmt.Sprintf("getting module(s), none found! module_id: %v", moduleID)
+			if parts := strings.Split(moduleID, "/"); len(parts) >= 3 {
+				errMsg += didYouMean(suggestModules(ctx, httpClient, parts[1], parts[2], logger))
+			}
+			return nil, utils.LogAndReturnError(logger, errMsg, nil)
+		}
+		var module client.TerraformModuleVersionDetails
+		if err := client.DecodeRegistryResponse(response, &module, logger); err != nil {
+			return nil, utils.LogAndReturnError(logger, "unmarshalling module details", err)
+		}
+		part := module.Root
+		skeleton.Source = module.ID
+		if submodule != "" {
+			var ok bool
+			if part, ok = findModulePart(module.Submodules, submodule); !ok {
+				return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "getting the submodule",
+					fmt.Errorf("%s has no submodule %q, available submodules: %s", module.ID, submodule, modulePartNames(module.Submodules)))
+			}
+			skeleton.Source = fmt.Sprintf("%s//%s", module.ID, part.Path)
+		}
+		variables = registryModuleVariables(part.Inputs)
+	} else {
+		variables, err = parseVariablesFile(source)
+		if err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing variables", err)
+		}
+	}
+
+	var unknown []string
+	for name := range answers {
+		if !hasModuleVariable(variables, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid answers",
+			fmt.Errorf("%s declares no variable %s", skeleton.Source, strings.Join(unknown, ", ")))
+	}
+
+	skeleton.Tfvars = renderTfvars(variables, answers, request.GetBool("include_optional", true))
+	for _, variable := range variables {
+		_, answered := answers[variable.Name]
+		skeleton.Variables = append(skeleton.Variables, TfvarsVariable{
+			Name:      variable.Name,
+			Type:      variable.Type,
+			Required:  variable.Required,
+			Sensitive: variable.Sensitive,
+			Answered:  answered,
+		})
+		if variable.Required && !answered {
+			skeleton.MissingRequired = append(skeleton.MissingRequired, variable.Name)
+		}
+	}
+
+	result, err := json.Marshal(skeleton)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling the tfvars skeleton", err)
+	}
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// parseTfvarsAnswers converts the answers, an object or its JSON encoding, to HCL expressions by variable name
+func parseTfvarsAnswers(raw any) (map[string]string, error) {
+	answers := map[string]string{}
+	if raw == nil {
+		return answers, nil
+	}
+	if text, ok := raw.(string); ok {
+		if strings.TrimSpace(text) == "" {
+			return answers, nil
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+			return nil, fmt.Errorf("answers must be an object of values by variable name: %w", err)
+		}
+		raw = decoded
+	}
+	values, ok := raw.(map[string]any)
+	if !ok {
+		return nil, errors.New("answers must be an object of values by variable name")
+	}
+	for name, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("encoding the answer for %s: %w", name, err)
+		}
+		expression, err := jsonToHCL(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("converting the answer for %s: %w", name, err)
+		}
+		answers[name] = expression
+	}
+	return answers, nil
+}
+
+// registryModuleVariables converts the inputs of a registry module, whose defaults are JSON encoded
+func registryModuleVariables(inputs []client.ModuleInput) []moduleVariable {
+	variables := make([]moduleVariable, 0, len(inputs))
+	for _, input := range inputs {
+		variable := moduleVariable{
+			Name:        input.Name,
+			Type:        input.Type,
+			Description: input.Description,
+			Required:    input.Required,
+		}
+		if !input.Required {
+			variable.Default = registryDefault(input.Default)
+		}
+		variables = append(variables, variable)
+	}
+	return variables
+}
+
+// registryDefault renders the default of a registry module input, which is usually the JSON encoding of the value
+func registryDefault(value any) string {
+	if value == nil {
+		return "null"
+	}
+	if text, ok := value.(string); ok {
+		if expression, err := jsonToHCL([]byte(text)); err == nil {
+			return expression
+		}
+		return string(hclwrite.TokensForValue(cty.StringVal(text)).Bytes())
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	expression, err := jsonToHCL(encoded)
+	if err != nil {
+		return "null"
+	}
+	return expression
+}
+
+// jsonToHCL renders a JSON value as an HCL expression
+func jsonToHCL(encoded []byte) (string, error) {
+	valueType, err := ctyjson.ImpliedType(encoded)
+	if err != nil {
+		return "", err
+	}
+	value, err := ctyjson.Unmarshal(encoded, valueType)
+	if err != nil {
+		return "", err
+	}
+	return string(hclwrite.TokensForValue(value).Bytes()), nil
+}
+
+// parseVariablesFile reads the variable blocks of a variables.tf file, in the order they are declared
+func parseVariablesFile(source string) ([]moduleVariable, error) {
+	file, diags := hclsyntax.ParseConfig([]byte(source), "variables.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, errors.New("variables.tf is not HCL native syntax")
+	}
+
+	var variables []moduleVariable
+	for _, block := range body.Blocks {
+		if block.Type != "variable" || len(block.Labels) != 1 {
+			continue
+		}
+		variable := moduleVariable{Name: block.Labels[0], Required: true}
+		if attribute, ok := block.Body.Attributes["type"]; ok {
+			variable.Type = strings.TrimSpace(string(attribute.Expr.Range().SliceBytes(file.Bytes)))
+		}
+		if attribute, ok := block.Body.Attributes["description"]; ok {
+			if value, diags := attribute.Expr.Value(nil); !diags.HasErrors() && value.Type() == cty.String && value.IsKnown() && !value.IsNull() {
+				variable.Description = value.AsString()
+			}
+		}
+		if attribute, ok := block.Body.Attributes["default"]; ok {
+			variable.Default = strings.TrimSpace(string(attribute.Expr.Range().SliceBytes(file.Bytes)))
+			variable.Required = false
+		}
+		if attribute, ok := block.Body.Attributes["sensitive"]; ok {
+			if value, diags := attribute.Expr.Value(nil); !diags.HasErrors() && value.Type() == cty.Bool && value.IsKnown() && !value.IsNull() {
+				variable.Sensitive = value.True()
+			}
+		}
+		variables = append(variables, variable)
+	}
+	if len(variables) == 0 {
+		return nil, errors.New("no variable blocks found")
+	}
+	return variables, nil
+}
+
+func hasModuleVariable(variables []moduleVariable, name string) bool {
+	for _, variable := range variables {
+		if variable.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typePlaceholder is the value of a required variable without an answer, an empty value of its type
+func typePlaceholder(variableType string) string {
+	variableType = strings.ReplaceAll(variableType, " ", "")
+	switch {
+	case variableType == "string":
+		return `""`
+	case variableType == "number":
+		return "0"
+	case variableType == "bool":
+		return "false"
+	case strings.HasPrefix(variableType, "list("), strings.HasPrefix(variableType, "set("), strings.HasPrefix(variableType, "tuple("):
+		return "[]"
+	case strings.HasPrefix(variableType, "map("), strings.HasPrefix(variableType, "object("):
+		return "{}"
+	}
+	return "null"
+}
+
+// renderTfvars writes the required variables, then the optional ones commented out unless they are answered
+func renderTfvars(variables []moduleVariable, answers map[string]string, includeOptional bool) string {
+	var required, optional []moduleVariable
+	for _, variable := range variables {
+		_, answered := answers[variable.Name]
+		switch {
+		case variable.Required || answered:
+			required = append(required, variable)
+		case includeOptional:
+			optional = append(optional, variable)
+		}
+	}
+
+	var builder strings.Builder
+	writeVariable := func(variable moduleVariable) {
+		if variable.Description != "" {
+			for _, line := range strings.Split(strings.TrimSpace(variable.Description), "\n") {
+				fmt.Fprintf(&builder, "# %s\n", strings.TrimSpace(line))
+			}
+		}
+		notes := []string{}
+		if variable.Type != "" {
+			notes = append(notes, "type: "+strings.Join(strings.Fields(variable.Type), " "))
+		}
+		if variable.Required {
+			notes = append(notes, "required")
+		} else {
+			notes = append(notes, "optional")
+		}
+		if variable.Sensitive {
+			notes = append(notes, "sensitive, consider setting TF_VAR_"+variable.Name+" instead")
+		}
+		fmt.Fprintf(&builder, "# %s\n", strings.Join(notes, ", "))
+
+		if answer, ok := answers[variable.Name]; ok {
+			fmt.Fprintf(&builder, "%s = %s\n\n", variable.Name, answer)
+			return
+		}
+		if variable.Required {
+			fmt.Fprintf(&builder, "%s = %s\n\n", variable.Name, typePlaceholder(variable.Type))
+			return
+		}
+		for _, line := range strings.Split(fmt.Sprintf("%s = %s", variable.Name, variable.Default), "\n") {
+			fmt.Fprintf(&builder, "# %s\n", line)
+		}
+		builder.WriteString("\n")
+	}
+
+	if len(required) > 0 {
+		builder.WriteString("# Required variables, and the optional variables with an answer\n\n")
+		for _, variable := range required {
+			writeVariable(variable)
+		}
+	}
+	if len(optional) > 0 {
+		builder.WriteString("# Optional variables, uncomment to override their default\n\n")
+		for _, variable := range optional {
+			writeVariable(variable)
+		}
+	}
+	return strings.TrimSuffix(string(hclwrite.Format([]byte(builder.String()))), "\n") + "\n"
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+)
+
+const testVariablesFile = `
+variable "name" {
+  description = "The name of the VPC"
+  type        = string
+}
+
+variable "cidr" {
+  description = "The IPv4 CIDR block of the VPC"
+  type        = string
+  default     = "10.0.0.0/16"
+}
+
+variable "tags" {
+  description = <<-EOT
+    Tags to add to all resources.
+    Merged with the provider default tags.
+  EOT
+  type    = map(string)
+  default = {
+    team = "network"
+  }
+}
+
+variable "db_password" {
+  type      = string
+  sensitive = true
+}
+`
+
+func TestParseVariablesFile(t *testing.T) {
+	variables, err := parseVariablesFile(testVariablesFile)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(variables) != 4 {
+		t.Fatalf("expected 4 variables, got %+v", variables)
+	}
+	if !variables[0].Required || variables[1].Required || variables[1].Default != `"10.0.0.0/16"` {
+		t.Errorf("unexpected variables %+v", variables[:2])
+	}
+	if variables[2].Type != "map(string)" || !strings.Contains(variables[2].Default, `team = "network"`) {
+		t.Errorf("unexpected tags variable %+v", variables[2])
+	}
+	if !variables[3].Sensitive {
+		t.Errorf("expected db_password to be sensitive")
+	}
+
+	if _, err := parseVariablesFile(`output "id" {}`); err == nil {
+		t.Errorf("expected an error without variable blocks")
+	}
+}
+
+func TestRegistryModuleVariables(t *testing.T) {
+	variables := registryModuleVariables([]client.ModuleInput{
+		{Name: "name", Type: "string", Default: "", Required: true},
+		{Name: "cidr", Type: "string", Default: `"10.0.0.0/16"`},
+		{Name: "azs", Type: "list(string)", Default: "[]"},
+		{Name: "enable_nat_gateway", Type: "bool", Default: false},
+		{Name: "tags", Type: "map(string)", Default: map[string]any{"team": "network"}},
+		{Name: "label", Type: "string", Default: "not json"},
+	})
+	for i, want := range []string{"", `"10.0.0.0/16"`, "[]", "false", "{\n  team = \"network\"\n}", `"not json"`} {
+		if variables[i].Default != want {
+			t.Errorf("expected the default of %s to be %q, got %q", variables[i].Name, want, variables[i].Default)
+		}
+	}
+}
+
+func TestGenerateTfvars(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := GenerateTfvars(logger)
+	if tool.Tool.Name != "generate_tfvars" {
+		t.Fatalf("unexpected tool name %q", tool.Tool.Name)
+	}
+
+	generate := func(arguments map[string]any) (TfvarsSkeleton, error) {
+		result, err := generateTfvarsHandler(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "generate_tfvars", Arguments: arguments}}, logger)
+		if err != nil {
+			return TfvarsSkeleton{}, err
+		}
+		var skeleton TfvarsSkeleton
+		if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &skeleton); err != nil {
+			t.Fatalf("unexpected result: %v", err)
+		}
+		return skeleton, nil
+	}
+
+	skeleton, err := generate(map[string]any{
+		"variables": testVariablesFile,
+		"answers":   map[string]any{"name": "main", "tags": map[string]any{"team": "platform"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"# The name of the VPC\n# type: string, required\nname = \"main\"\n",
+		"# type: string, required, sensitive, consider setting TF_VAR_db_password instead\ndb_password = \"\"\n",
+		"# Tags to add to all resources.\n# Merged with the provider default tags.\n# type: map(string), optional\ntags = {\n  team = \"platform\"\n}\n",
+		"# Optional variables, uncomment to override their default\n\n# The IPv4 CIDR block of the VPC\n# type: string, optional\n# cidr = \"10.0.0.0/16\"\n",
+	} {
+		if !strings.Contains(skeleton.Tfvars, want) {
+			t.Errorf("expected the tfvars to contain %q, got:\n%s", want, skeleton.Tfvars)
+		}
+	}
+	if strings.Join(skeleton.MissingRequired, ",") != "db_password" {
+		t.Errorf("unexpected missing required variables %v", skeleton.MissingRequired)
+	}
+
+	skeleton, err = generate(map[string]any{"variables": testVariablesFile, "answers": `{"db_password": "secret"}`, "include_optional": false})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(skeleton.Tfvars, "cidr") || !strings.Contains(skeleton.Tfvars, `db_password = "secret"`) {
+		t.Errorf("unexpected tfvars:\n%s", skeleton.Tfvars)
+	}
+
+	for name, arguments := range map[string]map[string]any{
+		"no source":        {},
+		"both sources":     {"module_id": "terraform-aws-modules/vpc/aws", "variables": testVariablesFile},
+		"orphan submodule": {"variables": testVariablesFile, "submodule": "vpc-endpoints"},
+		"unknown answer":   {"variables": testVariablesFile, "answers": map[string]any{"region": "eu-west-1"}},
+		"invalid answers":  {"variables": testVariablesFile, "answers": "[1]"},
+	} {
+		if _, err := generate(arguments); utils.ErrorCodeOf(err) != utils.ErrorCodeInvalidInput {
+			t.Errorf("%s: expected an invalid input error, got %v", name, err)
+		}
+	}
+}
@@ -5,7 +5,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -45,19 +44,19 @@ func GetLatestModuleVersion(logger *log.Logger) server.ServerTool {
 func getLatestModuleVersionHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	modulePublisher, err := request.RequireString("module_publisher")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: 'module_publisher' (the publisher of the module)", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: 'module_publisher' (the publisher of the module)", err)
 	}
 	modulePublisher = strings.ToLower(modulePublisher)
 
 	moduleName, err := request.RequireString("module_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: 'module_name' (the name of the module)", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: 'module_name' (the name of the module)", err)
 	}
 	moduleName = strings.ToLower(moduleName)
 
 	moduleProvider, err := request.RequireString("module_provider")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: 'module_provider' (the provider of the module)", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: 'module_provider' (the provider of the module)", err)
 	}
 	moduleProvider = strings.ToLower(moduleProvider)
 
@@ -65,16 +64,20 @@ func getLatestModuleVersionHandler(ctx context.Context, request mcp.CallToolRequ
 	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to get http client for public Terraform registry")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
 	}
 	uri := fmt.Sprintf("modules/%s/%s/%s", modulePublisher, moduleName, moduleProvider)
-	response, err := client.SendRegistryCall(httpClient, http.MethodGet, uri, logger)
+	response, err := client.SendRegistryCall(ctx, httpClient, http.MethodGet, uri, logger)
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("fetching module information for %s/%s from the %s provider", modulePublisher, moduleName, moduleProvider), err)
+		message := fmt.Sprintf("fetching module information for %s/%s from the %s provider", modulePublisher, moduleName, moduleProvider)
+		if client.ClassifyError(err) == utils.ErrorCodeNotFound {
+			message += didYouMean(suggestModules(ctx, httpClient, moduleName, moduleProvider, logger))
+		}
+		return nil, utils.LogAndReturnError(logger, message, err)
 	}
 
 	var moduleVersionDetails client.TerraformModuleVersionDetails
-	if err := json.Unmarshal(response, &moduleVersionDetails); err != nil {
+	if err := client.DecodeRegistryResponse(response, &moduleVersionDetails, logger); err != nil {
 		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("unmarshalling module information for %s/%s from the %s provider", modulePublisher, moduleName, moduleProvider), err)
 	}
 
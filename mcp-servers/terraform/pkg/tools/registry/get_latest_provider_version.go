@@ -40,25 +40,31 @@ func GetLatestProviderVersion(logger *log.Logger) server.ServerTool {
 func getLatestProviderVersionHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	namespace, err := request.RequireString("namespace")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: namespace of the Terraform provider is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: namespace of the Terraform provider is required", err)
 	}
 	namespace = strings.ToLower(namespace)
 
 	name, err := request.RequireString("name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: name of the Terraform provider is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: name of the Terraform provider is required", err)
 	}
 	name = strings.ToLower(name)
+	namespace, name = client.ResolveProviderAlias(namespace, name, logger)
 
 	// Get a simple http client to access the public Terraform registry from context
 	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to get http client for public Terraform registry")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
 	}
 
-	version, err := client.GetLatestProviderVersion(httpClient, namespace, name, logger)
+	version, err := client.GetLatestProviderVersion(ctx, httpClient, namespace, name, logger)
 	if err != nil {
+		if client.ClassifyError(err) == utils.ErrorCodeNotFound {
+			if suggestions := suggestProviders(ctx, httpClient, namespace, name, logger); len(suggestions) > 0 {
+				return nil, utils.LogAndReturnError(logger, fmt.Sprintf("fetching latest provider version of %s/%s%s", namespace, name, didYouMean(suggestions)), err)
+			}
+		}
 		return nil, utils.LogAndReturnError(logger, "fetching latest provider version", err)
 	}
 
@@ -5,9 +5,9 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 	"strings"
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/client"
@@ -23,7 +23,8 @@ const MODULE_BASE_PATH = "registry://modules"
 func ModuleDetails(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_module_details",
-			mcp.WithDescription(`Fetches up-to-date documentation on how to use a Terraform module. You must call 'search_modules' first to obtain the exact valid and compatible module_id required to use this tool.`),
+			mcp.WithDescription(`Fetches up-to-date documentation on how to use a Terraform module. You must call 'search_modules' first to obtain the exact valid and compatible module_id required to use this tool.
+By default the inputs, outputs and provider dependencies of the root module are returned with the examples. Set 'list_module_parts' to list the submodules and examples of the module, then 'submodule' or 'example' to get only that part.`),
 			mcp.WithTitleAnnotation("Retrieve documentation for a specific Terraform module"),
 			mcp.WithOpenWorldHintAnnotation(true),
 			mcp.WithReadOnlyHintAnnotation(true),
@@ -32,6 +33,17 @@ func ModuleDetails(logger *log.Logger) server.ServerTool {
 				mcp.Required(),
 				mcp.Description("Exact valid and compatible module_id retrieved from search_modules (e.g., 'squareops/terraform-kubernetes-mongodb/mongodb/2.1.1', 'GoogleCloudPlatform/vertex-ai/google/0.2.0')"),
 			),
+			mcp.WithString("submodule",
+				mcp.Description("Only return this submodule, by name or path, e.g. 'vpc-endpoints' or 'modules/vpc-endpoints'"),
+			),
+			mcp.WithString("example",
+				mcp.Description("Only return this example, by name or path, e.g. 'complete' or 'examples/complete'"),
+			),
+			mcp.WithBoolean("list_module_parts",
+				mcp.Description("Only list the submodules and examples of the module, with the number of inputs and outputs of each"),
+				mcp.DefaultBool(false),
+			),
+			client.WithSummarizeArgument(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return getModuleDetailsHandler(ctx, request, logger)
@@ -42,27 +54,48 @@ func ModuleDetails(logger *log.Logger) server.ServerTool {
 func getModuleDetailsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	moduleID, err := request.RequireString("module_id")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: module_id is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: module_id is required", err)
 	}
 	if moduleID == "" {
-		return nil, utils.LogAndReturnError(logger, "required input: module_id cannot be empty", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: module_id cannot be empty", nil)
 	}
 	moduleID = strings.ToLower(moduleID)
 
+	submodule := strings.TrimSpace(request.GetString("submodule", ""))
+	example := strings.TrimSpace(request.GetString("example", ""))
+	listParts := request.GetBool("list_module_parts", false)
+	selections := 0
+	for _, selected := range []bool{submodule != "", example != "", listParts} {
+		if selected {
+			selections++
+		}
+	}
+	if selections > 1 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "selecting the module part",
+			fmt.Errorf("only one of submodule, example and list_module_parts can be set"))
+	}
+
 	// Get a simple http client to access the public Terraform registry from context
 	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to get http client for public Terraform registry")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
 	}
 
 	var errMsg string
-	response, err := getModuleDetails(httpClient, moduleID, 0, logger)
+	response, err := getModuleDetails(ctx, httpClient, moduleID, 0, logger)
 	if err != nil {
-		errMsg = fmt.Sprintf("getting module(s), none found! module_id: %v,", moduleID)
+		errMsg = fmt.Sprintf("getting module(s), none found! module_id: %v", moduleID)
+		// module_id has the format namespace/name/provider/version
+		if parts := strings.Split(moduleID, "/"); len(parts) >= 3 {
+			errMsg += didYouMean(suggestModules(ctx, httpClient, parts[1], parts[2], logger))
+		}
 		return nil, utils.LogAndReturnError(logger, errMsg, nil)
 	}
-	moduleData, err := unmarshalTerraformModule(response)
+	if selections > 0 {
+		return getModulePartHandler(response, submodule, example, logger)
+	}
+	moduleData, err := unmarshalTerraformModule(response, logger)
 	if err != nil {
 		return nil, utils.LogAndReturnError(logger, "unmarshalling module details", err)
 	}
@@ -70,17 +103,23 @@ func getModuleDetailsHandler(ctx context.Context, request mcp.CallToolRequest, l
 		errMsg = fmt.Sprintf("getting module(s), none found! %s please provider a different moduleProvider", errMsg)
 		return nil, utils.LogAndReturnError(logger, errMsg, nil)
 	}
-	return mcp.NewToolResultText(moduleData), nil
+	client.IndexInBackground(ctx, logger, client.SemanticDocument{
+		ID:    moduleID,
+		Kind:  client.SemanticKindModule,
+		Title: moduleID,
+		Text:  moduleData,
+	})
+	return mcp.NewToolResultText(client.SummarizeDocument(ctx, request, fmt.Sprintf("module %s", moduleID), moduleData, logger)), nil
 }
 
-func getModuleDetails(httpClient *http.Client, moduleID string, currentOffset int, logger *log.Logger) ([]byte, error) {
+func getModuleDetails(ctx context.Context, httpClient *http.Client, moduleID string, currentOffset int, logger *log.Logger) ([]byte, error) {
 	uri := "modules"
 	if moduleID != "" {
 		uri = fmt.Sprintf("modules/%s", moduleID)
 	}
 
 	uri = fmt.Sprintf("%s?offset=%v", uri, currentOffset)
-	response, err := client.SendRegistryCall(httpClient, "GET", uri, logger)
+	response, err := client.SendRegistryCall(ctx, httpClient, "GET", uri, logger)
 	if err != nil {
 		// We shouldn't log the error here because we might hit a namespace that doesn't exist, it's better to let the caller handle it.
 		return nil, fmt.Errorf("getting module(s) for: %v, please provide a different provider name like aws, azurerm or google etc", moduleID)
@@ -90,12 +129,12 @@ func getModuleDetails(httpClient *http.Client, moduleID string, currentOffset in
 	return response, nil
 }
 
-func unmarshalTerraformModule(response []byte) (string, error) {
+func unmarshalTerraformModule(response []byte, logger *log.Logger) (string, error) {
 	// Handles one module
 	var terraformModules client.TerraformModuleVersionDetails
-	err := json.Unmarshal(response, &terraformModules)
+	err := client.DecodeRegistryResponse(response, &terraformModules, logger)
 	if err != nil {
-		return "", utils.LogAndReturnError(nil, "unmarshalling module details", err)
+		return "", utils.LogAndReturnError(logger, "unmarshalling module details", err)
 	}
 
 	var builder strings.Builder
@@ -105,12 +144,40 @@ func unmarshalTerraformModule(response []byte) (string, error) {
 	builder.WriteString(fmt.Sprintf("**Namespace:** %s\n\n", terraformModules.Namespace))
 	builder.WriteString(fmt.Sprintf("**Source:** %s\n\n", terraformModules.Source))
 
+	writeModuleInterface(&builder, terraformModules.Root)
+
+	// Format Examples
+	if len(terraformModules.Examples) > 0 {
+		builder.WriteString("### Examples\n\n")
+		for _, example := range terraformModules.Examples {
+			builder.WriteString(fmt.Sprintf("#### %s\n\n", example.Name))
+			// Optionally, include more details from example if needed, like inputs/outputs
+			// For now, just listing the name.
+			if example.Readme != "" {
+				builder.WriteString("**Readme:**\n\n")
+				// The readme is nested under the heading of the example
+				builder.WriteString(utils.SanitizeMarkdown(example.Readme, utils.MarkdownOptions{
+					BaseURL:         fmt.Sprintf("%s/modules/%s/", client.RegistryAddress(), terraformModules.ID),
+					TopHeadingLevel: 5,
+				}))
+				builder.WriteString("\n\n")
+			}
+		}
+		builder.WriteString("\n")
+	}
+
+	content := builder.String()
+	return content, nil
+}
+
+// writeModuleInterface writes the inputs, outputs and provider dependencies of a module part
+func writeModuleInterface(builder *strings.Builder, part client.ModulePart) {
 	// Format Inputs
-	if len(terraformModules.Root.Inputs) > 0 {
+	if len(part.Inputs) > 0 {
 		builder.WriteString("### Inputs\n\n")
 		builder.WriteString("| Name | Type | Description | Default | Required |\n")
 		builder.WriteString("|---|---|---|---|---|\n")
-		for _, input := range terraformModules.Root.Inputs {
+		for _, input := range part.Inputs {
 			builder.WriteString(fmt.Sprintf("| %s | %s | %s | `%v` | %t |\n",
 				input.Name,
 				input.Type,
@@ -123,11 +190,11 @@ func unmarshalTerraformModule(response []byte) (string, error) {
 	}
 
 	// Format Outputs
-	if len(terraformModules.Root.Outputs) > 0 {
+	if len(part.Outputs) > 0 {
 		builder.WriteString("### Outputs\n\n")
 		builder.WriteString("| Name | Description |\n")
 		builder.WriteString("|---|---|\n")
-		for _, output := range terraformModules.Root.Outputs {
+		for _, output := range part.Outputs {
 			builder.WriteString(fmt.Sprintf("| %s | %s |\n",
 				output.Name,
 				output.Description, // Consider cleaning potential newlines/markdown
@@ -137,11 +204,11 @@ func unmarshalTerraformModule(response []byte) (string, error) {
 	}
 
 	// Format Provider Dependencies
-	if len(terraformModules.Root.ProviderDependencies) > 0 {
+	if len(part.ProviderDependencies) > 0 {
 		builder.WriteString("### Provider Dependencies\n\n")
 		builder.WriteString("| Name | Namespace | Source | Version |\n")
 		builder.WriteString("|---|---|---|---|\n")
-		for _, dep := range terraformModules.Root.ProviderDependencies {
+		for _, dep := range part.ProviderDependencies {
 			builder.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
 				dep.Name,
 				dep.Namespace,
@@ -151,24 +218,102 @@ func unmarshalTerraformModule(response []byte) (string, error) {
 		}
 		builder.WriteString("\n")
 	}
+}
 
-	// Format Examples
-	if len(terraformModules.Examples) > 0 {
-		builder.WriteString("### Examples\n\n")
-		for _, example := range terraformModules.Examples {
-			builder.WriteString(fmt.Sprintf("#### %s\n\n", example.Name))
-			// Optionally, include more details from example if needed, like inputs/outputs
-			// For now, just listing the name.
-			if example.Readme != "" {
-				builder.WriteString("**Readme:**\n\n")
-				// Append readme content, potentially needs markdown escaping/sanitization depending on source
-				builder.WriteString(example.Readme)
-				builder.WriteString("\n\n")
-			}
+// getModulePartHandler returns the submodule or the example requested, or the list of the parts of the module
+func getModulePartHandler(response []byte, submodule, example string, logger *log.Logger) (*mcp.CallToolResult, error) {
+	var module client.TerraformModuleVersionDetails
+	if err := client.DecodeRegistryResponse(response, &module, logger); err != nil {
+		return nil, utils.LogAndReturnError(logger, "unmarshalling module details", err)
+	}
+
+	switch {
+	case submodule != "":
+		part, ok := findModulePart(module.Submodules, submodule)
+		if !ok {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "getting the submodule",
+				fmt.Errorf("%s has no submodule %q, available submodules: %s", module.ID, submodule, modulePartNames(module.Submodules)))
+		}
+		return mcp.NewToolResultText(formatModulePart(module, "Submodule", part)), nil
+	case example != "":
+		part, ok := findModulePart(module.Examples, example)
+		if !ok {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "getting the example",
+				fmt.Errorf("%s has no example %q, available examples: %s", module.ID, example, modulePartNames(module.Examples)))
+		}
+		return mcp.NewToolResultText(formatModulePart(module, "Example", part)), nil
+	}
+	return mcp.NewToolResultText(formatModuleParts(module)), nil
+}
+
+// findModulePart looks a submodule or an example up by its path, its name or the last element of its path
+func findModulePart(parts []client.ModulePart, name string) (client.ModulePart, bool) {
+	name = strings.Trim(strings.ToLower(name), "/")
+	for _, part := range parts {
+		partPath := strings.Trim(strings.ToLower(part.Path), "/")
+		if partPath == name || strings.ToLower(part.Name) == name || path.Base(partPath) == name {
+			return part, true
+		}
+	}
+	return client.ModulePart{}, false
+}
+
+// modulePartNames lists the names of the parts of a module for error messages
+func modulePartNames(parts []client.ModulePart) string {
+	if len(parts) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		names = append(names, part.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatModuleParts lists the submodules and examples of a module with the size of their interface
+func formatModuleParts(module client.TerraformModuleVersionDetails) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s/%s/%s\n\n", MODULE_BASE_PATH, module.Namespace, module.Name))
+	builder.WriteString(fmt.Sprintf("**Module Version:** %s\n\n", module.Version))
+	builder.WriteString("Call get_module_details again with 'submodule' or 'example' set to a name below to get only that part.\n\n")
+
+	for _, group := range []struct {
+		heading string
+		parts   []client.ModulePart
+	}{{"Submodules", module.Submodules}, {"Examples", module.Examples}} {
+		builder.WriteString(fmt.Sprintf("### %s\n\n", group.heading))
+		if len(group.parts) == 0 {
+			builder.WriteString("None\n\n")
+			continue
+		}
+		for _, part := range group.parts {
+			builder.WriteString(fmt.Sprintf("- %s (path: %s): %d inputs, %d outputs\n", part.Name, part.Path, len(part.Inputs), len(part.Outputs)))
 		}
 		builder.WriteString("\n")
 	}
+	return builder.String()
+}
 
-	content := builder.String()
-	return content, nil
+// formatModulePart renders the interface and the readme of a submodule or an example
+func formatModulePart(module client.TerraformModuleVersionDetails, kind string, part client.ModulePart) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s/%s/%s//%s\n\n", MODULE_BASE_PATH, module.Namespace, module.Name, part.Path))
+	builder.WriteString(fmt.Sprintf("**%s:** %s\n\n", kind, part.Name))
+	builder.WriteString(fmt.Sprintf("**Module Version:** %s\n\n", module.Version))
+	if kind == "Submodule" {
+		// Submodules are called with the registry source of the module and their path
+		builder.WriteString(fmt.Sprintf("**Source:** %s/%s/%s//%s\n\n", module.Namespace, module.Name, module.Provider, part.Path))
+	}
+
+	writeModuleInterface(&builder, part)
+
+	if part.Readme != "" {
+		builder.WriteString("### Readme\n\n")
+		builder.WriteString(utils.SanitizeMarkdown(part.Readme, utils.MarkdownOptions{
+			BaseURL:         fmt.Sprintf("%s/modules/%s/", client.RegistryAddress(), module.ID),
+			TopHeadingLevel: 4,
+		}))
+		builder.WriteString("\n")
+	}
+	return builder.String()
 }
@@ -6,6 +6,10 @@ package tools
 import (
 	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
 )
 
 // --- UnmarshalModuleSingular ---
@@ -49,7 +53,7 @@ func TestUnmarshalModuleSingular_ValidAllFields(t *testing.T) {
 		"versions": ["1.0.0"],
 		"deprecation": null
 	}`)
-	out, err := unmarshalTerraformModule(resp)
+	out, err := unmarshalTerraformModule(resp, log.New())
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -96,7 +100,7 @@ func TestUnmarshalModuleSingular_EmptySections(t *testing.T) {
 		"versions": ["1.0.0"],
 		"deprecation": null
 	}`)
-	out, err := unmarshalTerraformModule(resp)
+	out, err := unmarshalTerraformModule(resp, log.New())
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -107,8 +111,76 @@ func TestUnmarshalModuleSingular_EmptySections(t *testing.T) {
 
 func TestUnmarshalModuleSingular_InvalidJSON(t *testing.T) {
 	resp := []byte(`not a json`)
-	_, err := unmarshalTerraformModule(resp)
+	_, err := unmarshalTerraformModule(resp, log.New())
 	if err == nil || !strings.Contains(err.Error(), "unmarshalling module details") {
 		t.Errorf("expected unmarshalling error, got %v", err)
 	}
 }
+
+// --- module parts ---
+func TestModuleParts(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	resp := []byte(`{
+		"id": "terraform-aws-modules/vpc/aws/5.0.0",
+		"namespace": "terraform-aws-modules",
+		"name": "vpc",
+		"version": "5.0.0",
+		"provider": "aws",
+		"root": {"path": "", "name": "vpc", "inputs": [{"name": "cidr"}]},
+		"submodules": [
+			{"path": "modules/vpc-endpoints", "name": "vpc-endpoints", "readme": "# VPC endpoints", "inputs": [{"name": "vpc_id", "type": "string", "required": true}], "outputs": [{"name": "endpoints"}]}
+		],
+		"examples": [
+			{"path": "examples/complete", "name": "complete", "inputs": [], "outputs": []}
+		]
+	}`)
+
+	result, err := getModulePartHandler(resp, "", "", logger)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{"- vpc-endpoints (path: modules/vpc-endpoints): 1 inputs, 1 outputs", "- complete (path: examples/complete): 0 inputs, 0 outputs"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the parts list to contain %q, got %q", want, out)
+		}
+	}
+
+	for _, name := range []string{"vpc-endpoints", "modules/vpc-endpoints", "/modules/VPC-Endpoints"} {
+		result, err := getModulePartHandler(resp, name, "", logger)
+		if err != nil {
+			t.Fatalf("expected no error for %q, got %v", name, err)
+		}
+		out := result.Content[0].(mcp.TextContent).Text
+		if !strings.Contains(out, "**Source:** terraform-aws-modules/vpc/aws//modules/vpc-endpoints") || !strings.Contains(out, "| vpc_id | string |") {
+			t.Errorf("unexpected submodule details %q", out)
+		}
+		if strings.Contains(out, "| cidr |") {
+			t.Errorf("expected only the submodule inputs, got %q", out)
+		}
+	}
+
+	if _, err := getModulePartHandler(resp, "", "complete", logger); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	_, err = getModulePartHandler(resp, "", "basic", logger)
+	if err == nil || utils.ErrorCodeOf(err) != utils.ErrorCodeNotFound || !strings.Contains(err.Error(), "available examples: complete") {
+		t.Errorf("expected a not found error listing the examples, got %v", err)
+	}
+}
+
+func FuzzUnmarshalTerraformModule(f *testing.F) {
+	f.Add([]byte(`{"id": "ns/name/aws/1.0.0", "namespace": "ns", "name": "name", "root": {"inputs": [{"name": "a", "default": {"b": [1]}}]},
+		"submodules": [{"path": "modules/x", "name": "x"}], "examples": [{"path": "examples/y", "name": "y", "readme": "# Y\n[link](./z)"}]}`), "x", "")
+	f.Add([]byte(`{"examples": [{"path": "", "name": ""}], "submodules": null}`), "", "/")
+	f.Add([]byte(`{"root": "not an object", "published_at": 1}`), "", "")
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	f.Fuzz(func(t *testing.T, response []byte, submodule, example string) {
+		// A malformed registry response or a hostile part name fails with an error but never panics
+		_, _ = unmarshalTerraformModule(response, logger)
+		_, _ = getModulePartHandler(response, submodule, example, logger)
+	})
+}
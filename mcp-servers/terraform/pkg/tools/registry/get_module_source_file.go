@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxModuleSourceFileSize is the number of bytes of a source file returned to the agent
+const maxModuleSourceFileSize = 256 * 1024
+
+// ModuleSourceFile is a file from the source repository of a module
+type ModuleSourceFile struct {
+	ModuleID   string `json:"module_id"`
+	Repository string `json:"repository"`
+	Ref        string `json:"ref,omitempty"`
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	SHA        string `json:"sha"`
+	Content    string `json:"content"`
+	Truncated  bool   `json:"truncated"`
+}
+
+func GetModuleSourceFile(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("get_module_source_file",
+			mcp.WithDescription(`Fetches a file from the GitHub repository a Terraform module version was published from, at the tag of that version, e.g. 'main.tf' or 'modules/vpc-endpoints/variables.tf'. Use 'list_module_source_tree' to find the paths. Files larger than 256 KB are truncated and binary files are rejected.`),
+			mcp.WithTitleAnnotation("Read a source file of a Terraform module"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("module_id",
+				mcp.Required(),
+				mcp.Description("Exact valid and compatible module_id retrieved from search_modules (e.g., 'terraform-aws-modules/vpc/aws/5.1.0')"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Path of the file inside the repository, e.g. 'main.tf'"),
+			),
+			mcp.WithString("ref",
+				mcp.Description("Git branch, tag or commit to read instead of the tag of the module version"),
+				mcp.DefaultString(""),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return getModuleSourceFileHandler(ctx, request, logger)
+		},
+	}
+}
+
+func getModuleSourceFileHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	moduleID, err := request.RequireString("module_id")
+	if err != nil || moduleID == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: module_id is required", err)
+	}
+	rawPath, err := request.RequireString("path")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: path is required", err)
+	}
+	sourcePath, err := cleanSourcePath(rawPath)
+	if err != nil || sourcePath == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid path", err)
+	}
+
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "failed to get http client for the module source", err)
+	}
+
+	source, err := resolveModuleSource(ctx, httpClient, moduleID, request.GetString("ref", ""), logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "resolving the module source", err)
+	}
+
+	contents, err := client.GetGitHubContents(ctx, httpClient, source.Repository, sourcePath, source.Ref, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("fetching %q from %s", sourcePath, source.Repository), err)
+	}
+
+	file, err := moduleSourceFile(source, sourcePath, contents)
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "reading the module source file", err)
+	}
+
+	fileJSON, err := json.Marshal(file)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling module source file", err)
+	}
+	return mcp.NewToolResultText(string(fileJSON)), nil
+}
+
+// moduleSourceFile decodes the file returned by the GitHub contents API
+func moduleSourceFile(source moduleSource, sourcePath string, contents []client.GitHubContent) (ModuleSourceFile, error) {
+	if len(contents) != 1 || contents[0].Type != "file" || contents[0].Path != sourcePath {
+		return ModuleSourceFile{}, fmt.Errorf("%q is not a file, use list_module_source_tree to list a directory", sourcePath)
+	}
+	entry := contents[0]
+
+	// The contents API only inlines files up to 1 MB, larger files have an encoding of "none"
+	if entry.Encoding != "base64" {
+		return ModuleSourceFile{}, fmt.Errorf("%q is too large to be fetched (%d bytes)", sourcePath, entry.Size)
+	}
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(entry.Content, "\n", ""))
+	if err != nil {
+		return ModuleSourceFile{}, fmt.Errorf("decoding %q: %w", sourcePath, err)
+	}
+
+	truncated := false
+	if len(content) > maxModuleSourceFileSize {
+		content = content[:maxModuleSourceFileSize]
+		// Do not cut a multi-byte character in half
+		for i := 0; i < utf8.UTFMax-1 && !utf8.Valid(content); i++ {
+			content = content[:len(content)-1]
+		}
+		truncated = true
+	}
+	if !utf8.Valid(content) || strings.ContainsRune(string(content), 0) {
+		return ModuleSourceFile{}, fmt.Errorf("%q is a binary file", sourcePath)
+	}
+
+	return ModuleSourceFile{
+		ModuleID:   source.ModuleID,
+		Repository: source.Repository.String(),
+		Ref:        source.Ref,
+		Path:       entry.Path,
+		Size:       entry.Size,
+		SHA:        entry.SHA,
+		Content:    string(content),
+		Truncated:  truncated,
+	}, nil
+}
@@ -5,7 +5,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
@@ -31,6 +30,7 @@ func PolicyDetails(logger *log.Logger) server.ServerTool {
 				mcp.Required(),
 				mcp.Description("Matching terraform_policy_id retrieved from the 'search_policies' tool (e.g., 'policies/hashicorp/CIS-Policy-Set-for-AWS-Terraform/1.0.1')"),
 			),
+			client.WithSummarizeArgument(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return getPolicyDetailsHandler(ctx, request, logger)
@@ -41,29 +41,33 @@ func PolicyDetails(logger *log.Logger) server.ServerTool {
 func getPolicyDetailsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	terraformPolicyID, err := request.RequireString("terraform_policy_id")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: terraform_policy_id is required and must be a string, it is fetched by running the search_policies tool", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: terraform_policy_id is required and must be a string, it is fetched by running the search_policies tool", err)
 	}
 	if terraformPolicyID == "" {
-		return nil, utils.LogAndReturnError(logger, "required input: terraform_policy_id cannot be empty, it is fetched by running the search_policies tool", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: terraform_policy_id cannot be empty, it is fetched by running the search_policies tool", nil)
 	}
 
 	// Get a simple http client to access the public Terraform registry from context
 	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to get http client for public Terraform registry")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
 	}
-	policyResp, err := client.SendRegistryCall(httpClient, "GET", (&url.URL{Path: terraformPolicyID, RawQuery: url.Values{"include": {"policies,policy-modules,policy-library"}}.Encode()}).String(), logger, "v2")
+	policyResp, err := client.SendRegistryCall(ctx, httpClient, "GET", (&url.URL{Path: terraformPolicyID, RawQuery: url.Values{"include": {"policies,policy-modules,policy-library"}}.Encode()}).String(), logger, "v2")
 	if err != nil {
 		return nil, utils.LogAndReturnError(logger, "fetching policy details: registry API did not return a successful response", err)
 	}
 
 	var policyDetails client.TerraformPolicyDetails
-	if err := json.Unmarshal(policyResp, &policyDetails); err != nil {
+	if err := client.DecodeRegistryResponse(policyResp, &policyDetails, logger); err != nil {
 		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("unmarshalling policy details for %s", terraformPolicyID), err)
 	}
 
-	readme := utils.ExtractReadme(policyDetails.Data.Attributes.Readme)
+	readme := utils.SanitizeMarkdown(utils.ExtractReadme(policyDetails.Data.Attributes.Readme), utils.MarkdownOptions{
+		BaseURL:         fmt.Sprintf("%s/%s/", client.RegistryAddress(), strings.Trim(terraformPolicyID, "/")),
+		TopHeadingLevel: 3,
+	})
+	readme = client.SummarizeDocument(ctx, request, fmt.Sprintf("the README of policy set %s", terraformPolicyID), readme, logger)
 	var builder strings.Builder
 	builder.WriteString(fmt.Sprintf("## Policy details about %s \n\n%s", terraformPolicyID, readme))
 	policyList := ""
@@ -74,19 +78,21 @@ func getPolicyDetailsHandler(ctx context.Context, request mcp.CallToolRequest, l
 			var moduleBuilder strings.Builder
 			tmpl := `
 module "{{.Name}}" {
-	source = "https://registry.terraform.io/v2{{.PolicyID}}/policy-module/{{.Name}}.sentinel?checksum=sha256:{{.Shasum}}"
+	source = "{{.RegistryURL}}/v2{{.PolicyID}}/policy-module/{{.Name}}.sentinel?checksum=sha256:{{.Shasum}}"
 }
 `
 			type moduleData struct {
-				Name     string
-				PolicyID string
-				Shasum   string
+				Name        string
+				RegistryURL string
+				PolicyID    string
+				Shasum      string
 			}
 			t := template.Must(template.New("module").Parse(tmpl))
 			err := t.Execute(&moduleBuilder, moduleData{
-				Name:     policy.Attributes.Name,
-				PolicyID: terraformPolicyID,
-				Shasum:   policy.Attributes.Shasum,
+				Name:        policy.Attributes.Name,
+				RegistryURL: client.RegistryAddress(),
+				PolicyID:    terraformPolicyID,
+				Shasum:      policy.Attributes.Shasum,
 			})
 			if err != nil {
 				logger.WithError(err).Error("failed to render module template")
@@ -109,18 +115,20 @@ module "{{.Name}}" {
 {{ .ModuleList }}
 {{- end }}
 policy "<<POLICY_NAME>>" {
-  source = "https://registry.terraform.io/v2{{ .TerraformPolicyID }}/policy/<<POLICY_NAME>>.sentinel?checksum=<<POLICY_CHECKSUM>>"
+  source = "{{ .RegistryURL }}/v2{{ .TerraformPolicyID }}/policy/<<POLICY_NAME>>.sentinel?checksum=<<POLICY_CHECKSUM>>"
   enforcement_level = "advisory"
 }
 `
 	type hclTemplateData struct {
 		ModuleList        string
+		RegistryURL       string
 		TerraformPolicyID string
 	}
 	var hclBuilder strings.Builder
 	t := template.Must(template.New("hclPolicy").Parse(hclTmpl))
 	err = t.Execute(&hclBuilder, hclTemplateData{
 		ModuleList:        moduleList,
+		RegistryURL:       client.RegistryAddress(),
 		TerraformPolicyID: terraformPolicyID,
 	})
 	if err != nil {
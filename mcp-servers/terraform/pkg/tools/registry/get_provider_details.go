@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"path"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/client"
 	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
@@ -22,7 +23,8 @@ func GetProviderDocs(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("get_provider_details",
 			mcp.WithDescription(`Fetches up-to-date documentation for a specific service from a Terraform provider. 
-You must call 'search_providers' tool first to obtain the exact tfprovider-compatible provider_doc_id required to use this tool.`),
+You must call 'search_providers' tool first to obtain the exact tfprovider-compatible provider_doc_id required to use this tool.
+Documents of provider-defined functions are returned as a JSON signature: the name, the provider::<provider>::<name> call, the parameters with their types, the return type and an example.`),
 			mcp.WithTitleAnnotation("Fetch detailed Terraform provider documentation using a document ID"),
 			mcp.WithOpenWorldHintAnnotation(true),
 			mcp.WithReadOnlyHintAnnotation(true),
@@ -30,6 +32,11 @@ You must call 'search_providers' tool first to obtain the exact tfprovider-compa
 			mcp.WithString("provider_doc_id",
 				mcp.Required(),
 				mcp.Description("Exact tfprovider-compatible provider_doc_id, (e.g., '8894603', '8906901') retrieved from 'search_providers'")),
+			mcp.WithString("language",
+				mcp.Description("The expected language of the document, e.g. 'typescript' or 'python' for CDK for Terraform. The document is only returned when it matches; leave it empty to accept any language"),
+				mcp.Enum(utils.ProviderDocLanguages...),
+			),
+			client.WithSummarizeArgument(),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return getProviderDocsHandler(ctx, req, logger)
@@ -40,30 +47,60 @@ You must call 'search_providers' tool first to obtain the exact tfprovider-compa
 func getProviderDocsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	providerDocID, err := request.RequireString("provider_doc_id")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: provider_doc_id is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: provider_doc_id is required", err)
 	}
 	if providerDocID == "" {
-		return nil, utils.LogAndReturnError(logger, "required input: provider_doc_id cannot be empty", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: provider_doc_id cannot be empty", nil)
 	}
 	if _, err := strconv.Atoi(providerDocID); err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: provider_doc_id must be a valid number", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: provider_doc_id must be a valid number", err)
 	}
 
 	// Get a simple http client to access the public Terraform registry from context
 	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to get http client for public Terraform registry")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
 	}
 
-	detailResp, err := client.SendRegistryCall(httpClient, "GET", path.Join("provider-docs", providerDocID), logger, "v2")
+	detailResp, err := client.SendRegistryCall(ctx, httpClient, "GET", path.Join("provider-docs", providerDocID), logger, "v2")
 	if err != nil {
 		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("fetching provider-docs/%s, please make sure provider_doc_id is valid and the search_providers tool has run prior", providerDocID), err)
 	}
 
 	var details client.ProviderResourceDetails
-	if err := json.Unmarshal(detailResp, &details); err != nil {
+	if err := client.DecodeRegistryResponse(detailResp, &details, logger); err != nil {
 		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("unmarshalling provider-docs/%s", providerDocID), err)
 	}
-	return mcp.NewToolResultText(details.Data.Attributes.Content), nil
+
+	// Document IDs are specific to a language, so a mismatch means search_providers ran with another language
+	if language := strings.ToLower(request.GetString("language", "")); language != "" && details.Data.Attributes.Language != language {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("checking the language of provider-docs/%s", providerDocID),
+			fmt.Errorf("the document is written in %s, call search_providers with language '%s' to get the %s document ID", details.Data.Attributes.Language, language, language))
+	}
+	content := utils.SanitizeMarkdown(details.Data.Attributes.Content, utils.MarkdownOptions{
+		BaseURL:         client.RegistryAddress() + "/",
+		TopHeadingLevel: 1,
+	})
+	client.IndexInBackground(ctx, logger, client.SemanticDocument{
+		ID:    providerDocID,
+		Kind:  client.SemanticKindProviderDoc,
+		Title: fmt.Sprintf("%s (%s)", details.Data.Attributes.Title, details.Data.Attributes.Category),
+		Text:  content,
+	})
+
+	// Function docs are returned as a structured signature, falling back to the markdown when it cannot be parsed
+	if details.Data.Attributes.Category == "functions" {
+		signature, err := parseFunctionSignature(content)
+		if err != nil {
+			logger.Debugf("Parsing the function signature of provider-docs/%s: %v", providerDocID, err)
+			return mcp.NewToolResultText(content), nil
+		}
+		signatureJSON, err := json.Marshal(signature)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "marshalling function signature", err)
+		}
+		return mcp.NewToolResultText(string(signatureJSON)), nil
+	}
+	return mcp.NewToolResultText(client.SummarizeDocument(ctx, request, fmt.Sprintf("%s (%s)", details.Data.Attributes.Title, details.Data.Attributes.Category), content, logger)), nil
 }
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// ModuleSourceEntry is a file or directory in the source repository of a module
+type ModuleSourceEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Size int64  `json:"size,omitempty"`
+}
+
+// ModuleSourceTree lists a directory of the source repository of a module
+type ModuleSourceTree struct {
+	ModuleID   string              `json:"module_id"`
+	Repository string              `json:"repository"`
+	Ref        string              `json:"ref,omitempty"`
+	Path       string              `json:"path"`
+	Entries    []ModuleSourceEntry `json:"entries"`
+}
+
+func ListModuleSourceTree(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_module_source_tree",
+			mcp.WithDescription(`Lists the files and directories of the GitHub repository a Terraform module version was published from, at the tag of that version. Use it with 'get_module_source_file' to inspect code that the registry documentation omits. Only modules hosted on GitHub are supported.`),
+			mcp.WithTitleAnnotation("List the source files of a Terraform module"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("module_id",
+				mcp.Required(),
+				mcp.Description("Exact valid and compatible module_id retrieved from search_modules (e.g., 'terraform-aws-modules/vpc/aws/5.1.0')"),
+			),
+			mcp.WithString("path",
+				mcp.Description("Directory inside the repository to list, e.g. 'modules/vpc-endpoints'. Defaults to the repository root"),
+				mcp.DefaultString(""),
+			),
+			mcp.WithString("ref",
+				mcp.Description("Git branch, tag or commit to read instead of the tag of the module version"),
+				mcp.DefaultString(""),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listModuleSourceTreeHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listModuleSourceTreeHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	moduleID, err := request.RequireString("module_id")
+	if err != nil || moduleID == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: module_id is required", err)
+	}
+	sourcePath, err := cleanSourcePath(request.GetString("path", ""))
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid path", err)
+	}
+
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "failed to get http client for the module source", err)
+	}
+
+	source, err := resolveModuleSource(ctx, httpClient, moduleID, request.GetString("ref", ""), logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "resolving the module source", err)
+	}
+
+	contents, err := client.GetGitHubContents(ctx, httpClient, source.Repository, sourcePath, source.Ref, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("listing %q in %s", sourcePath, source.Repository), err)
+	}
+	if len(contents) == 1 && contents[0].Type != "dir" && contents[0].Path == sourcePath {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "listing the module source",
+			fmt.Errorf("%q is a file, use get_module_source_file to read it", sourcePath))
+	}
+
+	tree := ModuleSourceTree{
+		ModuleID:   source.ModuleID,
+		Repository: source.Repository.String(),
+		Ref:        source.Ref,
+		Path:       sourcePath,
+		Entries:    make([]ModuleSourceEntry, 0, len(contents)),
+	}
+	for _, entry := range contents {
+		tree.Entries = append(tree.Entries, ModuleSourceEntry{Name: entry.Name, Path: entry.Path, Type: entry.Type, Size: entry.Size})
+	}
+
+	treeJSON, err := json.Marshal(tree)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling module source tree", err)
+	}
+	return mcp.NewToolResultText(string(treeJSON)), nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func ListPopularModules(logger *log.Logger) server.ServerTool {
+	options := []mcp.ToolOption{
+		mcp.WithDescription(`Lists the most downloaded Terraform modules, optionally for one provider and one category such as 'vpc', 'eks' or 'storage-account'.
+Use it to answer questions like "what is the standard VPC module?" without guessing a search query, then call 'get_module_details' with the module_id of the best match.`),
+		mcp.WithTitleAnnotation("List the most popular Terraform modules"),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("provider",
+			mcp.Description("Only return modules for this provider, e.g. 'aws', 'azurerm' or 'google'"),
+			mcp.DefaultString(""),
+		),
+		mcp.WithString("category",
+			mcp.Description("Only return modules matching this category or keyword, e.g. 'vpc', 'kubernetes' or 'iam'"),
+			mcp.DefaultString(""),
+		),
+	}
+	options = append(options, withPopularityFilters("modules", false)...)
+
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_popular_modules", options...),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listPopularModulesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listPopularModulesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	provider := strings.ToLower(strings.TrimSpace(request.GetString("provider", "")))
+	category := strings.ToLower(strings.TrimSpace(request.GetString("category", "")))
+	if provider != "" {
+		_, provider = client.ResolveProviderAlias("", provider, logger)
+	}
+	verifiedOnly := request.GetBool("verified_only", false)
+	minDownloads := int64(request.GetInt("min_downloads", 0))
+	limit := popularLimit(request.GetInt("limit", defaultPopularLimit))
+
+	// Get a simple http client to access the public Terraform registry from context
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		logger.WithError(err).Error("failed to get http client for public Terraform registry")
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+	}
+
+	modules, err := fetchPopularModules(ctx, httpClient, provider, category, verifiedOnly, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing modules", err)
+	}
+	rankModules(&modules, minDownloads, limit)
+	if len(modules.Data) == 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "listing modules",
+			fmt.Errorf("no modules found for provider %q and category %q, relax the filters or use search_modules", provider, category))
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Most downloaded Terraform modules")
+	if provider != "" {
+		builder.WriteString(fmt.Sprintf(" for the %s provider", provider))
+	}
+	if category != "" {
+		builder.WriteString(fmt.Sprintf(" matching %q", category))
+	}
+	builder.WriteString("\n\nEach result includes:\n")
+	builder.WriteString("- module_id: The module ID (format: namespace/name/provider-name/module-version)\n")
+	builder.WriteString("- Description: A short description of the module\n")
+	builder.WriteString("- Downloads: The total number of times the module has been downloaded\n")
+	builder.WriteString("- Verified: Verification status of the module\n")
+	builder.WriteString("\n\n---\n\n")
+	for i, module := range modules.Data {
+		builder.WriteString(fmt.Sprintf("%d. module_id: %s\n", i+1, module.ID))
+		builder.WriteString(fmt.Sprintf("- Description: %s\n", module.Description))
+		builder.WriteString(fmt.Sprintf("- Downloads: %d\n", module.Downloads))
+		builder.WriteString(fmt.Sprintf("- Verified: %t\n", module.Verified))
+		builder.WriteString("---\n\n")
+	}
+	return mcp.NewToolResultText(builder.String()), nil
+}
+
+// fetchPopularModules reads up to maxPopularPages pages of matching modules
+func fetchPopularModules(ctx context.Context, httpClient *http.Client, provider, category string, verifiedOnly bool, logger *log.Logger) (client.TerraformModules, error) {
+	var modules client.TerraformModules
+	for page := 0; page < maxPopularPages; page++ {
+		response, err := client.SendRegistryCall(ctx, httpClient, http.MethodGet, popularModulesURI(provider, category, verifiedOnly, page*popularPageSize), logger)
+		if err != nil {
+			return modules, err
+		}
+
+		var pageModules client.TerraformModules
+		if err := client.DecodeRegistryResponse(response, &pageModules, logger); err != nil {
+			return modules, fmt.Errorf("unmarshalling modules: %w", err)
+		}
+		modules.Data = append(modules.Data, pageModules.Data...)
+		if pageModules.Metadata.NextURL == "" || len(pageModules.Data) < popularPageSize {
+			break
+		}
+	}
+	return modules, nil
+}
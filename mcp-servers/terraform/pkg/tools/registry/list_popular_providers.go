@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func ListPopularProviders(logger *log.Logger) server.ServerTool {
+	options := []mcp.ToolOption{
+		mcp.WithDescription(`Lists the most downloaded Terraform providers, optionally for one registry category such as 'networking', 'security-authentication' or 'database'.
+Use it to find the standard provider for a platform before calling 'get_latest_provider_version' or 'search_providers'.`),
+		mcp.WithTitleAnnotation("List the most popular Terraform providers"),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("category",
+			mcp.Description("Only return providers of this registry category slug, e.g. 'public-cloud', 'networking', 'database' or 'security-authentication'"),
+			mcp.DefaultString(""),
+		),
+	}
+	options = append(options, withPopularityFilters("providers", true)...)
+
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_popular_providers", options...),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listPopularProvidersHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listPopularProvidersHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	category := strings.ToLower(strings.TrimSpace(request.GetString("category", "")))
+	verifiedOnly := request.GetBool("verified_only", true)
+	minDownloads := int64(request.GetInt("min_downloads", 0))
+	limit := popularLimit(request.GetInt("limit", defaultPopularLimit))
+
+	// Get a simple http client to access the public Terraform registry from context
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		logger.WithError(err).Error("failed to get http client for public Terraform registry")
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+	}
+
+	providers, err := fetchPopularProviders(ctx, httpClient, category, verifiedOnly, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing providers", err)
+	}
+	rankProviders(&providers, minDownloads, limit)
+	if len(providers.Data) == 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "listing providers",
+			fmt.Errorf("no providers found in category %q, relax the filters or check the category slug", category))
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Most downloaded Terraform providers")
+	if category != "" {
+		builder.WriteString(fmt.Sprintf(" in the %s category", category))
+	}
+	builder.WriteString("\n\nEach result includes:\n")
+	builder.WriteString("- Provider: The provider address (format: namespace/name)\n")
+	builder.WriteString("- Tier: official, partner or community\n")
+	builder.WriteString("- Description: A short description of the provider\n")
+	builder.WriteString("- Downloads: The total number of times the provider has been downloaded\n")
+	builder.WriteString("\n\n---\n\n")
+	for i, provider := range providers.Data {
+		builder.WriteString(fmt.Sprintf("%d. Provider: %s\n", i+1, provider.Attributes.FullName))
+		builder.WriteString(fmt.Sprintf("- Tier: %s\n", provider.Attributes.Tier))
+		builder.WriteString(fmt.Sprintf("- Description: %s\n", provider.Attributes.Description))
+		builder.WriteString(fmt.Sprintf("- Downloads: %d\n", provider.Attributes.Downloads))
+		builder.WriteString("---\n\n")
+	}
+	return mcp.NewToolResultText(builder.String()), nil
+}
+
+// fetchPopularProviders reads up to maxPopularPages pages of matching providers
+func fetchPopularProviders(ctx context.Context, httpClient *http.Client, category string, verifiedOnly bool, logger *log.Logger) (client.ProviderList, error) {
+	var providers client.ProviderList
+	for page := 1; page <= maxPopularPages; page++ {
+		response, err := client.SendRegistryCall(ctx, httpClient, http.MethodGet, popularProvidersURI(category, verifiedOnly, page), logger, "v2")
+		if err != nil {
+			return providers, err
+		}
+
+		var pageProviders client.ProviderList
+		if err := client.DecodeRegistryResponse(response, &pageProviders, logger); err != nil {
+			return providers, fmt.Errorf("unmarshalling providers: %w", err)
+		}
+		providers.Data = append(providers.Data, pageProviders.Data...)
+		if pageProviders.Meta.Pagination.NextPage == 0 {
+			break
+		}
+	}
+	return providers, nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// guideSummaryConcurrency is the number of guides whose summary is fetched at the same time
+	guideSummaryConcurrency = 5
+	// otherGuides groups the guides without a subcategory
+	otherGuides = "Other"
+)
+
+// providerGuide is a guide of a provider with its one-line summary
+type providerGuide struct {
+	ID          string
+	Title       string
+	Subcategory string
+	Summary     string
+}
+
+func ListProviderGuides(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_provider_guides",
+			mcp.WithDescription(`Lists every guide of a Terraform provider version, grouped by subcategory, with a one-line summary of each guide.
+Use it to find a guide by its title, e.g. a version upgrade or an authentication guide, without knowing a service_slug, then call 'get_provider_details' with the provider_doc_id of the guide.`),
+			mcp.WithTitleAnnotation("List the guides of a Terraform provider"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("provider_name",
+				mcp.Required(),
+				mcp.Description("The name of the Terraform provider, e.g. 'aws', 'azurerm' or 'google'"),
+			),
+			mcp.WithString("provider_namespace",
+				mcp.Description("The publisher of the Terraform provider, typically the name of the company, or their GitHub organization name that created the provider"),
+				mcp.DefaultString("hashicorp"),
+			),
+			mcp.WithString("provider_version",
+				mcp.Description("The version of the Terraform provider in the format 'x.y.z', or 'latest' to get the latest version"),
+				mcp.DefaultString("latest"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listProviderGuidesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listProviderGuidesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	// For typical provider and namespace hallucinations
+	defaultErrorGuide := "please check the provider name, provider namespace or the provider version you're looking for, perhaps the provider is published under a different namespace or company name"
+
+	// Get a simple http client to access the public Terraform registry from context
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		logger.WithError(err).Error("failed to get http client for public Terraform registry")
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+	}
+	providerDetail, err := resolveProviderDetails(ctx, request, httpClient, defaultErrorGuide, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	providerVersionID, err := client.GetProviderVersionID(ctx, httpClient, providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting provider version ID", err)
+	}
+	uriPrefix := fmt.Sprintf("provider-docs?filter[provider-version]=%s&filter[category]=guides&filter[language]=hcl", providerVersionID)
+	docs, err := client.SendPaginatedRegistryCall(ctx, httpClient, uriPrefix, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting provider guides", err)
+	}
+	if len(docs) == 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "listing provider guides",
+			fmt.Errorf("the %s/%s provider version %s has no guides", providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion))
+	}
+
+	guides := summarizeGuides(ctx, httpClient, docs, logger)
+	return mcp.NewToolResultText(formatProviderGuides(providerDetail, guides)), nil
+}
+
+// summarizeGuides fetches the summary of each guide. A guide whose summary cannot be fetched is listed without one.
+func summarizeGuides(ctx context.Context, httpClient *http.Client, docs []client.ProviderDocData, logger *log.Logger) []providerGuide {
+	guides := make([]providerGuide, len(docs))
+	semaphore := make(chan struct{}, guideSummaryConcurrency)
+	var wg sync.WaitGroup
+	for i, doc := range docs {
+		subcategory, _ := doc.Attributes.Subcategory.(string)
+		guides[i] = providerGuide{ID: doc.ID, Title: doc.Attributes.Title, Subcategory: strings.TrimSpace(subcategory)}
+
+		wg.Add(1)
+		go func(guide *providerGuide) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			summary, err := getContentSnippet(ctx, httpClient, guide.ID, logger)
+			if err != nil {
+				logger.Warnf("Error fetching content snippet for provider guide ID: %s: %v", guide.ID, err)
+			}
+			guide.Summary = summary
+		}(&guides[i])
+	}
+	wg.Wait()
+	return guides
+}
+
+// formatProviderGuides renders the guides grouped by subcategory, in alphabetical order with the guides
+// without a subcategory last
+func formatProviderGuides(providerDetail client.ProviderDetail, guides []providerGuide) string {
+	groups := make(map[string][]providerGuide)
+	for _, guide := range guides {
+		subcategory := guide.Subcategory
+		if subcategory == "" {
+			subcategory = otherGuides
+		}
+		groups[subcategory] = append(groups[subcategory], guide)
+	}
+
+	subcategories := make([]string, 0, len(groups))
+	for subcategory := range groups {
+		subcategories = append(subcategories, subcategory)
+	}
+	sort.Slice(subcategories, func(i, j int) bool {
+		if (subcategories[i] == otherGuides) != (subcategories[j] == otherGuides) {
+			return subcategories[j] == otherGuides
+		}
+		return subcategories[i] < subcategories[j]
+	})
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# Guides of the Terraform provider %s/%s version %s\n\n", providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion))
+	builder.WriteString("Each guide includes its providerDocID, to read the guide with get_provider_details, and a one-line summary.\n")
+	for _, subcategory := range subcategories {
+		group := groups[subcategory]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Title < group[j].Title })
+
+		builder.WriteString(fmt.Sprintf("\n## %s\n\n", subcategory))
+		for _, guide := range group {
+			builder.WriteString(fmt.Sprintf("- %s (providerDocID: %s)", guide.Title, guide.ID))
+			if guide.Summary != "" {
+				builder.WriteString(": " + guide.Summary)
+			}
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+)
+
+func TestFormatProviderGuides(t *testing.T) {
+	providerDetail := client.ProviderDetail{ProviderNamespace: "hashicorp", ProviderName: "aws", ProviderVersion: "6.0.0"}
+	guides := []providerGuide{
+		{ID: "3", Title: "Resource Tagging"},
+		{ID: "2", Title: "Version 6 Upgrade Guide", Subcategory: "Upgrade Guides", Summary: "Changes in version 6.0.0."},
+		{ID: "1", Title: "Version 5 Upgrade Guide", Subcategory: "Upgrade Guides"},
+		{ID: "4", Title: "Using the AWS provider with LocalStack", Subcategory: "Authentication"},
+	}
+
+	want := `# Guides of the Terraform provider hashicorp/aws version 6.0.0
+
+Each guide includes its providerDocID, to read the guide with get_provider_details, and a one-line summary.
+
+## Authentication
+
+- Using the AWS provider with LocalStack (providerDocID: 4)
+
+## Upgrade Guides
+
+- Version 5 Upgrade Guide (providerDocID: 1)
+- Version 6 Upgrade Guide (providerDocID: 2): Changes in version 6.0.0.
+
+## Other
+
+- Resource Tagging (providerDocID: 3)
+`
+	if got := formatProviderGuides(providerDetail, guides); got != want {
+		t.Errorf("formatProviderGuides() =\n%s\nwant\n%s", got, want)
+	}
+}
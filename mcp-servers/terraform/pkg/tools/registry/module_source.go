@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// moduleSource is the GitHub repository and ref a registry module version was published from
+type moduleSource struct {
+	ModuleID   string
+	Repository client.GitHubRepository
+	Ref        string
+}
+
+// resolveModuleSource looks up the source repository of a module version in the registry. The
+// ref defaults to the tag the version was published from.
+func resolveModuleSource(ctx context.Context, httpClient *http.Client, moduleID string, ref string, logger *log.Logger) (moduleSource, error) {
+	response, err := getModuleDetails(ctx, httpClient, strings.ToLower(moduleID), 0, logger)
+	if err != nil {
+		return moduleSource{}, utils.WithErrorCode(utils.ErrorCodeNotFound, err)
+	}
+
+	var details client.TerraformModuleVersionDetails
+	if err := client.DecodeRegistryResponse(response, &details, logger); err != nil {
+		return moduleSource{}, fmt.Errorf("unmarshalling module details: %w", err)
+	}
+
+	repository, ok := client.ParseGitHubSource(details.Source)
+	if !ok {
+		return moduleSource{}, utils.WithErrorCode(utils.ErrorCodeInvalidInput,
+			fmt.Errorf("the source of module %s is not a GitHub repository: %q", moduleID, details.Source))
+	}
+
+	if ref == "" {
+		ref = details.Tag
+	}
+	return moduleSource{ModuleID: details.ID, Repository: repository, Ref: ref}, nil
+}
+
+// cleanSourcePath normalizes a path inside the module repository and rejects paths leaving it
+func cleanSourcePath(sourcePath string) (string, error) {
+	sourcePath = strings.TrimSpace(sourcePath)
+	for _, segment := range strings.Split(sourcePath, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("path %q must not contain '..'", sourcePath)
+		}
+	}
+
+	cleaned := strings.Trim(path.Clean("/"+sourcePath), "/")
+	return cleaned, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+)
+
+func TestCleanSourcePath(t *testing.T) {
+	tests := map[string]string{
+		"":                "",
+		"/":               "",
+		"main.tf":         "main.tf",
+		"/modules/vpc/":   "modules/vpc",
+		"modules//./vpc":  "modules/vpc",
+		" variables.tf ":  "variables.tf",
+		"examples/simple": "examples/simple",
+	}
+	for input, want := range tests {
+		got, err := cleanSourcePath(input)
+		if err != nil {
+			t.Errorf("cleanSourcePath(%q) returned error %v", input, err)
+		}
+		if got != want {
+			t.Errorf("cleanSourcePath(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	if _, err := cleanSourcePath("modules/../../etc/passwd"); err == nil {
+		t.Error("expected an error for a path containing '..'")
+	}
+}
+
+func TestModuleSourceFile(t *testing.T) {
+	source := moduleSource{ModuleID: "org/vpc/aws/1.0.0", Repository: client.GitHubRepository{Owner: "org", Name: "terraform-aws-vpc"}, Ref: "v1.0.0"}
+	encode := func(content string) string {
+		return base64.StdEncoding.EncodeToString([]byte(content))
+	}
+
+	file, err := moduleSourceFile(source, "main.tf", []client.GitHubContent{
+		{Type: "file", Path: "main.tf", Size: 18, SHA: "abc", Content: encode("resource \"x\" {}\n"), Encoding: "base64"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if file.Content != "resource \"x\" {}\n" || file.Truncated {
+		t.Errorf("unexpected file content %q (truncated: %t)", file.Content, file.Truncated)
+	}
+	if file.Repository != "org/terraform-aws-vpc" || file.Ref != "v1.0.0" {
+		t.Errorf("unexpected source %s@%s", file.Repository, file.Ref)
+	}
+
+	large := strings.Repeat("a", maxModuleSourceFileSize+10)
+	file, err = moduleSourceFile(source, "large.tf", []client.GitHubContent{
+		{Type: "file", Path: "large.tf", Content: encode(large), Encoding: "base64"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(file.Content) != maxModuleSourceFileSize || !file.Truncated {
+		t.Errorf("expected the content to be truncated to %d bytes, got %d", maxModuleSourceFileSize, len(file.Content))
+	}
+
+	errorCases := map[string][]client.GitHubContent{
+		"directory": {{Type: "file", Path: "modules/main.tf"}, {Type: "dir", Path: "modules/child"}},
+		"binary":    {{Type: "file", Path: "main.tf", Content: encode("\x00\x01\x02"), Encoding: "base64"}},
+		"too large": {{Type: "file", Path: "main.tf", Size: 2 << 20, Encoding: "none"}},
+	}
+	for name, contents := range errorCases {
+		if _, err := moduleSourceFile(source, "main.tf", contents); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// popularPageSize is the page size requested from the registry when ranking modules and providers
+	popularPageSize = 100
+	// maxPopularPages bounds the number of registry pages ranked by downloads
+	maxPopularPages = 10
+
+	defaultPopularLimit = 10
+	maxPopularLimit     = 50
+)
+
+// withPopularityFilters adds the arguments shared by the popularity tools
+func withPopularityFilters(kind string, verifiedByDefault bool) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithBoolean("verified_only",
+			mcp.Description(fmt.Sprintf("Only return %s published by HashiCorp or its partners", kind)),
+			mcp.DefaultBool(verifiedByDefault),
+		),
+		mcp.WithNumber("min_downloads",
+			mcp.Description(fmt.Sprintf("Only return %s downloaded at least this many times", kind)),
+			mcp.Min(0),
+			mcp.DefaultNumber(0),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Number of %s to return, ranked by downloads", kind)),
+			mcp.Min(1),
+			mcp.Max(maxPopularLimit),
+			mcp.DefaultNumber(defaultPopularLimit),
+		),
+	}
+}
+
+// popularLimit clamps the requested number of results
+func popularLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPopularLimit
+	}
+	return min(limit, maxPopularLimit)
+}
+
+// popularModulesURI lists modules, or searches them when a category is given, with the
+// provider and verification filters of the v1 registry API
+func popularModulesURI(provider, category string, verifiedOnly bool, offset int) string {
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(popularPageSize))
+	query.Set("offset", fmt.Sprint(offset))
+	if provider != "" {
+		query.Set("provider", provider)
+	}
+	if verifiedOnly {
+		query.Set("verified", "true")
+	}
+
+	if category != "" {
+		query.Set("q", category)
+		return "modules/search?" + query.Encode()
+	}
+	return "modules?" + query.Encode()
+}
+
+// rankModules sorts modules by downloads and keeps the limit most downloaded ones with at least minDownloads
+func rankModules(modules *client.TerraformModules, minDownloads int64, limit int) {
+	ranked := modules.Data[:0]
+	for _, module := range modules.Data {
+		if module.Downloads >= minDownloads {
+			ranked = append(ranked, module)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Downloads > ranked[j].Downloads
+	})
+	modules.Data = ranked[:min(len(ranked), limit)]
+}
+
+// popularProvidersURI lists providers with the category and tier filters of the v2 registry API
+func popularProvidersURI(category string, verifiedOnly bool, page int) string {
+	query := url.Values{}
+	query.Set("page[size]", fmt.Sprint(popularPageSize))
+	query.Set("page[number]", fmt.Sprint(page))
+	if category != "" {
+		query.Set("filter[category]", category)
+	}
+	if verifiedOnly {
+		query.Set("filter[tier]", "official,partner")
+	}
+	return "providers?" + query.Encode()
+}
+
+// rankProviders sorts providers by downloads and keeps the limit most downloaded ones with at least minDownloads
+func rankProviders(providers *client.ProviderList, minDownloads int64, limit int) {
+	ranked := providers.Data[:0]
+	for _, provider := range providers.Data {
+		if int64(provider.Attributes.Downloads) >= minDownloads && !provider.Attributes.Unlisted {
+			ranked = append(ranked, provider)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Attributes.Downloads > ranked[j].Attributes.Downloads
+	})
+	providers.Data = ranked[:min(len(ranked), limit)]
+}
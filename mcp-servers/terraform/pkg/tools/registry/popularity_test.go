@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+)
+
+func TestPopularModulesURI(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     string
+		category     string
+		verifiedOnly bool
+		offset       int
+		want         string
+	}{
+		{"no filters", "", "", false, 0, "modules?limit=100&offset=0"},
+		{"provider and verified", "aws", "", true, 100, "modules?limit=100&offset=100&provider=aws&verified=true"},
+		{"category searches", "aws", "vpc", false, 0, "modules/search?limit=100&offset=0&provider=aws&q=vpc"},
+	}
+	for _, tt := range tests {
+		if got := popularModulesURI(tt.provider, tt.category, tt.verifiedOnly, tt.offset); got != tt.want {
+			t.Errorf("%s: popularModulesURI() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPopularProvidersURI(t *testing.T) {
+	got := popularProvidersURI("networking", true, 2)
+	want := "providers?filter%5Bcategory%5D=networking&filter%5Btier%5D=official%2Cpartner&page%5Bnumber%5D=2&page%5Bsize%5D=100"
+	if got != want {
+		t.Errorf("popularProvidersURI() = %q, want %q", got, want)
+	}
+}
+
+func TestRankModules(t *testing.T) {
+	var modules client.TerraformModules
+	err := json.Unmarshal([]byte(`{"modules": [
+		{"id": "a/small/aws/1.0.0", "downloads": 50},
+		{"id": "terraform-aws-modules/vpc/aws/5.1.0", "downloads": 90000},
+		{"id": "b/medium/aws/1.0.0", "downloads": 500},
+		{"id": "c/large/aws/1.0.0", "downloads": 7000}
+	]}`), &modules)
+	if err != nil {
+		t.Fatalf("unmarshalling modules: %v", err)
+	}
+
+	rankModules(&modules, 100, 2)
+	if len(modules.Data) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(modules.Data))
+	}
+	if modules.Data[0].ID != "terraform-aws-modules/vpc/aws/5.1.0" || modules.Data[1].ID != "c/large/aws/1.0.0" {
+		t.Errorf("unexpected ranking %s, %s", modules.Data[0].ID, modules.Data[1].ID)
+	}
+}
+
+func TestRankProviders(t *testing.T) {
+	var providers client.ProviderList
+	err := json.Unmarshal([]byte(`{"data": [
+		{"attributes": {"full-name": "hashicorp/random", "downloads": 1000}},
+		{"attributes": {"full-name": "hashicorp/aws", "downloads": 5000}},
+		{"attributes": {"full-name": "old/unlisted", "downloads": 9000, "unlisted": true}},
+		{"attributes": {"full-name": "tiny/provider", "downloads": 5}}
+	]}`), &providers)
+	if err != nil {
+		t.Fatalf("unmarshalling providers: %v", err)
+	}
+
+	rankProviders(&providers, 10, 10)
+	if len(providers.Data) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(providers.Data))
+	}
+	if providers.Data[0].Attributes.FullName != "hashicorp/aws" || providers.Data[1].Attributes.FullName != "hashicorp/random" {
+		t.Errorf("unexpected ranking %s, %s", providers.Data[0].Attributes.FullName, providers.Data[1].Attributes.FullName)
+	}
+}
+
+func TestPopularLimit(t *testing.T) {
+	for input, want := range map[int]int{0: defaultPopularLimit, -1: defaultPopularLimit, 5: 5, 500: maxPopularLimit} {
+		if got := popularLimit(input); got != want {
+			t.Errorf("popularLimit(%d) = %d, want %d", input, got, want)
+		}
+	}
+}
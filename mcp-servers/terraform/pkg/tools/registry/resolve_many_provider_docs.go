@@ -0,0 +1,235 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxProviderDocLookups bounds the number of lookups in one resolve_many_provider_docs call
+	maxProviderDocLookups = 20
+	// providerDocLookupConcurrency is the number of lookups resolved at the same time
+	providerDocLookupConcurrency = 5
+)
+
+// ProviderDocLookup is one lookup of a resolve_many_provider_docs call
+type ProviderDocLookup struct {
+	Provider    string `json:"provider"`
+	ServiceSlug string `json:"service_slug"`
+	DataType    string `json:"data_type,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Language    string `json:"language,omitempty"`
+}
+
+// ProviderDocLookupResult is the outcome of one lookup, either the search_providers result or an error
+type ProviderDocLookupResult struct {
+	ProviderDocLookup
+	Result string          `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Code   utils.ErrorCode `json:"code,omitempty"`
+}
+
+// ProviderDocLookups is the result of the resolve_many_provider_docs tool
+type ProviderDocLookups struct {
+	Results   []ProviderDocLookupResult `json:"results"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+}
+
+func ResolveManyProviderDocs(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("resolve_many_provider_docs",
+			mcp.WithDescription(fmt.Sprintf(`Runs up to %d 'search_providers' lookups concurrently and returns their results in the order of the lookups.
+Use it instead of sequential 'search_providers' calls when documentation for several resources is needed, e.g. an aws_vpc, aws_subnet and aws_route_table.
+A failed lookup does not fail the others: each result holds either the list of provider_doc_id candidates or an error with its code.`, maxProviderDocLookups)),
+			mcp.WithTitleAnnotation("Resolve the provider document IDs of several Terraform services at once"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithArray("lookups",
+				mcp.Required(),
+				mcp.Description("The lookups to resolve"),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxProviderDocLookups),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"provider": map[string]any{
+							"type":        "string",
+							"description": "The provider name, e.g. 'aws', or namespace/name, e.g. 'integrations/github'",
+						},
+						"service_slug": map[string]any{
+							"type":        "string",
+							"description": "The slug of the service, e.g. 'vpc' or 's3_bucket'",
+						},
+						"data_type": map[string]any{
+							"type":        "string",
+							"description": "The type of the document, defaults to 'resources'",
+							"enum":        toAnySlice(utils.ProviderDataTypes),
+						},
+						"version": map[string]any{
+							"type":        "string",
+							"description": "The provider version in the format 'x.y.z', defaults to the latest version",
+						},
+						"language": map[string]any{
+							"type":        "string",
+							"description": "The language of the documentation, defaults to 'hcl'",
+							"enum":        toAnySlice(utils.ProviderDocLanguages),
+						},
+					},
+					"required": []any{"provider", "service_slug"},
+				}),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return resolveManyProviderDocsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func resolveManyProviderDocsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	lookups, err := parseProviderDocLookups(request.GetArguments()["lookups"])
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid lookups", err)
+	}
+
+	results := make([]ProviderDocLookupResult, len(lookups))
+	semaphore := make(chan struct{}, providerDocLookupConcurrency)
+	var wg sync.WaitGroup
+	for i, lookup := range lookups {
+		wg.Add(1)
+		go func(i int, lookup ProviderDocLookup) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[i] = resolveProviderDocLookup(ctx, request, lookup, logger)
+		}(i, lookup)
+	}
+	wg.Wait()
+
+	summary := ProviderDocLookups{Results: results}
+	for _, result := range results {
+		if result.Error == "" {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	summaryJSON, err := json.Marshal(summary)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling provider doc lookups", err)
+	}
+	return mcp.NewToolResultText(string(summaryJSON)), nil
+}
+
+// parseProviderDocLookups decodes the lookups argument
+func parseProviderDocLookups(raw any) ([]ProviderDocLookup, error) {
+	items, ok := raw.([]any)
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("lookups must be a non-empty array")
+	}
+	if len(items) > maxProviderDocLookups {
+		return nil, fmt.Errorf("at most %d lookups can be resolved at once, got %d", maxProviderDocLookups, len(items))
+	}
+
+	// Round-trip through JSON to decode the objects into the lookup struct
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var lookups []ProviderDocLookup
+	if err := json.Unmarshal(encoded, &lookups); err != nil {
+		return nil, fmt.Errorf("lookups must be objects with provider and service_slug: %w", err)
+	}
+	for i, lookup := range lookups {
+		if strings.TrimSpace(lookup.Provider) == "" || strings.TrimSpace(lookup.ServiceSlug) == "" {
+			return nil, fmt.Errorf("lookup %d must have a provider and a service_slug", i)
+		}
+	}
+	return lookups, nil
+}
+
+// resolveProviderDocLookup runs search_providers for a single lookup
+func resolveProviderDocLookup(ctx context.Context, request mcp.CallToolRequest, lookup ProviderDocLookup, logger *log.Logger) ProviderDocLookupResult {
+	result := ProviderDocLookupResult{ProviderDocLookup: lookup}
+
+	namespace, name, hasNamespace := strings.Cut(lookup.Provider, "/")
+	if !hasNamespace {
+		namespace, name = "", lookup.Provider
+	}
+	arguments := map[string]any{
+		"provider_name":      name,
+		"provider_namespace": namespace,
+		"service_slug":       lookup.ServiceSlug,
+		"provider_data_type": defaultString(lookup.DataType, "resources"),
+		"provider_version":   defaultString(lookup.Version, "latest"),
+		"language":           defaultString(lookup.Language, "hcl"),
+	}
+
+	lookupRequest := mcp.CallToolRequest{}
+	lookupRequest.Params.Name = "search_providers"
+	lookupRequest.Params.Arguments = arguments
+	lookupRequest.Params.Meta = request.Params.Meta
+
+	toolResult, err := resolveProviderDocIDHandler(ctx, lookupRequest, logger)
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+		result.Code = client.ClassifyError(err)
+	case toolResult == nil:
+		result.Error = "no result"
+		result.Code = utils.ErrorCodeInternal
+	default:
+		text := resultText(toolResult)
+		if toolResult.IsError {
+			result.Error = text
+			result.Code = utils.ErrorCodeInternal
+			if errorResult, ok := toolResult.StructuredContent.(utils.ToolErrorResult); ok {
+				result.Error, result.Code = errorResult.Error, errorResult.Code
+			}
+		} else {
+			result.Result = text
+		}
+	}
+	return result
+}
+
+// resultText returns the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+func defaultString(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestParseProviderDocLookups(t *testing.T) {
+	lookups, err := parseProviderDocLookups([]any{
+		map[string]any{"provider": "aws", "service_slug": "vpc"},
+		map[string]any{"provider": "integrations/github", "service_slug": "repository", "data_type": "data-sources"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(lookups) != 2 || lookups[1].Provider != "integrations/github" || lookups[1].DataType != "data-sources" {
+		t.Errorf("unexpected lookups %+v", lookups)
+	}
+
+	tooMany := make([]any, maxProviderDocLookups+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"provider": "aws", "service_slug": "vpc"}
+	}
+	invalid := map[string]any{
+		"not an array":     "aws",
+		"empty":            []any{},
+		"missing slug":     []any{map[string]any{"provider": "aws"}},
+		"not an object":    []any{"aws"},
+		"too many":         tooMany,
+		"missing provider": []any{map[string]any{"service_slug": "vpc"}},
+	}
+	for name, raw := range invalid {
+		if _, err := parseProviderDocLookups(raw); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestResolveManyProviderDocs_PerItemErrors(t *testing.T) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "resolve_many_provider_docs"
+	request.Params.Arguments = map[string]any{
+		"lookups": []any{
+			map[string]any{"provider": "aws", "service_slug": "vpc"},
+			map[string]any{"provider": "hashicorp/google", "service_slug": "compute_instance"},
+		},
+	}
+
+	// Without a session there is no registry client, so every lookup fails on its own
+	result, err := resolveManyProviderDocsHandler(context.Background(), request, log.New())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var lookups ProviderDocLookups
+	if err := json.Unmarshal([]byte(resultText(result)), &lookups); err != nil {
+		t.Fatalf("unmarshalling result: %v", err)
+	}
+	if lookups.Failed != 2 || lookups.Succeeded != 0 || len(lookups.Results) != 2 {
+		t.Fatalf("unexpected summary %+v", lookups)
+	}
+	if lookups.Results[0].Provider != "aws" || lookups.Results[1].Provider != "hashicorp/google" {
+		t.Errorf("results are not in the order of the lookups: %+v", lookups.Results)
+	}
+	for _, lookup := range lookups.Results {
+		if !strings.Contains(lookup.Error, "no active session") || lookup.Code == "" {
+			t.Errorf("unexpected lookup error %q (code %q)", lookup.Error, lookup.Code)
+		}
+	}
+}
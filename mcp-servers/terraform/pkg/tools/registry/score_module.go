@@ -0,0 +1,315 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Weights of the signals of a module scorecard. Signals that cannot be read are left out of the score.
+const (
+	weightDocumentation = 20
+	weightInputs        = 20
+	weightExamples      = 15
+	weightRecency       = 20
+	weightDownloads     = 15
+	weightMaintenance   = 10
+)
+
+// ModuleScorecard is the result of the score_module tool
+type ModuleScorecard struct {
+	ModuleID    string        `json:"module_id"`
+	Version     string        `json:"version"`
+	Verified    bool          `json:"verified"`
+	Deprecated  bool          `json:"deprecated"`
+	PublishedAt time.Time     `json:"published_at"`
+	Source      string        `json:"source"`
+	Score       int           `json:"score"`
+	Grade       string        `json:"grade"`
+	Signals     []ScoreSignal `json:"signals"`
+	Notes       []string      `json:"notes,omitempty"`
+}
+
+// ScoreSignal is a signal of a scorecard, scored from 0 to 100
+type ScoreSignal struct {
+	Name      string `json:"name"`
+	Score     int    `json:"score"`
+	Weight    int    `json:"weight"`
+	Value     string `json:"value"`
+	Available bool   `json:"available"`
+}
+
+// ScoreModule creates a tool that scores a registry module on objective quality signals.
+func ScoreModule(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("score_module",
+			mcp.WithDescription(`Scores a public registry module from 0 to 100 on objective signals so that candidate modules can be compared: documentation (readme, description, described outputs), inputs with descriptions, examples, recency of the latest release, the trend of its downloads and, when GITHUB_TOKEN is set, the maintenance of its source repository (archived, open issues per star, last push).
+Returns the score, a grade from A to D and every signal with its own score, weight and value. Call it once per candidate module, e.g. with the module IDs returned by 'search_modules'.`),
+			mcp.WithTitleAnnotation("Score the quality of a registry module"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("module_id",
+				mcp.Required(),
+				mcp.Description("The module to score as namespace/name/provider for its latest version, or namespace/name/provider/version, e.g. 'terraform-aws-modules/vpc/aws'"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return scoreModuleHandler(ctx, request, logger)
+		},
+	}
+}
+
+func scoreModuleHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	moduleID, err := request.RequireString("module_id")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: module_id is required", err)
+	}
+	moduleID = strings.Trim(strings.ToLower(strings.TrimSpace(moduleID)), "/")
+	parts := strings.Split(moduleID, "/")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("invalid module_id %q, expected namespace/name/provider or namespace/name/provider/version", moduleID), nil)
+	}
+
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		logger.WithError(err).Error("failed to get http client for public Terraform registry")
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+	}
+
+	response, err := client.SendRegistryCall(ctx, httpClient, "GET", "modules/"+moduleID, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("getting module %s%s", moduleID, didYouMean(suggestModules(ctx, httpClient, parts[1], parts[2], logger))), err)
+	}
+	var module client.TerraformModuleVersionDetails
+	if err := client.DecodeRegistryResponse(response, &module, logger); err != nil {
+		return nil, utils.LogAndReturnError(logger, "unmarshalling module details", err)
+	}
+
+	var notes []string
+	downloads, err := client.GetModuleDownloadsSummary(ctx, httpClient, parts[0], parts[1], parts[2], logger)
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("The downloads of the module could not be read: %v", err))
+	}
+
+	var repository *client.GitHubRepositoryInfo
+	if githubRepository, ok := client.ParseGitHubSource(module.Source); !ok {
+		notes = append(notes, "The source repository is not on GitHub, its maintenance is not scored")
+	} else if !client.GitHubSourceToolsEnabled() {
+		notes = append(notes, "Set GITHUB_TOKEN to score the maintenance of the source repository")
+	} else if repository, err = client.GetGitHubRepository(ctx, httpClient, githubRepository, logger); err != nil {
+		notes = append(notes, fmt.Sprintf("The source repository %s could not be read: %v", githubRepository, err))
+	}
+
+	scorecard := scoreModule(&module, downloads, repository, time.Now())
+	scorecard.Notes = append(notes, scorecard.Notes...)
+	resultJSON, err := json.Marshal(scorecard)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling module scorecard", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// scoreModule scores a module version from its details, the summary of its downloads and the metadata of its
+// source repository, the last two being optional
+func scoreModule(module *client.TerraformModuleVersionDetails, downloads *client.DownloadsSummary, repository *client.GitHubRepositoryInfo, now time.Time) *ModuleScorecard {
+	scorecard := &ModuleScorecard{
+		ModuleID:    fmt.Sprintf("%s/%s/%s/%s", module.Namespace, module.Name, module.Provider, module.Version),
+		Version:     module.Version,
+		Verified:    module.Verified,
+		Deprecated:  module.Deprecation != nil,
+		PublishedAt: module.PublishedAt,
+		Source:      module.Source,
+		Signals: []ScoreSignal{
+			documentationSignal(module),
+			inputsSignal(module.Root),
+			examplesSignal(module.Examples),
+			recencySignal(module, now),
+			downloadsSignal(downloads),
+			maintenanceSignal(repository, now),
+		},
+	}
+
+	var total, weights float64
+	for _, signal := range scorecard.Signals {
+		if signal.Available {
+			total += float64(signal.Score * signal.Weight)
+			weights += float64(signal.Weight)
+		}
+	}
+	if weights > 0 {
+		scorecard.Score = int(math.Round(total / weights))
+	}
+	if scorecard.Deprecated {
+		scorecard.Score = min(scorecard.Score, 25)
+		scorecard.Notes = append(scorecard.Notes, "The module is deprecated, its score is capped at 25")
+	}
+
+	switch {
+	case scorecard.Score >= 85:
+		scorecard.Grade = "A"
+	case scorecard.Score >= 70:
+		scorecard.Grade = "B"
+	case scorecard.Score >= 50:
+		scorecard.Grade = "C"
+	default:
+		scorecard.Grade = "D"
+	}
+	return scorecard
+}
+
+// documentationSignal scores the readme and description of a module and the descriptions of its outputs
+func documentationSignal(module *client.TerraformModuleVersionDetails) ScoreSignal {
+	score := 0.0
+	var found []string
+	if readme := strings.TrimSpace(module.Root.Readme); readme != "" {
+		score += 40
+		found = append(found, fmt.Sprintf("readme of %d characters", len(readme)))
+	} else {
+		found = append(found, "no readme")
+	}
+	if strings.TrimSpace(module.Description) != "" {
+		score += 20
+		found = append(found, "a description")
+	} else {
+		found = append(found, "no description")
+	}
+
+	described := 0
+	for _, output := range module.Root.Outputs {
+		if strings.TrimSpace(output.Description) != "" {
+			described++
+		}
+	}
+	if len(module.Root.Outputs) == 0 {
+		score += 40
+	} else {
+		score += 40 * float64(described) / float64(len(module.Root.Outputs))
+	}
+	found = append(found, fmt.Sprintf("%d of %d outputs described", described, len(module.Root.Outputs)))
+
+	return ScoreSignal{Name: "documentation", Score: int(math.Round(score)), Weight: weightDocumentation, Value: strings.Join(found, ", "), Available: true}
+}
+
+// inputsSignal scores the share of the inputs of a module with a description
+func inputsSignal(root client.ModulePart) ScoreSignal {
+	described := 0
+	for _, input := range root.Inputs {
+		if strings.TrimSpace(input.Description) != "" {
+			described++
+		}
+	}
+	score := 100
+	if len(root.Inputs) > 0 {
+		score = int(math.Round(100 * float64(described) / float64(len(root.Inputs))))
+	}
+	return ScoreSignal{Name: "inputs", Score: score, Weight: weightInputs, Value: fmt.Sprintf("%d of %d inputs described", described, len(root.Inputs)), Available: true}
+}
+
+// examplesSignal scores the number of examples published with a module
+func examplesSignal(examples []client.ModulePart) ScoreSignal {
+	score := 0
+	switch {
+	case len(examples) >= 2:
+		score = 100
+	case len(examples) == 1:
+		score = 70
+	}
+	return ScoreSignal{Name: "examples", Score: score, Weight: weightExamples, Value: fmt.Sprintf("%d examples", len(examples)), Available: true}
+}
+
+// recencySignal scores the age of the scored version
+func recencySignal(module *client.TerraformModuleVersionDetails, now time.Time) ScoreSignal {
+	if module.PublishedAt.IsZero() {
+		return ScoreSignal{Name: "recency", Weight: weightRecency, Value: "unknown publication date"}
+	}
+	days := int(now.Sub(module.PublishedAt).Hours() / 24)
+	return ScoreSignal{
+		Name:      "recency",
+		Score:     ageScore(days),
+		Weight:    weightRecency,
+		Value:     fmt.Sprintf("published %d days ago, %d versions", days, len(module.Versions)),
+		Available: true,
+	}
+}
+
+// downloadsSignal scores the trend of the downloads, comparing the last month with the monthly average of
+// the last year
+func downloadsSignal(downloads *client.DownloadsSummary) ScoreSignal {
+	signal := ScoreSignal{Name: "downloads", Weight: weightDownloads, Value: "unavailable"}
+	if downloads == nil || downloads.Year == 0 {
+		if downloads != nil {
+			signal.Value = "no downloads over the last year"
+			signal.Available = true
+		}
+		return signal
+	}
+
+	ratio := float64(downloads.Month) * 12 / float64(downloads.Year)
+	trend, score := "stable", 80
+	switch {
+	case ratio >= 1.1:
+		trend, score = "growing", 100
+	case ratio < 0.9:
+		trend, score = "declining", 50
+	}
+	signal.Score, signal.Available = score, true
+	signal.Value = fmt.Sprintf("%s: %d downloads last month, %d last year, %d in total", trend, downloads.Month, downloads.Year, downloads.Total)
+	return signal
+}
+
+// maintenanceSignal scores the source repository: archived, open issues per star and the age of the last push
+func maintenanceSignal(repository *client.GitHubRepositoryInfo, now time.Time) ScoreSignal {
+	signal := ScoreSignal{Name: "maintenance", Weight: weightMaintenance, Value: "unavailable"}
+	if repository == nil {
+		return signal
+	}
+	signal.Available = true
+	if repository.Archived {
+		signal.Value = fmt.Sprintf("%s is archived", repository.FullName)
+		return signal
+	}
+
+	issuesPerStar := float64(repository.OpenIssuesCount) / float64(max(repository.StargazersCount, 1))
+	issuesScore := 25
+	switch {
+	case issuesPerStar <= 0.02:
+		issuesScore = 100
+	case issuesPerStar <= 0.05:
+		issuesScore = 75
+	case issuesPerStar <= 0.1:
+		issuesScore = 50
+	}
+	days := int(now.Sub(repository.PushedAt).Hours() / 24)
+	signal.Score = (issuesScore + ageScore(days)) / 2
+	signal.Value = fmt.Sprintf("%d open issues and pull requests, %d stars, last push %d days ago", repository.OpenIssuesCount, repository.StargazersCount, days)
+	return signal
+}
+
+// ageScore scores an age in days, from 100 within three months to 0 after two years
+func ageScore(days int) int {
+	switch {
+	case days <= 90:
+		return 100
+	case days <= 180:
+		return 80
+	case days <= 365:
+		return 60
+	case days <= 730:
+		return 30
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreModule(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	module := &client.TerraformModuleVersionDetails{
+		Namespace:   "acme",
+		Name:        "vpc",
+		Provider:    "aws",
+		Version:     "2.0.0",
+		Description: "A VPC",
+		Source:      "https://github.com/acme/terraform-aws-vpc",
+		PublishedAt: now.AddDate(0, 0, -30),
+		Versions:    []string{"1.0.0", "2.0.0"},
+		Root: client.ModulePart{
+			Readme:  "# VPC",
+			Inputs:  []client.ModuleInput{{Name: "cidr", Description: "The CIDR"}, {Name: "name"}},
+			Outputs: []client.ModuleOutput{{Name: "id", Description: "The ID"}},
+		},
+		Examples: []client.ModulePart{{Name: "complete"}},
+	}
+
+	signals := func(scorecard *ModuleScorecard) map[string]ScoreSignal {
+		byName := map[string]ScoreSignal{}
+		for _, signal := range scorecard.Signals {
+			byName[signal.Name] = signal
+		}
+		return byName
+	}
+
+	t.Run("scores the available signals", func(t *testing.T) {
+		downloads := &client.DownloadsSummary{Week: 250, Month: 1000, Year: 12000, Total: 50000}
+		repository := &client.GitHubRepositoryInfo{FullName: "acme/terraform-aws-vpc", OpenIssuesCount: 3, StargazersCount: 100, PushedAt: now.AddDate(0, 0, -200)}
+
+		scorecard := scoreModule(module, downloads, repository, now)
+		assert.Equal(t, "acme/vpc/aws/2.0.0", scorecard.ModuleID)
+
+		byName := signals(scorecard)
+		assert.Equal(t, 100, byName["documentation"].Score)
+		assert.Equal(t, 50, byName["inputs"].Score)
+		assert.Equal(t, 70, byName["examples"].Score)
+		assert.Equal(t, 100, byName["recency"].Score)
+		assert.Equal(t, 80, byName["downloads"].Score)
+		assert.Contains(t, byName["downloads"].Value, "stable")
+		// 0.03 open issues per star and a last push 200 days ago
+		assert.Equal(t, 67, byName["maintenance"].Score)
+
+		// (100*20 + 50*20 + 70*15 + 100*20 + 80*15 + 67*10) / 100
+		assert.Equal(t, 79, scorecard.Score)
+		assert.Equal(t, "B", scorecard.Grade)
+	})
+
+	t.Run("leaves out unavailable signals", func(t *testing.T) {
+		scorecard := scoreModule(module, nil, nil, now)
+		byName := signals(scorecard)
+		assert.False(t, byName["downloads"].Available)
+		assert.False(t, byName["maintenance"].Available)
+		// (100*20 + 50*20 + 70*15 + 100*20) / 75
+		assert.Equal(t, 81, scorecard.Score)
+	})
+
+	t.Run("penalizes archived and deprecated modules", func(t *testing.T) {
+		deprecated := *module
+		deprecated.Deprecation = map[string]any{"reason": "use acme/network"}
+		deprecated.PublishedAt = now.AddDate(-3, 0, 0)
+
+		scorecard := scoreModule(&deprecated, &client.DownloadsSummary{Month: 10, Year: 1200}, &client.GitHubRepositoryInfo{FullName: "acme/vpc", Archived: true}, now)
+		byName := signals(scorecard)
+		assert.Equal(t, 0, byName["recency"].Score)
+		assert.Contains(t, byName["downloads"].Value, "declining")
+		assert.Equal(t, 0, byName["maintenance"].Score)
+		assert.True(t, scorecard.Deprecated)
+		assert.Equal(t, 25, scorecard.Score)
+		assert.Equal(t, "D", scorecard.Grade)
+		require.Len(t, scorecard.Notes, 1)
+	})
+}
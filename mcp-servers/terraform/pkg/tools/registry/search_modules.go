@@ -5,7 +5,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -20,6 +19,22 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// Orderings of the search_modules results
+const (
+	moduleSortDownloads   = "downloads"
+	moduleSortPublishedAt = "published_at"
+	moduleSortVerified    = "verified"
+)
+
+// moduleSearchOptions holds the filters and the ordering of a module search
+type moduleSearchOptions struct {
+	Namespace    string
+	Provider     string
+	VerifiedOnly bool
+	MinDownloads int64
+	SortBy       string
+}
+
 func SearchModules(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("search_modules",
@@ -40,11 +55,27 @@ If no modules were found, reattempt the search with a new moduleName query.`),
 				mcp.Required(),
 				mcp.Description("The query to search for Terraform modules."),
 			),
-			mcp.WithNumber("current_offset",
-				mcp.Description("Current offset for pagination"),
+			mcp.WithString("namespace",
+				mcp.Description("Only return modules published in this namespace, e.g. 'terraform-aws-modules'"),
+			),
+			mcp.WithString("provider",
+				mcp.Description("Only return modules for this provider, e.g. 'aws', 'azurerm' or 'google'"),
+			),
+			mcp.WithBoolean("verified_only",
+				mcp.Description("Only return modules published by HashiCorp or its partners"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithNumber("min_downloads",
+				mcp.Description("Only return modules downloaded at least this many times"),
 				mcp.Min(0),
 				mcp.DefaultNumber(0),
 			),
+			mcp.WithString("sort_by",
+				mcp.Description("Order of the results of each page: most downloaded first, most recently published first, or verified modules first"),
+				mcp.Enum(moduleSortDownloads, moduleSortPublishedAt, moduleSortVerified),
+				mcp.DefaultString(moduleSortDownloads),
+			),
+			utils.WithPagination(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return getSearchModulesHandler(ctx, request, logger)
@@ -55,24 +86,38 @@ If no modules were found, reattempt the search with a new moduleName query.`),
 func getSearchModulesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	moduleQuery, err := request.RequireString("module_query")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: module_query is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: module_query is required", err)
+	}
+	moduleQuery = client.ResolveProviderAliasesInQuery(strings.ToLower(moduleQuery), logger)
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, err.Error()), nil
+	}
+	options := moduleSearchOptions{
+		Namespace:    strings.TrimSpace(request.GetString("namespace", "")),
+		Provider:     strings.ToLower(strings.TrimSpace(request.GetString("provider", ""))),
+		VerifiedOnly: request.GetBool("verified_only", false),
+		MinDownloads: int64(request.GetInt("min_downloads", 0)),
+		SortBy:       request.GetString("sort_by", moduleSortDownloads),
+	}
+	if options.Provider != "" {
+		_, options.Provider = client.ResolveProviderAlias("", options.Provider, logger)
 	}
-	moduleQuery = strings.ToLower(moduleQuery)
-	currentOffsetValue := request.GetInt("current_offset", 0)
 
 	// Get a simple http client to access the public Terraform registry from context
 	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to get http client for public Terraform registry")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
 	}
 
 	var modulesData, errMsg string
-	response, err := sendSearchModulesCall(httpClient, moduleQuery, currentOffsetValue, logger)
+	var hasNextPage bool
+	response, err := sendSearchModulesCall(ctx, httpClient, moduleQuery, options, pagination, logger)
 	if err != nil {
 		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("finding module(s): none found for moduleName: %s", moduleQuery), err)
 	} else {
-		modulesData, err = unmarshalTerraformModules(response, moduleQuery, logger)
+		modulesData, hasNextPage, err = unmarshalTerraformModules(response, moduleQuery, options, logger)
 		if err != nil {
 			return nil, utils.LogAndReturnError(logger, fmt.Sprintf("unmarshalling modules for moduleName: %s", moduleQuery), err)
 		}
@@ -82,18 +127,16 @@ func getSearchModulesHandler(ctx context.Context, request mcp.CallToolRequest, l
 		errMsg = fmt.Sprintf("getting module(s), none found! query used: %s; error: %s", moduleQuery, errMsg)
 		return nil, utils.LogAndReturnError(logger, errMsg, nil)
 	}
-	return mcp.NewToolResultText(modulesData), nil
-}
 
-func sendSearchModulesCall(providerClient *http.Client, moduleQuery string, currentOffset int, logger *log.Logger) ([]byte, error) {
-	uri := "modules"
-	if moduleQuery != "" {
-		uri = fmt.Sprintf("%s/search?q='%s'&offset=%v", uri, url.PathEscape(moduleQuery), currentOffset)
-	} else {
-		uri = fmt.Sprintf("%s?offset=%v", uri, currentOffset)
+	nextPage := 0
+	if hasNextPage {
+		nextPage = pagination.Page + 1
 	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(modulesData), pagination, nextPage), nil
+}
 
-	response, err := client.SendRegistryCall(providerClient, "GET", uri, logger)
+func sendSearchModulesCall(ctx context.Context, providerClient *http.Client, moduleQuery string, options moduleSearchOptions, pagination utils.PaginationParams, logger *log.Logger) ([]byte, error) {
+	response, err := client.SendRegistryCall(ctx, providerClient, "GET", searchModulesURI(moduleQuery, options, pagination), logger)
 	if err != nil {
 		// We shouldn't log the error here because we might hit a namespace that doesn't exist, it's better to let the caller handle it.
 		return nil, fmt.Errorf("getting module(s) for: %v, call error: %v", moduleQuery, err)
@@ -103,21 +146,68 @@ func sendSearchModulesCall(providerClient *http.Client, moduleQuery string, curr
 	return response, nil
 }
 
-func unmarshalTerraformModules(response []byte, moduleQuery string, logger *log.Logger) (string, error) {
+// searchModulesURI searches modules, or lists them without a query, with the namespace, provider
+// and verification filters of the v1 registry API
+func searchModulesURI(moduleQuery string, options moduleSearchOptions, pagination utils.PaginationParams) string {
+	query := url.Values{}
+	query.Set("offset", fmt.Sprint(pagination.Offset()))
+	query.Set("limit", fmt.Sprint(pagination.PageSize))
+	if options.Provider != "" {
+		query.Set("provider", options.Provider)
+	}
+	if options.VerifiedOnly {
+		query.Set("verified", "true")
+	}
+
+	uri := "modules"
+	if moduleQuery != "" {
+		query.Set("q", fmt.Sprintf("'%s'", moduleQuery))
+		if options.Namespace != "" {
+			query.Set("namespace", options.Namespace)
+		}
+		uri = fmt.Sprintf("%s/search?%s", uri, query.Encode())
+	} else if options.Namespace != "" {
+		uri = fmt.Sprintf("%s/%s?%s", uri, url.PathEscape(options.Namespace), query.Encode())
+	} else {
+		uri = fmt.Sprintf("%s?%s", uri, query.Encode())
+	}
+	return uri
+}
+
+// unmarshalTerraformModules formats a page of search results, filtered by downloads and sorted as requested,
+// and reports whether the registry has more results
+func unmarshalTerraformModules(response []byte, moduleQuery string, options moduleSearchOptions, logger *log.Logger) (string, bool, error) {
 	// Get the list of modules
 	var terraformModules client.TerraformModules
-	err := json.Unmarshal(response, &terraformModules)
+	err := client.DecodeRegistryResponse(response, &terraformModules, logger)
 	if err != nil {
-		return "", utils.LogAndReturnError(logger, "unmarshalling modules", err)
+		return "", false, utils.LogAndReturnError(logger, "unmarshalling modules", err)
 	}
 
 	if len(terraformModules.Data) == 0 {
-		return "", utils.LogAndReturnError(logger, fmt.Sprintf("no modules found for query: %s", moduleQuery), nil)
+		return "", false, utils.LogAndReturnError(logger, fmt.Sprintf("no modules found for query: %s", moduleQuery), nil)
+	}
+
+	// The registry cannot filter by downloads, so the page is filtered here
+	modules := terraformModules.Data[:0]
+	for _, module := range terraformModules.Data {
+		if module.Downloads >= options.MinDownloads {
+			modules = append(modules, module)
+		}
 	}
 
-	// Sort by most downloaded
-	sort.Slice(terraformModules.Data, func(i, j int) bool {
-		return terraformModules.Data[i].Downloads > terraformModules.Data[j].Downloads
+	// Sort the page, ties by downloads then by ID so that the order is stable
+	sort.Slice(modules, func(i, j int) bool {
+		a, b := modules[i], modules[j]
+		switch {
+		case options.SortBy == moduleSortPublishedAt && !a.PublishedAt.Equal(b.PublishedAt):
+			return a.PublishedAt.After(b.PublishedAt)
+		case options.SortBy == moduleSortVerified && a.Verified != b.Verified:
+			return a.Verified
+		case a.Downloads != b.Downloads:
+			return a.Downloads > b.Downloads
+		}
+		return a.ID < b.ID
 	})
 
 	var builder strings.Builder
@@ -129,7 +219,10 @@ func unmarshalTerraformModules(response []byte, moduleQuery string, logger *log.
 	builder.WriteString("- Verified: Verification status of the module\n")
 	builder.WriteString("- Published: The date and time when the module was published\n")
 	builder.WriteString("\n\n---\n\n")
-	for _, module := range terraformModules.Data {
+	if len(modules) == 0 {
+		builder.WriteString(fmt.Sprintf("No module of this page was downloaded at least %d times.\n", options.MinDownloads))
+	}
+	for _, module := range modules {
 		builder.WriteString(fmt.Sprintf("- module_id: %s\n", module.ID))
 		builder.WriteString(fmt.Sprintf("- Name: %s\n", module.Name))
 		builder.WriteString(fmt.Sprintf("- Description: %s\n", module.Description))
@@ -138,5 +231,5 @@ func unmarshalTerraformModules(response []byte, moduleQuery string, logger *log.
 		builder.WriteString(fmt.Sprintf("- Published: %s\n", module.PublishedAt))
 		builder.WriteString("---\n\n")
 	}
-	return builder.String(), nil
+	return builder.String(), terraformModules.Metadata.NextURL != "", nil
 }
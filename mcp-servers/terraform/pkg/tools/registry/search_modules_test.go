@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestUnmarshalTerraformModules_Pagination(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	resp := []byte(`{
+		"meta": {"limit": 2, "current_offset": 0, "next_offset": 2, "next_url": "/v1/modules/search?limit=2&offset=2&q=vpc"},
+		"modules": [
+			{"id": "b/vpc/aws/1.0.0", "name": "vpc", "downloads": 10},
+			{"id": "a/vpc/aws/1.0.0", "name": "vpc", "downloads": 10}
+		]
+	}`)
+	out, hasNextPage, err := unmarshalTerraformModules(resp, "vpc", moduleSearchOptions{}, logger)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !hasNextPage {
+		t.Error("expected a next page")
+	}
+	// Modules with as many downloads are ordered by ID, so that pages are stable
+	if strings.Index(out, "a/vpc/aws/1.0.0") > strings.Index(out, "b/vpc/aws/1.0.0") {
+		t.Errorf("expected modules ordered by ID, got %q", out)
+	}
+
+	resp = []byte(`{"meta": {"limit": 2, "current_offset": 2}, "modules": [{"id": "c/vpc/aws/1.0.0", "name": "vpc"}]}`)
+	_, hasNextPage, err = unmarshalTerraformModules(resp, "vpc", moduleSearchOptions{}, logger)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if hasNextPage {
+		t.Error("expected the last page")
+	}
+}
+
+func TestSearchModulesURI(t *testing.T) {
+	pagination := utils.PaginationParams{Page: 2, PageSize: 10}
+	tests := []struct {
+		name     string
+		query    string
+		options  moduleSearchOptions
+		expected string
+	}{
+		{"search", "vpc", moduleSearchOptions{}, "modules/search?limit=10&offset=10&q=%27vpc%27"},
+		{"search with filters", "vpc", moduleSearchOptions{Namespace: "terraform-aws-modules", Provider: "aws", VerifiedOnly: true},
+			"modules/search?limit=10&namespace=terraform-aws-modules&offset=10&provider=aws&q=%27vpc%27&verified=true"},
+		{"list namespace", "", moduleSearchOptions{Namespace: "hashicorp"}, "modules/hashicorp?limit=10&offset=10"},
+		{"list", "", moduleSearchOptions{Provider: "google"}, "modules?limit=10&offset=10&provider=google"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if uri := searchModulesURI(tt.query, tt.options, pagination); uri != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, uri)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTerraformModules_FilterAndSort(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	resp := []byte(`{"meta": {}, "modules": [
+		{"id": "a/old/aws/1.0.0", "downloads": 500, "verified": false, "published_at": "2023-01-01T00:00:00Z"},
+		{"id": "b/new/aws/1.0.0", "downloads": 100, "verified": false, "published_at": "2025-01-01T00:00:00Z"},
+		{"id": "c/verified/aws/1.0.0", "downloads": 200, "verified": true, "published_at": "2024-01-01T00:00:00Z"},
+		{"id": "d/tiny/aws/1.0.0", "downloads": 1, "verified": true, "published_at": "2025-06-01T00:00:00Z"}
+	]}`)
+	order := func(sortBy string) []string {
+		out, _, err := unmarshalTerraformModules(resp, "aws", moduleSearchOptions{MinDownloads: 50, SortBy: sortBy}, logger)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if strings.Contains(out, "d/tiny") {
+			t.Errorf("expected modules below min_downloads to be filtered out, got %q", out)
+		}
+		var ids []string
+		for _, line := range strings.Split(out, "\n") {
+			// The legend of the fields is skipped
+			if id, ok := strings.CutPrefix(line, "- module_id: "); ok && !strings.Contains(id, " ") {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+
+	expected := map[string][]string{
+		moduleSortDownloads:   {"a/old/aws/1.0.0", "c/verified/aws/1.0.0", "b/new/aws/1.0.0"},
+		moduleSortPublishedAt: {"b/new/aws/1.0.0", "c/verified/aws/1.0.0", "a/old/aws/1.0.0"},
+		moduleSortVerified:    {"c/verified/aws/1.0.0", "a/old/aws/1.0.0", "b/new/aws/1.0.0"},
+	}
+	for sortBy, ids := range expected {
+		if got := order(sortBy); strings.Join(got, ",") != strings.Join(ids, ",") {
+			t.Errorf("sort_by %s: expected %v, got %v", sortBy, ids, got)
+		}
+	}
+}
+
+func FuzzUnmarshalTerraformModules(f *testing.F) {
+	f.Add([]byte(`{"meta": {"next_url": "/v1/modules?offset=10"}, "modules": [{"id": "a/vpc/aws/1.0.0", "downloads": 100, "verified": true}, {"id": "b/vpc/aws/2.0.0", "downloads": 5}]}`), int64(50), moduleSortVerified)
+	f.Add([]byte(`{"modules": [{"published_at": "not a date"}, null]}`), int64(-1), moduleSortPublishedAt)
+	f.Add([]byte(`{"modules": []}`), int64(0), "")
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	f.Fuzz(func(t *testing.T, response []byte, minDownloads int64, sortBy string) {
+		_, _, _ = unmarshalTerraformModules(response, "vpc", moduleSearchOptions{MinDownloads: minDownloads, SortBy: sortBy}, logger)
+	})
+}
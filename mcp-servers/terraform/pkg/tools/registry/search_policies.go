@@ -5,9 +5,9 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-mcp-server/pkg/client"
@@ -18,6 +18,9 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// maxPolicyPages caps the pages of 100 policies fetched to match the query, the public registry has far fewer
+const maxPolicyPages = 10
+
 func SearchPolicies(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("search_policies",
@@ -39,6 +42,7 @@ If no policies were found, reattempt the search with a new policy_query.`),
 				mcp.Required(),
 				mcp.Description("The query to search for Terraform modules."),
 			),
+			utils.WithPagination(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return getSearchPoliciesHandler(ctx, request, logger)
@@ -50,48 +54,66 @@ func getSearchPoliciesHandler(ctx context.Context, request mcp.CallToolRequest,
 	var terraformPolicies client.TerraformPolicyList
 	pq, err := request.RequireString("policy_query")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: policy_query is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: policy_query is required", err)
 	}
 	if pq == "" {
-		return nil, utils.LogAndReturnError(logger, "required input: policy_query cannot be empty", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: policy_query cannot be empty", nil)
 	}
 	pq = strings.ToLower(pq)
 
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, err.Error()), nil
+	}
+	if pagination.Page < 1 || pagination.PageSize < 1 {
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, "page and pageSize must be at least 1"), nil
+	}
+
 	// Get a simple http client to access the public Terraform registry from context
 	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to get http client for public Terraform registry")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
-	}
-	uri := (&url.URL{
-		Path: "policies",
-		RawQuery: url.Values{
-			"page[size]": {"100"}, // static list of 100 is fine for now
-			"include":    {"latest-version"},
-		}.Encode(),
-	}).String()
-	policyResp, err := client.SendRegistryCall(httpClient, "GET", uri, logger, "v2")
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "fetching policies: registry API did not return a successful response", err)
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
 	}
 
-	err = json.Unmarshal(policyResp, &terraformPolicies)
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "unmarshalling policy list", err)
+	// The registry cannot search policies, so every policy is fetched and matched here, and the
+	// matches are paginated in registry order
+	for page := 1; page <= maxPolicyPages; page++ {
+		uri := (&url.URL{
+			Path: "policies",
+			RawQuery: url.Values{
+				"page[number]": {strconv.Itoa(page)},
+				"page[size]":   {"100"},
+				"include":      {"latest-version"},
+			}.Encode(),
+		}).String()
+		policyResp, err := client.SendRegistryCall(ctx, httpClient, "GET", uri, logger, "v2")
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "fetching policies: registry API did not return a successful response", err)
+		}
+
+		var policyPage client.TerraformPolicyList
+		err = client.DecodeRegistryResponse(policyResp, &policyPage, logger)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "unmarshalling policy list", err)
+		}
+		terraformPolicies.Data = append(terraformPolicies.Data, policyPage.Data...)
+		if policyPage.Meta.Pagination.NextPage == nil || len(policyPage.Data) == 0 {
+			break
+		}
 	}
 
 	var builder strings.Builder
 	builder.WriteString(fmt.Sprintf("Matching Terraform Policies for query: %s\n\n", pq))
 	builder.WriteString("Each result includes:\n- terraform_policy_id: Unique identifier to be used with get_policy_details tool\n- Name: Policy name\n- Title: Policy description\n- Downloads: Policy downloads\n---\n\n")
 
-	contentAvailable := false
+	var matches []string
 	for _, policy := range terraformPolicies.Data {
 		cs, err := utils.ContainsSlug(strings.ToLower(policy.Attributes.Title), pq)
 		cs_pn, err_pn := utils.ContainsSlug(strings.ToLower(policy.Attributes.Name), pq)
 		if (cs || cs_pn) && err == nil && err_pn == nil {
-			contentAvailable = true
 			ID := strings.ReplaceAll(policy.Relationships.LatestVersion.Links.Related, "/v2/", "")
-			builder.WriteString(fmt.Sprintf(
+			matches = append(matches, fmt.Sprintf(
 				"- terraform_policy_id: %s\n- Name: %s\n- Title: %s\n- Downloads: %d\n---\n",
 				ID,
 				policy.Attributes.Name,
@@ -101,11 +123,21 @@ func getSearchPoliciesHandler(ctx context.Context, request mcp.CallToolRequest,
 		}
 	}
 
-	policyData := builder.String()
-	if !contentAvailable {
+	if len(matches) == 0 {
 		errMessage := fmt.Sprintf("finding policies, none found matching the query: %s. Try a different policy_query.", pq)
 		return nil, utils.LogAndReturnError(logger, errMessage, nil)
 	}
+	if pagination.Offset() >= len(matches) {
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, fmt.Sprintf("page %d is past the last page of the %d policies matching the query: %s", pagination.Page, len(matches), pq)), nil
+	}
 
-	return mcp.NewToolResultText(policyData), nil
+	end := min(pagination.Offset()+pagination.PageSize, len(matches))
+	for _, match := range matches[pagination.Offset():end] {
+		builder.WriteString(match)
+	}
+	nextPage := 0
+	if end < len(matches) {
+		nextPage = pagination.Page + 1
+	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(builder.String()), pagination, nextPage), nil
 }
@@ -5,7 +5,6 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"path"
@@ -48,10 +47,15 @@ If there are multiple good matches, mention this but proceed with the most relev
 				mcp.Description("The slug of the service you want to deploy or read using the Terraform provider, prefer using a single word, use underscores for multiple words and if unsure about the service_slug, use the provider_name for its value"),
 			),
 			mcp.WithString("provider_data_type",
-				mcp.Description("The type of the document to retrieve, for general information use 'guides', for deploying resources use 'resources', for reading pre-deployed resources use 'data-sources', for functions use 'functions', and for overview of the provider use 'overview'"),
-				mcp.Enum("resources", "data-sources", "functions", "guides", "overview"),
+				mcp.Description("The type of the document to retrieve, for general information use 'guides', for deploying resources use 'resources', for reading pre-deployed resources use 'data-sources', for temporary values that are never stored in state, e.g. secrets or tokens, use 'ephemeral-resources', for functions use 'functions', and for overview of the provider use 'overview'"),
+				mcp.Enum(utils.ProviderDataTypes...),
 				mcp.DefaultString("resources"),
 			),
+			mcp.WithString("language",
+				mcp.Description("The language of the documentation, 'hcl' for Terraform configuration or a CDK for Terraform language for CDKTF projects"),
+				mcp.Enum(utils.ProviderDocLanguages...),
+				mcp.DefaultString("hcl"),
+			),
 			mcp.WithString("provider_version",
 				mcp.Description("The version of the Terraform provider to retrieve in the format 'x.y.z', or 'latest' to get the latest version")),
 		),
@@ -69,28 +73,28 @@ func resolveProviderDocIDHandler(ctx context.Context, request mcp.CallToolReques
 	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to get http client for public Terraform registry")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
 	}
-	providerDetail, err := resolveProviderDetails(request, httpClient, defaultErrorGuide, logger)
+	providerDetail, err := resolveProviderDetails(ctx, request, httpClient, defaultErrorGuide, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	serviceSlug, err := request.RequireString("service_slug")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: service_slug is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: service_slug is required", err)
 	}
 	if serviceSlug == "" {
-		return nil, utils.LogAndReturnError(logger, "required input: service_slug cannot be empty", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: service_slug cannot be empty", nil)
 	}
 	serviceSlug = strings.ToLower(serviceSlug)
 
 	providerDataType := request.GetString("provider_data_type", "resources")
 	providerDetail.ProviderDataType = providerDataType
 
-	// Check if we need to use v2 API for guides, functions, or overview
+	// Check if we need to use v2 API for ephemeral resources, guides, functions, or overview
 	if utils.IsV2ProviderDataType(providerDetail.ProviderDataType) {
-		content, err := providerDetailsV2(httpClient, providerDetail, logger)
+		content, err := providerDetailsV2(ctx, httpClient, providerDetail, serviceSlug, logger)
 		if err != nil {
 			errMessage := fmt.Sprintf(`finding %s documentation for provider '%s' in the '%s' namespace, %s`,
 				providerDetail.ProviderDataType, providerDetail.ProviderName, providerDetail.ProviderNamespace, defaultErrorGuide)
@@ -105,29 +109,29 @@ func resolveProviderDocIDHandler(ctx context.Context, request mcp.CallToolReques
 
 	// For resources/data-sources, use the v1 API for better performance (single response)
 	uri := path.Join("providers", providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion)
-	response, err := client.SendRegistryCall(httpClient, "GET", uri, logger)
+	response, err := client.SendRegistryCall(ctx, httpClient, "GET", uri, logger)
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, fmt.Sprintf(`getting the "%s" provider, with version "%s" in the %s namespace, %s`, providerDetail.ProviderName, providerDetail.ProviderVersion, providerDetail.ProviderNamespace, defaultErrorGuide), nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, client.ClassifyError(err), fmt.Sprintf(`getting the "%s" provider, with version "%s" in the %s namespace, %s`, providerDetail.ProviderName, providerDetail.ProviderVersion, providerDetail.ProviderNamespace, defaultErrorGuide), nil)
 	}
 
 	var providerDocs client.ProviderDocs
-	if err := json.Unmarshal(response, &providerDocs); err != nil {
+	if err := client.DecodeRegistryResponse(response, &providerDocs, logger); err != nil {
 		return nil, utils.LogAndReturnError(logger, "unmarshalling provider docs", err)
 	}
 
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("Available Documentation (top matches) for %s in Terraform provider %s/%s version: %s\n\n", providerDetail.ProviderDataType, providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion))
+	builder.WriteString(fmt.Sprintf("Available %s Documentation (top matches) for %s in Terraform provider %s/%s version: %s\n\n", strings.ToUpper(providerDetail.Language), providerDetail.ProviderDataType, providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion))
 	builder.WriteString("Each result includes:\n- providerDocID: tfprovider-compatible identifier\n- Title: Service or resource name\n- Category: Type of document\n- Description: Brief summary of the document\n")
 	builder.WriteString("For best results, select libraries based on the service_slug match and category of information requested.\n\n---\n\n")
 
 	contentAvailable := false
 	for _, doc := range providerDocs.Docs {
-		if doc.Language == "hcl" && doc.Category == providerDetail.ProviderDataType {
+		if doc.Language == providerDetail.Language && doc.Category == providerDetail.ProviderDataType {
 			cs, err := utils.ContainsSlug(doc.Slug, serviceSlug)
 			cs_pn, err_pn := utils.ContainsSlug(fmt.Sprintf("%s_%s", providerDetail.ProviderName, doc.Slug), serviceSlug)
 			if (cs || cs_pn) && err == nil && err_pn == nil {
 				contentAvailable = true
-				descriptionSnippet, err := getContentSnippet(httpClient, doc.ID, logger)
+				descriptionSnippet, err := getContentSnippet(ctx, httpClient, doc.ID, logger)
 				if err != nil {
 					logger.Warnf("Error fetching content snippet for provider doc ID: %s: %v", doc.ID, err)
 				}
@@ -144,7 +148,7 @@ func resolveProviderDocIDHandler(ctx context.Context, request mcp.CallToolReques
 	return mcp.NewToolResultText(builder.String()), nil
 }
 
-func resolveProviderDetails(request mcp.CallToolRequest, httpClient *http.Client, defaultErrorGuide string, logger *log.Logger) (client.ProviderDetail, error) {
+func resolveProviderDetails(ctx context.Context, request mcp.CallToolRequest, httpClient *http.Client, defaultErrorGuide string, logger *log.Logger) (client.ProviderDetail, error) {
 	providerDetail := client.ProviderDetail{}
 	providerName := request.GetString("provider_name", "")
 	if providerName == "" {
@@ -158,6 +162,7 @@ func resolveProviderDetails(request mcp.CallToolRequest, httpClient *http.Client
 		providerNamespace = "hashicorp"
 	}
 	providerNamespace = strings.ToLower(providerNamespace)
+	providerNamespace, providerName = client.ResolveProviderAlias(providerNamespace, providerName, logger)
 
 	providerVersion := request.GetString("provider_version", "latest")
 	providerVersion = strings.ToLower(providerVersion)
@@ -165,12 +170,18 @@ func resolveProviderDetails(request mcp.CallToolRequest, httpClient *http.Client
 	providerDataType := request.GetString("provider_data_type", "resources")
 	providerDataType = strings.ToLower(providerDataType)
 
+	language := strings.ToLower(request.GetString("language", "hcl"))
+	if !utils.IsValidProviderDocLanguage(language) {
+		return providerDetail, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid language",
+			fmt.Errorf("language must be one of %s", strings.Join(utils.ProviderDocLanguages, ", ")))
+	}
+
 	var err error
 	providerVersionValue := ""
 	if utils.IsValidProviderVersionFormat(providerVersion) {
 		providerVersionValue = providerVersion
 	} else {
-		providerVersionValue, err = client.GetLatestProviderVersion(httpClient, providerNamespace, providerName, logger)
+		providerVersionValue, err = client.GetLatestProviderVersion(ctx, httpClient, providerNamespace, providerName, logger)
 		if err != nil {
 			providerVersionValue = ""
 			logger.Debugf("Error getting latest provider version in %s namespace: %v", providerNamespace, err)
@@ -180,13 +191,22 @@ func resolveProviderDetails(request mcp.CallToolRequest, httpClient *http.Client
 	// If the provider version doesn't exist, try the hashicorp namespace
 	if providerVersionValue == "" {
 		tryProviderNamespace := "hashicorp"
-		providerVersionValue, err = client.GetLatestProviderVersion(httpClient, tryProviderNamespace, providerName, logger)
+		providerVersionValue, err = client.GetLatestProviderVersion(ctx, httpClient, tryProviderNamespace, providerName, logger)
 		if err != nil {
 			// Just so we don't print the same namespace twice if they are the same
 			if providerNamespace != tryProviderNamespace {
 				tryProviderNamespace = fmt.Sprintf(`"%s" or the "%s"`, providerNamespace, tryProviderNamespace)
 			}
-			return providerDetail, utils.LogAndReturnError(logger, fmt.Sprintf(`getting the "%s" provider, with version "%s" in the %s namespace, %s`, providerName, providerVersion, tryProviderNamespace, defaultErrorGuide), nil)
+			guide := defaultErrorGuide
+			if client.ClassifyError(err) == utils.ErrorCodeNotFound {
+				// Point to the namespaces publishing the provider, or else to providers with a close name
+				if namespaces := findProviderNamespaces(ctx, httpClient, providerName, []string{providerNamespace, "hashicorp"}, logger); len(namespaces) > 0 {
+					guide += tryNamespaces(providerName, namespaces)
+				} else {
+					guide += didYouMean(suggestProviders(ctx, httpClient, providerNamespace, providerName, logger))
+				}
+			}
+			return providerDetail, utils.LogAndReturnErrorWithCode(logger, client.ClassifyError(err), fmt.Sprintf(`getting the "%s" provider, with version "%s" in the %s namespace, %s`, providerName, providerVersion, tryProviderNamespace, guide), nil)
 		}
 		providerNamespace = tryProviderNamespace // Update the namespace to hashicorp, if successful
 	}
@@ -200,24 +220,33 @@ func resolveProviderDetails(request mcp.CallToolRequest, httpClient *http.Client
 	providerDetail.ProviderNamespace = providerNamespace
 	providerDetail.ProviderVersion = providerVersionValue
 	providerDetail.ProviderDataType = providerDataTypeValue
+	providerDetail.Language = language
 	return providerDetail, nil
 }
 
 // providerDetailsV2 retrieves a list of documentation items for a specific provider category using v2 API with support for pagination using page numbers
-func providerDetailsV2(httpClient *http.Client, providerDetail client.ProviderDetail, logger *log.Logger) (string, error) {
-	providerVersionID, err := client.GetProviderVersionID(httpClient, providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion, logger)
+func providerDetailsV2(ctx context.Context, httpClient *http.Client, providerDetail client.ProviderDetail, serviceSlug string, logger *log.Logger) (string, error) {
+	providerVersionID, err := client.GetProviderVersionID(ctx, httpClient, providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion, logger)
 	if err != nil {
 		return "", utils.LogAndReturnError(logger, "getting provider version ID", err)
 	}
 	category := providerDetail.ProviderDataType
 	if category == "overview" {
-		return client.GetProviderOverviewDocs(httpClient, providerVersionID, logger)
+		content, err := client.GetProviderOverviewDocs(ctx, httpClient, providerVersionID, logger)
+		if err != nil {
+			return "", err
+		}
+		// The overview is nested under the "provider docs" heading
+		return utils.SanitizeMarkdown(content, utils.MarkdownOptions{
+			BaseURL:         fmt.Sprintf("%s/providers/%s/%s/%s/docs/", client.RegistryAddress(), providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion),
+			TopHeadingLevel: 2,
+		}), nil
 	}
 
-	uriPrefix := fmt.Sprintf("provider-docs?filter[provider-version]=%s&filter[category]=%s&filter[language]=hcl",
-		providerVersionID, category)
+	uriPrefix := fmt.Sprintf("provider-docs?filter[provider-version]=%s&filter[category]=%s&filter[language]=%s",
+		providerVersionID, category, providerDetail.Language)
 
-	docs, err := client.SendPaginatedRegistryCall(httpClient, uriPrefix, logger)
+	docs, err := client.SendPaginatedRegistryCall(ctx, httpClient, uriPrefix, logger)
 	if err != nil {
 		return "", utils.LogAndReturnError(logger, "getting provider documentation", err)
 	}
@@ -225,13 +254,16 @@ func providerDetailsV2(httpClient *http.Client, providerDetail client.ProviderDe
 	if len(docs) == 0 {
 		return "", fmt.Errorf("no %s documentation found for provider version %s", category, providerVersionID)
 	}
+	if category == "ephemeral-resources" {
+		docs = filterDocsBySlug(docs, providerDetail.ProviderName, serviceSlug)
+	}
 
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("Available Documentation (top matches) for %s in Terraform provider %s/%s version: %s\n\n", providerDetail.ProviderDataType, providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion))
+	builder.WriteString(fmt.Sprintf("Available %s Documentation (top matches) for %s in Terraform provider %s/%s version: %s\n\n", strings.ToUpper(providerDetail.Language), providerDetail.ProviderDataType, providerDetail.ProviderNamespace, providerDetail.ProviderName, providerDetail.ProviderVersion))
 	builder.WriteString("Each result includes:\n- providerDocID: tfprovider-compatible identifier\n- Title: Service or resource name\n- Category: Type of document\n- Description: Brief summary of the document\n")
 	builder.WriteString("For best results, select libraries based on the service_slug match and category of information requested.\n\n---\n\n")
 	for _, doc := range docs {
-		descriptionSnippet, err := getContentSnippet(httpClient, doc.ID, logger)
+		descriptionSnippet, err := getContentSnippet(ctx, httpClient, doc.ID, logger)
 		if err != nil {
 			logger.Warnf("Error fetching content snippet for provider doc ID: %s: %v", doc.ID, err)
 		}
@@ -241,13 +273,29 @@ func providerDetailsV2(httpClient *http.Client, providerDetail client.ProviderDe
 	return builder.String(), nil
 }
 
-func getContentSnippet(httpClient *http.Client, docID string, logger *log.Logger) (string, error) {
-	docContent, err := client.SendRegistryCall(httpClient, "GET", fmt.Sprintf("provider-docs/%s", docID), logger, "v2")
+// filterDocsBySlug keeps the docs matching the service slug like the v1 resources lookup, or every doc when none matches
+func filterDocsBySlug(docs []client.ProviderDocData, providerName, serviceSlug string) []client.ProviderDocData {
+	var matching []client.ProviderDocData
+	for _, doc := range docs {
+		cs, err := utils.ContainsSlug(doc.Attributes.Slug, serviceSlug)
+		cs_pn, err_pn := utils.ContainsSlug(fmt.Sprintf("%s_%s", providerName, doc.Attributes.Slug), serviceSlug)
+		if (cs || cs_pn) && err == nil && err_pn == nil {
+			matching = append(matching, doc)
+		}
+	}
+	if len(matching) == 0 {
+		return docs
+	}
+	return matching
+}
+
+func getContentSnippet(ctx context.Context, httpClient *http.Client, docID string, logger *log.Logger) (string, error) {
+	docContent, err := client.SendRegistryCall(ctx, httpClient, "GET", fmt.Sprintf("provider-docs/%s", docID), logger, "v2")
 	if err != nil {
 		return "", utils.LogAndReturnError(logger, fmt.Sprintf("fetching provider-docs/%s within getContentSnippet", docID), err)
 	}
 	var docDescription client.ProviderResourceDetails
-	if err := json.Unmarshal(docContent, &docDescription); err != nil {
+	if err := client.DecodeRegistryResponse(docContent, &docDescription, logger); err != nil {
 		return "", utils.LogAndReturnError(logger, fmt.Sprintf("unmarshalling provider-docs/%s within getContentSnippet", docID), err)
 	}
 
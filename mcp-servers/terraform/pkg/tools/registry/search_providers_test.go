@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+)
+
+func TestFilterDocsBySlug(t *testing.T) {
+	docs := make([]client.ProviderDocData, 3)
+	for i, slug := range []string{"secretsmanager_secret_version", "kms_secrets", "ssm_parameter"} {
+		docs[i].ID = slug
+		docs[i].Attributes.Slug = slug
+	}
+
+	ids := func(docs []client.ProviderDocData) []string {
+		var ids []string
+		for _, doc := range docs {
+			ids = append(ids, doc.ID)
+		}
+		return ids
+	}
+	if got := ids(filterDocsBySlug(docs, "aws", "secret")); len(got) != 2 || got[0] != "secretsmanager_secret_version" || got[1] != "kms_secrets" {
+		t.Errorf("unexpected docs %v", got)
+	}
+	if got := ids(filterDocsBySlug(docs, "aws", "aws_ssm")); len(got) != 1 || got[0] != "ssm_parameter" {
+		t.Errorf("unexpected docs %v", got)
+	}
+	// Without a match every doc is kept so that the agent can pick one by title
+	if got := filterDocsBySlug(docs, "aws", "nothing"); len(got) != 3 {
+		t.Errorf("expected every doc, got %v", ids(got))
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	defaultSemanticSearchLimit = 5
+	maxSemanticSearchLimit     = 20
+)
+
+func SemanticSearchDocs(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("semantic_search_docs",
+			mcp.WithDescription(`Searches the provider and module documentation fetched earlier by meaning rather than by name, e.g. "serverless container on AWS" or "managed postgres with private networking".
+Only documents already returned by 'get_provider_details' or 'get_module_details' are indexed, so use 'search_providers' and 'search_modules' to discover new documentation.
+Each result has the provider_doc_id or module_id to pass to 'get_provider_details' or 'get_module_details'.`),
+			mcp.WithTitleAnnotation("Search cached Terraform documentation by meaning"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("A description of what you are looking for, in natural language"),
+			),
+			mcp.WithString("kind",
+				mcp.Description("Only return documents of this kind"),
+				mcp.Enum("all", client.SemanticKindProviderDoc, client.SemanticKindModule),
+				mcp.DefaultString("all"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description(fmt.Sprintf("The number of results to return, at most %d", maxSemanticSearchLimit)),
+				mcp.DefaultNumber(defaultSemanticSearchLimit),
+				mcp.Min(1),
+				mcp.Max(maxSemanticSearchLimit),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return semanticSearchDocsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func semanticSearchDocsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil || strings.TrimSpace(query) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "query is required", err)
+	}
+	kind := request.GetString("kind", "all")
+	if kind == "all" {
+		kind = ""
+	}
+	limit := min(max(request.GetInt("limit", defaultSemanticSearchLimit), 1), maxSemanticSearchLimit)
+
+	index := client.GetSemanticIndex(logger)
+	if index == nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "semantic search",
+			fmt.Errorf("semantic search is not configured, set %s to an embeddings endpoint", client.EmbeddingsURL))
+	}
+	if index.Len() == 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "semantic search",
+			fmt.Errorf("no documentation has been indexed yet, fetch documents with get_provider_details or get_module_details first"))
+	}
+
+	matches, err := index.Search(ctx, query, kind, limit)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "semantic search", err)
+	}
+	if len(matches) == 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, "semantic search",
+			fmt.Errorf("no %s documents have been indexed yet", request.GetString("kind", "all")))
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Documents most similar to %q\n\n", query))
+	builder.WriteString("Each result includes:\n")
+	builder.WriteString("- provider_doc_id or module_id: The ID to pass to get_provider_details or get_module_details\n")
+	builder.WriteString("- Title: The title of the document\n")
+	builder.WriteString("- Score: The similarity to the query, from 0 to 1\n")
+	builder.WriteString("- Snippet: The start of the document\n")
+	builder.WriteString("\n\n---\n\n")
+	for i, match := range matches {
+		idLabel := "provider_doc_id"
+		if match.Kind == client.SemanticKindModule {
+			idLabel = "module_id"
+		}
+		builder.WriteString(fmt.Sprintf("%d. %s: %s\n", i+1, idLabel, match.ID))
+		builder.WriteString(fmt.Sprintf("- Title: %s\n", match.Title))
+		builder.WriteString(fmt.Sprintf("- Score: %.2f\n", match.Score))
+		builder.WriteString(fmt.Sprintf("- Snippet: %s\n", match.Snippet))
+		builder.WriteString("---\n\n")
+	}
+	return mcp.NewToolResultText(builder.String()), nil
+}
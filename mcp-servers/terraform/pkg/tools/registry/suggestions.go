@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxSuggestions is the number of candidates offered when a provider or module name is not found
+const maxSuggestions = 3
+
+// suggestion is a candidate name with its edit distance to the requested name
+type suggestion struct {
+	name      string
+	distance  int
+	downloads int64
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
+
+// nameDistance scores how close a candidate is to the requested name. Names containing each
+// other, e.g. "vault" and "vaults", are as close as a single typo.
+func nameDistance(requested, candidate string) (int, bool) {
+	requested, candidate = strings.ToLower(requested), strings.ToLower(candidate)
+	distance := levenshtein(requested, candidate)
+	if distance > 0 && (strings.Contains(candidate, requested) || strings.Contains(requested, candidate)) {
+		distance = min(distance, 1)
+	}
+	// Allow roughly one typo per three characters
+	return distance, distance <= max(1, len([]rune(requested))/3)
+}
+
+// rankSuggestions returns the names of the closest candidates, the most downloaded first among equally close ones
+func rankSuggestions(candidates []suggestion) []string {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].downloads > candidates[j].downloads
+	})
+
+	names := make([]string, 0, maxSuggestions)
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if len(names) == maxSuggestions {
+			break
+		}
+		if !seen[candidate.name] {
+			seen[candidate.name] = true
+			names = append(names, candidate.name)
+		}
+	}
+	return names
+}
+
+// matchProviders returns the official and partner providers whose name is close to name, in any namespace
+func matchProviders(providers client.ProviderList, namespace, name string) []string {
+	var candidates []suggestion
+	for _, provider := range providers.Data {
+		distance, ok := nameDistance(name, provider.Attributes.Name)
+		if !ok {
+			continue
+		}
+		// The exact provider that was not found cannot be a suggestion
+		if distance == 0 && strings.EqualFold(provider.Attributes.Namespace, namespace) {
+			continue
+		}
+		candidates = append(candidates, suggestion{
+			name:      strings.ToLower(provider.Attributes.Namespace + "/" + provider.Attributes.Name),
+			distance:  distance,
+			downloads: int64(provider.Attributes.Downloads),
+		})
+	}
+	return rankSuggestions(candidates)
+}
+
+// suggestProviders looks for official and partner providers with a name close to name. Failures
+// only lose the suggestions, so they are logged and not returned.
+func suggestProviders(ctx context.Context, httpClient *http.Client, namespace, name string, logger *log.Logger) []string {
+	providers, err := fetchPopularProviders(ctx, httpClient, "", true, logger)
+	if err != nil {
+		logger.Debugf("Listing providers for suggestions: %v", err)
+		return nil
+	}
+	return matchProviders(providers, namespace, name)
+}
+
+// matchProviderNamespaces returns the namespaces publishing a provider named exactly name, the most
+// downloaded first, leaving out the namespaces already tried
+func matchProviderNamespaces(providers client.ProviderList, name string, tried []string) []string {
+	var candidates []suggestion
+	for _, provider := range providers.Data {
+		namespace := strings.ToLower(provider.Attributes.Namespace)
+		if !strings.EqualFold(provider.Attributes.Name, name) || slices.Contains(tried, namespace) {
+			continue
+		}
+		candidates = append(candidates, suggestion{name: namespace, downloads: int64(provider.Attributes.Downloads)})
+	}
+	return rankSuggestions(candidates)
+}
+
+// findProviderNamespaces looks for providers named name in every namespace, community ones included,
+// when the provider is not in the namespaces tried. Failures only lose the candidates.
+func findProviderNamespaces(ctx context.Context, httpClient *http.Client, name string, tried []string, logger *log.Logger) []string {
+	query := url.Values{}
+	query.Set("filter[name]", name)
+	query.Set("page[size]", fmt.Sprint(popularPageSize))
+
+	response, err := client.SendRegistryCall(ctx, httpClient, http.MethodGet, "providers?"+query.Encode(), logger, "v2")
+	if err != nil {
+		logger.Debugf("Listing providers named %s for namespace candidates: %v", name, err)
+		return nil
+	}
+	var providers client.ProviderList
+	if err := client.DecodeRegistryResponse(response, &providers, logger); err != nil {
+		logger.Debugf("Unmarshalling providers for namespace candidates: %v", err)
+		return nil
+	}
+	return matchProviderNamespaces(providers, name, tried)
+}
+
+// matchModules returns the modules whose name is close to name, as namespace/name/provider
+func matchModules(modules client.TerraformModules, name string) []string {
+	var candidates []suggestion
+	for _, module := range modules.Data {
+		distance, ok := nameDistance(name, module.Name)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, suggestion{
+			name:      strings.ToLower(fmt.Sprintf("%s/%s/%s", module.Namespace, module.Name, module.Provider)),
+			distance:  distance,
+			downloads: module.Downloads,
+		})
+	}
+	return rankSuggestions(candidates)
+}
+
+// suggestModules searches the registry for modules with a name close to name, for the given provider if set
+func suggestModules(ctx context.Context, httpClient *http.Client, name, provider string, logger *log.Logger) []string {
+	query := url.Values{}
+	query.Set("q", name)
+	query.Set("limit", fmt.Sprint(popularPageSize))
+	if provider != "" {
+		query.Set("provider", provider)
+	}
+
+	response, err := client.SendRegistryCall(ctx, httpClient, http.MethodGet, "modules/search?"+query.Encode(), logger)
+	if err != nil {
+		logger.Debugf("Searching modules for suggestions: %v", err)
+		return nil
+	}
+	var modules client.TerraformModules
+	if err := client.DecodeRegistryResponse(response, &modules, logger); err != nil {
+		logger.Debugf("Unmarshalling modules for suggestions: %v", err)
+		return nil
+	}
+	return matchModules(modules, name)
+}
+
+// didYouMean renders the suggestions appended to a not found error
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %s?", strings.Join(suggestions, " or "))
+}
+
+// tryNamespaces renders the namespace candidates appended to a not found error
+func tryNamespaces(name string, namespaces []string) string {
+	if len(namespaces) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", providers named %s are published under these namespaces: %s, retry with one of them as provider_namespace",
+		name, strings.Join(namespaces, ", "))
+}
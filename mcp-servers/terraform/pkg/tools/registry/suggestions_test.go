@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"vault", "vault", 0},
+		{"vaults", "vault", 1},
+		{"azurem", "azurerm", 1},
+		{"gogle", "google", 1},
+		{"kubernetes", "kubernets", 1},
+		{"aws", "gcp", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMatchProviders(t *testing.T) {
+	var providers client.ProviderList
+	err := json.Unmarshal([]byte(`{"data": [
+		{"attributes": {"namespace": "hashicorp", "name": "vault", "downloads": 5000}},
+		{"attributes": {"namespace": "hashicorp", "name": "aws", "downloads": 90000}},
+		{"attributes": {"namespace": "hashicorp", "name": "azurerm", "downloads": 40000}},
+		{"attributes": {"namespace": "integrations", "name": "github", "downloads": 3000}},
+		{"attributes": {"namespace": "DataDog", "name": "datadog", "downloads": 2000}}
+	]}`), &providers)
+	if err != nil {
+		t.Fatalf("unmarshalling providers: %v", err)
+	}
+
+	tests := []struct {
+		namespace, name string
+		want            []string
+	}{
+		{"hashicorp", "vaults", []string{"hashicorp/vault"}},
+		{"hashicorp", "azurem", []string{"hashicorp/azurerm"}},
+		{"hashicorp", "github", []string{"integrations/github"}},
+		{"hashicorp", "datadog", []string{"datadog/datadog"}},
+		{"hashicorp", "vault", nil},
+		{"hashicorp", "nothing-like-it", nil},
+	}
+	for _, tt := range tests {
+		got := matchProviders(providers, tt.namespace, tt.name)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchProviders(%q, %q) = %v, want %v", tt.namespace, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchModules(t *testing.T) {
+	var modules client.TerraformModules
+	err := json.Unmarshal([]byte(`{"modules": [
+		{"namespace": "terraform-aws-modules", "name": "vpc", "provider": "aws", "downloads": 90000},
+		{"namespace": "someone", "name": "vpcs", "provider": "aws", "downloads": 10},
+		{"namespace": "terraform-aws-modules", "name": "eks", "provider": "aws", "downloads": 80000}
+	]}`), &modules)
+	if err != nil {
+		t.Fatalf("unmarshalling modules: %v", err)
+	}
+
+	got := matchModules(modules, "vpcs")
+	want := []string{"someone/vpcs/aws", "terraform-aws-modules/vpc/aws"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchModules() = %v, want %v", got, want)
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	if got := didYouMean(nil); got != "" {
+		t.Errorf("expected no suggestion, got %q", got)
+	}
+	if got := didYouMean([]string{"hashicorp/vault"}); got != ", did you mean hashicorp/vault?" {
+		t.Errorf("unexpected suggestion %q", got)
+	}
+	if got := didYouMean([]string{"a/b", "c/d"}); got != ", did you mean a/b or c/d?" {
+		t.Errorf("unexpected suggestions %q", got)
+	}
+}
+
+func TestMatchProviderNamespaces(t *testing.T) {
+	var providers client.ProviderList
+	err := json.Unmarshal([]byte(`{"data": [
+		{"attributes": {"namespace": "hashicorp", "name": "proxmox", "downloads": 10}},
+		{"attributes": {"namespace": "Telmate", "name": "proxmox", "downloads": 9000}},
+		{"attributes": {"namespace": "bpg", "name": "proxmox", "downloads": 12000}},
+		{"attributes": {"namespace": "someone", "name": "proxmox-ve", "downloads": 50000}}
+	]}`), &providers)
+	if err != nil {
+		t.Fatalf("unmarshalling providers: %v", err)
+	}
+
+	got := matchProviderNamespaces(providers, "proxmox", []string{"hashicorp"})
+	want := []string{"bpg", "telmate"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchProviderNamespaces() = %v, want %v", got, want)
+	}
+	if got := matchProviderNamespaces(providers, "nothing", nil); len(got) != 0 {
+		t.Errorf("expected no namespace, got %v", got)
+	}
+
+	if got := tryNamespaces("proxmox", want); got != ", providers named proxmox are published under these namespaces: bpg, telmate, retry with one of them as provider_namespace" {
+		t.Errorf("unexpected namespace candidates %q", got)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxSignatureFileBytes bounds the size of the checksums file and of its signature
+const maxSignatureFileBytes = 1 << 20
+
+// Trust levels of a provider, as Terraform reports them when installing it
+const (
+	trustOfficial  = "official"
+	trustPartner   = "partner"
+	trustCommunity = "community"
+)
+
+// ProviderSignatureReport is the result of the verify_provider_signature tool
+type ProviderSignatureReport struct {
+	Provider            string          `json:"provider"`
+	Version             string          `json:"version"`
+	OS                  string          `json:"os"`
+	Arch                string          `json:"arch"`
+	Filename            string          `json:"filename"`
+	Shasum              string          `json:"shasum"`
+	ShasumsURL          string          `json:"shasums_url"`
+	ShasumsSignatureURL string          `json:"shasums_signature_url"`
+	ShasumListed        bool            `json:"shasum_listed"`
+	SignatureValid      bool            `json:"signature_valid"`
+	SignatureError      string          `json:"signature_error,omitempty"`
+	Signer              *ProviderSigner `json:"signer,omitempty"`
+	TrustLevel          string          `json:"trust_level"`
+	Verified            bool            `json:"verified"`
+	Notes               []string        `json:"notes,omitempty"`
+}
+
+// ProviderSigner is the GPG key that signed the checksums of a provider release
+type ProviderSigner struct {
+	KeyID             string    `json:"key_id"`
+	Fingerprint       string    `json:"fingerprint"`
+	Identities        []string  `json:"identities"`
+	CreatedAt         time.Time `json:"created_at"`
+	Source            string    `json:"source,omitempty"`
+	SourceURL         string    `json:"source_url,omitempty"`
+	HasTrustSignature bool      `json:"has_trust_signature"`
+}
+
+// VerifyProviderSignature creates a tool to verify the checksums and signature of a provider release.
+func VerifyProviderSignature(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("verify_provider_signature",
+			mcp.WithDescription(`Verifies the release of a provider version for a platform the way 'terraform init' does: reads its SHA256SUMS file and detached signature from the registry, checks the signature against the signing keys the registry publishes for the namespace, and that the checksum of the package is listed.
+Reports whether the signature is valid, the key ID, fingerprint and identities of the signing key, and the trust level of the provider (official, partner or community), so that third-party providers can be vetted before they are adopted. The provider package itself is not downloaded.`),
+			mcp.WithTitleAnnotation("Verify the signature of a provider release"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("namespace",
+				mcp.Required(),
+				mcp.Description("The namespace of the Terraform provider, e.g., 'hashicorp'")),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("The name of the Terraform provider, e.g., 'aws'")),
+			mcp.WithString("version",
+				mcp.Description("The provider version to verify (default: the latest version)")),
+			mcp.WithString("os",
+				mcp.Description("The operating system of the package"),
+				mcp.DefaultString("linux")),
+			mcp.WithString("arch",
+				mcp.Description("The architecture of the package"),
+				mcp.DefaultString("amd64")),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return verifyProviderSignatureHandler(ctx, req, logger)
+		},
+	}
+}
+
+func verifyProviderSignatureHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	namespace, err := request.RequireString("namespace")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: namespace of the Terraform provider is required", err)
+	}
+	name, err := request.RequireString("name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: name of the Terraform provider is required", err)
+	}
+	namespace, name = client.ResolveProviderAlias(strings.ToLower(strings.TrimSpace(namespace)), strings.ToLower(strings.TrimSpace(name)), logger)
+	version := strings.TrimPrefix(strings.TrimSpace(request.GetString("version", "")), "v")
+	goos := strings.ToLower(strings.TrimSpace(request.GetString("os", "linux")))
+	arch := strings.ToLower(strings.TrimSpace(request.GetString("arch", "amd64")))
+
+	httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+	if err != nil {
+		logger.WithError(err).Error("failed to get http client for public Terraform registry")
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get http client for public Terraform registry: %v", err)), nil
+	}
+
+	if version == "" {
+		version, err = client.GetLatestProviderVersion(ctx, httpClient, namespace, name, logger)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "fetching latest provider version", err)
+		}
+	}
+	providerPackage, err := client.GetProviderPackage(ctx, httpClient, namespace, name, version, goos, arch, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("fetching the %s_%s package of provider %s/%s %s", goos, arch, namespace, name, version), err)
+	}
+
+	shasums, err := fetchSignatureFile(ctx, httpClient, providerPackage.ShasumsURL)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "downloading the checksums file", err)
+	}
+	signature, err := fetchSignatureFile(ctx, httpClient, providerPackage.ShasumsSignatureURL)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "downloading the checksums signature", err)
+	}
+
+	report := verifyProviderPackage(namespace+"/"+name, version, providerPackage, shasums, signature)
+	resultJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling signature report", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// verifyProviderPackage checks the detached signature of the checksums file against the signing keys of the
+// package, and that the checksum of the package is listed in it
+func verifyProviderPackage(provider string, version string, providerPackage *client.ProviderPackage, shasums []byte, signature []byte) ProviderSignatureReport {
+	report := ProviderSignatureReport{
+		Provider:            provider,
+		Version:             version,
+		OS:                  providerPackage.OS,
+		Arch:                providerPackage.Arch,
+		Filename:            providerPackage.Filename,
+		Shasum:              providerPackage.Shasum,
+		ShasumsURL:          providerPackage.ShasumsURL,
+		ShasumsSignatureURL: providerPackage.ShasumsSignatureURL,
+		ShasumListed:        shasumListed(shasums, providerPackage.Filename, providerPackage.Shasum),
+		TrustLevel:          trustCommunity,
+	}
+	if !report.ShasumListed {
+		report.Notes = append(report.Notes, fmt.Sprintf("The checksum of %s published by the registry is not listed in the checksums file", providerPackage.Filename))
+	}
+
+	var keyring openpgp.EntityList
+	keys := make(map[uint64]client.ProviderGPGPublicKey)
+	for _, key := range providerPackage.SigningKeys.GPGPublicKeys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.ASCIIArmor))
+		if err != nil {
+			report.Notes = append(report.Notes, fmt.Sprintf("The signing key %s could not be read: %v", key.KeyID, err))
+			continue
+		}
+		for _, entity := range entities {
+			keys[entity.PrimaryKey.KeyId] = key
+		}
+		keyring = append(keyring, entities...)
+	}
+	if len(keyring) == 0 {
+		report.SignatureError = "the registry publishes no usable signing key for this provider"
+		return report
+	}
+
+	signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(shasums), bytes.NewReader(signature), nil)
+	if err != nil {
+		report.SignatureError = err.Error()
+		return report
+	}
+	report.SignatureValid = true
+
+	key := keys[signer.PrimaryKey.KeyId]
+	report.Signer = &ProviderSigner{
+		KeyID:             strings.ToUpper(signer.PrimaryKey.KeyIdString()),
+		Fingerprint:       strings.ToUpper(fmt.Sprintf("%x", signer.PrimaryKey.Fingerprint)),
+		Identities:        []string{},
+		CreatedAt:         signer.PrimaryKey.CreationTime.UTC(),
+		Source:            key.Source,
+		SourceURL:         key.SourceURL,
+		HasTrustSignature: key.TrustSignature != "",
+	}
+	for identity := range signer.Identities {
+		report.Signer.Identities = append(report.Signer.Identities, identity)
+	}
+	sort.Strings(report.Signer.Identities)
+
+	switch {
+	case strings.HasPrefix(provider, "hashicorp/"):
+		report.TrustLevel = trustOfficial
+	case report.Signer.HasTrustSignature:
+		report.TrustLevel = trustPartner
+		report.Notes = append(report.Notes, "The signing key carries a trust signature, which 'terraform init' checks against the HashiCorp partners key")
+	}
+	report.Verified = report.SignatureValid && report.ShasumListed
+	return report
+}
+
+// shasumListed reports whether the SHA256SUMS file lists the checksum for the file name
+func shasumListed(shasums []byte, filename string, shasum string) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(shasums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == filename {
+			return strings.EqualFold(fields[0], shasum)
+		}
+	}
+	return false
+}
+
+// fetchSignatureFile downloads the checksums file of a release or its signature
+func fetchSignatureFile(ctx context.Context, httpClient *http.Client, url string) ([]byte, error) {
+	if url == "" {
+		return nil, fmt.Errorf("the registry returned no URL")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &client.RegistryStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSignatureFileBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxSignatureFileBytes {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, maxSignatureFileBytes)
+	}
+	return body, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSigningKey generates a signing key and returns it with its ASCII-armored public key
+func testSigningKey(t *testing.T, name string) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	require.NoError(t, err)
+
+	var public bytes.Buffer
+	writer, err := armor.Encode(&public, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(writer))
+	require.NoError(t, writer.Close())
+	return entity, public.String()
+}
+
+func TestVerifyProviderPackage(t *testing.T) {
+	signer, publicKey := testSigningKey(t, "Acme")
+	_, otherKey := testSigningKey(t, "Other")
+
+	shasums := []byte("0123abcd  terraform-provider-widget_1.2.0_darwin_arm64.zip\n4567ef01  terraform-provider-widget_1.2.0_linux_amd64.zip\n")
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.DetachSign(&signature, signer, bytes.NewReader(shasums), nil))
+
+	providerPackage := func(shasum string, keys ...client.ProviderGPGPublicKey) *client.ProviderPackage {
+		return &client.ProviderPackage{
+			OS:          "linux",
+			Arch:        "amd64",
+			Filename:    "terraform-provider-widget_1.2.0_linux_amd64.zip",
+			Shasum:      shasum,
+			SigningKeys: client.ProviderSigningKeys{GPGPublicKeys: keys},
+		}
+	}
+
+	t.Run("verifies a signed release", func(t *testing.T) {
+		key := client.ProviderGPGPublicKey{KeyID: "ABC", ASCIIArmor: publicKey, TrustSignature: "-----BEGIN PGP SIGNATURE-----", Source: "Acme"}
+		report := verifyProviderPackage("acme/widget", "1.2.0", providerPackage("4567ef01", client.ProviderGPGPublicKey{ASCIIArmor: otherKey}, key), shasums, signature.Bytes())
+
+		assert.True(t, report.SignatureValid)
+		assert.True(t, report.ShasumListed)
+		assert.True(t, report.Verified)
+		require.NotNil(t, report.Signer)
+		assert.Equal(t, strings.ToUpper(signer.PrimaryKey.KeyIdString()), report.Signer.KeyID)
+		assert.Equal(t, []string{"Acme <Acme@example.com>"}, report.Signer.Identities)
+		assert.Equal(t, "Acme", report.Signer.Source)
+		assert.Equal(t, trustPartner, report.TrustLevel)
+	})
+
+	t.Run("reports an official provider", func(t *testing.T) {
+		report := verifyProviderPackage("hashicorp/widget", "1.2.0", providerPackage("4567ef01", client.ProviderGPGPublicKey{ASCIIArmor: publicKey}), shasums, signature.Bytes())
+		assert.True(t, report.Verified)
+		assert.Equal(t, trustOfficial, report.TrustLevel)
+	})
+
+	t.Run("rejects a signature by another key", func(t *testing.T) {
+		report := verifyProviderPackage("acme/widget", "1.2.0", providerPackage("4567ef01", client.ProviderGPGPublicKey{ASCIIArmor: otherKey}), shasums, signature.Bytes())
+		assert.False(t, report.SignatureValid)
+		assert.False(t, report.Verified)
+		assert.NotEmpty(t, report.SignatureError)
+		assert.Nil(t, report.Signer)
+		assert.Equal(t, trustCommunity, report.TrustLevel)
+	})
+
+	t.Run("rejects tampered checksums", func(t *testing.T) {
+		tampered := bytes.Replace(shasums, []byte("4567ef01"), []byte("deadbeef"), 1)
+		report := verifyProviderPackage("acme/widget", "1.2.0", providerPackage("deadbeef", client.ProviderGPGPublicKey{ASCIIArmor: publicKey}), tampered, signature.Bytes())
+		assert.False(t, report.SignatureValid)
+		assert.True(t, report.ShasumListed)
+		assert.False(t, report.Verified)
+	})
+
+	t.Run("flags a checksum missing from the checksums file", func(t *testing.T) {
+		report := verifyProviderPackage("acme/widget", "1.2.0", providerPackage("ffff0000", client.ProviderGPGPublicKey{ASCIIArmor: publicKey}), shasums, signature.Bytes())
+		assert.True(t, report.SignatureValid)
+		assert.False(t, report.ShasumListed)
+		assert.False(t, report.Verified)
+		assert.NotEmpty(t, report.Notes)
+	})
+
+	t.Run("needs a signing key", func(t *testing.T) {
+		report := verifyProviderPackage("acme/widget", "1.2.0", providerPackage("4567ef01"), shasums, signature.Bytes())
+		assert.False(t, report.SignatureValid)
+		assert.Contains(t, report.SignatureError, "no usable signing key")
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 
 	"github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/terraform-mcp-server/pkg/client"
@@ -20,7 +21,7 @@ import (
 func ActionRun(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("action_run",
-			mcp.WithDescription(`Performs a variety of actions on a Terraform run. It can be used to approve and apply, discard or cancel a run.`),
+			mcp.WithDescription(`Performs a variety of actions on a Terraform run. It can be used to approve and apply, discard or cancel a run. Apply and discard must be confirmed: the first call returns a summary and a confirmation token, and the action is only performed when the tool is called again with the token.`),
 			mcp.WithTitleAnnotation("Apply, Discard or Cancel a Terraform run"),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
@@ -37,6 +38,8 @@ func ActionRun(logger *log.Logger) server.ServerTool {
 			mcp.WithString("comment",
 				mcp.Description("Optional comment for the action"),
 			),
+			withConfirmationToken(),
+			withDryRun(),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return actionRunHandler(ctx, req, logger)
@@ -47,12 +50,12 @@ func ActionRun(logger *log.Logger) server.ServerTool {
 func actionRunHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	runAction, err := request.RequireString("run_action")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'run_action' parameter is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'run_action' parameter is required", err)
 	}
 
 	runID, err := request.RequireString("run_id")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'run_id' parameter is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'run_id' parameter is required", err)
 	}
 
 	comment := request.GetString("comment", "Triggered via Terraform MCP Server")
@@ -62,24 +65,71 @@ func actionRunHandler(ctx context.Context, request mcp.CallToolRequest, logger *
 		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
 	}
 
-	var msg string
+	var payload any
 	switch runAction {
 	case "apply":
-		err = tfeClient.Runs.Apply(ctx, runID, tfe.RunApplyOptions{Comment: &comment})
-		msg = "Run approved and applied successfully, run the `get_run_details` tool to get more information about the run."
+		payload = &tfe.RunApplyOptions{Comment: &comment}
 	case "discard":
-		err = tfeClient.Runs.Discard(ctx, runID, tfe.RunDiscardOptions{Comment: &comment})
-		msg = "Run discarded successfully"
+		payload = &tfe.RunDiscardOptions{Comment: &comment}
 	case "cancel":
-		err = tfeClient.Runs.Cancel(ctx, runID, tfe.RunCancelOptions{Comment: &comment})
-		msg = "Run canceled successfully"
+		payload = &tfe.RunCancelOptions{Comment: &comment}
 	default:
-		return nil, utils.LogAndReturnError(logger, "invalid run action", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid run action", fmt.Errorf("unknown action %q", runAction))
+	}
+
+	// Applying and discarding a run cannot be undone, so they require a confirmation
+	dryRun := request.GetBool(dryRunParam, false)
+	if dryRun || runAction == "apply" || runAction == "discard" {
+		run, err := tfeClient.Runs.Read(ctx, runID)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading run %s", runID), err)
+		}
+
+		if dryRun {
+			effects := []string{fmt.Sprintf("Run %s is in status %s", run.ID, run.Status)}
+			switch runAction {
+			case "apply":
+				effects = append(effects, "Applies the plan of the run, changing the managed infrastructure")
+			case "discard":
+				effects = append(effects, "Discards the run, its plan can no longer be applied")
+			case "cancel":
+				effects = append(effects, "Cancels the run, interrupting its plan or apply")
+			}
+			return dryRunResult(request, "POST", fmt.Sprintf("runs/%s/actions/%s", url.PathEscape(runID), runAction), payload, effects, logger)
+		}
+
+		details := map[string]any{
+			"run_id":      run.ID,
+			"status":      run.Status,
+			"has_changes": run.HasChanges,
+			"message":     run.Message,
+		}
+		if run.Workspace != nil {
+			details["workspace_id"] = run.Workspace.ID
+		}
+		summary := fmt.Sprintf("%s run %s, which is in status %s", runAction, run.ID, run.Status)
+		if result, err := requireConfirmation(ctx, request, summary, details, logger); result != nil || err != nil {
+			return result, err
+		}
+	}
+
+	var msg string
+	switch options := payload.(type) {
+	case *tfe.RunApplyOptions:
+		err = tfeClient.Runs.Apply(ctx, runID, *options)
+		msg = "Run approved and applied successfully, run the `get_run_details` tool to get more information about the run."
+	case *tfe.RunDiscardOptions:
+		err = tfeClient.Runs.Discard(ctx, runID, *options)
+		msg = "Run discarded successfully"
+	case *tfe.RunCancelOptions:
+		err = tfeClient.Runs.Cancel(ctx, runID, *options)
+		msg = "Run canceled successfully"
 	}
 
 	if err != nil {
 		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("executing %s action on run %s", runAction, runID), err)
 	}
+	notifyRunUpdate(ctx, runID, "", "", msg)
 
 	result := map[string]interface{}{
 		"success": true,
@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// AddGPGKey creates a tool to add a GPG public key to the private registry of an organization.
+func AddGPGKey(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("add_gpg_key",
+			mcp.WithDescription(`Adds an ASCII-armored GPG public key to the private registry of a Terraform Cloud/Enterprise organization, so that private provider versions signed with the matching private key can be published.
+The key_id of the returned key is the one to pass to add_provider_platform when it creates a provider version.`),
+			mcp.WithTitleAnnotation("Add a GPG key to a private registry"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name, which is the namespace of the key"),
+			),
+			mcp.WithString("ascii_armor",
+				mcp.Required(),
+				mcp.Description("The ASCII-armored GPG public key, from '-----BEGIN PGP PUBLIC KEY BLOCK-----' to '-----END PGP PUBLIC KEY BLOCK-----'"),
+			),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return addGPGKeyHandler(ctx, request, logger)
+		},
+	}
+}
+
+func addGPGKeyHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	asciiArmor, err := request.RequireString("ascii_armor")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "ascii_armor is required", err)
+	}
+	asciiArmor = strings.TrimSpace(asciiArmor)
+	if !strings.HasPrefix(asciiArmor, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "ascii_armor must be an ASCII-armored GPG public key", nil)
+	}
+
+	options := tfe.GPGKeyCreateOptions{Namespace: terraformOrgName, AsciiArmor: asciiArmor + "\n"}
+	if request.GetBool(dryRunParam, false) {
+		effects := []string{fmt.Sprintf("Private provider versions of organization %s can be signed with the key", terraformOrgName)}
+		return dryRunResult(request, "POST", fmt.Sprintf("/api/registry/%s/v2/gpg-keys", tfe.PrivateRegistry), &options, effects, logger)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	gpgKey, err := tfeClient.GPGKeys.Create(ctx, tfe.PrivateRegistry, options)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "adding GPG key", err)
+	}
+
+	resultJSON, err := json.Marshal(summarizeGPGKey(gpgKey))
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling GPG key", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
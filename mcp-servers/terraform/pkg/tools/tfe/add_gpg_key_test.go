@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gpgKeysPath   = "/api/registry/private/v2/gpg-keys"
+	gpgPublicKey  = "-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nmQINBGM...\n-----END PGP PUBLIC KEY BLOCK-----"
+	gpgKeyIDValue = "32966F3FB5AC1129"
+)
+
+func TestGPGKeyTools(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	list := ListGPGKeys(logger)
+	assert.Equal(t, "list_gpg_keys", list.Tool.Name)
+	assert.True(t, *list.Tool.Annotations.ReadOnlyHint)
+
+	add := AddGPGKey(logger)
+	assert.Equal(t, "add_gpg_key", add.Tool.Name)
+	assert.False(t, *add.Tool.Annotations.ReadOnlyHint)
+	assert.Equal(t, []string{"terraform_org_name", "ascii_armor"}, add.Tool.InputSchema.Required)
+
+	request := func(name string, arguments map[string]any) mcp.CallToolRequest {
+		arguments["terraform_org_name"] = "acme"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name, Arguments: arguments}}
+	}
+
+	t.Run("lists the keys of the organization", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.RespondList("GET", gpgKeysPath, []*tfe.GPGKey{{ID: "13", KeyID: gpgKeyIDValue, Namespace: "acme", Source: "TerraformCloud"}},
+			&tfe.Pagination{CurrentPage: 1, TotalPages: 1})
+
+		result, err := listGPGKeysHandler(fake.Context(t), request("list_gpg_keys", map[string]any{}), logger)
+		require.NoError(t, err)
+
+		var keys GPGKeyListResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &keys))
+		require.Len(t, keys.GPGKeys, 1)
+		assert.Equal(t, gpgKeyIDValue, keys.GPGKeys[0].KeyID)
+		assert.Equal(t, []string{"acme"}, fake.Requests()[0].Query["filter[namespace]"])
+	})
+
+	t.Run("adds a key", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("POST", gpgKeysPath, http.StatusCreated, &tfe.GPGKey{ID: "13", KeyID: gpgKeyIDValue, Namespace: "acme"})
+
+		result, err := addGPGKeyHandler(fake.Context(t), request("add_gpg_key", map[string]any{"ascii_armor": gpgPublicKey}), logger)
+		require.NoError(t, err)
+
+		var key GPGKeySummary
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &key))
+		assert.Equal(t, gpgKeyIDValue, key.KeyID)
+		assert.Contains(t, string(fake.Requests()[0].Body), `"namespace":"acme"`)
+	})
+
+	t.Run("dry run sends nothing", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		result, err := addGPGKeyHandler(fake.Context(t), request("add_gpg_key", map[string]any{"ascii_armor": gpgPublicKey, dryRunParam: true}), logger)
+		require.NoError(t, err)
+
+		var dryRun DryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &dryRun))
+		assert.Equal(t, gpgKeysPath, dryRun.Path)
+		assert.Empty(t, fake.Requests())
+	})
+
+	t.Run("rejects a key that is not ASCII-armored", func(t *testing.T) {
+		_, err := addGPGKeyHandler(testutil.NewFakeTFE(t).Context(t), request("add_gpg_key", map[string]any{"ascii_armor": "mQINBGM..."}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ASCII-armored")
+	})
+}
@@ -0,0 +1,251 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// sha256Hex matches the SHA256 checksum of a provider binary, as listed in the SHA256SUMS file of its version
+	sha256Hex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+	// providerVersion matches the semantic version of a provider, without the leading v
+	providerVersion = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+)
+
+// ProviderPlatformResult is the result of the add_provider_platform tool. The upload URLs are only set while
+// the file they receive has not been uploaded yet.
+type ProviderPlatformResult struct {
+	Organization        string   `json:"organization"`
+	Provider            string   `json:"provider"`
+	Version             string   `json:"version"`
+	VersionCreated      bool     `json:"version_created"`
+	ShasumsUploadURL    string   `json:"shasums_upload_url,omitempty"`
+	ShasumsSigUploadURL string   `json:"shasums_sig_upload_url,omitempty"`
+	PlatformID          string   `json:"platform_id"`
+	OS                  string   `json:"os"`
+	Arch                string   `json:"arch"`
+	Filename            string   `json:"filename"`
+	Shasum              string   `json:"shasum"`
+	BinaryUploadURL     string   `json:"binary_upload_url,omitempty"`
+	NextSteps           []string `json:"next_steps,omitempty"`
+}
+
+// AddProviderPlatform creates a tool to add a platform binary to a version of a private provider.
+func AddProviderPlatform(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("add_provider_platform",
+			mcp.WithDescription(`Adds a platform (an OS and architecture) to a version of a private provider in the private registry of a Terraform Cloud/Enterprise organization, and returns the URL to upload its binary zip to.
+The version is created first when it does not exist and gpg_key_id is set, in which case the URLs to upload its SHA256SUMS file and signature are returned too. The key must have been added with add_gpg_key.
+The provider itself must already exist in the private registry. Nothing is uploaded by this tool: the files are sent with a PUT to the returned URLs.`),
+			mcp.WithTitleAnnotation("Add a platform to a private provider version"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("private_provider_name",
+				mcp.Required(),
+				mcp.Description("The name of the private provider, e.g. 'aws' for terraform-provider-aws"),
+			),
+			mcp.WithString("private_provider_namespace",
+				mcp.Description("The namespace of the private provider. Defaults to the organization name."),
+			),
+			mcp.WithString("version",
+				mcp.Required(),
+				mcp.Description("The semantic version of the provider, without a leading v (e.g., '1.2.0')"),
+			),
+			mcp.WithString("os",
+				mcp.Required(),
+				mcp.Description("The operating system of the binary (e.g., 'linux', 'darwin', 'windows')"),
+			),
+			mcp.WithString("arch",
+				mcp.Required(),
+				mcp.Description("The architecture of the binary (e.g., 'amd64', 'arm64')"),
+			),
+			mcp.WithString("filename",
+				mcp.Required(),
+				mcp.Description("The file name of the binary zip (e.g., 'terraform-provider-aws_1.2.0_linux_amd64.zip')"),
+			),
+			mcp.WithString("shasum",
+				mcp.Required(),
+				mcp.Description("The SHA256 checksum of the binary zip, as listed in the SHA256SUMS file of the version"),
+			),
+			mcp.WithString("gpg_key_id",
+				mcp.Description("The key_id of the GPG key the SHA256SUMS file is signed with. Required to create the version when it does not exist."),
+			),
+			mcp.WithString("protocols",
+				mcp.Description("Comma-separated Terraform plugin protocol versions supported by the version, when it is created"),
+				mcp.DefaultString("5.0"),
+			),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return addProviderPlatformHandler(ctx, request, logger)
+		},
+	}
+}
+
+func addProviderPlatformHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	providerName, err := request.RequireString("private_provider_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "private_provider_name is required", err)
+	}
+	namespace := strings.TrimSpace(request.GetString("private_provider_namespace", ""))
+	if namespace == "" {
+		namespace = terraformOrgName
+	}
+
+	versionID := tfe.RegistryProviderVersionID{
+		RegistryProviderID: tfe.RegistryProviderID{
+			OrganizationName: terraformOrgName,
+			RegistryName:     tfe.PrivateRegistry,
+			Namespace:        namespace,
+			Name:             strings.TrimSpace(providerName),
+		},
+		Version: strings.TrimPrefix(strings.TrimSpace(request.GetString("version", "")), "v"),
+	}
+	if !providerVersion.MatchString(versionID.Version) {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "version must be a semantic version, e.g. '1.2.0'", nil)
+	}
+
+	options := tfe.RegistryProviderPlatformCreateOptions{
+		OS:       strings.ToLower(strings.TrimSpace(request.GetString("os", ""))),
+		Arch:     strings.ToLower(strings.TrimSpace(request.GetString("arch", ""))),
+		Filename: strings.TrimSpace(request.GetString("filename", "")),
+		Shasum:   strings.ToLower(strings.TrimSpace(request.GetString("shasum", ""))),
+	}
+	if options.OS == "" || options.Arch == "" || options.Filename == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "os, arch and filename are required", nil)
+	}
+	if !sha256Hex.MatchString(options.Shasum) {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "shasum must be the hex-encoded SHA256 checksum of the binary zip", nil)
+	}
+
+	gpgKeyID := strings.TrimSpace(request.GetString("gpg_key_id", ""))
+	protocols := parseTagNames(request.GetString("protocols", "5.0"))
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	provider := versionID.Namespace + "/" + versionID.Name
+	version, err := tfeClient.RegistryProviderVersions.Read(ctx, versionID)
+	if err != nil && !errors.Is(err, tfe.ErrResourceNotFound) {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading version %s of provider %s", versionID.Version, provider), err)
+	}
+	var versionOptions *tfe.RegistryProviderVersionCreateOptions
+	if version == nil {
+		if gpgKeyID == "" {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput,
+				fmt.Sprintf("version %s of provider %s does not exist: set gpg_key_id to create it", versionID.Version, provider), nil)
+		}
+		versionOptions = &tfe.RegistryProviderVersionCreateOptions{Version: versionID.Version, KeyID: gpgKeyID, Protocols: protocols}
+	}
+
+	if request.GetBool(dryRunParam, false) {
+		return addProviderPlatformDryRun(request, versionID, versionOptions, options, logger)
+	}
+
+	result := ProviderPlatformResult{Organization: terraformOrgName, Provider: provider, Version: versionID.Version}
+	if versionOptions != nil {
+		version, err = tfeClient.RegistryProviderVersions.Create(ctx, versionID.RegistryProviderID, *versionOptions)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, fmt.Sprintf("creating version %s of provider %s", versionID.Version, provider), err)
+		}
+		result.VersionCreated = true
+	}
+	if !version.ShasumsUploaded {
+		result.ShasumsUploadURL, _ = version.ShasumsUploadURL()
+	}
+	if !version.ShasumsSigUploaded {
+		result.ShasumsSigUploadURL, _ = version.ShasumsSigUploadURL()
+	}
+
+	platform, err := tfeClient.RegistryProviderPlatforms.Create(ctx, versionID, options)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("adding platform %s_%s to version %s of provider %s", options.OS, options.Arch, versionID.Version, provider), err)
+	}
+	result.PlatformID = platform.ID
+	result.OS, result.Arch, result.Filename, result.Shasum = platform.OS, platform.Arch, platform.Filename, platform.Shasum
+	if !platform.ProviderBinaryUploaded {
+		result.BinaryUploadURL, _ = platform.Links["provider-binary-upload"].(string)
+	}
+	result.NextSteps = providerPlatformNextSteps(result)
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling provider platform", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// addProviderPlatformDryRun renders the requests adding a platform, preceded by the creation of its
+// version when versionOptions is set
+func addProviderPlatformDryRun(request mcp.CallToolRequest, versionID tfe.RegistryProviderVersionID, versionOptions *tfe.RegistryProviderVersionCreateOptions, options tfe.RegistryProviderPlatformCreateOptions, logger *log.Logger) (*mcp.CallToolResult, error) {
+	providerPath := fmt.Sprintf("organizations/%s/registry-providers/%s/%s/%s",
+		url.PathEscape(versionID.OrganizationName), url.PathEscape(string(versionID.RegistryName)),
+		url.PathEscape(versionID.Namespace), url.PathEscape(versionID.Name))
+	provider := versionID.Namespace + "/" + versionID.Name
+
+	result := BulkDryRunResult{DryRun: true, Requests: []DryRunResult{}}
+	if versionOptions != nil {
+		dryRun, err := newDryRun(request, "POST", providerPath+"/versions", versionOptions,
+			[]string{fmt.Sprintf("Creates version %s of provider %s, signed with GPG key %s", versionID.Version, provider, versionOptions.KeyID)})
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+		}
+		result.Requests = append(result.Requests, dryRun)
+	}
+	dryRun, err := newDryRun(request, "POST", fmt.Sprintf("%s/versions/%s/platforms", providerPath, url.PathEscape(versionID.Version)), &options,
+		[]string{fmt.Sprintf("Adds platform %s_%s to version %s of provider %s, awaiting the upload of %s", options.OS, options.Arch, versionID.Version, provider, options.Filename)})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+	}
+	result.Requests = append(result.Requests, dryRun)
+	result.Total = len(result.Requests)
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling dry run result", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// providerPlatformNextSteps lists the uploads left to publish the platform
+func providerPlatformNextSteps(result ProviderPlatformResult) []string {
+	var steps []string
+	if result.ShasumsUploadURL != "" {
+		steps = append(steps, "Upload the SHA256SUMS file of the version to shasums_upload_url")
+	}
+	if result.ShasumsSigUploadURL != "" {
+		steps = append(steps, "Upload the SHA256SUMS.sig signature of the version to shasums_sig_upload_url")
+	}
+	if result.BinaryUploadURL != "" {
+		steps = append(steps, fmt.Sprintf("Upload %s to binary_upload_url", result.Filename))
+	}
+	return steps
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	providerPath     = "/organizations/acme/registry-providers/private/acme/widget"
+	providerShasum   = "5f0cd7e1a9f8c1ae0e0c7d5b6c0a3f1b2e4d6f8091a2b3c4d5e6f708192a3b4c"
+	providerFilename = "terraform-provider-widget_1.2.0_linux_amd64.zip"
+)
+
+// respondRaw serves a JSON:API document as is, for the upload links the fake cannot marshal
+func respondRaw(fake *testutil.FakeTFE, method, path string, status int, document string) {
+	fake.Handle(method, path, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(document))
+	})
+}
+
+func TestAddProviderPlatform(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := AddProviderPlatform(logger)
+	assert.Equal(t, "add_provider_platform", tool.Tool.Name)
+	assert.False(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, []string{"terraform_org_name", "private_provider_name", "version", "os", "arch", "filename", "shasum"}, tool.Tool.InputSchema.Required)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, dryRunParam)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		defaults := map[string]any{
+			"terraform_org_name":    "acme",
+			"private_provider_name": "widget",
+			"version":               "v1.2.0",
+			"os":                    "linux",
+			"arch":                  "amd64",
+			"filename":              providerFilename,
+			"shasum":                strings.ToUpper(providerShasum),
+		}
+		for key, value := range arguments {
+			defaults[key] = value
+		}
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "add_provider_platform", Arguments: defaults}}
+	}
+	platform := `{"data": {"id": "provpltfrm-123", "type": "registry-provider-platforms",
+		"attributes": {"os": "linux", "arch": "amd64", "filename": "` + providerFilename + `", "shasum": "` + providerShasum + `", "provider-binary-uploaded": false},
+		"links": {"provider-binary-upload": "https://archivist.example.com/v1/object/binary"}}}`
+	decode := func(t *testing.T, result *mcp.CallToolResult) ProviderPlatformResult {
+		var platformResult ProviderPlatformResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &platformResult))
+		return platformResult
+	}
+
+	t.Run("adds a platform to an existing version", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		respondRaw(fake, "GET", providerPath+"/versions/1.2.0", http.StatusOK, `{"data": {"id": "provver-123", "type": "registry-provider-versions",
+			"attributes": {"version": "1.2.0", "shasums-uploaded": true, "shasums-sig-uploaded": true}}}`)
+		respondRaw(fake, "POST", providerPath+"/versions/1.2.0/platforms", http.StatusCreated, platform)
+
+		result, err := addProviderPlatformHandler(fake.Context(t), request(map[string]any{}), logger)
+		require.NoError(t, err)
+
+		platformResult := decode(t, result)
+		assert.False(t, platformResult.VersionCreated)
+		assert.Equal(t, "acme/widget", platformResult.Provider)
+		assert.Equal(t, "provpltfrm-123", platformResult.PlatformID)
+		assert.Empty(t, platformResult.ShasumsUploadURL)
+		assert.Equal(t, "https://archivist.example.com/v1/object/binary", platformResult.BinaryUploadURL)
+		assert.Equal(t, []string{"Upload " + providerFilename + " to binary_upload_url"}, platformResult.NextSteps)
+
+		requests := fake.Requests()
+		require.Len(t, requests, 2)
+		assert.Contains(t, string(requests[1].Body), `"shasum":"`+providerShasum+`"`)
+	})
+
+	t.Run("creates a missing version with the GPG key", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		respondRaw(fake, "POST", providerPath+"/versions", http.StatusCreated, `{"data": {"id": "provver-123", "type": "registry-provider-versions",
+			"attributes": {"version": "1.2.0", "key-id": "32966F3FB5AC1129"},
+			"links": {"shasums-upload": "https://archivist.example.com/v1/object/shasums", "shasums-sig-upload": "https://archivist.example.com/v1/object/sig"}}}`)
+		respondRaw(fake, "POST", providerPath+"/versions/1.2.0/platforms", http.StatusCreated, platform)
+
+		result, err := addProviderPlatformHandler(fake.Context(t), request(map[string]any{"gpg_key_id": "32966F3FB5AC1129"}), logger)
+		require.NoError(t, err)
+
+		platformResult := decode(t, result)
+		assert.True(t, platformResult.VersionCreated)
+		assert.Equal(t, "https://archivist.example.com/v1/object/shasums", platformResult.ShasumsUploadURL)
+		assert.Equal(t, "https://archivist.example.com/v1/object/sig", platformResult.ShasumsSigUploadURL)
+		assert.Len(t, platformResult.NextSteps, 3)
+
+		requests := fake.Requests()
+		require.Len(t, requests, 3)
+		assert.Contains(t, string(requests[1].Body), `"protocols":["5.0"]`)
+	})
+
+	t.Run("requires a GPG key for a missing version", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		_, err := addProviderPlatformHandler(fake.Context(t), request(map[string]any{}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "set gpg_key_id")
+		assert.Len(t, fake.Requests(), 1)
+	})
+
+	t.Run("dry run only reads the version", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		result, err := addProviderPlatformHandler(fake.Context(t), request(map[string]any{"gpg_key_id": "32966F3FB5AC1129", dryRunParam: true}), logger)
+		require.NoError(t, err)
+
+		var dryRun BulkDryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &dryRun))
+		require.Equal(t, 2, dryRun.Total)
+		assert.Equal(t, "/api/v2"+providerPath+"/versions", dryRun.Requests[0].Path)
+		assert.Equal(t, "/api/v2"+providerPath+"/versions/1.2.0/platforms", dryRun.Requests[1].Path)
+		assert.Len(t, fake.Requests(), 1)
+	})
+
+	t.Run("rejects an invalid shasum", func(t *testing.T) {
+		_, err := addProviderPlatformHandler(testutil.NewFakeTFE(t).Context(t), request(map[string]any{"shasum": "abc"}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SHA256")
+	})
+}
@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// PolicySetWorkspace is a workspace a policy set is attached to
+type PolicySetWorkspace struct {
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name"`
+}
+
+// PolicySetAttachResult is the result of the attach_policy_set_to_workspaces tool
+type PolicySetAttachResult struct {
+	Organization    string               `json:"organization"`
+	PolicySetID     string               `json:"policy_set_id"`
+	PolicySetName   string               `json:"policy_set_name"`
+	Attached        []PolicySetWorkspace `json:"attached"`
+	AlreadyAttached []PolicySetWorkspace `json:"already_attached,omitempty"`
+}
+
+// AttachPolicySetToWorkspaces creates a tool to enforce a policy set on workspaces of its organization.
+func AttachPolicySetToWorkspaces(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("attach_policy_set_to_workspaces",
+			mcp.WithDescription(fmt.Sprintf(`Attaches a policy set to workspaces of its organization, named in workspace_names or matching the given name pattern, search term and/or tags, so that its policies are checked on their runs. At most %d workspaces can be targeted at once, and global policy sets, which apply to every workspace already, cannot be attached.
+The first call returns the workspaces and a confirmation token, and the policy set is only attached when the tool is called again with the token. Workspaces the policy set is already attached to are left unchanged.`, maxBulkWorkspaces)),
+			mcp.WithTitleAnnotation("Attach a policy set to Terraform workspaces"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("policy_set_id",
+				mcp.Required(),
+				mcp.Description("The ID of the policy set (e.g., 'polset-abc123def456'), as returned by list_policy_sets"),
+			),
+			mcp.WithString("workspace_names",
+				mcp.Description("Comma-separated names of the workspaces to attach the policy set to"),
+			),
+			mcp.WithString("workspace_name_pattern",
+				mcp.Description("Workspace name pattern with * wildcards at the start and/or end, e.g. 'app-*' or '*-prod'"),
+			),
+			mcp.WithString("workspace_search",
+				mcp.Description("Only target workspaces whose name contains this term"),
+			),
+			mcp.WithString("workspace_tags",
+				mcp.Description("Comma-separated list of tags; only workspaces with all of them are targeted"),
+			),
+			withConfirmationToken(),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return attachPolicySetToWorkspacesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func attachPolicySetToWorkspacesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	policySetID, err := request.RequireString("policy_set_id")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "policy_set_id is required", err)
+	}
+	policySetID = strings.TrimSpace(policySetID)
+
+	names := parseTagNames(request.GetString("workspace_names", ""))
+	namePattern := strings.TrimSpace(request.GetString("workspace_name_pattern", ""))
+	search := strings.TrimSpace(request.GetString("workspace_search", ""))
+	tags := strings.TrimSpace(request.GetString("workspace_tags", ""))
+	if len(names) == 0 && namePattern == "" && search == "" && tags == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "selecting workspaces", fmt.Errorf("at least one of 'workspace_names', 'workspace_name_pattern', 'workspace_search' or 'workspace_tags' is required"))
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	policySet, err := tfeClient.PolicySets.ReadWithOptions(ctx, policySetID, &tfe.PolicySetReadOptions{Include: []tfe.PolicySetIncludeOpt{tfe.PolicySetWorkspaces}})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading policy set %s", policySetID), err)
+	}
+	if policySet.Organization == nil || policySet.Organization.Name != terraformOrgName {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "reading policy set", fmt.Errorf("policy set %s is not in organization %s", policySetID, terraformOrgName))
+	}
+	if policySet.Global {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "attaching policy set", fmt.Errorf("policy set %s is global: it applies to every workspace already", policySet.Name))
+	}
+
+	workspaces, err := readWorkspacesNamed(ctx, tfeClient, terraformOrgName, names)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "reading workspaces", err)
+	}
+	if namePattern != "" || search != "" || tags != "" {
+		matched, err := matchWorkspaces(ctx, tfeClient, terraformOrgName, tags, namePattern, search)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "listing matching workspaces", err)
+		}
+		workspaces = append(workspaces, matched...)
+	}
+
+	result := planPolicySetAttachment(terraformOrgName, policySet, workspaces)
+	if len(result.Attached)+len(result.AlreadyAttached) > maxBulkWorkspaces {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "selecting workspaces", fmt.Errorf("the workspaces and filters match more than %d workspaces, narrow them down", maxBulkWorkspaces))
+	}
+	if len(result.Attached) == 0 {
+		return bulkResult(result, logger)
+	}
+
+	attach := make([]*tfe.Workspace, 0, len(result.Attached))
+	for _, workspace := range result.Attached {
+		attach = append(attach, &tfe.Workspace{ID: workspace.WorkspaceID})
+	}
+	if request.GetBool(dryRunParam, false) {
+		effects := []string{fmt.Sprintf("Checks the %s policies of policy set %s on the runs of %d workspaces", policySet.Kind, policySet.Name, len(attach))}
+		return dryRunResult(request, "POST", fmt.Sprintf("policy-sets/%s/relationships/workspaces", url.PathEscape(policySet.ID)), attach, effects, logger)
+	}
+
+	details := map[string]any{
+		"organization": terraformOrgName,
+		"policy_set":   policySet.Name,
+		"kind":         policySet.Kind,
+		"workspaces":   result.Attached,
+	}
+	summary := fmt.Sprintf("attach policy set %s to %d workspaces of organization %s", policySet.Name, len(attach), terraformOrgName)
+	if confirmation, err := requireConfirmation(ctx, request, summary, details, logger); confirmation != nil || err != nil {
+		return confirmation, err
+	}
+
+	if err := tfeClient.PolicySets.AddWorkspaces(ctx, policySet.ID, tfe.PolicySetAddWorkspacesOptions{Workspaces: attach}); err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("attaching policy set %s", policySet.Name), err)
+	}
+	return bulkResult(result, logger)
+}
+
+// planPolicySetAttachment splits the workspaces into the ones to attach the policy set to and the ones
+// it is attached to already, sorted by name and without duplicates
+func planPolicySetAttachment(organization string, policySet *tfe.PolicySet, workspaces []*tfe.Workspace) PolicySetAttachResult {
+	attached := make(map[string]bool, len(policySet.Workspaces))
+	for _, workspace := range policySet.Workspaces {
+		attached[workspace.ID] = true
+	}
+
+	result := PolicySetAttachResult{Organization: organization, PolicySetID: policySet.ID, PolicySetName: policySet.Name, Attached: []PolicySetWorkspace{}}
+	seen := make(map[string]bool, len(workspaces))
+	for _, workspace := range workspaces {
+		if seen[workspace.ID] {
+			continue
+		}
+		seen[workspace.ID] = true
+		entry := PolicySetWorkspace{WorkspaceID: workspace.ID, WorkspaceName: workspace.Name}
+		if attached[workspace.ID] {
+			result.AlreadyAttached = append(result.AlreadyAttached, entry)
+		} else {
+			result.Attached = append(result.Attached, entry)
+		}
+	}
+	for _, list := range [][]PolicySetWorkspace{result.Attached, result.AlreadyAttached} {
+		sort.Slice(list, func(i, j int) bool { return list[i].WorkspaceName < list[j].WorkspaceName })
+	}
+	return result
+}
+
+// readWorkspacesNamed reads the workspaces of an organization by name
+func readWorkspacesNamed(ctx context.Context, tfeClient *tfe.Client, organization string, names []string) ([]*tfe.Workspace, error) {
+	if len(names) > maxBulkWorkspaces {
+		return nil, fmt.Errorf("at most %d workspaces can be named, got %d", maxBulkWorkspaces, len(names))
+	}
+	workspaces := make([]*tfe.Workspace, 0, len(names))
+	for _, name := range names {
+		workspace, err := tfeClient.Workspaces.Read(ctx, organization, name)
+		if err != nil {
+			return nil, fmt.Errorf("reading workspace %s/%s: %w", organization, name, err)
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	return workspaces, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanPolicySetAttachment(t *testing.T) {
+	policySet := &tfe.PolicySet{ID: "polset-1", Name: "cis", Workspaces: []*tfe.Workspace{{ID: "ws-2"}}}
+	workspaces := []*tfe.Workspace{
+		{ID: "ws-3", Name: "app-3"},
+		{ID: "ws-1", Name: "app-1"},
+		{ID: "ws-2", Name: "app-2"},
+		// named and matched by a filter
+		{ID: "ws-1", Name: "app-1"},
+	}
+
+	result := planPolicySetAttachment("acme", policySet, workspaces)
+	assert.Equal(t, []PolicySetWorkspace{{WorkspaceID: "ws-1", WorkspaceName: "app-1"}, {WorkspaceID: "ws-3", WorkspaceName: "app-3"}}, result.Attached)
+	assert.Equal(t, []PolicySetWorkspace{{WorkspaceID: "ws-2", WorkspaceName: "app-2"}}, result.AlreadyAttached)
+}
+
+func TestAttachPolicySetToWorkspaces(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := AttachPolicySetToWorkspaces(logger)
+	assert.Equal(t, "attach_policy_set_to_workspaces", tool.Tool.Name)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, confirmationTokenParam)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, dryRunParam)
+
+	newFake := func(t *testing.T, policySet *tfe.PolicySet) *testutil.FakeTFE {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/policy-sets/polset-1", http.StatusOK, policySet)
+		fake.Respond("GET", "/organizations/acme/workspaces/app-1", http.StatusOK, &tfe.Workspace{ID: "ws-1", Name: "app-1"})
+		fake.Respond("GET", "/organizations/acme/workspaces/app-2", http.StatusOK, &tfe.Workspace{ID: "ws-2", Name: "app-2"})
+		fake.Handle("POST", "/policy-sets/polset-1/relationships/workspaces", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		return fake
+	}
+	policySet := &tfe.PolicySet{
+		ID:           "polset-1",
+		Name:         "cis",
+		Kind:         tfe.Sentinel,
+		Organization: &tfe.Organization{Name: "acme"},
+		Workspaces:   []*tfe.Workspace{{ID: "ws-2"}},
+	}
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		arguments["terraform_org_name"] = "acme"
+		arguments["policy_set_id"] = "polset-1"
+		arguments["workspace_names"] = "app-1, app-2"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "attach_policy_set_to_workspaces", Arguments: arguments}}
+	}
+
+	t.Run("attaches after a confirmation", func(t *testing.T) {
+		fake := newFake(t, policySet)
+		result, err := attachPolicySetToWorkspacesHandler(fake.Context(t), request(map[string]any{}), logger)
+		require.NoError(t, err)
+
+		var confirmation ConfirmationRequired
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &confirmation))
+		assert.Equal(t, "attach policy set cis to 1 workspaces of organization acme", confirmation.Summary)
+
+		result, err = attachPolicySetToWorkspacesHandler(fake.Context(t), request(map[string]any{confirmationTokenParam: confirmation.ConfirmationToken}), logger)
+		require.NoError(t, err)
+
+		var attached PolicySetAttachResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &attached))
+		assert.Equal(t, []PolicySetWorkspace{{WorkspaceID: "ws-1", WorkspaceName: "app-1"}}, attached.Attached)
+		assert.Equal(t, []PolicySetWorkspace{{WorkspaceID: "ws-2", WorkspaceName: "app-2"}}, attached.AlreadyAttached)
+
+		requests := fake.Requests()
+		last := requests[len(requests)-1]
+		assert.Equal(t, "POST", last.Method)
+		assert.Contains(t, string(last.Body), `"id":"ws-1"`)
+		assert.NotContains(t, string(last.Body), `"id":"ws-2"`)
+	})
+
+	t.Run("returns the request of a dry run", func(t *testing.T) {
+		fake := newFake(t, policySet)
+		result, err := attachPolicySetToWorkspacesHandler(fake.Context(t), request(map[string]any{dryRunParam: true}), logger)
+		require.NoError(t, err)
+
+		var dryRun DryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &dryRun))
+		assert.Equal(t, "/api/v2/policy-sets/polset-1/relationships/workspaces", dryRun.Path)
+		for _, r := range fake.Requests() {
+			assert.Equal(t, "GET", r.Method)
+		}
+	})
+
+	t.Run("rejects global policy sets", func(t *testing.T) {
+		global := *policySet
+		global.Global = true
+		_, err := attachPolicySetToWorkspacesHandler(newFake(t, &global).Context(t), request(map[string]any{}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is global")
+	})
+
+	t.Run("rejects policy sets of another organization", func(t *testing.T) {
+		other := *policySet
+		other.Organization = &tfe.Organization{Name: "other"}
+		_, err := attachPolicySetToWorkspacesHandler(newFake(t, &other).Context(t), request(map[string]any{}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not in organization acme")
+	})
+}
@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// BulkTag is the outcome of tagging one workspace
+type BulkTag struct {
+	WorkspaceID   string   `json:"workspace_id"`
+	WorkspaceName string   `json:"workspace_name"`
+	Added         []string `json:"added,omitempty"`
+	Removed       []string `json:"removed,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// BulkTagResult is the result of the bulk_tag_workspaces tool
+type BulkTagResult struct {
+	Organization string    `json:"organization"`
+	Total        int       `json:"total"`
+	Updated      int       `json:"updated"`
+	Unchanged    int       `json:"unchanged"`
+	Failed       int       `json:"failed"`
+	Workspaces   []BulkTag `json:"workspaces"`
+}
+
+// BulkTagWorkspaces creates a tool to add and remove tags across many workspaces at once.
+func BulkTagWorkspaces(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("bulk_tag_workspaces",
+			mcp.WithDescription(fmt.Sprintf(`Adds and removes tags on every workspace of an organization matching the given name pattern, search term and/or tags. At most %d workspaces can be targeted at once. Workspaces that already have the requested tags are left unchanged.
+The first call returns the changes and a confirmation token, and the tags are only changed when the tool is called again with the token. Returns the tags added to and removed from each workspace, or the error that prevented it.`, maxBulkWorkspaces)),
+			mcp.WithTitleAnnotation("Add and remove tags on many Terraform workspaces"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("workspace_name_pattern",
+				mcp.Description("Workspace name pattern with * wildcards at the start and/or end, e.g. 'app-*' or '*-prod'"),
+			),
+			mcp.WithString("workspace_search",
+				mcp.Description("Only target workspaces whose name contains this term"),
+			),
+			mcp.WithString("workspace_tags",
+				mcp.Description("Comma-separated list of tags; only workspaces with all of them are targeted"),
+			),
+			mcp.WithString("add_tags",
+				mcp.Description("Comma-separated list of tags to add"),
+			),
+			mcp.WithString("remove_tags",
+				mcp.Description("Comma-separated list of tags to remove"),
+			),
+			withConfirmationToken(),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return bulkTagWorkspacesHandler(ctx, req, logger)
+		},
+	}
+}
+
+func bulkTagWorkspacesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	namePattern := strings.TrimSpace(request.GetString("workspace_name_pattern", ""))
+	search := strings.TrimSpace(request.GetString("workspace_search", ""))
+	tags := strings.TrimSpace(request.GetString("workspace_tags", ""))
+	if namePattern == "" && search == "" && tags == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "selecting workspaces", fmt.Errorf("at least one of 'workspace_name_pattern', 'workspace_search' or 'workspace_tags' is required"))
+	}
+
+	addTags := parseTagNames(request.GetString("add_tags", ""))
+	removeTags := parseTagNames(request.GetString("remove_tags", ""))
+	if len(addTags) == 0 && len(removeTags) == 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "reading tags", fmt.Errorf("at least one of 'add_tags' or 'remove_tags' is required"))
+	}
+	for _, name := range addTags {
+		for _, removed := range removeTags {
+			if name == removed {
+				return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "reading tags", fmt.Errorf("tag %q cannot be both added and removed", name))
+			}
+		}
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspaces, err := matchWorkspaces(ctx, tfeClient, terraformOrgName, tags, namePattern, search)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing matching workspaces", err)
+	}
+	if len(workspaces) > maxBulkWorkspaces {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "selecting workspaces", fmt.Errorf("the filters match more than %d workspaces, narrow them down", maxBulkWorkspaces))
+	}
+	changes := plannedTagChanges(workspaces, addTags, removeTags)
+
+	if request.GetBool(dryRunParam, false) {
+		result := BulkDryRunResult{DryRun: true, Requests: []DryRunResult{}}
+		for _, change := range changes {
+			requests := []struct {
+				method string
+				names  []string
+				effect string
+			}{
+				{"POST", change.Added, "Adds tags %q to workspace %s"},
+				{"DELETE", change.Removed, "Removes tags %q from workspace %s"},
+			}
+			for _, tagRequest := range requests {
+				if len(tagRequest.names) == 0 {
+					continue
+				}
+				dryRun, err := newDryRun(request, tagRequest.method, workspaceTagsPath(change.WorkspaceID), tagsNamed(tagRequest.names),
+					[]string{fmt.Sprintf(tagRequest.effect, tagRequest.names, change.WorkspaceName)})
+				if err != nil {
+					return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+				}
+				result.Requests = append(result.Requests, dryRun)
+			}
+		}
+		result.Total = len(result.Requests)
+		return bulkResult(result, logger)
+	}
+
+	changed := 0
+	for _, change := range changes {
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			changed++
+		}
+	}
+	if changed == 0 {
+		return bulkResult(summarizeBulkTags(terraformOrgName, changes), logger)
+	}
+
+	details := map[string]any{
+		"organization": terraformOrgName,
+		"add_tags":     addTags,
+		"remove_tags":  removeTags,
+		"changes":      changes,
+	}
+	summary := fmt.Sprintf("change the tags of %d workspaces of organization %s", changed, terraformOrgName)
+	if result, err := requireConfirmation(ctx, request, summary, details, logger); result != nil || err != nil {
+		return result, err
+	}
+
+	applyTagChanges(ctx, tfeClient, changes, logger)
+	return bulkResult(summarizeBulkTags(terraformOrgName, changes), logger)
+}
+
+// plannedTagChanges returns, for each workspace, the tags it lacks from add and has from remove
+func plannedTagChanges(workspaces []*tfe.Workspace, add, remove []string) []BulkTag {
+	changes := make([]BulkTag, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		added, removed := tagChanges(workspace.TagNames, add, remove)
+		changes = append(changes, BulkTag{WorkspaceID: workspace.ID, WorkspaceName: workspace.Name, Added: added, Removed: removed})
+	}
+	return changes
+}
+
+// applyTagChanges updates the tags of the workspaces, defaultBulkRunConcurrency at a time, recording
+// the error of the workspaces that could not be updated
+func applyTagChanges(ctx context.Context, tfeClient *tfe.Client, changes []BulkTag, logger *log.Logger) {
+	semaphore := make(chan struct{}, defaultBulkRunConcurrency)
+	var wg sync.WaitGroup
+	for i := range changes {
+		change := &changes[i]
+		if len(change.Added) == 0 && len(change.Removed) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if err := ctx.Err(); err != nil {
+				change.Error = err.Error()
+				return
+			}
+			if err := updateWorkspaceTags(ctx, tfeClient, change.WorkspaceID, change.Added, change.Removed); err != nil {
+				logger.WithField("workspace", change.WorkspaceName).Warnf("Failed to update tags: %v", err)
+				change.Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func summarizeBulkTags(organization string, changes []BulkTag) BulkTagResult {
+	result := BulkTagResult{Organization: organization, Total: len(changes), Workspaces: changes}
+	for _, change := range changes {
+		switch {
+		case change.Error != "":
+			result.Failed++
+		case len(change.Added) == 0 && len(change.Removed) == 0:
+			result.Unchanged++
+		default:
+			result.Updated++
+		}
+	}
+	return result
+}
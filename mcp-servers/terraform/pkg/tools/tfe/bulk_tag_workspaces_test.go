@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagChanges(t *testing.T) {
+	assert.Equal(t, []string{"prod", "team-a"}, parseTagNames(" prod, team-a,,prod "))
+	assert.Nil(t, parseTagNames(""))
+
+	added, removed := tagChanges([]string{"prod", "legacy"}, []string{"prod", "team-a"}, []string{"legacy", "unused"})
+	assert.Equal(t, []string{"team-a"}, added)
+	assert.Equal(t, []string{"legacy"}, removed)
+
+	added, removed = replacedTags([]string{"prod", "legacy"}, []string{"prod", "team-a"})
+	assert.Equal(t, []string{"team-a"}, added)
+	assert.Equal(t, []string{"legacy"}, removed)
+}
+
+func TestApplyTagChanges(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	var mu sync.Mutex
+	requests := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.Header().Set("TFP-API-Version", "2.5")
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/workspaces/ws-1/relationships/tags", "/api/v2/workspaces/ws-2/relationships/tags":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			requests[r.Method+" "+r.URL.Path] = string(body)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tfeClient, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", RetryServerErrors: false})
+	require.NoError(t, err)
+
+	workspaces := []*tfe.Workspace{
+		{ID: "ws-1", Name: "app-1", TagNames: []string{"legacy"}},
+		{ID: "ws-2", Name: "app-2", TagNames: []string{"team-a"}},
+		// ws-3 already has the tag and is left unchanged
+		{ID: "ws-3", Name: "app-3", TagNames: []string{"team-a"}},
+		{ID: "ws-missing", Name: "app-4"},
+	}
+	changes := plannedTagChanges(workspaces, []string{"team-a"}, []string{"legacy"})
+	changes[1].Added = []string{"team-b"}
+	applyTagChanges(t.Context(), tfeClient, changes, logger)
+	result := summarizeBulkTags("acme", changes)
+
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, 2, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, 1, result.Failed)
+	assert.NotEmpty(t, result.Workspaces[3].Error)
+
+	require.Len(t, requests, 3)
+	assert.Contains(t, requests["POST /api/v2/workspaces/ws-1/relationships/tags"], `"name":"team-a"`)
+	assert.Contains(t, requests["DELETE /api/v2/workspaces/ws-1/relationships/tags"], `"name":"legacy"`)
+	assert.Contains(t, requests["POST /api/v2/workspaces/ws-2/relationships/tags"], `"name":"team-b"`)
+}
+
+func TestTagsDryRunPayload(t *testing.T) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "bulk_tag_workspaces"
+
+	dryRun, err := newDryRun(request, "POST", workspaceTagsPath("ws-1"), tagsNamed([]string{"prod"}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v2/workspaces/ws-1/relationships/tags", dryRun.Path)
+	assert.JSONEq(t, `{"data": [{"type": "tags", "attributes": {"name": "prod"}}]}`, string(dryRun.Payload))
+}
+
+func TestBulkTagWorkspacesTool(t *testing.T) {
+	tool := BulkTagWorkspaces(log.New())
+
+	assert.Equal(t, "bulk_tag_workspaces", tool.Tool.Name)
+	assert.False(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.Tool.InputSchema.Required, "terraform_org_name")
+	assert.Contains(t, tool.Tool.InputSchema.Properties, confirmationTokenParam)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, dryRunParam)
+}
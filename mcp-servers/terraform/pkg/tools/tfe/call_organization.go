@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+)
+
+// CallOrganization returns the organization of the workspace or run a tool call names by its
+// workspace_id or run_id argument, for the organization allowlists. Calls naming neither return an
+// empty string.
+func CallOrganization(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (string, error) {
+	workspaceID := strings.TrimSpace(request.GetString("workspace_id", ""))
+	runID := strings.TrimSpace(request.GetString("run_id", ""))
+	if workspaceID == "" && runID == "" {
+		return "", nil
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return "", err
+	}
+
+	var workspace *tfe.Workspace
+	if workspaceID != "" {
+		if workspace, err = tfeClient.Workspaces.ReadByID(ctx, workspaceID); err != nil {
+			return "", fmt.Errorf("reading workspace %s: %w", workspaceID, err)
+		}
+	} else {
+		run, err := tfeClient.Runs.ReadWithOptions(ctx, runID, &tfe.RunReadOptions{Include: []tfe.RunIncludeOpt{tfe.RunWorkspace}})
+		if err != nil {
+			return "", fmt.Errorf("reading run %s: %w", runID, err)
+		}
+		workspace = run.Workspace
+	}
+	if workspace == nil || workspace.Organization == nil || workspace.Organization.Name == "" {
+		return "", fmt.Errorf("the organization of the workspace is unknown")
+	}
+	return workspace.Organization.Name, nil
+}
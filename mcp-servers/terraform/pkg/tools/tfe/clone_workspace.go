@@ -0,0 +1,325 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// CloneWorkspaceResult is the result of the clone_workspace tool
+type CloneWorkspaceResult struct {
+	SourceWorkspaceID string   `json:"source_workspace_id"`
+	WorkspaceID       string   `json:"workspace_id"`
+	WorkspaceName     string   `json:"workspace_name"`
+	Organization      string   `json:"organization"`
+	ProjectID         string   `json:"project_id,omitempty"`
+	VCSRepo           string   `json:"vcs_repo,omitempty"`
+	Tags              []string `json:"tags,omitempty"`
+	VariablesCopied   int      `json:"variables_copied"`
+	// SensitiveVariablesWithoutValue are the sensitive variables created with an empty value, to be set
+	SensitiveVariablesWithoutValue []string `json:"sensitive_variables_without_value,omitempty"`
+	// SkippedSensitiveVariables are the sensitive variables of the source workspace that were not copied
+	SkippedSensitiveVariables []string `json:"skipped_sensitive_variables,omitempty"`
+	Warnings                  []string `json:"warnings,omitempty"`
+}
+
+// workspaceClone is the plan of a clone: the options of the new workspace and its variables
+type workspaceClone struct {
+	organization string
+	options      *tfe.WorkspaceCreateOptions
+	variables    []*tfe.VariableCreateOptions
+	result       CloneWorkspaceResult
+}
+
+// CloneWorkspace creates a tool to copy a workspace into a new workspace.
+func CloneWorkspace(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("clone_workspace",
+			mcp.WithDescription(`Creates a new workspace with the settings, variables, tags and VCS connection of an existing workspace, optionally in another project or organization, e.g. to create a new environment from an existing one.
+Sensitive variable values cannot be read from HCP Terraform/TFE: sensitive variables are skipped, or created with an empty value to be set afterwards with include_sensitive_variables. When cloning into another organization, the VCS connection needs the vcs_repo_oauth_token_id of that organization, and workspaces running on an agent pool cannot be cloned.
+If a variable cannot be created, the new workspace is deleted so that no partial clone is left behind. The state and the runs of the workspace are not copied.`),
+			mcp.WithTitleAnnotation("Clone a Terraform workspace"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			WithWorkspace("The name of the workspace to clone"),
+			mcp.WithString("new_workspace_name",
+				mcp.Required(),
+				mcp.Description("The name of the new workspace"),
+			),
+			mcp.WithString("target_org_name",
+				mcp.Description("Optional organization of the new workspace, the organization of the cloned workspace by default"),
+			),
+			mcp.WithString("target_project_id",
+				mcp.Description("Optional project ID of the new workspace. By default the project of the cloned workspace, or the default project of another organization"),
+			),
+			mcp.WithBoolean("include_variables",
+				mcp.Description("Whether to copy the Terraform and environment variables"),
+				mcp.DefaultBool(true),
+			),
+			mcp.WithBoolean("include_sensitive_variables",
+				mcp.Description("Whether to create the sensitive variables with an empty value, so that only their values need to be set. They are skipped by default"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("include_vcs",
+				mcp.Description("Whether to connect the new workspace to the VCS repository of the cloned workspace"),
+				mcp.DefaultBool(true),
+			),
+			mcp.WithString("vcs_repo_oauth_token_id",
+				mcp.Description("Optional OAuth token ID of the VCS connection, required to keep the VCS connection when cloning into another organization"),
+			),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return cloneWorkspaceHandler(ctx, request, logger)
+		},
+	}
+}
+
+func cloneWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+
+	newWorkspaceName, err := request.RequireString("new_workspace_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'new_workspace_name' parameter is required", err)
+	}
+	newWorkspaceName = strings.TrimSpace(newWorkspaceName)
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+
+	source, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+	terraformOrgName := source.Organization.Name
+
+	targetOrgName := strings.TrimSpace(request.GetString("target_org_name", ""))
+	if targetOrgName == "" {
+		targetOrgName = terraformOrgName
+	}
+	if targetOrgName == terraformOrgName && newWorkspaceName == source.Name {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "new_workspace_name must differ from workspace_name when cloning into the same organization", nil)
+	}
+	// The access middleware only checks the organization of the cloned workspace, the target organization is checked here
+	if !client.OrganizationAllowed(ctx, targetOrgName) {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeUnauthorized, fmt.Sprintf("organization %q is not in the organizations this session may access", targetOrgName), nil)
+	}
+	var variables []*tfe.Variable
+	if request.GetBool("include_variables", true) {
+		if variables, err = listWorkspaceVariables(ctx, tfeClient, source.ID); err != nil {
+			return nil, utils.LogAndReturnError(logger, "listing workspace variables", err)
+		}
+	}
+
+	clone, err := planWorkspaceClone(source, variables, cloneOptions{
+		sourceOrganization: terraformOrgName,
+		organization:       targetOrgName,
+		name:               newWorkspaceName,
+		projectID:          strings.TrimSpace(request.GetString("target_project_id", "")),
+		includeSensitive:   request.GetBool("include_sensitive_variables", false),
+		includeVCS:         request.GetBool("include_vcs", true),
+		oauthTokenID:       strings.TrimSpace(request.GetString("vcs_repo_oauth_token_id", "")),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "planning the clone", err)
+	}
+
+	if request.GetBool(dryRunParam, false) {
+		return cloneDryRun(request, clone, logger)
+	}
+
+	workspace, err := tfeClient.Workspaces.Create(ctx, targetOrgName, *clone.options)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "creating workspace", err)
+	}
+	if err := createCloneVariables(ctx, tfeClient, workspace.ID, clone.variables); err != nil {
+		// Roll back so that a retry does not fail on the name of a half-configured workspace
+		if rollbackErr := tfeClient.Workspaces.DeleteByID(ctx, workspace.ID); rollbackErr != nil {
+			logger.WithField("workspace", workspace.ID).Errorf("Failed to delete the partially cloned workspace: %v", rollbackErr)
+			return nil, utils.LogAndReturnError(logger, "copying variables", fmt.Errorf("%w; deleting the new workspace %s also failed: %v", err, workspace.ID, rollbackErr))
+		}
+		return nil, utils.LogAndReturnError(logger, "copying variables, the new workspace was deleted", err)
+	}
+
+	clone.result.WorkspaceID = workspace.ID
+	if workspace.Project != nil {
+		clone.result.ProjectID = workspace.Project.ID
+	}
+	resultJSON, err := json.Marshal(clone.result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling clone result", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// cloneOptions are the arguments of a clone
+type cloneOptions struct {
+	sourceOrganization string
+	organization       string
+	name               string
+	projectID          string
+	includeSensitive   bool
+	includeVCS         bool
+	oauthTokenID       string
+}
+
+// planWorkspaceClone builds the requests creating the clone of source and its variables. The project,
+// the agent pool and the VCS OAuth token belong to the organization, so they are only kept when
+// cloning within it.
+func planWorkspaceClone(source *tfe.Workspace, variables []*tfe.Variable, opts cloneOptions) (*workspaceClone, error) {
+	sameOrganization := opts.organization == opts.sourceOrganization
+	options := &tfe.WorkspaceCreateOptions{
+		Name:                tfe.String(opts.name),
+		Description:         tfe.String(source.Description),
+		AllowDestroyPlan:    tfe.Bool(source.AllowDestroyPlan),
+		AssessmentsEnabled:  tfe.Bool(source.AssessmentsEnabled),
+		AutoApply:           tfe.Bool(source.AutoApply),
+		AutoApplyRunTrigger: tfe.Bool(source.AutoApplyRunTrigger),
+		FileTriggersEnabled: tfe.Bool(source.FileTriggersEnabled),
+		GlobalRemoteState:   tfe.Bool(source.GlobalRemoteState),
+		QueueAllRuns:        tfe.Bool(source.QueueAllRuns),
+		SpeculativeEnabled:  tfe.Bool(source.SpeculativeEnabled),
+		TriggerPrefixes:     source.TriggerPrefixes,
+		TriggerPatterns:     source.TriggerPatterns,
+		WorkingDirectory:    tfe.String(source.WorkingDirectory),
+		Tags:                tagsNamed(source.TagNames),
+		SourceName:          tfe.String(SourceName),
+	}
+	if source.TerraformVersion != "" {
+		options.TerraformVersion = tfe.String(source.TerraformVersion)
+	}
+
+	result := CloneWorkspaceResult{SourceWorkspaceID: source.ID, WorkspaceName: opts.name, Organization: opts.organization, Tags: source.TagNames}
+
+	switch {
+	case opts.projectID != "":
+		options.Project = &tfe.Project{ID: opts.projectID}
+	case sameOrganization && source.Project != nil:
+		options.Project = &tfe.Project{ID: source.Project.ID}
+	}
+	if options.Project != nil {
+		result.ProjectID = options.Project.ID
+	}
+
+	if source.ExecutionMode != "" {
+		options.ExecutionMode = tfe.String(source.ExecutionMode)
+	}
+	if source.ExecutionMode == "agent" {
+		if !sameOrganization || source.AgentPool == nil {
+			return nil, errors.New("the workspace runs on an agent pool, which cannot be copied into another organization")
+		}
+		options.AgentPoolID = tfe.String(source.AgentPool.ID)
+	}
+
+	if opts.includeVCS && source.VCSRepo != nil {
+		oauthTokenID := opts.oauthTokenID
+		if oauthTokenID == "" && sameOrganization {
+			oauthTokenID = source.VCSRepo.OAuthTokenID
+		}
+		vcsRepo := &tfe.VCSRepoOptions{
+			Identifier:        tfe.String(source.VCSRepo.Identifier),
+			Branch:            tfe.String(source.VCSRepo.Branch),
+			IngressSubmodules: tfe.Bool(source.VCSRepo.IngressSubmodules),
+		}
+		if source.VCSRepo.TagsRegex != "" {
+			vcsRepo.TagsRegex = tfe.String(source.VCSRepo.TagsRegex)
+		}
+		switch {
+		case oauthTokenID != "":
+			vcsRepo.OAuthTokenID = tfe.String(oauthTokenID)
+		case sameOrganization && source.VCSRepo.GHAInstallationID != "":
+			vcsRepo.GHAInstallationID = tfe.String(source.VCSRepo.GHAInstallationID)
+		default:
+			return nil, errors.New("the VCS connection of the workspace cannot be copied into another organization without vcs_repo_oauth_token_id, set it or include_vcs to false")
+		}
+		options.VCSRepo = vcsRepo
+		result.VCSRepo = source.VCSRepo.Identifier
+	}
+
+	clone := &workspaceClone{organization: opts.organization, options: options}
+	for _, variable := range variables {
+		if variable.Sensitive && !opts.includeSensitive {
+			result.SkippedSensitiveVariables = append(result.SkippedSensitiveVariables, variable.Key)
+			continue
+		}
+		category := variable.Category
+		create := &tfe.VariableCreateOptions{
+			Key:      tfe.String(variable.Key),
+			Value:    tfe.String(variable.Value),
+			Category: &category,
+			HCL:      tfe.Bool(variable.HCL),
+		}
+		if variable.Description != "" {
+			create.Description = tfe.String(variable.Description)
+		}
+		if variable.Sensitive {
+			create.Sensitive = tfe.Bool(true)
+			create.Value = tfe.String("")
+			result.SensitiveVariablesWithoutValue = append(result.SensitiveVariablesWithoutValue, variable.Key)
+		}
+		clone.variables = append(clone.variables, create)
+	}
+	result.VariablesCopied = len(clone.variables)
+	if len(result.SensitiveVariablesWithoutValue) > 0 {
+		result.Warnings = append(result.Warnings, "Set the values of the sensitive variables before running the new workspace")
+	}
+	if !sameOrganization && source.Project != nil && opts.projectID == "" {
+		result.Warnings = append(result.Warnings, "The new workspace is in the default project of the organization, set target_project_id to choose another one")
+	}
+	clone.result = result
+	return clone, nil
+}
+
+// createCloneVariables creates the variables of the clone one at a time, stopping at the first error
+func createCloneVariables(ctx context.Context, tfeClient *tfe.Client, workspaceID string, variables []*tfe.VariableCreateOptions) error {
+	for _, variable := range variables {
+		if _, err := tfeClient.Variables.Create(ctx, workspaceID, *variable); err != nil {
+			return fmt.Errorf("creating variable %s: %w", *variable.Key, err)
+		}
+	}
+	return nil
+}
+
+// cloneDryRun describes the requests of a clone: the creation of the workspace, then of each variable
+func cloneDryRun(request mcp.CallToolRequest, clone *workspaceClone, logger *log.Logger) (*mcp.CallToolResult, error) {
+	effects := []string{fmt.Sprintf("Creates workspace %s in organization %s", clone.result.WorkspaceName, clone.organization)}
+	if clone.result.VCSRepo != "" {
+		effects = append(effects, fmt.Sprintf("Connects the workspace to the VCS repository %s", clone.result.VCSRepo))
+	}
+	effects = append(effects, clone.result.Warnings...)
+	workspaceRequest, err := newDryRun(request, "POST", fmt.Sprintf("organizations/%s/workspaces", url.PathEscape(clone.organization)), clone.options, effects)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+	}
+
+	result := BulkDryRunResult{DryRun: true, Requests: []DryRunResult{workspaceRequest}}
+	for _, variable := range clone.variables {
+		// The ID of the new workspace is only known once it is created
+		dryRun, err := newDryRun(request, "POST", "workspaces/{new_workspace_id}/vars", variable,
+			[]string{fmt.Sprintf("Creates %s variable %s", *variable.Category, *variable.Key)})
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+		}
+		result.Requests = append(result.Requests, dryRun)
+	}
+	result.Total = len(result.Requests)
+	return bulkResult(result, logger)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cloneSourceFixture() *tfe.Workspace {
+	return &tfe.Workspace{
+		ID:               "ws-123",
+		Name:             "staging",
+		Description:      "Staging environment",
+		ExecutionMode:    "remote",
+		AutoApply:        true,
+		TerraformVersion: "1.9.0",
+		WorkingDirectory: "envs",
+		TagNames:         []string{"app", "staging"},
+		Project:          &tfe.Project{ID: "prj-1"},
+		VCSRepo:          &tfe.VCSRepo{Identifier: "acme/infra", Branch: "main", OAuthTokenID: "ot-1"},
+	}
+}
+
+func TestPlanWorkspaceClone(t *testing.T) {
+	options := cloneOptions{sourceOrganization: "acme", organization: "acme", name: "production", includeVCS: true}
+
+	t.Run("same organization", func(t *testing.T) {
+		clone, err := planWorkspaceClone(cloneSourceFixture(), workspaceVariablesFixture(), options)
+		require.NoError(t, err)
+
+		assert.Equal(t, "production", *clone.options.Name)
+		assert.Equal(t, "prj-1", clone.options.Project.ID)
+		assert.Equal(t, "ot-1", *clone.options.VCSRepo.OAuthTokenID)
+		assert.True(t, *clone.options.AutoApply)
+		assert.Len(t, clone.options.Tags, 2)
+		assert.Len(t, clone.variables, 3)
+		assert.Equal(t, []string{"db_password"}, clone.result.SkippedSensitiveVariables)
+		assert.Equal(t, 3, clone.result.VariablesCopied)
+	})
+
+	t.Run("sensitive variables without their value", func(t *testing.T) {
+		opts := options
+		opts.includeSensitive = true
+		clone, err := planWorkspaceClone(cloneSourceFixture(), workspaceVariablesFixture(), opts)
+		require.NoError(t, err)
+
+		require.Len(t, clone.variables, 4)
+		assert.Equal(t, "", *clone.variables[2].Value)
+		assert.True(t, *clone.variables[2].Sensitive)
+		assert.Equal(t, []string{"db_password"}, clone.result.SensitiveVariablesWithoutValue)
+		assert.NotEmpty(t, clone.result.Warnings)
+	})
+
+	t.Run("another organization", func(t *testing.T) {
+		opts := options
+		opts.organization = "acme-prod"
+		_, err := planWorkspaceClone(cloneSourceFixture(), nil, opts)
+		assert.ErrorContains(t, err, "vcs_repo_oauth_token_id")
+
+		opts.oauthTokenID = "ot-2"
+		clone, err := planWorkspaceClone(cloneSourceFixture(), nil, opts)
+		require.NoError(t, err)
+		assert.Nil(t, clone.options.Project)
+		assert.Equal(t, "ot-2", *clone.options.VCSRepo.OAuthTokenID)
+
+		opts.includeVCS = false
+		clone, err = planWorkspaceClone(cloneSourceFixture(), nil, opts)
+		require.NoError(t, err)
+		assert.Nil(t, clone.options.VCSRepo)
+
+		source := cloneSourceFixture()
+		source.ExecutionMode = "agent"
+		source.AgentPool = &tfe.AgentPool{ID: "apool-1"}
+		_, err = planWorkspaceClone(source, nil, opts)
+		assert.ErrorContains(t, err, "agent pool")
+	})
+}
+
+func TestCloneWorkspaceHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := CloneWorkspace(logger)
+	assert.Equal(t, "clone_workspace", tool.Tool.Name)
+	assert.False(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.Tool.InputSchema.Required, "new_workspace_name")
+	assert.Contains(t, tool.Tool.InputSchema.Properties, dryRunParam)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		arguments["terraform_org_name"] = "acme"
+		arguments["workspace_name"] = "staging"
+		arguments["new_workspace_name"] = "production"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "clone_workspace", Arguments: arguments}}
+	}
+	newFake := func(t *testing.T) *testutil.FakeTFE {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/organizations/acme/workspaces/staging", http.StatusOK, cloneSourceFixture())
+		fake.RespondList("GET", "/workspaces/ws-123/vars", workspaceVariablesFixture(), &tfe.Pagination{CurrentPage: 1, TotalPages: 1})
+		fake.Respond("POST", "/organizations/acme/workspaces", http.StatusCreated, &tfe.Workspace{ID: "ws-456", Name: "production", Project: &tfe.Project{ID: "prj-1"}})
+		return fake
+	}
+
+	t.Run("copies the workspace and its variables", func(t *testing.T) {
+		fake := newFake(t)
+		fake.Respond("POST", "/workspaces/ws-456/vars", http.StatusCreated, &tfe.Variable{ID: "var-9"})
+
+		result, err := cloneWorkspaceHandler(fake.Context(t), request(map[string]any{}), logger)
+		require.NoError(t, err)
+
+		var clone CloneWorkspaceResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &clone))
+		assert.Equal(t, "ws-456", clone.WorkspaceID)
+		assert.Equal(t, "acme/infra", clone.VCSRepo)
+		assert.Equal(t, 3, clone.VariablesCopied)
+
+		requests := fake.Requests()
+		require.Len(t, requests, 6)
+		assert.Contains(t, string(requests[2].Body), `"name":"production"`)
+		assert.Contains(t, string(requests[2].Body), `"oauth-token-id":"ot-1"`)
+	})
+
+	t.Run("deletes the new workspace when a variable fails", func(t *testing.T) {
+		fake := newFake(t)
+		fake.RespondError("POST", "/workspaces/ws-456/vars", http.StatusUnprocessableEntity, "invalid key")
+		fake.Handle("DELETE", "/workspaces/ws-456", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		_, err := cloneWorkspaceHandler(fake.Context(t), request(map[string]any{}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the new workspace was deleted")
+
+		requests := fake.Requests()
+		assert.Equal(t, "DELETE", requests[len(requests)-1].Method)
+		assert.Equal(t, "/workspaces/ws-456", requests[len(requests)-1].Path)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		fake := newFake(t)
+		result, err := cloneWorkspaceHandler(fake.Context(t), request(map[string]any{dryRunParam: true}), logger)
+		require.NoError(t, err)
+
+		var dryRun BulkDryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &dryRun))
+		assert.Equal(t, 4, dryRun.Total)
+		assert.Equal(t, "/api/v2/organizations/acme/workspaces", dryRun.Requests[0].Path)
+		assert.Len(t, fake.Requests(), 2)
+	})
+
+	t.Run("rejects the same name in the same organization", func(t *testing.T) {
+		arguments := request(map[string]any{})
+		arguments.Params.Arguments.(map[string]any)["new_workspace_name"] = "staging"
+		_, err := cloneWorkspaceHandler(newFake(t).Context(t), arguments, logger)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// confirmationTokenParam is the argument used to echo the confirmation token back to a destructive tool
+	confirmationTokenParam = "confirmation_token"
+	// confirmationTokenTTL is how long a confirmation token can be used after it was issued
+	confirmationTokenTTL = 5 * time.Minute
+)
+
+var errInvalidConfirmationToken = errors.New("the confirmation token is invalid, expired or was issued for a different call, call the tool again without confirmation_token to get a new one")
+
+// withConfirmationToken adds the confirmation_token argument to a destructive tool
+func withConfirmationToken() mcp.ToolOption {
+	return mcp.WithString(confirmationTokenParam,
+		mcp.Description("One-time token returned by a previous call of this tool with the same arguments. Leave it empty to get a summary of the operation and a token; the operation is only performed when the token is sent back."),
+	)
+}
+
+// pendingConfirmation is a destructive tool call waiting for its confirmation token
+type pendingConfirmation struct {
+	sessionID   string
+	tool        string
+	fingerprint string
+	expiresAt   time.Time
+}
+
+// confirmationStore holds the issued confirmation tokens until they are used or expire
+type confirmationStore struct {
+	mu      sync.Mutex
+	pending map[string]pendingConfirmation
+	now     func() time.Time
+}
+
+func newConfirmationStore() *confirmationStore {
+	return &confirmationStore{
+		pending: make(map[string]pendingConfirmation),
+		now:     time.Now,
+	}
+}
+
+var confirmations = newConfirmationStore()
+
+// issue returns a new token for the tool call identified by sessionID, tool and fingerprint
+func (s *confirmationStore) issue(sessionID, tool, fingerprint string) (string, time.Time, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("generating confirmation token: %w", err)
+	}
+	token := "confirm-" + hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, pending := range s.pending {
+		if now.After(pending.expiresAt) {
+			delete(s.pending, key)
+		}
+	}
+
+	expiresAt := now.Add(confirmationTokenTTL)
+	s.pending[token] = pendingConfirmation{sessionID: sessionID, tool: tool, fingerprint: fingerprint, expiresAt: expiresAt}
+	return token, expiresAt, nil
+}
+
+// consume reports whether token was issued for the same tool call and has not expired.
+// A token can only be presented once, whether it matches or not.
+func (s *confirmationStore) consume(token, sessionID, tool, fingerprint string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, ok := s.pending[token]
+	if !ok {
+		return false
+	}
+	delete(s.pending, token)
+
+	return pending.sessionID == sessionID &&
+		pending.tool == tool &&
+		pending.fingerprint == fingerprint &&
+		!s.now().After(pending.expiresAt)
+}
+
+// ConfirmationRequired is the result of a destructive tool called without a confirmation token
+type ConfirmationRequired struct {
+	ConfirmationRequired bool           `json:"confirmation_required"`
+	Tool                 string         `json:"tool"`
+	Summary              string         `json:"summary"`
+	Message              string         `json:"message"`
+	Details              map[string]any `json:"details,omitempty"`
+	ConfirmationToken    string         `json:"confirmation_token"`
+	ExpiresAt            time.Time      `json:"expires_at"`
+}
+
+// ConfirmationDeclined is the result of a destructive tool the end user declined when asked by the client
+type ConfirmationDeclined struct {
+	Cancelled bool   `json:"cancelled"`
+	Tool      string `json:"tool"`
+	Summary   string `json:"summary"`
+	Message   string `json:"message"`
+}
+
+// requestFingerprint identifies the arguments of a tool call, without the confirmation token and dry_run
+func requestFingerprint(request mcp.CallToolRequest) (string, error) {
+	arguments := make(map[string]any)
+	for key, value := range request.GetArguments() {
+		if key != confirmationTokenParam && key != dryRunParam {
+			arguments[key] = value
+		}
+	}
+	// Maps are marshalled with sorted keys, so equal arguments give the same fingerprint
+	raw, err := json.Marshal(arguments)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// requireConfirmation implements the two-phase confirmation of destructive tools. It returns a nil
+// result when the request carries a valid token for the same call and the operation can proceed.
+// Otherwise, when the client supports elicitation, the end user is asked to confirm the operation
+// directly; if the client cannot ask, it returns the summary of the operation with a new one-time token.
+func requireConfirmation(ctx context.Context, request mcp.CallToolRequest, summary string, details map[string]any, logger *log.Logger) (*mcp.CallToolResult, error) {
+	tool := request.Params.Name
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "computing the confirmation fingerprint", err)
+	}
+
+	sessionID := ""
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+
+	if token := request.GetString(confirmationTokenParam, ""); token != "" {
+		if confirmations.consume(token, sessionID, tool, fingerprint) {
+			logger.WithFields(log.Fields{"tool": tool, "session": sessionID}).Infof("Confirmed destructive operation: %s", summary)
+			return nil, nil
+		}
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "checking the confirmation token", errInvalidConfirmationToken)
+	}
+
+	if action, content, asked := elicit(ctx, confirmationMessage(tool, summary, details), map[string]any{
+		"confirm": map[string]any{
+			"type":        "boolean",
+			"title":       "Proceed",
+			"description": "Perform the operation now",
+		},
+	}, []string{"confirm"}, logger); asked {
+		if action == mcp.ElicitationResponseActionAccept && content["confirm"] == true {
+			logger.WithFields(log.Fields{"tool": tool, "session": sessionID}).Infof("Confirmed destructive operation through elicitation: %s", summary)
+			return nil, nil
+		}
+
+		logger.WithFields(log.Fields{"tool": tool, "session": sessionID}).Infof("Declined destructive operation through elicitation: %s", summary)
+		resultJSON, err := json.Marshal(ConfirmationDeclined{
+			Cancelled: true,
+			Tool:      tool,
+			Summary:   summary,
+			Message:   "The user did not confirm the operation, nothing has been changed.",
+		})
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "marshalling declined confirmation", err)
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+
+	token, expiresAt, err := confirmations.issue(sessionID, tool, fingerprint)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "issuing a confirmation token", err)
+	}
+
+	resultJSON, err := json.Marshal(ConfirmationRequired{
+		ConfirmationRequired: true,
+		Tool:                 tool,
+		Summary:              summary,
+		Message:              fmt.Sprintf("Nothing has been changed yet. Call %s again with the same arguments and confirmation_token set to this token to perform the operation.", tool),
+		Details:              details,
+		ConfirmationToken:    token,
+		ExpiresAt:            expiresAt.UTC(),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling confirmation request", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// confirmationMessage is the question shown to the end user when a destructive operation is confirmed through elicitation
+func confirmationMessage(tool, summary string, details map[string]any) string {
+	message := fmt.Sprintf("%s will %s.", tool, summary)
+	if len(details) > 0 {
+		keys := make([]string, 0, len(details))
+		for key := range details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			message += fmt.Sprintf("\n- %s: %v", key, details[key])
+		}
+	}
+	return message + "\nDo you want to proceed?"
+}
@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmationStore(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newConfirmationStore()
+	store.now = func() time.Time { return now }
+
+	t.Run("token is valid once", func(t *testing.T) {
+		token, expiresAt, err := store.issue("session", "delete_workspace_safely", "fp")
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(confirmationTokenTTL), expiresAt)
+		assert.True(t, store.consume(token, "session", "delete_workspace_safely", "fp"))
+		assert.False(t, store.consume(token, "session", "delete_workspace_safely", "fp"))
+	})
+
+	t.Run("token is bound to the call", func(t *testing.T) {
+		for name, call := range map[string][3]string{
+			"other session":   {"other", "delete_workspace_safely", "fp"},
+			"other tool":      {"session", "action_run", "fp"},
+			"other arguments": {"session", "delete_workspace_safely", "other"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				token, _, err := store.issue("session", "delete_workspace_safely", "fp")
+				require.NoError(t, err)
+				assert.False(t, store.consume(token, call[0], call[1], call[2]))
+				// A mismatching attempt uses the token up
+				assert.False(t, store.consume(token, "session", "delete_workspace_safely", "fp"))
+			})
+		}
+	})
+
+	t.Run("token expires", func(t *testing.T) {
+		token, _, err := store.issue("session", "delete_workspace_safely", "fp")
+		require.NoError(t, err)
+		now = now.Add(confirmationTokenTTL + time.Second)
+		assert.False(t, store.consume(token, "session", "delete_workspace_safely", "fp"))
+	})
+
+	t.Run("expired tokens are removed", func(t *testing.T) {
+		_, _, err := store.issue("session", "delete_workspace_safely", "fp")
+		require.NoError(t, err)
+		now = now.Add(confirmationTokenTTL + time.Second)
+		_, _, err = store.issue("session", "delete_workspace_safely", "fp")
+		require.NoError(t, err)
+		assert.Len(t, store.pending, 1)
+	})
+}
+
+func TestRequireConfirmation(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "delete_workspace_safely"
+	request.Params.Arguments = map[string]any{"workspace_id": "ws-123"}
+
+	// The first call returns a summary and a token instead of proceeding
+	result, err := requireConfirmation(t.Context(), request, "delete workspace ws-123", map[string]any{"resource_count": 0}, logger)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.IsError)
+
+	var confirmation ConfirmationRequired
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &confirmation))
+	assert.True(t, confirmation.ConfirmationRequired)
+	assert.Equal(t, "delete workspace ws-123", confirmation.Summary)
+	assert.NotEmpty(t, confirmation.ConfirmationToken)
+
+	// A token for different arguments is rejected
+	other := request
+	other.Params.Arguments = map[string]any{"workspace_id": "ws-456", confirmationTokenParam: confirmation.ConfirmationToken}
+	_, err = requireConfirmation(t.Context(), other, "delete workspace ws-456", nil, logger)
+	assert.ErrorIs(t, err, errInvalidConfirmationToken)
+	assert.Equal(t, utils.ErrorCodeInvalidInput, utils.ErrorCodeOf(err))
+
+	// Echoing a fresh token back with the same arguments lets the operation proceed
+	result, err = requireConfirmation(t.Context(), request, "delete workspace ws-123", nil, logger)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &confirmation))
+	request.Params.Arguments = map[string]any{"workspace_id": "ws-123", confirmationTokenParam: confirmation.ConfirmationToken}
+	result, err = requireConfirmation(t.Context(), request, "delete workspace ws-123", nil, logger)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	// The token cannot be replayed
+	_, err = requireConfirmation(t.Context(), request, "delete workspace ws-123", nil, logger)
+	assert.ErrorIs(t, err, errInvalidConfirmationToken)
+}
+
+func TestDestructiveToolsAcceptConfirmationToken(t *testing.T) {
+	logger := log.New()
+	for _, tool := range []mcp.Tool{DeleteWorkspaceSafely(logger).Tool, ActionRun(logger).Tool, UpdateWorkspace(logger).Tool} {
+		assert.Contains(t, tool.InputSchema.Properties, confirmationTokenParam, tool.Name)
+		assert.NotContains(t, tool.InputSchema.Required, confirmationTokenParam, tool.Name)
+	}
+}
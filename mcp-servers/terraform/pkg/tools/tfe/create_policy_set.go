@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreatePolicySet creates a tool to create a Sentinel or OPA policy set in a Terraform organization.
+func CreatePolicySet(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("create_policy_set",
+			mcp.WithDescription(`Creates a Sentinel or OPA policy set in a Terraform Cloud/Enterprise organization, usually read from a VCS repository holding the policies and their policy set configuration.
+A global policy set is enforced on every workspace of the organization at once; otherwise attach it to workspaces with attach_policy_set_to_workspaces.`),
+			mcp.WithTitleAnnotation("Create a policy set in a Terraform organization"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("The name of the policy set, made of letters, numbers, - and _"),
+			),
+			mcp.WithString("description",
+				mcp.Description("The description of the policy set"),
+			),
+			mcp.WithString("kind",
+				mcp.Description("The policy framework of the policy set"),
+				mcp.Enum(string(tfe.Sentinel), string(tfe.OPA)),
+				mcp.DefaultString(string(tfe.Sentinel)),
+			),
+			mcp.WithBoolean("global",
+				mcp.Description("Whether the policy set is enforced on every workspace of the organization"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("overridable",
+				mcp.Description("Whether users can override failed mandatory policies. Only for OPA policy sets."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithString("vcs_repo_identifier",
+				mcp.Description("The VCS repository holding the policies (e.g., 'org/policies')"),
+			),
+			mcp.WithString("vcs_repo_branch",
+				mcp.Description("The branch of the VCS repository (default: the default branch of the repository)"),
+			),
+			mcp.WithString("vcs_repo_oauth_token_id",
+				mcp.Description("OAuth token ID for VCS integration. Required with vcs_repo_identifier."),
+			),
+			mcp.WithString("policies_path",
+				mcp.Description("The directory of the VCS repository holding the policy set, when it is not the root"),
+			),
+			mcp.WithString("policy_tool_version",
+				mcp.Description("The version of Sentinel or OPA evaluating the policies (default: the latest)"),
+			),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return createPolicySetHandler(ctx, request, logger)
+		},
+	}
+}
+
+func createPolicySetHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	name, err := request.RequireString("name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "name is required", err)
+	}
+	name = strings.TrimSpace(name)
+
+	kind := tfe.PolicyKind(strings.TrimSpace(request.GetString("kind", string(tfe.Sentinel))))
+	if kind != tfe.Sentinel && kind != tfe.OPA {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "kind must be 'sentinel' or 'opa'", nil)
+	}
+	overridable := request.GetBool("overridable", false)
+	if overridable && kind != tfe.OPA {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "only OPA policy sets can be overridable", nil)
+	}
+	global := request.GetBool("global", false)
+
+	options := &tfe.PolicySetCreateOptions{
+		Name:   &name,
+		Kind:   kind,
+		Global: &global,
+	}
+	if description := strings.TrimSpace(request.GetString("description", "")); description != "" {
+		options.Description = &description
+	}
+	if kind == tfe.OPA {
+		options.Overridable = &overridable
+	}
+	if policiesPath := strings.TrimSpace(request.GetString("policies_path", "")); policiesPath != "" {
+		options.PoliciesPath = &policiesPath
+	}
+	if version := strings.TrimSpace(request.GetString("policy_tool_version", "")); version != "" {
+		options.PolicyToolVersion = &version
+	}
+
+	vcsRepoIdentifier := strings.TrimSpace(request.GetString("vcs_repo_identifier", ""))
+	if vcsRepoIdentifier != "" {
+		vcsRepoOAuthTokenID := strings.TrimSpace(request.GetString("vcs_repo_oauth_token_id", ""))
+		if vcsRepoOAuthTokenID == "" {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "vcs_repo_oauth_token_id is required when vcs_repo_identifier is provided", nil)
+		}
+		options.VCSRepo = &tfe.VCSRepoOptions{
+			Identifier:   &vcsRepoIdentifier,
+			OAuthTokenID: &vcsRepoOAuthTokenID,
+		}
+		if vcsRepoBranch := strings.TrimSpace(request.GetString("vcs_repo_branch", "")); vcsRepoBranch != "" {
+			options.VCSRepo.Branch = &vcsRepoBranch
+		}
+	}
+
+	if request.GetBool(dryRunParam, false) {
+		effects := []string{fmt.Sprintf("Creates %s policy set %s in organization %s", kind, name, terraformOrgName)}
+		if global {
+			effects = append(effects, "Enforces the policies on every workspace of the organization, including the ones created later")
+		}
+		if options.VCSRepo != nil {
+			effects = append(effects, fmt.Sprintf("Reads the policies from the VCS repository %s", vcsRepoIdentifier))
+		}
+		return dryRunResult(request, "POST", fmt.Sprintf("organizations/%s/policy-sets", url.PathEscape(terraformOrgName)), options, effects, logger)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	policySet, err := tfeClient.PolicySets.Create(ctx, terraformOrgName, *options)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "creating policy set", err)
+	}
+
+	resultJSON, err := json.Marshal(summarizePolicySet(policySet))
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling policy set", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
@@ -6,7 +6,7 @@ package tools
 import (
 	"bytes"
 	"context"
-	"strings"
+	"fmt"
 
 	"github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/jsonapi"
@@ -17,6 +17,9 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// runTypes are the kinds of run that create_run and create_runs_bulk can start
+var runTypes = []string{"plan_and_apply", "refresh_state", "plan_only", "allow_empty_apply", "auto_approve", "is_destroy"}
+
 // CreateRun creates a tool to create a new Terraform run.
 func CreateRun(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
@@ -25,22 +28,16 @@ func CreateRun(logger *log.Logger) server.ServerTool {
 			mcp.WithTitleAnnotation("Create a new Terraform run"),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
-			mcp.WithString("terraform_org_name",
-				mcp.Required(),
-				mcp.Description("The Terraform Cloud/Enterprise organization name"),
-			),
-			mcp.WithString("workspace_name",
-				mcp.Required(),
-				mcp.Description("The name of the workspace to create a run in"),
-			),
+			WithWorkspace("The name of the workspace to create a run in"),
 			mcp.WithString("run_type",
 				mcp.Description("A run type for the run"),
-				mcp.Enum("plan_and_apply", "refresh_state", "plan_only", "allow_empty_apply", "auto_approve", "is_destroy"),
+				mcp.Enum(runTypes...),
 				mcp.DefaultString("plan_and_apply"),
 			),
 			mcp.WithString("message",
 				mcp.Description("Optional message for the run"),
 			),
+			withDryRun(),
 		),
 		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return createRunHandler(ctx, req, logger)
@@ -49,17 +46,9 @@ func CreateRun(logger *log.Logger) server.ServerTool {
 }
 
 func createRunHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
-	terraformOrgName, err := request.RequireString("terraform_org_name")
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required", err)
-	}
-	terraformOrgName = strings.TrimSpace(terraformOrgName)
-
-	workspaceName, err := request.RequireString("workspace_name")
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'workspace_name' parameter is required", err)
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
 	}
-	workspaceName = strings.TrimSpace(workspaceName)
 
 	runType := request.GetString("run_type", "plan_and_apply")
 	message := request.GetString("message", "Triggered via Terraform MCP Server")
@@ -69,11 +58,47 @@ func createRunHandler(ctx context.Context, request mcp.CallToolRequest, logger *
 		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
 	}
 
-	workspace, err := tfeClient.Workspaces.Read(ctx, terraformOrgName, workspaceName)
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	options := runCreateOptions(workspace, runType, message)
+
+	if request.GetBool(dryRunParam, false) {
+		return dryRunResult(request, "POST", "runs", options, createRunEffects(workspace, runType), logger)
+	}
+
+	run, err := tfeClient.Runs.Create(ctx, *options)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "creating run", err)
+	}
+	notifyRunUpdate(ctx, run.ID, workspace.Name, string(run.Status), "Run queued")
+
+	buf := bytes.NewBuffer(nil)
+	err = jsonapi.MarshalPayloadWithoutIncluded(buf, run)
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "reading workspace", err)
+		return nil, utils.LogAndReturnError(logger, "marshalling run creation", err)
 	}
 
+	return mcp.NewToolResultText(buf.String()), nil
+}
+
+// notifyRunUpdate tells the client about a change of a run with an info log notification. The
+// workspace and the status are left out when they are not known.
+func notifyRunUpdate(ctx context.Context, runID, workspaceName, status, message string) {
+	data := map[string]any{"message": message, "run_id": runID}
+	if workspaceName != "" {
+		data["workspace"] = workspaceName
+	}
+	if status != "" {
+		data["status"] = status
+	}
+	client.NotifyClient(ctx, mcp.LoggingLevelInfo, client.ClientLoggerRuns, data)
+}
+
+// runCreateOptions returns the options of a run of the given type in workspace
+func runCreateOptions(workspace *tfe.Workspace, runType, message string) *tfe.RunCreateOptions {
 	options := &tfe.RunCreateOptions{
 		Workspace: workspace,
 	}
@@ -95,17 +120,25 @@ func createRunHandler(ctx context.Context, request mcp.CallToolRequest, logger *
 	if message != "" {
 		options.Message = &message
 	}
+	return options
+}
 
-	run, err := tfeClient.Runs.Create(ctx, *options)
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "creating run", err)
-	}
-
-	buf := bytes.NewBuffer(nil)
-	err = jsonapi.MarshalPayloadWithoutIncluded(buf, run)
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "marshalling run creation", err)
+// createRunEffects describes the run that create_run would queue
+func createRunEffects(workspace *tfe.Workspace, runType string) []string {
+	effects := []string{fmt.Sprintf("Queues a run in workspace %s (%s)", workspace.Name, workspace.ID)}
+	switch runType {
+	case "plan_and_apply":
+		effects = append(effects, "The plan waits for a confirmation before it is applied")
+	case "refresh_state":
+		effects = append(effects, "The run only refreshes the state and does not change any resource")
+	case "plan_only":
+		effects = append(effects, "The run is a speculative plan that cannot be applied")
+	case "allow_empty_apply":
+		effects = append(effects, "The run can be applied even when the plan has no changes")
+	case "auto_approve":
+		effects = append(effects, "The plan is applied automatically when it succeeds")
+	case "is_destroy":
+		effects = append(effects, fmt.Sprintf("The run destroys the %d resources managed by the workspace", workspace.ResourceCount))
 	}
-
-	return mcp.NewToolResultText(buf.String()), nil
+	return effects
 }
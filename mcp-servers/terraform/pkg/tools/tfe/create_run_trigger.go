@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateRunTrigger creates a tool to make the successful applies of one workspace queue runs in another.
+func CreateRunTrigger(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("create_run_trigger",
+			mcp.WithDescription(`Creates a run trigger so that every successful apply in the source workspace queues a run in the target workspace, e.g. to apply a network workspace before the application workspaces that read its outputs. Both workspaces must belong to the same organization.`),
+			mcp.WithTitleAnnotation("Create a run trigger between Terraform workspaces"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			WithWorkspace("The name of the workspace in which runs are queued"),
+			mcp.WithString("source_workspace_name",
+				mcp.Description("The name of the workspace whose successful applies queue the runs. Required unless source_workspace_id is set."),
+			),
+			mcp.WithString("source_workspace_id",
+				mcp.Description("The ID of the workspace whose successful applies queue the runs, instead of source_workspace_name"),
+			),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return createRunTriggerHandler(ctx, req, logger)
+		},
+	}
+}
+
+func createRunTriggerHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+
+	sourceWorkspaceID := strings.TrimSpace(request.GetString("source_workspace_id", ""))
+	sourceWorkspaceName := strings.TrimSpace(request.GetString("source_workspace_name", ""))
+	if (sourceWorkspaceID == "") == (sourceWorkspaceName == "") {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "Either the 'source_workspace_id' or the 'source_workspace_name' parameter is required", nil)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+	// The source workspace must be in the organization of the workspace, whichever way it is addressed
+	sourceRef := workspaceRef{ID: sourceWorkspaceID, Organization: workspace.Organization.Name, Name: sourceWorkspaceName}
+	sourceWorkspace, err := sourceRef.read(ctx, tfeClient)
+	if errors.Is(err, errWorkspaceOrganization) {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "reading source workspace", err)
+	}
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "reading source workspace", err)
+	}
+	if sourceWorkspace.ID == workspace.ID {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "creating run trigger", fmt.Errorf("a workspace cannot trigger its own runs"))
+	}
+
+	options := &tfe.RunTriggerCreateOptions{Sourceable: sourceWorkspace}
+	if request.GetBool(dryRunParam, false) {
+		effects := []string{fmt.Sprintf("Every successful apply in workspace %s queues a run in workspace %s", sourceWorkspace.Name, workspace.Name)}
+		return dryRunResult(request, "POST", fmt.Sprintf("workspaces/%s/run-triggers", url.PathEscape(workspace.ID)), options, effects, logger)
+	}
+
+	runTrigger, err := tfeClient.RunTriggers.Create(ctx, workspace.ID, *options)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "creating run trigger", err)
+	}
+
+	summary := summarizeRunTrigger(runTrigger)
+	// The create response does not always carry the names, the workspaces were read above
+	summary.SourceWorkspaceID, summary.SourceWorkspaceName = sourceWorkspace.ID, sourceWorkspace.Name
+	summary.WorkspaceID, summary.WorkspaceName = workspace.ID, workspace.Name
+
+	resultJSON, err := json.Marshal(summary)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling run trigger", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxBulkWorkspaces caps the number of workspaces a single bulk call can change
+	maxBulkWorkspaces = 100
+	// defaultBulkRunConcurrency is the number of runs created in parallel when max_concurrency is not set
+	defaultBulkRunConcurrency = 5
+	// maxBulkRunConcurrency is the largest accepted max_concurrency
+	maxBulkRunConcurrency = 20
+)
+
+// BulkRun is the outcome of creating a run in one workspace
+type BulkRun struct {
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name"`
+	RunID         string `json:"run_id,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// BulkRunResult is the result of the create_runs_bulk tool
+type BulkRunResult struct {
+	Organization string    `json:"organization"`
+	RunType      string    `json:"run_type"`
+	Total        int       `json:"total"`
+	Created      int       `json:"created"`
+	Failed       int       `json:"failed"`
+	Runs         []BulkRun `json:"runs"`
+}
+
+// BulkDryRunResult lists the requests create_runs_bulk would send
+type BulkDryRunResult struct {
+	DryRun   bool           `json:"dry_run"`
+	Total    int            `json:"total"`
+	Requests []DryRunResult `json:"requests"`
+}
+
+// CreateRunsBulk creates a tool to start the same kind of run in many workspaces at once.
+func CreateRunsBulk(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("create_runs_bulk",
+			mcp.WithDescription(fmt.Sprintf(`Creates the same kind of run in every workspace of an organization matching the given tags and/or name pattern, e.g. to roll out a module upgrade across a fleet. At most %d workspaces can be targeted at once. The first call returns the matched workspaces and a confirmation token, and the runs are only created when the tool is called again with the token. Returns the run created in each workspace, or the error that prevented it.`, maxBulkWorkspaces)),
+			mcp.WithTitleAnnotation("Create Terraform runs in many workspaces"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("workspace_tags",
+				mcp.Description("Comma-separated list of tags; only workspaces with all of them are targeted"),
+			),
+			mcp.WithString("workspace_name_pattern",
+				mcp.Description("Workspace name pattern with * wildcards at the start and/or end, e.g. 'app-*' or '*-prod'"),
+			),
+			mcp.WithString("run_type",
+				mcp.Description("A run type for the runs"),
+				mcp.Enum(runTypes...),
+				mcp.DefaultString("plan_and_apply"),
+			),
+			mcp.WithString("message",
+				mcp.Description("Optional message for the runs"),
+			),
+			mcp.WithNumber("max_concurrency",
+				mcp.Description(fmt.Sprintf("Number of runs created in parallel (default: %d)", defaultBulkRunConcurrency)),
+				mcp.Min(1),
+				mcp.Max(maxBulkRunConcurrency),
+			),
+			withConfirmationToken(),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return createRunsBulkHandler(ctx, req, logger)
+		},
+	}
+}
+
+func createRunsBulkHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	tags := strings.TrimSpace(request.GetString("workspace_tags", ""))
+	namePattern := strings.TrimSpace(request.GetString("workspace_name_pattern", ""))
+	if tags == "" && namePattern == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "selecting workspaces", fmt.Errorf("at least one of 'workspace_tags' or 'workspace_name_pattern' is required"))
+	}
+
+	runType := request.GetString("run_type", "plan_and_apply")
+	message := request.GetString("message", "Triggered via Terraform MCP Server")
+	concurrency := request.GetInt("max_concurrency", defaultBulkRunConcurrency)
+	if concurrency < 1 || concurrency > maxBulkRunConcurrency {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'max_concurrency' parameter is invalid", fmt.Errorf("must be between 1 and %d", maxBulkRunConcurrency))
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspaces, err := matchWorkspaces(ctx, tfeClient, terraformOrgName, tags, namePattern, "")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing matching workspaces", err)
+	}
+	if len(workspaces) > maxBulkWorkspaces {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "selecting workspaces", fmt.Errorf("the filters match more than %d workspaces, narrow them down", maxBulkWorkspaces))
+	}
+
+	if request.GetBool(dryRunParam, false) {
+		result := BulkDryRunResult{DryRun: true, Total: len(workspaces), Requests: make([]DryRunResult, 0, len(workspaces))}
+		for _, workspace := range workspaces {
+			dryRun, err := newDryRun(request, "POST", "runs", runCreateOptions(workspace, runType, message), createRunEffects(workspace, runType))
+			if err != nil {
+				return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+			}
+			result.Requests = append(result.Requests, dryRun)
+		}
+		return bulkResult(result, logger)
+	}
+
+	if len(workspaces) == 0 {
+		return bulkResult(BulkRunResult{Organization: terraformOrgName, RunType: runType, Runs: []BulkRun{}}, logger)
+	}
+
+	names := make([]string, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		names = append(names, workspace.Name)
+	}
+	details := map[string]any{
+		"organization": terraformOrgName,
+		"run_type":     runType,
+		"workspaces":   names,
+	}
+	summary := fmt.Sprintf("create a %s run in %d workspaces of organization %s", runType, len(workspaces), terraformOrgName)
+	if result, err := requireConfirmation(ctx, request, summary, details, logger); result != nil || err != nil {
+		return result, err
+	}
+
+	result := createRuns(ctx, tfeClient, workspaces, runType, message, concurrency, logger)
+	result.Organization = terraformOrgName
+	return bulkResult(result, logger)
+}
+
+// matchWorkspaces lists every workspace of the organization with all the tags, a name matching the pattern
+// and the search term. It stops after the first page beyond maxBulkWorkspaces, since such a selection is rejected.
+func matchWorkspaces(ctx context.Context, tfeClient *tfe.Client, organization, tags, namePattern, search string) ([]*tfe.Workspace, error) {
+	options := &tfe.WorkspaceListOptions{
+		ListOptions:  tfe.ListOptions{PageNumber: 1, PageSize: 100},
+		Search:       search,
+		Tags:         tags,
+		WildcardName: namePattern,
+	}
+
+	var workspaces []*tfe.Workspace
+	for {
+		page, err := tfeClient.Workspaces.List(ctx, organization, options)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, page.Items...)
+		if page.Pagination == nil || page.NextPage == 0 || len(workspaces) > maxBulkWorkspaces {
+			return workspaces, nil
+		}
+		options.PageNumber = page.NextPage
+	}
+}
+
+// createRuns creates a run in each workspace, at most concurrency at a time. The runs are
+// returned in the order of the workspaces, with the error of the ones that could not be created.
+func createRuns(ctx context.Context, tfeClient *tfe.Client, workspaces []*tfe.Workspace, runType, message string, concurrency int, logger *log.Logger) BulkRunResult {
+	runs := make([]BulkRun, len(workspaces))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, workspace := range workspaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			runs[i] = BulkRun{WorkspaceID: workspace.ID, WorkspaceName: workspace.Name}
+			if err := ctx.Err(); err != nil {
+				runs[i].Error = err.Error()
+				return
+			}
+
+			run, err := tfeClient.Runs.Create(ctx, *runCreateOptions(workspace, runType, message))
+			if err != nil {
+				logger.WithField("workspace", workspace.Name).Warnf("Failed to create run: %v", err)
+				runs[i].Error = err.Error()
+				return
+			}
+			runs[i].RunID = run.ID
+			runs[i].Status = string(run.Status)
+			notifyRunUpdate(ctx, run.ID, workspace.Name, runs[i].Status, "Run queued")
+		}()
+	}
+	wg.Wait()
+
+	return summarizeBulkRuns(runType, runs)
+}
+
+func summarizeBulkRuns(runType string, runs []BulkRun) BulkRunResult {
+	result := BulkRunResult{RunType: runType, Total: len(runs), Runs: runs}
+	for _, run := range runs {
+		if run.Error != "" {
+			result.Failed++
+		} else {
+			result.Created++
+		}
+	}
+	return result
+}
+
+func bulkResult(result any, logger *log.Logger) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling bulk run result", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
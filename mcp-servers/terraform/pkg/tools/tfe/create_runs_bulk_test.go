@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRuns(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	var active, maxActive atomic.Int32
+	var mu sync.Mutex
+	var created []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.Header().Set("TFP-API-Version", "2.5")
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/runs":
+			current := active.Add(1)
+			defer active.Add(-1)
+			for {
+				previous := maxActive.Load()
+				if current <= previous || maxActive.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			body, _ := io.ReadAll(r.Body)
+			if strings.Contains(string(body), `"ws-fail"`) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"errors": [{"status": "422", "title": "invalid", "detail": "workspace has no configuration"}]}`))
+				return
+			}
+			var payload struct {
+				Data struct {
+					Relationships struct {
+						Workspace struct {
+							Data struct {
+								ID string `json:"id"`
+							} `json:"data"`
+						} `json:"workspace"`
+					} `json:"relationships"`
+				} `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			workspaceID := payload.Data.Relationships.Workspace.Data.ID
+			mu.Lock()
+			created = append(created, workspaceID)
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data": {"id": "run-` + workspaceID + `", "type": "runs", "attributes": {"status": "pending"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tfeClient, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", RetryServerErrors: false})
+	require.NoError(t, err)
+
+	workspaces := []*tfe.Workspace{
+		{ID: "ws-1", Name: "app-1"},
+		{ID: "ws-fail", Name: "app-2"},
+		{ID: "ws-3", Name: "app-3"},
+		{ID: "ws-4", Name: "app-4"},
+		{ID: "ws-5", Name: "app-5"},
+	}
+	result := createRuns(t.Context(), tfeClient, workspaces, "plan_only", "module upgrade", 2, logger)
+
+	assert.Equal(t, 5, result.Total)
+	assert.Equal(t, 4, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.LessOrEqual(t, maxActive.Load(), int32(2))
+	assert.Len(t, created, 4)
+
+	// Results keep the order of the workspaces
+	require.Len(t, result.Runs, 5)
+	assert.Equal(t, BulkRun{WorkspaceID: "ws-1", WorkspaceName: "app-1", RunID: "run-ws-1", Status: "pending"}, result.Runs[0])
+	assert.Equal(t, "app-2", result.Runs[1].WorkspaceName)
+	assert.Empty(t, result.Runs[1].RunID)
+	assert.NotEmpty(t, result.Runs[1].Error)
+}
+
+func TestCreateRunsBulkTool(t *testing.T) {
+	tool := CreateRunsBulk(log.New())
+
+	assert.Equal(t, "create_runs_bulk", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.DestructiveHint)
+	assert.Contains(t, tool.Tool.InputSchema.Required, "terraform_org_name")
+	assert.Contains(t, tool.Tool.InputSchema.Properties, confirmationTokenParam)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, dryRunParam)
+	assert.Equal(t, float64(maxBulkRunConcurrency), tool.Tool.InputSchema.Properties["max_concurrency"].(map[string]any)["maximum"])
+}
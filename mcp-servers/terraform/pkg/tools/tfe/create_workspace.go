@@ -5,6 +5,8 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/hashicorp/go-tfe"
@@ -51,7 +53,7 @@ func CreateWorkspace(logger *log.Logger) server.ServerTool {
 				mcp.Description("Execution mode: 'remote', 'local', or 'agent' (default: 'remote')"),
 			),
 			mcp.WithString("project_id",
-				mcp.Description("Optional project ID to associate the workspace with"),
+				mcp.Description("Optional project ID to associate the workspace with. When omitted and the client supports elicitation, the user is asked to pick one of the organization's projects"),
 			),
 			mcp.WithString("vcs_repo_identifier",
 				mcp.Description("Optional VCS repository identifier (e.g., 'org/repo')"),
@@ -65,6 +67,7 @@ func CreateWorkspace(logger *log.Logger) server.ServerTool {
 			mcp.WithString("tags",
 				mcp.Description("Optional comma-separated list of tags to apply to the workspace"),
 			),
+			withDryRun(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return createWorkspaceHandler(ctx, request, logger)
@@ -76,13 +79,13 @@ func createWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, lo
 	// Get required parameters
 	terraformOrgName, err := request.RequireString("terraform_org_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
 	}
 	terraformOrgName = strings.TrimSpace(terraformOrgName)
 
 	workspaceName, err := request.RequireString("workspace_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'workspace_name' parameter is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'workspace_name' parameter is required", err)
 	}
 	workspaceName = strings.TrimSpace(workspaceName)
 
@@ -111,7 +114,7 @@ func createWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, lo
 	case "remote", "":
 		executionMode = "remote"
 	default:
-		return nil, utils.LogAndReturnError(logger, "invalid execution_mode: must be 'remote', 'local', or 'agent'", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid execution_mode: must be 'remote', 'local', or 'agent'", nil)
 	}
 
 	// Parse tags
@@ -169,7 +172,7 @@ func createWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, lo
 	// Configure VCS repository if provided
 	if vcsRepoIdentifier != "" {
 		if vcsRepoOAuthTokenID == "" {
-			return nil, utils.LogAndReturnError(logger, "vcs_repo_oauth_token_id is required when vcs_repo_identifier is provided", nil)
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "vcs_repo_oauth_token_id is required when vcs_repo_identifier is provided", nil)
 		}
 
 		vcsRepo := &tfe.VCSRepoOptions{
@@ -184,6 +187,33 @@ func createWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, lo
 		options.VCSRepo = vcsRepo
 	}
 
+	if request.GetBool(dryRunParam, false) {
+		effects := []string{
+			fmt.Sprintf("Creates workspace %s in organization %s", workspaceName, terraformOrgName),
+			fmt.Sprintf("Runs use the %s execution mode", executionMode),
+		}
+		if autoApply {
+			effects = append(effects, "Successful plans are applied automatically")
+		}
+		if projectID != "" {
+			effects = append(effects, fmt.Sprintf("Adds the workspace to project %s", projectID))
+		}
+		if options.VCSRepo != nil {
+			effects = append(effects, fmt.Sprintf("Connects the workspace to the VCS repository %s", vcsRepoIdentifier))
+		}
+		if len(tags) > 0 {
+			effects = append(effects, fmt.Sprintf("Tags the workspace with %s", tagsStr))
+		}
+		return dryRunResult(request, "POST", fmt.Sprintf("organizations/%s/workspaces", url.PathEscape(terraformOrgName)), options, effects, logger)
+	}
+
+	// Without a project the workspace lands in the default project, ask the end user when the client can
+	if projectID == "" {
+		if projectID = elicitWorkspaceProject(ctx, tfeClient, terraformOrgName, workspaceName, logger); projectID != "" {
+			options.Project = &tfe.Project{ID: projectID}
+		}
+	}
+
 	// Create the workspace
 	workspace, err := tfeClient.Workspaces.Create(ctx, terraformOrgName, *options)
 	if err != nil {
@@ -197,3 +227,45 @@ func createWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, lo
 
 	return mcp.NewToolResultText(buf.String()), nil
 }
+
+// elicitWorkspaceProject asks the end user which project a new workspace goes in when the organization has
+// more than one. It returns an empty ID, keeping the default project, when the client cannot ask or the user
+// does not choose one.
+func elicitWorkspaceProject(ctx context.Context, tfeClient *tfe.Client, terraformOrgName, workspaceName string, logger *log.Logger) string {
+	if _, ok := elicitationSession(ctx); !ok {
+		return ""
+	}
+
+	projects, err := tfeClient.Projects.List(ctx, terraformOrgName, &tfe.ProjectListOptions{
+		ListOptions: tfe.ListOptions{PageSize: 100},
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Listing projects to ask for the workspace project, using the default project")
+		return ""
+	}
+	if len(projects.Items) < 2 {
+		return ""
+	}
+
+	names := make([]string, 0, len(projects.Items))
+	projectIDs := make(map[string]string, len(projects.Items))
+	for _, project := range projects.Items {
+		names = append(names, project.Name)
+		projectIDs[project.Name] = project.ID
+	}
+
+	action, content, asked := elicit(ctx, fmt.Sprintf("Which project should workspace %s go in? Organization %s has %d projects; skip to use the default project.", workspaceName, terraformOrgName, len(names)), map[string]any{
+		"project": map[string]any{
+			"type":        "string",
+			"title":       "Project",
+			"description": "Project of the new workspace",
+			"enum":        names,
+		},
+	}, []string{"project"}, logger)
+	if !asked || action != mcp.ElicitationResponseActionAccept {
+		return ""
+	}
+
+	name, _ := content["project"].(string)
+	return projectIDs[name]
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -254,3 +256,34 @@ func TestCreateWorkspace(t *testing.T) {
 		}
 	})
 }
+
+func TestElicitWorkspaceProject(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	fake := testutil.NewFakeTFE(t)
+	fake.RespondList("GET", "/organizations/acme/projects", []*tfe.Project{
+		{ID: "prj-default", Name: "Default Project"},
+		{ID: "prj-platform", Name: "platform"},
+	}, &tfe.Pagination{CurrentPage: 1, TotalPages: 1})
+	tfeClient := fake.Client(t)
+
+	t.Run("client without elicitation keeps the default project", func(t *testing.T) {
+		assert.Empty(t, elicitWorkspaceProject(t.Context(), tfeClient, "acme", "staging", logger))
+	})
+
+	t.Run("chosen project", func(t *testing.T) {
+		var requests []mcp.ElicitationRequest
+		ctx := elicitationContext(t.Context(), elicitationAnswer(mcp.ElicitationResponseActionAccept, map[string]any{"project": "platform"}, &requests))
+		assert.Equal(t, "prj-platform", elicitWorkspaceProject(ctx, tfeClient, "acme", "staging", logger))
+
+		require.Len(t, requests, 1)
+		project := requests[0].Params.RequestedSchema.(map[string]any)["properties"].(map[string]any)["project"].(map[string]any)
+		assert.Equal(t, []string{"Default Project", "platform"}, project["enum"])
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		ctx := elicitationContext(t.Context(), elicitationAnswer(mcp.ElicitationResponseActionDecline, nil, nil))
+		assert.Empty(t, elicitWorkspaceProject(ctx, tfeClient, "acme", "staging", logger))
+	})
+}
@@ -5,8 +5,10 @@ package tools
 
 import (
 	"context"
-	"strings"
+	"fmt"
+	"net/url"
 
+	"github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/terraform-mcp-server/pkg/client"
 	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
 	log "github.com/sirupsen/logrus"
@@ -15,20 +17,19 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// DeleteWorkspaceSafely creates a tool to safely delete a Terraform workspace by ID.
+// DeleteWorkspaceSafely creates a tool to safely delete a Terraform workspace.
 // It will only delete the workspace if it has no managed resources.
 func DeleteWorkspaceSafely(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("delete_workspace_safely",
-			mcp.WithDescription(`Safely deletes a Terraform workspace by ID only if it is not managing any resources. This prevents accidental deletion of workspaces that still have active infrastructure. This is a destructive operation.`),
-			mcp.WithTitleAnnotation("Safely delete a Terraform workspace by ID"),
+			mcp.WithDescription(`Safely deletes a Terraform workspace, addressed by ID or by organization and name, only if it is not managing any resources. This prevents accidental deletion of workspaces that still have active infrastructure. This is a destructive operation: the first call returns a summary and a confirmation token, and the workspace is only deleted when the tool is called again with the token.`),
+			mcp.WithTitleAnnotation("Safely delete a Terraform workspace"),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithOpenWorldHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(true),
-			mcp.WithString("workspace_id",
-				mcp.Required(),
-				mcp.Description("The ID of the workspace to delete (e.g., 'ws-abc123def456')"),
-			),
+			WithWorkspace("The name of the workspace to delete"),
+			withConfirmationToken(),
+			withDryRun(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return deleteWorkspaceSafelyHandler(ctx, request, logger)
@@ -37,12 +38,9 @@ func DeleteWorkspaceSafely(logger *log.Logger) server.ServerTool {
 }
 
 func deleteWorkspaceSafelyHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
-	// Get required parameters
-	workspaceID, err := request.RequireString("workspace_id")
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'workspace_id' parameter is required", err)
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
 	}
-	workspaceID = strings.TrimSpace(workspaceID)
 
 	// Get a Terraform client from context
 	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
@@ -51,13 +49,31 @@ func deleteWorkspaceSafelyHandler(ctx context.Context, request mcp.CallToolReque
 	}
 
 	// First, get the workspace details to check its current state
-	workspace, err := tfeClient.Workspaces.ReadByID(ctx, workspaceID)
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "reading workspace details", err)
+		return nil, err
+	}
+
+	if request.GetBool(dryRunParam, false) {
+		return dryRunResult(request, "POST", safeDeletePath(workspace.ID), nil, safeDeleteEffects(workspace), logger)
+	}
+
+	details := map[string]any{
+		"workspace_id":   workspace.ID,
+		"workspace_name": workspace.Name,
+		"resource_count": workspace.ResourceCount,
+		"locked":         workspace.Locked,
+	}
+	if workspace.Organization != nil {
+		details["organization"] = workspace.Organization.Name
+	}
+	summary := fmt.Sprintf("delete workspace %s (%s), which manages %d resources", workspace.Name, workspace.ID, workspace.ResourceCount)
+	if result, err := requireConfirmation(ctx, request, summary, details, logger); result != nil || err != nil {
+		return result, err
 	}
 
 	// Perform the deletion using workspace ID
-	err = tfeClient.Workspaces.SafeDeleteByID(ctx, workspaceID)
+	err = tfeClient.Workspaces.SafeDeleteByID(ctx, workspace.ID)
 	if err != nil {
 		return nil, utils.LogAndReturnError(logger, "deleting workspace", err)
 	}
@@ -69,3 +85,17 @@ func deleteWorkspaceSafelyHandler(ctx context.Context, request mcp.CallToolReque
 
 	return mcp.NewToolResultText(buf.String()), nil
 }
+
+// safeDeletePath is the API path of the safe delete of a workspace
+func safeDeletePath(workspaceID string) string {
+	return fmt.Sprintf("workspaces/%s/actions/safe-delete", url.PathEscape(workspaceID))
+}
+
+// safeDeleteEffects predicts the effects of the safe delete of a workspace
+func safeDeleteEffects(workspace *tfe.Workspace) []string {
+	effects := []string{fmt.Sprintf("Deletes workspace %s (%s) and its state versions, variables and run history", workspace.Name, workspace.ID)}
+	if workspace.ResourceCount > 0 {
+		effects = append(effects, fmt.Sprintf("The deletion fails because the workspace manages %d resources", workspace.ResourceCount))
+	}
+	return effects
+}
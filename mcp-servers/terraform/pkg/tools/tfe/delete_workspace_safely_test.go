@@ -21,7 +21,7 @@ func TestDeleteWorkspaceSafely(t *testing.T) {
 		tool := DeleteWorkspaceSafely(logger)
 		
 		assert.Equal(t, "delete_workspace_safely", tool.Tool.Name)
-		assert.Contains(t, tool.Tool.Description, "Safely deletes a Terraform workspace by ID")
+		assert.Contains(t, tool.Tool.Description, "Safely deletes a Terraform workspace, addressed by ID")
 		assert.NotNil(t, tool.Handler)
 		
 		// Verify it's marked as destructive
@@ -30,8 +30,9 @@ func TestDeleteWorkspaceSafely(t *testing.T) {
 		assert.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
 		assert.False(t, *tool.Tool.Annotations.ReadOnlyHint)
 		
-		// Check that required parameters are defined
-		assert.Contains(t, tool.Tool.InputSchema.Required, "workspace_id")
+		// The workspace is addressed by ID, or by organization and name
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "workspace_id")
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "workspace_name")
 	})
 
 	t.Run("parameter validation", func(t *testing.T) {
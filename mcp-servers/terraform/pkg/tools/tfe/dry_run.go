@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/jsonapi"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+)
+
+// dryRunParam is the argument that makes a mutating tool describe the request it would send instead of sending it
+const dryRunParam = "dry_run"
+
+// withDryRun adds the dry_run argument to a mutating tool
+func withDryRun() mcp.ToolOption {
+	return mcp.WithBoolean(dryRunParam,
+		mcp.Description("If true, return the API request and its predicted effects without changing anything. Only read requests are sent to HCP Terraform/TFE."),
+		mcp.DefaultBool(false),
+	)
+}
+
+// DryRunResult describes the API request a mutating tool would send
+type DryRunResult struct {
+	DryRun  bool            `json:"dry_run"`
+	Tool    string          `json:"tool"`
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Effects []string        `json:"effects"`
+}
+
+// dryRunResult renders the request of a mutating tool. payload is the go-tfe options struct sent
+// with the request, or nil when the request has no body.
+func dryRunResult(request mcp.CallToolRequest, method, path string, payload any, effects []string, logger *log.Logger) (*mcp.CallToolResult, error) {
+	result, err := newDryRun(request, method, path, payload, effects)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling dry run result", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// newDryRun describes a single API request, for tools that send several of them. path is relative
+// to /api/v2/, unless it is absolute like the paths of the registry API.
+func newDryRun(request mcp.CallToolRequest, method, path string, payload any, effects []string) (DryRunResult, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/api/v2/" + path
+	}
+	result := DryRunResult{
+		DryRun:  true,
+		Tool:    request.Params.Name,
+		Method:  method,
+		Path:    path,
+		Effects: effects,
+	}
+
+	if payload != nil {
+		body, err := encodePayload(payload)
+		if err != nil {
+			return DryRunResult{}, err
+		}
+		result.Payload = body
+	}
+	return result, nil
+}
+
+// encodePayload encodes a request body the same way go-tfe does: structs with json tags are
+// sent as plain JSON and all other options, including lists of resources, as a JSON:API document
+func encodePayload(payload any) (json.RawMessage, error) {
+	model := reflect.Indirect(reflect.ValueOf(payload)).Type()
+	for i := 0; model.Kind() == reflect.Struct && i < model.NumField(); i++ {
+		if model.Field(i).Tag.Get("json") != "" {
+			return json.Marshal(payload)
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := jsonapi.MarshalPayloadWithoutIncluded(buf, payload); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodePayload(t *testing.T) {
+	t.Run("JSON:API options", func(t *testing.T) {
+		payload, err := encodePayload(&tfe.WorkspaceCreateOptions{Name: tfe.String("staging"), ExecutionMode: tfe.String("agent")})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"data": {"type": "workspaces", "attributes": {"name": "staging", "execution-mode": "agent"}}}`, string(payload))
+	})
+
+	t.Run("JSON options", func(t *testing.T) {
+		payload, err := encodePayload(&tfe.RunApplyOptions{Comment: tfe.String("ship it")})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"comment": "ship it"}`, string(payload))
+	})
+}
+
+func TestDryRunResult(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "delete_workspace_safely"
+
+	result, err := dryRunResult(request, "POST", "workspaces/ws-123/actions/safe-delete", nil, []string{"Deletes workspace staging (ws-123)"}, logger)
+	require.NoError(t, err)
+
+	var dryRun DryRunResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &dryRun))
+	assert.Equal(t, DryRunResult{
+		DryRun:  true,
+		Tool:    "delete_workspace_safely",
+		Method:  "POST",
+		Path:    "/api/v2/workspaces/ws-123/actions/safe-delete",
+		Effects: []string{"Deletes workspace staging (ws-123)"},
+	}, dryRun)
+}
+
+func TestUpdateWorkspaceEffects(t *testing.T) {
+	effects := updateWorkspaceEffects("org/staging", &tfe.WorkspaceUpdateOptions{
+		ExecutionMode: tfe.String("agent"),
+		AutoApply:     tfe.Bool(false),
+	})
+	assert.Equal(t, []string{
+		"Updates workspace org/staging",
+		`Sets execution_mode to "agent"`,
+		"Sets auto_apply to false",
+	}, effects)
+
+	assert.Equal(t, []string{"Leaves workspace org/staging unchanged"}, updateWorkspaceEffects("org/staging", &tfe.WorkspaceUpdateOptions{}))
+}
+
+func TestCreateRunEffects(t *testing.T) {
+	workspace := &tfe.Workspace{ID: "ws-123", Name: "staging", ResourceCount: 4}
+	assert.Equal(t, []string{
+		"Queues a run in workspace staging (ws-123)",
+		"The run destroys the 4 resources managed by the workspace",
+	}, createRunEffects(workspace, "is_destroy"))
+}
+
+func TestMutatingToolsAcceptDryRun(t *testing.T) {
+	logger := log.New()
+	tools := []mcp.Tool{
+		CreateWorkspace(logger).Tool,
+		UpdateWorkspace(logger).Tool,
+		DeleteWorkspaceSafely(logger).Tool,
+		CreateRun(logger).Tool,
+		ActionRun(logger).Tool,
+	}
+	for _, tool := range tools {
+		require.Contains(t, tool.InputSchema.Properties, dryRunParam, tool.Name)
+		assert.Equal(t, "boolean", tool.InputSchema.Properties[dryRunParam].(map[string]any)["type"], tool.Name)
+	}
+}
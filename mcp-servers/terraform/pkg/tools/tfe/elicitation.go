@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// elicitationSession returns the session of the current tool call when its client declared
+// the elicitation capability during initialization
+func elicitationSession(ctx context.Context) (server.SessionWithElicitation, bool) {
+	session := server.ClientSessionFromContext(ctx)
+	elicitor, ok := session.(server.SessionWithElicitation)
+	if !ok {
+		return nil, false
+	}
+	withClientInfo, ok := session.(server.SessionWithClientInfo)
+	if !ok || withClientInfo.GetClientCapabilities().Elicitation == nil {
+		return nil, false
+	}
+	return elicitor, true
+}
+
+// elicit asks the end user, through the client, for the properties described by the JSON schema.
+// asked is false when the client does not support elicitation or the request failed, in which case
+// the caller falls back to its non-interactive behaviour. Otherwise action is the user's answer and
+// content holds the submitted values when the user accepted.
+func elicit(ctx context.Context, message string, properties map[string]any, required []string, logger *log.Logger) (action mcp.ElicitationResponseAction, content map[string]any, asked bool) {
+	session, ok := elicitationSession(ctx)
+	if !ok {
+		return "", nil, false
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	result, err := session.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message:         message,
+			RequestedSchema: schema,
+		},
+	})
+	if err != nil || result == nil {
+		logger.WithError(err).Warn("Elicitation request failed, falling back to the non-interactive flow")
+		return "", nil, false
+	}
+
+	content, _ = result.Content.(map[string]any)
+	return result.Action, content, true
+}
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// elicitFunc answers the elicitation requests of an in-process client
+type elicitFunc func(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error)
+
+func (f elicitFunc) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	return f(ctx, request)
+}
+
+// elicitationContext returns the context of a tool call from a client that supports elicitation
+func elicitationContext(ctx context.Context, answer elicitFunc) context.Context {
+	session := server.NewInProcessSessionWithHandlers("elicitation-session", nil, answer, nil)
+	session.SetClientCapabilities(mcp.ClientCapabilities{Elicitation: &struct{}{}})
+	return server.NewMCPServer("test", "0.0.0").WithContext(ctx, session)
+}
+
+// elicitationAnswer returns an elicitFunc that always gives the same answer and records the requests
+func elicitationAnswer(action mcp.ElicitationResponseAction, content map[string]any, requests *[]mcp.ElicitationRequest) elicitFunc {
+	return func(_ context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+		if requests != nil {
+			*requests = append(*requests, request)
+		}
+		return &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{Action: action, Content: content}}, nil
+	}
+}
+
+func TestElicit(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	properties := map[string]any{"confirm": map[string]any{"type": "boolean"}}
+
+	t.Run("without a session", func(t *testing.T) {
+		_, _, asked := elicit(t.Context(), "proceed?", properties, nil, logger)
+		assert.False(t, asked)
+	})
+
+	t.Run("client without the capability", func(t *testing.T) {
+		session := server.NewInProcessSessionWithHandlers("session", nil, elicitationAnswer(mcp.ElicitationResponseActionAccept, nil, nil), nil)
+		ctx := server.NewMCPServer("test", "0.0.0").WithContext(t.Context(), session)
+		_, _, asked := elicit(ctx, "proceed?", properties, nil, logger)
+		assert.False(t, asked)
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		var requests []mcp.ElicitationRequest
+		ctx := elicitationContext(t.Context(), elicitationAnswer(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": true}, &requests))
+		action, content, asked := elicit(ctx, "proceed?", properties, []string{"confirm"}, logger)
+		require.True(t, asked)
+		assert.Equal(t, mcp.ElicitationResponseActionAccept, action)
+		assert.Equal(t, true, content["confirm"])
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "proceed?", requests[0].Params.Message)
+		schema := requests[0].Params.RequestedSchema.(map[string]any)
+		assert.Equal(t, "object", schema["type"])
+		assert.Equal(t, []string{"confirm"}, schema["required"])
+	})
+
+	t.Run("failed request", func(t *testing.T) {
+		ctx := elicitationContext(t.Context(), func(context.Context, mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+			return nil, errors.New("client went away")
+		})
+		_, _, asked := elicit(ctx, "proceed?", properties, nil, logger)
+		assert.False(t, asked)
+	})
+}
+
+func TestRequireConfirmationElicitation(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "delete_workspace_safely"
+	request.Params.Arguments = map[string]any{"workspace_id": "ws-123"}
+
+	t.Run("confirmed", func(t *testing.T) {
+		var requests []mcp.ElicitationRequest
+		ctx := elicitationContext(t.Context(), elicitationAnswer(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": true}, &requests))
+		result, err := requireConfirmation(ctx, request, "delete workspace ws-123", map[string]any{"resource_count": 3}, logger)
+		require.NoError(t, err)
+		assert.Nil(t, result)
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "delete_workspace_safely will delete workspace ws-123.\n- resource_count: 3\nDo you want to proceed?", requests[0].Params.Message)
+	})
+
+	for name, answer := range map[string]elicitFunc{
+		"not confirmed": elicitationAnswer(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": false}, nil),
+		"declined":      elicitationAnswer(mcp.ElicitationResponseActionDecline, nil, nil),
+		"cancelled":     elicitationAnswer(mcp.ElicitationResponseActionCancel, nil, nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := requireConfirmation(elicitationContext(t.Context(), answer), request, "delete workspace ws-123", nil, logger)
+			require.NoError(t, err)
+			require.NotNil(t, result)
+
+			var declined ConfirmationDeclined
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &declined))
+			assert.True(t, declined.Cancelled)
+			assert.Equal(t, "delete_workspace_safely", declined.Tool)
+		})
+	}
+
+	t.Run("falls back to a token when the request fails", func(t *testing.T) {
+		ctx := elicitationContext(t.Context(), func(context.Context, mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+			return nil, errors.New("client went away")
+		})
+		result, err := requireConfirmation(ctx, request, "delete workspace ws-123", nil, logger)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+
+		var confirmation ConfirmationRequired
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &confirmation))
+		assert.True(t, confirmation.ConfirmationRequired)
+		assert.NotEmpty(t, confirmation.ConfirmationToken)
+	})
+}
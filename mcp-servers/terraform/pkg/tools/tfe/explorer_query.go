@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxExplorerFilters is the number of filters an explorer query may combine
+const maxExplorerFilters = 10
+
+var (
+	// explorerViews are the views of the Explorer API
+	explorerViews = []string{"workspaces", "tf_versions", "providers", "modules"}
+	// explorerOperators are the comparisons of Explorer filters
+	explorerOperators = []string{"is", "is_not", "contains", "does_not_contain", "is_empty", "is_not_empty", "gt", "lt", "gteq", "lteq", "is_before", "is_after"}
+	// explorerField matches the snake_case name of an Explorer field, e.g. terraform_version
+	explorerField = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// ExplorerFilter is a condition on a field of an Explorer view. Value is ignored by is_empty and is_not_empty.
+type ExplorerFilter struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+}
+
+// ExplorerQueryResult is the result of the explorer_query tool. Each row holds the fields of one workspace,
+// Terraform version, provider or module, named as the API returns them.
+type ExplorerQueryResult struct {
+	Organization string           `json:"organization"`
+	View         string           `json:"view"`
+	Rows         []map[string]any `json:"rows"`
+	Pagination   *tfe.Pagination  `json:"pagination,omitempty"`
+}
+
+// explorerResponse is the JSON:API document of an Explorer query, whose attributes depend on the view
+type explorerResponse struct {
+	Data []struct {
+		ID         string         `json:"id"`
+		Attributes map[string]any `json:"attributes"`
+	} `json:"data"`
+	Meta struct {
+		Pagination *tfe.Pagination `json:"pagination"`
+	} `json:"meta"`
+}
+
+// ExplorerQuery creates a tool to query the Explorer views of an HCP Terraform organization.
+func ExplorerQuery(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("explorer_query",
+			mcp.WithDescription(`Queries the Explorer of an HCP Terraform organization, which aggregates data across all its workspaces: the workspaces view lists them with their Terraform version, run status, drift and check results; the tf_versions, providers and modules views list each Terraform version, provider and module in use with the workspaces using it.
+Prefer it to listing workspaces one by one for fleet-level questions, e.g. which workspaces still run Terraform 0.x or use a given provider version.
+Fields and filters use snake_case field names (e.g., 'workspace_name', 'version', 'workspace_count'), while the rows return the fields as the API names them.`),
+			mcp.WithTitleAnnotation("Query the Explorer of an HCP Terraform organization"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("view",
+				mcp.Required(),
+				mcp.Description("The Explorer view to query"),
+				mcp.Enum(explorerViews...),
+			),
+			mcp.WithString("fields",
+				mcp.Description("Comma-separated fields to return, e.g. 'workspace_name,current_run_status'. Defaults to every field of the view."),
+			),
+			mcp.WithString("sort",
+				mcp.Description("The field to sort by, prefixed with '-' for a descending order (e.g., '-workspace_count')"),
+			),
+			mcp.WithArray("filters",
+				mcp.Description("Conditions the rows must all meet"),
+				mcp.MaxItems(maxExplorerFilters),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"field": map[string]any{
+							"type":        "string",
+							"description": "The snake_case name of the field, e.g. 'terraform_version'",
+						},
+						"operator": map[string]any{
+							"type":        "string",
+							"description": "The comparison of the field with the value",
+							"enum":        toAnySlice(explorerOperators),
+						},
+						"value": map[string]any{
+							"type":        "string",
+							"description": "The value to compare the field with, unused by is_empty and is_not_empty",
+						},
+					},
+					"required": []any{"field", "operator"},
+				}),
+			),
+			utils.WithPagination(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return explorerQueryHandler(ctx, request, logger)
+		},
+	}
+}
+
+func explorerQueryHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	view := strings.TrimSpace(request.GetString("view", ""))
+	if !slices.Contains(explorerViews, view) {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("view must be one of %s", strings.Join(explorerViews, ", ")), nil)
+	}
+	filters, err := parseExplorerFilters(request.GetArguments()["filters"])
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid filters", err)
+	}
+
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
+	}
+
+	query := url.Values{
+		"type":         {view},
+		"page[number]": {strconv.Itoa(pagination.Page)},
+		"page[size]":   {strconv.Itoa(pagination.PageSize)},
+	}
+	if fields := parseTagNames(request.GetString("fields", "")); len(fields) > 0 {
+		for _, field := range fields {
+			if !explorerField.MatchString(field) {
+				return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("invalid field %q: fields are snake_case names", field), nil)
+			}
+		}
+		query.Set("fields", strings.Join(fields, ","))
+	}
+	if sort := strings.TrimSpace(request.GetString("sort", "")); sort != "" {
+		if !explorerField.MatchString(strings.TrimPrefix(sort, "-")) {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("invalid sort %q: sort by a snake_case field name", sort), nil)
+		}
+		query.Set("sort", sort)
+	}
+	for i, filter := range filters {
+		query.Set(fmt.Sprintf("filter[%d][%s][%s][0]", i, filter.Field, filter.Operator), filter.Value)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	req, err := tfeClient.NewRequestWithAdditionalQueryParams("GET", fmt.Sprintf("organizations/%s/explorer", url.PathEscape(terraformOrgName)), nil, query)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "creating explorer request", err)
+	}
+	response := &explorerResponse{}
+	if err := req.DoJSON(ctx, response); err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("querying the %s explorer view of organization %s", view, terraformOrgName), err)
+	}
+
+	result := ExplorerQueryResult{
+		Organization: terraformOrgName,
+		View:         view,
+		Rows:         make([]map[string]any, 0, len(response.Data)),
+		Pagination:   response.Meta.Pagination,
+	}
+	for _, row := range response.Data {
+		result.Rows = append(result.Rows, row.Attributes)
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling explorer rows", err)
+	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(resultJSON)), pagination, nextPage(response.Meta.Pagination)), nil
+}
+
+// parseExplorerFilters decodes the filters argument
+func parseExplorerFilters(raw any) ([]ExplorerFilter, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("filters must be an array")
+	}
+	if len(items) > maxExplorerFilters {
+		return nil, fmt.Errorf("at most %d filters can be combined, got %d", maxExplorerFilters, len(items))
+	}
+
+	// Round-trip through JSON to decode the objects into the filter struct
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var filters []ExplorerFilter
+	if err := json.Unmarshal(encoded, &filters); err != nil {
+		return nil, fmt.Errorf("filters must be objects with a field, an operator and a value: %w", err)
+	}
+	for i, filter := range filters {
+		if !explorerField.MatchString(filter.Field) {
+			return nil, fmt.Errorf("filter %d must have a snake_case field", i)
+		}
+		if !slices.Contains(explorerOperators, filter.Operator) {
+			return nil, fmt.Errorf("filter %d has an unknown operator %q", i, filter.Operator)
+		}
+		if filter.Value == "" && filter.Operator != "is_empty" && filter.Operator != "is_not_empty" {
+			return nil, fmt.Errorf("filter %d must have a value", i)
+		}
+	}
+	return filters, nil
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
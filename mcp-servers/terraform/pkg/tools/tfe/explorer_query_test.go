@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplorerQuery(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := ExplorerQuery(logger)
+	assert.Equal(t, "explorer_query", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Equal(t, []string{"terraform_org_name", "view"}, tool.Tool.InputSchema.Required)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		arguments["terraform_org_name"] = "acme"
+		arguments["view"] = "workspaces"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "explorer_query", Arguments: arguments}}
+	}
+
+	t.Run("sends fields, sort and filters", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Handle("GET", "/organizations/acme/explorer", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = w.Write([]byte(`{
+				"data": [{"id": "ws-123", "type": "visibility-workspace", "attributes": {"workspace-name": "network", "workspace-terraform-version": "0.14.11"}}],
+				"meta": {"pagination": {"current-page": 1, "next-page": 2, "total-pages": 2, "total-count": 21}}
+			}`))
+		})
+
+		result, err := explorerQueryHandler(fake.Context(t), request(map[string]any{
+			"fields": "workspace_name, workspace_terraform_version",
+			"sort":   "-workspace_name",
+			"filters": []any{
+				map[string]any{"field": "workspace_terraform_version", "operator": "contains", "value": "0.14"},
+				map[string]any{"field": "project_name", "operator": "is_not_empty"},
+			},
+			"pageSize": float64(20),
+		}), logger)
+		require.NoError(t, err)
+
+		var query ExplorerQueryResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &query))
+		require.Len(t, query.Rows, 1)
+		assert.Equal(t, "network", query.Rows[0]["workspace-name"])
+		assert.Equal(t, 21, query.Pagination.TotalCount)
+		assert.Contains(t, result.Content[1].(mcp.TextContent).Text, "page=2")
+
+		sent := fake.Requests()[0].Query
+		assert.Equal(t, "workspaces", sent.Get("type"))
+		assert.Equal(t, "workspace_name,workspace_terraform_version", sent.Get("fields"))
+		assert.Equal(t, "-workspace_name", sent.Get("sort"))
+		assert.Equal(t, "20", sent.Get("page[size]"))
+		assert.Equal(t, "0.14", sent.Get("filter[0][workspace_terraform_version][contains][0]"))
+		assert.Contains(t, sent, "filter[1][project_name][is_not_empty][0]")
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		for name, arguments := range map[string]map[string]any{
+			"field":    {"fields": "workspace-name"},
+			"sort":     {"sort": "name desc"},
+			"operator": {"filters": []any{map[string]any{"field": "workspace_name", "operator": "like", "value": "app"}}},
+			"value":    {"filters": []any{map[string]any{"field": "workspace_name", "operator": "is"}}},
+		} {
+			_, err := explorerQueryHandler(testutil.NewFakeTFE(t).Context(t), request(arguments), logger)
+			assert.Error(t, err, name)
+		}
+	})
+}
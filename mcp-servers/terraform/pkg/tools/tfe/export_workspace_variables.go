@@ -0,0 +1,208 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// variablesFormatJSON and variablesFormatTfvars are the formats of exported and imported variables
+	variablesFormatJSON   = "json"
+	variablesFormatTfvars = "tfvars"
+)
+
+// WorkspaceVariable is a workspace variable as exported by export_workspace_variables and imported by
+// import_workspace_variables. The value of a masked sensitive variable is left out.
+type WorkspaceVariable struct {
+	Key         string  `json:"key"`
+	Value       *string `json:"value,omitempty"`
+	Category    string  `json:"category"`
+	HCL         bool    `json:"hcl"`
+	Sensitive   bool    `json:"sensitive"`
+	Description string  `json:"description,omitempty"`
+}
+
+// WorkspaceVariablesExport is the result of the export_workspace_variables tool in JSON format
+type WorkspaceVariablesExport struct {
+	Organization string              `json:"organization"`
+	Workspace    string              `json:"workspace"`
+	Variables    []WorkspaceVariable `json:"variables"`
+	// Excluded lists the keys of the sensitive variables left out with sensitive_values set to exclude
+	Excluded []string `json:"excluded,omitempty"`
+}
+
+// ExportWorkspaceVariables creates a tool to export the variables of a workspace as JSON or tfvars.
+func ExportWorkspaceVariables(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("export_workspace_variables",
+			mcp.WithDescription(`Exports the Terraform and environment variables of a workspace, e.g. to clone a workspace or promote its configuration to another environment with import_workspace_variables.
+The JSON format includes every variable with its category, HCL and sensitive flags and description. The tfvars format only includes Terraform variables, as a terraform.tfvars file.
+Sensitive values cannot be read from HCP Terraform/TFE: they are masked, i.e. listed without their value, or excluded.`),
+			mcp.WithTitleAnnotation("Export the variables of a Terraform workspace"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			WithWorkspace("The name of the workspace to export the variables of"),
+			mcp.WithString("format",
+				mcp.Description("The format of the export: 'json' for every variable, or 'tfvars' for the Terraform variables only"),
+				mcp.Enum(variablesFormatJSON, variablesFormatTfvars),
+				mcp.DefaultString(variablesFormatJSON),
+			),
+			mcp.WithString("sensitive_values",
+				mcp.Description("How sensitive variables are exported: 'mask' lists them without their value, 'exclude' leaves them out"),
+				mcp.Enum("mask", "exclude"),
+				mcp.DefaultString("mask"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return exportWorkspaceVariablesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func exportWorkspaceVariablesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+
+	format := strings.ToLower(strings.TrimSpace(request.GetString("format", variablesFormatJSON)))
+	if format != variablesFormatJSON && format != variablesFormatTfvars {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid format: must be 'json' or 'tfvars'", nil)
+	}
+	sensitiveValues := strings.ToLower(strings.TrimSpace(request.GetString("sensitive_values", "mask")))
+	if sensitiveValues != "mask" && sensitiveValues != "exclude" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid sensitive_values: must be 'mask' or 'exclude'", nil)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+	terraformOrgName := workspace.Organization.Name
+	variables, err := listWorkspaceVariables(ctx, tfeClient, workspace.ID)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing workspace variables", err)
+	}
+
+	export := exportVariables(terraformOrgName, workspace.Name, variables, sensitiveValues == "exclude")
+	if format == variablesFormatTfvars {
+		return mcp.NewToolResultText(renderTfvars(export)), nil
+	}
+
+	resultJSON, err := json.Marshal(export)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling workspace variables", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// listWorkspaceVariables returns every page of the variables of a workspace
+func listWorkspaceVariables(ctx context.Context, tfeClient *tfe.Client, workspaceID string) ([]*tfe.Variable, error) {
+	var variables []*tfe.Variable
+	options := &tfe.VariableListOptions{ListOptions: tfe.ListOptions{PageSize: 100}}
+	for {
+		page, err := tfeClient.Variables.List(ctx, workspaceID, options)
+		if err != nil {
+			return nil, err
+		}
+		variables = append(variables, page.Items...)
+		next := nextPage(page.Pagination)
+		if next == 0 {
+			return variables, nil
+		}
+		options.PageNumber = next
+	}
+}
+
+// exportVariables converts the variables of a workspace, the Terraform variables first, then by key. Sensitive
+// variables are listed without their value, or left out when exclude is set.
+func exportVariables(organization, workspace string, variables []*tfe.Variable, excludeSensitive bool) WorkspaceVariablesExport {
+	export := WorkspaceVariablesExport{Organization: organization, Workspace: workspace, Variables: []WorkspaceVariable{}}
+	for _, variable := range variables {
+		if variable.Sensitive && excludeSensitive {
+			export.Excluded = append(export.Excluded, variable.Key)
+			continue
+		}
+		exported := WorkspaceVariable{
+			Key:         variable.Key,
+			Category:    string(variable.Category),
+			HCL:         variable.HCL,
+			Sensitive:   variable.Sensitive,
+			Description: variable.Description,
+		}
+		if !variable.Sensitive {
+			value := variable.Value
+			exported.Value = &value
+		}
+		export.Variables = append(export.Variables, exported)
+	}
+	sort.Slice(export.Variables, func(i, j int) bool {
+		if export.Variables[i].Category != export.Variables[j].Category {
+			return export.Variables[i].Category == string(tfe.CategoryTerraform)
+		}
+		return export.Variables[i].Key < export.Variables[j].Key
+	})
+	sort.Strings(export.Excluded)
+	return export
+}
+
+// renderTfvars writes the Terraform variables of an export as a tfvars file. HCL values are written
+// as they are, other values as strings. The variables a tfvars file cannot hold are listed in comments.
+func renderTfvars(export WorkspaceVariablesExport) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Terraform variables of workspace %s/%s\n", export.Organization, export.Workspace)
+
+	var masked, environment []string
+	for _, variable := range export.Variables {
+		switch {
+		case variable.Category != string(tfe.CategoryTerraform):
+			environment = append(environment, variable.Key)
+		case variable.Value == nil:
+			masked = append(masked, variable.Key)
+		}
+	}
+	if len(masked) > 0 {
+		fmt.Fprintf(&builder, "# Sensitive variables without a value: %s\n", strings.Join(masked, ", "))
+	}
+	if len(export.Excluded) > 0 {
+		fmt.Fprintf(&builder, "# Sensitive variables excluded: %s\n", strings.Join(export.Excluded, ", "))
+	}
+	if len(environment) > 0 {
+		fmt.Fprintf(&builder, "# Environment variables not included: %s\n", strings.Join(environment, ", "))
+	}
+	builder.WriteString("\n")
+
+	for _, variable := range export.Variables {
+		if variable.Category != string(tfe.CategoryTerraform) || variable.Value == nil {
+			continue
+		}
+		if variable.Description != "" {
+			fmt.Fprintf(&builder, "# %s\n", strings.ReplaceAll(variable.Description, "\n", "\n# "))
+		}
+		value := strings.TrimSpace(*variable.Value)
+		if !variable.HCL {
+			value = string(hclwrite.TokensForValue(cty.StringVal(*variable.Value)).Bytes())
+		}
+		fmt.Fprintf(&builder, "%s = %s\n", variable.Key, value)
+	}
+	return builder.String()
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func workspaceVariablesFixture() []*tfe.Variable {
+	return []*tfe.Variable{
+		{ID: "var-1", Key: "region", Value: "eu-west-1", Category: tfe.CategoryTerraform, Description: "AWS region"},
+		{ID: "var-2", Key: "AWS_DEFAULT_REGION", Value: "eu-west-1", Category: tfe.CategoryEnv},
+		{ID: "var-3", Key: "db_password", Category: tfe.CategoryTerraform, Sensitive: true},
+		{ID: "var-4", Key: "tags", Value: "{\n  team = \"platform\"\n}", Category: tfe.CategoryTerraform, HCL: true},
+	}
+}
+
+func TestExportWorkspaceVariables(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := ExportWorkspaceVariables(logger)
+	assert.Equal(t, "export_workspace_variables", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, "workspace_id")
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		arguments["terraform_org_name"] = "acme"
+		arguments["workspace_name"] = "staging"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "export_workspace_variables", Arguments: arguments}}
+	}
+	fake := testutil.NewFakeTFE(t)
+	fake.Respond("GET", "/organizations/acme/workspaces/staging", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging"})
+	fake.RespondList("GET", "/workspaces/ws-123/vars", workspaceVariablesFixture(), &tfe.Pagination{CurrentPage: 1, TotalPages: 1})
+
+	t.Run("json with masked sensitive values", func(t *testing.T) {
+		result, err := exportWorkspaceVariablesHandler(fake.Context(t), request(map[string]any{}), logger)
+		require.NoError(t, err)
+
+		var export WorkspaceVariablesExport
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &export))
+		require.Len(t, export.Variables, 4)
+		keys := []string{}
+		for _, variable := range export.Variables {
+			keys = append(keys, variable.Key)
+		}
+		assert.Equal(t, []string{"db_password", "region", "tags", "AWS_DEFAULT_REGION"}, keys)
+		assert.Nil(t, export.Variables[0].Value)
+		assert.True(t, export.Variables[0].Sensitive)
+		assert.Equal(t, "eu-west-1", *export.Variables[1].Value)
+		assert.Equal(t, "AWS region", export.Variables[1].Description)
+		assert.Empty(t, export.Excluded)
+	})
+
+	t.Run("json without sensitive variables", func(t *testing.T) {
+		result, err := exportWorkspaceVariablesHandler(fake.Context(t), request(map[string]any{"sensitive_values": "exclude"}), logger)
+		require.NoError(t, err)
+
+		var export WorkspaceVariablesExport
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &export))
+		assert.Len(t, export.Variables, 3)
+		assert.Equal(t, []string{"db_password"}, export.Excluded)
+	})
+
+	t.Run("tfvars", func(t *testing.T) {
+		result, err := exportWorkspaceVariablesHandler(fake.Context(t), request(map[string]any{"format": "tfvars"}), logger)
+		require.NoError(t, err)
+		assert.Equal(t, `# Terraform variables of workspace acme/staging
+# Sensitive variables without a value: db_password
+# Environment variables not included: AWS_DEFAULT_REGION
+
+# AWS region
+region = "eu-west-1"
+tags = {
+  team = "platform"
+}
+`, result.Content[0].(mcp.TextContent).Text)
+	})
+}
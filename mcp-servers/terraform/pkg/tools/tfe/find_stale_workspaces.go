@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStaleDays       = 90
+	defaultErroredRunCount = 3
+	maxErroredRunCount     = 20
+	// erroredRunsConcurrency is the number of run lists read at the same time
+	erroredRunsConcurrency = 5
+)
+
+// Reasons a workspace is flagged as stale
+const (
+	staleReasonNoRuns      = "no_recent_runs"
+	staleReasonNoResources = "no_resources"
+	staleReasonErroredRuns = "errored_runs"
+)
+
+// StaleWorkspace is a workspace flagged by find_stale_workspaces, with the reasons it was flagged
+type StaleWorkspace struct {
+	WorkspaceID   string        `json:"workspace_id"`
+	WorkspaceName string        `json:"workspace_name"`
+	ProjectID     string        `json:"project_id,omitempty"`
+	ResourceCount int           `json:"resource_count"`
+	CreatedAt     time.Time     `json:"created_at"`
+	LastRunAt     *time.Time    `json:"last_run_at,omitempty"`
+	LastRunStatus string        `json:"last_run_status,omitempty"`
+	Reasons       []string      `json:"reasons"`
+	Cleanup       *DryRunResult `json:"cleanup,omitempty"`
+}
+
+// StaleWorkspaceList is the result of the find_stale_workspaces tool
+type StaleWorkspaceList struct {
+	Organization string           `json:"organization"`
+	StaleDays    int              `json:"stale_days"`
+	Checked      int              `json:"checked"`
+	Truncated    bool             `json:"truncated,omitempty"`
+	Workspaces   []StaleWorkspace `json:"workspaces"`
+}
+
+// FindStaleWorkspaces creates a tool to flag workspaces that look abandoned.
+func FindStaleWorkspaces(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("find_stale_workspaces",
+			mcp.WithDescription(fmt.Sprintf(`Flags the workspaces of a Terraform organization that look abandoned: no run in the last stale_days days, no managed resources although created more than stale_days days ago, or only errored runs recently.
+With propose_cleanup, each flagged workspace includes the dry run of delete_workspace_safely, so the cleanup plan can be reviewed before deleting anything. Nothing is changed by this tool. At most %d workspaces are checked.`, maxReportWorkspaces)),
+			mcp.WithTitleAnnotation("Find stale Terraform workspaces"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("project_id",
+				mcp.Description("Only check the workspaces of this project"),
+			),
+			mcp.WithNumber("stale_days",
+				mcp.Description("The number of days without a run after which a workspace is stale"),
+				mcp.DefaultNumber(defaultStaleDays),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("errored_run_count",
+				mcp.Description("Flag workspaces whose last errored_run_count runs all errored"),
+				mcp.DefaultNumber(defaultErroredRunCount),
+				mcp.Min(1),
+				mcp.Max(maxErroredRunCount),
+			),
+			mcp.WithBoolean("propose_cleanup",
+				mcp.Description("Include the dry run of delete_workspace_safely for each flagged workspace"),
+				mcp.DefaultBool(false),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return findStaleWorkspacesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func findStaleWorkspacesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+	projectID := strings.TrimSpace(request.GetString("project_id", ""))
+	staleDays := max(request.GetInt("stale_days", defaultStaleDays), 1)
+	erroredRunCount := min(max(request.GetInt("errored_run_count", defaultErroredRunCount), 1), maxErroredRunCount)
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspaces, truncated, err := listOrgWorkspaces(ctx, tfeClient, terraformOrgName, projectID)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing workspaces in organization", err)
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -staleDays)
+	erroredRuns := checkErroredRuns(ctx, tfeClient, workspaces, erroredRunCount, logger)
+	stale := staleWorkspaces(workspaces, cutoff, erroredRuns)
+
+	if request.GetBool("propose_cleanup", false) {
+		// The cleanup plan is the dry run of delete_workspace_safely for each stale workspace
+		deleteRequest := mcp.CallToolRequest{}
+		deleteRequest.Params.Name = "delete_workspace_safely"
+		byID := make(map[string]*tfe.Workspace, len(workspaces))
+		for _, workspace := range workspaces {
+			byID[workspace.ID] = workspace
+		}
+		for i := range stale {
+			workspace := byID[stale[i].WorkspaceID]
+			cleanup, err := newDryRun(deleteRequest, "POST", safeDeletePath(workspace.ID), nil, safeDeleteEffects(workspace))
+			if err != nil {
+				return nil, utils.LogAndReturnError(logger, "describing the cleanup", err)
+			}
+			stale[i].Cleanup = &cleanup
+		}
+	}
+
+	resultJSON, err := json.Marshal(StaleWorkspaceList{
+		Organization: terraformOrgName,
+		StaleDays:    staleDays,
+		Checked:      len(workspaces),
+		Truncated:    truncated,
+		Workspaces:   stale,
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling stale workspaces", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// checkErroredRuns returns the IDs of the workspaces whose last count runs all errored. Only the
+// workspaces with an errored current run are checked, one run list request each.
+func checkErroredRuns(ctx context.Context, tfeClient *tfe.Client, workspaces []*tfe.Workspace, count int, logger *log.Logger) map[string]bool {
+	errored := make(map[string]bool)
+	var mu sync.Mutex
+	semaphore := make(chan struct{}, erroredRunsConcurrency)
+	var wg sync.WaitGroup
+	for _, workspace := range workspaces {
+		if workspace.CurrentRun == nil || workspace.CurrentRun.Status != tfe.RunErrored {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			runs, err := tfeClient.Runs.List(ctx, workspace.ID, &tfe.RunListOptions{ListOptions: tfe.ListOptions{PageNumber: 1, PageSize: count}})
+			if err != nil {
+				logger.WithField("workspace", workspace.Name).Warnf("Failed to list runs: %v", err)
+				return
+			}
+			if len(runs.Items) < count {
+				return
+			}
+			for _, run := range runs.Items[:count] {
+				if run.Status != tfe.RunErrored {
+					return
+				}
+			}
+			mu.Lock()
+			errored[workspace.ID] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return errored
+}
+
+// staleWorkspaces flags the workspaces without a run since cutoff, without resources although created
+// before cutoff, or listed in erroredRuns. The stale workspaces are sorted by name.
+func staleWorkspaces(workspaces []*tfe.Workspace, cutoff time.Time, erroredRuns map[string]bool) []StaleWorkspace {
+	stale := []StaleWorkspace{}
+	for _, workspace := range workspaces {
+		candidate := StaleWorkspace{
+			WorkspaceID:   workspace.ID,
+			WorkspaceName: workspace.Name,
+			ResourceCount: workspace.ResourceCount,
+			CreatedAt:     workspace.CreatedAt,
+		}
+		if workspace.Project != nil {
+			candidate.ProjectID = workspace.Project.ID
+		}
+
+		lastActivity := workspace.CreatedAt
+		if run := workspace.CurrentRun; run != nil && !run.CreatedAt.IsZero() {
+			candidate.LastRunAt = &run.CreatedAt
+			candidate.LastRunStatus = string(run.Status)
+			lastActivity = run.CreatedAt
+		}
+		if lastActivity.Before(cutoff) {
+			candidate.Reasons = append(candidate.Reasons, staleReasonNoRuns)
+		}
+		if workspace.ResourceCount == 0 && workspace.CreatedAt.Before(cutoff) {
+			candidate.Reasons = append(candidate.Reasons, staleReasonNoResources)
+		}
+		if erroredRuns[workspace.ID] {
+			candidate.Reasons = append(candidate.Reasons, staleReasonErroredRuns)
+		}
+		if len(candidate.Reasons) > 0 {
+			stale = append(stale, candidate)
+		}
+	}
+	sort.SliceStable(stale, func(a, b int) bool { return stale[a].WorkspaceName < stale[b].WorkspaceName })
+	return stale
+}
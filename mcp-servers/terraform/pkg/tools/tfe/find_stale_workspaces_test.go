@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleWorkspaces(t *testing.T) {
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -90)
+	old := now.AddDate(-1, 0, 0)
+
+	workspaces := []*tfe.Workspace{
+		// Active workspace
+		{ID: "ws-active", Name: "active", CreatedAt: old, ResourceCount: 10, CurrentRun: &tfe.Run{Status: tfe.RunApplied, CreatedAt: now}},
+		// No run since it was created a year ago
+		{ID: "ws-idle", Name: "idle", CreatedAt: old, ResourceCount: 5},
+		// New workspace without resources yet
+		{ID: "ws-new", Name: "new", CreatedAt: now},
+		// Old run and no resources
+		{ID: "ws-empty", Name: "empty", CreatedAt: old, CurrentRun: &tfe.Run{Status: tfe.RunPlannedAndFinished, CreatedAt: old}},
+		// Recent but errored runs
+		{ID: "ws-broken", Name: "broken", CreatedAt: old, ResourceCount: 3, CurrentRun: &tfe.Run{Status: tfe.RunErrored, CreatedAt: now}},
+	}
+
+	stale := staleWorkspaces(workspaces, cutoff, map[string]bool{"ws-broken": true})
+	require.Len(t, stale, 3)
+	assert.Equal(t, "broken", stale[0].WorkspaceName)
+	assert.Equal(t, []string{staleReasonErroredRuns}, stale[0].Reasons)
+	assert.Equal(t, "empty", stale[1].WorkspaceName)
+	assert.Equal(t, []string{staleReasonNoRuns, staleReasonNoResources}, stale[1].Reasons)
+	assert.Equal(t, string(tfe.RunPlannedAndFinished), stale[1].LastRunStatus)
+	assert.Equal(t, "idle", stale[2].WorkspaceName)
+	assert.Equal(t, []string{staleReasonNoRuns}, stale[2].Reasons)
+	assert.Nil(t, stale[2].LastRunAt)
+}
+
+func TestCheckErroredRuns(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.Header().Set("TFP-API-Version", "2.5")
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/workspaces/ws-broken/runs":
+			assert.Equal(t, "2", r.URL.Query().Get("page[size]"))
+			_, _ = w.Write([]byte(`{"data": [
+				{"id": "run-2", "type": "runs", "attributes": {"status": "errored"}},
+				{"id": "run-1", "type": "runs", "attributes": {"status": "errored"}}]}`))
+		case "/api/v2/workspaces/ws-flaky/runs":
+			_, _ = w.Write([]byte(`{"data": [
+				{"id": "run-4", "type": "runs", "attributes": {"status": "errored"}},
+				{"id": "run-3", "type": "runs", "attributes": {"status": "applied"}}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tfeClient, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", RetryServerErrors: false})
+	require.NoError(t, err)
+
+	workspaces := []*tfe.Workspace{
+		{ID: "ws-broken", Name: "broken", CurrentRun: &tfe.Run{Status: tfe.RunErrored}},
+		{ID: "ws-flaky", Name: "flaky", CurrentRun: &tfe.Run{Status: tfe.RunErrored}},
+		// Workspaces without an errored current run are not checked
+		{ID: "ws-ok", Name: "ok", CurrentRun: &tfe.Run{Status: tfe.RunApplied}},
+		{ID: "ws-none", Name: "none"},
+	}
+	errored := checkErroredRuns(t.Context(), tfeClient, workspaces, 2, logger)
+	assert.Equal(t, map[string]bool{"ws-broken": true}, errored)
+}
+
+func TestSafeDeleteEffects(t *testing.T) {
+	effects := safeDeleteEffects(&tfe.Workspace{ID: "ws-1", Name: "app", ResourceCount: 2})
+	require.Len(t, effects, 2)
+	assert.Contains(t, effects[1], "manages 2 resources")
+	assert.Equal(t, "workspaces/ws%2F1/actions/safe-delete", safeDeletePath("ws/1"))
+}
@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultAuditTrailDays is how far back the audit trail is read without a since argument
+const defaultAuditTrailDays = 7
+
+// AuditTrailEntry is an event of the audit trail of an organization: an action of an actor on a resource
+type AuditTrailEntry struct {
+	ID             string         `json:"id"`
+	Timestamp      time.Time      `json:"timestamp"`
+	Type           string         `json:"type"`
+	Action         string         `json:"action"`
+	ResourceType   string         `json:"resource_type"`
+	ResourceID     string         `json:"resource_id"`
+	Actor          string         `json:"actor"`
+	ActorID        string         `json:"actor_id"`
+	ActorType      string         `json:"actor_type"`
+	ImpersonatorID string         `json:"impersonator_id,omitempty"`
+	RequestID      string         `json:"request_id,omitempty"`
+	Meta           map[string]any `json:"meta,omitempty"`
+}
+
+// AuditTrailFilter selects the audit trail entries get_audit_trail returns. Empty fields match every entry.
+type AuditTrailFilter struct {
+	Until        *time.Time `json:"until,omitempty"`
+	Actor        string     `json:"actor,omitempty"`
+	Action       string     `json:"action,omitempty"`
+	ResourceType string     `json:"resource_type,omitempty"`
+	ResourceID   string     `json:"resource_id,omitempty"`
+}
+
+// AuditTrailResult is the result of the get_audit_trail tool
+type AuditTrailResult struct {
+	Organization string            `json:"organization"`
+	Since        time.Time         `json:"since"`
+	Filter       AuditTrailFilter  `json:"filter"`
+	Entries      []AuditTrailEntry `json:"entries"`
+	// Scanned is the number of entries of the page before filtering
+	Scanned    int                       `json:"scanned"`
+	Pagination *tfe.AuditTrailPagination `json:"pagination,omitempty"`
+}
+
+// GetAuditTrail creates a tool to read the audit trail of a Terraform organization.
+func GetAuditTrail(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("get_audit_trail",
+			mcp.WithDescription(fmt.Sprintf(`Reads the audit trail of an HCP Terraform organization: who did what to which resource and when, e.g. to find who deleted a workspace last week. Entries can be filtered by time range, actor, action, resource type and resource ID.
+The filters apply to each page of the audit trail, so a page may hold fewer entries than pageSize: keep paging until the last page to search the whole time range. Without since, the last %d days are read.
+The audit trail API needs an organization API token of the organization, and is only available on the HCP Terraform plans including audit logging.`, defaultAuditTrailDays)),
+			mcp.WithTitleAnnotation("Get the audit trail of a Terraform organization"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("since",
+				mcp.Description("The start of the time range, as an RFC 3339 timestamp (e.g., '2025-01-31T09:00:00Z') or a date (e.g., '2025-01-31')"),
+			),
+			mcp.WithString("until",
+				mcp.Description("The end of the time range, as an RFC 3339 timestamp or a date, excluded. Defaults to now."),
+			),
+			mcp.WithString("actor",
+				mcp.Description("Only return the entries of this actor: a user name, team or token description, or an accessor ID (e.g., 'user-abc123')"),
+			),
+			mcp.WithString("action",
+				mcp.Description("Only return the entries with this action (e.g., 'create', 'update', 'destroy')"),
+			),
+			mcp.WithString("resource_type",
+				mcp.Description("Only return the entries on this type of resource (e.g., 'workspace', 'run', 'variable')"),
+			),
+			mcp.WithString("resource_id",
+				mcp.Description("Only return the entries on this resource (e.g., 'ws-abc123def456')"),
+			),
+			utils.WithPagination(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return getAuditTrailHandler(ctx, request, logger)
+		},
+	}
+}
+
+func getAuditTrailHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	since := time.Now().UTC().AddDate(0, 0, -defaultAuditTrailDays)
+	if value := strings.TrimSpace(request.GetString("since", "")); value != "" {
+		if since, err = parseAuditTrailTime(value); err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid since", err)
+		}
+	}
+	filter := AuditTrailFilter{
+		Actor:        strings.TrimSpace(request.GetString("actor", "")),
+		Action:       strings.TrimSpace(request.GetString("action", "")),
+		ResourceType: strings.TrimSpace(request.GetString("resource_type", "")),
+		ResourceID:   strings.TrimSpace(request.GetString("resource_id", "")),
+	}
+	if value := strings.TrimSpace(request.GetString("until", "")); value != "" {
+		until, err := parseAuditTrailTime(value)
+		if err != nil {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid until", err)
+		}
+		if !until.After(since) {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "until must be after since", nil)
+		}
+		filter.Until = &until
+	}
+
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	// The audit trail is the one of the organization of the token, which must be the requested organization
+	organization, err := tfeClient.Organizations.Read(ctx, terraformOrgName)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading organization %s", terraformOrgName), err)
+	}
+
+	auditTrail, err := tfeClient.AuditTrails.List(ctx, &tfe.AuditTrailListOptions{
+		Since: since,
+		ListOptions: &tfe.ListOptions{
+			PageNumber: pagination.Page,
+			PageSize:   pagination.PageSize,
+		},
+	})
+	if errors.Is(err, tfe.ErrUnauthorized) || errors.Is(err, tfe.ErrResourceNotFound) {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeUnauthorized,
+			fmt.Sprintf("reading the audit trail of organization %s: the audit trail needs an organization API token and a plan including audit logging", terraformOrgName), err)
+	}
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "reading audit trail", err)
+	}
+	for _, item := range auditTrail.Items {
+		if item.Auth.OrganizationID != "" && item.Auth.OrganizationID != organization.ExternalID {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput,
+				fmt.Sprintf("reading the audit trail of organization %s", terraformOrgName),
+				fmt.Errorf("the token is an organization token of %s, not of %s", item.Auth.OrganizationID, organization.ExternalID))
+		}
+	}
+
+	result := AuditTrailResult{
+		Organization: terraformOrgName,
+		Since:        since,
+		Filter:       filter,
+		Entries:      filterAuditTrail(auditTrail.Items, filter),
+		Scanned:      len(auditTrail.Items),
+		Pagination:   auditTrail.AuditTrailPagination,
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling audit trail", err)
+	}
+	next := 0
+	if auditTrail.AuditTrailPagination != nil {
+		next = auditTrail.NextPage
+	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(resultJSON)), pagination, next), nil
+}
+
+// parseAuditTrailTime parses an RFC 3339 timestamp or a date, at midnight UTC
+func parseAuditTrailTime(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 timestamp nor a YYYY-MM-DD date", value)
+	}
+	return timestamp.UTC(), nil
+}
+
+// filterAuditTrail returns the entries matching the filter. Actors, actions and resource types match
+// regardless of case.
+func filterAuditTrail(items []*tfe.AuditTrail, filter AuditTrailFilter) []AuditTrailEntry {
+	entries := []AuditTrailEntry{}
+	for _, item := range items {
+		switch {
+		case filter.Until != nil && !item.Timestamp.Before(*filter.Until):
+			continue
+		case filter.Actor != "" && !strings.EqualFold(item.Auth.Description, filter.Actor) && item.Auth.AccessorID != filter.Actor:
+			continue
+		case filter.Action != "" && !strings.EqualFold(item.Resource.Action, filter.Action):
+			continue
+		case filter.ResourceType != "" && !strings.EqualFold(item.Resource.Type, filter.ResourceType):
+			continue
+		case filter.ResourceID != "" && item.Resource.ID != filter.ResourceID:
+			continue
+		}
+
+		entry := AuditTrailEntry{
+			ID:           item.ID,
+			Timestamp:    item.Timestamp,
+			Type:         item.Type,
+			Action:       item.Resource.Action,
+			ResourceType: item.Resource.Type,
+			ResourceID:   item.Resource.ID,
+			Actor:        item.Auth.Description,
+			ActorID:      item.Auth.AccessorID,
+			ActorType:    item.Auth.Type,
+			RequestID:    item.Request.ID,
+			Meta:         item.Resource.Meta,
+		}
+		if item.Auth.ImpersonatorID != nil {
+			entry.ImpersonatorID = *item.Auth.ImpersonatorID
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
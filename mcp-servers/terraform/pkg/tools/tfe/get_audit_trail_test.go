@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditTrailFixture is a page of the audit trail of organization org-acme, which the API serves as plain JSON
+const auditTrailFixture = `{
+	"data": [
+		{"id": "ae66e491-db59-457c-8445-9c908ee726ae", "version": "0", "type": "Resource", "timestamp": "2025-01-30T10:00:00Z",
+		 "auth": {"accessor_id": "user-alice", "description": "alice", "type": "Client", "organization_id": "org-acme"},
+		 "request": {"id": "req-1"},
+		 "resource": {"id": "ws-staging", "type": "workspace", "action": "destroy", "meta": {"name": "staging"}}},
+		{"id": "b9a4e1f0-3c25-4d7e-9d3a-0c6b2e5f7a18", "version": "0", "type": "Resource", "timestamp": "2025-01-30T11:00:00Z",
+		 "auth": {"accessor_id": "user-bob", "description": "bob", "type": "Client", "organization_id": "org-acme"},
+		 "request": {"id": "req-2"},
+		 "resource": {"id": "run-123", "type": "run", "action": "create", "meta": null}},
+		{"id": "c2d8f3a7-1e49-4b6a-8f2d-5a7c9e1b3d60", "version": "0", "type": "Resource", "timestamp": "2025-02-02T09:00:00Z",
+		 "auth": {"accessor_id": "user-alice", "description": "alice", "type": "Client", "organization_id": "org-acme"},
+		 "request": {"id": "req-3"},
+		 "resource": {"id": "ws-prod", "type": "workspace", "action": "destroy", "meta": {"name": "prod"}}}
+	],
+	"pagination": {"current_page": 1, "prev_page": 0, "next_page": 2, "total_pages": 2, "total_count": 4}
+}`
+
+func TestGetAuditTrail(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := GetAuditTrail(logger)
+	assert.Equal(t, "get_audit_trail", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Equal(t, []string{"terraform_org_name"}, tool.Tool.InputSchema.Required)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		arguments["terraform_org_name"] = "acme"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_audit_trail", Arguments: arguments}}
+	}
+	newFake := func(t *testing.T, externalID string) *testutil.FakeTFE {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/organizations/acme", http.StatusOK, &tfe.Organization{Name: "acme", ExternalID: externalID})
+		fake.Handle("GET", "/organization/audit-trail", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(auditTrailFixture))
+		})
+		return fake
+	}
+
+	t.Run("filters a page by actor, action and time range", func(t *testing.T) {
+		fake := newFake(t, "org-acme")
+		result, err := getAuditTrailHandler(fake.Context(t), request(map[string]any{
+			"since":  "2025-01-25",
+			"until":  "2025-02-01T00:00:00Z",
+			"actor":  "Alice",
+			"action": "destroy",
+		}), logger)
+		require.NoError(t, err)
+
+		var auditTrail AuditTrailResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &auditTrail))
+		require.Len(t, auditTrail.Entries, 1)
+		assert.Equal(t, "ws-staging", auditTrail.Entries[0].ResourceID)
+		assert.Equal(t, "user-alice", auditTrail.Entries[0].ActorID)
+		assert.Equal(t, "staging", auditTrail.Entries[0].Meta["name"])
+		assert.Equal(t, 3, auditTrail.Scanned)
+		assert.Equal(t, 2, auditTrail.Pagination.NextPage)
+		assert.Contains(t, result.Content[1].(mcp.TextContent).Text, "page=2")
+
+		requests := fake.Requests()
+		require.Len(t, requests, 2)
+		assert.Equal(t, "2025-01-25T00:00:00Z", requests[1].Query.Get("since"))
+	})
+
+	t.Run("rejects the token of another organization", func(t *testing.T) {
+		_, err := getAuditTrailHandler(newFake(t, "org-other").Context(t), request(map[string]any{}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "organization token of org-acme")
+	})
+
+	t.Run("rejects an inverted time range", func(t *testing.T) {
+		_, err := getAuditTrailHandler(newFake(t, "org-acme").Context(t), request(map[string]any{"since": "2025-02-01", "until": "2025-01-01"}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "until must be after since")
+	})
+}
+
+func TestParseAuditTrailTime(t *testing.T) {
+	date, err := parseAuditTrailTime("2025-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), date)
+
+	timestamp, err := parseAuditTrailTime("2025-01-31T10:00:00+02:00")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 31, 8, 0, 0, 0, time.UTC), timestamp)
+
+	_, err = parseAuditTrailTime("last week")
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// OrgEntitlements is the result of the get_org_entitlements tool
+type OrgEntitlements struct {
+	Organization string          `json:"organization"`
+	Entitlements map[string]bool `json:"entitlements"`
+	Available    []string        `json:"available"`
+	Unavailable  []string        `json:"unavailable"`
+}
+
+// GetOrgEntitlements creates a tool to report the features included in the plan of a Terraform organization.
+func GetOrgEntitlements(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("get_org_entitlements",
+			mcp.WithDescription(`Reports which features the plan of a Terraform organization includes, e.g. Sentinel policy sets, agents, audit logging, run tasks, SSO and the private registry. Use it before recommending a feature the organization may not have.`),
+			mcp.WithTitleAnnotation("Get the entitlements of a Terraform organization"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return getOrgEntitlementsHandler(ctx, req, logger)
+		},
+	}
+}
+
+func getOrgEntitlementsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	entitlements, err := tfeClient.Organizations.ReadEntitlements(ctx, terraformOrgName)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "reading organization entitlements", err)
+	}
+
+	resultJSON, err := json.Marshal(summarizeEntitlements(terraformOrgName, entitlements))
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling organization entitlements", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// summarizeEntitlements lists the features of the organization by name, and which of them are available
+func summarizeEntitlements(organization string, entitlements *tfe.Entitlements) OrgEntitlements {
+	features := map[string]bool{
+		"agents":                        entitlements.Agents,
+		"audit_logging":                 entitlements.AuditLogging,
+		"cost_estimation":               entitlements.CostEstimation,
+		"global_run_tasks":              entitlements.GlobalRunTasks,
+		"operations":                    entitlements.Operations,
+		"private_module_registry":       entitlements.PrivateModuleRegistry,
+		"private_run_tasks":             entitlements.PrivateRunTasks,
+		"run_tasks":                     entitlements.RunTasks,
+		"sentinel":                      entitlements.Sentinel,
+		"sso":                           entitlements.SSO,
+		"state_storage":                 entitlements.StateStorage,
+		"teams":                         entitlements.Teams,
+		"vcs_integrations":              entitlements.VCSIntegrations,
+		"waypoint_actions":              entitlements.WaypointActions,
+		"waypoint_templates_and_addons": entitlements.WaypointTemplatesAndAddons,
+	}
+
+	result := OrgEntitlements{
+		Organization: organization,
+		Entitlements: features,
+		Available:    []string{},
+		Unavailable:  []string{},
+	}
+	for feature, enabled := range features {
+		if enabled {
+			result.Available = append(result.Available, feature)
+		} else {
+			result.Unavailable = append(result.Unavailable, feature)
+		}
+	}
+	sort.Strings(result.Available)
+	sort.Strings(result.Unavailable)
+	return result
+}
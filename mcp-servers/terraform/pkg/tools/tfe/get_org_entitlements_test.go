@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrgEntitlements(t *testing.T) {
+	tool := GetOrgEntitlements(log.New())
+	assert.Equal(t, "get_org_entitlements", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.Tool.InputSchema.Required, "terraform_org_name")
+}
+
+func TestSummarizeEntitlements(t *testing.T) {
+	summary := summarizeEntitlements("example", &tfe.Entitlements{
+		Agents:                true,
+		Sentinel:              true,
+		StateStorage:          true,
+		Operations:            true,
+		PrivateModuleRegistry: true,
+	})
+
+	assert.Equal(t, "example", summary.Organization)
+	assert.Len(t, summary.Entitlements, 15)
+	assert.True(t, summary.Entitlements["sentinel"])
+	assert.False(t, summary.Entitlements["audit_logging"])
+	assert.Equal(t, []string{"agents", "operations", "private_module_registry", "sentinel", "state_storage"}, summary.Available)
+	assert.Contains(t, summary.Unavailable, "audit_logging")
+	assert.Contains(t, summary.Unavailable, "sso")
+	assert.Len(t, summary.Unavailable, 10)
+}
@@ -47,6 +47,7 @@ The module-namespace is usually the name of the Terraform organization. Obtain t
 			mcp.WithString("private_module_version",
 				mcp.Description("Specific version of the module to retrieve details for. If not provided, the latest version will be used"),
 			),
+			client.WithSummarizeArgument(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return getPrivateModuleDetailsHandler(ctx, request, logger)
@@ -58,14 +59,14 @@ func getPrivateModuleDetailsHandler(ctx context.Context, request mcp.CallToolReq
 	// Get Terraform org name
 	terraformOrgName, err := request.RequireString("terraform_org_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
 	}
 	terraformOrgName = strings.TrimSpace(terraformOrgName)
 
 	// Get Terraform module id
 	moduleID, err := request.RequireString("private_module_id")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "private_module_id is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "private_module_id is required", err)
 	}
 	moduleID = strings.TrimSpace(moduleID)
 
@@ -77,13 +78,13 @@ func getPrivateModuleDetailsHandler(ctx context.Context, request mcp.CallToolReq
 	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
 	if err != nil {
 		err = utils.LogAndReturnError(logger, "failed to get terraform client for TFE, ensure TFE_TOKEN and TFE_ADDRESS are properly set.", err)
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get terraform client for TFE: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get terraform client for TFE: %v", err)), nil
 	}
 
 	// Split moduleID into org name, module name, and provider
 	parts := strings.Split(moduleID, "/")
 	if len(parts) != 3 {
-		return mcp.NewToolResultError("private_module_id must be in the format 'module-namespace/module-name/module-provider-name'"), nil
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, "private_module_id must be in the format 'module-namespace/module-name/module-provider-name'"), nil
 	}
 	// Create module ID for TFE API
 	tfeModuleID := tfe.RegistryModuleID{
@@ -108,7 +109,7 @@ func getPrivateModuleDetailsHandler(ctx context.Context, request mcp.CallToolReq
 	module, err = tfeClient.RegistryModules.Read(ctx, tfeModuleID)
 	if err != nil {
 		logger.WithError(err).Error("failed to read private module details")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to read private module details: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to read private module details: %v", err)), nil
 	}
 
 	// Get detailed module information from Terraform Registry (specific version or latest), it'll automatically use the latest version with empty string
@@ -116,6 +117,9 @@ func getPrivateModuleDetailsHandler(ctx context.Context, request mcp.CallToolReq
 	if err != nil {
 		logger.WithError(err).Warn("failed to get detailed module information from Terraform Registry, continuing with basic info")
 	}
+	if terraformRegistryModule != nil {
+		terraformRegistryModule.Root.Readme = client.SummarizeDocument(ctx, request, fmt.Sprintf("the README of module %s", moduleID), terraformRegistryModule.Root.Readme, logger)
+	}
 
 	return buildPrivateModuleDetailsResponse(module, terraformRegistryModule, tfeClient.BaseURL().Host, logger), nil
 }
@@ -60,21 +60,21 @@ func getPrivateProviderDetailsHandler(ctx context.Context, request mcp.CallToolR
 	// Get Terraform organization name
 	terraformOrgName, err := request.RequireString("terraform_org_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
 	}
 	terraformOrgName = strings.TrimSpace(terraformOrgName)
 
 	// Get Terraform provider namespace
 	privateProviderNamespace, err := request.RequireString("private_provider_namespace")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "private_provider_namespace is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "private_provider_namespace is required", err)
 	}
 	privateProviderNamespace = strings.TrimSpace(privateProviderNamespace)
 
 	// Get Terraform provider name
 	privateProviderName, err := request.RequireString("private_provider_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "private_provider_name is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "private_provider_name is required", err)
 	}
 	privateProviderName = strings.TrimSpace(privateProviderName)
 
@@ -86,7 +86,7 @@ func getPrivateProviderDetailsHandler(ctx context.Context, request mcp.CallToolR
 	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
 	if err != nil {
 		err = utils.LogAndReturnError(logger, "failed to get terraform client for TFE, ensure TFE_TOKEN and TFE_ADDRESS are properly set.", err)
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get terraform client for TFE: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get terraform client for TFE: %v", err)), nil
 	}
 
 	// Create provider ID
@@ -116,7 +116,7 @@ func getPrivateProviderDetailsHandler(ctx context.Context, request mcp.CallToolR
 	provider, err := tfeClient.RegistryProviders.Read(ctx, providerID, readOptions)
 	if err != nil {
 		logger.WithError(err).Error("failed to get private provider details")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get private provider details: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get private provider details: %v", err)), nil
 	}
 
 	// Build response
@@ -37,7 +37,7 @@ func GetRunDetails(logger *log.Logger) server.ServerTool {
 func getRunDetailsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	runID, err := request.RequireString("run_id")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'run_id' parameter is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'run_id' parameter is required", err)
 	}
 
 	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// Check statuses of the JSON plan of an assessment
+const (
+	checkStatusPass    = "pass"
+	checkStatusFail    = "fail"
+	checkStatusError   = "error"
+	checkStatusUnknown = "unknown"
+)
+
+// assessmentPlan holds the parts of the JSON plan of an assessment read by the assessment tools
+type assessmentPlan struct {
+	ResourceDrift []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_drift"`
+	Checks []struct {
+		Address struct {
+			Kind      string `json:"kind"`
+			ToDisplay string `json:"to_display"`
+		} `json:"address"`
+		Status    string `json:"status"`
+		Instances []struct {
+			Address struct {
+				ToDisplay string `json:"to_display"`
+			} `json:"address"`
+			Status   string `json:"status"`
+			Problems []struct {
+				Message string `json:"message"`
+			} `json:"problems"`
+		} `json:"instances"`
+	} `json:"checks"`
+}
+
+// DriftedResource is a resource whose real infrastructure no longer matches the state
+type DriftedResource struct {
+	Address string   `json:"address"`
+	Actions []string `json:"actions"`
+}
+
+// CheckCounts counts the checks of an assessment by status
+type CheckCounts struct {
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Errored int `json:"errored"`
+	Unknown int `json:"unknown"`
+}
+
+// WorkspaceAssessment is the result of the get_workspace_assessment tool
+type WorkspaceAssessment struct {
+	WorkspaceID      string            `json:"workspace_id"`
+	WorkspaceName    string            `json:"workspace_name"`
+	AssessmentID     string            `json:"assessment_id"`
+	CreatedAt        time.Time         `json:"created_at"`
+	Succeeded        bool              `json:"succeeded"`
+	Drifted          bool              `json:"drifted"`
+	ErrorMessage     string            `json:"error_message,omitempty"`
+	DriftedResources []DriftedResource `json:"drifted_resources"`
+	Checks           CheckCounts       `json:"checks"`
+}
+
+// GetWorkspaceAssessment creates a tool to get the latest health assessment of a workspace.
+func GetWorkspaceAssessment(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("get_workspace_assessment",
+			mcp.WithDescription(`Gets the latest health assessment of a workspace: whether it succeeded, whether the infrastructure drifted from the state, the drifted resources, and the number of continuous validation checks (check blocks, preconditions and postconditions) passing, failing, errored or unknown.
+Use list_workspace_check_results for the failing checks and their messages. Health assessments must be enabled on the workspace; reading the drifted resources and checks needs admin access to it.`),
+			mcp.WithTitleAnnotation("Get the latest health assessment of a Terraform workspace"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			WithWorkspace("The name of the workspace"),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return getWorkspaceAssessmentHandler(ctx, request, logger)
+		},
+	}
+}
+
+func getWorkspaceAssessmentHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+	assessment, plan, err := readAssessment(ctx, tfeClient, workspace, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	result := WorkspaceAssessment{
+		WorkspaceID:      workspace.ID,
+		WorkspaceName:    workspace.Name,
+		AssessmentID:     assessment.ID,
+		CreatedAt:        assessment.CreatedAt,
+		Succeeded:        assessment.Succeeded,
+		Drifted:          assessment.Drifted,
+		ErrorMessage:     assessment.ErrorMsg,
+		DriftedResources: []DriftedResource{},
+	}
+	if plan != nil {
+		result.DriftedResources = driftedResources(plan)
+		result.Checks = countChecks(plan)
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling assessment", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// readAssessment reads the latest assessment of a workspace and its JSON plan. The plan is nil when the
+// assessment failed before planning.
+func readAssessment(ctx context.Context, tfeClient *tfe.Client, workspace *tfe.Workspace, logger *log.Logger) (*assessmentResult, *assessmentPlan, error) {
+	req, err := tfeClient.NewRequest("GET", fmt.Sprintf("workspaces/%s/current-assessment-result", url.PathEscape(workspace.ID)), nil)
+	if err != nil {
+		return nil, nil, utils.LogAndReturnError(logger, "creating assessment request", err)
+	}
+	assessment := &assessmentResult{}
+	if err := req.Do(ctx, assessment); err != nil {
+		if errors.Is(err, tfe.ErrResourceNotFound) {
+			return nil, nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound,
+				fmt.Sprintf("workspace %s has no assessment: health assessments are disabled or none has run yet", workspace.Name), err)
+		}
+		return nil, nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading the assessment of workspace %s", workspace.Name), err)
+	}
+	if !assessment.Succeeded {
+		return assessment, nil, nil
+	}
+
+	req, err = tfeClient.NewRequest("GET", fmt.Sprintf("assessment-results/%s/json-output", url.PathEscape(assessment.ID)), nil)
+	if err != nil {
+		return nil, nil, utils.LogAndReturnError(logger, "creating assessment output request", err)
+	}
+	plan := &assessmentPlan{}
+	if err := req.DoJSON(ctx, plan); err != nil {
+		return nil, nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading the JSON plan of assessment %s", assessment.ID), err)
+	}
+	return assessment, plan, nil
+}
+
+// driftedResources lists the resources of the drift of a plan, by address
+func driftedResources(plan *assessmentPlan) []DriftedResource {
+	resources := []DriftedResource{}
+	for _, drift := range plan.ResourceDrift {
+		resources = append(resources, DriftedResource{Address: drift.Address, Actions: drift.Change.Actions})
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Address < resources[j].Address })
+	return resources
+}
+
+// countChecks counts the checks of a plan by status
+func countChecks(plan *assessmentPlan) CheckCounts {
+	var counts CheckCounts
+	for _, check := range plan.Checks {
+		switch check.Status {
+		case checkStatusPass:
+			counts.Passed++
+		case checkStatusFail:
+			counts.Failed++
+		case checkStatusError:
+			counts.Errored++
+		default:
+			counts.Unknown++
+		}
+	}
+	return counts
+}
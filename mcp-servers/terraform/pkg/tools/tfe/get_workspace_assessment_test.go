@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assessmentPlanFixture is the JSON plan of an assessment with a drifted resource and a failing check
+const assessmentPlanFixture = `{
+	"format_version": "1.2",
+	"resource_drift": [
+		{"address": "aws_security_group.web", "mode": "managed", "type": "aws_security_group", "change": {"actions": ["update"]}}
+	],
+	"checks": [
+		{"address": {"kind": "check", "name": "health", "to_display": "check.health"}, "status": "fail",
+		 "instances": [{"address": {"to_display": "check.health"}, "status": "fail", "problems": [{"message": "https://app.example.com returned 503"}]}]},
+		{"address": {"kind": "resource", "to_display": "aws_instance.web"}, "status": "pass",
+		 "instances": [{"address": {"to_display": "aws_instance.web"}, "status": "pass"}]},
+		{"address": {"kind": "output_value", "to_display": "output.url"}, "status": "unknown"}
+	]
+}`
+
+func TestWorkspaceAssessmentTools(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	get := GetWorkspaceAssessment(logger)
+	assert.Equal(t, "get_workspace_assessment", get.Tool.Name)
+	assert.True(t, *get.Tool.Annotations.ReadOnlyHint)
+	list := ListWorkspaceCheckResults(logger)
+	assert.Equal(t, "list_workspace_check_results", list.Tool.Name)
+	assert.Contains(t, list.Tool.InputSchema.Properties, "failing_only")
+
+	request := func(name string, arguments map[string]any) mcp.CallToolRequest {
+		arguments["workspace_id"] = "ws-123"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: name, Arguments: arguments}}
+	}
+	newFake := func(t *testing.T, assessment *assessmentResult) *testutil.FakeTFE {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/workspaces/ws-123", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "web", Organization: &tfe.Organization{Name: "acme"}})
+		if assessment != nil {
+			fake.Respond("GET", "/workspaces/ws-123/current-assessment-result", http.StatusOK, assessment)
+		}
+		fake.Handle("GET", "/assessment-results/asmtres-123/json-output", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(assessmentPlanFixture))
+		})
+		return fake
+	}
+	createdAt := time.Date(2025, 2, 1, 6, 0, 0, 0, time.UTC)
+	succeeded := &assessmentResult{ID: "asmtres-123", Drifted: true, Succeeded: true, CreatedAt: createdAt}
+
+	t.Run("summarizes drift and checks", func(t *testing.T) {
+		result, err := getWorkspaceAssessmentHandler(newFake(t, succeeded).Context(t), request("get_workspace_assessment", map[string]any{}), logger)
+		require.NoError(t, err)
+
+		var assessment WorkspaceAssessment
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &assessment))
+		assert.True(t, assessment.Drifted)
+		assert.Equal(t, createdAt, assessment.CreatedAt)
+		assert.Equal(t, []DriftedResource{{Address: "aws_security_group.web", Actions: []string{"update"}}}, assessment.DriftedResources)
+		assert.Equal(t, CheckCounts{Passed: 1, Failed: 1, Unknown: 1}, assessment.Checks)
+	})
+
+	t.Run("reports a failed assessment without its plan", func(t *testing.T) {
+		failed := &assessmentResult{ID: "asmtres-456", ErrorMsg: "provider credentials expired", CreatedAt: createdAt}
+		fake := newFake(t, failed)
+		result, err := getWorkspaceAssessmentHandler(fake.Context(t), request("get_workspace_assessment", map[string]any{}), logger)
+		require.NoError(t, err)
+
+		var assessment WorkspaceAssessment
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &assessment))
+		assert.False(t, assessment.Succeeded)
+		assert.Equal(t, "provider credentials expired", assessment.ErrorMessage)
+		assert.Len(t, fake.Requests(), 2)
+
+		_, err = listWorkspaceCheckResultsHandler(fake.Context(t), request("list_workspace_check_results", map[string]any{}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provider credentials expired")
+	})
+
+	t.Run("reports workspaces without an assessment", func(t *testing.T) {
+		_, err := getWorkspaceAssessmentHandler(newFake(t, nil).Context(t), request("get_workspace_assessment", map[string]any{}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has no assessment")
+	})
+
+	t.Run("lists the failing checks first", func(t *testing.T) {
+		fake := newFake(t, succeeded)
+		result, err := listWorkspaceCheckResultsHandler(fake.Context(t), request("list_workspace_check_results", map[string]any{}), logger)
+		require.NoError(t, err)
+
+		var checks WorkspaceCheckResults
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &checks))
+		require.Len(t, checks.Checks, 2)
+		assert.Equal(t, CheckResult{
+			Address:  "check.health",
+			Kind:     "check",
+			Status:   "fail",
+			Problems: []CheckProblem{{Instance: "check.health", Message: "https://app.example.com returned 503"}},
+		}, checks.Checks[0])
+		assert.Equal(t, "output.url", checks.Checks[1].Address)
+
+		result, err = listWorkspaceCheckResultsHandler(fake.Context(t), request("list_workspace_check_results", map[string]any{"failing_only": false}), logger)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &checks))
+		assert.Len(t, checks.Checks, 3)
+		assert.Equal(t, "pass", checks.Checks[2].Status)
+	})
+}
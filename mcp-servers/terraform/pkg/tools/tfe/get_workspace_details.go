@@ -32,14 +32,7 @@ func GetWorkspaceDetails(logger *log.Logger) server.ServerTool {
 			mcp.WithOpenWorldHintAnnotation(true),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
-			mcp.WithString("terraform_org_name",
-				mcp.Required(),
-				mcp.Description("The Terraform Cloud/Enterprise organization name"),
-			),
-			mcp.WithString("workspace_name",
-				mcp.Required(),
-				mcp.Description("The name of the workspace to get details for"),
-			),
+			WithWorkspace("The name of the workspace to get details for"),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return getWorkspaceDetailsHandler(ctx, request, logger)
@@ -48,18 +41,9 @@ func GetWorkspaceDetails(logger *log.Logger) server.ServerTool {
 }
 
 func getWorkspaceDetailsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
-	// Get required parameters
-	terraformOrgName, err := request.RequireString("terraform_org_name")
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required", err)
-	}
-	terraformOrgName = strings.TrimSpace(terraformOrgName)
-
-	workspaceName, err := request.RequireString("workspace_name")
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'workspace_name' parameter is required", err)
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
 	}
-	workspaceName = strings.TrimSpace(workspaceName)
 
 	// Get a Terraform client from context
 	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
@@ -67,9 +51,9 @@ func getWorkspaceDetailsHandler(ctx context.Context, request mcp.CallToolRequest
 		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
 	}
 
-	workspace, err := tfeClient.Workspaces.Read(ctx, terraformOrgName, workspaceName)
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "reading workspace details", err)
+		return nil, err
 	}
 
 	buf, err := getWorkspaceDetailsForTools(ctx, "get_workspace_details", tfeClient, workspace, logger, true)
@@ -30,9 +30,9 @@ func TestGetWorkspaceDetails(t *testing.T) {
 		assert.NotNil(t, tool.Tool.Annotations.DestructiveHint)
 		assert.False(t, *tool.Tool.Annotations.DestructiveHint)
 		
-		// Check that required parameters are defined
-		assert.Contains(t, tool.Tool.InputSchema.Required, "terraform_org_name")
-		assert.Contains(t, tool.Tool.InputSchema.Required, "workspace_name")
+		// The workspace is addressed by organization and name, or by ID
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "terraform_org_name")
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "workspace_id")
 	})
 
 	t.Run("parameter validation", func(t *testing.T) {
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+)
+
+// GuardrailWorkspaces returns the workspaces targeted by a tool call, for the guardrail policy. The
+// workspace is found from the workspace_id, run_id or terraform_org_name and workspace_name arguments,
+// and the workspaces of bulk tools from their workspace_names and workspace filters. A workspace about
+// to be created is returned with the tags it is created with.
+func GuardrailWorkspaces(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) ([]client.GuardrailWorkspace, error) {
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceID := strings.TrimSpace(request.GetString("workspace_id", ""))
+	runID := strings.TrimSpace(request.GetString("run_id", ""))
+	organization := strings.TrimSpace(request.GetString("terraform_org_name", ""))
+	workspaceName := strings.TrimSpace(request.GetString("workspace_name", ""))
+	namePattern := strings.TrimSpace(request.GetString("workspace_name_pattern", ""))
+	search := strings.TrimSpace(request.GetString("workspace_search", ""))
+	tags := strings.TrimSpace(request.GetString("workspace_tags", ""))
+	names := parseTagNames(request.GetString("workspace_names", ""))
+
+	switch {
+	case workspaceID != "":
+		workspace, err := tfeClient.Workspaces.ReadByID(ctx, workspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("reading workspace %s: %w", workspaceID, err)
+		}
+		return guardrailWorkspaces(workspace), nil
+
+	case runID != "":
+		run, err := tfeClient.Runs.ReadWithOptions(ctx, runID, &tfe.RunReadOptions{Include: []tfe.RunIncludeOpt{tfe.RunWorkspace}})
+		if err != nil {
+			return nil, fmt.Errorf("reading run %s: %w", runID, err)
+		}
+		if run.Workspace == nil {
+			return nil, fmt.Errorf("run %s has no workspace", runID)
+		}
+		return guardrailWorkspaces(run.Workspace), nil
+
+	case organization != "" && workspaceName != "":
+		workspace, err := tfeClient.Workspaces.Read(ctx, organization, workspaceName)
+		if errors.Is(err, tfe.ErrResourceNotFound) && request.Params.Name == "create_workspace" {
+			return []client.GuardrailWorkspace{{Name: workspaceName, Tags: parseTagNames(request.GetString("tags", ""))}}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading workspace %s/%s: %w", organization, workspaceName, err)
+		}
+		return guardrailWorkspaces(workspace), nil
+
+	case organization != "" && (len(names) > 0 || namePattern != "" || search != "" || tags != ""):
+		workspaces, err := readWorkspacesNamed(ctx, tfeClient, organization, names)
+		if err != nil {
+			return nil, err
+		}
+		if namePattern != "" || search != "" || tags != "" {
+			matched, err := matchWorkspaces(ctx, tfeClient, organization, tags, namePattern, search)
+			if err != nil {
+				return nil, fmt.Errorf("listing matching workspaces: %w", err)
+			}
+			workspaces = append(workspaces, matched...)
+		}
+		return guardrailWorkspaces(workspaces...), nil
+	}
+	return nil, fmt.Errorf("the call does not name a workspace")
+}
+
+func guardrailWorkspaces(workspaces ...*tfe.Workspace) []client.GuardrailWorkspace {
+	result := make([]client.GuardrailWorkspace, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		result = append(result, client.GuardrailWorkspace{Name: workspace.Name, Tags: workspace.TagNames})
+	}
+	return result
+}
@@ -0,0 +1,372 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// maxImportedVariables is the number of variables import_workspace_variables upserts at once
+const maxImportedVariables = 200
+
+// ImportedVariable is the outcome of importing one variable: created, updated, unchanged, skipped or failed
+type ImportedVariable struct {
+	Key      string `json:"key"`
+	Category string `json:"category"`
+	Action   string `json:"action"`
+	Reason   string `json:"reason,omitempty"`
+	Error    string `json:"error,omitempty"`
+
+	// variable is the imported variable and existing the variable of the workspace it updates
+	variable WorkspaceVariable
+	existing *tfe.Variable
+}
+
+// ImportVariablesResult is the result of the import_workspace_variables tool
+type ImportVariablesResult struct {
+	Organization string             `json:"organization"`
+	Workspace    string             `json:"workspace"`
+	Total        int                `json:"total"`
+	Created      int                `json:"created"`
+	Updated      int                `json:"updated"`
+	Unchanged    int                `json:"unchanged"`
+	Skipped      int                `json:"skipped"`
+	Failed       int                `json:"failed"`
+	Variables    []ImportedVariable `json:"variables"`
+}
+
+// ImportWorkspaceVariables creates a tool to create or update many variables of a workspace at once.
+func ImportWorkspaceVariables(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("import_workspace_variables",
+			mcp.WithDescription(fmt.Sprintf(`Creates or updates the variables of a workspace from the output of export_workspace_variables, e.g. to clone a workspace or promote its configuration to another environment. At most %d variables can be imported at once.
+Variables are matched by key and category: missing variables are created, variables with a different value, HCL flag, sensitive flag or description are updated, and the others are left unchanged. Variables of the workspace that are not imported are kept.
+Sensitive variables exported without their value are skipped. Overwriting existing variables must be confirmed: the first call returns the changes and a confirmation token, and the variables are only changed when the tool is called again with the token.`, maxImportedVariables)),
+			mcp.WithTitleAnnotation("Create or update the variables of a Terraform workspace"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			WithWorkspace("The name of the workspace to import the variables into"),
+			mcp.WithString("variables",
+				mcp.Required(),
+				mcp.Description("The variables to import: the JSON output of export_workspace_variables or a JSON list of its variables, or the content of a tfvars file"),
+			),
+			mcp.WithString("format",
+				mcp.Description("The format of 'variables': 'json', or 'tfvars' to import Terraform variables"),
+				mcp.Enum(variablesFormatJSON, variablesFormatTfvars),
+				mcp.DefaultString(variablesFormatJSON),
+			),
+			mcp.WithString("sensitive_keys",
+				mcp.Description("Optional comma-separated list of keys to import as sensitive variables, in addition to the variables marked sensitive in the JSON"),
+			),
+			withConfirmationToken(),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return importWorkspaceVariablesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func importWorkspaceVariablesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+
+	source, err := request.RequireString("variables")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'variables' parameter is required", err)
+	}
+
+	var variables []WorkspaceVariable
+	switch format := strings.ToLower(strings.TrimSpace(request.GetString("format", variablesFormatJSON))); format {
+	case variablesFormatJSON:
+		variables, err = parseVariablesJSON(source)
+	case variablesFormatTfvars:
+		variables, err = parseTfvars(source)
+	default:
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid format: must be 'json' or 'tfvars'", nil)
+	}
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing variables", err)
+	}
+	if len(variables) == 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing variables", errors.New("no variables to import"))
+	}
+	if len(variables) > maxImportedVariables {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "parsing variables", fmt.Errorf("at most %d variables can be imported at once", maxImportedVariables))
+	}
+	for _, key := range parseTagNames(request.GetString("sensitive_keys", "")) {
+		for i := range variables {
+			if variables[i].Key == key {
+				variables[i].Sensitive = true
+			}
+		}
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+	terraformOrgName := workspace.Organization.Name
+	existing, err := listWorkspaceVariables(ctx, tfeClient, workspace.ID)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing workspace variables", err)
+	}
+	changes := plannedVariableChanges(existing, variables)
+
+	if request.GetBool(dryRunParam, false) {
+		result := BulkDryRunResult{DryRun: true, Requests: []DryRunResult{}}
+		for _, change := range changes {
+			if change.variable.Sensitive && change.variable.Value != nil {
+				change.variable.Value = tfe.String("(sensitive)")
+			}
+			method, path, payload := variableRequest(workspace.ID, change)
+			if method == "" {
+				continue
+			}
+			dryRun, err := newDryRun(request, method, path, payload, []string{fmt.Sprintf("%s %s variable %s of workspace %s", variableActionVerb(change.Action), change.Category, change.Key, workspace.Name)})
+			if err != nil {
+				return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+			}
+			result.Requests = append(result.Requests, dryRun)
+		}
+		result.Total = len(result.Requests)
+		return bulkResult(result, logger)
+	}
+
+	var overwritten []string
+	for _, change := range changes {
+		if change.Action == "updated" {
+			overwritten = append(overwritten, change.Key)
+		}
+	}
+	if len(overwritten) > 0 {
+		details := map[string]any{
+			"organization": terraformOrgName,
+			"workspace":    workspace.Name,
+			"changes":      changes,
+		}
+		summary := fmt.Sprintf("overwrite %d existing variables of workspace %s/%s: %s", len(overwritten), terraformOrgName, workspace.Name, strings.Join(overwritten, ", "))
+		if result, err := requireConfirmation(ctx, request, summary, details, logger); result != nil || err != nil {
+			return result, err
+		}
+	}
+
+	applyVariableChanges(ctx, tfeClient, workspace.ID, changes, logger)
+	return bulkResult(summarizeImportedVariables(terraformOrgName, workspace.Name, changes), logger)
+}
+
+// parseVariablesJSON reads the output of export_workspace_variables, or the list of its variables
+func parseVariablesJSON(source string) ([]WorkspaceVariable, error) {
+	source = strings.TrimSpace(source)
+	var variables []WorkspaceVariable
+	if strings.HasPrefix(source, "[") {
+		if err := json.Unmarshal([]byte(source), &variables); err != nil {
+			return nil, err
+		}
+	} else {
+		var export WorkspaceVariablesExport
+		if err := json.Unmarshal([]byte(source), &export); err != nil {
+			return nil, err
+		}
+		variables = export.Variables
+	}
+
+	seen := make(map[string]bool, len(variables))
+	for i, variable := range variables {
+		variable.Key = strings.TrimSpace(variable.Key)
+		if variable.Key == "" {
+			return nil, fmt.Errorf("variable %d has no key", i)
+		}
+		switch variable.Category {
+		case "":
+			variable.Category = string(tfe.CategoryTerraform)
+		case string(tfe.CategoryTerraform), string(tfe.CategoryEnv):
+		default:
+			return nil, fmt.Errorf("variable %s has category %q, must be 'terraform' or 'env'", variable.Key, variable.Category)
+		}
+		if variable.HCL && variable.Category == string(tfe.CategoryEnv) {
+			return nil, fmt.Errorf("environment variable %s cannot be HCL", variable.Key)
+		}
+		if seen[variable.Category+"/"+variable.Key] {
+			return nil, fmt.Errorf("%s variable %s is listed more than once", variable.Category, variable.Key)
+		}
+		seen[variable.Category+"/"+variable.Key] = true
+		variables[i] = variable
+	}
+	return variables, nil
+}
+
+// parseTfvars reads the Terraform variables of a tfvars file. String literals are imported as strings,
+// and the other values as HCL with their source text.
+func parseTfvars(source string) ([]WorkspaceVariable, error) {
+	file, diags := hclsyntax.ParseConfig([]byte(source), "terraform.tfvars", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+	attributes, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+
+	variables := make([]WorkspaceVariable, 0, len(attributes))
+	for name, attribute := range attributes {
+		variable := WorkspaceVariable{Key: name, Category: string(tfe.CategoryTerraform)}
+		value, diags := attribute.Expr.Value(nil)
+		if !diags.HasErrors() && value.IsKnown() && !value.IsNull() && value.Type() == cty.String {
+			text := value.AsString()
+			variable.Value = &text
+		} else {
+			text := string(attribute.Expr.Range().SliceBytes(file.Bytes))
+			variable.Value = &text
+			variable.HCL = true
+		}
+		variables = append(variables, variable)
+	}
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Key < variables[j].Key })
+	return variables, nil
+}
+
+// plannedVariableChanges matches the imported variables with the variables of the workspace by key and category
+func plannedVariableChanges(existing []*tfe.Variable, variables []WorkspaceVariable) []ImportedVariable {
+	byKey := make(map[string]*tfe.Variable, len(existing))
+	for _, variable := range existing {
+		byKey[string(variable.Category)+"/"+variable.Key] = variable
+	}
+
+	changes := make([]ImportedVariable, 0, len(variables))
+	for _, variable := range variables {
+		change := ImportedVariable{Key: variable.Key, Category: variable.Category, variable: variable, existing: byKey[variable.Category+"/"+variable.Key]}
+		switch {
+		case variable.Value == nil:
+			change.Action = "skipped"
+			change.Reason = "sensitive value not exported"
+		case change.existing == nil:
+			change.Action = "created"
+		case change.existing.Sensitive && !variable.Sensitive:
+			change.Action = "skipped"
+			change.Reason = "the existing variable is sensitive, it cannot be made non-sensitive"
+		case change.existing.Sensitive:
+			// Sensitive values cannot be read back, so they are always overwritten
+			change.Action = "updated"
+		case change.existing.Value != *variable.Value || change.existing.HCL != variable.HCL || variable.Sensitive ||
+			(variable.Description != "" && change.existing.Description != variable.Description):
+			change.Action = "updated"
+		default:
+			change.Action = "unchanged"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// variableRequest returns the API request creating or updating the variable of a change, or an empty
+// method when the change needs no request
+func variableRequest(workspaceID string, change ImportedVariable) (string, string, any) {
+	variable := change.variable
+	category := tfe.CategoryType(variable.Category)
+	var description *string
+	if variable.Description != "" {
+		description = tfe.String(variable.Description)
+	}
+	var sensitive *bool
+	if variable.Sensitive {
+		sensitive = tfe.Bool(true)
+	}
+
+	switch change.Action {
+	case "created":
+		return "POST", fmt.Sprintf("workspaces/%s/vars", url.PathEscape(workspaceID)), &tfe.VariableCreateOptions{
+			Key:         tfe.String(variable.Key),
+			Value:       variable.Value,
+			Description: description,
+			Category:    &category,
+			HCL:         tfe.Bool(variable.HCL),
+			Sensitive:   sensitive,
+		}
+	case "updated":
+		return "PATCH", fmt.Sprintf("workspaces/%s/vars/%s", url.PathEscape(workspaceID), url.PathEscape(change.existing.ID)), &tfe.VariableUpdateOptions{
+			Value:       variable.Value,
+			Description: description,
+			HCL:         tfe.Bool(variable.HCL),
+			Sensitive:   sensitive,
+		}
+	}
+	return "", "", nil
+}
+
+func variableActionVerb(action string) string {
+	if action == "created" {
+		return "Creates"
+	}
+	return "Updates"
+}
+
+// applyVariableChanges creates and updates the variables one at a time, recording the error of the
+// variables that could not be changed
+func applyVariableChanges(ctx context.Context, tfeClient *tfe.Client, workspaceID string, changes []ImportedVariable, logger *log.Logger) {
+	for i := range changes {
+		change := &changes[i]
+		method, _, payload := variableRequest(workspaceID, *change)
+		if method == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			change.Error = err.Error()
+			continue
+		}
+
+		var err error
+		if method == "POST" {
+			_, err = tfeClient.Variables.Create(ctx, workspaceID, *payload.(*tfe.VariableCreateOptions))
+		} else {
+			_, err = tfeClient.Variables.Update(ctx, workspaceID, change.existing.ID, *payload.(*tfe.VariableUpdateOptions))
+		}
+		if err != nil {
+			logger.WithField("variable", change.Key).Warnf("Failed to import variable: %v", err)
+			change.Error = err.Error()
+		}
+	}
+}
+
+func summarizeImportedVariables(organization, workspace string, changes []ImportedVariable) ImportVariablesResult {
+	result := ImportVariablesResult{Organization: organization, Workspace: workspace, Total: len(changes), Variables: changes}
+	for _, change := range changes {
+		switch {
+		case change.Error != "":
+			result.Failed++
+		case change.Action == "created":
+			result.Created++
+		case change.Action == "updated":
+			result.Updated++
+		case change.Action == "skipped":
+			result.Skipped++
+		default:
+			result.Unchanged++
+		}
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportedVariables(t *testing.T) {
+	variables, err := parseTfvars(`
+region = "eu-west-1"
+tags = {
+  team = "platform"
+}
+count = 3
+`)
+	require.NoError(t, err)
+	require.Len(t, variables, 3)
+	assert.Equal(t, WorkspaceVariable{Key: "count", Value: tfe.String("3"), Category: "terraform", HCL: true}, variables[0])
+	assert.Equal(t, WorkspaceVariable{Key: "region", Value: tfe.String("eu-west-1"), Category: "terraform"}, variables[1])
+	assert.Equal(t, "{\n  team = \"platform\"\n}", *variables[2].Value)
+	assert.True(t, variables[2].HCL)
+
+	_, err = parseTfvars(`variable "region" {}`)
+	assert.Error(t, err)
+
+	// The output of export_workspace_variables and the bare list of its variables are both accepted
+	variables, err = parseVariablesJSON(`{"organization": "acme", "workspace": "staging", "variables": [{"key": "region", "value": "eu-west-1"}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, []WorkspaceVariable{{Key: "region", Value: tfe.String("eu-west-1"), Category: "terraform"}}, variables)
+	variables, err = parseVariablesJSON(`[{"key": "TOKEN", "category": "env", "sensitive": true}]`)
+	require.NoError(t, err)
+	assert.Nil(t, variables[0].Value)
+
+	for _, invalid := range []string{
+		`[{"key": ""}]`,
+		`[{"key": "region", "category": "policy-set"}]`,
+		`[{"key": "PATH", "category": "env", "hcl": true}]`,
+		`[{"key": "region"}, {"key": "region", "category": "terraform"}]`,
+		`region = "eu-west-1"`,
+	} {
+		_, err := parseVariablesJSON(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPlannedVariableChanges(t *testing.T) {
+	changes := plannedVariableChanges(workspaceVariablesFixture(), []WorkspaceVariable{
+		{Key: "region", Value: tfe.String("eu-west-1"), Category: "terraform"},
+		{Key: "AWS_DEFAULT_REGION", Value: tfe.String("us-east-1"), Category: "env"},
+		{Key: "db_password", Category: "terraform", Sensitive: true},
+		{Key: "tags", Value: tfe.String("{}"), Category: "terraform", HCL: true},
+		{Key: "instance_type", Value: tfe.String("t3.micro"), Category: "terraform"},
+		// A Terraform variable does not match the environment variable of the same key
+		{Key: "region", Value: tfe.String("eu-west-1"), Category: "env"},
+	})
+
+	actions := []string{}
+	for _, change := range changes {
+		actions = append(actions, change.Key+"="+change.Action)
+	}
+	assert.Equal(t, []string{"region=unchanged", "AWS_DEFAULT_REGION=updated", "db_password=skipped", "tags=updated", "instance_type=created", "region=created"}, actions)
+	assert.Equal(t, "sensitive value not exported", changes[2].Reason)
+
+	summary := summarizeImportedVariables("acme", "staging", changes)
+	assert.Equal(t, ImportVariablesResult{Organization: "acme", Workspace: "staging", Total: 6, Created: 2, Updated: 2, Unchanged: 1, Skipped: 1, Variables: changes}, summary)
+}
+
+func TestImportWorkspaceVariablesHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := ImportWorkspaceVariables(logger)
+	assert.Equal(t, "import_workspace_variables", tool.Tool.Name)
+	assert.False(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.Tool.InputSchema.Required, "variables")
+	assert.Contains(t, tool.Tool.InputSchema.Properties, confirmationTokenParam)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, dryRunParam)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		arguments["terraform_org_name"] = "acme"
+		arguments["workspace_name"] = "production"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "import_workspace_variables", Arguments: arguments}}
+	}
+	newFake := func(t *testing.T) *testutil.FakeTFE {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/organizations/acme/workspaces/production", http.StatusOK, &tfe.Workspace{ID: "ws-456", Name: "production"})
+		fake.RespondList("GET", "/workspaces/ws-456/vars", workspaceVariablesFixture(), &tfe.Pagination{CurrentPage: 1, TotalPages: 1})
+		fake.Respond("POST", "/workspaces/ws-456/vars", http.StatusCreated, &tfe.Variable{ID: "var-9", Key: "instance_type"})
+		fake.Respond("PATCH", "/workspaces/ws-456/vars/var-1", http.StatusOK, &tfe.Variable{ID: "var-1", Key: "region"})
+		return fake
+	}
+
+	t.Run("creates missing variables without a confirmation", func(t *testing.T) {
+		fake := newFake(t)
+		result, err := importWorkspaceVariablesHandler(fake.Context(t), request(map[string]any{
+			"variables":      `instance_type = "t3.micro"` + "\n" + `region = "eu-west-1"`,
+			"format":         "tfvars",
+			"sensitive_keys": "instance_type",
+		}), logger)
+		require.NoError(t, err)
+
+		var imported ImportVariablesResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &imported))
+		assert.Equal(t, 1, imported.Created)
+		assert.Equal(t, 1, imported.Unchanged)
+
+		requests := fake.Requests()
+		require.Len(t, requests, 3)
+		assert.Equal(t, "POST", requests[2].Method)
+		assert.JSONEq(t, `{"data": {"type": "vars", "attributes": {"key": "instance_type", "value": "t3.micro", "category": "terraform", "hcl": false, "sensitive": true}}}`, string(requests[2].Body))
+	})
+
+	t.Run("overwriting variables needs a confirmation", func(t *testing.T) {
+		fake := newFake(t)
+		arguments := map[string]any{"variables": `[{"key": "region", "value": "us-east-1"}]`}
+		result, err := importWorkspaceVariablesHandler(fake.Context(t), request(arguments), logger)
+		require.NoError(t, err)
+
+		var confirmation ConfirmationRequired
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &confirmation))
+		assert.Equal(t, "overwrite 1 existing variables of workspace acme/production: region", confirmation.Summary)
+		assert.Len(t, fake.Requests(), 2)
+
+		arguments[confirmationTokenParam] = confirmation.ConfirmationToken
+		result, err = importWorkspaceVariablesHandler(fake.Context(t), request(arguments), logger)
+		require.NoError(t, err)
+		var imported ImportVariablesResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &imported))
+		assert.Equal(t, 1, imported.Updated)
+		requests := fake.Requests()
+		assert.Equal(t, "PATCH", requests[len(requests)-1].Method)
+	})
+
+	t.Run("dry run masks sensitive values", func(t *testing.T) {
+		fake := newFake(t)
+		result, err := importWorkspaceVariablesHandler(fake.Context(t), request(map[string]any{
+			"variables": `[{"key": "api_key", "value": "secret-value", "sensitive": true}]`,
+			dryRunParam: true,
+		}), logger)
+		require.NoError(t, err)
+
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.NotContains(t, text, "secret-value")
+		assert.Contains(t, text, "Creates terraform variable api_key of workspace production")
+		assert.Len(t, fake.Requests(), 2)
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// GPGKeySummary describes a GPG key of the private registry. KeyID is the ID provider versions are signed with.
+type GPGKeySummary struct {
+	ID             string    `json:"id"`
+	KeyID          string    `json:"key_id"`
+	Namespace      string    `json:"namespace"`
+	Source         string    `json:"source,omitempty"`
+	TrustSignature string    `json:"trust_signature,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// GPGKeyListResult is the result of the list_gpg_keys tool
+type GPGKeyListResult struct {
+	Organization string          `json:"organization"`
+	GPGKeys      []GPGKeySummary `json:"gpg_keys"`
+	Pagination   *tfe.Pagination `json:"pagination,omitempty"`
+}
+
+// ListGPGKeys creates a tool to list the GPG keys private providers of an organization can be signed with.
+func ListGPGKeys(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_gpg_keys",
+			mcp.WithDescription(`Lists the GPG keys of the private registry of a Terraform Cloud/Enterprise organization. The key_id of a key is the one to sign the SHA256SUMS file of a private provider version with, and to pass to add_provider_platform when it creates the version.`),
+			mcp.WithTitleAnnotation("List the GPG keys of a private registry"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name, which is the namespace of its GPG keys"),
+			),
+			utils.WithPagination(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listGPGKeysHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listGPGKeysHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	gpgKeys, err := tfeClient.GPGKeys.ListPrivate(ctx, tfe.GPGKeyListOptions{
+		ListOptions: tfe.ListOptions{
+			PageNumber: pagination.Page,
+			PageSize:   pagination.PageSize,
+		},
+		Namespaces: []string{terraformOrgName},
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing GPG keys", err)
+	}
+
+	result := GPGKeyListResult{
+		Organization: terraformOrgName,
+		GPGKeys:      make([]GPGKeySummary, 0, len(gpgKeys.Items)),
+		Pagination:   gpgKeys.Pagination,
+	}
+	for _, gpgKey := range gpgKeys.Items {
+		result.GPGKeys = append(result.GPGKeys, summarizeGPGKey(gpgKey))
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling GPG keys", err)
+	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(resultJSON)), pagination, nextPage(gpgKeys.Pagination)), nil
+}
+
+func summarizeGPGKey(gpgKey *tfe.GPGKey) GPGKeySummary {
+	return GPGKeySummary{
+		ID:             gpgKey.ID,
+		KeyID:          gpgKey.KeyID,
+		Namespace:      gpgKey.Namespace,
+		Source:         gpgKey.Source,
+		TrustSignature: gpgKey.TrustSignature,
+		CreatedAt:      gpgKey.CreatedAt,
+	}
+}
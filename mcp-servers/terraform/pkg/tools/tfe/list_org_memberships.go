@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// OrgMembership summarizes the membership of a user in an organization
+type OrgMembership struct {
+	ID               string   `json:"id"`
+	Email            string   `json:"email"`
+	Status           string   `json:"status"`
+	Username         string   `json:"username,omitempty"`
+	IsServiceAccount bool     `json:"is_service_account"`
+	TwoFactorEnabled *bool    `json:"two_factor_enabled,omitempty"`
+	Teams            []string `json:"teams"`
+}
+
+// OrgMembershipList is the result of the list_org_memberships tool
+type OrgMembershipList struct {
+	Organization string          `json:"organization"`
+	Memberships  []OrgMembership `json:"memberships"`
+	Pagination   *tfe.Pagination `json:"pagination,omitempty"`
+}
+
+// ListOrgMemberships creates a tool to list the members of a Terraform organization.
+func ListOrgMemberships(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_org_memberships",
+			mcp.WithDescription(`Lists the members of a Terraform organization with their status, teams and whether two-factor authentication is enabled, optionally filtered by name or email.`),
+			mcp.WithTitleAnnotation("List the members of a Terraform organization"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("query",
+				mcp.Description("Optional search string matched against user names and emails"),
+			),
+			mcp.WithString("status",
+				mcp.Description("Optional membership status filter"),
+				mcp.Enum(string(tfe.OrganizationMembershipActive), string(tfe.OrganizationMembershipInvited)),
+			),
+			utils.WithPagination(),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listOrgMembershipsHandler(ctx, req, logger)
+		},
+	}
+}
+
+func listOrgMembershipsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	query := strings.TrimSpace(request.GetString("query", ""))
+	status := request.GetString("status", "")
+
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	memberships, err := tfeClient.OrganizationMemberships.List(ctx, terraformOrgName, &tfe.OrganizationMembershipListOptions{
+		ListOptions: tfe.ListOptions{
+			PageNumber: pagination.Page,
+			PageSize:   pagination.PageSize,
+		},
+		Include: []tfe.OrgMembershipIncludeOpt{tfe.OrgMembershipUser, tfe.OrgMembershipTeam},
+		Status:  tfe.OrganizationMembershipStatus(status),
+		Query:   query,
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing organization memberships", err)
+	}
+
+	result := OrgMembershipList{
+		Organization: terraformOrgName,
+		Memberships:  make([]OrgMembership, 0, len(memberships.Items)),
+		Pagination:   memberships.Pagination,
+	}
+	for _, membership := range memberships.Items {
+		result.Memberships = append(result.Memberships, summarizeMembership(membership))
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling organization memberships", err)
+	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(resultJSON)), pagination, nextPage(memberships.Pagination)), nil
+}
+
+func summarizeMembership(membership *tfe.OrganizationMembership) OrgMembership {
+	summary := OrgMembership{
+		ID:     membership.ID,
+		Email:  membership.Email,
+		Status: string(membership.Status),
+		Teams:  make([]string, 0, len(membership.Teams)),
+	}
+	if user := membership.User; user != nil {
+		summary.Username = user.Username
+		summary.IsServiceAccount = user.IsServiceAccount
+		if user.TwoFactor != nil {
+			summary.TwoFactorEnabled = &user.TwoFactor.Enabled
+		}
+	}
+	for _, team := range membership.Teams {
+		if team != nil {
+			summary.Teams = append(summary.Teams, team.Name)
+		}
+	}
+	return summary
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListOrgMemberships(t *testing.T) {
+	tool := ListOrgMemberships(log.New())
+	assert.Equal(t, "list_org_memberships", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.Tool.InputSchema.Required, "terraform_org_name")
+	assert.Equal(t, []string{"active", "invited"}, tool.Tool.InputSchema.Properties["status"].(map[string]any)["enum"])
+}
+
+func TestSummarizeMembership(t *testing.T) {
+	enabled := true
+	summary := summarizeMembership(&tfe.OrganizationMembership{
+		ID:     "ou-123",
+		Email:  "dev@example.com",
+		Status: tfe.OrganizationMembershipActive,
+		User:   &tfe.User{Username: "dev", TwoFactor: &tfe.TwoFactor{Enabled: true}},
+		Teams:  []*tfe.Team{{Name: "owners"}, {Name: "platform"}},
+	})
+	assert.Equal(t, OrgMembership{
+		ID:               "ou-123",
+		Email:            "dev@example.com",
+		Status:           "active",
+		Username:         "dev",
+		TwoFactorEnabled: &enabled,
+		Teams:            []string{"owners", "platform"},
+	}, summary)
+
+	// Invited users have no user details yet
+	invited := summarizeMembership(&tfe.OrganizationMembership{ID: "ou-456", Email: "new@example.com", Status: tfe.OrganizationMembershipInvited})
+	assert.Empty(t, invited.Username)
+	assert.Nil(t, invited.TwoFactorEnabled)
+	assert.Empty(t, invited.Teams)
+}
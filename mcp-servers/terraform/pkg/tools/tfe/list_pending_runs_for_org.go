@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// runStatusesAwaitingConfirmation are the statuses of runs that wait for a user to apply or discard them
+var runStatusesAwaitingConfirmation = map[tfe.RunStatus]bool{
+	tfe.RunPlanned:                  true,
+	tfe.RunCostEstimated:            true,
+	tfe.RunPolicyChecked:            true,
+	tfe.RunPolicyOverride:           true,
+	tfe.RunPolicySoftFailed:         true,
+	tfe.RunPostPlanAwaitingDecision: true,
+}
+
+// PendingRun summarizes a run that has not reached a final status
+type PendingRun struct {
+	RunID                string    `json:"run_id"`
+	Status               string    `json:"status"`
+	WorkspaceID          string    `json:"workspace_id,omitempty"`
+	WorkspaceName        string    `json:"workspace_name,omitempty"`
+	Message              string    `json:"message,omitempty"`
+	Source               string    `json:"source,omitempty"`
+	HasChanges           bool      `json:"has_changes"`
+	IsDestroy            bool      `json:"is_destroy"`
+	AwaitingConfirmation bool      `json:"awaiting_confirmation"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// PendingRunList is the result of the list_pending_runs_for_org tool
+type PendingRunList struct {
+	Organization string                  `json:"organization"`
+	Runs         []PendingRun            `json:"runs"`
+	Pagination   *tfe.PaginationNextPrev `json:"pagination,omitempty"`
+}
+
+// ListPendingRunsForOrg creates a tool to list the runs of an organization that have not finished yet.
+func ListPendingRunsForOrg(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_pending_runs_for_org",
+			mcp.WithDescription(`Lists the runs of a Terraform organization that have not reached a final status: queued, planning, waiting for a confirmation or applying, across all workspaces. Use it to review the run queue, e.g. after create_runs_bulk.`),
+			mcp.WithTitleAnnotation("List pending Terraform runs in an organization"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithBoolean("awaiting_confirmation_only",
+				mcp.Description("Only list the runs waiting for a user to apply or discard them"),
+				mcp.DefaultBool(false),
+			),
+			utils.WithPagination(),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listPendingRunsForOrgHandler(ctx, req, logger)
+		},
+	}
+}
+
+func listPendingRunsForOrgHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	awaitingConfirmationOnly := request.GetBool("awaiting_confirmation_only", false)
+
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	runs, err := tfeClient.Runs.ListForOrganization(ctx, terraformOrgName, &tfe.RunListForOrganizationOptions{
+		ListOptions: tfe.ListOptions{
+			PageNumber: pagination.Page,
+			PageSize:   pagination.PageSize,
+		},
+		StatusGroup: "non_final",
+		Include:     []tfe.RunIncludeOpt{tfe.RunWorkspace},
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing pending runs in organization", err)
+	}
+
+	result := PendingRunList{
+		Organization: terraformOrgName,
+		Runs:         pendingRuns(runs.Items, awaitingConfirmationOnly),
+		Pagination:   runs.PaginationNextPrev,
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling pending runs", err)
+	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(resultJSON)), pagination, nextPageWithoutTotal(runs.PaginationNextPrev)), nil
+}
+
+// pendingRuns summarizes runs, keeping only the ones waiting for a confirmation when awaitingConfirmationOnly is set
+func pendingRuns(runs []*tfe.Run, awaitingConfirmationOnly bool) []PendingRun {
+	pending := make([]PendingRun, 0, len(runs))
+	for _, run := range runs {
+		awaitingConfirmation := runStatusesAwaitingConfirmation[run.Status]
+		if awaitingConfirmationOnly && !awaitingConfirmation {
+			continue
+		}
+
+		summary := PendingRun{
+			RunID:                run.ID,
+			Status:               string(run.Status),
+			Message:              run.Message,
+			Source:               string(run.Source),
+			HasChanges:           run.HasChanges,
+			IsDestroy:            run.IsDestroy,
+			AwaitingConfirmation: awaitingConfirmation,
+			CreatedAt:            run.CreatedAt,
+		}
+		if run.Workspace != nil {
+			summary.WorkspaceID = run.Workspace.ID
+			summary.WorkspaceName = run.Workspace.Name
+		}
+		pending = append(pending, summary)
+	}
+	return pending
+}
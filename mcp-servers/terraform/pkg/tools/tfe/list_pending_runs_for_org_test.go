@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingRuns(t *testing.T) {
+	runs := []*tfe.Run{
+		{ID: "run-1", Status: tfe.RunPlanning, Workspace: &tfe.Workspace{ID: "ws-1", Name: "app-1"}},
+		{ID: "run-2", Status: tfe.RunPlanned, HasChanges: true, Workspace: &tfe.Workspace{ID: "ws-2", Name: "app-2"}},
+		{ID: "run-3", Status: tfe.RunPolicyChecked},
+	}
+
+	all := pendingRuns(runs, false)
+	require.Len(t, all, 3)
+	assert.Equal(t, "app-1", all[0].WorkspaceName)
+	assert.False(t, all[0].AwaitingConfirmation)
+	assert.True(t, all[1].AwaitingConfirmation)
+
+	awaiting := pendingRuns(runs, true)
+	require.Len(t, awaiting, 2)
+	assert.Equal(t, "run-2", awaiting[0].RunID)
+	assert.Equal(t, "run-3", awaiting[1].RunID)
+	assert.Empty(t, awaiting[1].WorkspaceID)
+}
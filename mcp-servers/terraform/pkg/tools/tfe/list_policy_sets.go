@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// PolicySetSummary describes a Sentinel or OPA policy set of an organization
+type PolicySetSummary struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description,omitempty"`
+	Kind           string    `json:"kind"`
+	Global         bool      `json:"global"`
+	Overridable    bool      `json:"overridable"`
+	PolicyCount    int       `json:"policy_count"`
+	WorkspaceCount int       `json:"workspace_count"`
+	ProjectCount   int       `json:"project_count"`
+	VCSRepo        string    `json:"vcs_repo,omitempty"`
+	VCSBranch      string    `json:"vcs_branch,omitempty"`
+	PoliciesPath   string    `json:"policies_path,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PolicySetListResult is the result of the list_policy_sets tool
+type PolicySetListResult struct {
+	Organization string             `json:"organization"`
+	PolicySets   []PolicySetSummary `json:"policy_sets"`
+	Pagination   *tfe.Pagination    `json:"pagination,omitempty"`
+}
+
+// ListPolicySets creates a tool to list the policy sets of a Terraform organization.
+func ListPolicySets(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_policy_sets",
+			mcp.WithDescription(`Lists the Sentinel and OPA policy sets of a Terraform Cloud/Enterprise organization, with whether they are enforced on every workspace (global), their number of policies, workspaces and projects, and the VCS repository they are read from.`),
+			mcp.WithTitleAnnotation("List the policy sets of a Terraform organization"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("search",
+				mcp.Description("Only list the policy sets whose name contains this term"),
+			),
+			mcp.WithString("kind",
+				mcp.Description("Only list the policy sets of this policy framework"),
+				mcp.Enum(string(tfe.Sentinel), string(tfe.OPA)),
+			),
+			utils.WithPagination(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listPolicySetsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listPolicySetsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	policySets, err := tfeClient.PolicySets.List(ctx, terraformOrgName, &tfe.PolicySetListOptions{
+		ListOptions: tfe.ListOptions{
+			PageNumber: pagination.Page,
+			PageSize:   pagination.PageSize,
+		},
+		Search: strings.TrimSpace(request.GetString("search", "")),
+		Kind:   tfe.PolicyKind(strings.TrimSpace(request.GetString("kind", ""))),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing policy sets", err)
+	}
+
+	result := PolicySetListResult{
+		Organization: terraformOrgName,
+		PolicySets:   make([]PolicySetSummary, 0, len(policySets.Items)),
+		Pagination:   policySets.Pagination,
+	}
+	for _, policySet := range policySets.Items {
+		result.PolicySets = append(result.PolicySets, summarizePolicySet(policySet))
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling policy sets", err)
+	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(resultJSON)), pagination, nextPage(policySets.Pagination)), nil
+}
+
+func summarizePolicySet(policySet *tfe.PolicySet) PolicySetSummary {
+	summary := PolicySetSummary{
+		ID:             policySet.ID,
+		Name:           policySet.Name,
+		Description:    policySet.Description,
+		Kind:           string(policySet.Kind),
+		Global:         policySet.Global,
+		Overridable:    policySet.Overridable != nil && *policySet.Overridable,
+		PolicyCount:    policySet.PolicyCount,
+		WorkspaceCount: policySet.WorkspaceCount,
+		ProjectCount:   policySet.ProjectCount,
+		PoliciesPath:   policySet.PoliciesPath,
+		CreatedAt:      policySet.CreatedAt,
+		UpdatedAt:      policySet.UpdatedAt,
+	}
+	if policySet.VCSRepo != nil {
+		summary.VCSRepo = policySet.VCSRepo.Identifier
+		summary.VCSBranch = policySet.VCSRepo.Branch
+	}
+	return summary
+}
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultRunEventLimit = 20
+	maxRunEventLimit     = 100
+)
+
+// RecentRunEvents is the result of the list_recent_run_events tool
+type RecentRunEvents struct {
+	Total  int               `json:"total"`
+	Events []client.RunEvent `json:"events"`
+}
+
+// ListRecentRunEvents creates a tool to list the plan and apply events received by the webhook endpoints.
+func ListRecentRunEvents(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_recent_run_events",
+			mcp.WithDescription(fmt.Sprintf(`Lists the plan and apply events recently received from HCP Terraform/TFE notification webhooks and Atlantis webhooks, the most recent first.
+Use it to find out what CI is doing, e.g. which runs just errored or need a confirmation, without polling the runs of every workspace. Only the last %d events since the server started are kept, use list_runs for older runs.`, client.MaxRunEvents)),
+			mcp.WithTitleAnnotation("List recent Terraform run events from webhooks"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Description("Only list the HCP Terraform/TFE events of this organization"),
+			),
+			mcp.WithString("workspace_name",
+				mcp.Description("Only list the events of this workspace, an HCP Terraform/TFE workspace or an Atlantis workspace"),
+			),
+			mcp.WithString("repository",
+				mcp.Description("Only list the Atlantis events of this repository, e.g. 'acme/infrastructure'"),
+			),
+			mcp.WithString("source",
+				mcp.Description("Only list the events of this source"),
+				mcp.Enum(client.RunEventSourceTFE, client.RunEventSourceAtlantis),
+			),
+			mcp.WithString("status",
+				mcp.Description("Only list the events with this run status, e.g. 'errored', 'planned' or 'applied'"),
+			),
+			mcp.WithString("since",
+				mcp.Description("Only list the events received within this duration, e.g. '30m' or '24h'"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("The maximum number of events to return"),
+				mcp.DefaultNumber(defaultRunEventLimit),
+				mcp.Min(1),
+				mcp.Max(maxRunEventLimit),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listRecentRunEventsHandler(ctx, request, client.GetRunEventStore(), logger)
+		},
+	}
+}
+
+func listRecentRunEventsHandler(ctx context.Context, request mcp.CallToolRequest, store *client.RunEventStore, logger *log.Logger) (*mcp.CallToolResult, error) {
+	organization := strings.TrimSpace(request.GetString("terraform_org_name", ""))
+	workspace := strings.TrimSpace(request.GetString("workspace_name", ""))
+	repository := strings.TrimSpace(request.GetString("repository", ""))
+	source := request.GetString("source", "")
+	status := strings.ToLower(strings.TrimSpace(request.GetString("status", "")))
+	limit := request.GetInt("limit", defaultRunEventLimit)
+	if limit < 1 || limit > maxRunEventLimit {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid limit",
+			fmt.Errorf("limit must be between 1 and %d", maxRunEventLimit))
+	}
+	var since time.Time
+	if value := strings.TrimSpace(request.GetString("since", "")); value != "" {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid since",
+				fmt.Errorf("since must be a positive duration, e.g. '30m' or '24h': %q", value))
+		}
+		since = time.Now().Add(-duration)
+	}
+
+	events := store.Recent(func(event client.RunEvent) bool {
+		// Events of organizations outside of the allowed organizations are hidden like the organizations themselves
+		if event.Organization != "" && !client.OrganizationAllowed(ctx, event.Organization) {
+			return false
+		}
+		return (organization == "" || strings.EqualFold(event.Organization, organization)) &&
+			(workspace == "" || strings.EqualFold(event.Workspace, workspace)) &&
+			(repository == "" || strings.EqualFold(event.Repository, repository)) &&
+			(source == "" || event.Source == source) &&
+			(status == "" || event.Status == status) &&
+			!event.ReceivedAt.Before(since)
+	})
+
+	result := RecentRunEvents{Total: len(events), Events: events}
+	if len(result.Events) > limit {
+		result.Events = result.Events[:limit]
+	}
+	if result.Events == nil {
+		result.Events = []client.RunEvent{}
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling run events", err)
+	}
+	return mcp.NewToolResultText(string(encoded)), nil
+}
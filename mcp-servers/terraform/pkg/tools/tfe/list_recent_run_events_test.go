@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRecentRunEvents(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := ListRecentRunEvents(logger)
+	assert.Equal(t, "list_recent_run_events", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	now := time.Now()
+	store := client.NewRunEventStore(client.MaxRunEvents)
+	store.Add(
+		client.RunEvent{Source: client.RunEventSourceTFE, Status: "errored", Organization: "acme", Workspace: "network", RunID: "run-1", ReceivedAt: now.Add(-2 * time.Hour)},
+		client.RunEvent{Source: client.RunEventSourceAtlantis, Status: "applied", Workspace: "default", Repository: "acme/infrastructure", ReceivedAt: now.Add(-time.Hour)},
+		client.RunEvent{Source: client.RunEventSourceTFE, Status: "planned", Organization: "acme", Workspace: "app", RunID: "run-2", ReceivedAt: now.Add(-time.Minute)},
+		client.RunEvent{Source: client.RunEventSourceTFE, Status: "errored", Organization: "other", Workspace: "app", RunID: "run-3", ReceivedAt: now},
+	)
+
+	list := func(t *testing.T, arguments map[string]any) RecentRunEvents {
+		result, err := listRecentRunEventsHandler(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "list_recent_run_events", Arguments: arguments}}, store, logger)
+		require.NoError(t, err)
+		var events RecentRunEvents
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &events))
+		return events
+	}
+	runIDs := func(events RecentRunEvents) []string {
+		ids := []string{}
+		for _, event := range events.Events {
+			ids = append(ids, event.RunID)
+		}
+		return ids
+	}
+
+	events := list(t, map[string]any{"limit": 2})
+	assert.Equal(t, 4, events.Total)
+	assert.Equal(t, []string{"run-3", "run-2"}, runIDs(events))
+
+	assert.Equal(t, []string{"run-2", "run-1"}, runIDs(list(t, map[string]any{"terraform_org_name": "acme"})))
+	assert.Equal(t, []string{"run-3", "run-1"}, runIDs(list(t, map[string]any{"status": "Errored"})))
+	assert.Equal(t, []string{"run-3", "run-2"}, runIDs(list(t, map[string]any{"workspace_name": "app"})))
+	assert.Equal(t, []string{"run-3", "run-2"}, runIDs(list(t, map[string]any{"since": "30m"})))
+
+	events = list(t, map[string]any{"source": client.RunEventSourceAtlantis, "repository": "ACME/infrastructure"})
+	require.Len(t, events.Events, 1)
+	assert.Equal(t, "applied", events.Events[0].Status)
+
+	assert.Empty(t, list(t, map[string]any{"workspace_name": "missing"}).Events)
+
+	for name, arguments := range map[string]map[string]any{
+		"invalid since":  {"since": "yesterday"},
+		"negative since": {"since": "-1h"},
+		"invalid limit":  {"limit": 500},
+	} {
+		_, err := listRecentRunEventsHandler(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}}, store, logger)
+		assert.Equal(t, utils.ErrorCodeInvalidInput, utils.ErrorCodeOf(err), name)
+	}
+}
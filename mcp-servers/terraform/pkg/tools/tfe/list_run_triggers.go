@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// RunTriggerSummary describes a run trigger: a successful apply in the source workspace queues a run in the workspace
+type RunTriggerSummary struct {
+	ID                  string    `json:"id"`
+	SourceWorkspaceID   string    `json:"source_workspace_id,omitempty"`
+	SourceWorkspaceName string    `json:"source_workspace_name"`
+	WorkspaceID         string    `json:"workspace_id,omitempty"`
+	WorkspaceName       string    `json:"workspace_name"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// RunTriggerListResult is the result of the list_run_triggers tool
+type RunTriggerListResult struct {
+	Workspace   string              `json:"workspace"`
+	Direction   string              `json:"direction"`
+	RunTriggers []RunTriggerSummary `json:"run_triggers"`
+	Pagination  *tfe.Pagination     `json:"pagination,omitempty"`
+}
+
+// ListRunTriggers creates a tool to list the run triggers of a Terraform workspace.
+func ListRunTriggers(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_run_triggers",
+			mcp.WithDescription(`Lists the run triggers of a Terraform workspace. Inbound triggers list the source workspaces whose successful applies queue a run in this workspace; outbound triggers list the workspaces in which a successful apply of this workspace queues a run.`),
+			mcp.WithTitleAnnotation("List the run triggers of a Terraform workspace"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			WithWorkspace("The name of the workspace"),
+			mcp.WithString("direction",
+				mcp.Description("Whether to list the triggers that start runs in this workspace (inbound) or the ones started by it (outbound)"),
+				mcp.Enum(string(tfe.RunTriggerInbound), string(tfe.RunTriggerOutbound)),
+				mcp.DefaultString(string(tfe.RunTriggerInbound)),
+			),
+			utils.WithPagination(),
+		),
+		Handler: func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listRunTriggersHandler(ctx, req, logger)
+		},
+	}
+}
+
+func listRunTriggersHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+
+	direction := request.GetString("direction", string(tfe.RunTriggerInbound))
+
+	pagination, err := utils.OptionalPaginationParams(request)
+	if err != nil {
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	runTriggers, err := tfeClient.RunTriggers.List(ctx, workspace.ID, &tfe.RunTriggerListOptions{
+		ListOptions: tfe.ListOptions{
+			PageNumber: pagination.Page,
+			PageSize:   pagination.PageSize,
+		},
+		RunTriggerType: tfe.RunTriggerFilterOp(direction),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing run triggers", err)
+	}
+
+	result := RunTriggerListResult{
+		Workspace:   workspace.Name,
+		Direction:   direction,
+		RunTriggers: make([]RunTriggerSummary, 0, len(runTriggers.Items)),
+		Pagination:  runTriggers.Pagination,
+	}
+	for _, runTrigger := range runTriggers.Items {
+		result.RunTriggers = append(result.RunTriggers, summarizeRunTrigger(runTrigger))
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling run triggers", err)
+	}
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(resultJSON)), pagination, nextPage(runTriggers.Pagination)), nil
+}
+
+func summarizeRunTrigger(runTrigger *tfe.RunTrigger) RunTriggerSummary {
+	summary := RunTriggerSummary{
+		ID:                  runTrigger.ID,
+		SourceWorkspaceName: runTrigger.SourceableName,
+		WorkspaceName:       runTrigger.WorkspaceName,
+		CreatedAt:           runTrigger.CreatedAt,
+	}
+	if runTrigger.SourceableChoice != nil && runTrigger.SourceableChoice.Workspace != nil {
+		summary.SourceWorkspaceID = runTrigger.SourceableChoice.Workspace.ID
+	}
+	if runTrigger.Workspace != nil {
+		summary.WorkspaceID = runTrigger.Workspace.ID
+	}
+	return summary
+}
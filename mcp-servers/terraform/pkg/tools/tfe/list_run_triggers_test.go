@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTriggerTools(t *testing.T) {
+	logger := log.New()
+
+	list := ListRunTriggers(logger)
+	assert.Equal(t, "list_run_triggers", list.Tool.Name)
+	assert.True(t, *list.Tool.Annotations.ReadOnlyHint)
+	assert.Equal(t, []string{"inbound", "outbound"}, list.Tool.InputSchema.Properties["direction"].(map[string]any)["enum"])
+
+	create := CreateRunTrigger(logger)
+	assert.Equal(t, "create_run_trigger", create.Tool.Name)
+	assert.False(t, *create.Tool.Annotations.ReadOnlyHint)
+	assert.Empty(t, create.Tool.InputSchema.Required)
+	assert.Contains(t, create.Tool.InputSchema.Properties, "workspace_id")
+	assert.Contains(t, create.Tool.InputSchema.Properties, "source_workspace_id")
+	assert.Contains(t, create.Tool.InputSchema.Properties, dryRunParam)
+}
+
+func TestSummarizeRunTrigger(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	summary := summarizeRunTrigger(&tfe.RunTrigger{
+		ID:               "rt-123",
+		CreatedAt:        createdAt,
+		SourceableName:   "network",
+		WorkspaceName:    "app",
+		SourceableChoice: &tfe.SourceableChoice{Workspace: &tfe.Workspace{ID: "ws-network"}},
+		Workspace:        &tfe.Workspace{ID: "ws-app"},
+	})
+	assert.Equal(t, RunTriggerSummary{
+		ID:                  "rt-123",
+		SourceWorkspaceID:   "ws-network",
+		SourceWorkspaceName: "network",
+		WorkspaceID:         "ws-app",
+		WorkspaceName:       "app",
+		CreatedAt:           createdAt,
+	}, summary)
+}
+
+func TestRunTriggerPayload(t *testing.T) {
+	payload, err := encodePayload(&tfe.RunTriggerCreateOptions{Sourceable: &tfe.Workspace{ID: "ws-network"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data": {"type": "run-triggers", "relationships": {"sourceable": {"data": {"type": "workspaces", "id": "ws-network"}}}}}`, string(payload))
+}
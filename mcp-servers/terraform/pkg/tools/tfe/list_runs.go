@@ -26,12 +26,14 @@ func ListRuns(logger *log.Logger) server.ServerTool {
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
 			mcp.WithString("terraform_org_name",
-				mcp.Required(),
-				mcp.Description("Lists the runs in Terraform Cloud/Enterprise organization based on filters if no workspace is specified"),
+				mcp.Description("Lists the runs in Terraform Cloud/Enterprise organization based on filters if no workspace is specified. Required unless workspace_id is set."),
 			),
 			mcp.WithString("workspace_name",
 				mcp.Description("If specified, lists the runs in the given workspace instead of the organization based on filters"),
 			),
+			mcp.WithString("workspace_id",
+				mcp.Description("If specified, lists the runs in the workspace with this ID (e.g., 'ws-abc123def456'), instead of workspace_name"),
+			),
 			mcp.WithString("vcs_username",
 				mcp.Description("Searches for runs that match the VCS username you supply"),
 			),
@@ -77,19 +79,21 @@ func ListRuns(logger *log.Logger) server.ServerTool {
 }
 
 func listRunsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
-	terraformOrgName, err := request.RequireString("terraform_org_name")
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required", err)
+	terraformOrgName := strings.TrimSpace(request.GetString("terraform_org_name", ""))
+	workspaceName := strings.TrimSpace(request.GetString("workspace_name", ""))
+	workspaceID := strings.TrimSpace(request.GetString("workspace_id", ""))
+	if workspaceID == "" && terraformOrgName == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required unless workspace_id is set", nil)
+	}
+	if _, err := workspaceRefFromRequest(request); err != nil && (workspaceID != "" || workspaceName != "") {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
 	}
-	terraformOrgName = strings.TrimSpace(terraformOrgName)
-
-	workspaceName := request.GetString("workspace_name", "")
 	vcsUsername := request.GetString("vcs_username", "")
 	status := request.GetString("status", "")
 
 	pagination, err := utils.OptionalPaginationParams(request)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
 	}
 
 	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
@@ -98,7 +102,8 @@ func listRunsHandler(ctx context.Context, request mcp.CallToolRequest, logger *l
 	}
 
 	buf := bytes.NewBuffer(nil)
-	if workspaceName != "" {
+	next := 0
+	if workspaceName != "" || workspaceID != "" {
 
 		// Set up pagination options
 		options := &tfe.RunListOptions{
@@ -118,9 +123,9 @@ func listRunsHandler(ctx context.Context, request mcp.CallToolRequest, logger *l
 		}
 
 		// Get runs for the specified workspace with options
-		workspace, err := tfeClient.Workspaces.Read(ctx, terraformOrgName, workspaceName)
+		workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
 		if err != nil {
-			return nil, utils.LogAndReturnError(logger, "reading workspace", err)
+			return nil, err
 		}
 
 		runs, err := tfeClient.Runs.List(ctx, workspace.ID, options)
@@ -132,6 +137,7 @@ func listRunsHandler(ctx context.Context, request mcp.CallToolRequest, logger *l
 		if err != nil {
 			return nil, utils.LogAndReturnError(logger, "marshalling search runs", err)
 		}
+		next = nextPage(runs.Pagination)
 
 	} else {
 
@@ -162,7 +168,8 @@ func listRunsHandler(ctx context.Context, request mcp.CallToolRequest, logger *l
 		if err != nil {
 			return nil, utils.LogAndReturnError(logger, "marshalling search organization runs", err)
 		}
+		next = nextPageWithoutTotal(runs.PaginationNextPrev)
 	}
 
-	return mcp.NewToolResultText(buf.String()), nil
+	return utils.WithNextPageHint(mcp.NewToolResultText(buf.String()), pagination, next), nil
 }
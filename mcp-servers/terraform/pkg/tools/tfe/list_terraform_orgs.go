@@ -43,7 +43,7 @@ func listTerraformOrgsHandler(ctx context.Context, request mcp.CallToolRequest,
 
 	pagination, err := utils.OptionalPaginationParams(request)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
 	}
 
 	orgs, err := tfeClient.Organizations.List(ctx, &tfe.OrganizationListOptions{
@@ -59,7 +59,10 @@ func listTerraformOrgsHandler(ctx context.Context, request mcp.CallToolRequest,
 
 	orgNames := make([]string, 0, len(orgs.Items))
 	for _, org := range orgs.Items {
-		orgNames = append(orgNames, org.Name)
+		// Organizations the session may not access are left out
+		if client.OrganizationAllowed(ctx, org.Name) {
+			orgNames = append(orgNames, org.Name)
+		}
 	}
 
 	orgsJSON, err := json.Marshal(orgNames)
@@ -67,5 +70,5 @@ func listTerraformOrgsHandler(ctx context.Context, request mcp.CallToolRequest,
 		return nil, utils.LogAndReturnError(logger, "marshalling organization names", err)
 	}
 
-	return mcp.NewToolResultText(string(orgsJSON)), nil
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(orgsJSON)), pagination, nextPage(orgs.Pagination)), nil
 }
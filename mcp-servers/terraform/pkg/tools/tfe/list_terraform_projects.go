@@ -38,15 +38,15 @@ func ListTerraformProjects(logger *log.Logger) server.ServerTool {
 func listTerraformProjectsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
 	terraformOrgName, err := request.RequireString("terraform_org_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "required input: terraform_org_name is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: terraform_org_name is required", err)
 	}
 	if terraformOrgName == "" {
-		return nil, utils.LogAndReturnError(logger, "required input: terraform_org_name cannot be empty", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "required input: terraform_org_name cannot be empty", nil)
 	}
 
 	pagination, err := utils.OptionalPaginationParams(request)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
 	}
 
 	// Get a Terraform client from context
@@ -83,5 +83,5 @@ func listTerraformProjectsHandler(ctx context.Context, request mcp.CallToolReque
 		return nil, utils.LogAndReturnError(logger, "marshalling project infos", err)
 	}
 
-	return mcp.NewToolResultText(string(projectJSON)), nil
+	return utils.WithNextPageHint(mcp.NewToolResultText(string(projectJSON)), pagination, nextPage(projects.Pagination)), nil
 }
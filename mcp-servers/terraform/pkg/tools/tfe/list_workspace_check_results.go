@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// CheckResult is the outcome of a continuous validation check: a check block, or the preconditions and
+// postconditions of a resource or output. Problems hold the messages of the failing instances.
+type CheckResult struct {
+	Address  string         `json:"address"`
+	Kind     string         `json:"kind"`
+	Status   string         `json:"status"`
+	Problems []CheckProblem `json:"problems,omitempty"`
+}
+
+// CheckProblem is the error message of a failing instance of a check
+type CheckProblem struct {
+	Instance string `json:"instance"`
+	Message  string `json:"message"`
+}
+
+// WorkspaceCheckResults is the result of the list_workspace_check_results tool
+type WorkspaceCheckResults struct {
+	WorkspaceID   string        `json:"workspace_id"`
+	WorkspaceName string        `json:"workspace_name"`
+	AssessmentID  string        `json:"assessment_id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Counts        CheckCounts   `json:"counts"`
+	Checks        []CheckResult `json:"checks"`
+}
+
+// ListWorkspaceCheckResults creates a tool to list the continuous validation results of a workspace.
+func ListWorkspaceCheckResults(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_workspace_check_results",
+			mcp.WithDescription(`Lists the continuous validation results of the latest health assessment of a workspace: each check block, precondition and postcondition with its status (pass, fail, error or unknown) and the error messages of its failing instances.
+By default only the checks that do not pass are listed. Health assessments must be enabled on the workspace, and reading the results needs admin access to it.`),
+			mcp.WithTitleAnnotation("List the continuous validation results of a Terraform workspace"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			WithWorkspace("The name of the workspace"),
+			mcp.WithBoolean("failing_only",
+				mcp.Description("Only list the checks that failed, errored or could not be evaluated"),
+				mcp.DefaultBool(true),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listWorkspaceCheckResultsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listWorkspaceCheckResultsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+	failingOnly := request.GetBool("failing_only", true)
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+	assessment, plan, err := readAssessment(ctx, tfeClient, workspace, logger)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, utils.LogAndReturnError(logger, "listing check results",
+			fmt.Errorf("assessment %s of workspace %s failed before checks could run: %s", assessment.ID, workspace.Name, assessment.ErrorMsg))
+	}
+
+	result := WorkspaceCheckResults{
+		WorkspaceID:   workspace.ID,
+		WorkspaceName: workspace.Name,
+		AssessmentID:  assessment.ID,
+		CreatedAt:     assessment.CreatedAt,
+		Counts:        countChecks(plan),
+		Checks:        checkResults(plan, failingOnly),
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling check results", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// checkResults lists the checks of a plan, failing ones first, leaving out the passing checks when failingOnly is set
+func checkResults(plan *assessmentPlan, failingOnly bool) []CheckResult {
+	results := []CheckResult{}
+	for _, check := range plan.Checks {
+		if failingOnly && check.Status == checkStatusPass {
+			continue
+		}
+		result := CheckResult{Address: check.Address.ToDisplay, Kind: check.Address.Kind, Status: check.Status}
+		if result.Status == "" {
+			result.Status = checkStatusUnknown
+		}
+		for _, instance := range check.Instances {
+			for _, problem := range instance.Problems {
+				result.Problems = append(result.Problems, CheckProblem{Instance: instance.Address.ToDisplay, Message: problem.Message})
+			}
+		}
+		results = append(results, result)
+	}
+
+	rank := map[string]int{checkStatusFail: 0, checkStatusError: 1, checkStatusUnknown: 2, checkStatusPass: 3}
+	sort.SliceStable(results, func(i, j int) bool {
+		if rank[results[i].Status] != rank[results[j].Status] {
+			return rank[results[i].Status] < rank[results[j].Status]
+		}
+		return results[i].Address < results[j].Address
+	})
+	return results
+}
@@ -58,7 +58,7 @@ func searchTerraformWorkspacesHandler(ctx context.Context, request mcp.CallToolR
 	// Get Terraform org name
 	terraformOrgName, err := request.RequireString("terraform_org_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
 	}
 	terraformOrgName = strings.TrimSpace(terraformOrgName)
 
@@ -94,7 +94,7 @@ func searchTerraformWorkspacesHandler(ctx context.Context, request mcp.CallToolR
 
 	pagination, err := utils.OptionalPaginationParams(request)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), err.Error()), nil
 	}
 
 	workspaces, err := tfeClient.Workspaces.List(ctx, terraformOrgName, &tfe.WorkspaceListOptions{
@@ -119,5 +119,5 @@ func searchTerraformWorkspacesHandler(ctx context.Context, request mcp.CallToolR
 		return nil, utils.LogAndReturnError(logger, "marshalling workspace creation result", err)
 	}
 
-	return mcp.NewToolResultText(buf.String()), nil
+	return utils.WithNextPageHint(mcp.NewToolResultText(buf.String()), pagination, nextPage(workspaces.Pagination)), nil
 }
@@ -10,8 +10,11 @@ import (
 	"testing"
 
 	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockCallToolRequest implements the mcp.CallToolRequest interface for testing
@@ -94,15 +97,40 @@ func TestSearchWorkspaces(t *testing.T) {
 			},
 		}
 
-		mockWorkspaceList := &tfe.WorkspaceList{
-			Items:      mockWorkspaces,
-			Pagination: &tfe.Pagination{CurrentPage: 1, TotalCount: 2},
-		}
+		fake := testutil.NewFakeTFE(t)
+		fake.RespondList("GET", "/organizations/test-org/workspaces", mockWorkspaces, &tfe.Pagination{CurrentPage: 1, NextPage: 2, TotalPages: 2, TotalCount: 4})
+
+		result, err := searchTerraformWorkspacesHandler(fake.Context(t), mcp.CallToolRequest{Params: mcp.CallToolParams{
+			Name: "list_workspaces",
+			Arguments: map[string]any{
+				"terraform_org_name": "test-org",
+				"search_query":       "test",
+				"project_id":         "prj-123",
+				"tags":               "env:prod, team:backend",
+				"exclude_tags":       "deprecated",
+				"wildcard_name":      "test-*",
+				"pageSize":           float64(2),
+			},
+		}}, logger)
+		require.NoError(t, err)
+
+		// The tool arguments become the query of the workspaces API
+		requests := fake.Requests()
+		require.Len(t, requests, 1)
+		query := requests[0].Query
+		assert.Equal(t, "test", query.Get("search[name]"))
+		assert.Equal(t, "prj-123", query.Get("filter[project][id]"))
+		assert.Equal(t, "env:prod,team:backend", query.Get("search[tags]"))
+		assert.Equal(t, "deprecated", query.Get("search[exclude-tags]"))
+		assert.Equal(t, "test-*", query.Get("search[wildcard-name]"))
+		assert.Equal(t, "1", query.Get("page[number]"))
+		assert.Equal(t, "2", query.Get("page[size]"))
 
-		// Verify the mock workspace list structure
-		assert.Len(t, mockWorkspaceList.Items, 2)
-		assert.Equal(t, "ws-123", mockWorkspaceList.Items[0].ID)
-		assert.Equal(t, "test-workspace-1", mockWorkspaceList.Items[0].Name)
+		require.Len(t, result.Content, 2)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, "ws-123")
+		assert.Contains(t, text, "test-workspace-2")
+		assert.Contains(t, result.Content[1].(mcp.TextContent).Text, "page=2, pageSize=2")
 	})
 
 	t.Run("missing required parameter", func(t *testing.T) {
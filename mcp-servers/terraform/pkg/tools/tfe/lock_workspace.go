@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WorkspaceLockResult is the result of the lock_workspace and unlock_workspace tools
+type WorkspaceLockResult struct {
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name"`
+	Locked        bool   `json:"locked"`
+	Message       string `json:"message"`
+}
+
+// LockWorkspace creates a tool to lock a Terraform workspace so that no run can be started in it.
+func LockWorkspace(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("lock_workspace",
+			mcp.WithDescription(`Locks a Terraform workspace so that no new run can start in it, e.g. before changing several variables. The lock is held by the token of the server until unlock_workspace is called.`),
+			mcp.WithTitleAnnotation("Lock a Terraform workspace"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			WithWorkspace("The name of the workspace to lock"),
+			mcp.WithString("reason",
+				mcp.Description("Optional reason for the lock, shown to other users of the workspace"),
+			),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return lockWorkspaceHandler(ctx, request, logger)
+		},
+	}
+}
+
+func lockWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+
+	reason := request.GetString("reason", "Locked via Terraform MCP Server")
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+	if workspace.Locked {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("locking workspace %s/%s", workspace.Organization.Name, workspace.Name), tfe.ErrWorkspaceLocked)
+	}
+
+	options := &tfe.WorkspaceLockOptions{Reason: &reason}
+	if request.GetBool(dryRunParam, false) {
+		effects := []string{
+			fmt.Sprintf("Locks workspace %s (%s) with the reason %q", workspace.Name, workspace.ID, reason),
+			"No new run can start in the workspace until it is unlocked",
+		}
+		return dryRunResult(request, "POST", fmt.Sprintf("workspaces/%s/actions/lock", url.PathEscape(workspace.ID)), options, effects, logger)
+	}
+
+	workspace, err = tfeClient.Workspaces.Lock(ctx, workspace.ID, *options)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "locking workspace", err)
+	}
+
+	return workspaceLockResult(workspace, fmt.Sprintf("Workspace locked: %s", reason), logger)
+}
+
+func workspaceLockResult(workspace *tfe.Workspace, message string, logger *log.Logger) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.Marshal(WorkspaceLockResult{
+		WorkspaceID:   workspace.ID,
+		WorkspaceName: workspace.Name,
+		Locked:        workspace.Locked,
+		Message:       message,
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling workspace lock result", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockWorkspace(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel) // Reduce noise in tests
+
+	t.Run("tool creation", func(t *testing.T) {
+		tool := LockWorkspace(logger)
+
+		assert.Equal(t, "lock_workspace", tool.Tool.Name)
+		assert.NotNil(t, tool.Handler)
+		assert.False(t, *tool.Tool.Annotations.ReadOnlyHint)
+		assert.False(t, *tool.Tool.Annotations.DestructiveHint)
+
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "terraform_org_name")
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "workspace_id")
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "reason")
+		assert.NotContains(t, tool.Tool.InputSchema.Required, "reason")
+	})
+
+	t.Run("lock result", func(t *testing.T) {
+		result, err := workspaceLockResult(&tfe.Workspace{ID: "ws-123", Name: "staging", Locked: true}, "Workspace locked: variable changes", logger)
+		require.NoError(t, err)
+
+		var lock WorkspaceLockResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &lock))
+		assert.Equal(t, WorkspaceLockResult{WorkspaceID: "ws-123", WorkspaceName: "staging", Locked: true, Message: "Workspace locked: variable changes"}, lock)
+	})
+}
+
+func TestUnlockWorkspace(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel) // Reduce noise in tests
+
+	tool := UnlockWorkspace(logger)
+
+	assert.Equal(t, "unlock_workspace", tool.Tool.Name)
+	assert.NotNil(t, tool.Handler)
+	assert.False(t, *tool.Tool.Annotations.ReadOnlyHint)
+
+	assert.Contains(t, tool.Tool.InputSchema.Properties, "terraform_org_name")
+	assert.Contains(t, tool.Tool.InputSchema.Properties, "workspace_id")
+	require.Contains(t, tool.Tool.InputSchema.Properties, "force")
+	assert.Equal(t, false, tool.Tool.InputSchema.Properties["force"].(map[string]any)["default"])
+	assert.Contains(t, tool.Tool.InputSchema.Properties, confirmationTokenParam)
+}
+
+func TestLockWorkspaceHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel) // Reduce noise in tests
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "lock_workspace", Arguments: arguments}}
+	}
+
+	t.Run("locks the workspace with the reason", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/organizations/acme/workspaces/staging", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging"})
+		fake.Respond("POST", "/workspaces/ws-123/actions/lock", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Locked: true})
+
+		result, err := lockWorkspaceHandler(fake.Context(t), request(map[string]any{
+			"terraform_org_name": " acme ",
+			"workspace_name":     "staging",
+			"reason":             "variable changes",
+		}), logger)
+		require.NoError(t, err)
+
+		var lock WorkspaceLockResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &lock))
+		assert.Equal(t, WorkspaceLockResult{WorkspaceID: "ws-123", WorkspaceName: "staging", Locked: true, Message: "Workspace locked: variable changes"}, lock)
+
+		requests := fake.Requests()
+		require.Len(t, requests, 2)
+		assert.Equal(t, "POST", requests[1].Method)
+		assert.JSONEq(t, `{"data": {"type": "", "attributes": {"reason": "variable changes"}}}`, string(requests[1].Body))
+	})
+
+	t.Run("already locked workspace", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/organizations/acme/workspaces/staging", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Locked: true})
+
+		_, err := lockWorkspaceHandler(fake.Context(t), request(map[string]any{"terraform_org_name": "acme", "workspace_name": "staging"}), logger)
+		require.Error(t, err)
+		assert.Equal(t, utils.ErrorCodeInvalidInput, utils.ErrorCodeOf(err))
+		assert.Len(t, fake.Requests(), 1)
+	})
+
+	t.Run("missing workspace", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+
+		_, err := lockWorkspaceHandler(fake.Context(t), request(map[string]any{"terraform_org_name": "acme", "workspace_name": "staging"}), logger)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, tfe.ErrResourceNotFound)
+	})
+
+	t.Run("dry run does not lock", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/organizations/acme/workspaces/staging", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging"})
+
+		result, err := lockWorkspaceHandler(fake.Context(t), request(map[string]any{"terraform_org_name": "acme", "workspace_name": "staging", dryRunParam: true}), logger)
+		require.NoError(t, err)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "workspaces/ws-123/actions/lock")
+		assert.Len(t, fake.Requests(), 1)
+	})
+}
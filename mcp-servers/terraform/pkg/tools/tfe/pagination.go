@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"github.com/hashicorp/go-tfe"
+)
+
+// nextPage returns the next page of a list, or 0 on the last page
+func nextPage(pagination *tfe.Pagination) int {
+	if pagination == nil {
+		return 0
+	}
+	return pagination.NextPage
+}
+
+// nextPageWithoutTotal returns the next page of a list counted without a total, e.g. the runs of an organization
+func nextPageWithoutTotal(pagination *tfe.PaginationNextPrev) int {
+	if pagination == nil {
+		return 0
+	}
+	return pagination.NextPage
+}
@@ -0,0 +1,490 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// stateReadConcurrency is the number of current state versions read at the same time
+	stateReadConcurrency = 5
+	publicRegistryHost   = "registry.terraform.io"
+)
+
+// Risk levels of a workspace upgrade, from the safest
+const (
+	upgradeRiskLow    = "low"
+	upgradeRiskMedium = "medium"
+	upgradeRiskHigh   = "high"
+)
+
+var upgradeRiskRank = map[string]int{upgradeRiskLow: 0, upgradeRiskMedium: 1, upgradeRiskHigh: 2}
+
+// upgradeStop is a release every workspace older than its minor version must be upgraded to before going further
+type upgradeStop struct {
+	version string
+	note    string
+}
+
+var upgradeStops = []upgradeStop{
+	{"0.12.31", "0.12 rewrites the configuration language: run 'terraform 0.12upgrade' on every module and review the result"},
+	{"0.13.7", "0.13 requires provider source addresses: run 'terraform 0.13upgrade' to add required_providers blocks"},
+	{"0.14.11", "0.14 adds the dependency lock file .terraform.lock.hcl, commit it with the configuration"},
+}
+
+var (
+	// providerAddressPattern matches the provider of a state resource, e.g. provider["registry.terraform.io/hashicorp/aws"]
+	providerAddressPattern = regexp.MustCompile(`provider\["([^"]+)"\]`)
+	// legacyProviderPattern matches the provider of a state resource written before 0.13, e.g. provider.aws
+	legacyProviderPattern = regexp.MustCompile(`^provider\.([a-z0-9_-]+)`)
+)
+
+// ProviderCompatibility is the Terraform version support of the latest release of a provider
+type ProviderCompatibility struct {
+	Source        string   `json:"source"`
+	LatestVersion string   `json:"latest_version,omitempty"`
+	Protocols     []string `json:"protocols,omitempty"`
+	// MinTerraformVersion is the oldest Terraform version able to run the latest release
+	MinTerraformVersion string `json:"min_terraform_version,omitempty"`
+	// Blocked is set when no release of the provider runs on Terraform 0.12 or later
+	Blocked bool   `json:"blocked,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
+// WorkspaceUpgrade is a workspace of an upgrade wave with the risks of upgrading it
+type WorkspaceUpgrade struct {
+	WorkspaceID   string   `json:"workspace_id"`
+	WorkspaceName string   `json:"workspace_name"`
+	ResourceCount int      `json:"resource_count"`
+	AutoApply     bool     `json:"auto_apply"`
+	Providers     []string `json:"providers,omitempty"`
+	Risk          string   `json:"risk"`
+	Notes         []string `json:"notes,omitempty"`
+}
+
+// UpgradeWave is a group of workspaces on the same Terraform version, upgraded together. The first
+// workspace is the canary, upgraded and verified before the others.
+type UpgradeWave struct {
+	Wave        int                `json:"wave"`
+	FromVersion string             `json:"from_version"`
+	UpgradePath []string           `json:"upgrade_path"`
+	PathNotes   []string           `json:"path_notes,omitempty"`
+	Risk        string             `json:"risk"`
+	Canary      string             `json:"canary"`
+	Workspaces  []WorkspaceUpgrade `json:"workspaces"`
+}
+
+// SkippedWorkspace is a workspace left out of the upgrade plan
+type SkippedWorkspace struct {
+	WorkspaceID      string `json:"workspace_id"`
+	WorkspaceName    string `json:"workspace_name"`
+	TerraformVersion string `json:"terraform_version"`
+	Reason           string `json:"reason"`
+}
+
+// TerraformUpgradePlan is the result of the plan_terraform_version_upgrade tool
+type TerraformUpgradePlan struct {
+	Organization  string                  `json:"organization"`
+	TargetVersion string                  `json:"target_version"`
+	Checked       int                     `json:"checked"`
+	UpToDate      int                     `json:"up_to_date"`
+	Truncated     bool                    `json:"truncated,omitempty"`
+	Waves         []UpgradeWave           `json:"waves"`
+	Skipped       []SkippedWorkspace      `json:"skipped,omitempty"`
+	Providers     []ProviderCompatibility `json:"providers,omitempty"`
+}
+
+// PlanTerraformVersionUpgrade creates a tool to plan the rollout of a new Terraform version across an organization.
+func PlanTerraformVersionUpgrade(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("plan_terraform_version_upgrade",
+			mcp.WithDescription(fmt.Sprintf(`Plans the upgrade of the workspaces of a Terraform organization to target_version. Workspaces are grouped by their current Terraform version into ordered waves, lowest risk first, each with the intermediate releases to go through and a canary workspace to upgrade first.
+With check_providers, the providers of each workspace are read from its current state and cross-referenced with the public registry to flag providers that cannot run on newer Terraform versions.
+Workspaces already on target_version or later are counted as up to date, and workspaces that follow "latest" or a version constraint are skipped. Nothing is changed by this tool. At most %d workspaces are read.`, maxReportWorkspaces)),
+			mcp.WithTitleAnnotation("Plan a Terraform version upgrade across an organization"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("target_version",
+				mcp.Required(),
+				mcp.Description("The Terraform version to upgrade to, e.g. 1.9.8"),
+			),
+			mcp.WithString("project_id",
+				mcp.Description("Only plan the upgrade of the workspaces of this project"),
+			),
+			mcp.WithBoolean("check_providers",
+				mcp.Description("Read the current state of each workspace to cross-reference its providers with the public registry, one extra API call per workspace and per provider"),
+				mcp.DefaultBool(true),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return planTerraformVersionUpgradeHandler(ctx, request, logger)
+		},
+	}
+}
+
+func planTerraformVersionUpgradeHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+	targetVersion, err := request.RequireString("target_version")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'target_version' parameter is required", err)
+	}
+	target, err := version.NewVersion(strings.TrimPrefix(strings.TrimSpace(targetVersion), "v"))
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "validating target_version", err)
+	}
+	projectID := strings.TrimSpace(request.GetString("project_id", ""))
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspaces, truncated, err := listOrgWorkspaces(ctx, tfeClient, terraformOrgName, projectID)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing workspaces in organization", err)
+	}
+
+	var providers map[string][]string
+	var compatibility map[string]ProviderCompatibility
+	if request.GetBool("check_providers", true) {
+		providers = readWorkspaceProviders(ctx, tfeClient, outdatedWorkspaces(workspaces, target), logger)
+		httpClient, err := client.GetHttpClientFromContext(ctx, logger)
+		if err != nil {
+			logger.Warnf("Skipping the registry lookup of providers: %v", err)
+		} else {
+			compatibility = providerCompatibility(ctx, httpClient, providers, logger)
+		}
+	}
+
+	plan := planUpgrade(terraformOrgName, target, workspaces, providers, compatibility)
+	plan.Truncated = truncated
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling upgrade plan", err)
+	}
+	return mcp.NewToolResultText(string(planJSON)), nil
+}
+
+// outdatedWorkspaces returns the workspaces pinned to a version older than target
+func outdatedWorkspaces(workspaces []*tfe.Workspace, target *version.Version) []*tfe.Workspace {
+	var outdated []*tfe.Workspace
+	for _, workspace := range workspaces {
+		current, err := version.NewVersion(workspace.TerraformVersion)
+		if err == nil && current.LessThan(target) {
+			outdated = append(outdated, workspace)
+		}
+	}
+	return outdated
+}
+
+// readWorkspaceProviders reads the providers used by the current state of each workspace, keyed by
+// workspace ID. Workspaces whose state cannot be read, or has not been processed yet, are left out.
+func readWorkspaceProviders(ctx context.Context, tfeClient *tfe.Client, workspaces []*tfe.Workspace, logger *log.Logger) map[string][]string {
+	providers := make(map[string][]string)
+	var mu sync.Mutex
+	semaphore := make(chan struct{}, stateReadConcurrency)
+	var wg sync.WaitGroup
+	for _, workspace := range workspaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			state, err := tfeClient.StateVersions.ReadCurrent(ctx, workspace.ID)
+			if err != nil {
+				logger.WithField("workspace", workspace.Name).Debugf("Failed to read the current state: %v", err)
+				return
+			}
+			if !state.ResourcesProcessed && len(state.Resources) == 0 {
+				return
+			}
+			sources := make(map[string]bool)
+			for _, resource := range state.Resources {
+				if source := providerSource(resource.Provider); source != "" {
+					sources[source] = true
+				}
+			}
+			mu.Lock()
+			providers[workspace.ID] = sortedKeys(sources)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return providers
+}
+
+// providerSource returns the source address of the provider of a state resource. Providers of the
+// public registry are returned as namespace/name, and legacy addresses as hashicorp/name.
+func providerSource(provider string) string {
+	if match := providerAddressPattern.FindStringSubmatch(provider); match != nil {
+		return strings.TrimPrefix(match[1], publicRegistryHost+"/")
+	}
+	if match := legacyProviderPattern.FindStringSubmatch(provider); match != nil {
+		return "hashicorp/" + match[1]
+	}
+	return ""
+}
+
+// providerCompatibility looks up the latest release of each public registry provider and the Terraform
+// versions able to run it
+func providerCompatibility(ctx context.Context, httpClient *http.Client, providers map[string][]string, logger *log.Logger) map[string]ProviderCompatibility {
+	sources := make(map[string]bool)
+	for _, workspaceProviders := range providers {
+		for _, source := range workspaceProviders {
+			sources[source] = true
+		}
+	}
+
+	compatibility := make(map[string]ProviderCompatibility, len(sources))
+	for _, source := range sortedKeys(sources) {
+		parts := strings.Split(source, "/")
+		if len(parts) != 2 {
+			compatibility[source] = ProviderCompatibility{Source: source, Note: "Not a public registry provider, check its compatibility manually"}
+			continue
+		}
+		releases, err := client.GetProviderRegistryVersions(ctx, httpClient, parts[0], parts[1], logger)
+		if err != nil {
+			compatibility[source] = ProviderCompatibility{Source: source, Note: "Not found in the public registry, check its compatibility manually"}
+			continue
+		}
+		compatibility[source] = releaseCompatibility(source, releases)
+	}
+	return compatibility
+}
+
+// releaseCompatibility derives the Terraform versions supported by the latest stable release of a
+// provider from its plugin protocols: protocol 4 runs on Terraform 0.11 and earlier, protocol 5 on
+// 0.12 and later, and protocol 6 on 1.0 and later.
+func releaseCompatibility(source string, releases []client.ProviderRegistryVersion) ProviderCompatibility {
+	result := ProviderCompatibility{Source: source}
+	// A provider is blocked when its releases report protocols, all older than protocol 5
+	reportsProtocols, supportsProtocol5 := false, false
+	var latest *version.Version
+	for _, release := range releases {
+		releaseVersion, err := version.NewVersion(release.Version)
+		if err != nil || releaseVersion.Prerelease() != "" {
+			continue
+		}
+		for _, protocol := range release.Protocols {
+			reportsProtocols = true
+			if !strings.HasPrefix(protocol, "4") {
+				supportsProtocol5 = true
+			}
+		}
+		if latest == nil || releaseVersion.GreaterThan(latest) {
+			latest = releaseVersion
+			result.LatestVersion = release.Version
+			result.Protocols = release.Protocols
+		}
+	}
+	if latest == nil {
+		return ProviderCompatibility{Source: source, Note: "No stable release in the public registry"}
+	}
+
+	for _, protocol := range result.Protocols {
+		minimum := ""
+		switch {
+		case strings.HasPrefix(protocol, "4"):
+			minimum = "0.10.0"
+		case strings.HasPrefix(protocol, "5"):
+			minimum = "0.12.0"
+		case strings.HasPrefix(protocol, "6"):
+			minimum = "1.0.0"
+		}
+		if minimum != "" && (result.MinTerraformVersion == "" || version.Must(version.NewVersion(minimum)).LessThan(version.Must(version.NewVersion(result.MinTerraformVersion)))) {
+			result.MinTerraformVersion = minimum
+		}
+	}
+	if reportsProtocols && !supportsProtocol5 {
+		result.Blocked = true
+		result.Note = "No release supports Terraform 0.12 or later, replace the provider before upgrading"
+	}
+	return result
+}
+
+// upgradePath returns the releases to upgrade through from current to target, ending with target,
+// and what changes on the way
+func upgradePath(current, target *version.Version) ([]string, []string) {
+	var path, notes []string
+	for _, stop := range upgradeStops {
+		stopVersion := version.Must(version.NewVersion(stop.version))
+		stopMinor := version.Must(version.NewVersion(fmt.Sprintf("%d.%d.0", stopVersion.Segments()[0], stopVersion.Segments()[1])))
+		if current.GreaterThanOrEqual(stopMinor) || target.LessThan(stopMinor) {
+			continue
+		}
+		notes = append(notes, stop.note)
+		if stopVersion.LessThan(target) {
+			path = append(path, stop.version)
+		}
+	}
+	sensitiveOutputs := version.Must(version.NewVersion("0.15.0"))
+	if current.LessThan(sensitiveOutputs) && target.GreaterThanOrEqual(sensitiveOutputs) {
+		notes = append(notes, "0.15 propagates sensitive provider attributes, outputs referencing them must set sensitive = true")
+	}
+	return append(path, target.String()), notes
+}
+
+// planUpgrade groups the outdated workspaces into waves. Waves are ordered by risk and then by current
+// version, newest first, so the smallest upgrades go first; the workspaces of a wave are ordered by
+// risk and resource count, so the canary is the safest and smallest workspace.
+func planUpgrade(organization string, target *version.Version, workspaces []*tfe.Workspace, providers map[string][]string, compatibility map[string]ProviderCompatibility) TerraformUpgradePlan {
+	plan := TerraformUpgradePlan{
+		Organization:  organization,
+		TargetVersion: target.String(),
+		Checked:       len(workspaces),
+		Waves:         []UpgradeWave{},
+	}
+
+	byVersion := make(map[string]*UpgradeWave)
+	currentVersions := make(map[string]*version.Version)
+	for _, workspace := range workspaces {
+		current, err := version.NewVersion(workspace.TerraformVersion)
+		if err != nil {
+			plan.Skipped = append(plan.Skipped, SkippedWorkspace{
+				WorkspaceID:      workspace.ID,
+				WorkspaceName:    workspace.Name,
+				TerraformVersion: workspace.TerraformVersion,
+				Reason:           "The workspace does not pin a Terraform version",
+			})
+			continue
+		}
+		if current.GreaterThanOrEqual(target) {
+			plan.UpToDate++
+			continue
+		}
+
+		wave, ok := byVersion[current.String()]
+		if !ok {
+			path, notes := upgradePath(current, target)
+			wave = &UpgradeWave{FromVersion: current.String(), UpgradePath: path, PathNotes: notes, Risk: upgradeRiskLow}
+			byVersion[current.String()] = wave
+			currentVersions[current.String()] = current
+		}
+		upgrade := workspaceUpgrade(workspace, current, len(wave.UpgradePath), providers, compatibility)
+		if upgradeRiskRank[upgrade.Risk] > upgradeRiskRank[wave.Risk] {
+			wave.Risk = upgrade.Risk
+		}
+		wave.Workspaces = append(wave.Workspaces, upgrade)
+	}
+
+	for _, wave := range byVersion {
+		sort.SliceStable(wave.Workspaces, func(a, b int) bool {
+			left, right := wave.Workspaces[a], wave.Workspaces[b]
+			if left.Risk != right.Risk {
+				return upgradeRiskRank[left.Risk] < upgradeRiskRank[right.Risk]
+			}
+			if left.ResourceCount != right.ResourceCount {
+				return left.ResourceCount < right.ResourceCount
+			}
+			return left.WorkspaceName < right.WorkspaceName
+		})
+		wave.Canary = wave.Workspaces[0].WorkspaceName
+		plan.Waves = append(plan.Waves, *wave)
+	}
+	sort.SliceStable(plan.Waves, func(a, b int) bool {
+		left, right := plan.Waves[a], plan.Waves[b]
+		if left.Risk != right.Risk {
+			return upgradeRiskRank[left.Risk] < upgradeRiskRank[right.Risk]
+		}
+		return currentVersions[left.FromVersion].GreaterThan(currentVersions[right.FromVersion])
+	})
+	for i := range plan.Waves {
+		plan.Waves[i].Wave = i + 1
+	}
+	sort.SliceStable(plan.Skipped, func(a, b int) bool { return plan.Skipped[a].WorkspaceName < plan.Skipped[b].WorkspaceName })
+
+	for _, provider := range compatibility {
+		plan.Providers = append(plan.Providers, provider)
+	}
+	sort.SliceStable(plan.Providers, func(a, b int) bool { return plan.Providers[a].Source < plan.Providers[b].Source })
+	return plan
+}
+
+// workspaceUpgrade rates the risk of upgrading a workspace through steps releases
+func workspaceUpgrade(workspace *tfe.Workspace, current *version.Version, steps int, providers map[string][]string, compatibility map[string]ProviderCompatibility) WorkspaceUpgrade {
+	upgrade := WorkspaceUpgrade{
+		WorkspaceID:   workspace.ID,
+		WorkspaceName: workspace.Name,
+		ResourceCount: workspace.ResourceCount,
+		AutoApply:     workspace.AutoApply,
+		Risk:          upgradeRiskLow,
+	}
+	raise := func(risk, note string) {
+		if upgradeRiskRank[risk] > upgradeRiskRank[upgrade.Risk] {
+			upgrade.Risk = risk
+		}
+		upgrade.Notes = append(upgrade.Notes, note)
+	}
+
+	if current.LessThan(version.Must(version.NewVersion("0.12.0"))) {
+		raise(upgradeRiskHigh, "The configuration must be rewritten for the 0.12 language")
+	}
+	if steps > 1 {
+		raise(upgradeRiskMedium, fmt.Sprintf("%d upgrades are needed, with a run on each intermediate release", steps))
+	}
+	if workspace.AutoApply {
+		raise(upgradeRiskMedium, "Auto apply is enabled, disable it so the first run on each release can be reviewed")
+	}
+	if workspace.ResourceCount == 0 {
+		upgrade.Notes = append(upgrade.Notes, "The workspace manages no resources")
+	}
+
+	if providers != nil {
+		workspaceProviders, ok := providers[workspace.ID]
+		if !ok {
+			raise(upgradeRiskMedium, "The providers could not be read from the current state, check them before upgrading")
+		}
+		upgrade.Providers = workspaceProviders
+		for _, source := range workspaceProviders {
+			provider, ok := compatibility[source]
+			if !ok {
+				continue
+			}
+			switch {
+			case provider.Blocked:
+				raise(upgradeRiskHigh, fmt.Sprintf("%s: %s", source, provider.Note))
+			case provider.Note != "":
+				upgrade.Notes = append(upgrade.Notes, fmt.Sprintf("%s: %s", source, provider.Note))
+			case provider.MinTerraformVersion != "" && current.LessThan(version.Must(version.NewVersion(provider.MinTerraformVersion))):
+				upgrade.Notes = append(upgrade.Notes, fmt.Sprintf("%s: the latest release %s needs Terraform %s or later, the upgrade unblocks it", source, provider.LatestVersion, provider.MinTerraformVersion))
+			}
+		}
+	}
+	return upgrade
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
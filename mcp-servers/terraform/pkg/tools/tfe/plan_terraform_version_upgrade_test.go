@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradePath(t *testing.T) {
+	target := version.Must(version.NewVersion("1.9.8"))
+
+	path, notes := upgradePath(version.Must(version.NewVersion("0.11.14")), target)
+	assert.Equal(t, []string{"0.12.31", "0.13.7", "0.14.11", "1.9.8"}, path)
+	assert.Len(t, notes, 4)
+
+	path, notes = upgradePath(version.Must(version.NewVersion("0.13.5")), target)
+	assert.Equal(t, []string{"0.14.11", "1.9.8"}, path)
+	assert.Len(t, notes, 2)
+
+	path, notes = upgradePath(version.Must(version.NewVersion("1.5.7")), target)
+	assert.Equal(t, []string{"1.9.8"}, path)
+	assert.Empty(t, notes)
+
+	// The last stop is not repeated when it is the target
+	path, _ = upgradePath(version.Must(version.NewVersion("0.12.31")), version.Must(version.NewVersion("0.13.7")))
+	assert.Equal(t, []string{"0.13.7"}, path)
+}
+
+func TestProviderSource(t *testing.T) {
+	assert.Equal(t, "hashicorp/aws", providerSource(`provider["registry.terraform.io/hashicorp/aws"]`))
+	assert.Equal(t, "app.terraform.io/acme/internal", providerSource(`provider["app.terraform.io/acme/internal"]`))
+	assert.Equal(t, "hashicorp/google", providerSource("provider.google.europe"))
+	assert.Equal(t, "", providerSource(""))
+}
+
+func TestReleaseCompatibility(t *testing.T) {
+	compatibility := releaseCompatibility("hashicorp/aws", []client.ProviderRegistryVersion{
+		{Version: "6.0.0-beta1", Protocols: []string{"6.0"}},
+		{Version: "5.80.0", Protocols: []string{"5.0"}},
+		{Version: "2.70.0", Protocols: []string{"4.0", "5.0"}},
+	})
+	assert.Equal(t, "5.80.0", compatibility.LatestVersion)
+	assert.Equal(t, "0.12.0", compatibility.MinTerraformVersion)
+	assert.False(t, compatibility.Blocked)
+
+	compatibility = releaseCompatibility("acme/legacy", []client.ProviderRegistryVersion{{Version: "1.2.0", Protocols: []string{"4.0"}}})
+	assert.True(t, compatibility.Blocked)
+	assert.Equal(t, "0.10.0", compatibility.MinTerraformVersion)
+
+	compatibility = releaseCompatibility("hashicorp/random", []client.ProviderRegistryVersion{{Version: "3.6.0", Protocols: []string{"6.0"}}})
+	assert.Equal(t, "1.0.0", compatibility.MinTerraformVersion)
+}
+
+func TestPlanUpgrade(t *testing.T) {
+	workspaces := []*tfe.Workspace{
+		{ID: "ws-1", Name: "network", TerraformVersion: "1.5.7", ResourceCount: 40},
+		{ID: "ws-2", Name: "dns", TerraformVersion: "1.5.7", ResourceCount: 3},
+		{ID: "ws-3", Name: "billing", TerraformVersion: "0.11.14", ResourceCount: 10},
+		{ID: "ws-4", Name: "app", TerraformVersion: "0.13.5", ResourceCount: 5, AutoApply: true},
+		{ID: "ws-5", Name: "current", TerraformVersion: "1.9.8"},
+		{ID: "ws-6", Name: "floating", TerraformVersion: "latest"},
+	}
+	providers := map[string][]string{
+		"ws-1": {"hashicorp/aws"},
+		"ws-2": {"hashicorp/aws", "hashicorp/random"},
+		"ws-3": {"acme/legacy"},
+	}
+	compatibility := map[string]ProviderCompatibility{
+		"hashicorp/aws":    {Source: "hashicorp/aws", LatestVersion: "5.80.0", MinTerraformVersion: "0.12.0"},
+		"hashicorp/random": {Source: "hashicorp/random", LatestVersion: "3.6.0", MinTerraformVersion: "1.0.0"},
+		"acme/legacy":      {Source: "acme/legacy", LatestVersion: "1.2.0", Blocked: true, Note: "No release supports Terraform 0.12 or later"},
+	}
+
+	plan := planUpgrade("acme", version.Must(version.NewVersion("1.9.8")), workspaces, providers, compatibility)
+
+	assert.Equal(t, 6, plan.Checked)
+	assert.Equal(t, 1, plan.UpToDate)
+	require.Len(t, plan.Skipped, 1)
+	assert.Equal(t, "floating", plan.Skipped[0].WorkspaceName)
+	require.Len(t, plan.Waves, 3)
+
+	// Lowest risk first, the smallest workspace is the canary
+	assert.Equal(t, 1, plan.Waves[0].Wave)
+	assert.Equal(t, "1.5.7", plan.Waves[0].FromVersion)
+	assert.Equal(t, upgradeRiskLow, plan.Waves[0].Risk)
+	assert.Equal(t, "dns", plan.Waves[0].Canary)
+	assert.Equal(t, []string{"1.9.8"}, plan.Waves[0].UpgradePath)
+
+	// The providers of ws-4 could not be read, and it has auto apply enabled
+	assert.Equal(t, "0.13.5", plan.Waves[1].FromVersion)
+	assert.Equal(t, upgradeRiskMedium, plan.Waves[1].Risk)
+	assert.Len(t, plan.Waves[1].Workspaces[0].Notes, 3)
+
+	assert.Equal(t, "0.11.14", plan.Waves[2].FromVersion)
+	assert.Equal(t, upgradeRiskHigh, plan.Waves[2].Risk)
+	assert.Contains(t, plan.Waves[2].Workspaces[0].Notes, "acme/legacy: No release supports Terraform 0.12 or later")
+
+	require.Len(t, plan.Providers, 3)
+	assert.Equal(t, "acme/legacy", plan.Providers[0].Source)
+}
+
+func TestReadWorkspaceProviders(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.Header().Set("TFP-API-Version", "2.5")
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/workspaces/ws-1/current-state-version":
+			_, _ = w.Write([]byte(`{"data": {"id": "sv-1", "type": "state-versions", "attributes": {"resources-processed": true, "resources": [
+			  {"name": "vpc", "type": "aws_vpc", "count": 1, "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]"},
+			  {"name": "subnet", "type": "aws_subnet", "count": 3, "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]"},
+			  {"name": "id", "type": "random_id", "count": 1, "provider": "provider[\"registry.terraform.io/hashicorp/random\"]"}
+			]}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tfeClient, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", RetryServerErrors: false})
+	require.NoError(t, err)
+
+	providers := readWorkspaceProviders(t.Context(), tfeClient, []*tfe.Workspace{{ID: "ws-1", Name: "network"}, {ID: "ws-2", Name: "empty"}}, logger)
+	assert.Equal(t, map[string][]string{"ws-1": {"hashicorp/aws", "hashicorp/random"}}, providers)
+}
+
+func TestPlanTerraformVersionUpgradeTool(t *testing.T) {
+	tool := PlanTerraformVersionUpgrade(log.New())
+
+	assert.Equal(t, "plan_terraform_version_upgrade", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, []string{"terraform_org_name", "target_version"}, tool.Tool.InputSchema.Required)
+}
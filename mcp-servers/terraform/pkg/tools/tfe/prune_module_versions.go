@@ -0,0 +1,312 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultKeepModuleVersions is the number of most recent versions prune_module_versions keeps by default
+	defaultKeepModuleVersions = 10
+	// maxPrunedModuleVersions bounds the versions deleted by one call, the oldest first
+	maxPrunedModuleVersions = 100
+)
+
+// PrunedModuleVersion is a module version selected for deletion, with the error that prevented it
+type PrunedModuleVersion struct {
+	Version   string `json:"version"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at,omitempty"`
+	Deleted   bool   `json:"deleted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PruneModuleVersionsResult is the result of the prune_module_versions tool
+type PruneModuleVersionsResult struct {
+	Organization string                `json:"organization"`
+	Module       string                `json:"module"`
+	Total        int                   `json:"total"`
+	Kept         int                   `json:"kept"`
+	Deleted      int                   `json:"deleted"`
+	Failed       int                   `json:"failed"`
+	Remaining    int                   `json:"remaining,omitempty"`
+	Versions     []PrunedModuleVersion `json:"versions"`
+}
+
+// PruneModuleVersions creates a tool to delete the old versions of a private registry module.
+func PruneModuleVersions(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("prune_module_versions",
+			mcp.WithDescription(fmt.Sprintf(`Deletes the old versions of a module of the private registry of a Terraform organization, e.g. for modules published on every merge. The 'keep_latest' most recent versions that were published successfully are always kept, as well as the versions listed in 'keep_versions' and, when 'older_than_days' is set, the versions published more recently. Versions whose publication failed do not count towards 'keep_latest'.
+At most %d versions are deleted at once, the oldest first; call the tool again to delete the remaining ones. Deleted versions cannot be restored and the workspaces still pinned to them fail to initialize: use 'find_deprecated_module_consumers' or 'find_module_consumers' first. Call the tool with dry_run to review the versions to delete. The first call without it returns the versions and a confirmation token, and they are only deleted when the tool is called again with the token.`, maxPrunedModuleVersions)),
+			mcp.WithTitleAnnotation("Delete old private module versions"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("private_module_id",
+				mcp.Required(),
+				mcp.Description("The private module ID in the format 'module-namespace/module-name/module-provider-name', e.g. 'my-tfc-org/vpc/aws'"),
+			),
+			mcp.WithNumber("keep_latest",
+				mcp.Description("The number of most recent successfully published versions to keep"),
+				mcp.DefaultNumber(defaultKeepModuleVersions),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("older_than_days",
+				mcp.Description("Only delete versions published more than this number of days ago. 0 deletes versions of any age"),
+				mcp.DefaultNumber(0),
+				mcp.Min(0),
+			),
+			mcp.WithString("keep_versions",
+				mcp.Description("Comma-separated list of versions never to delete, e.g. '1.0.0, 2.3.1'"),
+			),
+			withConfirmationToken(),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return pruneModuleVersionsHandler(ctx, request, logger)
+		},
+	}
+}
+
+func pruneModuleVersionsHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil || strings.TrimSpace(terraformOrgName) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+	moduleID, err := request.RequireString("private_module_id")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'private_module_id' parameter is required", err)
+	}
+	moduleID = strings.TrimSpace(moduleID)
+	parts := strings.Split(moduleID, "/")
+	if len(parts) != 3 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "private_module_id must be in the format 'module-namespace/module-name/module-provider-name'", nil)
+	}
+	keepLatest := request.GetInt("keep_latest", defaultKeepModuleVersions)
+	if keepLatest < 1 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "keep_latest must be at least 1", nil)
+	}
+	olderThanDays := request.GetInt("older_than_days", 0)
+	if olderThanDays < 0 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "older_than_days cannot be negative", nil)
+	}
+	keep := make(map[string]bool)
+	for _, kept := range strings.Split(request.GetString("keep_versions", ""), ",") {
+		if kept = strings.TrimPrefix(strings.TrimSpace(kept), "v"); kept != "" {
+			keep[kept] = true
+		}
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+
+	registryModuleID := tfe.RegistryModuleID{
+		Organization: terraformOrgName,
+		Namespace:    parts[0],
+		Name:         parts[1],
+		Provider:     parts[2],
+		RegistryName: tfe.PrivateRegistry,
+	}
+	module, err := tfeClient.RegistryModules.Read(ctx, registryModuleID)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading private module %s", moduleID), err)
+	}
+
+	candidates := pruneCandidates(module.VersionStatuses, keepLatest, keep)
+	if olderThanDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -olderThanDays)
+		if candidates, err = publishedBefore(ctx, tfeClient, registryModuleID, candidates, cutoff); err != nil {
+			return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading the publication dates of module %s", moduleID), err)
+		}
+	}
+	result := PruneModuleVersionsResult{
+		Organization: terraformOrgName,
+		Module:       moduleID,
+		Total:        len(module.VersionStatuses),
+		Kept:         len(module.VersionStatuses) - len(candidates),
+		Versions:     candidates,
+	}
+	if len(candidates) > maxPrunedModuleVersions {
+		result.Remaining = len(candidates) - maxPrunedModuleVersions
+		result.Versions = candidates[:maxPrunedModuleVersions]
+	}
+
+	if request.GetBool(dryRunParam, false) {
+		dryRun := BulkDryRunResult{DryRun: true, Total: len(result.Versions), Requests: make([]DryRunResult, 0, len(result.Versions))}
+		for _, candidate := range result.Versions {
+			effect := fmt.Sprintf("Deletes version %s of module %s", candidate.Version, moduleID)
+			if candidate.CreatedAt != "" {
+				effect += ", published " + candidate.CreatedAt
+			}
+			deleteRequest, err := newDryRun(request, "DELETE", client.ModuleVersionPath(registryModuleID, candidate.Version), nil, []string{effect})
+			if err != nil {
+				return nil, utils.LogAndReturnError(logger, "encoding dry run payload", err)
+			}
+			dryRun.Requests = append(dryRun.Requests, deleteRequest)
+		}
+		return bulkResult(dryRun, logger)
+	}
+	if len(result.Versions) == 0 {
+		return bulkResult(result, logger)
+	}
+
+	versions := make([]string, 0, len(result.Versions))
+	for _, candidate := range result.Versions {
+		versions = append(versions, candidate.Version)
+	}
+	details := map[string]any{
+		"organization": terraformOrgName,
+		"module":       moduleID,
+		"versions":     versions,
+		"kept":         result.Kept,
+	}
+	summary := fmt.Sprintf("delete %d versions of module %s, from %s to %s, keeping %d", len(versions), moduleID, versions[0], versions[len(versions)-1], result.Kept)
+	if confirmation, err := requireConfirmation(ctx, request, summary, details, logger); confirmation != nil || err != nil {
+		return confirmation, err
+	}
+
+	deleteModuleVersions(ctx, tfeClient, registryModuleID, result.Versions, logger)
+	for _, candidate := range result.Versions {
+		if candidate.Deleted {
+			result.Deleted++
+		} else {
+			result.Failed++
+		}
+	}
+	return bulkResult(result, logger)
+}
+
+// pruneCandidates returns the versions to delete, the oldest first: all but the keepLatest most recent
+// versions published successfully and the versions of keep
+func pruneCandidates(statuses []tfe.RegistryModuleVersionStatuses, keepLatest int, keep map[string]bool) []PrunedModuleVersion {
+	sorted := make([]tfe.RegistryModuleVersionStatuses, len(statuses))
+	copy(sorted, statuses)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareVersionStrings(sorted[i].Version, sorted[j].Version) > 0
+	})
+
+	candidates := []PrunedModuleVersion{}
+	kept := 0
+	for _, status := range sorted {
+		if status.Status == tfe.RegistryModuleVersionStatusOk && kept < keepLatest {
+			kept++
+			continue
+		}
+		if keep[status.Version] {
+			continue
+		}
+		candidates = append(candidates, PrunedModuleVersion{Version: status.Version, Status: string(status.Status)})
+	}
+	for i, j := 0, len(candidates)-1; i < j; i, j = i+1, j-1 {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+	return candidates
+}
+
+// compareVersionStrings orders versions semantically, with the versions that cannot be parsed first
+func compareVersionStrings(a, b string) int {
+	va, errA := version.NewVersion(a)
+	vb, errB := version.NewVersion(b)
+	switch {
+	case errA == nil && errB == nil:
+		return va.Compare(vb)
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// publishedBefore reads the publication date of the candidates and keeps the ones published before cutoff
+func publishedBefore(ctx context.Context, tfeClient *tfe.Client, moduleID tfe.RegistryModuleID, candidates []PrunedModuleVersion, cutoff time.Time) ([]PrunedModuleVersion, error) {
+	var mu sync.Mutex
+	var firstErr error
+	semaphore := make(chan struct{}, defaultBulkRunConcurrency)
+	var wg sync.WaitGroup
+	for i := range candidates {
+		candidate := &candidates[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			moduleVersion, err := tfeClient.RegistryModules.ReadVersion(ctx, moduleID, candidate.Version)
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("version %s: %w", candidate.Version, err)
+				}
+				return
+			}
+			candidate.CreatedAt = moduleVersion.CreatedAt
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	old := []PrunedModuleVersion{}
+	for _, candidate := range candidates {
+		// Versions without a readable publication date are kept, as their age cannot be checked
+		createdAt, err := time.Parse(time.RFC3339, candidate.CreatedAt)
+		if err == nil && createdAt.Before(cutoff) {
+			old = append(old, candidate)
+		}
+	}
+	return old, nil
+}
+
+// deleteModuleVersions deletes the versions, defaultBulkRunConcurrency at a time, recording the error of
+// the versions that could not be deleted
+func deleteModuleVersions(ctx context.Context, tfeClient *tfe.Client, moduleID tfe.RegistryModuleID, versions []PrunedModuleVersion, logger *log.Logger) {
+	semaphore := make(chan struct{}, defaultBulkRunConcurrency)
+	var wg sync.WaitGroup
+	for i := range versions {
+		candidate := &versions[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if err := ctx.Err(); err != nil {
+				candidate.Error = err.Error()
+				return
+			}
+			if err := tfeClient.RegistryModules.DeleteVersion(ctx, moduleID, candidate.Version); err != nil {
+				logger.WithField("version", candidate.Version).Warnf("Failed to delete module version: %v", err)
+				candidate.Error = err.Error()
+				return
+			}
+			candidate.Deleted = true
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneCandidates(t *testing.T) {
+	statuses := []tfe.RegistryModuleVersionStatuses{
+		{Version: "1.10.0", Status: tfe.RegistryModuleVersionStatusOk},
+		{Version: "1.2.0", Status: tfe.RegistryModuleVersionStatusOk},
+		{Version: "1.9.0", Status: tfe.RegistryModuleVersionStatusOk},
+		{Version: "1.11.0", Status: tfe.RegistryModuleVersionStatusRegIngressFailed},
+		{Version: "1.0.0", Status: tfe.RegistryModuleVersionStatusOk},
+		{Version: "1.1.0", Status: tfe.RegistryModuleVersionStatusOk},
+	}
+
+	versions := func(candidates []PrunedModuleVersion) []string {
+		names := []string{}
+		for _, candidate := range candidates {
+			names = append(names, candidate.Version)
+		}
+		return names
+	}
+
+	// The failed 1.11.0 does not count towards the kept versions, and the oldest versions come first
+	assert.Equal(t, []string{"1.0.0", "1.1.0", "1.2.0", "1.11.0"}, versions(pruneCandidates(statuses, 2, nil)))
+	assert.Equal(t, []string{"1.1.0", "1.11.0"}, versions(pruneCandidates(statuses, 2, map[string]bool{"1.0.0": true, "1.2.0": true})))
+	assert.Equal(t, []string{"1.11.0"}, versions(pruneCandidates(statuses, 10, nil)))
+}
+
+func TestPruneModuleVersions(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := PruneModuleVersions(logger)
+	assert.Equal(t, "prune_module_versions", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.DestructiveHint)
+	assert.True(t, *tool.Tool.Annotations.DestructiveHint)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, confirmationTokenParam)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, dryRunParam)
+
+	now := time.Now()
+	published := map[string]time.Time{
+		"1.0.0": now.AddDate(0, 0, -90),
+		"1.1.0": now.AddDate(0, 0, -60),
+		"1.2.0": now.AddDate(0, 0, -5),
+		"1.3.0": now.AddDate(0, 0, -1),
+	}
+	newFake := func(t *testing.T) *testutil.FakeTFE {
+		fake := testutil.NewFakeTFE(t)
+		statuses := []tfe.RegistryModuleVersionStatuses{}
+		for version := range published {
+			statuses = append(statuses, tfe.RegistryModuleVersionStatuses{Version: version, Status: tfe.RegistryModuleVersionStatusOk})
+		}
+		fake.Respond("GET", "/organizations/acme/registry-modules/private/acme/vpc/aws", http.StatusOK, &tfe.RegistryModule{
+			ID: "mod-123", Name: "vpc", Namespace: "acme", Provider: "aws", VersionStatuses: statuses,
+		})
+		fake.Handle("GET", "/organizations/acme/registry-modules/private/acme/vpc/aws/version", func(w http.ResponseWriter, r *http.Request) {
+			version := r.URL.Query().Get("module_version")
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = fmt.Fprintf(w, `{"data": {"id": "modver-%s", "type": "registry-module-versions", "attributes": {"version": %q, "status": "ok", "created-at": %q}}}`,
+				version, version, published[version].Format(time.RFC3339))
+		})
+		for version := range published {
+			fake.Handle("DELETE", "/organizations/acme/registry-modules/private/acme/vpc/aws/"+version, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+		}
+		return fake
+	}
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		arguments["terraform_org_name"] = "acme"
+		arguments["private_module_id"] = "acme/vpc/aws"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "prune_module_versions", Arguments: arguments}}
+	}
+
+	t.Run("deletes old versions after a confirmation", func(t *testing.T) {
+		fake := newFake(t)
+		arguments := map[string]any{"keep_latest": 1, "older_than_days": 30}
+		result, err := pruneModuleVersionsHandler(fake.Context(t), request(arguments), logger)
+		require.NoError(t, err)
+
+		var confirmation ConfirmationRequired
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &confirmation))
+		assert.Equal(t, "delete 2 versions of module acme/vpc/aws, from 1.0.0 to 1.1.0, keeping 2", confirmation.Summary)
+
+		arguments[confirmationTokenParam] = confirmation.ConfirmationToken
+		result, err = pruneModuleVersionsHandler(fake.Context(t), request(arguments), logger)
+		require.NoError(t, err)
+
+		var pruned PruneModuleVersionsResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &pruned))
+		assert.Equal(t, 4, pruned.Total)
+		assert.Equal(t, 2, pruned.Kept)
+		assert.Equal(t, 2, pruned.Deleted)
+		assert.Zero(t, pruned.Failed)
+
+		var deleted []string
+		for _, sent := range fake.Requests() {
+			if sent.Method == "DELETE" {
+				deleted = append(deleted, sent.Path)
+			}
+		}
+		sort.Strings(deleted)
+		assert.Equal(t, []string{
+			"/organizations/acme/registry-modules/private/acme/vpc/aws/1.0.0",
+			"/organizations/acme/registry-modules/private/acme/vpc/aws/1.1.0",
+		}, deleted)
+	})
+
+	t.Run("returns the requests of a dry run", func(t *testing.T) {
+		fake := newFake(t)
+		result, err := pruneModuleVersionsHandler(fake.Context(t), request(map[string]any{"keep_latest": 2, "keep_versions": "1.0.0", dryRunParam: true}), logger)
+		require.NoError(t, err)
+
+		var dryRun BulkDryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &dryRun))
+		require.Len(t, dryRun.Requests, 1)
+		assert.Equal(t, "DELETE", dryRun.Requests[0].Method)
+		assert.Equal(t, "/api/v2/organizations/acme/registry-modules/private/acme/vpc/aws/1.1.0", dryRun.Requests[0].Path)
+		for _, sent := range fake.Requests() {
+			assert.Equal(t, "GET", sent.Method)
+		}
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		for name, arguments := range map[string]map[string]any{
+			"invalid module":   {"private_module_id": "vpc/aws"},
+			"keep nothing":     {"keep_latest": 0},
+			"negative max age": {"older_than_days": -1},
+		} {
+			arguments["terraform_org_name"] = "acme"
+			if _, ok := arguments["private_module_id"]; !ok {
+				arguments["private_module_id"] = "acme/vpc/aws"
+			}
+			_, err := pruneModuleVersionsHandler(t.Context(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}}, logger)
+			assert.Equal(t, utils.ErrorCodeInvalidInput, utils.ErrorCodeOf(err), name)
+		}
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxReportWorkspaces bounds the number of workspaces read by the organization-wide reports
+	maxReportWorkspaces = 5000
+	// driftCheckConcurrency is the number of assessment results read at the same time
+	driftCheckConcurrency = 5
+)
+
+// assessmentResult is the latest health assessment of a workspace
+type assessmentResult struct {
+	ID        string    `jsonapi:"primary,assessment-results"`
+	Drifted   bool      `jsonapi:"attr,drifted"`
+	Succeeded bool      `jsonapi:"attr,succeeded"`
+	ErrorMsg  string    `jsonapi:"attr,error-msg"`
+	CreatedAt time.Time `jsonapi:"attr,created-at,iso8601"`
+}
+
+// WorkspaceReportRow summarizes one workspace of an organization report
+type WorkspaceReportRow struct {
+	WorkspaceID      string `json:"workspace_id"`
+	WorkspaceName    string `json:"workspace_name"`
+	ProjectID        string `json:"project_id,omitempty"`
+	TerraformVersion string `json:"terraform_version"`
+	ExecutionMode    string `json:"execution_mode"`
+	Locked           bool   `json:"locked"`
+	ResourceCount    int    `json:"resource_count"`
+	CurrentRunStatus string `json:"current_run_status,omitempty"`
+	Failing          bool   `json:"failing"`
+	RunFailures      int    `json:"run_failures"`
+	// Drifted is nil when the workspace has no health assessment
+	Drifted *bool `json:"drifted,omitempty"`
+}
+
+// WorkspaceReport is the result of the report_org_workspaces tool
+type WorkspaceReport struct {
+	Organization       string               `json:"organization"`
+	WorkspaceCount     int                  `json:"workspace_count"`
+	ResourceCount      int                  `json:"resource_count"`
+	LockedCount        int                  `json:"locked_count"`
+	FailingCount       int                  `json:"failing_count"`
+	DriftedCount       int                  `json:"drifted_count"`
+	AssessedCount      int                  `json:"assessed_count"`
+	ByTerraformVersion map[string]int       `json:"by_terraform_version"`
+	ByExecutionMode    map[string]int       `json:"by_execution_mode"`
+	ByCurrentRunStatus map[string]int       `json:"by_current_run_status"`
+	Truncated          bool                 `json:"truncated,omitempty"`
+	Workspaces         []WorkspaceReportRow `json:"workspaces,omitempty"`
+}
+
+// ReportOrgWorkspaces creates a tool to summarize all the workspaces of an organization.
+func ReportOrgWorkspaces(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("report_org_workspaces",
+			mcp.WithDescription(fmt.Sprintf(`Aggregates the workspaces of a Terraform organization into a summary: counts by Terraform version, execution mode and current run status, and the number of locked, failing and drifted workspaces and managed resources.
+Use it for platform-wide questions such as "which workspaces still run Terraform 0.x?" instead of paging through list_workspaces. At most %d workspaces are read.
+The JSON format returns the summary, with one row per workspace when include_workspaces is set; the CSV format returns one row per workspace.`, maxReportWorkspaces)),
+			mcp.WithTitleAnnotation("Summarize the workspaces of a Terraform organization"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("project_id",
+				mcp.Description("Only report the workspaces of this project"),
+			),
+			mcp.WithString("format",
+				mcp.Description("The output format"),
+				mcp.Enum("json", "csv"),
+				mcp.DefaultString("json"),
+			),
+			mcp.WithBoolean("include_workspaces",
+				mcp.Description("Include one row per workspace in the JSON summary"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("check_drift",
+				mcp.Description("Read the latest health assessment of the workspaces with assessments enabled to count drifted workspaces, one extra API call per workspace"),
+				mcp.DefaultBool(true),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return reportOrgWorkspacesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func reportOrgWorkspacesHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+	projectID := strings.TrimSpace(request.GetString("project_id", ""))
+	format := request.GetString("format", "json")
+	if format != "json" && format != "csv" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "validating format", fmt.Errorf("format must be 'json' or 'csv', got %q", format))
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	workspaces, truncated, err := listOrgWorkspaces(ctx, tfeClient, terraformOrgName, projectID)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing workspaces in organization", err)
+	}
+	rows := make([]WorkspaceReportRow, len(workspaces))
+	for i, workspace := range workspaces {
+		rows[i] = workspaceReportRow(workspace)
+	}
+	if request.GetBool("check_drift", true) {
+		checkDrift(ctx, tfeClient, workspaces, rows, logger)
+	}
+
+	if format == "csv" {
+		csvReport, err := workspaceReportCSV(rows)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "writing workspace report", err)
+		}
+		return mcp.NewToolResultText(csvReport), nil
+	}
+
+	report := summarizeWorkspaces(terraformOrgName, rows)
+	report.Truncated = truncated
+	if request.GetBool("include_workspaces", false) {
+		report.Workspaces = rows
+	}
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling workspace report", err)
+	}
+	return mcp.NewToolResultText(string(reportJSON)), nil
+}
+
+// listOrgWorkspaces lists up to maxReportWorkspaces workspaces of an organization with their current run
+func listOrgWorkspaces(ctx context.Context, tfeClient *tfe.Client, organization, projectID string) ([]*tfe.Workspace, bool, error) {
+	options := &tfe.WorkspaceListOptions{
+		ListOptions: tfe.ListOptions{PageNumber: 1, PageSize: 100},
+		ProjectID:   projectID,
+		Include:     []tfe.WSIncludeOpt{tfe.WSCurrentRun},
+	}
+
+	var workspaces []*tfe.Workspace
+	for {
+		page, err := tfeClient.Workspaces.List(ctx, organization, options)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, workspace := range page.Items {
+			if len(workspaces) == maxReportWorkspaces {
+				return workspaces, true, nil
+			}
+			workspaces = append(workspaces, workspace)
+		}
+		if page.Pagination == nil || page.NextPage == 0 {
+			return workspaces, false, nil
+		}
+		options.PageNumber = page.NextPage
+	}
+}
+
+func workspaceReportRow(workspace *tfe.Workspace) WorkspaceReportRow {
+	row := WorkspaceReportRow{
+		WorkspaceID:      workspace.ID,
+		WorkspaceName:    workspace.Name,
+		TerraformVersion: workspace.TerraformVersion,
+		ExecutionMode:    workspace.ExecutionMode,
+		Locked:           workspace.Locked,
+		ResourceCount:    workspace.ResourceCount,
+		RunFailures:      workspace.RunFailures,
+	}
+	if workspace.Project != nil {
+		row.ProjectID = workspace.Project.ID
+	}
+	if workspace.CurrentRun != nil {
+		row.CurrentRunStatus = string(workspace.CurrentRun.Status)
+		row.Failing = workspace.CurrentRun.Status == tfe.RunErrored
+	}
+	return row
+}
+
+// checkDrift reads the latest assessment of the workspaces with health assessments enabled.
+// Workspaces without an assessment, or whose assessment cannot be read, are left unassessed.
+func checkDrift(ctx context.Context, tfeClient *tfe.Client, workspaces []*tfe.Workspace, rows []WorkspaceReportRow, logger *log.Logger) {
+	semaphore := make(chan struct{}, driftCheckConcurrency)
+	var wg sync.WaitGroup
+	for i, workspace := range workspaces {
+		if !workspace.AssessmentsEnabled {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			row := &rows[i]
+			req, err := tfeClient.NewRequest("GET", fmt.Sprintf("workspaces/%s/current-assessment-result", row.WorkspaceID), nil)
+			if err != nil {
+				return
+			}
+			result := &assessmentResult{}
+			if err := req.Do(ctx, result); err != nil {
+				if !errors.Is(err, tfe.ErrResourceNotFound) {
+					logger.WithField("workspace", row.WorkspaceName).Warnf("Failed to read the assessment result: %v", err)
+				}
+				return
+			}
+			row.Drifted = &result.Drifted
+		}()
+	}
+	wg.Wait()
+}
+
+func summarizeWorkspaces(organization string, rows []WorkspaceReportRow) WorkspaceReport {
+	report := WorkspaceReport{
+		Organization:       organization,
+		WorkspaceCount:     len(rows),
+		ByTerraformVersion: make(map[string]int),
+		ByExecutionMode:    make(map[string]int),
+		ByCurrentRunStatus: make(map[string]int),
+	}
+	for _, row := range rows {
+		report.ResourceCount += row.ResourceCount
+		report.ByTerraformVersion[defaultLabel(row.TerraformVersion)]++
+		report.ByExecutionMode[defaultLabel(row.ExecutionMode)]++
+		report.ByCurrentRunStatus[defaultLabel(row.CurrentRunStatus)]++
+		if row.Locked {
+			report.LockedCount++
+		}
+		if row.Failing {
+			report.FailingCount++
+		}
+		if row.Drifted != nil {
+			report.AssessedCount++
+			if *row.Drifted {
+				report.DriftedCount++
+			}
+		}
+	}
+	return report
+}
+
+// defaultLabel names the group of workspaces without a value, e.g. without any run
+func defaultLabel(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// workspaceReportCSV writes one row per workspace, sorted by name
+func workspaceReportCSV(rows []WorkspaceReportRow) (string, error) {
+	sorted := append([]WorkspaceReportRow(nil), rows...)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].WorkspaceName < sorted[b].WorkspaceName })
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	records := [][]string{{"workspace_id", "workspace_name", "project_id", "terraform_version", "execution_mode", "locked", "resource_count", "current_run_status", "failing", "run_failures", "drifted"}}
+	for _, row := range sorted {
+		drifted := ""
+		if row.Drifted != nil {
+			drifted = strconv.FormatBool(*row.Drifted)
+		}
+		records = append(records, []string{
+			row.WorkspaceID,
+			row.WorkspaceName,
+			row.ProjectID,
+			row.TerraformVersion,
+			row.ExecutionMode,
+			strconv.FormatBool(row.Locked),
+			strconv.Itoa(row.ResourceCount),
+			row.CurrentRunStatus,
+			strconv.FormatBool(row.Failing),
+			strconv.Itoa(row.RunFailures),
+			drifted,
+		})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reportWorkspacesPage1 = `{
+  "data": [
+    {"id": "ws-1", "type": "workspaces", "attributes": {"name": "network", "terraform-version": "1.9.0", "execution-mode": "remote", "resource-count": 12, "assessments-enabled": true},
+     "relationships": {"current-run": {"data": {"id": "run-1", "type": "runs"}}}},
+    {"id": "ws-2", "type": "workspaces", "attributes": {"name": "app", "terraform-version": "1.9.0", "execution-mode": "agent", "resource-count": 30, "locked": true, "assessments-enabled": true, "run-failures": 2},
+     "relationships": {"current-run": {"data": {"id": "run-2", "type": "runs"}}}}
+  ],
+  "included": [
+    {"id": "run-1", "type": "runs", "attributes": {"status": "applied"}},
+    {"id": "run-2", "type": "runs", "attributes": {"status": "errored"}}
+  ],
+  "meta": {"pagination": {"current-page": 1, "next-page": 2, "total-pages": 2, "total-count": 3}}
+}`
+
+const reportWorkspacesPage2 = `{
+  "data": [
+    {"id": "ws-3", "type": "workspaces", "attributes": {"name": "legacy", "terraform-version": "0.12.31", "execution-mode": "local", "resource-count": 0}}
+  ],
+  "meta": {"pagination": {"current-page": 2, "next-page": null, "total-pages": 2, "total-count": 3}}
+}`
+
+func TestReportWorkspaces(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.Header().Set("TFP-API-Version", "2.5")
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2/organizations/acme/workspaces":
+			assert.Equal(t, "current_run", r.URL.Query().Get("include"))
+			if r.URL.Query().Get("page[number]") == "2" {
+				_, _ = w.Write([]byte(reportWorkspacesPage2))
+				return
+			}
+			_, _ = w.Write([]byte(reportWorkspacesPage1))
+		case "/api/v2/workspaces/ws-1/current-assessment-result":
+			_, _ = w.Write([]byte(`{"data": {"id": "asmtres-1", "type": "assessment-results", "attributes": {"drifted": true, "succeeded": true}}}`))
+		default:
+			// ws-2 has assessments enabled but no assessment yet
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tfeClient, err := tfe.NewClient(&tfe.Config{Address: srv.URL, Token: "token", RetryServerErrors: false})
+	require.NoError(t, err)
+
+	workspaces, truncated, err := listOrgWorkspaces(t.Context(), tfeClient, "acme", "")
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	require.Len(t, workspaces, 3)
+
+	rows := make([]WorkspaceReportRow, len(workspaces))
+	for i, workspace := range workspaces {
+		rows[i] = workspaceReportRow(workspace)
+	}
+	checkDrift(t.Context(), tfeClient, workspaces, rows, logger)
+	report := summarizeWorkspaces("acme", rows)
+
+	assert.Equal(t, 3, report.WorkspaceCount)
+	assert.Equal(t, 42, report.ResourceCount)
+	assert.Equal(t, 1, report.LockedCount)
+	assert.Equal(t, 1, report.FailingCount)
+	assert.Equal(t, 1, report.DriftedCount)
+	assert.Equal(t, 1, report.AssessedCount)
+	assert.Equal(t, map[string]int{"1.9.0": 2, "0.12.31": 1}, report.ByTerraformVersion)
+	assert.Equal(t, map[string]int{"remote": 1, "agent": 1, "local": 1}, report.ByExecutionMode)
+	assert.Equal(t, map[string]int{"applied": 1, "errored": 1, "none": 1}, report.ByCurrentRunStatus)
+
+	csvReport, err := workspaceReportCSV(rows)
+	require.NoError(t, err)
+	records, err := csv.NewReader(strings.NewReader(csvReport)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, "workspace_id", records[0][0])
+	// Rows are sorted by workspace name
+	assert.Equal(t, []string{"ws-2", "app", "", "1.9.0", "agent", "true", "30", "errored", "true", "2", ""}, records[1])
+	assert.Equal(t, "legacy", records[2][1])
+	assert.Equal(t, "true", records[3][10])
+}
+
+func TestReportOrgWorkspacesTool(t *testing.T) {
+	tool := ReportOrgWorkspaces(log.New())
+
+	assert.Equal(t, "report_org_workspaces", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.Tool.InputSchema.Required, "terraform_org_name")
+	assert.Equal(t, []string{"json", "csv"}, tool.Tool.InputSchema.Properties["format"].(map[string]any)["enum"])
+}
@@ -0,0 +1,390 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// maxRunRetryAttempts bounds the runs a single retry_run call may queue
+	maxRunRetryAttempts = 5
+	// maxRunRetryWait bounds how long retry_run waits for its runs, in minutes
+	maxRunRetryWait = 120
+	// runFailureLogBytes is the size of the end of a run log searched for its failure
+	runFailureLogBytes = 64 * 1024
+)
+
+// runForceCanceled is the status of force-canceled runs, which go-tfe does not define
+const runForceCanceled tfe.RunStatus = "force_canceled"
+
+// retryRunPollInterval is the interval between two reads of a retried run
+var retryRunPollInterval = 10 * time.Second
+
+// retryableRunStatuses are the statuses of the runs retry_run re-queues
+var retryableRunStatuses = map[tfe.RunStatus]bool{
+	tfe.RunErrored:   true,
+	tfe.RunCanceled:  true,
+	runForceCanceled: true,
+}
+
+// settledRunStatuses are the statuses in which a run no longer changes without a user action
+var settledRunStatuses = map[tfe.RunStatus]bool{
+	tfe.RunApplied:            true,
+	tfe.RunPlannedAndFinished: true,
+	tfe.RunPlannedAndSaved:    true,
+	tfe.RunErrored:            true,
+	tfe.RunCanceled:           true,
+	runForceCanceled:          true,
+	tfe.RunDiscarded:          true,
+	tfe.RunPolicySoftFailed:   true,
+}
+
+// transientRunFailure matches the errors a new run is likely not to hit again, e.g. the throttling of a
+// provider API, a gateway error or a network timeout
+var transientRunFailure = regexp.MustCompile(`(?i)(throttl|rate exceeded|rate limit|too many requests|\b429\b|requestlimitexceeded|service unavailable|\b503\b|bad gateway|\b502\b|gateway timeout|\b504\b|connection reset|connection refused|i/o timeout|tls handshake timeout|timeout awaiting response headers|context deadline exceeded|temporary failure in name resolution)`)
+
+// ansiEscape matches the color codes of run logs
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// RetryRunAttempt is a run queued by retry_run
+type RetryRunAttempt struct {
+	Attempt int    `json:"attempt"`
+	RunID   string `json:"run_id"`
+	Status  string `json:"status"`
+	// Failure is the first error of the log of an errored run
+	Failure string `json:"failure,omitempty"`
+	// Transient is true when the failure looks transient, e.g. throttling, so that the run was retried
+	Transient bool `json:"transient,omitempty"`
+}
+
+// RetryRunResult is the result of the retry_run tool
+type RetryRunResult struct {
+	RetriedRunID string            `json:"retried_run_id"`
+	WorkspaceID  string            `json:"workspace_id,omitempty"`
+	Attempts     []RetryRunAttempt `json:"attempts"`
+	// RunID and Status are the last run queued and its last known status
+	RunID   string `json:"run_id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// RetryRun creates a tool to re-queue a failed run, optionally retrying transient failures.
+func RetryRun(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("retry_run",
+			mcp.WithDescription(`Re-queues an errored or canceled Terraform run with the same configuration version and options: destroy, refresh-only, plan-only, targets, replacements, auto-apply and run variables.
+With auto_retry, the tool waits for the new run and queues another one while it fails with a transient error, e.g. the throttling of a provider API, a gateway error or a network timeout, up to max_attempts runs. Other failures are not retried. Every attempt is reported with its status and failure. With auto_retry, the call only returns once the last run settles or wait_timeout_minutes elapses.`),
+			mcp.WithTitleAnnotation("Retry a failed Terraform run"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("run_id",
+				mcp.Required(),
+				mcp.Description("The ID of the errored or canceled run to retry"),
+			),
+			mcp.WithString("message",
+				mcp.Description("Optional message for the new runs, 'Retry of <run_id>' followed by the message of the retried run by default"),
+			),
+			mcp.WithBoolean("auto_retry",
+				mcp.Description("Whether to wait for the new run and retry it while it fails with a transient error"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithNumber("max_attempts",
+				mcp.Description("The maximum number of runs queued with auto_retry"),
+				mcp.DefaultNumber(3),
+				mcp.Min(1),
+				mcp.Max(maxRunRetryAttempts),
+			),
+			mcp.WithNumber("wait_timeout_minutes",
+				mcp.Description("How long to wait for the runs with auto_retry. The last run keeps going after the timeout"),
+				mcp.DefaultNumber(30),
+				mcp.Min(1),
+				mcp.Max(maxRunRetryWait),
+			),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return retryRunHandler(ctx, request, logger)
+		},
+	}
+}
+
+func retryRunHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	runID, err := request.RequireString("run_id")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'run_id' parameter is required", err)
+	}
+	runID = strings.TrimSpace(runID)
+
+	autoRetry := request.GetBool("auto_retry", false)
+	maxAttempts := request.GetInt("max_attempts", 3)
+	if maxAttempts < 1 || maxAttempts > maxRunRetryAttempts {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("max_attempts must be between 1 and %d", maxRunRetryAttempts), nil)
+	}
+	if !autoRetry {
+		maxAttempts = 1
+	}
+	waitTimeout := request.GetInt("wait_timeout_minutes", 30)
+	if waitTimeout < 1 || waitTimeout > maxRunRetryWait {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("wait_timeout_minutes must be between 1 and %d", maxRunRetryWait), nil)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client", err)
+	}
+
+	run, err := tfeClient.Runs.ReadWithOptions(ctx, runID, &tfe.RunReadOptions{Include: []tfe.RunIncludeOpt{tfe.RunWorkspace}})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading run %s", runID), err)
+	}
+	if !retryableRunStatuses[run.Status] {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("run %s is %s, only errored or canceled runs can be retried", run.ID, run.Status), nil)
+	}
+	if run.Workspace == nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading run %s", runID), errors.New("the workspace of the run is unknown"))
+	}
+
+	options := retryRunOptions(run, strings.TrimSpace(request.GetString("message", "")))
+	if request.GetBool(dryRunParam, false) {
+		effects := retryRunEffects(run)
+		if autoRetry {
+			effects = append(effects, fmt.Sprintf("Waits for the run and queues it again while it fails with a transient error, up to %d runs", maxAttempts))
+		}
+		return dryRunResult(request, "POST", "runs", options, effects, logger)
+	}
+
+	result := RetryRunResult{RetriedRunID: run.ID, WorkspaceID: run.Workspace.ID, Attempts: []RetryRunAttempt{}}
+	deadline := time.Now().Add(time.Duration(waitTimeout) * time.Minute)
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		newRun, err := tfeClient.Runs.Create(ctx, *options)
+		if err != nil {
+			if len(result.Attempts) == 0 {
+				return nil, utils.LogAndReturnError(logger, "creating run", err)
+			}
+			result.Message = fmt.Sprintf("Queuing attempt %d failed: %v", attempt, err)
+			break
+		}
+		notifyRunUpdate(ctx, newRun.ID, run.Workspace.Name, string(newRun.Status), fmt.Sprintf("Retry %d of run %s queued", attempt, run.ID))
+		result.Attempts = append(result.Attempts, RetryRunAttempt{Attempt: attempt, RunID: newRun.ID, Status: string(newRun.Status)})
+		result.RunID, result.Status = newRun.ID, string(newRun.Status)
+		current := &result.Attempts[len(result.Attempts)-1]
+
+		if !autoRetry {
+			result.Message = fmt.Sprintf("Run %s queued, run the `get_run_details` tool to follow it", newRun.ID)
+			break
+		}
+
+		settled, err := waitForRun(ctx, tfeClient, newRun.ID, deadline)
+		if settled != nil {
+			current.Status, result.Status = string(settled.Status), string(settled.Status)
+		}
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			result.Message = fmt.Sprintf("Stopped waiting after %d minutes, run %s is still %s", waitTimeout, newRun.ID, result.Status)
+			break
+		}
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, fmt.Sprintf("waiting for run %s", newRun.ID), err)
+		}
+		notifyRunUpdate(ctx, settled.ID, run.Workspace.Name, string(settled.Status), fmt.Sprintf("Retry %d of run %s is %s", attempt, run.ID, settled.Status))
+
+		if settled.Status != tfe.RunErrored {
+			result.Message = fmt.Sprintf("Run %s is %s", settled.ID, settled.Status)
+			break
+		}
+		failure, err := runFailure(ctx, tfeClient, settled)
+		if err != nil {
+			logger.WithField("run", settled.ID).Warnf("Failed to read the log of the errored run: %v", err)
+		}
+		current.Failure, current.Transient = failure.message, failure.transient
+		if !failure.transient {
+			result.Message = fmt.Sprintf("Run %s failed with an error that is not transient, it was not retried", settled.ID)
+			break
+		}
+		if attempt == maxAttempts {
+			result.Message = fmt.Sprintf("Run %s failed with a transient error, the %d attempts are used up", settled.ID, maxAttempts)
+		}
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling retry result", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// retryRunOptions returns the options of a run with the configuration version and options of run
+func retryRunOptions(run *tfe.Run, message string) *tfe.RunCreateOptions {
+	if message == "" {
+		message = fmt.Sprintf("Retry of %s", run.ID)
+		if run.Message != "" {
+			message += ": " + run.Message
+		}
+	}
+	options := &tfe.RunCreateOptions{
+		Workspace:       run.Workspace,
+		Message:         &message,
+		IsDestroy:       tfe.Bool(run.IsDestroy),
+		RefreshOnly:     tfe.Bool(run.RefreshOnly),
+		Refresh:         tfe.Bool(run.Refresh),
+		PlanOnly:        tfe.Bool(run.PlanOnly),
+		AllowEmptyApply: tfe.Bool(run.AllowEmptyApply),
+		AutoApply:       tfe.Bool(run.AutoApply),
+		TargetAddrs:     run.TargetAddrs,
+		ReplaceAddrs:    run.ReplaceAddrs,
+	}
+	if run.ConfigurationVersion != nil && run.ConfigurationVersion.ID != "" {
+		options.ConfigurationVersion = &tfe.ConfigurationVersion{ID: run.ConfigurationVersion.ID}
+	}
+	// Only plan-only runs may use another Terraform version than the workspace
+	if run.PlanOnly && run.TerraformVersion != "" {
+		options.TerraformVersion = tfe.String(run.TerraformVersion)
+	}
+	if run.SavePlan {
+		options.SavePlan = tfe.Bool(true)
+	}
+	for _, variable := range run.Variables {
+		options.Variables = append(options.Variables, &tfe.RunVariable{Key: variable.Key, Value: variable.Value})
+	}
+	return options
+}
+
+// retryRunEffects predicts the effects of the retry of a run
+func retryRunEffects(run *tfe.Run) []string {
+	effects := []string{fmt.Sprintf("Queues a run in workspace %s (%s), retrying run %s which is %s", run.Workspace.Name, run.Workspace.ID, run.ID, run.Status)}
+	if run.ConfigurationVersion != nil && run.ConfigurationVersion.ID != "" {
+		effects = append(effects, fmt.Sprintf("The run uses configuration version %s, like the retried run", run.ConfigurationVersion.ID))
+	}
+	switch {
+	case run.IsDestroy:
+		effects = append(effects, "The run destroys the resources managed by the workspace")
+	case run.RefreshOnly:
+		effects = append(effects, "The run only refreshes the state")
+	case run.PlanOnly:
+		effects = append(effects, "The run only plans, it cannot be applied")
+	}
+	if run.AutoApply && !run.PlanOnly {
+		effects = append(effects, "A successful plan is applied without a confirmation")
+	}
+	return effects
+}
+
+// waitForRun reads a run until it settles, i.e. it finished or waits for a confirmation, or until deadline
+func waitForRun(ctx context.Context, tfeClient *tfe.Client, runID string, deadline time.Time) (*tfe.Run, error) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	var last *tfe.Run
+	for {
+		run, err := tfeClient.Runs.ReadWithOptions(ctx, runID, &tfe.RunReadOptions{Include: []tfe.RunIncludeOpt{tfe.RunApply}})
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			return last, err
+		}
+		last = run
+		if settledRunStatuses[run.Status] || (run.Actions != nil && run.Actions.IsConfirmable) {
+			return run, nil
+		}
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-time.After(retryRunPollInterval):
+		}
+	}
+}
+
+// runFailureCause is the failure of an errored run
+type runFailureCause struct {
+	message   string
+	transient bool
+}
+
+// runFailure reads the log of the plan or apply that failed, and returns its first error and whether
+// the errors look transient
+func runFailure(ctx context.Context, tfeClient *tfe.Client, run *tfe.Run) (runFailureCause, error) {
+	var logs io.Reader
+	var err error
+	switch {
+	case run.Apply != nil && run.Apply.Status == tfe.ApplyErrored:
+		logs, err = tfeClient.Applies.Logs(ctx, run.Apply.ID)
+	case run.Plan != nil && run.Plan.ID != "":
+		logs, err = tfeClient.Plans.Logs(ctx, run.Plan.ID)
+	default:
+		return runFailureCause{}, fmt.Errorf("run %s has no plan", run.ID)
+	}
+	if err != nil {
+		return runFailureCause{}, err
+	}
+	tail, err := logTail(logs, runFailureLogBytes)
+	if err != nil {
+		return runFailureCause{}, err
+	}
+	return classifyRunFailure(tail), nil
+}
+
+// logTail returns the last size bytes of a log
+func logTail(logs io.Reader, size int) (string, error) {
+	buf := make([]byte, 0, size)
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := logs.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if len(buf) > size {
+			buf = append(buf[:0], buf[len(buf)-size:]...)
+		}
+		if errors.Is(err, io.EOF) {
+			return string(buf), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// classifyRunFailure finds the first error of a run log, in the human-readable or the JSON format. The
+// failure is transient when the errors, or the whole log when no error line is found, match transientRunFailure.
+func classifyRunFailure(logs string) runFailureCause {
+	var cause runFailureCause
+	errorsFrom := -1
+	scanner := bufio.NewScanner(strings.NewReader(ansiEscape.ReplaceAllString(logs, "")))
+	scanner.Buffer(make([]byte, 0, 64*1024), runFailureLogBytes)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimLeft(scanner.Text(), "│╷╵ \t"))
+		var entry struct {
+			Message string `json:"@message"`
+		}
+		if strings.HasPrefix(line, "{") && json.Unmarshal([]byte(line), &entry) == nil && entry.Message != "" {
+			line = entry.Message
+		}
+		lines = append(lines, line)
+		if errorsFrom < 0 && strings.Contains(line, "Error:") {
+			errorsFrom = len(lines) - 1
+			cause.message = line[strings.Index(line, "Error:"):]
+		}
+	}
+	if errorsFrom < 0 {
+		errorsFrom = 0
+	}
+	cause.transient = transientRunFailure.MatchString(strings.Join(lines[errorsFrom:], "\n"))
+	return cause
+}
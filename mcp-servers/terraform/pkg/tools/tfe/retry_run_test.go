@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyRunFailure(t *testing.T) {
+	tests := []struct {
+		name          string
+		log           string
+		wantMessage   string
+		wantTransient bool
+	}{
+		{
+			name:          "provider throttling",
+			log:           "aws_instance.web: Creating...\n╷\n│ \x1b[31mError: \x1b[0mcreating EC2 Instance: operation error EC2: RunInstances, StatusCode: 400, api error RequestLimitExceeded: Request limit exceeded.\n╵\n",
+			wantMessage:   "Error: creating EC2 Instance: operation error EC2: RunInstances, StatusCode: 400, api error RequestLimitExceeded: Request limit exceeded.",
+			wantTransient: true,
+		},
+		{
+			name:          "json log",
+			log:           `{"@level":"info","@message":"Terraform 1.9.0"}` + "\n" + `{"@level":"error","@message":"Error: reading Storage Account: unexpected status 503 Service Unavailable"}` + "\n",
+			wantMessage:   "Error: reading Storage Account: unexpected status 503 Service Unavailable",
+			wantTransient: true,
+		},
+		{
+			name:        "configuration error",
+			log:         "Retrying after a rate limit of the registry\n│ Error: Unsupported argument\n│ An argument named \"foo\" is not expected here.\n",
+			wantMessage: "Error: Unsupported argument",
+		},
+		{
+			name:          "no error line",
+			log:           "dial tcp 10.0.0.1:443: i/o timeout\n",
+			wantTransient: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause := classifyRunFailure(tt.log)
+			assert.Equal(t, tt.wantMessage, cause.message)
+			assert.Equal(t, tt.wantTransient, cause.transient)
+		})
+	}
+}
+
+func TestRetryRunOptions(t *testing.T) {
+	run := &tfe.Run{
+		ID:                   "run-1",
+		Message:              "Nightly apply",
+		IsDestroy:            true,
+		AutoApply:            true,
+		TargetAddrs:          []string{"module.app"},
+		Variables:            []*tfe.RunVariableAttr{{Key: "replicas", Value: "3"}},
+		ConfigurationVersion: &tfe.ConfigurationVersion{ID: "cv-1"},
+		Workspace:            &tfe.Workspace{ID: "ws-1"},
+	}
+	options := retryRunOptions(run, "")
+	assert.Equal(t, "Retry of run-1: Nightly apply", *options.Message)
+	assert.Equal(t, "cv-1", options.ConfigurationVersion.ID)
+	assert.True(t, *options.IsDestroy)
+	assert.True(t, *options.AutoApply)
+	assert.Equal(t, []string{"module.app"}, options.TargetAddrs)
+	assert.Equal(t, []*tfe.RunVariable{{Key: "replicas", Value: "3"}}, options.Variables)
+	assert.Nil(t, options.TerraformVersion)
+
+	assert.Equal(t, "manual retry", *retryRunOptions(run, "manual retry").Message)
+}
+
+func TestRetryRunHandler(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+	interval := retryRunPollInterval
+	retryRunPollInterval = time.Millisecond
+	t.Cleanup(func() { retryRunPollInterval = interval })
+
+	tool := RetryRun(logger)
+	assert.Equal(t, "retry_run", tool.Tool.Name)
+	assert.True(t, *tool.Tool.Annotations.DestructiveHint)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, dryRunParam)
+
+	errored := &tfe.Run{
+		ID:                   "run-1",
+		Status:               tfe.RunErrored,
+		ConfigurationVersion: &tfe.ConfigurationVersion{ID: "cv-1"},
+		Workspace:            &tfe.Workspace{ID: "ws-1", Name: "staging"},
+	}
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		arguments["run_id"] = "run-1"
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "retry_run", Arguments: arguments}}
+	}
+
+	t.Run("rejects a run that did not fail", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/runs/run-1", http.StatusOK, &tfe.Run{ID: "run-1", Status: tfe.RunApplied, Workspace: &tfe.Workspace{ID: "ws-1"}})
+		_, err := retryRunHandler(fake.Context(t), request(map[string]any{}), logger)
+		assert.ErrorContains(t, err, "only errored or canceled runs can be retried")
+	})
+
+	t.Run("queues the run again", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/runs/run-1", http.StatusOK, errored)
+		fake.Respond("POST", "/runs", http.StatusCreated, &tfe.Run{ID: "run-2", Status: tfe.RunPending})
+
+		result, err := retryRunHandler(fake.Context(t), request(map[string]any{}), logger)
+		require.NoError(t, err)
+		var retry RetryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &retry))
+		assert.Equal(t, "run-2", retry.RunID)
+		require.Len(t, retry.Attempts, 1)
+
+		requests := fake.Requests()
+		require.Len(t, requests, 2)
+		assert.Contains(t, string(requests[1].Body), `"id":"cv-1"`)
+	})
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/runs/run-1", http.StatusOK, errored)
+		fake.RespondSequence("POST", "/runs", http.StatusCreated, &tfe.Run{ID: "run-2", Status: tfe.RunPending}, &tfe.Run{ID: "run-3", Status: tfe.RunPending})
+		fake.Respond("GET", "/runs/run-2", http.StatusOK, &tfe.Run{ID: "run-2", Status: tfe.RunErrored, Plan: &tfe.Plan{ID: "plan-2"}})
+		fake.Respond("GET", "/runs/run-3", http.StatusOK, &tfe.Run{ID: "run-3", Status: tfe.RunApplied})
+		fake.Respond("GET", "/plans/plan-2", http.StatusOK, &tfe.Plan{ID: "plan-2", Status: tfe.PlanErrored, LogReadURL: fake.Server.URL + "/logs/plan-2"})
+		serveLog(fake, "/logs/plan-2", "│ Error: creating S3 Bucket: ThrottlingException: Rate exceeded\n")
+
+		result, err := retryRunHandler(fake.Context(t), request(map[string]any{"auto_retry": true}), logger)
+		require.NoError(t, err)
+		var retry RetryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &retry))
+		assert.Equal(t, []RetryRunAttempt{
+			{Attempt: 1, RunID: "run-2", Status: "errored", Failure: "Error: creating S3 Bucket: ThrottlingException: Rate exceeded", Transient: true},
+			{Attempt: 2, RunID: "run-3", Status: "applied"},
+		}, retry.Attempts)
+		assert.Equal(t, "run-3", retry.RunID)
+		assert.Equal(t, "applied", retry.Status)
+	})
+
+	t.Run("stops on other failures", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/runs/run-1", http.StatusOK, errored)
+		fake.Respond("POST", "/runs", http.StatusCreated, &tfe.Run{ID: "run-2", Status: tfe.RunPending})
+		fake.Respond("GET", "/runs/run-2", http.StatusOK, &tfe.Run{ID: "run-2", Status: tfe.RunErrored, Plan: &tfe.Plan{ID: "plan-2"}})
+		fake.Respond("GET", "/plans/plan-2", http.StatusOK, &tfe.Plan{ID: "plan-2", Status: tfe.PlanErrored, LogReadURL: fake.Server.URL + "/logs/plan-2"})
+		serveLog(fake, "/logs/plan-2", "│ Error: Invalid reference\n")
+
+		result, err := retryRunHandler(fake.Context(t), request(map[string]any{"auto_retry": true}), logger)
+		require.NoError(t, err)
+		var retry RetryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &retry))
+		require.Len(t, retry.Attempts, 1)
+		assert.False(t, retry.Attempts[0].Transient)
+		assert.Contains(t, retry.Message, "not transient")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		fake := testutil.NewFakeTFE(t)
+		fake.Respond("GET", "/runs/run-1", http.StatusOK, errored)
+		result, err := retryRunHandler(fake.Context(t), request(map[string]any{dryRunParam: true}), logger)
+		require.NoError(t, err)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "configuration version cv-1")
+		assert.Len(t, fake.Requests(), 1)
+	})
+}
+
+// serveLog serves a run log like the archivist, between STX and ETX markers and read by offset and limit
+func serveLog(fake *testutil.FakeTFE, path, content string) {
+	stream := "\x02" + content + "\x03"
+	fake.Handle("GET", path, func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset = min(offset, len(stream))
+		_, _ = fmt.Fprint(w, stream[offset:min(offset+limit, len(stream))])
+	})
+}
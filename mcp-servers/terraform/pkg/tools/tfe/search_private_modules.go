@@ -54,7 +54,7 @@ func searchPrivateModulesHandler(ctx context.Context, request mcp.CallToolReques
 	// Get required parameters
 	terraformOrgName, err := request.RequireString("terraform_org_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "terraform_org_name is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "terraform_org_name is required", err)
 	}
 	searchQuery := request.GetString("search_query", "")
 	pageSize := request.GetInt("page_size", 100)
@@ -62,17 +62,17 @@ func searchPrivateModulesHandler(ctx context.Context, request mcp.CallToolReques
 
 	// Validate page size and number
 	if pageSize < 1 || pageSize > 100 {
-		return mcp.NewToolResultError("page_size must be between 1 and 100"), nil
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, "page_size must be between 1 and 100"), nil
 	}
 	if pageNumber < 1 {
-		return mcp.NewToolResultError("page_number must be at least 1"), nil
+		return utils.NewToolResultErrorWithCode(utils.ErrorCodeInvalidInput, "page_number must be at least 1"), nil
 	}
 
 	// Get a Terraform client from context
 	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
 	if err != nil {
 		err = utils.LogAndReturnError(logger, "failed to get terraform client for TFE, ensure TFE_TOKEN and TFE_ADDRESS are properly set.", err)
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get terraform client for TFE: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get terraform client for TFE: %v", err)), nil
 	}
 
 	// Prepare list options
@@ -103,7 +103,7 @@ func searchPrivateModulesHandler(ctx context.Context, request mcp.CallToolReques
 	moduleList, err := tfeClient.RegistryModules.List(ctx, terraformOrgName, listOptions)
 	if err != nil {
 		logger.WithError(err).Error("failed to list private modules")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to list private modules: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to list private modules: %v", err)), nil
 	}
 
 	// Build response
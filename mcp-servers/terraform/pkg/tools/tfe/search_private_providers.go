@@ -59,7 +59,7 @@ func searchPrivateProvidersHandler(ctx context.Context, request mcp.CallToolRequ
 	// Get required parameters
 	terraformOrgName, err := request.RequireString("terraform_org_name")
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required for the Terraform Cloud/Enterprise organization.", err)
 	}
 	terraformOrgName = strings.TrimSpace(terraformOrgName)
 
@@ -71,19 +71,19 @@ func searchPrivateProvidersHandler(ctx context.Context, request mcp.CallToolRequ
 
 	// Validate page size
 	if pageSize < 1 || pageSize > 100 {
-		return nil, utils.LogAndReturnError(logger, "page_size must be between 1 and 100", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "page_size must be between 1 and 100", nil)
 	}
 
 	// Validate page number
 	if pageNumber < 1 {
-		return nil, utils.LogAndReturnError(logger, "page_number must be greater than 0", nil)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "page_number must be greater than 0", nil)
 	}
 
 	// Get the terraform client from context
 	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
 	if err != nil {
 		err = utils.LogAndReturnError(logger, "failed to get terraform client for TFE, ensure TFE_TOKEN and TFE_ADDRESS are properly set.", err)
-		return mcp.NewToolResultError(fmt.Sprintf("failed to get terraform client for TFE: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to get terraform client for TFE: %v", err)), nil
 	}
 
 	// Prepare list options
@@ -120,7 +120,7 @@ func searchPrivateProvidersHandler(ctx context.Context, request mcp.CallToolRequ
 	providerList, err := tfeClient.RegistryProviders.List(ctx, terraformOrgName, listOptions)
 	if err != nil {
 		logger.WithError(err).Error("failed to list private providers")
-		return mcp.NewToolResultError(fmt.Sprintf("failed to list private providers: %v", err)), nil
+		return utils.NewToolResultErrorWithCode(client.ClassifyError(err), fmt.Sprintf("failed to list private providers: %v", err)), nil
 	}
 
 	// Build response
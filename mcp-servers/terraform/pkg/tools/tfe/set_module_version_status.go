@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+// moduleVersionActions are the lifecycle changes of a private module version
+var moduleVersionActions = []string{"deprecate", "undeprecate", "revoke", "unrevoke"}
+
+// ModuleVersionStatusResult is the result of the set_module_version_status tool
+type ModuleVersionStatusResult struct {
+	Module      string                           `json:"module"`
+	Version     string                           `json:"version"`
+	Action      string                           `json:"action"`
+	Deprecation *client.ModuleVersionDeprecation `json:"deprecation,omitempty"`
+	Revocation  *client.ModuleVersionRevocation  `json:"revocation,omitempty"`
+}
+
+// SetModuleVersionStatus creates a tool to deprecate or revoke a version of a private registry module.
+func SetModuleVersionStatus(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("set_module_version_status",
+			mcp.WithDescription(`Deprecates or revokes a version of a module of the private registry of a Terraform organization, or reverts it.
+A deprecated version stays usable, but its consumers get a warning with the reason and link in their runs. A revoked version can no longer be used by new runs, which breaks the workspaces still pinned to it: revoking must be confirmed, the first call returns a summary and a confirmation token and the version is only revoked when the tool is called again with the token.
+Use 'find_deprecated_module_consumers' to list the workspaces still using deprecated or revoked versions. Requires an HCP Terraform/TFE release supporting module version deprecation.`),
+			mcp.WithTitleAnnotation("Deprecate or revoke a private module version"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("terraform_org_name",
+				mcp.Required(),
+				mcp.Description("The Terraform Cloud/Enterprise organization name"),
+			),
+			mcp.WithString("private_module_id",
+				mcp.Required(),
+				mcp.Description("The private module ID in the format 'module-namespace/module-name/module-provider-name', e.g. 'my-tfc-org/vpc/aws'"),
+			),
+			mcp.WithString("version",
+				mcp.Required(),
+				mcp.Description("The module version to change, e.g. '1.4.0'"),
+			),
+			mcp.WithString("action",
+				mcp.Required(),
+				mcp.Description("The lifecycle change: 'deprecate' or 'revoke' the version, or revert it with 'undeprecate' or 'unrevoke'"),
+				mcp.Enum(moduleVersionActions...),
+			),
+			mcp.WithString("reason",
+				mcp.Description("Why the version is deprecated or revoked, shown to its consumers, e.g. 'Use 2.x, which supports the AWS provider v5'"),
+			),
+			mcp.WithString("link",
+				mcp.Description("A URL with more information, e.g. the upgrade guide"),
+			),
+			withConfirmationToken(),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return setModuleVersionStatusHandler(ctx, request, logger)
+		},
+	}
+}
+
+func setModuleVersionStatusHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	terraformOrgName, err := request.RequireString("terraform_org_name")
+	if err != nil || strings.TrimSpace(terraformOrgName) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'terraform_org_name' parameter is required", err)
+	}
+	terraformOrgName = strings.TrimSpace(terraformOrgName)
+	moduleID, err := request.RequireString("private_module_id")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'private_module_id' parameter is required", err)
+	}
+	moduleID = strings.TrimSpace(moduleID)
+	parts := strings.Split(moduleID, "/")
+	if len(parts) != 3 {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "private_module_id must be in the format 'module-namespace/module-name/module-provider-name'", nil)
+	}
+	version, err := request.RequireString("version")
+	if err != nil || strings.TrimSpace(version) == "" {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'version' parameter is required", err)
+	}
+	version = strings.TrimSpace(version)
+	action, err := request.RequireString("action")
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "The 'action' parameter is required", err)
+	}
+	action = strings.ToLower(strings.TrimSpace(action))
+	reason := strings.TrimSpace(request.GetString("reason", ""))
+	link := strings.TrimSpace(request.GetString("link", ""))
+
+	lifecycle := client.ModuleVersionLifecycle{Version: version}
+	switch action {
+	case "deprecate":
+		lifecycle.Deprecation = &client.ModuleVersionDeprecation{Status: client.ModuleVersionDeprecated, Reason: reason, Link: link}
+	case "undeprecate":
+		lifecycle.Deprecation = &client.ModuleVersionDeprecation{Status: client.ModuleVersionUndeprecated}
+	case "revoke":
+		lifecycle.Revocation = &client.ModuleVersionRevocation{Status: client.ModuleVersionRevoked, Reason: reason, Link: link}
+	case "unrevoke":
+		lifecycle.Revocation = &client.ModuleVersionRevocation{Status: client.ModuleVersionUnrevoked}
+	default:
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("invalid action %q: must be one of %s", action, strings.Join(moduleVersionActions, ", ")), nil)
+	}
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+
+	registryModuleID := tfe.RegistryModuleID{
+		Organization: terraformOrgName,
+		Namespace:    parts[0],
+		Name:         parts[1],
+		Provider:     parts[2],
+		RegistryName: tfe.PrivateRegistry,
+	}
+	module, err := tfeClient.RegistryModules.Read(ctx, registryModuleID)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading private module %s", moduleID), err)
+	}
+	versions := make([]string, 0, len(module.VersionStatuses))
+	for _, status := range module.VersionStatuses {
+		versions = append(versions, status.Version)
+	}
+	if !slices.Contains(versions, version) {
+		sort.Strings(versions)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeNotFound, fmt.Sprintf("finding version %s of module %s", version, moduleID),
+			fmt.Errorf("the module has the versions %s", strings.Join(versions, ", ")))
+	}
+
+	if request.GetBool(dryRunParam, false) {
+		effects := []string{fmt.Sprintf("Marks version %s of module %s as %s", version, moduleID, action+"d")}
+		switch action {
+		case "deprecate":
+			effects = append(effects, "Runs of the workspaces using the version show a deprecation warning")
+		case "revoke":
+			effects = append(effects, "New runs of the workspaces pinned to the version fail until they upgrade")
+		}
+		return dryRunResult(request, "PATCH", client.ModuleVersionPath(registryModuleID, version), client.NewModuleVersionDocument(lifecycle), effects, logger)
+	}
+
+	if action == "revoke" {
+		// Revoking breaks the workspaces pinned to the version, so it requires a confirmation
+		details := map[string]any{
+			"organization": terraformOrgName,
+			"module":       moduleID,
+			"version":      version,
+			"reason":       reason,
+		}
+		summary := fmt.Sprintf("revoke version %s of module %s, failing the new runs of the workspaces still pinned to it", version, moduleID)
+		if result, err := requireConfirmation(ctx, request, summary, details, logger); result != nil || err != nil {
+			return result, err
+		}
+	}
+
+	updated, err := client.UpdateModuleVersionLifecycle(ctx, tfeClient, registryModuleID, lifecycle)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("changing the status of version %s of module %s", version, moduleID), err)
+	}
+
+	resultJSON, err := json.Marshal(ModuleVersionStatusResult{
+		Module:      moduleID,
+		Version:     version,
+		Action:      action,
+		Deprecation: updated.Deprecation,
+		Revocation:  updated.Revocation,
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling module version status", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
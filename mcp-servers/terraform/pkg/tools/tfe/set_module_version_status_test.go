@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const moduleVersionTestPath = "/organizations/acme/registry-modules/private/acme/vpc/aws/1.4.0"
+
+func newModuleVersionFake(t *testing.T) *testutil.FakeTFE {
+	fake := testutil.NewFakeTFE(t)
+	fake.Respond("GET", "/organizations/acme/registry-modules/private/acme/vpc/aws", http.StatusOK, &tfe.RegistryModule{
+		ID: "mod-123", Name: "vpc", Namespace: "acme", Provider: "aws",
+		VersionStatuses: []tfe.RegistryModuleVersionStatuses{
+			{Version: "1.4.0", Status: tfe.RegistryModuleVersionStatusOk},
+			{Version: "2.0.0", Status: tfe.RegistryModuleVersionStatusOk},
+		},
+	})
+	return fake
+}
+
+func TestSetModuleVersionStatus(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	tool := SetModuleVersionStatus(logger)
+	assert.Equal(t, "set_module_version_status", tool.Tool.Name)
+	require.NotNil(t, tool.Tool.Annotations.DestructiveHint)
+	assert.True(t, *tool.Tool.Annotations.DestructiveHint)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, confirmationTokenParam)
+
+	request := func(arguments map[string]any) mcp.CallToolRequest {
+		base := map[string]any{"terraform_org_name": "acme", "private_module_id": "acme/vpc/aws", "version": "1.4.0"}
+		for key, value := range arguments {
+			base[key] = value
+		}
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "set_module_version_status", Arguments: base}}
+	}
+
+	t.Run("deprecates a version", func(t *testing.T) {
+		fake := newModuleVersionFake(t)
+		fake.Handle("PATCH", moduleVersionTestPath, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			_, _ = w.Write([]byte(`{"data": {"type": "registry-module-versions", "attributes": {"version": "1.4.0", "deprecation": {"deprecated-status": "Deprecated", "reason": "Use 2.x"}}}}`))
+		})
+
+		result, err := setModuleVersionStatusHandler(fake.Context(t), request(map[string]any{"action": "deprecate", "reason": "Use 2.x"}), logger)
+		require.NoError(t, err)
+
+		var status ModuleVersionStatusResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &status))
+		require.NotNil(t, status.Deprecation)
+		assert.Equal(t, client.ModuleVersionDeprecated, status.Deprecation.Status)
+		assert.Equal(t, "Use 2.x", status.Deprecation.Reason)
+
+		requests := fake.Requests()
+		patch := requests[len(requests)-1]
+		assert.Equal(t, "PATCH", patch.Method)
+		assert.JSONEq(t, `{"data": {"type": "registry-module-versions", "attributes": {"deprecation": {"deprecated-status": "Deprecated", "reason": "Use 2.x"}}}}`, string(patch.Body))
+	})
+
+	t.Run("revoking needs a confirmation", func(t *testing.T) {
+		fake := newModuleVersionFake(t)
+		result, err := setModuleVersionStatusHandler(fake.Context(t), request(map[string]any{"action": "revoke"}), logger)
+		require.NoError(t, err)
+
+		var confirmation ConfirmationRequired
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &confirmation))
+		assert.True(t, confirmation.ConfirmationRequired)
+		for _, sent := range fake.Requests() {
+			assert.Equal(t, "GET", sent.Method)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		fake := newModuleVersionFake(t)
+		result, err := setModuleVersionStatusHandler(fake.Context(t), request(map[string]any{"action": "undeprecate", "dry_run": true}), logger)
+		require.NoError(t, err)
+
+		var dryRun DryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &dryRun))
+		assert.Equal(t, "PATCH", dryRun.Method)
+		assert.Equal(t, "/api/v2"+moduleVersionTestPath, dryRun.Path)
+		assert.Contains(t, string(dryRun.Payload), `"deprecated-status":"Undeprecated"`)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		fake := newModuleVersionFake(t)
+		_, err := setModuleVersionStatusHandler(fake.Context(t), request(map[string]any{"action": "deprecate", "version": "3.0.0"}), logger)
+		require.Error(t, err)
+		assert.Equal(t, utils.ErrorCodeNotFound, utils.ErrorCodeOf(err))
+		assert.Contains(t, err.Error(), "1.4.0, 2.0.0")
+	})
+
+	t.Run("unsupported by the server", func(t *testing.T) {
+		fake := newModuleVersionFake(t)
+		_, err := setModuleVersionStatusHandler(fake.Context(t), request(map[string]any{"action": "deprecate"}), logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support deprecating and revoking module versions")
+		assert.Equal(t, utils.ErrorCodeNotFound, client.ClassifyError(err))
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		_, err := setModuleVersionStatusHandler(t.Context(), request(map[string]any{"action": "delete"}), logger)
+		assert.Equal(t, utils.ErrorCodeInvalidInput, utils.ErrorCodeOf(err))
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// UnlockWorkspace creates a tool to unlock a Terraform workspace, optionally overriding a lock held by someone else.
+func UnlockWorkspace(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("unlock_workspace",
+			mcp.WithDescription(`Unlocks a Terraform workspace locked with lock_workspace. With force set to true, a lock held by another user or team is removed as well; a forced unlock must be confirmed: the first call returns a summary and a confirmation token, and the lock is only removed when the tool is called again with the token.`),
+			mcp.WithTitleAnnotation("Unlock a Terraform workspace"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			WithWorkspace("The name of the workspace to unlock"),
+			mcp.WithBoolean("force",
+				mcp.Description("Remove the lock even if it is held by another user or team. Requires the force-unlock permission."),
+				mcp.DefaultBool(false),
+			),
+			withConfirmationToken(),
+			withDryRun(),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return unlockWorkspaceHandler(ctx, request, logger)
+		},
+	}
+}
+
+func unlockWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	if _, err := workspaceRefFromRequest(request); err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+
+	force := request.GetBool("force", false)
+
+	tfeClient, err := client.GetTfeClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Terraform client - please ensure TFE_TOKEN and TFE_ADDRESS are properly configured", err)
+	}
+
+	workspace, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
+	}
+	if !workspace.Locked {
+		return workspaceLockResult(workspace, "Workspace is not locked", logger)
+	}
+
+	action := "unlock"
+	if force {
+		action = "force-unlock"
+	}
+
+	if request.GetBool(dryRunParam, false) {
+		effects := []string{fmt.Sprintf("Unlocks workspace %s (%s), so that runs can start again", workspace.Name, workspace.ID)}
+		if force {
+			effects = append(effects, "Removes the lock even if it is held by another user or team")
+		}
+		return dryRunResult(request, "POST", fmt.Sprintf("workspaces/%s/actions/%s", url.PathEscape(workspace.ID), action), nil, effects, logger)
+	}
+
+	if force {
+		// A forced unlock can interrupt the work of whoever holds the lock, so it requires a confirmation
+		details := map[string]any{
+			"organization":   workspace.Organization.Name,
+			"workspace_id":   workspace.ID,
+			"workspace_name": workspace.Name,
+		}
+		summary := fmt.Sprintf("force-unlock workspace %s/%s, removing a lock that may be held by another user or team", workspace.Organization.Name, workspace.Name)
+		if result, err := requireConfirmation(ctx, request, summary, details, logger); result != nil || err != nil {
+			return result, err
+		}
+
+		workspace, err = tfeClient.Workspaces.ForceUnlock(ctx, workspace.ID)
+	} else {
+		workspace, err = tfeClient.Workspaces.Unlock(ctx, workspace.ID)
+	}
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("running %s on workspace", action), err)
+	}
+
+	return workspaceLockResult(workspace, "Workspace unlocked", logger)
+}
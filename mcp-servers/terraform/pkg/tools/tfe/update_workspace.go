@@ -6,6 +6,9 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/go-tfe"
@@ -21,19 +24,12 @@ import (
 func UpdateWorkspace(logger *log.Logger) server.ServerTool {
 	return server.ServerTool{
 		Tool: mcp.NewTool("update_workspace",
-			mcp.WithDescription(`Updates an existing Terraform workspace configuration. This is a potentially destructive operation that may affect infrastructure resources.`),
+			mcp.WithDescription(`Updates an existing Terraform workspace configuration. This is a potentially destructive operation that may affect infrastructure resources. Changing the execution mode must be confirmed: the first call returns a summary and a confirmation token, and the workspace is only updated when the tool is called again with the token. Returns the settings that changed, with their value before and after the update, and warnings about the consequences of the changes.`),
 			mcp.WithTitleAnnotation("Update an existing Terraform workspace"),
 			mcp.WithOpenWorldHintAnnotation(true),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(false),
-			mcp.WithString("terraform_org_name",
-				mcp.Required(),
-				mcp.Description("The Terraform Cloud/Enterprise organization name"),
-			),
-			mcp.WithString("workspace_name",
-				mcp.Required(),
-				mcp.Description("The name of the workspace to update"),
-			),
+			WithWorkspace("The name of the workspace to update"),
 			mcp.WithString("new_name",
 				mcp.Description("Optional new name for the workspace"),
 			),
@@ -67,6 +63,8 @@ func UpdateWorkspace(logger *log.Logger) server.ServerTool {
 			mcp.WithString("tags",
 				mcp.Description("Optional comma-separated list of tags to replace existing tags"),
 			),
+			withConfirmationToken(),
+			withDryRun(),
 		),
 		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return updateWorkspaceHandler(ctx, request, logger)
@@ -75,18 +73,10 @@ func UpdateWorkspace(logger *log.Logger) server.ServerTool {
 }
 
 func updateWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
-	// Get required parameters
-	terraformOrgName, err := request.RequireString("terraform_org_name")
-	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'terraform_org_name' parameter is required", err)
-	}
-	terraformOrgName = strings.TrimSpace(terraformOrgName)
-
-	workspaceName, err := request.RequireString("workspace_name")
+	ref, err := workspaceRefFromRequest(request)
 	if err != nil {
-		return nil, utils.LogAndReturnError(logger, "The 'workspace_name' parameter is required", err)
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
 	}
-	workspaceName = strings.TrimSpace(workspaceName)
 
 	// Get optional parameters - we'll check if they're provided by comparing to empty/default values
 	newName := request.GetString("new_name", "")
@@ -149,39 +139,189 @@ func updateWorkspaceHandler(ctx context.Context, request mcp.CallToolRequest, lo
 		case "remote":
 			options.ExecutionMode = tfe.String("remote")
 		default:
-			return nil, utils.LogAndReturnError(logger, "invalid execution_mode: must be 'remote', 'local', or 'agent'", nil)
+			return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, "invalid execution_mode: must be 'remote', 'local', or 'agent'", nil)
 		}
 	}
 
 	// Parse trigger prefixes
 	if triggerPrefixesStr != "" {
-		if triggerPrefixesStr == "" {
-			options.TriggerPrefixes = []string{}
-		} else {
-			prefixes := strings.Split(strings.TrimSpace(triggerPrefixesStr), ",")
-			for i, prefix := range prefixes {
-				prefixes[i] = strings.TrimSpace(prefix)
-			}
-			options.TriggerPrefixes = prefixes
+		prefixes := strings.Split(strings.TrimSpace(triggerPrefixesStr), ",")
+		for i, prefix := range prefixes {
+			prefixes[i] = strings.TrimSpace(prefix)
 		}
+		options.TriggerPrefixes = prefixes
+	}
+
+	// Tags are not workspace settings, they are replaced through the workspace tags API after the update
+	tagNames := parseTagNames(tagsStr)
+
+	if request.GetBool(dryRunParam, false) {
+		effects := updateWorkspaceEffects(ref.String(), options)
+		if len(tagNames) > 0 {
+			effects = append(effects, fmt.Sprintf("Replaces the tags with %q", tagNames))
+		}
+		return dryRunResult(request, "PATCH", ref.path(), options, effects, logger)
+	}
+
+	// The current workspace is read first, to confirm an execution mode change and to diff the update
+	current, err := ResolveWorkspace(ctx, tfeClient, request, logger)
+	if err != nil {
+		return nil, err
 	}
+	terraformOrgName := current.Organization.Name
 
-	// Parse tags - Note: Tags might not be updatable via WorkspaceUpdateOptions
-	// This would require a separate API call to manage tags
-	if tagsStr != "" {
-		logger.Warnf("Tag updates are not supported via workspace update - tags parameter ignored")
+	// Changing the execution mode moves where runs execute and which credentials they use,
+	// so it requires a confirmation
+	if options.ExecutionMode != nil && current.ExecutionMode != *options.ExecutionMode {
+		details := map[string]any{
+			"organization":           terraformOrgName,
+			"workspace_id":           current.ID,
+			"workspace_name":         current.Name,
+			"current_execution_mode": current.ExecutionMode,
+			"new_execution_mode":     *options.ExecutionMode,
+		}
+		summary := fmt.Sprintf("change the execution mode of workspace %s/%s from %s to %s", terraformOrgName, current.Name, current.ExecutionMode, *options.ExecutionMode)
+		if result, err := requireConfirmation(ctx, request, summary, details, logger); result != nil || err != nil {
+			return result, err
+		}
 	}
 
 	// Update the workspace
-	workspace, err := tfeClient.Workspaces.Update(ctx, terraformOrgName, workspaceName, *options)
+	workspace, err := tfeClient.Workspaces.UpdateByID(ctx, current.ID, *options)
 	if err != nil {
 		return nil, utils.LogAndReturnError(logger, "updating workspace", err)
 	}
 
-	resultJSON, err := json.Marshal(workspace)
+	if len(tagNames) > 0 {
+		add, remove := replacedTags(workspace.TagNames, tagNames)
+		if err := updateWorkspaceTags(ctx, tfeClient, workspace.ID, add, remove); err != nil {
+			return nil, utils.LogAndReturnError(logger, "updating workspace tags", err)
+		}
+		workspace.TagNames = tagNames
+	}
+
+	resultJSON, err := json.Marshal(diffWorkspaceUpdate(terraformOrgName, current, workspace))
 	if err != nil {
 		return nil, utils.LogAndReturnError(logger, "marshalling workspace update result", err)
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
 }
+
+// WorkspaceUpdateResult is the result of the update_workspace tool: the settings it changed, with their
+// value before and after the update, and warnings about the consequences of the changes
+type WorkspaceUpdateResult struct {
+	WorkspaceID   string                   `json:"workspace_id"`
+	WorkspaceName string                   `json:"workspace_name"`
+	Organization  string                   `json:"organization"`
+	Changes       []WorkspaceSettingChange `json:"changes"`
+	Warnings      []string                 `json:"warnings,omitempty"`
+}
+
+// WorkspaceSettingChange is a workspace setting changed by update_workspace
+type WorkspaceSettingChange struct {
+	Setting string `json:"setting"`
+	Before  any    `json:"before"`
+	After   any    `json:"after"`
+}
+
+// diffWorkspaceUpdate compares a workspace before and after an update. Settings the update did not
+// change, including those it set to their current value, are left out.
+func diffWorkspaceUpdate(organization string, before, after *tfe.Workspace) WorkspaceUpdateResult {
+	result := WorkspaceUpdateResult{
+		WorkspaceID:   after.ID,
+		WorkspaceName: after.Name,
+		Organization:  organization,
+		Changes:       []WorkspaceSettingChange{},
+	}
+	compare := func(setting string, beforeValue, afterValue any) {
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			result.Changes = append(result.Changes, WorkspaceSettingChange{Setting: setting, Before: beforeValue, After: afterValue})
+		}
+	}
+
+	compare("name", before.Name, after.Name)
+	compare("description", before.Description, after.Description)
+	compare("terraform_version", before.TerraformVersion, after.TerraformVersion)
+	compare("working_directory", before.WorkingDirectory, after.WorkingDirectory)
+	compare("execution_mode", before.ExecutionMode, after.ExecutionMode)
+	compare("auto_apply", before.AutoApply, after.AutoApply)
+	compare("queue_all_runs", before.QueueAllRuns, after.QueueAllRuns)
+	compare("speculative_enabled", before.SpeculativeEnabled, after.SpeculativeEnabled)
+	compare("file_triggers_enabled", before.FileTriggersEnabled, after.FileTriggersEnabled)
+	compare("trigger_prefixes", sortedCopy(before.TriggerPrefixes), sortedCopy(after.TriggerPrefixes))
+	compare("tags", sortedCopy(before.TagNames), sortedCopy(after.TagNames))
+
+	for _, change := range result.Changes {
+		switch change.Setting {
+		case "name":
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Configurations and tfe_outputs data sources referring to the workspace as %q must use %q", before.Name, after.Name))
+		case "execution_mode":
+			result.Warnings = append(result.Warnings, executionModeWarning(after.ExecutionMode))
+		case "terraform_version":
+			result.Warnings = append(result.Warnings, fmt.Sprintf("The next run uses Terraform %s and may upgrade the state, which older versions cannot read", after.TerraformVersion))
+		case "working_directory":
+			result.Warnings = append(result.Warnings, "Runs now use another directory of the repository, the next plan may propose to replace or destroy resources")
+		case "auto_apply":
+			if after.AutoApply {
+				result.Warnings = append(result.Warnings, "Successful plans are now applied without a confirmation")
+			}
+		}
+	}
+	return result
+}
+
+// executionModeWarning describes where the runs of a workspace execute with an execution mode
+func executionModeWarning(mode string) string {
+	switch mode {
+	case "local":
+		return "Plans and applies now run on the machines calling Terraform, HCP Terraform/TFE only stores the state"
+	case "agent":
+		return "Plans and applies now run on the agents of the agent pool, with their network access and credentials"
+	default:
+		return "Plans and applies now run on the HCP Terraform/TFE workers, with the variables of the workspace as credentials"
+	}
+}
+
+// sortedCopy returns a sorted copy of values, with nil and empty slices both returned as nil
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// updateWorkspaceEffects lists the settings changed by a workspace update
+func updateWorkspaceEffects(workspace string, options *tfe.WorkspaceUpdateOptions) []string {
+	var effects []string
+	setString := func(setting string, value *string) {
+		if value != nil {
+			effects = append(effects, fmt.Sprintf("Sets %s to %q", setting, *value))
+		}
+	}
+	setBool := func(setting string, value *bool) {
+		if value != nil {
+			effects = append(effects, fmt.Sprintf("Sets %s to %t", setting, *value))
+		}
+	}
+
+	setString("name", options.Name)
+	setString("description", options.Description)
+	setString("terraform_version", options.TerraformVersion)
+	setString("working_directory", options.WorkingDirectory)
+	setString("execution_mode", options.ExecutionMode)
+	setBool("auto_apply", options.AutoApply)
+	setBool("queue_all_runs", options.QueueAllRuns)
+	setBool("speculative_enabled", options.SpeculativeEnabled)
+	setBool("file_triggers_enabled", options.FileTriggersEnabled)
+	if options.TriggerPrefixes != nil {
+		effects = append(effects, fmt.Sprintf("Sets trigger_prefixes to %q", options.TriggerPrefixes))
+	}
+
+	if len(effects) == 0 {
+		return []string{fmt.Sprintf("Leaves workspace %s unchanged", workspace)}
+	}
+	return append([]string{fmt.Sprintf("Updates workspace %s", workspace)}, effects...)
+}
@@ -5,13 +5,17 @@ package tools
 
 import (
 	"encoding/json"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUpdateWorkspace(t *testing.T) {
@@ -31,9 +35,9 @@ func TestUpdateWorkspace(t *testing.T) {
 		assert.NotNil(t, tool.Tool.Annotations.ReadOnlyHint)
 		assert.False(t, *tool.Tool.Annotations.ReadOnlyHint)
 
-		// Check that required parameters are defined
-		assert.Contains(t, tool.Tool.InputSchema.Required, "terraform_org_name")
-		assert.Contains(t, tool.Tool.InputSchema.Required, "workspace_name")
+		// The workspace is addressed by organization and name, or by ID
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "terraform_org_name")
+		assert.Contains(t, tool.Tool.InputSchema.Properties, "workspace_id")
 	})
 
 	t.Run("parameter validation", func(t *testing.T) {
@@ -205,3 +209,44 @@ func TestUpdateWorkspace(t *testing.T) {
 		}
 	})
 }
+
+func TestDiffWorkspaceUpdate(t *testing.T) {
+	before := &tfe.Workspace{ID: "ws-123", Name: "staging", ExecutionMode: "remote", TerraformVersion: "1.9.0", TagNames: []string{"b", "a"}}
+	after := &tfe.Workspace{ID: "ws-123", Name: "staging", ExecutionMode: "agent", TerraformVersion: "1.9.0", AutoApply: true, TagNames: []string{"a", "b"}}
+
+	result := diffWorkspaceUpdate("acme", before, after)
+	assert.Equal(t, "ws-123", result.WorkspaceID)
+	assert.Equal(t, []WorkspaceSettingChange{
+		{Setting: "execution_mode", Before: "remote", After: "agent"},
+		{Setting: "auto_apply", Before: false, After: true},
+	}, result.Changes)
+	assert.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0], "agent pool")
+
+	unchanged := diffWorkspaceUpdate("acme", before, before)
+	assert.Empty(t, unchanged.Changes)
+	assert.Empty(t, unchanged.Warnings)
+}
+
+func TestUpdateWorkspaceHandlerDiff(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	fake := testutil.NewFakeTFE(t)
+	fake.Respond("GET", "/organizations/acme/workspaces/staging", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Description: "old", ExecutionMode: "remote"})
+	fake.Respond("PATCH", "/workspaces/ws-123", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Description: "new", ExecutionMode: "remote"})
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "update_workspace", Arguments: map[string]any{
+		"terraform_org_name": "acme",
+		"workspace_name":     "staging",
+		"description":        "new",
+		"execution_mode":     "remote",
+	}}}
+	result, err := updateWorkspaceHandler(fake.Context(t), request, logger)
+	require.NoError(t, err)
+
+	var update WorkspaceUpdateResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &update))
+	assert.Equal(t, []WorkspaceSettingChange{{Setting: "description", Before: "old", After: "new"}}, update.Changes)
+	assert.Empty(t, update.Warnings)
+}
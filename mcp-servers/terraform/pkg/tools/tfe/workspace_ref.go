@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+)
+
+// errWorkspaceOrganization is returned when a workspace_id does not belong to the terraform_org_name of a call
+var errWorkspaceOrganization = errors.New("the workspace is not in the organization")
+
+// workspaceRef is the workspace a tool call addresses, either by its ID or by its organization and name,
+// so that the workspace_id returned by one tool can be passed to the next one
+type workspaceRef struct {
+	ID           string
+	Organization string
+	Name         string
+}
+
+// WithWorkspace adds the arguments addressing the workspace of a tool: workspace_id, or terraform_org_name
+// and workspace_name. nameDescription describes the workspace_name argument.
+func WithWorkspace(nameDescription string) mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithString("terraform_org_name",
+			mcp.Description("The Terraform Cloud/Enterprise organization name. Required with workspace_name."),
+		)(tool)
+		mcp.WithString("workspace_name",
+			mcp.Description(nameDescription+". Required unless workspace_id is set."),
+		)(tool)
+		mcp.WithString("workspace_id",
+			mcp.Description("The ID of the workspace (e.g., 'ws-abc123def456'), instead of terraform_org_name and workspace_name"),
+		)(tool)
+	}
+}
+
+// workspaceRefFromRequest returns the workspace addressed by the workspace_id, terraform_org_name and
+// workspace_name arguments of a tool call
+func workspaceRefFromRequest(request mcp.CallToolRequest) (workspaceRef, error) {
+	return newWorkspaceRef(
+		request.GetString("workspace_id", ""),
+		request.GetString("terraform_org_name", ""),
+		request.GetString("workspace_name", ""),
+	)
+}
+
+// newWorkspaceRef validates the arguments addressing a workspace. With an ID, the organization is optional
+// and checked once the workspace is read.
+func newWorkspaceRef(id, organization, name string) (workspaceRef, error) {
+	ref := workspaceRef{ID: strings.TrimSpace(id), Organization: strings.TrimSpace(organization), Name: strings.TrimSpace(name)}
+	switch {
+	case ref.ID != "" && ref.Name != "":
+		return ref, fmt.Errorf("set either workspace_id or workspace_name, not both")
+	case ref.ID != "":
+		return ref, nil
+	case ref.Organization == "" || ref.Name == "":
+		return ref, fmt.Errorf("either workspace_id, or terraform_org_name and workspace_name are required")
+	}
+	return ref, nil
+}
+
+// String returns the workspace as organization/name, or its ID
+func (r workspaceRef) String() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return r.Organization + "/" + r.Name
+}
+
+// path returns the API path of the workspace, used by dry runs
+func (r workspaceRef) path() string {
+	if r.ID != "" {
+		return fmt.Sprintf("workspaces/%s", url.PathEscape(r.ID))
+	}
+	return fmt.Sprintf("organizations/%s/workspaces/%s", url.PathEscape(r.Organization), url.PathEscape(r.Name))
+}
+
+// read reads the workspace. A workspace read by ID must belong to the organization of the reference,
+// when it has one. The organization of the returned workspace is always set.
+func (r workspaceRef) read(ctx context.Context, tfeClient *tfe.Client) (*tfe.Workspace, error) {
+	if r.ID == "" {
+		workspace, err := tfeClient.Workspaces.Read(ctx, r.Organization, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		if workspace.Organization == nil {
+			workspace.Organization = &tfe.Organization{Name: r.Organization}
+		}
+		return workspace, nil
+	}
+
+	workspace, err := tfeClient.Workspaces.ReadByID(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	if workspace.Organization == nil || workspace.Organization.Name == "" {
+		return nil, fmt.Errorf("the organization of workspace %s is unknown", r.ID)
+	}
+	if r.Organization != "" && workspace.Organization.Name != r.Organization {
+		return nil, fmt.Errorf("%w: workspace %s is in organization %s, not %s", errWorkspaceOrganization, r.ID, workspace.Organization.Name, r.Organization)
+	}
+	return workspace, nil
+}
+
+// ResolveWorkspace reads the workspace a tool call addresses by workspace_id, or by terraform_org_name
+// and workspace_name, returning tool errors for invalid arguments and failed reads
+func ResolveWorkspace(ctx context.Context, tfeClient *tfe.Client, request mcp.CallToolRequest, logger *log.Logger) (*tfe.Workspace, error) {
+	ref, err := workspaceRefFromRequest(request)
+	if err != nil {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, err.Error(), nil)
+	}
+	workspace, err := ref.read(ctx, tfeClient)
+	if errors.Is(err, errWorkspaceOrganization) {
+		return nil, utils.LogAndReturnErrorWithCode(logger, utils.ErrorCodeInvalidInput, fmt.Sprintf("reading workspace %s", ref), err)
+	}
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, fmt.Sprintf("reading workspace %s", ref), err)
+	}
+	return workspace, nil
+}
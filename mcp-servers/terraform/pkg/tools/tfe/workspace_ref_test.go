@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/terraform-mcp-server/internal/testutil"
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWorkspaceRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		id, org, ws string
+		wantErr     string
+		wantString  string
+		wantPath    string
+	}{
+		{name: "by name", org: "acme", ws: "staging", wantString: "acme/staging", wantPath: "organizations/acme/workspaces/staging"},
+		{name: "by ID", id: " ws-123 ", wantString: "ws-123", wantPath: "workspaces/ws-123"},
+		{name: "by ID with organization", id: "ws-123", org: "acme", wantString: "ws-123", wantPath: "workspaces/ws-123"},
+		{name: "both", id: "ws-123", org: "acme", ws: "staging", wantErr: "not both"},
+		{name: "name without organization", ws: "staging", wantErr: "are required"},
+		{name: "nothing", wantErr: "are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := newWorkspaceRef(tt.id, tt.org, tt.ws)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantString, ref.String())
+			assert.Equal(t, tt.wantPath, ref.path())
+		})
+	}
+}
+
+func TestWorkspaceRefRead(t *testing.T) {
+	fake := testutil.NewFakeTFE(t)
+	fake.Respond("GET", "/workspaces/ws-123", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Organization: &tfe.Organization{Name: "acme"}})
+	fake.Respond("GET", "/organizations/acme/workspaces/staging", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging"})
+	tfeClient := fake.Client(t)
+
+	workspace, err := workspaceRef{ID: "ws-123"}.read(t.Context(), tfeClient)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", workspace.Organization.Name)
+
+	workspace, err = workspaceRef{Organization: "acme", Name: "staging"}.read(t.Context(), tfeClient)
+	require.NoError(t, err)
+	assert.Equal(t, "ws-123", workspace.ID)
+	assert.Equal(t, "acme", workspace.Organization.Name)
+
+	_, err = workspaceRef{ID: "ws-123", Organization: "other"}.read(t.Context(), tfeClient)
+	assert.ErrorIs(t, err, errWorkspaceOrganization)
+}
+
+func TestWorkspaceToolsByID(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	fake := testutil.NewFakeTFE(t)
+	fake.Respond("GET", "/workspaces/ws-123", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Organization: &tfe.Organization{Name: "acme"}})
+	fake.Respond("POST", "/workspaces/ws-123/actions/lock", http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "staging", Locked: true})
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "lock_workspace", Arguments: map[string]any{"workspace_id": "ws-123"}}}
+	result, err := lockWorkspaceHandler(fake.Context(t), request, logger)
+	require.NoError(t, err)
+
+	var lock WorkspaceLockResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &lock))
+	assert.Equal(t, "ws-123", lock.WorkspaceID)
+	assert.True(t, lock.Locked)
+
+	request.Params.Arguments = map[string]any{"workspace_id": "ws-123", "terraform_org_name": "other"}
+	_, err = lockWorkspaceHandler(fake.Context(t), request, logger)
+	assert.ErrorContains(t, err, "not other")
+
+	request.Params.Arguments = map[string]any{"workspace_name": "staging"}
+	_, err = lockWorkspaceHandler(fake.Context(t), request, logger)
+	assert.ErrorContains(t, err, "are required")
+}
@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// parseTagNames splits a comma-separated list of tags, dropping empty and repeated names
+func parseTagNames(tagsStr string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(tagsStr, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// tagChanges returns the tags to add to and remove from a workspace tagged with current so that
+// it has every tag of add and none of remove
+func tagChanges(current, add, remove []string) ([]string, []string) {
+	has := make(map[string]bool, len(current))
+	for _, name := range current {
+		has[name] = true
+	}
+	var added, removed []string
+	for _, name := range add {
+		if !has[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range remove {
+		if has[name] {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
+
+// replacedTags returns the tags to add to and remove from a workspace tagged with current so that
+// it is tagged with exactly desired
+func replacedTags(current, desired []string) ([]string, []string) {
+	keep := make(map[string]bool, len(desired))
+	for _, name := range desired {
+		keep[name] = true
+	}
+	var stale []string
+	for _, name := range current {
+		if !keep[name] {
+			stale = append(stale, name)
+		}
+	}
+	return tagChanges(current, desired, stale)
+}
+
+// updateWorkspaceTags adds and removes tags of a workspace through the workspace tags API
+func updateWorkspaceTags(ctx context.Context, tfeClient *tfe.Client, workspaceID string, add, remove []string) error {
+	if len(add) > 0 {
+		if err := tfeClient.Workspaces.AddTags(ctx, workspaceID, tfe.WorkspaceAddTagsOptions{Tags: tagsNamed(add)}); err != nil {
+			return fmt.Errorf("adding tags: %w", err)
+		}
+	}
+	if len(remove) > 0 {
+		if err := tfeClient.Workspaces.RemoveTags(ctx, workspaceID, tfe.WorkspaceRemoveTagsOptions{Tags: tagsNamed(remove)}); err != nil {
+			return fmt.Errorf("removing tags: %w", err)
+		}
+	}
+	return nil
+}
+
+func tagsNamed(names []string) []*tfe.Tag {
+	tags := make([]*tfe.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, &tfe.Tag{Name: name})
+	}
+	return tags
+}
+
+// workspaceTagsPath is the path of the workspace tags API, used by dry runs
+func workspaceTagsPath(workspaceID string) string {
+	return fmt.Sprintf("workspaces/%s/relationships/tags", url.PathEscape(workspaceID))
+}
@@ -4,7 +4,10 @@
 package tools
 
 import (
+	"github.com/hashicorp/terraform-mcp-server/pkg/client"
+	analysisTools "github.com/hashicorp/terraform-mcp-server/pkg/tools/analysis"
 	registryTools "github.com/hashicorp/terraform-mcp-server/pkg/tools/registry"
+	tfeTools "github.com/hashicorp/terraform-mcp-server/pkg/tools/tfe"
 	"github.com/mark3labs/mcp-go/server"
 	log "github.com/sirupsen/logrus"
 )
@@ -20,9 +23,24 @@ func RegisterTools(hcServer *server.MCPServer, logger *log.Logger) {
 	getProviderDocsTool := registryTools.GetProviderDocs(logger)
 	hcServer.AddTool(getProviderDocsTool.Tool, getProviderDocsTool.Handler)
 
+	getResolveManyProviderDocsTool := registryTools.ResolveManyProviderDocs(logger)
+	hcServer.AddTool(getResolveManyProviderDocsTool.Tool, getResolveManyProviderDocsTool.Handler)
+
 	getLatestProviderVersionTool := registryTools.GetLatestProviderVersion(logger)
 	hcServer.AddTool(getLatestProviderVersionTool.Tool, getLatestProviderVersionTool.Handler)
 
+	getListPopularProvidersTool := registryTools.ListPopularProviders(logger)
+	hcServer.AddTool(getListPopularProvidersTool.Tool, getListPopularProvidersTool.Handler)
+
+	getListProviderGuidesTool := registryTools.ListProviderGuides(logger)
+	hcServer.AddTool(getListProviderGuidesTool.Tool, getListProviderGuidesTool.Handler)
+
+	getGenerateCDKTFSnippetTool := registryTools.GenerateCDKTFSnippet(logger)
+	hcServer.AddTool(getGenerateCDKTFSnippetTool.Tool, getGenerateCDKTFSnippetTool.Handler)
+
+	getVerifyProviderSignatureTool := registryTools.VerifyProviderSignature(logger)
+	hcServer.AddTool(getVerifyProviderSignatureTool.Tool, getVerifyProviderSignatureTool.Handler)
+
 	// Module tools
 	getSearchModulesTool := registryTools.SearchModules(logger)
 	hcServer.AddTool(getSearchModulesTool.Tool, getSearchModulesTool.Handler)
@@ -33,10 +51,113 @@ func RegisterTools(hcServer *server.MCPServer, logger *log.Logger) {
 	getLatestModuleVersionTool := registryTools.GetLatestModuleVersion(logger)
 	hcServer.AddTool(getLatestModuleVersionTool.Tool, getLatestModuleVersionTool.Handler)
 
+	getListPopularModulesTool := registryTools.ListPopularModules(logger)
+	hcServer.AddTool(getListPopularModulesTool.Tool, getListPopularModulesTool.Handler)
+
+	getScoreModuleTool := registryTools.ScoreModule(logger)
+	hcServer.AddTool(getScoreModuleTool.Tool, getScoreModuleTool.Handler)
+
+	getGenerateTfvarsTool := registryTools.GenerateTfvars(logger)
+	hcServer.AddTool(getGenerateTfvarsTool.Tool, getGenerateTfvarsTool.Handler)
+
+	// Module source tools (only available with a GitHub token)
+	if client.GitHubSourceToolsEnabled() {
+		getListModuleSourceTreeTool := registryTools.ListModuleSourceTree(logger)
+		hcServer.AddTool(getListModuleSourceTreeTool.Tool, getListModuleSourceTreeTool.Handler)
+
+		getModuleSourceFileTool := registryTools.GetModuleSourceFile(logger)
+		hcServer.AddTool(getModuleSourceFileTool.Tool, getModuleSourceFileTool.Handler)
+	}
+
+	// Semantic search over the fetched docs (only available with an embeddings endpoint)
+	if client.SemanticSearchEnabled() {
+		getSemanticSearchDocsTool := registryTools.SemanticSearchDocs(logger)
+		hcServer.AddTool(getSemanticSearchDocsTool.Tool, getSemanticSearchDocsTool.Handler)
+	}
+
+	// Run events are received by the webhook endpoints of the StreamableHTTP transport (only available with a webhook token)
+	if client.RunEventWebhooksEnabled() {
+		getListRecentRunEventsTool := tfeTools.ListRecentRunEvents(logger)
+		hcServer.AddTool(getListRecentRunEventsTool.Tool, getListRecentRunEventsTool.Handler)
+	}
+
 	// Policy tools
 	getSearchPoliciesTool := registryTools.SearchPolicies(logger)
 	hcServer.AddTool(getSearchPoliciesTool.Tool, getSearchPoliciesTool.Handler)
 
 	getPolicyDetailsTool := registryTools.PolicyDetails(logger)
 	hcServer.AddTool(getPolicyDetailsTool.Tool, getPolicyDetailsTool.Handler)
+
+	// Analysis tools
+	getAnalyzeStateTool := analysisTools.AnalyzeState(logger)
+	hcServer.AddTool(getAnalyzeStateTool.Tool, getAnalyzeStateTool.Handler)
+
+	getGenerateMovedBlocksTool := analysisTools.GenerateMovedBlocks(logger)
+	hcServer.AddTool(getGenerateMovedBlocksTool.Tool, getGenerateMovedBlocksTool.Handler)
+
+	getPlanBackendMigrationTool := analysisTools.PlanBackendMigration(logger)
+	hcServer.AddTool(getPlanBackendMigrationTool.Tool, getPlanBackendMigrationTool.Handler)
+
+	getExportDependencyInventoryTool := analysisTools.ExportDependencyInventory(logger)
+	hcServer.AddTool(getExportDependencyInventoryTool.Tool, getExportDependencyInventoryTool.Handler)
+
+	getFindModuleConsumersTool := analysisTools.FindModuleConsumers(logger)
+	hcServer.AddTool(getFindModuleConsumersTool.Tool, getFindModuleConsumersTool.Handler)
+
+	getFindDeprecatedModuleConsumersTool := analysisTools.FindDeprecatedModuleConsumers(logger)
+	hcServer.AddTool(getFindDeprecatedModuleConsumersTool.Tool, getFindDeprecatedModuleConsumersTool.Handler)
+
+	getGenerateProviderMirrorConfigTool := analysisTools.GenerateProviderMirrorConfig(logger)
+	hcServer.AddTool(getGenerateProviderMirrorConfigTool.Tool, getGenerateProviderMirrorConfigTool.Handler)
+
+	getAnalyzeLockFileTool := analysisTools.AnalyzeLockFile(logger)
+	hcServer.AddTool(getAnalyzeLockFileTool.Tool, getAnalyzeLockFileTool.Handler)
+
+	getScanConfigurationTool := analysisTools.ScanConfiguration(logger)
+	hcServer.AddTool(getScanConfigurationTool.Tool, getScanConfigurationTool.Handler)
+
+	getEstimatePlanCostTool := analysisTools.EstimatePlanCost(logger)
+	hcServer.AddTool(getEstimatePlanCostTool.Tool, getEstimatePlanCostTool.Handler)
+
+	getLintNamingAndTagsTool := analysisTools.LintNamingAndTags(logger)
+	hcServer.AddTool(getLintNamingAndTagsTool.Tool, getLintNamingAndTagsTool.Handler)
+
+	// Advisories are read from the GitHub API, which only allows a few anonymous calls per hour
+	if client.GitHubSourceToolsEnabled() {
+		getCheckAdvisoriesTool := analysisTools.CheckAdvisories(logger)
+		hcServer.AddTool(getCheckAdvisoriesTool.Tool, getCheckAdvisoriesTool.Handler)
+	}
+}
+
+// TFEToolMiddleware restricts the HCP Terraform/TFE tools to the allowed organizations, then enforces the
+// guardrail policy on the calls allowed.
+func TFEToolMiddleware(logger *log.Logger, schemas client.ToolSchemaLookup) server.ToolHandlerMiddleware {
+	organizationAccess := OrganizationAccessMiddleware(logger)
+	guardrails := GuardrailMiddleware(logger, schemas)
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return organizationAccess(guardrails(next))
+	}
+}
+
+// OrganizationAccessMiddleware rejects calls targeting organizations outside of MCP_ALLOWED_ORGANIZATIONS,
+// in MCP_DENIED_ORGANIZATIONS or outside of the allowlist sent by the caller. Invalid lists stop the server.
+func OrganizationAccessMiddleware(logger *log.Logger) server.ToolHandlerMiddleware {
+	access, err := client.LoadOrganizationAccessFromEnv()
+	if err != nil {
+		logger.Fatalf("Invalid organization access lists: %v", err)
+	}
+	return client.NewOrganizationAccessMiddleware(access, tfeTools.CallOrganization, logger).Middleware()
+}
+
+// GuardrailMiddleware enforces the guardrail policy of MCP_GUARDRAIL_POLICY_FILE on the HCP Terraform/TFE
+// tools. An invalid policy stops the server, so that the tools it restricts never run unguarded.
+func GuardrailMiddleware(logger *log.Logger, schemas client.ToolSchemaLookup) server.ToolHandlerMiddleware {
+	policy, err := client.LoadGuardrailPolicyFromEnv()
+	if err != nil {
+		logger.Fatalf("Invalid guardrail policy: %v", err)
+	}
+	if policy != nil {
+		logger.Infof("Enforcing %d guardrail rules", len(policy.Rules))
+	}
+	return client.NewGuardrailMiddleware(policy, tfeTools.GuardrailWorkspaces, schemas, logger).Middleware()
 }
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrorCode is the machine-readable category of a tool error
+type ErrorCode string
+
+const (
+	ErrorCodeNotFound        ErrorCode = "NOT_FOUND"
+	ErrorCodeUnauthorized    ErrorCode = "UNAUTHORIZED"
+	ErrorCodeUpstreamTimeout ErrorCode = "UPSTREAM_TIMEOUT"
+	ErrorCodeInvalidInput    ErrorCode = "INVALID_INPUT"
+	ErrorCodeRateLimited     ErrorCode = "RATE_LIMITED"
+	ErrorCodePolicyViolation ErrorCode = "POLICY_VIOLATION"
+	ErrorCodeCancelled       ErrorCode = "CANCELLED"
+	ErrorCodeInternal        ErrorCode = "INTERNAL"
+)
+
+// CodedError is an error with an explicit error code
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// WithErrorCode attaches an error code to err
+func WithErrorCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// ErrorCodeOf returns the code attached to err or one of the errors it wraps, or an empty code
+func ErrorCodeOf(err error) ErrorCode {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
+
+// LogAndReturnErrorWithCode logs the error with context like LogAndReturnError and attaches an error code to it
+func LogAndReturnErrorWithCode(logger *log.Logger, code ErrorCode, context string, err error) error {
+	err = fmt.Errorf("%s, %w", context, err)
+	if logger != nil {
+		logger.WithField("code", code).Errorf("Error in %s, %v", context, err)
+	}
+	return WithErrorCode(code, err)
+}
+
+// FieldError describes an invalid tool argument
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ToolErrorResult is the structured content of a tool error result
+type ToolErrorResult struct {
+	Error  string       `json:"error"`
+	Code   ErrorCode    `json:"code"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// NewToolResultErrorWithCode returns a tool error result whose message starts with the error code
+// and whose structured content holds the code, so that agents can tell error categories apart
+func NewToolResultErrorWithCode(code ErrorCode, message string) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(fmt.Sprintf("[%s] %s", code, message))
+	result.StructuredContent = ToolErrorResult{Error: message, Code: code}
+	return result
+}
@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MarkdownOptions configures SanitizeMarkdown
+type MarkdownOptions struct {
+	// BaseURL is the URL relative links are resolved against, relative links are kept when it is empty
+	BaseURL string
+	// TopHeadingLevel is the level of the highest heading once normalized, headings are kept when it is 0
+	TopHeadingLevel int
+}
+
+var (
+	// frontMatterRegex matches the YAML front-matter at the start of registry docs
+	frontMatterRegex = regexp.MustCompile(`^---[ \t]*\n(?s:.*?)\n---[ \t]*(\n|$)`)
+	// unsafeHTMLRegex matches elements whose content must not be kept, and HTML comments
+	unsafeHTMLRegex = regexp.MustCompile(`(?is)<script\b.*?</script\s*>|<style\b.*?</style\s*>|<iframe\b.*?</iframe\s*>|<!--.*?-->`)
+	// lineBreakTagRegex matches <br> tags, which are replaced with a space so tables stay on one line
+	lineBreakTagRegex = regexp.MustCompile(`(?i)<br\s*/?>`)
+	// htmlTagRegex matches the tags of common HTML elements. Placeholders such as <name> are not HTML.
+	htmlTagRegex = regexp.MustCompile(`(?i)</?(a|abbr|b|blockquote|center|code|dd|del|details|div|dl|dt|em|font|h[1-6]|hr|i|img|input|ins|kbd|li|ol|p|picture|pre|s|section|small|source|span|strike|strong|sub|summary|sup|table|tbody|td|th|thead|tr|u|ul|video|script|style|iframe)\b[^<>]*>`)
+	// inlineCodeRegex matches inline code spans, which are kept verbatim
+	inlineCodeRegex = regexp.MustCompile("`[^`\n]+`")
+	// inlineLinkRegex matches inline links and images, capturing the target
+	inlineLinkRegex = regexp.MustCompile(`(!?\[[^\]\n]*\]\()([^()\s]+)((?:\s+"[^"\n]*")?\))`)
+	// referenceLinkRegex matches link reference definitions, capturing the target
+	referenceLinkRegex = regexp.MustCompile(`(?m)^( {0,3}\[[^\]\n]+\]:[ \t]*)(\S+)`)
+	// headingRegex matches ATX headings, capturing the hashes
+	headingRegex = regexp.MustCompile(`^ {0,3}(#{1,6})([ \t]|$)`)
+	// blankLinesRegex matches runs of blank lines left behind by removed content
+	blankLinesRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// SanitizeMarkdown prepares markdown from the registry for clients: it strips the front-matter,
+// removes scripts, comments and HTML tags, resolves relative links against the base URL and
+// shifts the headings so the highest one is at the top heading level. Fenced code blocks are
+// kept verbatim.
+func SanitizeMarkdown(markdown string, options MarkdownOptions) string {
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	markdown = frontMatterRegex.ReplaceAllString(markdown, "")
+
+	var base *url.URL
+	if options.BaseURL != "" {
+		if parsed, err := url.Parse(options.BaseURL); err == nil && parsed.IsAbs() {
+			base = parsed
+		}
+	}
+
+	segments := splitCodeFences(markdown)
+	for i := range segments {
+		if !segments[i].code {
+			segments[i].text = sanitizeProse(segments[i].text, base)
+		}
+	}
+	if options.TopHeadingLevel > 0 {
+		normalizeHeadings(segments, options.TopHeadingLevel)
+	}
+
+	var builder strings.Builder
+	for _, segment := range segments {
+		builder.WriteString(segment.text)
+	}
+	return strings.TrimSpace(blankLinesRegex.ReplaceAllString(builder.String(), "\n\n"))
+}
+
+// markdownSegment is either prose or a fenced code block
+type markdownSegment struct {
+	text string
+	code bool
+}
+
+// splitCodeFences splits markdown into prose and fenced code blocks, an unclosed fence runs to the end
+func splitCodeFences(markdown string) []markdownSegment {
+	var segments []markdownSegment
+	var current strings.Builder
+	fence := ""
+	flush := func(code bool) {
+		if current.Len() > 0 {
+			segments = append(segments, markdownSegment{text: current.String(), code: code})
+			current.Reset()
+		}
+	}
+
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			flush(false)
+			fence = trimmed[:3]
+			current.WriteString(line)
+		case fence != "" && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "":
+			current.WriteString(line)
+			flush(true)
+			fence = ""
+		default:
+			current.WriteString(line)
+		}
+	}
+	flush(fence != "")
+	return segments
+}
+
+// sanitizeProse removes HTML and resolves the links of prose, leaving inline code spans untouched
+func sanitizeProse(text string, base *url.URL) string {
+	var builder strings.Builder
+	last := 0
+	for _, span := range inlineCodeRegex.FindAllStringIndex(text, -1) {
+		builder.WriteString(sanitizeText(text[last:span[0]], base))
+		builder.WriteString(text[span[0]:span[1]])
+		last = span[1]
+	}
+	builder.WriteString(sanitizeText(text[last:], base))
+	return builder.String()
+}
+
+func sanitizeText(text string, base *url.URL) string {
+	text = unsafeHTMLRegex.ReplaceAllString(text, "")
+	text = lineBreakTagRegex.ReplaceAllString(text, " ")
+	text = htmlTagRegex.ReplaceAllString(text, "")
+	if base == nil {
+		return text
+	}
+
+	text = inlineLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		parts := inlineLinkRegex.FindStringSubmatch(link)
+		return parts[1] + resolveLink(base, parts[2]) + parts[3]
+	})
+	return referenceLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+		parts := referenceLinkRegex.FindStringSubmatch(link)
+		return parts[1] + resolveLink(base, parts[2])
+	})
+}
+
+// resolveLink makes a relative link absolute. Anchors and links with a scheme are kept.
+func resolveLink(base *url.URL, target string) string {
+	if strings.HasPrefix(target, "#") {
+		return target
+	}
+	reference, err := url.Parse(target)
+	if err != nil || reference.Scheme != "" {
+		return target
+	}
+	return base.ResolveReference(reference).String()
+}
+
+// normalizeHeadings shifts the headings of the prose segments so the highest one is at the top level
+func normalizeHeadings(segments []markdownSegment, topLevel int) {
+	highest := 0
+	for _, segment := range segments {
+		if segment.code {
+			continue
+		}
+		for _, line := range strings.Split(segment.text, "\n") {
+			if match := headingRegex.FindStringSubmatch(line); match != nil && (highest == 0 || len(match[1]) < highest) {
+				highest = len(match[1])
+			}
+		}
+	}
+	if highest == 0 || highest == topLevel {
+		return
+	}
+
+	shift := topLevel - highest
+	for i, segment := range segments {
+		if segment.code {
+			continue
+		}
+		lines := strings.Split(segment.text, "\n")
+		for j, line := range lines {
+			match := headingRegex.FindStringSubmatchIndex(line)
+			if match == nil {
+				continue
+			}
+			level := min(max(match[3]-match[2]+shift, 1), 6)
+			lines[j] = strings.Repeat("#", level) + line[match[3]:]
+		}
+		segments[i].text = strings.Join(lines, "\n")
+	}
+}
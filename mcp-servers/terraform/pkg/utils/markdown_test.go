@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !integration
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeMarkdown(t *testing.T) {
+	registry := MarkdownOptions{BaseURL: "https://registry.terraform.io/providers/hashicorp/aws/5.0.0/docs/"}
+
+	tests := []struct {
+		name     string
+		markdown string
+		options  MarkdownOptions
+		expected string
+	}{
+		{
+			name:     "front-matter",
+			markdown: "---\nsubcategory: \"VPC\"\npage_title: \"AWS: aws_vpc\"\ndescription: |-\n  Provides a VPC resource.\n---\n\n# Resource: aws_vpc\n",
+			expected: "# Resource: aws_vpc",
+		},
+		{
+			name:     "scripts and comments",
+			markdown: "Intro<script>alert('x')</script>\n<!-- hidden\nnote -->\n<style>p { color: red }</style>text",
+			expected: "Intro\n\ntext",
+		},
+		{
+			name:     "html tags",
+			markdown: "<p align=\"center\"><img src=\"logo.png\"></p>\n\n| a | b<br>c |\n\n<details><summary>More</summary>\n\nHidden\n</details>",
+			expected: "| a | b c |\n\nMore\n\nHidden",
+		},
+		{
+			name:     "placeholders and inline code",
+			markdown: "Import with `terraform import aws_vpc.test <b>id</b>` using <vpc_id> or <name>.",
+			expected: "Import with `terraform import aws_vpc.test <b>id</b>` using <vpc_id> or <name>.",
+		},
+		{
+			name:     "relative links",
+			markdown: "See [subnet](../r/subnet.html), [guide](/docs/guides/tags), [anchor](#argument-reference), [site](https://www.terraform.io) and ![diagram](images/vpc.png \"VPC\").\n\n[ref]: guides/tags",
+			options:  registry,
+			expected: "See [subnet](https://registry.terraform.io/providers/hashicorp/aws/5.0.0/r/subnet.html), [guide](https://registry.terraform.io/docs/guides/tags), [anchor](#argument-reference), [site](https://www.terraform.io) and ![diagram](https://registry.terraform.io/providers/hashicorp/aws/5.0.0/docs/images/vpc.png \"VPC\").\n\n[ref]: https://registry.terraform.io/providers/hashicorp/aws/5.0.0/docs/guides/tags",
+		},
+		{
+			name:     "relative links without a base URL",
+			markdown: "See [subnet](../r/subnet.html).",
+			expected: "See [subnet](../r/subnet.html).",
+		},
+		{
+			name:     "heading levels",
+			markdown: "### Usage\n\n#### Inputs\n\n```hcl\n# a comment, not a heading\n```\n\n###### Deep",
+			options:  MarkdownOptions{TopHeadingLevel: 2},
+			expected: "## Usage\n\n### Inputs\n\n```hcl\n# a comment, not a heading\n```\n\n##### Deep",
+		},
+		{
+			name:     "heading levels are clamped",
+			markdown: "# Example\n\n#### Details\n\n###### Deep",
+			options:  MarkdownOptions{TopHeadingLevel: 5},
+			expected: "##### Example\n\n###### Details\n\n###### Deep",
+		},
+		{
+			name:     "code blocks are kept verbatim",
+			markdown: "```html\n<script>keep()</script>\n[link](relative)\n```",
+			options:  registry,
+			expected: "```html\n<script>keep()</script>\n[link](relative)\n```",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, SanitizeMarkdown(test.markdown, test.options))
+		})
+	}
+}
+
+func FuzzSanitizeMarkdown(f *testing.F) {
+	f.Add("---\npage_title: x\n---\n# Resource\n\n[link](../guides/x.md)\n```hcl\n<script>\n```\n", 2)
+	f.Add("<script>alert(1)<!-- --></script>\n[ref]: ./relative\n###### deep", 6)
+	f.Add("```\nunterminated fence", 0)
+	f.Fuzz(func(t *testing.T, markdown string, topHeadingLevel int) {
+		SanitizeMarkdown(markdown, MarkdownOptions{
+			BaseURL:         "https://registry.terraform.io/providers/hashicorp/aws/5.0.0/docs/",
+			TopHeadingLevel: topHeadingLevel % 8,
+		})
+	})
+}
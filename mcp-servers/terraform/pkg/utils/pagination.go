@@ -94,3 +94,19 @@ func WithPagination() mcp.ToolOption {
 		)(tool)
 	}
 }
+
+// Offset returns the number of results before the requested page
+func (p PaginationParams) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// WithNextPageHint appends to the result of a list tool a hint telling how to get the next page,
+// where nextPage is 0 on the last page. The hint is a separate content block so that JSON results stay parseable.
+func WithNextPageHint(result *mcp.CallToolResult, pagination PaginationParams, nextPage int) *mcp.CallToolResult {
+	hint := fmt.Sprintf("Page %d is the last page of results.", pagination.Page)
+	if nextPage > 0 {
+		hint = fmt.Sprintf("More results are available: call this tool again with the same arguments and page=%d, pageSize=%d to get the next page.", nextPage, pagination.PageSize)
+	}
+	result.Content = append(result.Content, mcp.NewTextContent(hint))
+	return result
+}
@@ -4,6 +4,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -521,6 +522,19 @@ func TestPaginationParams_Struct(t *testing.T) {
 	assert.Equal(t, "", zeroParams.After)
 }
 
+func TestWithNextPageHint(t *testing.T) {
+	pagination := PaginationParams{Page: 2, PageSize: 20}
+	assert.Equal(t, 20, pagination.Offset())
+
+	result := WithNextPageHint(mcp.NewToolResultText(`["a","b"]`), pagination, 3)
+	require.Len(t, result.Content, 2)
+	assert.Equal(t, `["a","b"]`, result.Content[0].(mcp.TextContent).Text)
+	assert.Contains(t, result.Content[1].(mcp.TextContent).Text, "page=3, pageSize=20")
+
+	result = WithNextPageHint(mcp.NewToolResultText(`[]`), pagination, 0)
+	assert.Equal(t, "Page 2 is the last page of results.", result.Content[1].(mcp.TextContent).Text)
+}
+
 // Benchmark tests for performance
 func BenchmarkOptionalParam(b *testing.B) {
 	req := mockCallToolRequest(map[string]interface{}{
@@ -558,3 +572,22 @@ func BenchmarkOptionalPaginationParams(b *testing.B) {
 		_, _ = OptionalPaginationParams(req)
 	}
 }
+
+func FuzzOptionalPaginationParams(f *testing.F) {
+	f.Add(`{"page": 2, "pageSize": 10, "after": "cursor"}`)
+	f.Add(`{"page": "2", "pageSize": null}`)
+	f.Add(`{"page": -1, "pageSize": 1e300}`)
+	f.Add(`{"after": ["a"]}`)
+	f.Fuzz(func(t *testing.T, arguments string) {
+		// The arguments are decoded like the arguments of a tool call
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return
+		}
+		params, err := OptionalPaginationParams(mockCallToolRequest(args))
+		if err != nil {
+			return
+		}
+		_ = params.Offset()
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RedactedSecret replaces the secrets found in log entries and tool results
+const RedactedSecret = "[REDACTED]"
+
+// secretPattern matches a secret. When the pattern has a group, only the text after the group is
+// masked, so that e.g. the name of a key or the Bearer scheme stays readable.
+type secretPattern struct {
+	// hint is a lowercase substring every match contains, to skip the regular expression on most
+	// strings, or empty to always run it
+	hint    string
+	pattern *regexp.Regexp
+}
+
+var secretPatterns = []secretPattern{
+	// HCP Terraform/TFE user, team and organization tokens
+	{".atlasv1.", regexp.MustCompile(`[A-Za-z0-9]{14}\.atlasv1\.[A-Za-z0-9_=-]{20,}`)},
+	// AWS access key IDs
+	{"", regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	// AWS secret access keys, recognized by the name they are assigned to
+	{"secret", regexp.MustCompile(`(?i)(\baws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?)[A-Za-z0-9/+=]{40}`)},
+	// Bearer tokens in Authorization headers
+	{"bearer", regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/-]{8,}=*`)},
+	// GitHub tokens, e.g. the token of the module source tools
+	{"", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	// Vault service, batch and recovery tokens
+	{"hv", regexp.MustCompile(`\bhv[sbr]\.[A-Za-z0-9_-]{24,}`)},
+}
+
+// RedactSecrets masks the TFE tokens, AWS keys, bearer tokens, GitHub and Vault tokens found in text
+func RedactSecrets(text string) string {
+	// Masking a secret never adds a hint, so the hints are looked up in the original text
+	lower := strings.ToLower(text)
+	for _, secret := range secretPatterns {
+		if !strings.Contains(lower, secret.hint) {
+			continue
+		}
+		if secret.pattern.NumSubexp() == 0 {
+			text = secret.pattern.ReplaceAllLiteralString(text, RedactedSecret)
+		} else {
+			text = secret.pattern.ReplaceAllString(text, "${1}"+RedactedSecret)
+		}
+	}
+	return text
+}
@@ -31,26 +31,9 @@ func ConstructProviderVersionURI(providerNamespace string, providerName string,
 }
 
 // ContainsSlug checks if the sourceName string contains the slug string anywhere within it.
-// It safely handles potential regex metacharacters in the slug.
+// The slug is matched literally, so regex metacharacters and invalid UTF-8 in tool arguments are harmless.
 func ContainsSlug(sourceName string, slug string) (bool, error) {
-	// Use regexp.QuoteMeta to escape any special regex characters in the slug.
-	// This ensures the slug is treated as a literal string in the pattern.
-	escapedSlug := regexp.QuoteMeta(slug)
-
-	// Construct the regex pattern dynamically: ".*" + escapedSlug + ".*"
-	// This pattern means "match any characters, then the escaped slug, then any characters".
-	pattern := ".*" + escapedSlug + ".*"
-
-	// regexp.MatchString compiles and runs the regex against the sourceName.
-	// It returns true if a match is found, false otherwise.
-	// It also returns an error if the pattern is invalid (unlikely here due to QuoteMeta).
-	matched, err := regexp.MatchString(pattern, sourceName)
-	if err != nil {
-		fmt.Printf("Error compiling or matching regex pattern '%s': %v\n", pattern, err)
-		return false, err // Propagate the error
-	}
-
-	return matched, nil
+	return strings.Contains(sourceName, slug), nil
 }
 
 // IsValidProviderVersionFormat checks if the provider version format is valid.
@@ -61,9 +44,18 @@ func IsValidProviderVersionFormat(version string) bool {
 	return matched
 }
 
+// ProviderDataTypes are the categories of provider docs. Ephemeral resources are published by providers built for Terraform 1.10+.
+var ProviderDataTypes = []string{"resources", "data-sources", "ephemeral-resources", "functions", "guides", "overview"}
+
 func IsValidProviderDataType(providerDataType string) bool {
-	validTypes := []string{"resources", "data-sources", "functions", "guides", "overview"}
-	return slices.Contains(validTypes, providerDataType)
+	return slices.Contains(ProviderDataTypes, providerDataType)
+}
+
+// ProviderDocLanguages are the languages of provider docs: HCL, and the CDK for Terraform languages
+var ProviderDocLanguages = []string{"hcl", "typescript", "python", "go", "csharp", "java"}
+
+func IsValidProviderDocLanguage(language string) bool {
+	return slices.Contains(ProviderDocLanguages, language)
 }
 
 // LogAndReturnError logs the error with context and returns a formatted error.
@@ -76,7 +68,7 @@ func LogAndReturnError(logger *log.Logger, context string, err error) error {
 }
 
 func IsV2ProviderDataType(dataType string) bool {
-	v2Categories := []string{"guides", "functions", "overview"}
+	v2Categories := []string{"ephemeral-resources", "guides", "functions", "overview"}
 	return slices.Contains(v2Categories, dataType)
 }
 
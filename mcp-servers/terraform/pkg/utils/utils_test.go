@@ -70,7 +70,7 @@ func TestIsValidProviderVersionFormat(t *testing.T) {
 }
 
 func TestIsValidProviderDataType(t *testing.T) {
-	valid := []string{"resources", "data-sources", "functions", "guides", "overview"}
+	valid := []string{"resources", "data-sources", "ephemeral-resources", "functions", "guides", "overview"}
 	invalid := []string{"foo", "bar", ""}
 	for _, v := range valid {
 		if !IsValidProviderDataType(v) {
@@ -84,6 +84,21 @@ func TestIsValidProviderDataType(t *testing.T) {
 	}
 }
 
+func TestIsValidProviderDocLanguage(t *testing.T) {
+	valid := []string{"hcl", "typescript", "python", "go", "csharp", "java"}
+	invalid := []string{"HCL", "ts", "javascript", ""}
+	for _, v := range valid {
+		if !IsValidProviderDocLanguage(v) {
+			t.Errorf("expected %q to be valid", v)
+		}
+	}
+	for _, v := range invalid {
+		if IsValidProviderDocLanguage(v) {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
 func TestLogAndReturnError_NilLogger(t *testing.T) {
 	err := LogAndReturnError(nil, "context", fmt.Errorf("fail"))
 	if err == nil || !strings.Contains(err.Error(), "context") {
@@ -92,7 +107,7 @@ func TestLogAndReturnError_NilLogger(t *testing.T) {
 }
 
 func TestIsV2ProviderDataType(t *testing.T) {
-	valid := []string{"guides", "functions", "overview"}
+	valid := []string{"ephemeral-resources", "guides", "functions", "overview"}
 	invalid := []string{"resources", "data-sources", "foo"}
 	for _, v := range valid {
 		if !IsV2ProviderDataType(v) {
@@ -207,3 +222,65 @@ func TestExtractReadme(t *testing.T) {
 		})
 	}
 }
+
+func TestLogAndReturnErrorWithCode(t *testing.T) {
+	err := LogAndReturnErrorWithCode(logger, ErrorCodeInvalidInput, "required input: module_id is required", fmt.Errorf("missing argument"))
+	require.Error(t, err)
+	assert.Equal(t, ErrorCodeInvalidInput, ErrorCodeOf(err))
+	assert.Contains(t, err.Error(), "module_id is required")
+
+	// The code is found through wrapping errors
+	assert.Equal(t, ErrorCodeInvalidInput, ErrorCodeOf(fmt.Errorf("calling tool: %w", err)))
+	assert.Equal(t, ErrorCode(""), ErrorCodeOf(fmt.Errorf("plain error")))
+	assert.NoError(t, WithErrorCode(ErrorCodeNotFound, nil))
+
+	result := NewToolResultErrorWithCode(ErrorCodeNotFound, "workspace not found")
+	assert.True(t, result.IsError)
+	assert.Equal(t, ToolErrorResult{Error: "workspace not found", Code: ErrorCodeNotFound}, result.StructuredContent)
+}
+
+func FuzzExtractProviderNameAndVersion(f *testing.F) {
+	f.Add("registry://providers/hashicorp/namespace/aws/version/3.0.0")
+	f.Add("registry://providers/hashicorp/providers/aws/versions/latest")
+	f.Add("a/b/c/d")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, uri string) {
+		namespace, name, version, err := ExtractProviderNameAndVersion(uri)
+		if err != nil {
+			return
+		}
+		for _, segment := range []string{namespace, name, version} {
+			if strings.Contains(segment, "/") {
+				t.Errorf("segment %q of %q contains a separator", segment, uri)
+			}
+		}
+	})
+}
+
+func FuzzContainsSlug(f *testing.F) {
+	f.Add("aws_s3_bucket", "s3")
+	f.Add("aws_s3_bucket", "ec2")
+	f.Add("aws_instance", ".*")
+	f.Add("data\nsource", "a\ns")
+	f.Add("\xff", "\xff")
+	f.Fuzz(func(t *testing.T, sourceName, slug string) {
+		contains, err := ContainsSlug(sourceName, slug)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Contains(sourceName, slug), contains)
+	})
+}
+
+func FuzzIsValidProviderVersionFormat(f *testing.F) {
+	for _, version := range []string{"1.0.0", "v1.2.3", "1.0.0-beta", "1.0", "latest", ""} {
+		f.Add(version)
+	}
+	f.Fuzz(func(t *testing.T, version string) {
+		if !IsValidProviderVersionFormat(version) {
+			return
+		}
+		// A valid version is safe to use as a URI segment and a query parameter
+		if strings.ContainsAny(version, "/?#&% \n") {
+			t.Errorf("version %q is valid", version)
+		}
+	})
+}
@@ -35,10 +35,12 @@ var (
 // GetHumanVersion composes the parts of the version in a way that's suitable
 // for displaying to humans.
 func GetHumanVersion() string {
-	version := Version
-	release := VersionPrerelease
-	metadata := VersionMetadata
+	return HumanVersion(Version, VersionPrerelease, VersionMetadata)
+}
 
+// HumanVersion composes a version, its pre-release marker and its metadata for displaying to humans. The
+// other MCP servers of the repository use it for their own versions.
+func HumanVersion(version string, release string, metadata string) string {
 	if release != "" {
 		version += fmt.Sprintf("-%s", release)
 	}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/mcpserver"
+	vaultClient "github.com/vignesan/infra-genie/mcp-servers/vault/pkg/client"
+	"github.com/vignesan/infra-genie/mcp-servers/vault/pkg/tools"
+	"github.com/vignesan/infra-genie/mcp-servers/vault/version"
+)
+
+var serverConfig = mcpserver.Config{
+	Name:                "vault-mcp-server",
+	Title:               "Vault MCP Server",
+	Description:         `A Vault MCP server that exposes read-oriented tools for secrets engines and policies.`,
+	Version:             version.Version,
+	BuildInfo:           fmt.Sprintf("Version: %s\nCommit: %s\nBuild Date: %s", version.GetHumanVersion(), version.GitCommit, version.BuildDate),
+	Register:            tools.RegisterTools,
+	ContextMiddleware:   vaultClient.ContextHeaders.Middleware,
+	OnRegisterSession:   vaultClient.SessionClients.OnRegisterSession,
+	OnUnregisterSession: vaultClient.SessionClients.OnUnregisterSession,
+}
+
+func main() {
+	mcpserver.Execute(serverConfig)
+}
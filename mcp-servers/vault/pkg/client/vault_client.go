@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-mcp-server/pkg/mcpserver"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	VaultAddress        = "VAULT_ADDR"
+	VaultToken          = "VAULT_TOKEN"
+	VaultNamespace      = "VAULT_NAMESPACE"
+	VaultSkipTLSVerify  = "VAULT_SKIP_VERIFY"
+	DefaultVaultAddress = "https://127.0.0.1:8200"
+)
+
+// VaultClient is a minimal client for the Vault HTTP API
+type VaultClient struct {
+	Address    string
+	Token      string
+	Namespace  string
+	HTTPClient *http.Client
+}
+
+// VaultResponse is the common envelope returned by the Vault HTTP API
+type VaultResponse struct {
+	RequestID string          `json:"request_id"`
+	Data      json.RawMessage `json:"data"`
+	Errors    []string        `json:"errors"`
+}
+
+// NewVaultClient creates a new Vault client
+func NewVaultClient(address string, token string, namespace string, skipTLSVerify bool, logger *log.Logger) (*VaultClient, error) {
+	if token == "" {
+		logger.Warn("No Vault token provided, Vault client will not be available")
+		return nil, fmt.Errorf("required input: no Vault token provided")
+	}
+
+	if _, err := url.Parse(address); err != nil {
+		return nil, fmt.Errorf("invalid Vault address %q: %w", address, err)
+	}
+
+	httpClient := cleanhttp.DefaultPooledClient()
+	httpClient.Timeout = 10 * time.Second
+	transport := httpClient.Transport.(*http.Transport)
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: skipTLSVerify}
+
+	client := &VaultClient{
+		Address:    strings.TrimSuffix(address, "/"),
+		Token:      token,
+		Namespace:  namespace,
+		HTTPClient: httpClient,
+	}
+
+	return client, nil
+}
+
+// ContextHeaders are the Vault settings read from the headers of the StreamableHTTP requests
+var ContextHeaders = mcpserver.ContextHeaders{
+	Service: "Vault",
+	Address: VaultAddress,
+	Token:   VaultToken,
+	Others:  []string{VaultNamespace, VaultSkipTLSVerify},
+}
+
+// SessionClients holds the Vault client of each session
+var SessionClients = &mcpserver.SessionClients[*VaultClient]{
+	Service: "Vault",
+	Create:  newVaultClientFromContext,
+	Missing: "VAULT_TOKEN is provided",
+}
+
+// GetVaultClientFromContext returns the Vault client of the session of ctx
+func GetVaultClientFromContext(ctx context.Context, logger *log.Logger) (*VaultClient, error) {
+	return SessionClients.FromContext(ctx, logger)
+}
+
+// newVaultClientFromContext creates a Vault client using the values stored in the request context or environment
+func newVaultClientFromContext(ctx context.Context, logger *log.Logger) (*VaultClient, error) {
+	address := mcpserver.ContextValue(ctx, VaultAddress, DefaultVaultAddress)
+	token := mcpserver.ContextValue(ctx, VaultToken, "")
+	namespace := mcpserver.ContextValue(ctx, VaultNamespace, "")
+	skipTLSVerify, _ := strconv.ParseBool(mcpserver.ContextValue(ctx, VaultSkipTLSVerify, "false"))
+
+	return NewVaultClient(address, token, namespace, skipTLSVerify, logger)
+}
+
+// Read performs a GET request against the given API path, e.g. sys/mounts
+func (c *VaultClient) Read(ctx context.Context, path string) (*VaultResponse, error) {
+	return c.do(ctx, http.MethodGet, path)
+}
+
+// List performs a LIST request against the given API path and returns the listed keys
+func (c *VaultClient) List(ctx context.Context, path string) ([]string, error) {
+	resp, err := c.do(ctx, "LIST", path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Keys []string `json:"keys"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("decoding list response for %s: %w", path, err)
+	}
+	return data.Keys, nil
+}
+
+func (c *VaultClient) do(ctx context.Context, method string, path string) (*VaultResponse, error) {
+	reqURL := fmt.Sprintf("%s/v1/%s", c.Address, strings.TrimPrefix(path, "/"))
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", c.Token)
+	req.Header.Set("X-Vault-Request", "true")
+	if c.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.Namespace)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading Vault response: %w", err)
+	}
+
+	var vaultResp VaultResponse
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &vaultResp); err != nil && resp.StatusCode == http.StatusOK {
+			return nil, fmt.Errorf("decoding Vault response: %w", err)
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if len(vaultResp.Errors) > 0 {
+			return nil, fmt.Errorf("vault returned %s for %s: %s", resp.Status, path, strings.Join(vaultResp.Errors, "; "))
+		}
+		return nil, fmt.Errorf("vault returned %s for %s", resp.Status, path)
+	}
+
+	return &vaultResp, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultClient(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.ErrorLevel)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		assert.Equal(t, "admin", r.Header.Get("X-Vault-Namespace"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/sys/mounts":
+			w.Write([]byte(`{"data":{"secret/":{"type":"kv","options":{"version":"2"}}}}`))
+		case r.Method == "LIST" && r.URL.Path == "/v1/sys/policies/acl":
+			w.Write([]byte(`{"data":{"keys":["default","root"]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewVaultClient(server.URL+"/", "test-token", "admin", false, logger)
+	require.NoError(t, err)
+
+	t.Run("read", func(t *testing.T) {
+		resp, err := client.Read(t.Context(), "sys/mounts")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"secret/":{"type":"kv","options":{"version":"2"}}}`, string(resp.Data))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		keys, err := client.List(t.Context(), "/sys/policies/acl")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"default", "root"}, keys)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := client.Read(t.Context(), "secret/metadata/missing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		denied := &VaultClient{Address: server.URL, Token: "wrong", Namespace: "admin", HTTPClient: server.Client()}
+		_, err := denied.Read(t.Context(), "sys/mounts")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "permission denied")
+	})
+}
+
+func TestNewVaultClientRequiresToken(t *testing.T) {
+	_, err := NewVaultClient("https://vault.example.com", "", "", false, log.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/vault/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ListPolicies creates a tool to list the ACL policies defined in Vault.
+func ListPolicies(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_policies",
+			mcp.WithDescription(`Lists the names of the ACL policies defined in Vault. Use 'render_policy' to view the HCL of a policy.`),
+			mcp.WithTitleAnnotation("List Vault ACL policies"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listPoliciesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listPoliciesHandler(ctx context.Context, _ mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	vaultClient, err := client.GetVaultClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Vault client - please ensure VAULT_TOKEN and VAULT_ADDR are properly configured", err)
+	}
+
+	policies, err := vaultClient.List(ctx, "sys/policies/acl")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing policies", err)
+	}
+
+	buf, err := json.Marshal(map[string]any{
+		"policies": policies,
+		"total":    len(policies),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling policies", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/vault/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SecretEngine describes a mounted secrets engine
+type SecretEngine struct {
+	Path        string            `json:"path"`
+	Type        string            `json:"type"`
+	Description string            `json:"description,omitempty"`
+	Accessor    string            `json:"accessor,omitempty"`
+	Local       bool              `json:"local"`
+	SealWrap    bool              `json:"seal_wrap"`
+	Options     map[string]string `json:"options,omitempty"`
+}
+
+// ListSecretEngines creates a tool to list the secrets engines mounted in Vault.
+func ListSecretEngines(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("list_secret_engines",
+			mcp.WithDescription(`Lists the secrets engines mounted in Vault with their type, description and options (such as the KV version). Requires a Vault token with read access to sys/mounts.`),
+			mcp.WithTitleAnnotation("List Vault secrets engines"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return listSecretEnginesHandler(ctx, request, logger)
+		},
+	}
+}
+
+func listSecretEnginesHandler(ctx context.Context, _ mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	vaultClient, err := client.GetVaultClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Vault client - please ensure VAULT_TOKEN and VAULT_ADDR are properly configured", err)
+	}
+
+	resp, err := vaultClient.Read(ctx, "sys/mounts")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "listing secrets engines", err)
+	}
+
+	engines, err := parseSecretEngines(resp.Data)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "decoding secrets engines", err)
+	}
+
+	buf, err := json.Marshal(map[string]any{
+		"secret_engines": engines,
+		"total":          len(engines),
+	})
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling secrets engines", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+func parseSecretEngines(data json.RawMessage) ([]SecretEngine, error) {
+	var mounts map[string]struct {
+		Type        string            `json:"type"`
+		Description string            `json:"description"`
+		Accessor    string            `json:"accessor"`
+		Local       bool              `json:"local"`
+		SealWrap    bool              `json:"seal_wrap"`
+		Options     map[string]string `json:"options"`
+	}
+	if err := json.Unmarshal(data, &mounts); err != nil {
+		return nil, err
+	}
+
+	engines := make([]SecretEngine, 0, len(mounts))
+	for path, mount := range mounts {
+		engines = append(engines, SecretEngine{
+			Path:        path,
+			Type:        mount.Type,
+			Description: mount.Description,
+			Accessor:    mount.Accessor,
+			Local:       mount.Local,
+			SealWrap:    mount.SealWrap,
+			Options:     mount.Options,
+		})
+	}
+	sort.Slice(engines, func(i, j int) bool {
+		return engines[i].Path < engines[j].Path
+	})
+	return engines, nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/vault/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ReadKVMetadata creates a tool to read the metadata of a KV version 2 secret without reading its data.
+func ReadKVMetadata(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("read_kv_metadata",
+			mcp.WithDescription(`Reads the metadata of a secret stored in a KV version 2 secrets engine: versions, creation and deletion times, max versions and custom metadata. Secret values are never read.
+When 'path' is empty or ends with '/', the keys under that path are listed instead.`),
+			mcp.WithTitleAnnotation("Read KV secret metadata"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("mount",
+				mcp.Required(),
+				mcp.Description("The mount path of the KV version 2 secrets engine, e.g. 'secret'"),
+			),
+			mcp.WithString("path",
+				mcp.Description("The secret path within the mount, e.g. 'apps/web/config'. Leave empty or end with '/' to list keys"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return readKVMetadataHandler(ctx, request, logger)
+		},
+	}
+}
+
+func readKVMetadataHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	mount, err := request.RequireString("mount")
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "The 'mount' parameter is required", err)
+	}
+	mount = strings.Trim(strings.TrimSpace(mount), "/")
+	if mount == "" {
+		return nil, utils.LogAndReturnError(logger, "The 'mount' parameter cannot be empty", nil)
+	}
+	secretPath := strings.TrimPrefix(strings.TrimSpace(request.GetString("path", "")), "/")
+	metadataPath, err := kvMetadataPath(mount, secretPath)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "invalid KV path", err)
+	}
+
+	vaultClient, err := client.GetVaultClientFromContext(ctx, logger)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "getting Vault client - please ensure VAULT_TOKEN and VAULT_ADDR are properly configured", err)
+	}
+
+	var result map[string]any
+	if secretPath == "" || strings.HasSuffix(secretPath, "/") {
+		keys, err := vaultClient.List(ctx, metadataPath)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "listing KV keys", err)
+		}
+		result = map[string]any{
+			"mount": mount,
+			"path":  secretPath,
+			"keys":  keys,
+		}
+	} else {
+		resp, err := vaultClient.Read(ctx, metadataPath)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading KV metadata", err)
+		}
+		var metadata map[string]any
+		if err := json.Unmarshal(resp.Data, &metadata); err != nil {
+			return nil, utils.LogAndReturnError(logger, "decoding KV metadata", err)
+		}
+		result = map[string]any{
+			"mount":    mount,
+			"path":     secretPath,
+			"metadata": metadata,
+		}
+	}
+
+	buf, err := json.Marshal(result)
+	if err != nil {
+		return nil, utils.LogAndReturnError(logger, "marshalling KV metadata", err)
+	}
+	return mcp.NewToolResultText(string(buf)), nil
+}
+
+// kvMetadataPath returns the API path of the metadata of secretPath in mount. The arguments cannot leave the
+// metadata endpoint, e.g. to read the data of the secret with a query string or another API with '..'.
+func kvMetadataPath(mount string, secretPath string) (string, error) {
+	for name, value := range map[string]string{"mount": mount, "path": secretPath} {
+		if strings.ContainsAny(value, "?#") {
+			return "", fmt.Errorf("the '%s' parameter cannot contain '?' or '#'", name)
+		}
+		for _, segment := range strings.Split(value, "/") {
+			if segment == "." || segment == ".." {
+				return "", fmt.Errorf("the '%s' parameter cannot contain '.' or '..' segments", name)
+			}
+		}
+	}
+	return fmt.Sprintf("%s/metadata/%s", escapeKVPath(mount), escapeKVPath(secretPath)), nil
+}
+
+// escapeKVPath escapes each segment of a KV path while keeping the separators
+func escapeKVPath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVMetadataPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		mount       string
+		path        string
+		expected    string
+		expectError bool
+	}{
+		{name: "secret", mount: "secret", path: "apps/web/config", expected: "secret/metadata/apps/web/config"},
+		{name: "listing", mount: "team/kv", path: "apps/", expected: "team/kv/metadata/apps/"},
+		{name: "segments are escaped", mount: "secret", path: "apps/web app/50%", expected: "secret/metadata/apps/web%20app/50%25"},
+		{name: "query in mount", mount: "secret/data/app?x=", expectError: true},
+		{name: "fragment in path", mount: "secret", path: "app#x", expectError: true},
+		{name: "parent in path", mount: "secret", path: "../../sys/mounts", expectError: true},
+		{name: "parent in mount", mount: "secret/..", path: "app", expectError: true},
+		{name: "current in path", mount: "secret", path: "./app", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := kvMetadataPath(tt.mount, tt.path)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
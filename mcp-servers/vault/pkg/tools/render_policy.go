@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-mcp-server/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/vignesan/infra-genie/mcp-servers/vault/pkg/client"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// validCapabilities are the capabilities accepted in a Vault ACL policy path rule
+var validCapabilities = []string{"create", "read", "update", "patch", "delete", "list", "sudo", "deny", "subscribe"}
+
+// policyRule is a single path stanza of an ACL policy
+type policyRule struct {
+	Path         string
+	Capabilities []string
+}
+
+// RenderPolicy creates a tool that renders the HCL of an existing Vault ACL policy or builds one from path rules.
+func RenderPolicy(logger *log.Logger) server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool("render_policy",
+			mcp.WithDescription(`Renders Vault ACL policy HCL. Provide 'policy_name' to fetch and render an existing policy, or 'rules' to build a new policy from path and capability pairs without contacting Vault.
+Rules use the format 'path=capability1|capability2', one rule per line, e.g. 'secret/data/apps/web/*=read|list'.`),
+			mcp.WithTitleAnnotation("Render Vault policy HCL"),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("policy_name",
+				mcp.Description("The name of an existing ACL policy to render"),
+			),
+			mcp.WithString("rules",
+				mcp.Description("Newline-separated 'path=capability1|capability2' rules used to build a new policy"),
+			),
+		),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return renderPolicyHandler(ctx, request, logger)
+		},
+	}
+}
+
+func renderPolicyHandler(ctx context.Context, request mcp.CallToolRequest, logger *log.Logger) (*mcp.CallToolResult, error) {
+	policyName := strings.TrimSpace(request.GetString("policy_name", ""))
+	rulesStr := strings.TrimSpace(request.GetString("rules", ""))
+
+	switch {
+	case policyName != "" && rulesStr != "":
+		return nil, utils.LogAndReturnError(logger, "only one of 'policy_name' or 'rules' can be provided", nil)
+	case rulesStr != "":
+		rules, err := parsePolicyRules(rulesStr)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "parsing policy rules", err)
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("```hcl\n%s```", renderPolicyHCL(rules))), nil
+	case policyName != "":
+		vaultClient, err := client.GetVaultClientFromContext(ctx, logger)
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "getting Vault client - please ensure VAULT_TOKEN and VAULT_ADDR are properly configured", err)
+		}
+
+		resp, err := vaultClient.Read(ctx, "sys/policies/acl/"+url.PathEscape(policyName))
+		if err != nil {
+			return nil, utils.LogAndReturnError(logger, "reading policy", err)
+		}
+
+		var policy struct {
+			Name   string `json:"name"`
+			Policy string `json:"policy"`
+		}
+		if err := json.Unmarshal(resp.Data, &policy); err != nil {
+			return nil, utils.LogAndReturnError(logger, "decoding policy", err)
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("# Policy: %s\n\n```hcl\n%s\n```", policyName, strings.TrimSpace(policy.Policy))), nil
+	default:
+		return nil, utils.LogAndReturnError(logger, "required input: either 'policy_name' or 'rules' must be provided", nil)
+	}
+}
+
+// parsePolicyRules parses 'path=cap1|cap2' lines into policy rules, merging duplicate paths.
+func parsePolicyRules(input string) ([]policyRule, error) {
+	var rules []policyRule
+	index := make(map[string]int)
+
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		path, capsStr, ok := strings.Cut(line, "=")
+		path = strings.Trim(strings.TrimSpace(path), `"`)
+		if !ok || path == "" {
+			return nil, fmt.Errorf("invalid rule %q, expected 'path=capability1|capability2'", line)
+		}
+
+		var capabilities []string
+		for _, capability := range strings.FieldsFunc(capsStr, func(r rune) bool { return r == '|' || r == ',' || r == ' ' }) {
+			capability = strings.ToLower(capability)
+			if !slices.Contains(validCapabilities, capability) {
+				return nil, fmt.Errorf("invalid capability %q for path %q, valid capabilities are: %s", capability, path, strings.Join(validCapabilities, ", "))
+			}
+			capabilities = append(capabilities, capability)
+		}
+		if len(capabilities) == 0 {
+			return nil, fmt.Errorf("no capabilities provided for path %q", path)
+		}
+
+		if i, exists := index[path]; exists {
+			for _, capability := range capabilities {
+				if !slices.Contains(rules[i].Capabilities, capability) {
+					rules[i].Capabilities = append(rules[i].Capabilities, capability)
+				}
+			}
+			continue
+		}
+		index[path] = len(rules)
+		rules = append(rules, policyRule{Path: path, Capabilities: slices.Compact(capabilities)})
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no rules provided")
+	}
+	return rules, nil
+}
+
+// renderPolicyHCL renders policy rules as Vault ACL policy HCL.
+func renderPolicyHCL(rules []policyRule) string {
+	var builder strings.Builder
+	for i, rule := range rules {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		quoted := make([]string, 0, len(rule.Capabilities))
+		for _, capability := range rule.Capabilities {
+			quoted = append(quoted, fmt.Sprintf("%q", capability))
+		}
+		builder.WriteString(fmt.Sprintf("path %q {\n  capabilities = [%s]\n}\n", rule.Path, strings.Join(quoted, ", ")))
+	}
+	return builder.String()
+}
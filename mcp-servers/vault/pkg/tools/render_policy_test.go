@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicyRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []policyRule
+		expectError bool
+	}{
+		{
+			name:  "single rule",
+			input: "secret/data/apps/web/*=read|list",
+			expected: []policyRule{
+				{Path: "secret/data/apps/web/*", Capabilities: []string{"read", "list"}},
+			},
+		},
+		{
+			name:  "duplicate paths are merged and comments ignored",
+			input: "# web app\nsecret/data/web=read\nsecret/data/web=read,update\n\nsys/mounts=READ",
+			expected: []policyRule{
+				{Path: "secret/data/web", Capabilities: []string{"read", "update"}},
+				{Path: "sys/mounts", Capabilities: []string{"read"}},
+			},
+		},
+		{
+			name:        "invalid capability",
+			input:       "secret/data/web=write",
+			expectError: true,
+		},
+		{
+			name:        "missing capabilities",
+			input:       "secret/data/web=",
+			expectError: true,
+		},
+		{
+			name:        "missing separator",
+			input:       "secret/data/web",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := parsePolicyRules(tc.input)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rules)
+		})
+	}
+}
+
+func TestRenderPolicyHCL(t *testing.T) {
+	rules := []policyRule{
+		{Path: "secret/data/web", Capabilities: []string{"read", "list"}},
+		{Path: "sys/mounts", Capabilities: []string{"read"}},
+	}
+	expected := `path "secret/data/web" {
+  capabilities = ["read", "list"]
+}
+
+path "sys/mounts" {
+  capabilities = ["read"]
+}
+`
+	assert.Equal(t, expected, renderPolicyHCL(rules))
+}
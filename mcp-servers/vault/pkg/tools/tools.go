@@ -0,0 +1,25 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tools
+
+import (
+	"github.com/mark3labs/mcp-go/server"
+	log "github.com/sirupsen/logrus"
+)
+
+func RegisterTools(hcServer *server.MCPServer, logger *log.Logger) {
+	// Secrets engine tools
+	listSecretEnginesTool := ListSecretEngines(logger)
+	hcServer.AddTool(listSecretEnginesTool.Tool, listSecretEnginesTool.Handler)
+
+	readKVMetadataTool := ReadKVMetadata(logger)
+	hcServer.AddTool(readKVMetadataTool.Tool, readKVMetadataTool.Handler)
+
+	// Policy tools
+	listPoliciesTool := ListPolicies(logger)
+	hcServer.AddTool(listPoliciesTool.Tool, listPoliciesTool.Handler)
+
+	renderPolicyTool := RenderPolicy(logger)
+	hcServer.AddTool(renderPolicyTool.Tool, renderPolicyTool.Handler)
+}